@@ -4,6 +4,7 @@ import (
 	"context"
 	"flag"
 	"fmt"
+	"log/slog"
 	"os"
 	"os/exec"
 	"os/signal"
@@ -14,6 +15,7 @@ import (
 	"github.com/CLIAIMONITOR/internal/agents"
 	"github.com/CLIAIMONITOR/internal/captain"
 	"github.com/CLIAIMONITOR/internal/instance"
+	"github.com/CLIAIMONITOR/internal/logger"
 	"github.com/CLIAIMONITOR/internal/mcp"
 	"github.com/CLIAIMONITOR/internal/memory"
 	"github.com/CLIAIMONITOR/internal/metrics"
@@ -37,22 +39,44 @@ func main() {
 	statePath := flag.String("state", "data/state.json", "State persistence file")
 	mcpHost := flag.String("mcp-host", "localhost", "MCP server hostname (for agents to connect)")
 
+	// Logging flags: text is easiest to read in development, json suits log aggregation in production
+	logLevel := flag.String("log-level", "info", "Log level: debug, info, warn, error")
+	logFormat := flag.String("log-format", logger.FormatText, "Log format: text (development) or json (production)")
+
 	// Instance management flags
-	status := flag.Bool("status", false, "Show status of running instance")
-	stop := flag.Bool("stop", false, "Stop running instance gracefully")
+	status := flag.Bool("status", false, "Show status of running instance (exit 0 = running, 1 = degraded/not responding, 2 = not running)")
+	stop := flag.Bool("stop", false, "Stop running instance gracefully (exit 0 = stopped, 1 = not stopped within timeout, 2 = not running)")
 	forceStop := flag.Bool("force-stop", false, "Force kill running instance")
+	conflictStrategy := flag.String("conflict-strategy", "", "Port conflict handling when not interactive: fail-fast (default), next-port, kill")
+	conflictTimeout := flag.Duration("conflict-timeout", instance.DefaultConflictTimeout, "How long to wait at the interactive conflict prompt before applying --conflict-strategy (0 waits forever)")
+	shutdownTimeout := flag.Duration("shutdown-timeout", 10*time.Second, "Minimum time allowed for graceful shutdown")
+	shutdownTimeoutPerAgent := flag.Duration("shutdown-timeout-per-agent", 5*time.Second, "Shutdown time allowed per running agent; the larger of this total and --shutdown-timeout is used")
+	captainLog := flag.String("captain-log", "", "Copy the Captain pane's output to this file (rotated at --captain-log-max-bytes, --captain-log-keep kept)")
+	captainLogMaxBytes := flag.Int64("captain-log-max-bytes", captain.DefaultLogMaxBytes, "Captain log size that triggers rotation")
+	captainLogKeep := flag.Int("captain-log-keep", captain.DefaultLogMaxKeep, "How many rotated Captain logs are kept")
 	flag.Parse()
 
+	// Configure structured logging before anything else logs
+	level, err := logger.ParseLevel(*logLevel)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Invalid -log-level: %v\n", err)
+		os.Exit(1)
+	}
+	if !logger.ValidFormat(*logFormat) {
+		fmt.Fprintf(os.Stderr, "Invalid -log-format %q (expected text or json)\n", *logFormat)
+		os.Exit(1)
+	}
+	appLogger := logger.NewLogger(level, *logFormat)
+	slog.SetDefault(appLogger)
+
 	// Handle status command
 	if *status {
-		showInstanceStatus(*statePath, *port)
-		os.Exit(0)
+		os.Exit(showInstanceStatus(*statePath, *port))
 	}
 
 	// Handle stop commands
 	if *stop || *forceStop {
-		stopInstance(*statePath, *forceStop)
-		os.Exit(0)
+		os.Exit(stopInstance(*statePath, *forceStop))
 	}
 
 	// Get base path (executable directory or current directory)
@@ -87,7 +111,15 @@ func main() {
 	// Handle conflict if instance exists
 	if existingInfo != nil && existingInfo.IsRunning {
 		resolver := instance.NewConflictResolver(instanceMgr, instance.IsInteractive())
-		if err := resolver.Resolve(existingInfo); err != nil {
+		if *conflictStrategy != "" {
+			strategy, err := instance.ParseNonInteractiveStrategy(*conflictStrategy)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Invalid -conflict-strategy: %v\n", err)
+				os.Exit(1)
+			}
+			resolver.SetNonInteractiveStrategy(strategy)
+		}
+		if err := resolver.ResolveWithTimeout(existingInfo, *conflictTimeout); err != nil {
 			fmt.Fprintf(os.Stderr, "Failed to resolve instance conflict: %v\n", err)
 			os.Exit(1)
 		}
@@ -102,6 +134,14 @@ func main() {
 	}
 	defer instanceMgr.ReleaseLock()
 
+	// Remove PID files and MCP configs left behind by a previous crash
+	orphansCleaned, err := agents.CleanupOrphanedFiles(basePath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: Failed to clean up orphaned agent files: %v\n", err)
+	}
+	if orphansCleaned > 0 {
+		fmt.Printf("  Cleaned up %d orphaned agent files\n", orphansCleaned)
+	}
 
 	// Set Captain window title and workspace for WezTerm styling
 	setCaptainWindowTitle()
@@ -210,6 +250,9 @@ func main() {
 		basePath,
 		*port,
 	)
+	srv.SetLogger(appLogger)
+	srv.SetOrphanedFilesCleaned(orphansCleaned)
+	srv.SetTeamsConfigPath(*configPath)
 
 	// Setup graceful shutdown
 	shutdown := make(chan os.Signal, 1)
@@ -257,25 +300,26 @@ func main() {
 		fmt.Fprintf(os.Stderr, "Warning: Failed to write PID file: %v\n", err)
 	}
 
-	// Convert config to map for Captain
-	configMap := make(map[string]types.AgentConfig)
-	for _, agent := range config.Agents {
-		configMap[agent.Name] = agent
-	}
-
 	// Create cancellable context for Captain
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
-	// Initialize and start Captain orchestrator (background task processor)
-	captainOrchestrator := captain.NewCaptain(basePath, spawner, memoryDB, configMap)
+	// Start the server's Captain orchestrator (background task processor) so
+	// tasks queued through the API are picked up by the orchestration loop
+	captainOrchestrator := srv.Captain()
 	go captainOrchestrator.Run(ctx)
 	fmt.Println("  Captain orchestrator started")
 
 	// Initialize and start Captain Supervisor (manages interactive Captain terminal)
+	if *captainLog != "" && !filepath.IsAbs(*captainLog) {
+		*captainLog = filepath.Join(basePath, *captainLog)
+	}
 	captainSupervisor := captain.NewCaptainSupervisor(captain.SupervisorConfig{
-		BasePath:   basePath,
-		ServerPort: *port,
+		BasePath:    basePath,
+		ServerPort:  *port,
+		LogFile:     *captainLog,
+		LogMaxBytes: *captainLogMaxBytes,
+		LogMaxKeep:  *captainLogKeep,
 	})
 
 	// Wire supervisor to server for API endpoints
@@ -338,13 +382,21 @@ func main() {
 		fmt.Printf("  Note: Captain may have already exited: %v\n", err)
 	}
 
-	// Wait for graceful shutdown
-	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 10*time.Second)
+	// Wait for graceful shutdown, allowing more time when many agents are running
+	currentState := store.GetState()
+	activeAgents := 0
+	for _, agent := range currentState.Agents {
+		if agent.PID > 0 && agent.Status != types.StatusDisconnected {
+			activeAgents++
+		}
+	}
+	timeout := computeShutdownTimeout(*shutdownTimeout, *shutdownTimeoutPerAgent, activeAgents)
+	fmt.Printf("Shutdown timeout: %s (%d active agents)\n", timeout, activeAgents)
+	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), timeout)
 	defer shutdownCancel()
 
 	// Stop all agents - use PIDs from store since spawner may not track them correctly
 	fmt.Println("Stopping agents...")
-	currentState := store.GetState()
 	for agentID, agent := range currentState.Agents {
 		if agent.PID > 0 {
 			// Try to kill the process directly using PID from store
@@ -357,7 +409,7 @@ func main() {
 		}
 	}
 
-	// Cleanup all generated config and prompt files
+	// Cleanup all generated config and prompt files and persist agent ID counters
 	fmt.Println("Cleaning up agent files...")
 	spawner.CleanupAllAgentFiles()
 
@@ -385,6 +437,15 @@ func main() {
 	fmt.Println("Goodbye!")
 }
 
+// computeShutdownTimeout returns the larger of base and perAgent for each
+// active agent
+func computeShutdownTimeout(base, perAgent time.Duration, activeAgents int) time.Duration {
+	if scaled := time.Duration(activeAgents) * perAgent; scaled > base {
+		return scaled
+	}
+	return base
+}
+
 // getBasePath returns the directory containing the executable,
 // or the current working directory if running via `go run`
 func getBasePath() (string, error) {
@@ -407,8 +468,9 @@ func getBasePath() (string, error) {
 	return dir, nil
 }
 
-// showInstanceStatus displays information about the running instance
-func showInstanceStatus(statePath string, port int) {
+// showInstanceStatus displays information about the running instance and
+// returns the -status exit code (see instance.StatusExitCode)
+func showInstanceStatus(statePath string, port int) int {
 	basePath, _ := getBasePath()
 	pidPath := filepath.Join(basePath, "data", "cliaimonitor.pid")
 	mgr := instance.NewManager(pidPath, statePath, port)
@@ -416,12 +478,12 @@ func showInstanceStatus(statePath string, port int) {
 
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-		return
+		return instance.ExitDegraded
 	}
 
 	if info == nil {
 		fmt.Println("No CLIAIMONITOR instance is currently running")
-		return
+		return instance.StatusExitCode(nil)
 	}
 
 	// Display formatted instance information
@@ -487,10 +549,12 @@ func showInstanceStatus(statePath string, port int) {
 	fmt.Printf("  Stop instance:   cliaimonitor.exe -stop\n")
 	fmt.Printf("  Force kill:      cliaimonitor.exe -force-stop\n")
 	fmt.Println()
+
+	return instance.StatusExitCode(info)
 }
 
-// stopInstance stops the running instance
-func stopInstance(statePath string, force bool) {
+// stopInstance stops the running instance and returns the -stop exit code
+func stopInstance(statePath string, force bool) int {
 	basePath, _ := getBasePath()
 	pidPath := filepath.Join(basePath, "data", "cliaimonitor.pid")
 	mgr := instance.NewManager(pidPath, statePath, 0)
@@ -498,40 +562,43 @@ func stopInstance(statePath string, force bool) {
 
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-		os.Exit(1)
+		return instance.ExitDegraded
 	}
 
 	if info == nil {
 		fmt.Println("No CLIAIMONITOR instance is currently running")
-		return
+		return instance.StopExitCode(nil, false)
 	}
 
 	if force {
 		fmt.Printf("Force killing process %d...\n", info.PID)
 		if err := instance.KillProcess(info.PID); err != nil {
 			fmt.Fprintf(os.Stderr, "Failed to kill process: %v\n", err)
-			os.Exit(1)
+			return instance.StopExitCode(info, false)
 		}
 		time.Sleep(1 * time.Second)
 		mgr.RemovePIDFile()
 		fmt.Println("Instance terminated ✓")
-	} else {
-		fmt.Printf("Sending graceful shutdown request to instance on port %d...\n", info.Port)
-		if err := instance.SendShutdownRequest(info.Port); err != nil {
-			fmt.Fprintf(os.Stderr, "Failed to send shutdown request: %v\n", err)
-			fmt.Println("Try using -force-stop to force kill the process")
-			os.Exit(1)
-		}
+		return instance.StopExitCode(info, true)
+	}
 
-		// Wait for process to exit
-		fmt.Println("Waiting for graceful shutdown...")
-		if instance.WaitForPortToBeAvailable(info.Port, 5*time.Second) {
-			fmt.Println("Instance stopped successfully ✓")
-		} else {
-			fmt.Println("Warning: Instance may still be running")
-			fmt.Println("Try: cliaimonitor.exe -force-stop")
-		}
+	fmt.Printf("Sending graceful shutdown request to instance on port %d...\n", info.Port)
+	if err := instance.SendShutdownRequest(info.Port); err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to send shutdown request: %v\n", err)
+		fmt.Println("Try using -force-stop to force kill the process")
+		return instance.StopExitCode(info, false)
+	}
+
+	// Wait for process to exit
+	fmt.Println("Waiting for graceful shutdown...")
+	if !instance.WaitForPortToBeAvailable(info.Port, 5*time.Second) {
+		fmt.Println("Warning: Instance may still be running")
+		fmt.Println("Try: cliaimonitor.exe -force-stop")
+		return instance.StopExitCode(info, false)
 	}
+
+	fmt.Println("Instance stopped successfully ✓")
+	return instance.StopExitCode(info, true)
 }
 
 // setCaptainWindowTitle sets the WezTerm window title and workspace to CLITCOMMANDER
@@ -0,0 +1,118 @@
+package agents
+
+import "github.com/CLIAIMONITOR/internal/types"
+
+// DefaultColorPalette returns the built-in palettes AllocateColor hands out,
+// in allocation order. The gray default palette is left out so every
+// allocated pane is tinted, and Snake's and Captain's palettes are left out
+// so only those roles wear them.
+func DefaultColorPalette() []AgentColors {
+	return []AgentColors{paletteGreen, palettePurple, paletteRed, paletteBlue}
+}
+
+// AllocateColor returns the pane colors for an agent. Colors released by
+// stopped agents are reused first; otherwise the next palette entry is taken
+// round-robin, so up to len(ColorPalette) running agents never share a
+// color. Calling it again for the same agent returns the same colors.
+func (s *ProcessSpawner) AllocateColor(agentID string) AgentColors {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if len(s.ColorPalette) == 0 {
+		return paletteDefault
+	}
+	if s.agentColors == nil {
+		s.agentColors = make(map[string]int)
+	}
+	if idx, ok := s.agentColors[agentID]; ok && idx < len(s.ColorPalette) {
+		return s.ColorPalette[idx]
+	}
+
+	idx := -1
+	for len(s.freeColors) > 0 {
+		candidate := s.freeColors[0]
+		s.freeColors = s.freeColors[1:]
+		if candidate < len(s.ColorPalette) {
+			idx = candidate
+			break
+		}
+	}
+	if idx < 0 {
+		idx = s.colorIndex % len(s.ColorPalette)
+		s.colorIndex++
+	}
+	s.agentColors[agentID] = idx
+	return s.ColorPalette[idx]
+}
+
+// assignPaneColors picks the agent's pane colors and records them for
+// PaneColors. An agent keeps its config's own colors (see GetAgentColors)
+// unless it has none of its own or another running pane already shows that
+// background; then it takes the next palette color, with any teams.yaml
+// colors still applied on top. Snake and Captain always keep their role
+// palettes, as does a config with a teams.yaml background.
+func (s *ProcessSpawner) assignPaneColors(agentID string, config types.AgentConfig) AgentColors {
+	colors := GetAgentColors(config)
+	palette := paletteFor(config)
+	fixed := palette == paletteSnake || palette == paletteCaptain || config.Colors.Background != ""
+	if !fixed && (palette == paletteDefault || s.backgroundInUse(agentID, colors.BgHex)) {
+		colors = s.AllocateColor(agentID).withOverrides(config.Colors)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.paneColors == nil {
+		s.paneColors = make(map[string]AgentColors)
+	}
+	s.paneColors[agentID] = colors
+	return colors
+}
+
+// backgroundInUse reports whether a running agent other than agentID has a
+// pane with background bgHex
+func (s *ProcessSpawner) backgroundInUse(agentID, bgHex string) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	for id, c := range s.paneColors {
+		if id != agentID && c.BgHex == bgHex {
+			return true
+		}
+	}
+	return false
+}
+
+// PaneColors returns the colors applied to each running agent's pane, keyed
+// by agent ID
+func (s *ProcessSpawner) PaneColors() map[string]AgentColors {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	colors := make(map[string]AgentColors, len(s.paneColors))
+	for id, c := range s.paneColors {
+		colors[id] = c
+	}
+	return colors
+}
+
+// releaseColor returns an agent's color to the free pool once no other
+// running agent shares it. Callers hold s.mu.
+func (s *ProcessSpawner) releaseColor(agentID string) {
+	delete(s.paneColors, agentID)
+	idx, ok := s.agentColors[agentID]
+	if !ok {
+		return
+	}
+	delete(s.agentColors, agentID)
+
+	for _, other := range s.agentColors {
+		if other == idx {
+			return
+		}
+	}
+	for _, free := range s.freeColors {
+		if free == idx {
+			return
+		}
+	}
+	s.freeColors = append(s.freeColors, idx)
+}
@@ -0,0 +1,113 @@
+package agents
+
+import (
+	"testing"
+
+	"github.com/CLIAIMONITOR/internal/types"
+)
+
+func TestAllocateColor(t *testing.T) {
+	spawner := NewSpawner(t.TempDir(), "http://localhost:3000/mcp/sse", newTestDB(t))
+	spawner.ColorPalette = []AgentColors{paletteGreen, palettePurple, paletteRed}
+
+	a := spawner.AllocateColor("team-a001")
+	b := spawner.AllocateColor("team-b001")
+	c := spawner.AllocateColor("team-c001")
+	if a.BgHex == b.BgHex || b.BgHex == c.BgHex || a.BgHex == c.BgHex {
+		t.Fatalf("Expected distinct colors for %d agents, got %s, %s, %s", len(spawner.ColorPalette), a.BgHex, b.BgHex, c.BgHex)
+	}
+	if again := spawner.AllocateColor("team-a001"); again.BgHex != a.BgHex {
+		t.Errorf("Re-allocating for the same agent changed its color from %s to %s", a.BgHex, again.BgHex)
+	}
+
+	// A stopped agent's color goes to the next agent
+	spawner.RemoveAgent("team-b001")
+	if d := spawner.AllocateColor("team-d001"); d.BgHex != b.BgHex {
+		t.Errorf("Expected released color %s to be reused, got %s", b.BgHex, d.BgHex)
+	}
+
+	// Past the palette size colors wrap round-robin
+	if e := spawner.AllocateColor("team-e001"); e.BgHex != a.BgHex {
+		t.Errorf("Expected palette to wrap to %s, got %s", a.BgHex, e.BgHex)
+	}
+
+	// A color still held by another agent is not freed
+	spawner.RemoveAgent("team-a001")
+	if f := spawner.AllocateColor("team-f001"); f.BgHex == a.BgHex {
+		t.Errorf("Color %s reissued while team-e001 still holds it", a.BgHex)
+	}
+}
+
+func TestAllocateColorEmptyPalette(t *testing.T) {
+	spawner := NewSpawner(t.TempDir(), "http://localhost:3000/mcp/sse", newTestDB(t))
+	spawner.ColorPalette = nil
+
+	if colors := spawner.AllocateColor("team-a001"); colors.BgHex != paletteDefault.BgHex {
+		t.Errorf("Expected default palette with no ColorPalette, got %s", colors.BgHex)
+	}
+}
+
+func TestStopAgentReleasesColor(t *testing.T) {
+	spawner := NewSpawner(t.TempDir(), "http://localhost:3000/mcp/sse", newTestDB(t))
+	first := spawner.AllocateColor("test-agent-001")
+	spawner.mu.Lock()
+	spawner.runningAgents["test-agent-001"] = 99999
+	spawner.mu.Unlock()
+
+	if err := spawner.StopAgent("test-agent-001"); err != nil {
+		t.Fatalf("StopAgent failed: %v", err)
+	}
+	if next := spawner.AllocateColor("test-agent-002"); next.BgHex != first.BgHex {
+		t.Errorf("Expected stopped agent's color %s to be reused, got %s", first.BgHex, next.BgHex)
+	}
+}
+
+func TestDefaultColorPaletteExcludesRolePalettes(t *testing.T) {
+	for _, colors := range DefaultColorPalette() {
+		if colors == paletteSnake || colors == paletteCaptain || colors == paletteDefault {
+			t.Errorf("Palette %s should not be in the allocation pool", colors.BgHex)
+		}
+	}
+}
+
+func TestPaneColors(t *testing.T) {
+	spawner := NewSpawner(t.TempDir(), "http://localhost:3000/mcp/sse", newTestDB(t))
+	spawner.ColorPalette = []AgentColors{paletteGreen, palettePurple}
+
+	own := spawner.assignPaneColors("team-a001", types.AgentConfig{Name: "SNTRed"})
+	clash := spawner.assignPaneColors("team-b001", types.AgentConfig{Name: "SNTRed"})
+	plain := spawner.assignPaneColors("team-c001", types.AgentConfig{Name: "Worker"})
+	custom := spawner.assignPaneColors("team-d001", types.AgentConfig{Name: "SNTRed", Colors: types.AgentColors{Background: "#101010"}})
+	snakes := []AgentColors{
+		spawner.assignPaneColors("snake-001", types.AgentConfig{Name: "Snake"}),
+		spawner.assignPaneColors("snake-002", types.AgentConfig{Name: "Snake"}),
+	}
+
+	if own != paletteRed {
+		t.Errorf("Expected SNTRed to keep its own red, got %s", own.BgHex)
+	}
+	if clash.BgHex != paletteGreen.BgHex {
+		t.Errorf("Expected a second SNTRed to take palette color %s, got %s", paletteGreen.BgHex, clash.BgHex)
+	}
+	if plain.BgHex != palettePurple.BgHex {
+		t.Errorf("Expected a config without colors to take palette color %s, got %s", palettePurple.BgHex, plain.BgHex)
+	}
+	if custom.BgHex != "#101010" || custom.TabHex != paletteRed.TabHex {
+		t.Errorf("Expected teams.yaml background over the config's palette, got %+v", custom)
+	}
+	for _, snake := range snakes {
+		if snake != paletteSnake {
+			t.Errorf("Expected Snake to keep its role palette, got %s", snake.BgHex)
+		}
+	}
+
+	panes := spawner.PaneColors()
+	if len(panes) != 6 || panes["team-a001"].BgHex != own.BgHex || panes["team-d001"].BgHex != custom.BgHex {
+		t.Fatalf("Expected PaneColors to report applied colors, got %+v", panes)
+	}
+
+	spawner.RemoveAgent("team-a001")
+	if _, ok := spawner.PaneColors()["team-a001"]; ok {
+		t.Error("Expected removed agent to be dropped from PaneColors")
+	}
+}
@@ -2,7 +2,10 @@ package agents
 
 import (
 	"fmt"
+	"strconv"
 	"strings"
+
+	"github.com/CLIAIMONITOR/internal/types"
 )
 
 // AgentColors holds ANSI escape sequences for styling agent panes
@@ -12,111 +15,187 @@ type AgentColors struct {
 	FgColor  string // Foreground color for text
 	BgHex    string // Hex color for terminal default background (OSC 11)
 	BgRGB    string // RGB format for OSC 11: "rgb:RR/GG/BB" (WezTerm compatible)
+	FgHex    string // Hex of FgColor, for the dashboard
+	TabHex   string // Hex of BgBright, used for the banner and dashboard tab
 	Emoji    string // Emoji for quick visual identification
 	Reset    string // Reset sequence to clear all formatting
 }
 
-// GetAgentColors returns the color scheme for a given agent configuration name
-func GetAgentColors(configName string) AgentColors {
-	lowerName := strings.ToLower(configName)
-	reset := "\x1b[0m"
+const colorReset = "\x1b[0m"
 
-	switch {
-	case strings.Contains(lowerName, "green"):
-		return AgentColors{
-			BgDark:   "\x1b[48;2;5;30;15m",      // Dark emerald background
-			BgBright: "\x1b[48;2;34;197;94m",    // Bright emerald background
-			FgColor:  "\x1b[38;2;34;197;94m",    // Emerald text
-			BgHex:    "#051E0F",                 // Hex for OSC 11 terminal background
-			BgRGB:    "rgb:05/1e/0f",            // RGB for WezTerm OSC 11
-			Emoji:    "🟢",                       // Green circle
-			Reset:    reset,
-		}
-	case strings.Contains(lowerName, "purple"):
-		return AgentColors{
-			BgDark:   "\x1b[48;2;20;10;35m",     // Dark violet background
-			BgBright: "\x1b[48;2;168;85;247m",   // Bright violet background
-			FgColor:  "\x1b[38;2;168;85;247m",   // Violet text
-			BgHex:    "#140A23",                 // Hex for OSC 11 terminal background
-			BgRGB:    "rgb:14/0a/23",            // RGB for WezTerm OSC 11
-			Emoji:    "🟣",                       // Purple circle
-			Reset:    reset,
-		}
-	case strings.Contains(lowerName, "red"):
-		return AgentColors{
-			BgDark:   "\x1b[48;2;35;10;10m",     // Dark rose background
-			BgBright: "\x1b[48;2;239;68;68m",    // Bright rose background
-			FgColor:  "\x1b[38;2;239;68;68m",    // Rose text
-			BgHex:    "#230A0A",                 // Hex for OSC 11 terminal background
-			BgRGB:    "rgb:23/0a/0a",            // RGB for WezTerm OSC 11
-			Emoji:    "🔴",                       // Red circle
-			Reset:    reset,
-		}
-	case strings.Contains(lowerName, "snake"):
-		return AgentColors{
-			BgDark:   "\x1b[48;2;5;25;30m",      // Dark cyan background
-			BgBright: "\x1b[48;2;6;182;212m",    // Bright cyan background
-			FgColor:  "\x1b[38;2;6;182;212m",    // Cyan text
-			BgHex:    "#05191E",                 // Hex for OSC 11 terminal background
-			BgRGB:    "rgb:05/19/1e",            // RGB for WezTerm OSC 11
-			Emoji:    "🐍",                       // Snake emoji
-			Reset:    reset,
-		}
-	case strings.Contains(lowerName, "captain"):
-		return AgentColors{
-			BgDark:   "\x1b[48;2;35;27;3m",      // Dark gold background
-			BgBright: "\x1b[48;2;234;179;8m",    // Bright gold background
-			FgColor:  "\x1b[38;2;234;179;8m",    // Gold text
-			BgHex:    "#231B03",                 // Hex for OSC 11 terminal background
-			BgRGB:    "rgb:23/1b/03",            // RGB for WezTerm OSC 11
-			Emoji:    "⭐",                       // Star emoji
-			Reset:    reset,
-		}
-	case strings.Contains(lowerName, "blue"):
-		return AgentColors{
-			BgDark:   "\x1b[48;2;2;25;35m",      // Dark sky background
-			BgBright: "\x1b[48;2;14;165;233m",   // Bright sky background
-			FgColor:  "\x1b[38;2;14;165;233m",   // Sky text
-			BgHex:    "#021923",                 // Hex for OSC 11 terminal background
-			BgRGB:    "rgb:02/19/23",            // RGB for WezTerm OSC 11
-			Emoji:    "🔵",                       // Blue circle
-			Reset:    reset,
-		}
-	default:
-		return AgentColors{
-			BgDark:   "\x1b[48;2;20;20;20m",     // Dark gray background
-			BgBright: "\x1b[48;2;100;100;100m",  // Gray background
-			FgColor:  "\x1b[38;2;200;200;200m",  // Light gray text
-			BgHex:    "#141414",                 // Hex for OSC 11 terminal background
-			BgRGB:    "rgb:14/14/14",            // RGB for WezTerm OSC 11
-			Emoji:    "⚪",                       // White circle
-			Reset:    reset,
-		}
+// Built-in palettes. Agents whose config name contains one of these color
+// words (or snake/captain) get that palette.
+var (
+	paletteGreen = AgentColors{
+		BgDark:   "\x1b[48;2;5;30;15m",   // Dark emerald background
+		BgBright: "\x1b[48;2;34;197;94m", // Bright emerald background
+		FgColor:  "\x1b[38;2;34;197;94m", // Emerald text
+		BgHex:    "#051E0F",
+		BgRGB:    "rgb:05/1e/0f",
+		FgHex:    "#22C55E",
+		TabHex:   "#22C55E",
+		Emoji:    "🟢",
+		Reset:    colorReset,
+	}
+	palettePurple = AgentColors{
+		BgDark:   "\x1b[48;2;20;10;35m",   // Dark violet background
+		BgBright: "\x1b[48;2;168;85;247m", // Bright violet background
+		FgColor:  "\x1b[38;2;168;85;247m", // Violet text
+		BgHex:    "#140A23",
+		BgRGB:    "rgb:14/0a/23",
+		FgHex:    "#A855F7",
+		TabHex:   "#A855F7",
+		Emoji:    "🟣",
+		Reset:    colorReset,
+	}
+	paletteRed = AgentColors{
+		BgDark:   "\x1b[48;2;35;10;10m",  // Dark rose background
+		BgBright: "\x1b[48;2;239;68;68m", // Bright rose background
+		FgColor:  "\x1b[38;2;239;68;68m", // Rose text
+		BgHex:    "#230A0A",
+		BgRGB:    "rgb:23/0a/0a",
+		FgHex:    "#EF4444",
+		TabHex:   "#EF4444",
+		Emoji:    "🔴",
+		Reset:    colorReset,
+	}
+	paletteSnake = AgentColors{
+		BgDark:   "\x1b[48;2;5;25;30m",   // Dark cyan background
+		BgBright: "\x1b[48;2;6;182;212m", // Bright cyan background
+		FgColor:  "\x1b[38;2;6;182;212m", // Cyan text
+		BgHex:    "#05191E",
+		BgRGB:    "rgb:05/19/1e",
+		FgHex:    "#06B6D4",
+		TabHex:   "#06B6D4",
+		Emoji:    "🐍",
+		Reset:    colorReset,
+	}
+	paletteCaptain = AgentColors{
+		BgDark:   "\x1b[48;2;35;27;3m",   // Dark gold background
+		BgBright: "\x1b[48;2;234;179;8m", // Bright gold background
+		FgColor:  "\x1b[38;2;234;179;8m", // Gold text
+		BgHex:    "#231B03",
+		BgRGB:    "rgb:23/1b/03",
+		FgHex:    "#EAB308",
+		TabHex:   "#EAB308",
+		Emoji:    "⭐",
+		Reset:    colorReset,
 	}
+	paletteBlue = AgentColors{
+		BgDark:   "\x1b[48;2;2;25;35m",    // Dark sky background
+		BgBright: "\x1b[48;2;14;165;233m", // Bright sky background
+		FgColor:  "\x1b[38;2;14;165;233m", // Sky text
+		BgHex:    "#021923",
+		BgRGB:    "rgb:02/19/23",
+		FgHex:    "#0EA5E9",
+		TabHex:   "#0EA5E9",
+		Emoji:    "🔵",
+		Reset:    colorReset,
+	}
+	paletteDefault = AgentColors{
+		BgDark:   "\x1b[48;2;20;20;20m",    // Dark gray background
+		BgBright: "\x1b[48;2;100;100;100m", // Gray background
+		FgColor:  "\x1b[38;2;200;200;200m", // Light gray text
+		BgHex:    "#141414",
+		BgRGB:    "rgb:14/14/14",
+		FgHex:    "#C8C8C8",
+		TabHex:   "#646464",
+		Emoji:    "⚪",
+		Reset:    colorReset,
+	}
+)
+
+// namePalettes maps config name keywords to palettes, checked in order
+var namePalettes = []struct {
+	keyword string
+	colors  AgentColors
+}{
+	{"green", paletteGreen},
+	{"purple", palettePurple},
+	{"red", paletteRed},
+	{"snake", paletteSnake},
+	{"captain", paletteCaptain},
+	{"blue", paletteBlue},
 }
 
-// GenerateBanner creates a colored Unicode box banner for agent identification
-// The banner displays at the top of the agent's pane when spawned
-func GenerateBanner(agentID, configName, role string) string {
-	colors := GetAgentColors(configName)
+// GetAgentColors returns the color scheme for an agent config. Colors set in
+// teams.yaml win; otherwise a color word in the config name picks the
+// palette, then the role does (red for reviewers, green for coders, blue for
+// planners), then gray.
+func GetAgentColors(config types.AgentConfig) AgentColors {
+	colors := paletteFor(config)
+	return colors.withOverrides(config.Colors)
+}
 
-	// Black text on bright background for banner
-	blackText := "\x1b[38;2;0;0;0m"
+// paletteFor returns the built-in palette for an agent config
+func paletteFor(config types.AgentConfig) AgentColors {
+	lowerName := strings.ToLower(config.Name)
+	for _, p := range namePalettes {
+		if strings.Contains(lowerName, p.keyword) {
+			return p.colors
+		}
+	}
 
-	// Create the banner with Unicode box-drawing characters
-	banner := fmt.Sprintf("%s%s\n", colors.BgBright, blackText)
-	banner += "╔══════════════════════════════════════════════════════╗\n"
-	banner += fmt.Sprintf("║  %s %-10s │ %-20s          ║\n", colors.Emoji, role, configName)
-	banner += fmt.Sprintf("║  Agent: %-44s ║\n", agentID)
-	banner += "╚══════════════════════════════════════════════════════╝\n"
-	banner += colors.Reset
+	role := strings.ToLower(string(config.Role))
+	switch {
+	case strings.Contains(role, "review"), strings.Contains(role, "audit"), strings.Contains(role, "security"):
+		return paletteRed
+	case strings.Contains(role, "develop"), strings.Contains(role, "engineer"),
+		strings.Contains(role, "implement"), strings.Contains(role, "code"):
+		return paletteGreen
+	case strings.Contains(role, "plan"), strings.Contains(role, "supervis"), strings.Contains(role, "architect"):
+		return paletteBlue
+	default:
+		return paletteDefault
+	}
+}
+
+// withOverrides applies the hex colors configured in teams.yaml. Invalid or
+// empty values keep the palette's color; LoadTeamsConfig rejects invalid ones.
+func (c AgentColors) withOverrides(o types.AgentColors) AgentColors {
+	if r, g, b, ok := parseHexColor(o.Background); ok {
+		c.BgDark = fmt.Sprintf("\x1b[48;2;%d;%d;%dm", r, g, b)
+		c.BgHex = fmt.Sprintf("#%02X%02X%02X", r, g, b)
+		c.BgRGB = fmt.Sprintf("rgb:%02x/%02x/%02x", r, g, b)
+	}
+	if r, g, b, ok := parseHexColor(o.Foreground); ok {
+		c.FgColor = fmt.Sprintf("\x1b[38;2;%d;%d;%dm", r, g, b)
+		c.FgHex = fmt.Sprintf("#%02X%02X%02X", r, g, b)
+	}
+	if r, g, b, ok := parseHexColor(o.TabColor); ok {
+		c.BgBright = fmt.Sprintf("\x1b[48;2;%d;%d;%dm", r, g, b)
+		c.TabHex = fmt.Sprintf("#%02X%02X%02X", r, g, b)
+	}
+	return c
+}
 
-	return banner
+// parseHexColor parses "#RRGGBB" (the # is optional)
+func parseHexColor(hex string) (r, g, b uint8, ok bool) {
+	hex = strings.TrimPrefix(strings.TrimSpace(hex), "#")
+	if len(hex) != 6 {
+		return 0, 0, 0, false
+	}
+	v, err := strconv.ParseUint(hex, 16, 32)
+	if err != nil {
+		return 0, 0, 0, false
+	}
+	return uint8(v >> 16), uint8(v >> 8), uint8(v), true
 }
 
-// GenerateBackgroundTint returns the ANSI escape sequence to set a subtle
-// background tint for the entire pane based on agent type
-func GenerateBackgroundTint(configName string) string {
-	colors := GetAgentColors(configName)
-	return colors.BgDark
+// validateAgentColors reports the first invalid hex color in a config
+func validateAgentColors(config types.AgentConfig) error {
+	fields := []struct{ name, value string }{
+		{"background", config.Colors.Background},
+		{"foreground", config.Colors.Foreground},
+		{"tab_color", config.Colors.TabColor},
+	}
+	for _, f := range fields {
+		if f.value == "" {
+			continue
+		}
+		if _, _, _, ok := parseHexColor(f.value); !ok {
+			return fmt.Errorf("agent %s: invalid colors.%s %q (want #RRGGBB)", config.Name, f.name, f.value)
+		}
+	}
+	return nil
 }
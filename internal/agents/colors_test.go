@@ -1,8 +1,9 @@
 package agents
 
 import (
-	"strings"
 	"testing"
+
+	"github.com/CLIAIMONITOR/internal/types"
 )
 
 func TestGetAgentColors(t *testing.T) {
@@ -73,7 +74,7 @@ func TestGetAgentColors(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got := GetAgentColors(tt.configName)
+			got := GetAgentColors(types.AgentConfig{Name: tt.configName})
 
 			if got.Emoji != tt.wantEmoji {
 				t.Errorf("GetAgentColors(%q).Emoji = %q, want %q", tt.configName, got.Emoji, tt.wantEmoji)
@@ -94,123 +95,13 @@ func TestGetAgentColors(t *testing.T) {
 	}
 }
 
-func TestGenerateBanner(t *testing.T) {
-	tests := []struct {
-		name       string
-		agentID    string
-		configName string
-		role       string
-		wantEmoji  string
-		wantID     bool
-		wantRole   bool
-	}{
-		{
-			name:       "Green SGT banner",
-			agentID:    "team-sntgreen001",
-			configName: "SNTGreen",
-			role:       "Go Developer",
-			wantEmoji:  "🟢",
-			wantID:     true,
-			wantRole:   true,
-		},
-		{
-			name:       "Purple SGT banner",
-			agentID:    "team-sntpurple001",
-			configName: "SNTPurple",
-			role:       "Code Reviewer",
-			wantEmoji:  "🟣",
-			wantID:     true,
-			wantRole:   true,
-		},
-		{
-			name:       "Snake banner",
-			agentID:    "team-snake001",
-			configName: "Snake",
-			role:       "Reconnaissance",
-			wantEmoji:  "🐍",
-			wantID:     true,
-			wantRole:   true,
-		},
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			got := GenerateBanner(tt.agentID, tt.configName, tt.role)
-
-			// Check that banner contains expected elements
-			if !strings.Contains(got, tt.wantEmoji) {
-				t.Errorf("GenerateBanner() missing emoji %q", tt.wantEmoji)
-			}
-			if tt.wantID && !strings.Contains(got, tt.agentID) {
-				t.Errorf("GenerateBanner() missing agent ID %q", tt.agentID)
-			}
-			if tt.wantRole && !strings.Contains(got, tt.role) {
-				t.Errorf("GenerateBanner() missing role %q", tt.role)
-			}
-
-			// Check for Unicode box-drawing characters
-			if !strings.Contains(got, "╔") || !strings.Contains(got, "╚") {
-				t.Error("GenerateBanner() missing Unicode box-drawing characters")
-			}
-
-			// Check for ANSI codes
-			if !strings.Contains(got, "\x1b[") {
-				t.Error("GenerateBanner() missing ANSI escape sequences")
-			}
-
-			// Check for reset sequence at the end
-			if !strings.HasSuffix(got, "\x1b[0m") {
-				t.Error("GenerateBanner() should end with reset sequence")
-			}
-		})
-	}
-}
-
-func TestGenerateBackgroundTint(t *testing.T) {
-	tests := []struct {
-		name       string
-		configName string
-		want       string
-	}{
-		{
-			name:       "Green background",
-			configName: "SNTGreen",
-			want:       "\x1b[48;2;5;30;15m",
-		},
-		{
-			name:       "Purple background",
-			configName: "SNTPurple",
-			want:       "\x1b[48;2;20;10;35m",
-		},
-		{
-			name:       "Red background",
-			configName: "SNTRed",
-			want:       "\x1b[48;2;35;10;10m",
-		},
-		{
-			name:       "Snake background",
-			configName: "Snake",
-			want:       "\x1b[48;2;5;25;30m",
-		},
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			got := GenerateBackgroundTint(tt.configName)
-			if got != tt.want {
-				t.Errorf("GenerateBackgroundTint(%q) = %q, want %q", tt.configName, got, tt.want)
-			}
-		})
-	}
-}
-
 func TestAgentColorsConsistency(t *testing.T) {
 	// Verify that all color fields are populated for all agent types
 	testConfigs := []string{"SNTGreen", "SNTPurple", "SNTRed", "Snake", "Captain", "BlueTest", "Unknown"}
 
 	for _, config := range testConfigs {
 		t.Run(config, func(t *testing.T) {
-			colors := GetAgentColors(config)
+			colors := GetAgentColors(types.AgentConfig{Name: config})
 
 			if colors.BgDark == "" {
 				t.Errorf("BgDark is empty for %s", config)
@@ -230,3 +121,50 @@ func TestAgentColorsConsistency(t *testing.T) {
 		})
 	}
 }
+
+func TestGetAgentColorsFromConfig(t *testing.T) {
+	config := types.AgentConfig{
+		Name: "SNTGreen",
+		Role: types.RoleGoDeveloper,
+		Colors: types.AgentColors{
+			Background: "#102030",
+			TabColor:   "ff8800",
+		},
+	}
+	got := GetAgentColors(config)
+
+	if got.BgRGB != "rgb:10/20/30" || got.BgHex != "#102030" {
+		t.Errorf("background = %q / %q, want rgb:10/20/30 / #102030", got.BgRGB, got.BgHex)
+	}
+	if got.BgDark != "\x1b[48;2;16;32;48m" {
+		t.Errorf("BgDark = %q", got.BgDark)
+	}
+	if got.BgBright != "\x1b[48;2;255;136;0m" || got.TabHex != "#FF8800" {
+		t.Errorf("tab color = %q / %q", got.BgBright, got.TabHex)
+	}
+	// Unset fields keep the name palette
+	if got.FgHex != "#22C55E" || got.Emoji != "🟢" {
+		t.Errorf("foreground = %q, emoji = %q, want green palette", got.FgHex, got.Emoji)
+	}
+}
+
+func TestGetAgentColorsRoleFallback(t *testing.T) {
+	tests := []struct {
+		role      types.AgentRole
+		wantEmoji string
+	}{
+		{types.RoleReviewSGT, "🔴"},
+		{types.RoleCodeAuditor, "🔴"},
+		{types.RoleGoDeveloper, "🟢"},
+		{types.RoleImplementationSGT, "🟢"},
+		{types.RoleSupervisor, "🔵"},
+		{"Planner", "🔵"},
+		{"Docs", "⚪"},
+	}
+	for _, tt := range tests {
+		got := GetAgentColors(types.AgentConfig{Name: "Agent", Role: tt.role})
+		if got.Emoji != tt.wantEmoji {
+			t.Errorf("role %q: emoji = %q, want %q", tt.role, got.Emoji, tt.wantEmoji)
+		}
+	}
+}
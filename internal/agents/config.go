@@ -19,6 +19,12 @@ func LoadTeamsConfig(filepath string) (*types.TeamsConfig, error) {
 		return nil, err
 	}
 
+	for _, agent := range append(config.Agents, config.Supervisor) {
+		if err := validateAgentColors(agent); err != nil {
+			return nil, err
+		}
+	}
+
 	return &config, nil
 }
 
@@ -0,0 +1,99 @@
+package agents
+
+import (
+	"strconv"
+
+	"github.com/CLIAIMONITOR/internal/types"
+)
+
+// ConfigFieldAgent is the ConfigChange field used when a whole agent is
+// added to or removed from teams.yaml. Its values are ConfigAgentPresent
+// and ConfigAgentAbsent.
+const ConfigFieldAgent = "agent"
+
+// Values of a ConfigFieldAgent change
+const (
+	ConfigAgentPresent = "present"
+	ConfigAgentAbsent  = "absent"
+)
+
+// ConfigChange is one agent field that differs between two teams configs.
+// Field uses the teams.yaml key, e.g. "model" or "colors.tab_color".
+type ConfigChange struct {
+	AgentName string `json:"agent_name"`
+	Field     string `json:"field"`
+	OldValue  string `json:"old_value"`
+	NewValue  string `json:"new_value"`
+}
+
+// DiffConfigs returns the per-agent differences from old to new, matching
+// agents (and the supervisor) by name. Agents only in one config produce a
+// single ConfigFieldAgent change. Changes follow old's agent order, then
+// agents new to new in its order. A nil config is treated as empty.
+func DiffConfigs(old, new *types.TeamsConfig) []ConfigChange {
+	oldAgents := configAgents(old)
+	newAgents := configAgents(new)
+
+	newByName := make(map[string]types.AgentConfig, len(newAgents))
+	for _, cfg := range newAgents {
+		newByName[cfg.Name] = cfg
+	}
+	oldNames := make(map[string]bool, len(oldAgents))
+
+	changes := []ConfigChange{}
+	for _, before := range oldAgents {
+		oldNames[before.Name] = true
+		after, ok := newByName[before.Name]
+		if !ok {
+			changes = append(changes, ConfigChange{AgentName: before.Name, Field: ConfigFieldAgent, OldValue: ConfigAgentPresent, NewValue: ConfigAgentAbsent})
+			continue
+		}
+		beforeFields := agentConfigFields(before)
+		afterFields := agentConfigFields(after)
+		for i, field := range beforeFields {
+			if field.value != afterFields[i].value {
+				changes = append(changes, ConfigChange{AgentName: before.Name, Field: field.name, OldValue: field.value, NewValue: afterFields[i].value})
+			}
+		}
+	}
+	for _, after := range newAgents {
+		if !oldNames[after.Name] {
+			changes = append(changes, ConfigChange{AgentName: after.Name, Field: ConfigFieldAgent, OldValue: ConfigAgentAbsent, NewValue: ConfigAgentPresent})
+		}
+	}
+	return changes
+}
+
+// configAgents returns a config's agents followed by its supervisor, if named
+func configAgents(config *types.TeamsConfig) []types.AgentConfig {
+	if config == nil {
+		return nil
+	}
+	agents := append([]types.AgentConfig{}, config.Agents...)
+	if config.Supervisor.Name != "" {
+		agents = append(agents, config.Supervisor)
+	}
+	return agents
+}
+
+type configField struct {
+	name  string
+	value string
+}
+
+// agentConfigFields lists the compared fields of an agent config, always in
+// the same order
+func agentConfigFields(cfg types.AgentConfig) []configField {
+	return []configField{
+		{"model", cfg.Model},
+		{"role", string(cfg.Role)},
+		{"color", cfg.Color},
+		{"prefix", cfg.Prefix},
+		{"numbering", strconv.FormatBool(cfg.Numbering)},
+		{"prompt_file", cfg.PromptFile},
+		{"skip_permissions", strconv.FormatBool(cfg.SkipPermissions)},
+		{"colors.background", cfg.Colors.Background},
+		{"colors.foreground", cfg.Colors.Foreground},
+		{"colors.tab_color", cfg.Colors.TabColor},
+	}
+}
@@ -0,0 +1,43 @@
+package agents
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/CLIAIMONITOR/internal/types"
+)
+
+func TestDiffConfigs(t *testing.T) {
+	old := &types.TeamsConfig{
+		Agents: []types.AgentConfig{
+			{Name: "SNTGreen", Model: "claude-sonnet-4-5", Role: types.RoleGoDeveloper, Color: "#00cc66"},
+			{Name: "OpusPurple", Model: "claude-opus-4-5", Role: types.RoleCodeAuditor},
+		},
+		Supervisor: types.AgentConfig{Name: "Supervisor", Model: "claude-opus-4-5", Role: types.RoleSupervisor},
+	}
+	new := &types.TeamsConfig{
+		Agents: []types.AgentConfig{
+			{Name: "SNTGreen", Model: "claude-opus-4-5", Role: types.RoleGoDeveloper, Color: "#00cc66", Numbering: true},
+			{Name: "Security", Model: "claude-sonnet-4-5", Role: types.RoleSecurity},
+		},
+		Supervisor: types.AgentConfig{Name: "Supervisor", Model: "claude-opus-4-5", Role: types.RoleSupervisor, Colors: types.AgentColors{TabColor: "#ffd700"}},
+	}
+
+	want := []ConfigChange{
+		{AgentName: "SNTGreen", Field: "model", OldValue: "claude-sonnet-4-5", NewValue: "claude-opus-4-5"},
+		{AgentName: "SNTGreen", Field: "numbering", OldValue: "false", NewValue: "true"},
+		{AgentName: "OpusPurple", Field: ConfigFieldAgent, OldValue: ConfigAgentPresent, NewValue: ConfigAgentAbsent},
+		{AgentName: "Supervisor", Field: "colors.tab_color", OldValue: "", NewValue: "#ffd700"},
+		{AgentName: "Security", Field: ConfigFieldAgent, OldValue: ConfigAgentAbsent, NewValue: ConfigAgentPresent},
+	}
+	if got := DiffConfigs(old, new); !reflect.DeepEqual(got, want) {
+		t.Errorf("DiffConfigs() =\n%+v\nwant\n%+v", got, want)
+	}
+
+	if got := DiffConfigs(old, old); len(got) != 0 {
+		t.Errorf("DiffConfigs() of identical configs = %+v, want none", got)
+	}
+	if got := DiffConfigs(nil, old); len(got) != 3 {
+		t.Errorf("DiffConfigs(nil, old) returned %d changes, want 3 additions", len(got))
+	}
+}
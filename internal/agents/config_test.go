@@ -3,6 +3,7 @@ package agents
 import (
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/CLIAIMONITOR/internal/types"
@@ -204,3 +205,37 @@ func TestLoadTeamsConfigPartialConfig(t *testing.T) {
 		t.Errorf("expected empty supervisor name, got '%s'", config.Supervisor.Name)
 	}
 }
+
+func TestLoadTeamsConfigColors(t *testing.T) {
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "teams.yaml")
+
+	configYAML := `agents:
+  - name: TestAgent
+    role: Engineer
+    colors:
+      background: "#051E0F"
+      foreground: "#22C55E"
+      tab_color: "#22C55E"
+`
+	if err := os.WriteFile(configPath, []byte(configYAML), 0644); err != nil {
+		t.Fatalf("Failed to write test config: %v", err)
+	}
+
+	config, err := LoadTeamsConfig(configPath)
+	if err != nil {
+		t.Fatalf("LoadTeamsConfig() error = %v", err)
+	}
+	colors := config.Agents[0].Colors
+	if colors.Background != "#051E0F" || colors.Foreground != "#22C55E" || colors.TabColor != "#22C55E" {
+		t.Errorf("colors = %+v", colors)
+	}
+
+	invalid := strings.Replace(configYAML, `"#051E0F"`, `"dark green"`, 1)
+	if err := os.WriteFile(configPath, []byte(invalid), 0644); err != nil {
+		t.Fatalf("Failed to write test config: %v", err)
+	}
+	if _, err := LoadTeamsConfig(configPath); err == nil {
+		t.Error("LoadTeamsConfig() should reject an invalid background color")
+	}
+}
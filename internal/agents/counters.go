@@ -0,0 +1,108 @@
+package agents
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+const (
+	// countersLockTimeout is how long SaveCounters waits for another writer
+	countersLockTimeout = 5 * time.Second
+	// countersLockStale is the age after which a leftover lock file is ignored
+	countersLockStale = 30 * time.Second
+)
+
+// countersPath returns the file agent sequence counters persist to
+func (s *ProcessSpawner) countersPath() string {
+	return filepath.Join(s.basePath, "data", "agent_counters.json")
+}
+
+// LoadCounters restores agent sequence counters saved by SaveCounters so IDs
+// like team-sntgreen001 are not reissued after a restart. A missing file is
+// not an error. Loaded values never lower a counter already in use.
+func (s *ProcessSpawner) LoadCounters() error {
+	data, err := os.ReadFile(s.countersPath())
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read agent counters: %w", err)
+	}
+
+	var counters map[string]int
+	if err := json.Unmarshal(data, &counters); err != nil {
+		return fmt.Errorf("failed to parse agent counters: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for agentType, seq := range counters {
+		if seq > s.agentCounters[agentType] {
+			s.agentCounters[agentType] = seq
+		}
+	}
+	return nil
+}
+
+// SaveCounters writes agent sequence counters as a {"SNTGreen": 3} JSON map.
+// Writers are serialized with an O_EXCL lock file and the data is written to
+// a temp file and renamed into place.
+func (s *ProcessSpawner) SaveCounters() error {
+	s.mu.RLock()
+	data, err := json.MarshalIndent(s.agentCounters, "", "  ")
+	s.mu.RUnlock()
+	if err != nil {
+		return fmt.Errorf("failed to encode agent counters: %w", err)
+	}
+
+	path := s.countersPath()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create data directory: %w", err)
+	}
+
+	unlock, err := lockFile(path + ".lock")
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	tempPath := path + ".tmp"
+	if err := os.WriteFile(tempPath, data, 0644); err != nil {
+		return fmt.Errorf("failed to write agent counters: %w", err)
+	}
+	if err := os.Rename(tempPath, path); err != nil {
+		os.Remove(tempPath)
+		return fmt.Errorf("failed to replace agent counters: %w", err)
+	}
+	return nil
+}
+
+// lockFile acquires an exclusive lock by creating lockPath with O_EXCL. A lock
+// older than countersLockStale is assumed abandoned by a crashed process.
+func lockFile(lockPath string) (func(), error) {
+	deadline := time.Now().Add(countersLockTimeout)
+	for {
+		f, err := os.OpenFile(lockPath, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0644)
+		if err == nil {
+			fmt.Fprintf(f, "%d", os.Getpid())
+			f.Close()
+			return func() { os.Remove(lockPath) }, nil
+		}
+		if !errors.Is(err, os.ErrExist) {
+			return nil, fmt.Errorf("failed to create lock file: %w", err)
+		}
+
+		if info, statErr := os.Stat(lockPath); statErr == nil && time.Since(info.ModTime()) > countersLockStale {
+			os.Remove(lockPath)
+			continue
+		}
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("timed out waiting for lock %s", lockPath)
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+}
@@ -0,0 +1,47 @@
+package agents
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestCountersPersistAcrossRestart tests that agent IDs continue after a restart
+func TestCountersPersistAcrossRestart(t *testing.T) {
+	basePath := t.TempDir()
+
+	first := NewSpawner(basePath, "http://localhost:3000/mcp/sse", nil)
+	first.GenerateAgentID("SNTGreen")
+	first.GenerateAgentID("SNTGreen")
+	first.GenerateAgentID("Snake")
+	if err := first.SaveCounters(); err != nil {
+		t.Fatalf("SaveCounters failed: %v", err)
+	}
+
+	if _, err := os.Stat(filepath.Join(basePath, "data", "agent_counters.json.lock")); !os.IsNotExist(err) {
+		t.Error("Expected lock file to be removed after save")
+	}
+
+	second := NewSpawner(basePath, "http://localhost:3000/mcp/sse", nil)
+	if got := second.GenerateAgentID("SNTGreen"); got != "team-sntgreen003" {
+		t.Errorf("Expected team-sntgreen003 after restart, got %s", got)
+	}
+	if got := second.GenerateAgentID("Snake"); got != "team-snake002" {
+		t.Errorf("Expected team-snake002 after restart, got %s", got)
+	}
+}
+
+// TestLoadCountersInvalidFile tests that a corrupt counters file is reported
+func TestLoadCountersInvalidFile(t *testing.T) {
+	basePath := t.TempDir()
+	os.MkdirAll(filepath.Join(basePath, "data"), 0755)
+	os.WriteFile(filepath.Join(basePath, "data", "agent_counters.json"), []byte("not json"), 0644)
+
+	spawner := NewSpawner(basePath, "http://localhost:3000/mcp/sse", nil)
+	if err := spawner.LoadCounters(); err == nil {
+		t.Error("Expected error for invalid counters file")
+	}
+	if got := spawner.GenerateAgentID("Snake"); got != "team-snake001" {
+		t.Errorf("Expected counters to start fresh, got %s", got)
+	}
+}
@@ -0,0 +1,59 @@
+package agents
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// agentMCPConfig is the file format read by claude --mcp-config
+type agentMCPConfig struct {
+	MCPServers map[string]agentMCPServer `json:"mcpServers"`
+}
+
+// agentMCPServer is one MCP server connection in an agentMCPConfig
+type agentMCPServer struct {
+	Type    string            `json:"type"`
+	URL     string            `json:"url"`
+	Headers map[string]string `json:"headers,omitempty"`
+}
+
+// mcpConfigPath returns where an agent's MCP config is written; it matches
+// the pattern CleanupAgentFiles and CleanupAllAgentFiles remove
+func (s *ProcessSpawner) mcpConfigPath(agentID string) string {
+	return filepath.Join(s.configsPath, "mcp", fmt.Sprintf("%s-mcp.json", agentID))
+}
+
+// GenerateMCPConfigFile writes the agent's Claude MCP config to
+// configs/mcp/{agentID}-mcp.json, pointing it at mcpServerURL with its agent
+// ID header, and returns the file path
+func (s *ProcessSpawner) GenerateMCPConfigFile(agentID, mcpServerURL string) (string, error) {
+	config := agentMCPConfig{
+		MCPServers: map[string]agentMCPServer{
+			"cliaimonitor": {
+				Type: "http",
+				URL:  mcpServerURL,
+				Headers: map[string]string{
+					"X-Agent-ID": agentID,
+				},
+			},
+		},
+	}
+
+	configPath := s.mcpConfigPath(agentID)
+	if err := os.MkdirAll(filepath.Dir(configPath), 0755); err != nil {
+		return "", fmt.Errorf("failed to create mcp config dir: %w", err)
+	}
+
+	data, err := json.MarshalIndent(config, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal MCP config: %w", err)
+	}
+
+	if err := os.WriteFile(configPath, data, 0644); err != nil {
+		return "", fmt.Errorf("failed to write MCP config: %w", err)
+	}
+
+	return configPath, nil
+}
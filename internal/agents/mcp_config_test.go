@@ -0,0 +1,87 @@
+package agents
+
+import (
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/CLIAIMONITOR/internal/types"
+)
+
+// TestGenerateMCPConfigFile tests the per-agent MCP config written at spawn time
+func TestGenerateMCPConfigFile(t *testing.T) {
+	basePath := t.TempDir()
+	spawner := NewSpawner(basePath, "http://localhost:3000/mcp", nil)
+
+	path, err := spawner.GenerateMCPConfigFile("team-sntgreen001", "http://localhost:3000/mcp")
+	if err != nil {
+		t.Fatalf("GenerateMCPConfigFile failed: %v", err)
+	}
+	if want := filepath.Join(basePath, "configs", "mcp", "team-sntgreen001-mcp.json"); path != want {
+		t.Errorf("Expected path %s, got %s", want, path)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read config: %v", err)
+	}
+	var config agentMCPConfig
+	if err := json.Unmarshal(data, &config); err != nil {
+		t.Fatalf("Config is not valid JSON: %v", err)
+	}
+	server, ok := config.MCPServers["cliaimonitor"]
+	if !ok {
+		t.Fatalf("Expected cliaimonitor server, got %v", config.MCPServers)
+	}
+	if server.Type != "http" || server.URL != "http://localhost:3000/mcp" || server.Headers["X-Agent-ID"] != "team-sntgreen001" {
+		t.Errorf("Unexpected server config: %+v", server)
+	}
+
+	if err := spawner.CleanupAgentFiles("team-sntgreen001"); err != nil {
+		t.Fatalf("CleanupAgentFiles failed: %v", err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Error("Expected MCP config to be removed by CleanupAgentFiles")
+	}
+}
+
+// TestDryRunSkipsMCPConfig tests that dry runs don't leave MCP configs on disk
+func TestDryRunSkipsMCPConfig(t *testing.T) {
+	basePath := t.TempDir()
+	spawner := NewSpawner(basePath, "http://localhost:3000/mcp", nil)
+	spawner.DryRun = true
+
+	if _, err := spawner.SpawnAgent(types.AgentConfig{Name: "SNTGreen", Model: "claude-sonnet-4-5"}, "team-sntgreen001", t.TempDir(), ""); err != nil {
+		t.Fatalf("SpawnAgent failed: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(basePath, "configs", "mcp", "team-sntgreen001-mcp.json")); !os.IsNotExist(err) {
+		t.Errorf("Expected no MCP config for a dry run, got %v", err)
+	}
+}
+
+// TestFailedSpawnRemovesMCPConfig tests that a failed spawn cleans up its MCP config
+func TestFailedSpawnRemovesMCPConfig(t *testing.T) {
+	if _, err := exec.LookPath("wezterm.exe"); err == nil {
+		t.Skip("WezTerm is installed; spawning would open a real pane")
+	}
+	basePath := t.TempDir()
+	spawner := NewSpawner(basePath, "http://localhost:3000/mcp", nil)
+
+	if _, err := spawner.SpawnAgent(types.AgentConfig{Name: "SNTGreen", Model: "claude-sonnet-4-5"}, "team-sntgreen001", t.TempDir(), ""); err == nil {
+		t.Fatal("Expected spawn to fail without WezTerm")
+	}
+	if _, err := os.Stat(filepath.Join(basePath, "configs", "mcp", "team-sntgreen001-mcp.json")); !os.IsNotExist(err) {
+		t.Errorf("Expected MCP config to be removed after a failed spawn, got %v", err)
+	}
+}
+
+// TestBuildAgentCommandWithoutMCPConfig tests that the flag is omitted without a config
+func TestBuildAgentCommandWithoutMCPConfig(t *testing.T) {
+	cmd := buildAgentCommand(types.AgentConfig{Model: "claude-sonnet-4-5"}, "team-sntgreen001", "go", "")
+	want := `title team-sntgreen001 && claude --model claude-sonnet-4-5 --dangerously-skip-permissions "go"`
+	if cmd != want {
+		t.Errorf("Expected command %q, got %q", want, cmd)
+	}
+}
@@ -0,0 +1,158 @@
+package agents
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/CLIAIMONITOR/internal/types"
+)
+
+// mockBasePID is the first PID handed out by MockSpawner when OnSpawnAgent
+// is not set
+const mockBasePID = 10000
+
+// SpawnCall records the arguments of one MockSpawner spawn
+type SpawnCall struct {
+	Config        types.AgentConfig
+	AgentID       string
+	ProjectPath   string
+	InitialPrompt string
+	Headless      bool
+}
+
+// MockSpawner implements Spawner without WezTerm for unit tests. Spawns are
+// recorded in SpawnCalls; OnSpawnAgent, when set, chooses the PID and error
+// returned, otherwise each spawn succeeds with a fresh fake PID.
+type MockSpawner struct {
+	mu sync.Mutex
+
+	SpawnCalls   []SpawnCall
+	StopCalls    []string
+	OnSpawnAgent func(config types.AgentConfig, agentID, projectPath, initialPrompt string) (int, error)
+
+	running  map[string]int
+	counters map[string]int
+	nextPID  int
+}
+
+// NewMockSpawner creates an empty mock spawner
+func NewMockSpawner() *MockSpawner {
+	return &MockSpawner{
+		running:  make(map[string]int),
+		counters: make(map[string]int),
+		nextPID:  mockBasePID,
+	}
+}
+
+// SpawnAgent records the call and returns OnSpawnAgent's result
+func (m *MockSpawner) SpawnAgent(config types.AgentConfig, agentID string, projectPath string, initialPrompt string) (int, error) {
+	return m.SpawnAgentWithOptions(config, agentID, projectPath, initialPrompt, false)
+}
+
+// SpawnAgentWithOptions records the call and returns OnSpawnAgent's result.
+// Successful spawns are tracked as running.
+func (m *MockSpawner) SpawnAgentWithOptions(config types.AgentConfig, agentID string, projectPath string, initialPrompt string, headless bool) (int, error) {
+	m.mu.Lock()
+	m.SpawnCalls = append(m.SpawnCalls, SpawnCall{
+		Config:        config,
+		AgentID:       agentID,
+		ProjectPath:   projectPath,
+		InitialPrompt: initialPrompt,
+		Headless:      headless,
+	})
+	onSpawn := m.OnSpawnAgent
+	m.mu.Unlock()
+
+	var pid int
+	var err error
+	if onSpawn != nil {
+		pid, err = onSpawn(config, agentID, projectPath, initialPrompt)
+	} else {
+		m.mu.Lock()
+		m.nextPID++
+		pid = m.nextPID
+		m.mu.Unlock()
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	m.mu.Lock()
+	m.running[agentID] = pid
+	m.mu.Unlock()
+	return pid, nil
+}
+
+// StopAgent records the call and forgets the agent
+func (m *MockSpawner) StopAgent(agentID string) error {
+	return m.StopAgentWithReason(agentID, "")
+}
+
+// StopAgentWithReason records the call and forgets the agent. Stopping an
+// agent that is not running is an error, as with ProcessSpawner.
+func (m *MockSpawner) StopAgentWithReason(agentID string, reason string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.StopCalls = append(m.StopCalls, agentID)
+	if _, ok := m.running[agentID]; !ok {
+		return fmt.Errorf("agent %s not found", agentID)
+	}
+	delete(m.running, agentID)
+	return nil
+}
+
+// IsAgentRunning reports whether pid belongs to a spawned, unstopped agent
+func (m *MockSpawner) IsAgentRunning(pid int) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, running := range m.running {
+		if running == pid {
+			return true
+		}
+	}
+	return false
+}
+
+// GetRunningAgents returns a copy of the running agents
+func (m *MockSpawner) GetRunningAgents() map[string]int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	result := make(map[string]int, len(m.running))
+	for id, pid := range m.running {
+		result[id] = pid
+	}
+	return result
+}
+
+// GenerateAgentID mirrors ProcessSpawner's team-{type}{seq:03d} format
+func (m *MockSpawner) GenerateAgentID(agentType string) string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.counters[agentType]++
+	return fmt.Sprintf("team-%s%03d", strings.ToLower(agentType), m.counters[agentType])
+}
+
+// AssertSpawnedWith fails t unless an agent was spawned with both the given
+// config name and agent ID
+func (m *MockSpawner) AssertSpawnedWith(t testing.TB, configName, agentID string) {
+	t.Helper()
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, call := range m.SpawnCalls {
+		if call.Config.Name == configName && call.AgentID == agentID {
+			return
+		}
+	}
+	spawned := make([]string, 0, len(m.SpawnCalls))
+	for _, call := range m.SpawnCalls {
+		spawned = append(spawned, call.Config.Name+"/"+call.AgentID)
+	}
+	t.Errorf("expected spawn of %s/%s, got [%s]", configName, agentID, strings.Join(spawned, ", "))
+}
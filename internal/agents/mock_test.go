@@ -0,0 +1,54 @@
+package agents
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/CLIAIMONITOR/internal/types"
+)
+
+func TestMockSpawnerRecordsCalls(t *testing.T) {
+	m := NewMockSpawner()
+
+	id := m.GenerateAgentID("SNTGreen")
+	if id != "team-sntgreen001" {
+		t.Errorf("GenerateAgentID = %q, want team-sntgreen001", id)
+	}
+
+	pid, err := m.SpawnAgent(types.AgentConfig{Name: "SNTGreen"}, id, "/repo", "prompt")
+	if err != nil {
+		t.Fatalf("SpawnAgent failed: %v", err)
+	}
+	m.AssertSpawnedWith(t, "SNTGreen", id)
+	if !m.IsAgentRunning(pid) {
+		t.Error("Expected spawned agent to be running")
+	}
+
+	if err := m.StopAgent(id); err != nil {
+		t.Fatalf("StopAgent failed: %v", err)
+	}
+	if m.IsAgentRunning(pid) || len(m.StopCalls) != 1 {
+		t.Errorf("Expected agent stopped and call recorded, stops=%v", m.StopCalls)
+	}
+	if err := m.StopAgent(id); err == nil {
+		t.Error("Expected error stopping an agent twice")
+	}
+}
+
+func TestMockSpawnerOnSpawnAgent(t *testing.T) {
+	m := NewMockSpawner()
+	wantErr := errors.New("boom")
+	m.OnSpawnAgent = func(config types.AgentConfig, agentID, projectPath, initialPrompt string) (int, error) {
+		return 0, wantErr
+	}
+
+	if _, err := m.SpawnAgent(types.AgentConfig{Name: "Snake"}, "team-snake001", "", ""); !errors.Is(err, wantErr) {
+		t.Errorf("Expected configured error, got %v", err)
+	}
+	if len(m.SpawnCalls) != 1 {
+		t.Errorf("Expected failed spawn to be recorded, got %d calls", len(m.SpawnCalls))
+	}
+	if len(m.GetRunningAgents()) != 0 {
+		t.Error("Expected failed spawn not to be running")
+	}
+}
@@ -0,0 +1,70 @@
+package agents
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/CLIAIMONITOR/internal/logger"
+)
+
+// agentProcessRunning reports whether an agent PID is still alive
+var agentProcessRunning = (&ProcessSpawner{logger: logger.For("spawner")}).IsAgentRunning
+
+// CleanupOrphanedFiles removes agent files left behind by a crash: PID files
+// in data/pids whose process is no longer running, and MCP configs in
+// configs/mcp for agents without a live PID file. Agents that outlived the
+// crash keep their files. Returns how many files were removed.
+func CleanupOrphanedFiles(basePath string) (int, error) {
+	removed := 0
+	var lastErr error
+	remove := func(path string) {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			lastErr = fmt.Errorf("failed to remove %s: %w", path, err)
+			return
+		}
+		removed++
+	}
+
+	live := make(map[string]bool)
+	pidsDir := filepath.Join(basePath, "data", "pids")
+	entries, err := os.ReadDir(pidsDir)
+	if err != nil && !os.IsNotExist(err) {
+		return 0, fmt.Errorf("failed to read PID directory: %w", err)
+	}
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".pid") {
+			continue
+		}
+		path := filepath.Join(pidsDir, entry.Name())
+		data, err := os.ReadFile(path)
+		if err != nil {
+			lastErr = fmt.Errorf("failed to read PID file %s: %w", path, err)
+			continue
+		}
+		if pid, err := strconv.Atoi(strings.TrimSpace(string(data))); err == nil && pid > 0 && agentProcessRunning(pid) {
+			live[strings.TrimSuffix(entry.Name(), ".pid")] = true
+			continue
+		}
+		remove(path)
+	}
+
+	mcpDir := filepath.Join(basePath, "configs", "mcp")
+	entries, err = os.ReadDir(mcpDir)
+	if err != nil && !os.IsNotExist(err) {
+		return removed, fmt.Errorf("failed to read MCP config directory: %w", err)
+	}
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), "-mcp.json") {
+			continue
+		}
+		if live[strings.TrimSuffix(entry.Name(), "-mcp.json")] {
+			continue
+		}
+		remove(filepath.Join(mcpDir, entry.Name()))
+	}
+
+	return removed, lastErr
+}
@@ -0,0 +1,66 @@
+package agents
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestCleanupOrphanedFiles(t *testing.T) {
+	basePath := t.TempDir()
+	pidsDir := filepath.Join(basePath, "data", "pids")
+	mcpDir := filepath.Join(basePath, "configs", "mcp")
+	os.MkdirAll(pidsDir, 0755)
+	os.MkdirAll(mcpDir, 0755)
+
+	files := map[string]string{
+		filepath.Join(pidsDir, "team-alive001.pid"):         "4242",
+		filepath.Join(pidsDir, "team-dead001.pid"):          "5353",
+		filepath.Join(pidsDir, "team-garbled001.pid"):       "not-a-pid",
+		filepath.Join(pidsDir, "notes.txt"):                 "keep",
+		filepath.Join(mcpDir, "team-alive001-mcp.json"):     "{}",
+		filepath.Join(mcpDir, "team-dead001-mcp.json"):      "{}",
+		filepath.Join(mcpDir, "team-nopidfile001-mcp.json"): "{}",
+	}
+	for path, content := range files {
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	orig := agentProcessRunning
+	agentProcessRunning = func(pid int) bool { return pid == 4242 }
+	defer func() { agentProcessRunning = orig }()
+
+	removed, err := CleanupOrphanedFiles(basePath)
+	if err != nil {
+		t.Fatalf("CleanupOrphanedFiles failed: %v", err)
+	}
+	if removed != 4 {
+		t.Errorf("Expected 4 files removed, got %d", removed)
+	}
+
+	for _, kept := range []string{"team-alive001.pid", "notes.txt"} {
+		if _, err := os.Stat(filepath.Join(pidsDir, kept)); err != nil {
+			t.Errorf("Expected %s to be kept: %v", kept, err)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(mcpDir, "team-alive001-mcp.json")); err != nil {
+		t.Errorf("Expected live agent MCP config to be kept: %v", err)
+	}
+	for _, gone := range []string{
+		filepath.Join(pidsDir, "team-dead001.pid"),
+		filepath.Join(pidsDir, "team-garbled001.pid"),
+		filepath.Join(mcpDir, "team-dead001-mcp.json"),
+		filepath.Join(mcpDir, "team-nopidfile001-mcp.json"),
+	} {
+		if _, err := os.Stat(gone); !os.IsNotExist(err) {
+			t.Errorf("Expected %s to be removed", gone)
+		}
+	}
+
+	// Missing directories are not an error
+	if removed, err := CleanupOrphanedFiles(t.TempDir()); err != nil || removed != 0 {
+		t.Errorf("Expected 0, nil for empty base path, got %d, %v", removed, err)
+	}
+}
@@ -0,0 +1,131 @@
+package agents
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/CLIAIMONITOR/internal/types"
+)
+
+// SpawnHistorySize is how many spawn attempts the spawner remembers
+const SpawnHistorySize = 100
+
+// SpawnError describes a WezTerm command that failed while spawning an
+// agent, with its output kept separately for diagnosis
+type SpawnError struct {
+	Command  string `json:"command"`
+	ExitCode int    `json:"exit_code"` // -1 if the command did not run to exit
+	Stdout   string `json:"stdout"`
+	Stderr   string `json:"stderr"`
+	Err      error  `json:"-"`
+}
+
+// Error implements the error interface, including stderr when there is any
+func (e *SpawnError) Error() string {
+	if stderr := strings.TrimSpace(e.Stderr); stderr != "" {
+		return fmt.Sprintf("%s: %v: %s", e.Command, e.Err, stderr)
+	}
+	return fmt.Sprintf("%s: %v", e.Command, e.Err)
+}
+
+// Unwrap returns the underlying exec error
+func (e *SpawnError) Unwrap() error {
+	return e.Err
+}
+
+// SpawnAttempt records one SpawnAgentWithOptions call
+type SpawnAttempt struct {
+	AgentID     string      `json:"agent_id"`
+	AgentType   string      `json:"agent_type"`
+	Headless    bool        `json:"headless"`
+	Success     bool        `json:"success"`
+	PID         int         `json:"pid,omitempty"`
+	Error       string      `json:"error,omitempty"`
+	Diagnostics *SpawnError `json:"diagnostics,omitempty"`
+	AttemptedAt time.Time   `json:"attempted_at"`
+}
+
+// runSpawnCommand runs a WezTerm spawn command, capturing stdout and stderr
+// separately. stdout is returned for pane ID parsing; on failure the error
+// is a *SpawnError, which is also logged.
+func (s *ProcessSpawner) runSpawnCommand(cmd *exec.Cmd) ([]byte, error) {
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		spawnErr := &SpawnError{
+			Command:  strings.Join(cmd.Args, " "),
+			ExitCode: -1,
+			Stdout:   stdout.String(),
+			Stderr:   stderr.String(),
+			Err:      err,
+		}
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			spawnErr.ExitCode = exitErr.ExitCode()
+		}
+		s.logger.Error("spawn command failed",
+			"command", spawnErr.Command,
+			"exit_code", spawnErr.ExitCode,
+			"stdout", spawnErr.Stdout,
+			"stderr", spawnErr.Stderr,
+			"error", err)
+		return stdout.Bytes(), spawnErr
+	}
+	return stdout.Bytes(), nil
+}
+
+// recordSpawnAttempt adds a spawn result to the history ring buffer
+func (s *ProcessSpawner) recordSpawnAttempt(config types.AgentConfig, agentID string, headless bool, pid int, err error) {
+	attempt := SpawnAttempt{
+		AgentID:     agentID,
+		AgentType:   config.Name,
+		Headless:    headless,
+		Success:     err == nil,
+		PID:         pid,
+		AttemptedAt: time.Now(),
+	}
+	if err != nil {
+		attempt.Error = err.Error()
+		var spawnErr *SpawnError
+		if errors.As(err, &spawnErr) {
+			attempt.Diagnostics = spawnErr
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.spawnHistory) < SpawnHistorySize {
+		s.spawnHistory = append(s.spawnHistory, attempt)
+		return
+	}
+	s.spawnHistory[s.spawnHistoryNext] = attempt
+	s.spawnHistoryNext = (s.spawnHistoryNext + 1) % SpawnHistorySize
+}
+
+// SpawnHistory returns up to limit of the most recent spawn attempts, newest
+// first. A limit of zero or less returns all remembered attempts.
+func (s *ProcessSpawner) SpawnHistory(limit int) []SpawnAttempt {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	n := len(s.spawnHistory)
+	if limit <= 0 || limit > n {
+		limit = n
+	}
+	history := make([]SpawnAttempt, 0, limit)
+	// The newest entry sits just before spawnHistoryNext once the buffer wraps
+	newest := n - 1
+	if n == SpawnHistorySize {
+		newest = (s.spawnHistoryNext - 1 + n) % n
+	}
+	for i := 0; i < limit; i++ {
+		history = append(history, s.spawnHistory[(newest-i+n)%n])
+	}
+	return history
+}
@@ -0,0 +1,92 @@
+package agents
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+
+	"github.com/CLIAIMONITOR/internal/types"
+)
+
+// TestSpawnCommandHelperProcess is run as a failing spawn command by
+// TestRunSpawnCommandCapturesDiagnostics rather than as a test
+func TestSpawnCommandHelperProcess(t *testing.T) {
+	if os.Getenv("SPAWN_HELPER") != "1" {
+		return
+	}
+	fmt.Fprint(os.Stdout, "partial output")
+	fmt.Fprint(os.Stderr, "no such pane")
+	os.Exit(2)
+}
+
+// TestRunSpawnCommandCapturesDiagnostics tests that failures keep stdout and stderr apart
+func TestRunSpawnCommandCapturesDiagnostics(t *testing.T) {
+	spawner := NewSpawner(t.TempDir(), "", nil)
+
+	cmd := exec.Command(os.Args[0], "-test.run=^TestSpawnCommandHelperProcess$")
+	cmd.Env = append(os.Environ(), "SPAWN_HELPER=1")
+	_, err := spawner.runSpawnCommand(cmd)
+
+	var spawnErr *SpawnError
+	if !errors.As(err, &spawnErr) {
+		t.Fatalf("Expected *SpawnError, got %v", err)
+	}
+	if spawnErr.ExitCode != 2 || spawnErr.Stdout != "partial output" || spawnErr.Stderr != "no such pane" {
+		t.Errorf("Unexpected diagnostics: %+v", spawnErr)
+	}
+	if !strings.Contains(spawnErr.Command, "TestSpawnCommandHelperProcess") {
+		t.Errorf("Expected command to be recorded, got %q", spawnErr.Command)
+	}
+	if !strings.Contains(err.Error(), "no such pane") {
+		t.Errorf("Expected stderr in error message, got %q", err.Error())
+	}
+
+	_, err = spawner.runSpawnCommand(exec.Command("definitely-not-a-wezterm-binary"))
+	if !errors.As(err, &spawnErr) || spawnErr.ExitCode != -1 {
+		t.Errorf("Expected exit code -1 for a command that never ran, got %v", err)
+	}
+}
+
+// TestSpawnHistory tests that attempts are kept newest first in a bounded ring
+func TestSpawnHistory(t *testing.T) {
+	spawner := NewSpawner(t.TempDir(), "", nil)
+	spawner.DryRun = true
+	config := types.AgentConfig{Name: "SNTGreen", Model: "claude-sonnet-4-5"}
+
+	if _, err := spawner.SpawnAgent(config, "team-sntgreen001", t.TempDir(), ""); err != nil {
+		t.Fatalf("SpawnAgent failed: %v", err)
+	}
+	failure := &SpawnError{Command: "wezterm.exe cli spawn", ExitCode: 1, Stderr: "boom", Err: errors.New("exit status 1")}
+	spawner.recordSpawnAttempt(config, "team-sntgreen002", true, 0, fmt.Errorf("failed to spawn agent window: %w", failure))
+
+	history := spawner.SpawnHistory(20)
+	if len(history) != 2 {
+		t.Fatalf("Expected 2 attempts, got %d", len(history))
+	}
+	if history[0].AgentID != "team-sntgreen002" || history[0].Success || history[0].Diagnostics != failure {
+		t.Errorf("Unexpected newest attempt: %+v", history[0])
+	}
+	if history[1].AgentID != "team-sntgreen001" || !history[1].Success || history[1].PID != DryRunPIDBase+1 {
+		t.Errorf("Unexpected oldest attempt: %+v", history[1])
+	}
+
+	for i := 0; i < SpawnHistorySize+5; i++ {
+		spawner.recordSpawnAttempt(config, fmt.Sprintf("agent-%d", i), false, i, nil)
+	}
+	history = spawner.SpawnHistory(0)
+	if len(history) != SpawnHistorySize {
+		t.Fatalf("Expected history capped at %d, got %d", SpawnHistorySize, len(history))
+	}
+	if newest := fmt.Sprintf("agent-%d", SpawnHistorySize+4); history[0].AgentID != newest {
+		t.Errorf("Expected newest %s, got %s", newest, history[0].AgentID)
+	}
+	if history[SpawnHistorySize-1].AgentID != "agent-5" {
+		t.Errorf("Expected oldest agent-5, got %s", history[SpawnHistorySize-1].AgentID)
+	}
+	if got := spawner.SpawnHistory(3); len(got) != 3 || got[0].AgentID != history[0].AgentID {
+		t.Errorf("Expected 3 newest attempts, got %+v", got)
+	}
+}
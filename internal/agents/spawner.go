@@ -3,7 +3,7 @@ package agents
 import (
 	"encoding/json"
 	"fmt"
-	"log"
+	"log/slog"
 	"os"
 	"os/exec"
 	"path/filepath"
@@ -14,12 +14,16 @@ import (
 	"time"
 
 	"github.com/CLIAIMONITOR/internal/instance"
+	"github.com/CLIAIMONITOR/internal/logger"
 	"github.com/CLIAIMONITOR/internal/memory"
 	"github.com/CLIAIMONITOR/internal/quotes"
 	"github.com/CLIAIMONITOR/internal/types"
 	"github.com/CLIAIMONITOR/internal/wezterm"
 )
 
+// DryRunPIDBase is added to an agent's sequence number to form its dry-run PID
+const DryRunPIDBase = 1000
+
 // Spawner manages agent process lifecycle
 type Spawner interface {
 	SpawnAgent(config types.AgentConfig, agentID string, projectPath string, initialPrompt string) (pid int, err error)
@@ -42,6 +46,22 @@ type ProcessSpawner struct {
 	agentPanes     map[string]int // agentID -> WezTerm pane ID
 	agentCounters  map[string]int // agentType -> sequence counter
 	memDB          memory.MemoryDB
+	logger         *slog.Logger
+
+	// DryRun skips WezTerm entirely: SpawnAgent records the agent with a
+	// fake PID (DryRunPIDBase + sequence) instead of starting Claude
+	DryRun bool
+
+	// ColorPalette is the set of pane colors AllocateColor hands out
+	// (default DefaultColorPalette). agentColors maps running agents to
+	// palette indexes; freeColors holds indexes released by stopped agents.
+	// paneColors holds the colors actually applied to each running agent's
+	// pane, teams.yaml overrides included.
+	ColorPalette []AgentColors
+	colorIndex   int
+	agentColors  map[string]int
+	freeColors   []int
+	paneColors   map[string]AgentColors
 
 	// Headless agents: spawn in dedicated hidden "Agents" workspace
 	agentWindowID int // Window ID for headless agents (-1 = not created yet)
@@ -50,11 +70,15 @@ type ProcessSpawner struct {
 	// Each tab holds up to 9 agents in a 3x3 grid
 	visibleTabID    int // Current tab ID for visible agents (-1 = no tab yet)
 	visibleTabPanes int // Count of panes in current visible tab
+
+	// Recent spawn attempts, a ring buffer of up to SpawnHistorySize
+	spawnHistory     []SpawnAttempt
+	spawnHistoryNext int
 }
 
 // NewSpawner creates a new process spawner
 func NewSpawner(basePath string, mcpServerURL string, memDB memory.MemoryDB) *ProcessSpawner {
-	return &ProcessSpawner{
+	s := &ProcessSpawner{
 		basePath:        basePath,
 		mcpServerURL:    mcpServerURL,
 		scriptsPath:     filepath.Join(basePath, "scripts"),
@@ -62,11 +86,20 @@ func NewSpawner(basePath string, mcpServerURL string, memDB memory.MemoryDB) *Pr
 		runningAgents:   make(map[string]int),
 		agentPanes:      make(map[string]int),
 		agentCounters:   make(map[string]int),
+		ColorPalette:    DefaultColorPalette(),
+		agentColors:     make(map[string]int),
 		memDB:           memDB,
+		logger:          logger.For("spawner"),
 		agentWindowID:   -1, // No headless window yet
 		visibleTabID:    -1, // No visible agent tab yet
 		visibleTabPanes: 0,
 	}
+
+	// Resume agent ID sequences from the previous run
+	if err := s.LoadCounters(); err != nil {
+		s.logger.Warn("failed to load agent counters", "error", err)
+	}
+	return s
 }
 
 // SetMemoryDB sets the memory database for the spawner
@@ -74,6 +107,11 @@ func (s *ProcessSpawner) SetMemoryDB(db memory.MemoryDB) {
 	s.memDB = db
 }
 
+// SetLogger sets the structured logger used by the spawner
+func (s *ProcessSpawner) SetLogger(l *slog.Logger) {
+	s.logger = logger.WithComponent(l, "spawner")
+}
+
 // PaneInfo holds WezTerm pane information for dynamic grid layout
 type PaneInfo struct {
 	PaneID   int `json:"pane_id"`
@@ -121,7 +159,7 @@ func (s *ProcessSpawner) getSpawnTarget() (needsNewWindow, needsNewTab bool, spl
 	// Query current panes
 	tabPanes, err := s.getAgentWindowPanes()
 	if err != nil {
-		log.Printf("[SPAWNER] Error querying panes: %v, creating new window", err)
+		s.logger.Error("error querying panes, creating new window", "error", err)
 		return true, false, 0, ""
 	}
 
@@ -194,13 +232,13 @@ func (s *ProcessSpawner) getVisibleSpawnTarget() (needsNewTab bool, splitFromPan
 	cmd := exec.Command("wezterm.exe", "cli", "list", "--format", "json")
 	output, err := cmd.Output()
 	if err != nil {
-		log.Printf("[SPAWNER] Error querying panes for visible spawn: %v", err)
+		s.logger.Error("error querying panes for visible spawn", "error", err)
 		return true, 0, "" // Create new tab using pane 0
 	}
 
 	var allPanes []PaneInfo
 	if err := json.Unmarshal(output, &allPanes); err != nil {
-		log.Printf("[SPAWNER] Error parsing panes: %v", err)
+		s.logger.Error("error parsing panes", "error", err)
 		return true, 0, ""
 	}
 
@@ -216,7 +254,7 @@ func (s *ProcessSpawner) getVisibleSpawnTarget() (needsNewTab bool, splitFromPan
 	}
 
 	if captainWindowID < 0 {
-		log.Printf("[SPAWNER] Could not find Captain's window, using pane 0")
+		s.logger.Warn("could not find Captain's window, using pane 0")
 		return true, 0, ""
 	}
 
@@ -286,13 +324,18 @@ func (s *ProcessSpawner) GenerateAgentID(agentType string) string {
 
 	// Increment counter for this agent type
 	s.agentCounters[agentType]++
-	seq := s.agentCounters[agentType]
+	return formatAgentID(agentType, s.agentCounters[agentType])
+}
 
-	// Normalize agent type to lowercase for team ID
-	normalizedType := strings.ToLower(agentType)
+// PreviewAgentID returns the ID GenerateAgentID would return next without
+// consuming the sequence number
+func (s *ProcessSpawner) PreviewAgentID(agentType string) string {
+	return formatAgentID(agentType, s.GetNextSequence(agentType))
+}
 
-	// Format: team-{type}{seq:03d}
-	return fmt.Sprintf("team-%s%03d", normalizedType, seq)
+// formatAgentID formats a team ID: team-{type}{seq:03d}
+func formatAgentID(agentType string, seq int) string {
+	return fmt.Sprintf("team-%s%03d", strings.ToLower(agentType), seq)
 }
 
 // GetNextSequence returns the next sequence number for an agent type (for preview)
@@ -329,22 +372,47 @@ func (s *ProcessSpawner) SpawnAgent(config types.AgentConfig, agentID string, pr
 // SpawnAgentWithOptions launches a team agent in WezTerm with visibility control
 // headless=true: spawns in hidden "Agents" workspace with 3x3 grid (Captain monitors via wezterm_get_text)
 // headless=false: spawns as a new tab in Captain's window (visible to user)
+// Every attempt is recorded in SpawnHistory.
 func (s *ProcessSpawner) SpawnAgentWithOptions(config types.AgentConfig, agentID string, projectPath string, initialPrompt string, headless bool) (int, error) {
+	pid, err := s.spawnAgent(config, agentID, projectPath, initialPrompt, headless)
+	s.recordSpawnAttempt(config, agentID, headless, pid, err)
+	return pid, err
+}
+
+// spawnAgent does the work of SpawnAgentWithOptions
+func (s *ProcessSpawner) spawnAgent(config types.AgentConfig, agentID string, projectPath string, initialPrompt string, headless bool) (int, error) {
 	// Serialize spawns to prevent race conditions when determining spawn target
 	s.spawnMu.Lock()
 	defer s.spawnMu.Unlock()
 
-	// Escape the initial prompt for shell
-	escapedPrompt := strings.ReplaceAll(initialPrompt, `"`, `\"`)
-	escapedPrompt = strings.ReplaceAll(escapedPrompt, `'`, `''`)
+	mcpConfigPath := ""
+	if s.mcpServerURL != "" {
+		mcpConfigPath = s.mcpConfigPath(agentID)
+	}
+	cmdChain := buildAgentCommand(config, agentID, initialPrompt, mcpConfigPath)
 
-	// Build command: set title and run Claude directly
-	cmdChain := fmt.Sprintf(
-		`title %s && claude --model %s --dangerously-skip-permissions "%s"`,
-		agentID,
-		config.Model,
-		escapedPrompt,
-	)
+	if s.DryRun {
+		pid := s.dryRunPID(agentID)
+		s.mu.Lock()
+		s.runningAgents[agentID] = pid
+		s.mu.Unlock()
+		s.logger.Info("dry_run_spawn", "agent_id", agentID, "pid", pid, "headless", headless, "project_path", projectPath, "command", cmdChain)
+		return pid, nil
+	}
+
+	// The MCP config is only needed by a real claude process; remove it
+	// again if the spawn fails
+	spawned := false
+	if mcpConfigPath != "" {
+		if _, err := s.GenerateMCPConfigFile(agentID, s.mcpServerURL); err != nil {
+			return 0, err
+		}
+		defer func() {
+			if !spawned {
+				os.Remove(mcpConfigPath)
+			}
+		}()
+	}
 
 	var cmd *exec.Cmd
 	var paneID int
@@ -359,14 +427,14 @@ func (s *ProcessSpawner) SpawnAgentWithOptions(config types.AgentConfig, agentID
 			needsNewWindow, needsNewTab, splitFromPaneID, splitDirection := s.getSpawnTarget()
 
 			if needsNewWindow {
-				log.Printf("[SPAWNER] Creating headless agent window in Agents workspace")
+				s.logger.Info("creating headless agent window in Agents workspace")
 				cmd = exec.Command("wezterm.exe", "cli", "spawn",
 					"--new-window",
 					"--workspace", "Agents",
 					"--cwd", projectPath,
 					"--", "cmd.exe")
 
-				output, spawnErr = cmd.CombinedOutput()
+				output, spawnErr = s.runSpawnCommand(cmd)
 				if spawnErr != nil {
 					return 0, fmt.Errorf("failed to spawn agent window: %w", spawnErr)
 				}
@@ -381,7 +449,7 @@ func (s *ProcessSpawner) SpawnAgentWithOptions(config types.AgentConfig, agentID
 							for _, p := range panes {
 								if p.PaneID == paneID {
 									s.agentWindowID = p.WindowID
-									log.Printf("[SPAWNER] Headless window created: window_id=%d, pane=%d", s.agentWindowID, paneID)
+									s.logger.Info("headless window created", "window_id", s.agentWindowID, "pane_id", paneID)
 									titleCmd := exec.Command("wezterm.exe", "cli", "set-window-title",
 										"--window-id", strconv.Itoa(s.agentWindowID),
 										"Agent Squad (Headless)")
@@ -393,23 +461,23 @@ func (s *ProcessSpawner) SpawnAgentWithOptions(config types.AgentConfig, agentID
 					}
 				}
 			} else if needsNewTab {
-				log.Printf("[SPAWNER] Creating new tab in headless window (pane %d)", splitFromPaneID)
+				s.logger.Info("creating new tab in headless window", "pane_id", splitFromPaneID)
 				cmd = exec.Command("wezterm.exe", "cli", "spawn",
 					"--pane-id", strconv.Itoa(splitFromPaneID),
 					"--cwd", projectPath,
 					"--", "cmd.exe")
-				output, spawnErr = cmd.CombinedOutput()
+				output, spawnErr = s.runSpawnCommand(cmd)
 				if spawnErr != nil {
 					return 0, fmt.Errorf("failed to spawn new tab: %w", spawnErr)
 				}
 			} else {
-				log.Printf("[SPAWNER] Splitting pane %d %s", splitFromPaneID, splitDirection)
+				s.logger.Info("splitting pane", "pane_id", splitFromPaneID, "direction", splitDirection)
 				cmd = exec.Command("wezterm.exe", "cli", "split-pane",
 					"--pane-id", strconv.Itoa(splitFromPaneID),
 					"--"+splitDirection,
 					"--cwd", projectPath,
 					"--", "cmd.exe")
-				output, spawnErr = cmd.CombinedOutput()
+				output, spawnErr = s.runSpawnCommand(cmd)
 				if spawnErr != nil {
 					return 0, fmt.Errorf("failed to split pane: %w", spawnErr)
 				}
@@ -420,26 +488,26 @@ func (s *ProcessSpawner) SpawnAgentWithOptions(config types.AgentConfig, agentID
 
 			if needsNewTab {
 				// Create new tab in Captain's window (use pane 0 as reference)
-				log.Printf("[SPAWNER] Creating new visible agent tab in Captain window for %s", agentID)
+				s.logger.Info("creating new visible agent tab in Captain window", "agent_id", agentID)
 				cmd = exec.Command("wezterm.exe", "cli", "spawn",
 					"--pane-id", "0",
 					"--cwd", projectPath,
 					"--", "cmd.exe")
 
-				output, spawnErr = cmd.CombinedOutput()
+				output, spawnErr = s.runSpawnCommand(cmd)
 				if spawnErr != nil {
 					return 0, fmt.Errorf("failed to spawn visible agent tab: %w", spawnErr)
 				}
 			} else {
 				// Split existing pane in visible agent tab
-				log.Printf("[SPAWNER] Splitting visible pane %d %s for %s", splitFromPaneID, splitDirection, agentID)
+				s.logger.Info("splitting visible pane", "pane_id", splitFromPaneID, "direction", splitDirection, "agent_id", agentID)
 				cmd = exec.Command("wezterm.exe", "cli", "split-pane",
 					"--pane-id", strconv.Itoa(splitFromPaneID),
 					"--"+splitDirection,
 					"--cwd", projectPath,
 					"--", "cmd.exe")
 
-				output, spawnErr = cmd.CombinedOutput()
+				output, spawnErr = s.runSpawnCommand(cmd)
 				if spawnErr != nil {
 					return 0, fmt.Errorf("failed to split visible pane: %w", spawnErr)
 				}
@@ -455,9 +523,11 @@ func (s *ProcessSpawner) SpawnAgentWithOptions(config types.AgentConfig, agentID
 		}
 
 		if paneID > 0 {
-			log.Printf("[SPAWNER] Agent %s spawned in pane %d (headless=%v)", agentID, paneID, headless)
+			s.logger.Info("agent spawned", "agent_id", agentID, "pane_id", paneID, "headless", headless)
 
-			colors := GetAgentColors(config.Name)
+			// Agents keep their config's colors; a palette color keeps
+			// clashing or uncolored panes distinct
+			colors := s.assignPaneColors(agentID, config)
 			time.Sleep(300 * time.Millisecond)
 
 			// Set background color
@@ -472,9 +542,9 @@ func (s *ProcessSpawner) SpawnAgentWithOptions(config types.AgentConfig, agentID
 			sendCmd := exec.Command("wezterm.exe", "cli", "send-text", "--pane-id", paneIDStr, "--no-paste")
 			sendCmd.Stdin = strings.NewReader(cmdChain + "\r\n")
 			if sendErr := sendCmd.Run(); sendErr != nil {
-				log.Printf("[SPAWNER] Warning: Failed to send command to pane %d: %v", paneID, sendErr)
+				s.logger.Warn("failed to send command to pane", "pane_id", paneID, "error", sendErr)
 			} else {
-				log.Printf("[SPAWNER] Command sent to pane %d for agent %s", paneID, agentID)
+				s.logger.Info("command sent to pane", "pane_id", paneID, "agent_id", agentID)
 			}
 
 			// Set tab title for visible agents
@@ -484,7 +554,7 @@ func (s *ProcessSpawner) SpawnAgentWithOptions(config types.AgentConfig, agentID
 				tabTitleCmd.Run()
 			}
 		} else {
-			log.Printf("[SPAWNER] Warning: Could not parse pane ID from output: %s", paneIDStr)
+			s.logger.Warn("could not parse pane ID from output", "output", paneIDStr)
 			paneID = -1
 		}
 	} else {
@@ -495,7 +565,7 @@ func (s *ProcessSpawner) SpawnAgentWithOptions(config types.AgentConfig, agentID
 	if cmd.Process != nil {
 		pid = cmd.Process.Pid
 	}
-	log.Printf("\"%s\" - %s", quotes.SpawnQuote(), agentID)
+	s.logger.Info(fmt.Sprintf("%q", quotes.SpawnQuote()), "agent_id", agentID)
 
 	if cmd != nil && cmd.Process != nil {
 		go func() {
@@ -507,9 +577,57 @@ func (s *ProcessSpawner) SpawnAgentWithOptions(config types.AgentConfig, agentID
 		s.SetAgentPaneID(agentID, paneID)
 	}
 
+	spawned = true
 	return pid, nil
 }
 
+// PreviewSpawn returns the dry-run PID and the command SpawnAgent would run
+// for an agent, without starting or recording it
+func (s *ProcessSpawner) PreviewSpawn(config types.AgentConfig, agentID string, projectPath string, initialPrompt string, headless bool) (int, string) {
+	pid := s.dryRunPID(agentID)
+	mcpConfigPath := ""
+	if s.mcpServerURL != "" {
+		mcpConfigPath = s.mcpConfigPath(agentID)
+	}
+	cmdChain := buildAgentCommand(config, agentID, initialPrompt, mcpConfigPath)
+	s.logger.Info("dry_run_spawn", "agent_id", agentID, "pid", pid, "headless", headless, "project_path", projectPath, "command", cmdChain, "preview", true)
+	return pid, cmdChain
+}
+
+// buildAgentCommand builds the command sent to an agent's pane: set the
+// window title and run Claude directly, connected to the MCP servers in
+// mcpConfigPath when it is set
+func buildAgentCommand(config types.AgentConfig, agentID string, initialPrompt string, mcpConfigPath string) string {
+	// Escape the initial prompt for shell
+	escapedPrompt := strings.ReplaceAll(initialPrompt, `"`, `\"`)
+	escapedPrompt = strings.ReplaceAll(escapedPrompt, `'`, `''`)
+
+	mcpFlag := ""
+	if mcpConfigPath != "" {
+		mcpFlag = fmt.Sprintf(` --mcp-config "%s"`, mcpConfigPath)
+	}
+
+	return fmt.Sprintf(
+		`title %s && claude%s --model %s --dangerously-skip-permissions "%s"`,
+		agentID,
+		mcpFlag,
+		config.Model,
+		escapedPrompt,
+	)
+}
+
+// dryRunPID returns DryRunPIDBase plus the sequence number at the end of
+// agentID, or plus the number of tracked agents if it has none
+func (s *ProcessSpawner) dryRunPID(agentID string) int {
+	digits := agentID[strings.LastIndexFunc(agentID, func(r rune) bool { return r < '0' || r > '9' })+1:]
+	if seq, err := strconv.Atoi(digits); err == nil {
+		return DryRunPIDBase + seq
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return DryRunPIDBase + len(s.runningAgents) + 1
+}
+
 // StopAgent terminates an agent process (backward compatible - no reason)
 func (s *ProcessSpawner) StopAgent(agentID string) error {
 	return s.StopAgentWithReason(agentID, "manual stop")
@@ -517,20 +635,21 @@ func (s *ProcessSpawner) StopAgent(agentID string) error {
 
 // StopAgentWithReason terminates an agent process with a specific reason
 func (s *ProcessSpawner) StopAgentWithReason(agentID string, reason string) error {
-	log.Printf("\"%s\" - %s", quotes.ShutdownQuote(), agentID)
+	s.logger.Info(fmt.Sprintf("%q", quotes.ShutdownQuote()), "agent_id", agentID)
 
 	// 1. Remove from running agents map
 	s.mu.Lock()
 	delete(s.runningAgents, agentID)
+	s.releaseColor(agentID)
 	s.mu.Unlock()
 
 	// 6. Try to kill by WezTerm pane ID first (most reliable method)
 	if paneID, ok := s.GetAgentPaneID(agentID); ok && paneID > 0 {
-		log.Printf("[SPAWNER] Killing agent %s via pane ID %d", agentID, paneID)
+		s.logger.Info("killing agent via pane ID", "agent_id", agentID, "pane_id", paneID)
 		if err := s.KillByPaneID(paneID); err != nil {
-			log.Printf("[SPAWNER] Warning: Failed to kill agent %s by pane ID %d: %v", agentID, paneID, err)
+			s.logger.Warn("failed to kill agent by pane ID", "agent_id", agentID, "pane_id", paneID, "error", err)
 		} else {
-			log.Printf("[SPAWNER] Successfully killed agent %s via pane ID %d", agentID, paneID)
+			s.logger.Info("killed agent via pane ID", "agent_id", agentID, "pane_id", paneID)
 		}
 		// Always remove pane ID from tracking (pane is either closed or already gone)
 		s.mu.Lock()
@@ -542,34 +661,34 @@ func (s *ProcessSpawner) StopAgentWithReason(agentID string, reason string) erro
 	// PID file contains PowerShell process PID inside the terminal
 	pid, err := s.GetAgentPIDFromFile(agentID)
 	if err == nil && pid > 0 {
-		log.Printf("[SPAWNER] Killing agent %s (PID: %d) and child processes", agentID, pid)
+		s.logger.Info("killing agent and child processes", "agent_id", agentID, "pid", pid)
 
 		// Kill any claude.exe child processes
 		if err := s.KillChildClaude(pid); err != nil {
-			log.Printf("[SPAWNER] Warning: Failed to kill claude.exe child for agent %s (PID %d): %v", agentID, pid, err)
+			s.logger.Warn("failed to kill claude.exe child", "agent_id", agentID, "pid", pid, "error", err)
 		}
 
 		// Kill the PowerShell process (this closes the terminal tab)
 		if err := instance.KillProcess(pid); err != nil {
-			log.Printf("[SPAWNER] Warning: Failed to kill PowerShell by PID for agent %s (PID %d): %v", agentID, pid, err)
+			s.logger.Warn("failed to kill PowerShell by PID", "agent_id", agentID, "pid", pid, "error", err)
 		}
 
 		// Clean up PID file
 		if err := s.CleanupAgentPIDFile(agentID); err != nil {
-			log.Printf("[SPAWNER] Warning: Failed to cleanup PID file for agent %s: %v", agentID, err)
+			s.logger.Warn("failed to cleanup PID file", "agent_id", agentID, "error", err)
 		}
 	} else if err != nil {
-		log.Printf("[SPAWNER] Warning: Failed to get agent PID from file for agent %s: %v", agentID, err)
+		s.logger.Warn("failed to get agent PID from file", "agent_id", agentID, "error", err)
 	}
 
 	// 8. Also try killing by window title (catches any stragglers)
 	if err := s.KillByWindowTitle(agentID); err != nil {
-		log.Printf("[SPAWNER] Warning: Failed to kill agent %s by window title: %v", agentID, err)
+		s.logger.Warn("failed to kill agent by window title", "agent_id", agentID, "error", err)
 	}
 
 	// 9. Kill any remaining powershell processes with our temp script
 	if err := s.KillByTempScript(agentID); err != nil {
-		log.Printf("[SPAWNER] Warning: Failed to kill agent %s by temp script: %v", agentID, err)
+		s.logger.Warn("failed to kill agent by temp script", "agent_id", agentID, "error", err)
 	}
 
 	return nil
@@ -649,7 +768,7 @@ func (s *ProcessSpawner) IsAgentRunning(pid int) bool {
 	cmd := exec.Command("tasklist", "/FI", fmt.Sprintf("PID eq %d", pid), "/NH")
 	output, err := cmd.Output()
 	if err != nil {
-		log.Printf("[SPAWNER] Warning: Failed to check if PID %d is running: %v", pid, err)
+		s.logger.Warn("failed to check if PID is running", "pid", pid, "error", err)
 		return false
 	}
 	// If process exists, output contains the PID
@@ -673,6 +792,7 @@ func (s *ProcessSpawner) RemoveAgent(agentID string) {
 	s.mu.Lock()
 	delete(s.runningAgents, agentID)
 	delete(s.agentPanes, agentID)
+	s.releaseColor(agentID)
 	s.mu.Unlock()
 }
 
@@ -692,8 +812,7 @@ func (s *ProcessSpawner) GetAgentByPID(pid int) string {
 // CleanupAgentFiles removes MCP config, prompt files, and PID file for an agent
 func (s *ProcessSpawner) CleanupAgentFiles(agentID string) error {
 	// Remove MCP config
-	mcpConfigPath := filepath.Join(s.configsPath, "mcp", fmt.Sprintf("%s-mcp.json", agentID))
-	if err := os.Remove(mcpConfigPath); err != nil && !os.IsNotExist(err) {
+	if err := os.Remove(s.mcpConfigPath(agentID)); err != nil && !os.IsNotExist(err) {
 		return fmt.Errorf("failed to remove MCP config: %w", err)
 	}
 
@@ -703,10 +822,16 @@ func (s *ProcessSpawner) CleanupAgentFiles(agentID string) error {
 	return nil
 }
 
-// CleanupAllAgentFiles removes all generated config and PID files
+// CleanupAllAgentFiles removes all generated config and PID files and
+// persists agent ID counters
 func (s *ProcessSpawner) CleanupAllAgentFiles() error {
 	var lastErr error
 
+	if err := s.SaveCounters(); err != nil {
+		s.logger.Warn("failed to save agent counters", "error", err)
+		lastErr = err
+	}
+
 	// Clean MCP configs
 	mcpDir := filepath.Join(s.configsPath, "mcp")
 	entries, err := os.ReadDir(mcpDir)
@@ -715,13 +840,13 @@ func (s *ProcessSpawner) CleanupAllAgentFiles() error {
 			if !entry.IsDir() && strings.HasSuffix(entry.Name(), "-mcp.json") {
 				filePath := filepath.Join(mcpDir, entry.Name())
 				if removeErr := os.Remove(filePath); removeErr != nil && !os.IsNotExist(removeErr) {
-					log.Printf("[SPAWNER] Warning: Failed to remove MCP config %s: %v", filePath, removeErr)
+					s.logger.Warn("failed to remove MCP config", "path", filePath, "error", removeErr)
 					lastErr = removeErr
 				}
 			}
 		}
 	} else if !os.IsNotExist(err) {
-		log.Printf("[SPAWNER] Warning: Failed to read MCP config directory: %v", err)
+		s.logger.Warn("failed to read MCP config directory", "error", err)
 		lastErr = err
 	}
 
@@ -733,13 +858,13 @@ func (s *ProcessSpawner) CleanupAllAgentFiles() error {
 			if !entry.IsDir() && strings.HasSuffix(entry.Name(), ".pid") {
 				filePath := filepath.Join(pidsDir, entry.Name())
 				if removeErr := os.Remove(filePath); removeErr != nil && !os.IsNotExist(removeErr) {
-					log.Printf("[SPAWNER] Warning: Failed to remove PID file %s: %v", filePath, removeErr)
+					s.logger.Warn("failed to remove PID file", "path", filePath, "error", removeErr)
 					lastErr = removeErr
 				}
 			}
 		}
 	} else if !os.IsNotExist(err) {
-		log.Printf("[SPAWNER] Warning: Failed to read PID directory: %v", err)
+		s.logger.Warn("failed to read PID directory", "error", err)
 		lastErr = err
 	}
 
@@ -7,6 +7,7 @@ import (
 	"testing"
 
 	"github.com/CLIAIMONITOR/internal/memory"
+	"github.com/CLIAIMONITOR/internal/types"
 )
 
 // newTestDB creates a real in-memory SQLite database for testing
@@ -539,3 +540,63 @@ func containsHelper(s, substr string) bool {
 	}
 	return false
 }
+
+// TestSpawnAgentDryRun tests that dry-run spawns skip WezTerm and record fake PIDs
+func TestSpawnAgentDryRun(t *testing.T) {
+	spawner := NewSpawner(t.TempDir(), "http://localhost:3000/mcp/sse", nil)
+	spawner.DryRun = true
+	config := types.AgentConfig{Name: "SNTGreen", Model: "claude-sonnet-4-5"}
+
+	id := spawner.GenerateAgentID("SNTGreen")
+	pid, err := spawner.SpawnAgentWithOptions(config, id, t.TempDir(), "do the thing", true)
+	if err != nil {
+		t.Fatalf("SpawnAgentWithOptions failed: %v", err)
+	}
+	if pid != DryRunPIDBase+1 {
+		t.Errorf("Expected PID %d, got %d", DryRunPIDBase+1, pid)
+	}
+
+	pid, err = spawner.SpawnAgent(config, "team-sntgreen007", t.TempDir(), "")
+	if err != nil {
+		t.Fatalf("SpawnAgent failed: %v", err)
+	}
+	if pid != DryRunPIDBase+7 {
+		t.Errorf("Expected PID %d, got %d", DryRunPIDBase+7, pid)
+	}
+
+	running := spawner.GetRunningAgents()
+	if len(running) != 2 || running[id] != DryRunPIDBase+1 || running["team-sntgreen007"] != DryRunPIDBase+7 {
+		t.Errorf("Unexpected running agents: %v", running)
+	}
+}
+
+// TestPreviewSpawn tests that previews return the command without recording the agent
+func TestPreviewSpawn(t *testing.T) {
+	basePath := t.TempDir()
+	spawner := NewSpawner(basePath, "http://localhost:3000/mcp/sse", nil)
+	config := types.AgentConfig{Name: "SNTGreen", Model: "claude-sonnet-4-5"}
+
+	id := spawner.PreviewAgentID("SNTGreen")
+	if id != "team-sntgreen001" {
+		t.Errorf("Expected team-sntgreen001, got %s", id)
+	}
+	if next := spawner.GenerateAgentID("SNTGreen"); next != id {
+		t.Errorf("Preview consumed a sequence number: got %s after preview %s", next, id)
+	}
+
+	pid, command := spawner.PreviewSpawn(config, id, t.TempDir(), `say "hi"`, false)
+	if pid != DryRunPIDBase+1 {
+		t.Errorf("Expected PID %d, got %d", DryRunPIDBase+1, pid)
+	}
+	mcpConfig := filepath.Join(basePath, "configs", "mcp", "team-sntgreen001-mcp.json")
+	want := `title team-sntgreen001 && claude --mcp-config "` + mcpConfig + `" --model claude-sonnet-4-5 --dangerously-skip-permissions "say \"hi\""`
+	if command != want {
+		t.Errorf("Expected command %q, got %q", want, command)
+	}
+	if _, err := os.Stat(mcpConfig); !os.IsNotExist(err) {
+		t.Error("PreviewSpawn should not write the MCP config")
+	}
+	if len(spawner.GetRunningAgents()) != 0 {
+		t.Errorf("PreviewSpawn should not record agents: %v", spawner.GetRunningAgents())
+	}
+}
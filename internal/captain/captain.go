@@ -12,11 +12,14 @@ import (
 	"time"
 
 	"github.com/CLIAIMONITOR/internal/agents"
+	"github.com/CLIAIMONITOR/internal/events"
 	"github.com/CLIAIMONITOR/internal/git"
+	"github.com/CLIAIMONITOR/internal/logger"
 	"github.com/CLIAIMONITOR/internal/memory"
 	"github.com/CLIAIMONITOR/internal/supervisor"
 	"github.com/CLIAIMONITOR/internal/tasks"
 	"github.com/CLIAIMONITOR/internal/types"
+	"github.com/google/uuid"
 )
 
 // AgentMode determines how an agent is spawned
@@ -40,27 +43,87 @@ const (
 	TaskPlanning       TaskType = "planning"       // Task management, API calls
 )
 
+// ValidTaskType reports whether t is a known mission task type
+func ValidTaskType(t TaskType) bool {
+	switch t {
+	case TaskRecon, TaskAnalysis, TaskImplementation, TaskTesting, TaskPlanning:
+		return true
+	}
+	return false
+}
+
+// Mission source types record how a mission reached Captain
+const (
+	SourceAPI      = "api"       // Pushed through the HTTP API or dashboard
+	SourceGitHub   = "github"    // Imported from a GitHub issue
+	SourceJSONFile = "json_file" // Loaded from pending_tasks.json
+	SourceInternal = "internal"  // Created by Captain itself (recon, action plans)
+)
+
+// ValidSourceType reports whether s is a known mission source type
+func ValidSourceType(s string) bool {
+	switch s {
+	case SourceAPI, SourceGitHub, SourceJSONFile, SourceInternal:
+		return true
+	}
+	return false
+}
+
+// AgentSpawner is the agent lifecycle Captain drives. It is satisfied by
+// *agents.ProcessSpawner and, in tests, *agents.MockSpawner.
+type AgentSpawner interface {
+	agents.Spawner
+	GenerateAgentID(agentType string) string
+}
+
 // Captain is the orchestrator that decides how to spawn agents
 type Captain struct {
 	mu           sync.RWMutex
 	basePath     string
-	spawner      *agents.ProcessSpawner
+	spawner      AgentSpawner
 	memDB        memory.MemoryDB
 	configs      map[string]types.AgentConfig
 	plannerAPIKey string
 	plannerURL   string
+	plannerTokens *plannerTokenCache
+	plannerProxyURL string
 
 	// Active subagent tracking
 	activeSubagents map[string]*SubagentResult
 
 	// Orchestration state
+	cycleMu        sync.Mutex // serializes runCycle between the ticker and TriggerCycle
+	runCtx         context.Context
 	running        bool
 	lastCycle      time.Time
 	cycleInterval  time.Duration
+	cyclePolicy    CyclePolicy
 	escalations    []Escalation
-	taskQueue      []*CaptainTask
+	escLoaded      bool // escalations merged with those stored in memDB
+	taskQueues     map[string][]*CaptainTask // pending and in-flight tasks by team ID
+	pausedTeams    map[string]bool
+	teamRunning    map[string]int // queued executions in flight by team ID
 	decisionEngine supervisor.DecisionEngine
 	reportParser   supervisor.ReportParser
+
+	// External notifications
+	taskWebhook *TaskWebhookDispatcher
+	eventBus    *events.Bus
+	activity    ActivityRecorder
+
+	// Consecutive scheduled scan failures by environment ID
+	scanFailures map[string]scanFailure
+
+	// Last Claude CLI pre-flight check (nil until it completes)
+	claudeStatus *ClaudeStatus
+
+	// ClaudeVersionRunner runs `claude --version` for the pre-flight check
+	// (default RunClaudeVersion)
+	ClaudeVersionRunner func(ctx context.Context) ([]byte, error)
+
+	// MaxOutputBytes caps captured subagent output; longer output is
+	// truncated and marked (default DefaultMaxOutputBytes)
+	MaxOutputBytes int
 }
 
 // SubagentResult contains the output from a subagent execution
@@ -74,6 +137,7 @@ type SubagentResult struct {
 	ExitCode    int           `json:"exit_code"`
 	Error       string        `json:"error,omitempty"`
 	Status      string        `json:"status"` // running, completed, failed
+	Truncated   bool          `json:"truncated,omitempty"` // Output was cut at MaxOutputBytes
 }
 
 // Mission describes a task to be executed
@@ -86,6 +150,10 @@ type Mission struct {
 	Priority     int               `json:"priority"`
 	RequiresHuman bool             `json:"requires_human"`
 	Metadata     map[string]string `json:"metadata,omitempty"`
+	SourceType   string            `json:"source_type,omitempty"` // api, github, json_file, internal
+	SourceRef    string            `json:"source_ref,omitempty"`  // e.g. GitHub issue URL or Planner task URL
+	RetryPolicy  RetryPolicy       `json:"retry_policy"`          // Subagent retries; runs once by default
+	TeamID       string            `json:"team_id,omitempty"`     // Queue the mission runs in; defaults to DefaultTeamID
 }
 
 // ModeDecision explains why a particular mode was chosen
@@ -94,11 +162,14 @@ type ModeDecision struct {
 	Reason      string    `json:"reason"`
 	AgentType   string    `json:"agent_type"`
 	Parallelizable bool   `json:"parallelizable"`
+	Profile     *ScanProfile `json:"profile,omitempty"` // Recon scan profile, if any
+	Policy      *memory.SecurityPolicy `json:"policy,omitempty"` // Recon environment security policy, if any
 }
 
 // CaptainTask holds a task with optional recon report
 type CaptainTask struct {
 	Mission      Mission                `json:"mission"`
+	TeamID       string                 `json:"team_id"`
 	NeedsRecon   bool                   `json:"needs_recon"`
 	ReconReport  *supervisor.ReconReport `json:"recon_report,omitempty"`
 	ActionPlan   *supervisor.ActionPlan  `json:"action_plan,omitempty"`
@@ -118,12 +189,13 @@ type Escalation struct {
 	CreatedAt    time.Time `json:"created_at"`
 	Resolved     bool      `json:"resolved"`
 	Resolution   string    `json:"resolution,omitempty"`
+	ResolvedBy   string    `json:"resolved_by,omitempty"`
 	ResolvedAt   *time.Time `json:"resolved_at,omitempty"`
 }
 
 // NewCaptain creates a new Captain orchestrator
-func NewCaptain(basePath string, spawner *agents.ProcessSpawner, memDB memory.MemoryDB, configs map[string]types.AgentConfig) *Captain {
-	return &Captain{
+func NewCaptain(basePath string, spawner AgentSpawner, memDB memory.MemoryDB, configs map[string]types.AgentConfig) *Captain {
+	c := &Captain{
 		basePath:        basePath,
 		spawner:         spawner,
 		memDB:           memDB,
@@ -132,11 +204,47 @@ func NewCaptain(basePath string, spawner *agents.ProcessSpawner, memDB memory.Me
 		activeSubagents: make(map[string]*SubagentResult),
 		running:         false,
 		cycleInterval:   30 * time.Second,
+		cyclePolicy:     NewLinearPolicy(),
 		escalations:     make([]Escalation, 0),
-		taskQueue:       make([]*CaptainTask, 0),
+		taskQueues:      make(map[string][]*CaptainTask),
+		pausedTeams:     make(map[string]bool),
+		teamRunning:     make(map[string]int),
 		decisionEngine:  supervisor.NewDecisionEngine(memDB),
 		reportParser:    supervisor.NewReportParser(),
+		MaxOutputBytes:  DefaultMaxOutputBytes,
+		taskWebhook:     NewTaskWebhookDispatcherFromEnv(),
 	}
+	return c
+}
+
+// ActivityRecorder receives Captain's task activity entries. It is
+// satisfied by persistence.Store.
+type ActivityRecorder interface {
+	AddActivity(activity *types.ActivityLog)
+}
+
+// SetActivityRecorder sets where queued task outcomes are logged
+func (c *Captain) SetActivityRecorder(r ActivityRecorder) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.activity = r
+}
+
+// recordActivity adds an entry to the activity log, if one is set
+func (c *Captain) recordActivity(agentID, action, details string) {
+	c.mu.RLock()
+	r := c.activity
+	c.mu.RUnlock()
+	if r == nil {
+		return
+	}
+	r.AddActivity(&types.ActivityLog{
+		ID:        fmt.Sprintf("activity-%d", time.Now().UnixNano()),
+		AgentID:   agentID,
+		Action:    action,
+		Details:   details,
+		Timestamp: time.Now(),
+	})
 }
 
 // SetPlannerAPIKey sets the API key for Planner integration
@@ -146,6 +254,53 @@ func (c *Captain) SetPlannerAPIKey(key string) {
 	c.plannerAPIKey = key
 }
 
+// SetTaskWebhookDispatcher overrides the task status webhook dispatcher
+func (c *Captain) SetTaskWebhookDispatcher(d *TaskWebhookDispatcher) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.taskWebhook = d
+}
+
+// setTaskStatus transitions a task to a new status and notifies the webhook
+func (c *Captain) setTaskStatus(task *CaptainTask, status string) {
+	c.mu.Lock()
+	oldStatus := task.Status
+	task.Status = status
+	task.UpdatedAt = time.Now()
+	webhook := c.taskWebhook
+	c.mu.Unlock()
+
+	webhook.DispatchTransition(task, oldStatus, status)
+}
+
+// taskStatus reads a queued task's status. Queued tasks are shared between
+// the cycle, running missions and API readers, so their fields are only
+// read and written under c.mu.
+func (c *Captain) taskStatus(task *CaptainTask) string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return task.Status
+}
+
+// updateTask changes a queued task's fields under c.mu
+func (c *Captain) updateTask(task *CaptainTask, update func(*CaptainTask)) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	update(task)
+	task.UpdatedAt = time.Now()
+}
+
+// copyTasks returns copies of tasks for callers outside the orchestration
+// cycle. Callers must hold c.mu.
+func copyTasks(tasks []*CaptainTask) []*CaptainTask {
+	copies := make([]*CaptainTask, len(tasks))
+	for i, task := range tasks {
+		taskCopy := *task
+		copies[i] = &taskCopy
+	}
+	return copies
+}
+
 // DecideMode determines the best execution mode for a mission
 // All tasks run as subagents (headless) in the Captain's process
 func (c *Captain) DecideMode(mission Mission) ModeDecision {
@@ -196,61 +351,102 @@ func selectImplementationAgent(priority int) string {
 
 // ExecuteMission runs a mission using the appropriate mode
 func (c *Captain) ExecuteMission(ctx context.Context, mission Mission) (*SubagentResult, error) {
+	if err := ValidateMission(mission); err != nil {
+		return nil, err
+	}
+
+	c.recordMission(mission)
+
 	decision := c.DecideMode(mission)
 
+	var result *SubagentResult
+	var err error
 	switch decision.Mode {
 	case ModeSubagent:
-		return c.executeSubagent(ctx, mission, decision)
+		result, err = c.executeSubagent(ctx, mission, decision)
 	case ModeTerminal:
-		return c.executeTerminal(ctx, mission, decision)
+		result, err = c.executeTerminal(ctx, mission, decision)
 	default:
-		return nil, fmt.Errorf("unknown mode: %s", decision.Mode)
+		err = fmt.Errorf("unknown mode: %s", decision.Mode)
+	}
+
+	c.recordMissionOutcome(mission, result, err)
+	return result, err
+}
+
+// recordMission stores the mission's provenance in the captain_tasks table
+func (c *Captain) recordMission(mission Mission) {
+	if c.memDB == nil {
+		return
+	}
+	sourceType := mission.SourceType
+	if sourceType == "" {
+		sourceType = SourceInternal
+	}
+	err := c.memDB.RecordCaptainTask(&memory.CaptainTaskRecord{
+		ID:         mission.ID,
+		Title:      mission.Title,
+		TaskType:   string(mission.TaskType),
+		SourceType: sourceType,
+		SourceRef:  mission.SourceRef,
+		Status:     "executing",
+	})
+	if err != nil {
+		logger.For("captain").Warn("failed to record mission", "mission_id", mission.ID, "error", err)
+	}
+}
+
+// recordMissionOutcome updates the captain_tasks row once execution returns.
+// Terminal missions report "spawned" since the agent keeps running.
+func (c *Captain) recordMissionOutcome(mission Mission, result *SubagentResult, err error) {
+	if c.memDB == nil {
+		return
+	}
+	status := "completed"
+	if err != nil {
+		status = "failed"
+	} else if result != nil && result.Status != "" {
+		status = result.Status
+	}
+	if updateErr := c.memDB.UpdateCaptainTaskStatus(mission.ID, status); updateErr != nil {
+		logger.For("captain").Warn("failed to update mission", "mission_id", mission.ID, "error", updateErr)
+	}
+	if err == nil && result != nil && result.AgentID != "" {
+		if assignErr := c.memDB.AssignCaptainTask(mission.ID, result.AgentID); assignErr != nil {
+			logger.For("captain").Warn("failed to record agent for mission", "mission_id", mission.ID, "agent_id", result.AgentID, "error", assignErr)
+		}
 	}
 }
 
 // ExecuteMissionsParallel runs multiple missions in parallel (subagent mode only)
-// Uses a worker pool to limit concurrent goroutines and prevent OOM
+// and waits for all of them. At most maxConcurrentWorkers run at once to
+// prevent OOM; the rest wait for a slot.
 func (c *Captain) ExecuteMissionsParallel(ctx context.Context, missions []Mission) []*SubagentResult {
-	// Worker pool configuration: limit concurrent missions to prevent unbounded goroutine spawn
 	const maxConcurrentWorkers = 10
-
-	results := make([]*SubagentResult, len(missions))
-
-	// Create semaphore channel to limit concurrent workers
 	sem := make(chan struct{}, maxConcurrentWorkers)
 
-	var wg sync.WaitGroup
-
+	channels := make([]<-chan *SubagentResult, len(missions))
 	for i, mission := range missions {
-		wg.Add(1)
-
-		// Acquire semaphore (blocks if at capacity)
-		sem <- struct{}{}
-
-		go func(idx int, m Mission) {
-			defer wg.Done()
-			defer func() { <-sem }() // Release semaphore when done
-
-			result, err := c.ExecuteMission(ctx, m)
-			if err != nil {
-				results[idx] = &SubagentResult{
-					AgentID:  fmt.Sprintf("mission-%d", idx),
-					Status:   "failed",
-					Error:    err.Error(),
-					TaskType: m.TaskType,
-				}
-				return
-			}
-			results[idx] = result
-		}(i, mission)
+		channels[i] = c.dispatch(ctx, mission, fmt.Sprintf("mission-%d", i), sem)
 	}
-
-	wg.Wait()
-	return results
+	return CollectResults(channels, 0)
 }
 
-// executeSubagent spawns a quick Claude agent and captures output
+// executeSubagent runs a mission as a subagent under its RetryPolicy,
+// recording each attempt in mission_attempts
 func (c *Captain) executeSubagent(ctx context.Context, mission Mission, decision ModeDecision) (*SubagentResult, error) {
+	attempt := 0
+	return executeWithRetry(ctx, func() (*SubagentResult, error) {
+		attempt++
+		startedAt := time.Now()
+		result, err := c.runSubagent(ctx, mission, decision)
+		c.recordMissionAttempt(mission, attempt, startedAt, result, err)
+		return result, err
+	}, mission.RetryPolicy)
+}
+
+// runSubagent spawns a quick Claude agent and captures output
+func (c *Captain) runSubagent(ctx context.Context, mission Mission, decision ModeDecision) (*SubagentResult, error) {
 	// Generate team-compatible agent ID (e.g., team-opusgreen001)
 	var agentID string
 	if c.spawner != nil {
@@ -294,6 +490,11 @@ func (c *Captain) executeSubagent(ctx context.Context, mission Mission, decision
 		"--dangerously-skip-permissions", // Skip permission prompts for automation
 	}
 
+	// Restrict tools for the environment being scanned
+	if decision.Profile != nil {
+		args = append(args, decision.Profile.cliArgs()...)
+	}
+
 	// Add model selection based on agent type
 	model := c.getModelForAgent(decision.AgentType)
 	if model != "" {
@@ -306,11 +507,18 @@ func (c *Captain) executeSubagent(ctx context.Context, mission Mission, decision
 	cmd := exec.CommandContext(ctx, "claude", args...)
 	cmd.Dir = mission.ProjectPath
 
-	// Capture output
-	output, err := cmd.CombinedOutput()
+	// Capture output, streaming it to /api/captain/tasks/{id}/logs as it arrives
+	output, err := c.runStreamingOutput(cmd, mission.ID, agentID)
 
 	result.EndTime = time.Now()
 	result.Duration = result.EndTime.Sub(result.StartTime)
+
+	// Chatty agents can produce far more output than is useful to keep
+	if truncated, ok := truncateOutput(output, c.MaxOutputBytes); ok {
+		logger.For("captain").Info("subagent output truncated", "agent_id", agentID, "bytes", len(output), "max_bytes", c.MaxOutputBytes)
+		output = truncated
+		result.Truncated = true
+	}
 	result.Output = string(output)
 
 	if err != nil {
@@ -388,6 +596,13 @@ func (c *Captain) buildSubagentPrompt(mission Mission, decision ModeDecision) st
 	case TaskRecon:
 		sb.WriteString("## Instructions\n")
 		sb.WriteString("You are a reconnaissance agent. Your mission is to scan, analyze, and report.\n")
+		if profile := decision.Profile; profile != nil {
+			for _, instruction := range profile.Instructions {
+				sb.WriteString(fmt.Sprintf("- %s\n", instruction))
+			}
+			sb.WriteString(fmt.Sprintf("- Provide findings in structured %s format\n", strings.ToUpper(profile.OutputFormat)))
+			break
+		}
 		sb.WriteString("- Observe only - do not modify files\n")
 		sb.WriteString("- Provide findings in structured YAML format\n")
 		sb.WriteString("- Prioritize by severity (critical > high > medium > low)\n")
@@ -403,9 +618,11 @@ func (c *Captain) buildSubagentPrompt(mission Mission, decision ModeDecision) st
 	case TaskPlanning:
 		sb.WriteString("## Instructions\n")
 		sb.WriteString("You are a planning agent. Interact with the Planner API.\n")
-		sb.WriteString(fmt.Sprintf("- API Base: %s\n", c.plannerURL))
-		if c.plannerAPIKey != "" {
-			sb.WriteString("- Use X-API-Key header for authenticated requests\n")
+		if c.plannerProxyURL != "" {
+			sb.WriteString(fmt.Sprintf("- API Base: %s\n", c.plannerProxyURL))
+			sb.WriteString("- The dashboard adds Planner credentials; do not send your own\n")
+		} else {
+			sb.WriteString(fmt.Sprintf("- API Base: %s\n", c.plannerURL))
 		}
 		sb.WriteString("- Return structured JSON results\n")
 
@@ -421,6 +638,14 @@ func (c *Captain) buildSubagentPrompt(mission Mission, decision ModeDecision) st
 		sb.WriteString("Complete the mission and report results.\n")
 	}
 
+	// Environment security policy constraints
+	if constraints := policyInstructions(decision.Policy); len(constraints) > 0 {
+		sb.WriteString("\n## Security Policy\n")
+		for _, constraint := range constraints {
+			sb.WriteString(fmt.Sprintf("- %s\n", constraint))
+		}
+	}
+
 	// Add metadata if present
 	if len(mission.Metadata) > 0 {
 		sb.WriteString("\n## Additional Context\n")
@@ -434,17 +659,7 @@ func (c *Captain) buildSubagentPrompt(mission Mission, decision ModeDecision) st
 
 // getModelForAgent returns the appropriate Claude model for an agent type
 func (c *Captain) getModelForAgent(agentType string) string {
-	// Check if we have a config for this agent
-	if config, exists := c.configs[agentType]; exists {
-		return config.Model
-	}
-
-	// Default model selection based on agent type prefix
-	agentLower := strings.ToLower(agentType)
-	if strings.HasPrefix(agentLower, "opus") || strings.HasPrefix(agentLower, "snake") {
-		return "claude-opus-4-5-20251101"
-	}
-	return "claude-sonnet-4-5-20250929"
+	return c.resolveModel(agentType).Model
 }
 
 // GetActiveSubagents returns currently running subagents
@@ -510,6 +725,8 @@ func (c *Captain) ImportPendingTasks() ([]Mission, error) {
 				"agent_recommendation": task.TaskRequirements.AgentRecommendation,
 				"repo":                task.Repo,
 			},
+			SourceType: SourceJSONFile,
+			SourceRef:  tasksFile,
 		})
 	}
 
@@ -568,14 +785,19 @@ func (c *Captain) resolveProjectPath(repo string) string {
 func (c *Captain) Run(ctx context.Context) {
 	c.mu.Lock()
 	c.running = true
+	c.runCtx = ctx
 	c.mu.Unlock()
 
-	ticker := time.NewTicker(c.cycleInterval)
-	defer ticker.Stop()
+	// Subagents fail without the Claude CLI, so warn at startup rather
+	// than on the first spawn. Runs in the background to keep startup fast.
+	go c.RefreshClaudeStatus(ctx)
 
 	// Run initial cycle immediately
 	c.runCycle(ctx)
 
+	ticker := time.NewTicker(c.nextCycleInterval())
+	defer ticker.Stop()
+
 	for {
 		select {
 		case <-ctx.Done():
@@ -585,76 +807,181 @@ func (c *Captain) Run(ctx context.Context) {
 			return
 		case <-ticker.C:
 			c.runCycle(ctx)
+			ticker.Reset(c.nextCycleInterval())
 		}
 	}
 }
 
+// nextCycleInterval asks the cycle policy for the wait before the next cycle
+// and records it as the current cycleInterval
+func (c *Captain) nextCycleInterval() time.Duration {
+	depth := c.queueDepth()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.cyclePolicy != nil {
+		if interval := c.cyclePolicy.NextInterval(depth); interval > 0 {
+			c.cycleInterval = interval
+		}
+	}
+	return c.cycleInterval
+}
+
+// queueDepth counts tasks that still need work from the orchestration cycle
+func (c *Captain) queueDepth() int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	depth := 0
+	for _, queue := range c.taskQueues {
+		depth += countQueued(queue)
+	}
+	return depth
+}
+
 // runCycle executes one orchestration cycle
 func (c *Captain) runCycle(ctx context.Context) {
+	c.cycleMu.Lock()
+	defer c.cycleMu.Unlock()
+
+	cycleStart := time.Now()
 	c.mu.Lock()
-	c.lastCycle = time.Now()
+	c.lastCycle = cycleStart
 	c.mu.Unlock()
 
-	// 1. Check for pending tasks
+	// Each cycle is its own trace unless it was started under one
+	if logger.TraceID(ctx) == "" {
+		ctx = logger.WithTraceID(ctx, uuid.New().String())
+	}
+
+	// Tasks this cycle acted on, keyed by mission ID
+	processed := make(map[string]bool)
+	spawned := 0
+	escalated := 0
+
+	// 1. Queue scheduled environment scans, then check for pending tasks
+	if queued := c.queueScheduledScans(ctx, cycleStart); queued > 0 {
+		logger.For("captain").Info("queued scheduled environment scans", "count", queued)
+	}
 	tasks := c.checkPendingTasks()
 
+	// Each team starts at most its concurrency limit of tasks; paused teams
+	// start none and their tasks are left where they are
+	slots := c.teamSlots(tasks)
+
 	// 2. For tasks needing recon, spawn Snake
 	for _, task := range tasks {
-		if task.NeedsRecon && task.Status == "pending" {
+		if task.NeedsRecon && c.taskStatus(task) == "pending" {
+			team := teamKey(task.TeamID)
+			if slots[team] <= 0 {
+				continue
+			}
+			if c.reconIsFresh(ctx, task.Mission.ProjectPath) {
+				logger.For("captain").Info("skipping recon, no repo changes since last scan", "task_id", task.Mission.ID)
+				c.updateTask(task, func(t *CaptainTask) { t.NeedsRecon = false })
+				processed[task.Mission.ID] = true
+				if isScheduledScan(task.Mission) {
+					// No drift: the scan is done without running Snake
+					c.markEnvironmentScanned(ctx, task.Mission)
+					c.setTaskStatus(task, "completed")
+					continue
+				}
+				// The last recon still holds: go straight to execution
+				slots[team]--
+				c.startQueuedTask(ctx, task)
+				continue
+			}
+			processed[task.Mission.ID] = true
+			slots[team]--
 			report, err := c.runSnakeRecon(ctx, task)
 			if err != nil {
 				// Mark task as failed
-				task.Status = "failed"
-				task.UpdatedAt = time.Now()
+				if isScheduledScan(task.Mission) {
+					c.recordScanFailure(task.Mission, time.Now())
+				}
+				c.setTaskStatus(task, "failed")
 				continue
 			}
-			task.ReconReport = report
-			task.Status = "recon_complete"
-			task.UpdatedAt = time.Now()
+			if isScheduledScan(task.Mission) {
+				c.markEnvironmentScanned(ctx, task.Mission)
+			}
+			c.updateTask(task, func(t *CaptainTask) { t.ReconReport = report })
+			c.setTaskStatus(task, "recon_complete")
 		}
 	}
 
 	// 3. Analyze and spawn agents
 	for _, task := range tasks {
-		if task.Status == "recon_complete" {
+		if c.taskStatus(task) == "recon_complete" && !c.TeamPaused(task.TeamID) {
+			processed[task.Mission.ID] = true
 			plan := c.analyzeAndPlan(task)
 			if plan != nil {
-				task.ActionPlan = plan
-				task.Status = "analyzing"
-				task.UpdatedAt = time.Now()
+				c.updateTask(task, func(t *CaptainTask) { t.ActionPlan = plan })
+				c.setTaskStatus(task, "analyzing")
 
 				// Check for escalation
 				if plan.RequiresHuman {
 					c.createEscalation(task, plan.EscalationReason)
-					task.Status = "escalated"
+					escalated++
+					c.setTaskStatus(task, "escalated")
 					continue
 				}
 
 				// Execute agent spawns - pass project path from mission
-				c.executeAgentSpawns(ctx, plan, task.Mission.ProjectPath)
-				task.Status = "executing"
-				task.UpdatedAt = time.Now()
+				spawned += c.executeAgentSpawns(ctx, plan, task.Mission.ProjectPath)
+				c.setTaskStatus(task, "executing")
 			}
 		}
 	}
 
-	// 4. Health check running agents
+	// 4. Execute pending tasks that need no recon
+	for _, task := range tasks {
+		if c.taskStatus(task) == "pending" && !task.NeedsRecon {
+			team := teamKey(task.TeamID)
+			if slots[team] <= 0 {
+				continue
+			}
+			slots[team]--
+			processed[task.Mission.ID] = true
+			c.startQueuedTask(ctx, task)
+		}
+	}
+
+	// 5. Health check running agents
 	c.checkAgentHealth()
 
-	// 5. Process escalations
+	// 6. Process escalations
 	c.processEscalations()
 
-	// Update task queue
+	// Update task queue, keeping tasks enqueued or removed during the cycle
 	c.mu.Lock()
-	c.taskQueue = tasks
+	c.taskQueues = groupTasksByTeam(mergeTaskQueue(tasks, c.queuedTasksLocked()))
 	c.mu.Unlock()
+
+	c.storeCycleMetric(&memory.OrchestratorMetric{
+		CycleAt:            cycleStart,
+		DurationMs:         time.Since(cycleStart).Milliseconds(),
+		TasksProcessed:     len(processed),
+		SubagentsSpawned:   spawned,
+		EscalationsCreated: escalated,
+		TraceID:            logger.TraceID(ctx),
+	})
+}
+
+// storeCycleMetric persists a cycle's metrics for trend analysis
+func (c *Captain) storeCycleMetric(metric *memory.OrchestratorMetric) {
+	if c.memDB == nil {
+		return
+	}
+	if err := c.memDB.StoreOrchestratorMetric(metric); err != nil {
+		logger.For("captain").Warn("failed to store cycle metrics", "error", err)
+	}
 }
 
 // checkPendingTasks loads tasks from pending_tasks.json or internal queue
 func (c *Captain) checkPendingTasks() []*CaptainTask {
 	c.mu.RLock()
-	existingTasks := make([]*CaptainTask, len(c.taskQueue))
-	copy(existingTasks, c.taskQueue)
+	existingTasks := c.queuedTasksLocked()
 	c.mu.RUnlock()
 
 	// Try to load new tasks from pending_tasks.json
@@ -674,6 +1001,7 @@ func (c *Captain) checkPendingTasks() []*CaptainTask {
 			if !found {
 				task := &CaptainTask{
 					Mission:    mission,
+					TeamID:     teamKey(mission.TeamID),
 					NeedsRecon: shouldRunRecon(mission),
 					Status:     "pending",
 					CreatedAt:  time.Now(),
@@ -716,31 +1044,47 @@ func shouldRunRecon(mission Mission) bool {
 }
 
 // runSnakeRecon spawns a Snake agent to perform reconnaissance
-func (c *Captain) runSnakeRecon(ctx context.Context, task *CaptainTask) (*supervisor.ReconReport, error) {
-	task.Status = "recon_running"
-	task.UpdatedAt = time.Now()
+func (c *Captain) runSnakeRecon(ctx context.Context, task *CaptainTask) (report *supervisor.ReconReport, err error) {
+	c.setTaskStatus(task, "recon_running")
+
+	// Scan customer, test and internal environments differently
+	envType := c.reconEnvType(ctx, task.Mission)
+	profile := ScanProfileFor(envType)
+	policy := c.reconSecurityPolicy(ctx, task.Mission, envType)
 
 	// Create a reconnaissance mission
 	reconMission := Mission{
 		ID:           fmt.Sprintf("recon-%s", task.Mission.ID),
 		Title:        fmt.Sprintf("Reconnaissance: %s", task.Mission.Title),
-		Description:  fmt.Sprintf("Scan and analyze the codebase to understand:\n%s\n\nProvide findings in YAML format.", task.Mission.Description),
+		Description:  fmt.Sprintf("Scan and analyze the codebase to understand:\n%s\n\nProvide findings in %s format.", task.Mission.Description, strings.ToUpper(profile.OutputFormat)),
 		TaskType:     TaskRecon,
 		ProjectPath:  task.Mission.ProjectPath,
 		Priority:     task.Mission.Priority,
 		RequiresHuman: false,
 		Metadata: map[string]string{
 			"parent_task": task.Mission.ID,
-			"format":      "yaml",
+			"format":      profile.OutputFormat,
+			metaEnvType:   envType,
 		},
+		SourceType:  SourceInternal,
+		SourceRef:   task.Mission.ID,
+		RetryPolicy: task.Mission.RetryPolicy,
 	}
 
-	// Execute Snake subagent
+	// Track the scan so its progress can be followed while Snake runs
+	scanID := c.startReconScan(ctx, reconMission)
+	defer func() { c.finishReconScan(ctx, scanID, report, err) }()
+	stopProgress := c.trackScanProgress(ctx, scanID)
+
+	// Execute Snake subagent, retrying under the parent mission's policy
 	result, err := c.executeSubagent(ctx, reconMission, ModeDecision{
 		Mode:      ModeSubagent,
 		AgentType: "Snake",
-		Reason:    "Reconnaissance mission",
+		Reason:    fmt.Sprintf("Reconnaissance mission (%s profile)", profile.EnvType),
+		Profile:   &profile,
+		Policy:    &policy,
 	})
+	stopProgress()
 
 	if err != nil {
 		return nil, fmt.Errorf("snake recon failed: %w", err)
@@ -750,45 +1094,100 @@ func (c *Captain) runSnakeRecon(ctx context.Context, task *CaptainTask) (*superv
 		return nil, fmt.Errorf("snake recon did not complete: %s", result.Status)
 	}
 
-	// Parse the output as YAML
-	report, err := c.reportParser.ParseYAML([]byte(result.Output))
-	if err != nil {
-		// Try JSON format as fallback
-		report, err = c.reportParser.ParseJSON([]byte(result.Output))
+	// Truncated output has lost its tail, so try just the report portion first
+	if result.Truncated {
+		report = parseTruncatedReport(c.reportParser, result.Output)
+	}
+	if report == nil {
+		// Parse the output as YAML
+		report, err = c.reportParser.ParseYAML([]byte(result.Output))
 		if err != nil {
-			return nil, fmt.Errorf("failed to parse recon report: %w", err)
+			// Try JSON format as fallback
+			report, err = c.reportParser.ParseJSON([]byte(result.Output))
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse recon report: %w", err)
+			}
 		}
 	}
+	if scanID != "" {
+		// Complete the tracked scan rather than recording a second one
+		report.ID = scanID
+	}
 
-	// Store report in memory DB
-	if err := c.storeReconReport(ctx, report, task.Mission.ProjectPath); err != nil {
+	// Store report in memory DB, merging findings already stored by earlier
+	// scans under the configured strategy
+	if err := c.storeReconReport(ctx, report, task.Mission.ProjectPath, envType, c.reconDuplicateStrategy()); err != nil {
 		// Log but don't fail - we still have the report
 		fmt.Printf("Warning: failed to store recon report: %v\n", err)
 	}
 
+	// Record the scan so unchanged repos can skip recon next cycle
+	if c.memDB != nil && task.Mission.ProjectPath != "" {
+		if repo, err := c.memDB.DiscoverRepo(task.Mission.ProjectPath); err == nil {
+			c.memDB.UpdateRepoScan(repo.ID)
+		}
+	}
+
 	return report, nil
 }
 
-// storeReconReport saves a reconnaissance report to the memory database
-func (c *Captain) storeReconReport(ctx context.Context, report *supervisor.ReconReport, projectPath string) error {
-	// Check if memDB implements ReconRepository interface
-	reconRepo, ok := c.memDB.(memory.ReconRepository)
-	if !ok {
-		// MemoryDB doesn't support reconnaissance storage yet
-		// This is not a fatal error - we still have the report in memory
-		return fmt.Errorf("memory database does not implement ReconRepository interface")
+// reconIsFresh reports whether a known repo has no new commits and was
+// scanned within the last 24 hours, so another recon pass can be skipped.
+// Unknown repos and git errors always return false.
+func (c *Captain) reconIsFresh(ctx context.Context, projectPath string) bool {
+	if c.memDB == nil || projectPath == "" {
+		return false
+	}
+	repo, err := c.memDB.GetRepoByPath(projectPath)
+	if err != nil || repo == nil {
+		return false
 	}
+	summary, err := c.memDB.DetectRepoChanges(ctx, repo.ID)
+	if err != nil {
+		return false
+	}
+	return !summary.NeedsRescan && time.Since(summary.LastScanned) < 24*time.Hour
+}
 
-	// Create or get environment
-	env := &memory.Environment{
+// reconEnvironment describes the environment recon scans of projectPath are
+// recorded against. An empty envType is recorded as internal.
+func reconEnvironment(projectPath, envType string) *memory.Environment {
+	if envType == "" {
+		envType = EnvTypeInternal
+	}
+	return &memory.Environment{
 		ID:          sanitizeEnvID(projectPath),
 		Name:        filepath.Base(projectPath),
 		Description: fmt.Sprintf("Project at %s", projectPath),
-		EnvType:     "internal",
+		EnvType:     envType,
 		BasePath:    projectPath,
 		Metadata:    make(map[string]interface{}),
 	}
+}
+
+// reconDuplicateStrategy returns the strategy for findings a rescan reports
+// again, from the recon_duplicate_strategy context key
+func (c *Captain) reconDuplicateStrategy() memory.DuplicateStrategy {
+	strategy, err := memory.ReconDuplicateStrategy(c.memDB)
+	if err != nil {
+		logger.For("captain").Warn("could not read recon duplicate strategy, using default", "strategy", strategy, "error", err)
+	}
+	return strategy
+}
+
+// storeReconReport saves a reconnaissance report to the memory database.
+// Findings already stored are handled according to strategy.
+func (c *Captain) storeReconReport(ctx context.Context, report *supervisor.ReconReport, projectPath, envType string, strategy memory.DuplicateStrategy) error {
+	// Check if memDB implements ReconRepository interface
+	reconRepo, ok := c.memDB.(memory.ReconRepository)
+	if !ok {
+		// MemoryDB doesn't support reconnaissance storage yet
+		// This is not a fatal error - we still have the report in memory
+		return fmt.Errorf("memory database does not implement ReconRepository interface")
+	}
 
+	// Create or get environment
+	env := reconEnvironment(projectPath, envType)
 	if err := reconRepo.RegisterEnvironment(ctx, env); err != nil {
 		return fmt.Errorf("failed to register environment: %w", err)
 	}
@@ -832,7 +1231,7 @@ func (c *Captain) storeReconReport(ctx context.Context, report *supervisor.Recon
 	}
 
 	if len(allFindings) > 0 {
-		if err := reconRepo.SaveFindings(ctx, allFindings); err != nil {
+		if err := reconRepo.SaveFindings(ctx, allFindings, strategy); err != nil {
 			return fmt.Errorf("failed to save findings: %w", err)
 		}
 	}
@@ -889,8 +1288,10 @@ func (c *Captain) analyzeAndPlan(task *CaptainTask) *supervisor.ActionPlan {
 	return plan
 }
 
-// executeAgentSpawns spawns terminal agents based on action plan
-func (c *Captain) executeAgentSpawns(ctx context.Context, plan *supervisor.ActionPlan, projectPath string) {
+// executeAgentSpawns spawns terminal agents based on action plan and returns
+// how many were spawned
+func (c *Captain) executeAgentSpawns(ctx context.Context, plan *supervisor.ActionPlan, projectPath string) int {
+	spawned := 0
 	// Spawn agents for each recommendation
 	for _, rec := range plan.AgentRecommendations {
 		mission := Mission{
@@ -906,6 +1307,8 @@ func (c *Captain) executeAgentSpawns(ctx context.Context, plan *supervisor.Actio
 				"agent_type":  rec.AgentType,
 				"finding_ids": strings.Join(rec.FindingIDs, ","),
 			},
+			SourceType: SourceInternal,
+			SourceRef:  plan.ID,
 		}
 
 		decision := ModeDecision{
@@ -921,8 +1324,10 @@ func (c *Captain) executeAgentSpawns(ctx context.Context, plan *supervisor.Actio
 			continue
 		}
 
+		spawned++
 		fmt.Printf("Spawned agent %s for task: %s\n", rec.AgentType, rec.Task)
 	}
+	return spawned
 }
 
 // checkAgentHealth monitors running agents for staleness or failures
@@ -987,6 +1392,7 @@ func (c *Captain) createEscalation(task *CaptainTask, reason string) {
 	}
 
 	c.escalations = append(c.escalations, escalation)
+	c.saveEscalation(escalation)
 	fmt.Printf("Escalation created: %s - %s\n", escalation.ID, reason)
 }
 
@@ -1007,34 +1413,45 @@ func (c *Captain) createAgentEscalation(agentID, reason, context string) {
 	}
 
 	c.escalations = append(c.escalations, escalation)
+	c.saveEscalation(escalation)
 	fmt.Printf("Agent escalation created: %s - %s\n", escalation.ID, reason)
 }
 
-// GetEscalations returns all escalations
+// GetEscalations returns all escalations. The first call also loads those
+// stored in the memory DB by earlier runs.
 func (c *Captain) GetEscalations() []Escalation {
-	c.mu.RLock()
-	defer c.mu.RUnlock()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.loadEscalations()
 
 	result := make([]Escalation, len(c.escalations))
 	copy(result, c.escalations)
 	return result
 }
 
-// GetTaskQueue returns current task queue
+// GetTaskQueue returns a snapshot of the current task queue
 func (c *Captain) GetTaskQueue() []*CaptainTask {
 	c.mu.RLock()
 	defer c.mu.RUnlock()
 
-	result := make([]*CaptainTask, len(c.taskQueue))
-	copy(result, c.taskQueue)
-	return result
+	return copyTasks(c.queuedTasksLocked())
 }
 
-// SetCycleInterval configures the orchestration cycle interval
+// SetCycleInterval configures a fixed orchestration cycle interval,
+// replacing any adaptive cycle policy
 func (c *Captain) SetCycleInterval(interval time.Duration) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 	c.cycleInterval = interval
+	c.cyclePolicy = FixedPolicy(interval)
+}
+
+// SetCyclePolicy sets the policy used to pick the interval between cycles
+func (c *Captain) SetCyclePolicy(policy CyclePolicy) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.cyclePolicy = policy
 }
 
 // IsRunning returns whether the orchestration loop is active
@@ -1089,6 +1506,8 @@ func (c *Captain) AssignNextTask(tm TaskManager) error {
 			"branch":  branchName,
 			"task_id": task.ID,
 		},
+		SourceType: SourceInternal,
+		SourceRef:  task.ID,
 	}
 
 	_, err := c.ExecuteMission(context.Background(), mission)
@@ -0,0 +1,141 @@
+package captain
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/CLIAIMONITOR/internal/agents"
+	"github.com/CLIAIMONITOR/internal/memory"
+	"github.com/CLIAIMONITOR/internal/types"
+)
+
+func newMockCaptain() (*Captain, *agents.MockSpawner) {
+	spawner := agents.NewMockSpawner()
+	configs := map[string]types.AgentConfig{
+		"SNTGreen": {Name: "SNTGreen", Role: "CodeImplementer"},
+	}
+	return NewCaptain(".", spawner, nil, configs), spawner
+}
+
+func TestExecuteTerminalSpawnsAgent(t *testing.T) {
+	c, spawner := newMockCaptain()
+	spawner.OnSpawnAgent = func(config types.AgentConfig, agentID, projectPath, initialPrompt string) (int, error) {
+		return 4242, nil
+	}
+
+	mission := Mission{ID: "m1", Title: "Fix bug", TaskType: TaskImplementation, ProjectPath: "/repo"}
+	result, err := c.executeTerminal(context.Background(), mission, ModeDecision{Mode: ModeTerminal, AgentType: "SNTGreen"})
+	if err != nil {
+		t.Fatalf("executeTerminal failed: %v", err)
+	}
+
+	spawner.AssertSpawnedWith(t, "SNTGreen", "team-sntgreen001")
+	if result.Status != "spawned" || result.ExitCode != 4242 {
+		t.Errorf("Expected spawned result with PID 4242, got %+v", result)
+	}
+	if call := spawner.SpawnCalls[0]; call.ProjectPath != "/repo" || !strings.Contains(call.InitialPrompt, "Fix bug") {
+		t.Errorf("Unexpected spawn call: %+v", call)
+	}
+	if running := spawner.GetRunningAgents(); running["team-sntgreen001"] != 4242 {
+		t.Errorf("Expected agent tracked as running, got %v", running)
+	}
+}
+
+func TestExecuteTerminalSpawnFailure(t *testing.T) {
+	c, spawner := newMockCaptain()
+	spawnErr := errors.New("wezterm unavailable")
+	spawner.OnSpawnAgent = func(config types.AgentConfig, agentID, projectPath, initialPrompt string) (int, error) {
+		return 0, spawnErr
+	}
+
+	mission := Mission{ID: "m1", Title: "Fix bug", TaskType: TaskImplementation}
+	if _, err := c.executeTerminal(context.Background(), mission, ModeDecision{Mode: ModeTerminal, AgentType: "SNTGreen"}); !errors.Is(err, spawnErr) {
+		t.Errorf("Expected wrapped spawn error, got %v", err)
+	}
+	if len(spawner.GetRunningAgents()) != 0 {
+		t.Error("Expected failed spawn not to be tracked as running")
+	}
+}
+
+func TestExecuteTerminalUnknownAgentType(t *testing.T) {
+	c, spawner := newMockCaptain()
+
+	mission := Mission{ID: "m1", Title: "Plan", TaskType: TaskPlanning}
+	if _, err := c.executeTerminal(context.Background(), mission, ModeDecision{Mode: ModeTerminal, AgentType: "Planner"}); err == nil {
+		t.Error("Expected error for agent type without config")
+	}
+	if len(spawner.SpawnCalls) != 0 {
+		t.Errorf("Expected no spawns, got %d", len(spawner.SpawnCalls))
+	}
+}
+
+func TestExecuteMissionRejectsInvalidMission(t *testing.T) {
+	c, spawner := newMockCaptain()
+
+	// Planning missions require metadata, so this fails validation before
+	// any agent is started
+	mission := Mission{ID: "m1", Title: "Plan", TaskType: TaskPlanning}
+	if _, err := c.ExecuteMission(context.Background(), mission); err == nil {
+		t.Fatal("Expected validation error")
+	}
+	if len(spawner.SpawnCalls) != 0 {
+		t.Errorf("Expected no spawns, got %d", len(spawner.SpawnCalls))
+	}
+}
+
+func TestRunCycleSkipsFreshRecon(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+
+	dir := t.TempDir()
+	past := time.Now().Add(-time.Hour).Format(time.RFC3339)
+	for _, args := range [][]string{
+		{"init", "-q"},
+		{"-c", "user.email=test@example.com", "-c", "user.name=Test", "commit", "-q", "--allow-empty", "-m", "init"},
+	} {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		cmd.Env = append(os.Environ(), "GIT_AUTHOR_DATE="+past, "GIT_COMMITTER_DATE="+past)
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v failed: %v\n%s", args, err, out)
+		}
+	}
+
+	db, err := memory.NewMemoryDB(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("Failed to create test database: %v", err)
+	}
+	defer db.Close()
+	repo, err := db.DiscoverRepo(dir)
+	if err != nil {
+		t.Fatalf("DiscoverRepo failed: %v", err)
+	}
+	if err := db.UpdateRepoScan(repo.ID); err != nil {
+		t.Fatalf("UpdateRepoScan failed: %v", err)
+	}
+
+	// A planning mission without metadata fails validation, so it ends
+	// quickly once it reaches execution
+	c := NewCaptain(t.TempDir(), agents.NewMockSpawner(), db, nil)
+	c.EnqueueMission(Mission{ID: "fresh", Title: "Plan", TaskType: TaskPlanning, ProjectPath: dir}, true)
+	task := liveTask(t, c, "fresh")
+	c.runCycle(context.Background())
+
+	if task.NeedsRecon {
+		t.Error("Expected recon to be skipped")
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for c.TeamStatus(task.TeamID).Running > 0 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if status := c.taskStatus(task); status != "failed" {
+		t.Errorf("Expected task to go straight to execution, got status %s", status)
+	}
+}
@@ -0,0 +1,86 @@
+package captain
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/CLIAIMONITOR/internal/logger"
+)
+
+// ClaudeVersionTimeout bounds the `claude --version` pre-flight check
+const ClaudeVersionTimeout = 5 * time.Second
+
+// RunClaudeVersion runs `claude --version` and returns its output
+func RunClaudeVersion(ctx context.Context) ([]byte, error) {
+	return exec.CommandContext(ctx, "claude", "--version").Output()
+}
+
+// ClaudeStatus is the result of the last Claude CLI pre-flight check
+type ClaudeStatus struct {
+	Available bool      `json:"available"`
+	Version   string    `json:"version"`
+	Error     string    `json:"error,omitempty"`
+	CheckedAt time.Time `json:"checked_at"`
+}
+
+// VerifyClaudeAccess checks that the Claude CLI subagents are run with is
+// on PATH and responds, by running `claude --version`
+func VerifyClaudeAccess(ctx context.Context) error {
+	_, err := claudeVersion(ctx, RunClaudeVersion)
+	return err
+}
+
+// claudeVersion returns the version reported by run, giving up after
+// ClaudeVersionTimeout
+func claudeVersion(ctx context.Context, run func(context.Context) ([]byte, error)) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, ClaudeVersionTimeout)
+	defer cancel()
+
+	output, err := run(ctx)
+	if ctx.Err() == context.DeadlineExceeded {
+		return "", fmt.Errorf("claude --version timed out after %s", ClaudeVersionTimeout)
+	}
+	if err != nil {
+		return "", fmt.Errorf("claude CLI not accessible: %w", err)
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// RefreshClaudeStatus re-runs the Claude CLI pre-flight check and returns
+// the new status
+func (c *Captain) RefreshClaudeStatus(ctx context.Context) ClaudeStatus {
+	run := c.ClaudeVersionRunner
+	if run == nil {
+		run = RunClaudeVersion
+	}
+	version, err := claudeVersion(ctx, run)
+	status := ClaudeStatus{
+		Available: err == nil,
+		Version:   version,
+		CheckedAt: time.Now(),
+	}
+	if err != nil {
+		status.Error = err.Error()
+		logger.For("captain").Warn("Claude CLI unavailable; subagents will fail until it is installed and authenticated", "error", err)
+	}
+
+	c.mu.Lock()
+	c.claudeStatus = &status
+	c.mu.Unlock()
+	return status
+}
+
+// ClaudeStatus returns the last Claude CLI pre-flight result, running the
+// check if it has not completed yet
+func (c *Captain) ClaudeStatus(ctx context.Context) ClaudeStatus {
+	c.mu.RLock()
+	status := c.claudeStatus
+	c.mu.RUnlock()
+	if status != nil {
+		return *status
+	}
+	return c.RefreshClaudeStatus(ctx)
+}
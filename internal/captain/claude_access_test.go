@@ -0,0 +1,68 @@
+package captain
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestClaudeVersion(t *testing.T) {
+	version, err := claudeVersion(context.Background(), func(ctx context.Context) ([]byte, error) {
+		return []byte("1.0.58 (Claude Code)\n"), nil
+	})
+	if err != nil || version != "1.0.58 (Claude Code)" {
+		t.Errorf("claudeVersion() = %q, %v, want trimmed version", version, err)
+	}
+
+	_, err = claudeVersion(context.Background(), func(ctx context.Context) ([]byte, error) {
+		return nil, errors.New(`exec: "claude": executable file not found in $PATH`)
+	})
+	if err == nil || !strings.Contains(err.Error(), "not accessible") {
+		t.Errorf("claudeVersion() = %v, want not accessible error", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = claudeVersion(ctx, func(ctx context.Context) ([]byte, error) {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	})
+	if err == nil {
+		t.Error("claudeVersion() with a cancelled context = nil, want error")
+	}
+}
+
+func TestCaptainClaudeStatus(t *testing.T) {
+	available := true
+	c := &Captain{ClaudeVersionRunner: func(ctx context.Context) ([]byte, error) {
+		if !available {
+			return nil, errors.New(`exec: "claude": executable file not found in $PATH`)
+		}
+		return []byte("1.0.58 (Claude Code)\n"), nil
+	}}
+
+	status := c.ClaudeStatus(context.Background())
+	if !status.Available || status.Version != "1.0.58 (Claude Code)" {
+		t.Errorf("ClaudeStatus() = %+v, want available with trimmed version", status)
+	}
+
+	// The cached status is kept until refreshed
+	available = false
+	if status := c.ClaudeStatus(context.Background()); !status.Available {
+		t.Error("ClaudeStatus() re-ran the check instead of using the cached result")
+	}
+	status = c.RefreshClaudeStatus(context.Background())
+	if status.Available || status.Version != "" || status.Error == "" {
+		t.Errorf("RefreshClaudeStatus() = %+v, want unavailable with error", status)
+	}
+}
+
+func TestNewCaptainSkipsClaudeCheck(t *testing.T) {
+	c := NewCaptain(t.TempDir(), nil, nil, nil)
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if c.claudeStatus != nil {
+		t.Error("NewCaptain should not run the Claude CLI check; Run starts it")
+	}
+}
@@ -0,0 +1,47 @@
+package captain
+
+import "time"
+
+// CyclePolicy decides how long Captain waits between orchestration cycles
+type CyclePolicy interface {
+	NextInterval(queueDepth int) time.Duration
+}
+
+// FixedPolicy always returns the same interval regardless of queue depth
+type FixedPolicy time.Duration
+
+// NextInterval returns the fixed interval
+func (p FixedPolicy) NextInterval(queueDepth int) time.Duration {
+	return time.Duration(p)
+}
+
+// LinearPolicy is the default adaptive interval policy: idle when the queue
+// is empty, faster as work piles up.
+type LinearPolicy struct {
+	EmptyInterval time.Duration // queue depth 0
+	LightInterval time.Duration // queue depth 1..LightMaxDepth
+	HeavyInterval time.Duration // queue depth > LightMaxDepth
+	LightMaxDepth int
+}
+
+// NewLinearPolicy returns the default 60s / 15s / 5s policy
+func NewLinearPolicy() *LinearPolicy {
+	return &LinearPolicy{
+		EmptyInterval: 60 * time.Second,
+		LightInterval: 15 * time.Second,
+		HeavyInterval: 5 * time.Second,
+		LightMaxDepth: 5,
+	}
+}
+
+// NextInterval returns the wait before the next cycle for the given queue depth
+func (p *LinearPolicy) NextInterval(queueDepth int) time.Duration {
+	switch {
+	case queueDepth <= 0:
+		return p.EmptyInterval
+	case queueDepth <= p.LightMaxDepth:
+		return p.LightInterval
+	default:
+		return p.HeavyInterval
+	}
+}
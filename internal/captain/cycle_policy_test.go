@@ -0,0 +1,45 @@
+package captain
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLinearPolicy_NextInterval(t *testing.T) {
+	p := NewLinearPolicy()
+
+	tests := []struct {
+		depth int
+		want  time.Duration
+	}{
+		{0, 60 * time.Second},
+		{1, 15 * time.Second},
+		{5, 15 * time.Second},
+		{6, 5 * time.Second},
+		{50, 5 * time.Second},
+	}
+
+	for _, tt := range tests {
+		if got := p.NextInterval(tt.depth); got != tt.want {
+			t.Errorf("NextInterval(%d) = %v, want %v", tt.depth, got, tt.want)
+		}
+	}
+}
+
+func TestCaptain_QueueDepth(t *testing.T) {
+	c := &Captain{
+		taskQueues: map[string][]*CaptainTask{
+			DefaultTeamID: {
+				{Status: "pending"},
+				{Status: "recon_complete"},
+			},
+			"blue": {
+				{Status: "executing"},
+				{Status: "failed"},
+			},
+		},
+	}
+	if got := c.queueDepth(); got != 2 {
+		t.Errorf("queueDepth() = %d, want 2", got)
+	}
+}
@@ -0,0 +1,93 @@
+package captain
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"time"
+)
+
+// DispatchSubagent starts a mission in the background and returns a channel
+// that receives its result, then closes. Execution errors arrive as a failed
+// result; an error is returned only if the mission cannot be started.
+func (c *Captain) DispatchSubagent(ctx context.Context, mission Mission) (<-chan *SubagentResult, error) {
+	if err := ValidateMission(mission); err != nil {
+		return nil, err
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return c.dispatch(ctx, mission, fmt.Sprintf("mission-%s", mission.ID), nil), nil
+}
+
+// dispatch runs a mission in a goroutine, holding a slot of sem (if not nil)
+// while it executes. Failed results are reported under failedID.
+func (c *Captain) dispatch(ctx context.Context, mission Mission, failedID string, sem chan struct{}) <-chan *SubagentResult {
+	ch := make(chan *SubagentResult, 1)
+	go func() {
+		defer close(ch)
+
+		if sem != nil {
+			select {
+			case sem <- struct{}{}:
+				defer func() { <-sem }()
+			case <-ctx.Done():
+				ch <- failedResult(failedID, mission, ctx.Err())
+				return
+			}
+		}
+
+		result, err := c.ExecuteMission(ctx, mission)
+		if err != nil {
+			result = failedResult(failedID, mission, err)
+		}
+		ch <- result
+	}()
+	return ch
+}
+
+// failedResult is the result reported for a mission that could not run
+func failedResult(agentID string, mission Mission, err error) *SubagentResult {
+	return &SubagentResult{
+		AgentID:  agentID,
+		Status:   "failed",
+		Error:    err.Error(),
+		TaskType: mission.TaskType,
+	}
+}
+
+// CollectResults waits for one result from each channel and returns them in
+// channel order. With a positive timeout it stops waiting at the deadline and
+// leaves the missing results nil; their channels can be collected again
+// later. Zero waits for all of them.
+func CollectResults(channels []<-chan *SubagentResult, timeout time.Duration) []*SubagentResult {
+	results := make([]*SubagentResult, len(channels))
+
+	// One select case per pending channel, plus the deadline as the last case
+	cases := make([]reflect.SelectCase, 0, len(channels)+1)
+	pending := make([]int, 0, len(channels))
+	for i, ch := range channels {
+		cases = append(cases, reflect.SelectCase{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(ch)})
+		pending = append(pending, i)
+	}
+	deadline := reflect.SelectCase{Dir: reflect.SelectRecv}
+	if timeout > 0 {
+		timer := time.NewTimer(timeout)
+		defer timer.Stop()
+		deadline.Chan = reflect.ValueOf(timer.C)
+	}
+	cases = append(cases, deadline)
+
+	for len(pending) > 0 {
+		chosen, value, ok := reflect.Select(cases)
+		if chosen == len(cases)-1 {
+			return results
+		}
+		if ok {
+			results[pending[chosen]] = value.Interface().(*SubagentResult)
+		}
+		cases = append(cases[:chosen], cases[chosen+1:]...)
+		pending = append(pending[:chosen], pending[chosen+1:]...)
+	}
+	return results
+}
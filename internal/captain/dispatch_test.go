@@ -0,0 +1,72 @@
+package captain
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestCollectResults(t *testing.T) {
+	first := make(chan *SubagentResult, 1)
+	second := make(chan *SubagentResult, 1)
+	slow := make(chan *SubagentResult, 1)
+
+	second <- &SubagentResult{AgentID: "b"}
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		first <- &SubagentResult{AgentID: "a"}
+	}()
+
+	results := CollectResults([]<-chan *SubagentResult{first, second, slow}, 200*time.Millisecond)
+	if len(results) != 3 {
+		t.Fatalf("Expected 3 results, got %d", len(results))
+	}
+	if results[0] == nil || results[0].AgentID != "a" || results[1] == nil || results[1].AgentID != "b" {
+		t.Errorf("Results not in channel order: %+v", results)
+	}
+	if results[2] != nil {
+		t.Errorf("Expected nil for the timed out channel, got %+v", results[2])
+	}
+
+	// Without a timeout every result is waited for
+	slow <- &SubagentResult{AgentID: "c"}
+	results = CollectResults([]<-chan *SubagentResult{slow}, 0)
+	if results[0] == nil || results[0].AgentID != "c" {
+		t.Errorf("Expected result c, got %+v", results)
+	}
+}
+
+func TestDispatchSubagentRejectsInvalidMission(t *testing.T) {
+	c := NewCaptain(".", nil, nil, nil)
+
+	if _, err := c.DispatchSubagent(context.Background(), Mission{ID: "p1", TaskType: TaskPlanning}); err == nil {
+		t.Error("Expected an error for a planning mission without metadata")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := c.DispatchSubagent(ctx, Mission{ID: "r1", TaskType: TaskRecon}); err == nil {
+		t.Error("Expected an error for a cancelled context")
+	}
+}
+
+func TestExecuteMissionsParallelReportsFailures(t *testing.T) {
+	c := NewCaptain(".", nil, nil, nil)
+	missions := []Mission{
+		{ID: "p1", TaskType: TaskPlanning},
+		{ID: "p2", TaskType: TaskPlanning},
+	}
+
+	results := c.ExecuteMissionsParallel(context.Background(), missions)
+	if len(results) != 2 {
+		t.Fatalf("Expected 2 results, got %d", len(results))
+	}
+	for i, r := range results {
+		if r == nil || r.Status != "failed" || r.Error == "" {
+			t.Fatalf("Expected failed result %d, got %+v", i, r)
+		}
+		if want := "mission-" + string(rune('0'+i)); r.AgentID != want {
+			t.Errorf("AgentID = %q, want %q", r.AgentID, want)
+		}
+	}
+}
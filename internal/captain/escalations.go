@@ -0,0 +1,128 @@
+package captain
+
+import (
+	"errors"
+	"time"
+
+	"github.com/CLIAIMONITOR/internal/events"
+	"github.com/CLIAIMONITOR/internal/logger"
+	"github.com/CLIAIMONITOR/internal/memory"
+)
+
+// EscalationTarget is the event bus target escalation_resolved events are sent to
+const EscalationTarget = "captain"
+
+var (
+	// ErrEscalationNotFound is returned when resolving an unknown escalation
+	ErrEscalationNotFound = errors.New("escalation not found")
+	// ErrEscalationResolved is returned when resolving an escalation twice
+	ErrEscalationResolved = errors.New("escalation already resolved")
+)
+
+// ResolveEscalation marks an escalation resolved, records the resolution in
+// the memory DB and publishes an escalation_resolved event
+func (c *Captain) ResolveEscalation(id, resolution, resolvedBy string) (*Escalation, error) {
+	c.mu.Lock()
+	c.loadEscalations()
+
+	var esc *Escalation
+	for i := range c.escalations {
+		if c.escalations[i].ID == id {
+			esc = &c.escalations[i]
+			break
+		}
+	}
+	if esc == nil {
+		c.mu.Unlock()
+		return nil, ErrEscalationNotFound
+	}
+	if esc.Resolved {
+		c.mu.Unlock()
+		return nil, ErrEscalationResolved
+	}
+
+	now := time.Now()
+	esc.Resolved = true
+	esc.Resolution = resolution
+	esc.ResolvedBy = resolvedBy
+	esc.ResolvedAt = &now
+	resolved := *esc
+	c.saveEscalation(resolved)
+	bus := c.eventBus
+	c.mu.Unlock()
+
+	logger.For("captain").Info("escalation resolved", "escalation_id", id, "resolved_by", resolvedBy)
+	if bus != nil {
+		bus.Publish(events.NewEvent(events.EventEscalationResolved, "captain", EscalationTarget, events.PriorityNormal, map[string]interface{}{
+			"escalation_id": resolved.ID,
+			"task_id":       resolved.TaskID,
+			"agent_id":      resolved.AgentID,
+			"resolution":    resolution,
+			"resolved_by":   resolvedBy,
+		}))
+	}
+	return &resolved, nil
+}
+
+// loadEscalations merges escalations stored in the memory DB into the
+// in-memory list, once. Caller must hold c.mu.
+func (c *Captain) loadEscalations() {
+	if c.escLoaded || c.memDB == nil {
+		return
+	}
+	records, err := c.memDB.GetCaptainEscalations()
+	if err != nil {
+		logger.For("captain").Warn("failed to load escalations", "error", err)
+		return
+	}
+	c.escLoaded = true
+
+	known := make(map[string]bool, len(c.escalations))
+	for _, esc := range c.escalations {
+		known[esc.ID] = true
+	}
+	var stored []Escalation
+	for _, r := range records {
+		if known[r.ID] {
+			continue
+		}
+		stored = append(stored, Escalation{
+			ID:         r.ID,
+			TaskID:     r.TaskID,
+			AgentID:    r.AgentID,
+			Reason:     r.Reason,
+			Context:    r.Context,
+			Question:   r.Question,
+			CreatedAt:  r.CreatedAt,
+			Resolved:   r.ResolvedAt != nil,
+			Resolution: r.Resolution,
+			ResolvedBy: r.ResolvedBy,
+			ResolvedAt: r.ResolvedAt,
+		})
+	}
+	// Stored escalations predate this run's
+	c.escalations = append(stored, c.escalations...)
+}
+
+// saveEscalation persists an escalation to the memory DB. Failures are logged;
+// the in-memory escalation stays authoritative for this run.
+func (c *Captain) saveEscalation(esc Escalation) {
+	if c.memDB == nil {
+		return
+	}
+	err := c.memDB.SaveCaptainEscalation(&memory.CaptainEscalationRecord{
+		ID:         esc.ID,
+		TaskID:     esc.TaskID,
+		AgentID:    esc.AgentID,
+		Reason:     esc.Reason,
+		Context:    esc.Context,
+		Question:   esc.Question,
+		Resolution: esc.Resolution,
+		ResolvedBy: esc.ResolvedBy,
+		CreatedAt:  esc.CreatedAt,
+		ResolvedAt: esc.ResolvedAt,
+	})
+	if err != nil {
+		logger.For("captain").Warn("failed to persist escalation", "escalation_id", esc.ID, "error", err)
+	}
+}
@@ -0,0 +1,76 @@
+package captain
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/CLIAIMONITOR/internal/events"
+	"github.com/CLIAIMONITOR/internal/memory"
+)
+
+func TestResolveEscalation(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "test.db")
+	db, err := memory.NewMemoryDB(dbPath)
+	if err != nil {
+		t.Fatalf("Failed to create test database: %v", err)
+	}
+	defer db.Close()
+
+	bus := events.NewBus(nil)
+	ch := bus.Subscribe(EscalationTarget, []events.EventType{events.EventEscalationResolved})
+
+	c := NewCaptain(".", nil, db, nil)
+	c.SetEventBus(bus)
+	c.createAgentEscalation("agent-1", "stuck", "no output for 10m")
+	c.createAgentEscalation("agent-2", "crashed", "exit 1")
+	escalations := c.GetEscalations()
+	if len(escalations) != 2 {
+		t.Fatalf("Expected 2 escalations, got %d", len(escalations))
+	}
+	id := escalations[0].ID
+
+	esc, err := c.ResolveEscalation(id, "restarted agent", "alice")
+	if err != nil {
+		t.Fatalf("ResolveEscalation failed: %v", err)
+	}
+	if !esc.Resolved || esc.Resolution != "restarted agent" || esc.ResolvedBy != "alice" || esc.ResolvedAt == nil {
+		t.Errorf("Unexpected resolved escalation: %+v", esc)
+	}
+
+	select {
+	case event := <-ch:
+		if event.Payload["escalation_id"] != id || event.Payload["resolved_by"] != "alice" {
+			t.Errorf("Unexpected event payload: %v", event.Payload)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Expected an escalation_resolved event")
+	}
+
+	if _, err := c.ResolveEscalation(id, "again", "bob"); !errors.Is(err, ErrEscalationResolved) {
+		t.Errorf("Expected ErrEscalationResolved, got %v", err)
+	}
+	if _, err := c.ResolveEscalation("missing", "x", "bob"); !errors.Is(err, ErrEscalationNotFound) {
+		t.Errorf("Expected ErrEscalationNotFound, got %v", err)
+	}
+
+	// A new Captain sees both escalations, with the resolution, from the DB
+	restarted := NewCaptain(".", nil, db, nil)
+	loaded := restarted.GetEscalations()
+	if len(loaded) != 2 {
+		t.Fatalf("Expected 2 stored escalations, got %+v", loaded)
+	}
+	for _, e := range loaded {
+		resolved := e.ID == id
+		if e.Resolved != resolved {
+			t.Errorf("Escalation %s: resolved = %v, want %v", e.ID, e.Resolved, resolved)
+		}
+		if resolved && (e.Resolution != "restarted agent" || e.ResolvedBy != "alice") {
+			t.Errorf("Stored resolution not loaded: %+v", e)
+		}
+	}
+	if _, err := restarted.ResolveEscalation(loaded[1].ID, "replaced", "bob"); err != nil {
+		t.Errorf("Resolving a stored escalation failed: %v", err)
+	}
+}
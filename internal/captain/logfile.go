@@ -0,0 +1,238 @@
+package captain
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/CLIAIMONITOR/internal/wezterm"
+)
+
+const (
+	// DefaultLogMaxBytes is the Captain log size that triggers rotation
+	DefaultLogMaxBytes = 10 * 1024 * 1024
+	// DefaultLogMaxKeep is how many rotated Captain logs are kept
+	DefaultLogMaxKeep = 3
+	// LogPollInterval is how often the Captain pane's text is copied to the log
+	LogPollInterval = 2 * time.Second
+	// logScrollbackLines is how far into the pane's scrollback each poll
+	// reads, so output that scrolled off screen between polls is still logged
+	logScrollbackLines = 500
+)
+
+// rotatingFile is an append-only log file that rotates to path.1 ... path.N
+// once it would grow past maxBytes
+type rotatingFile struct {
+	mu       sync.Mutex
+	path     string
+	maxBytes int64
+	maxKeep  int
+	file     *os.File
+	size     int64
+}
+
+// openRotatingFile opens (or creates) path for appending
+func openRotatingFile(path string, maxBytes int64, maxKeep int) (*rotatingFile, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create log directory: %w", err)
+	}
+	r := &rotatingFile{path: path, maxBytes: maxBytes, maxKeep: maxKeep}
+	if err := r.open(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func (r *rotatingFile) open() error {
+	f, err := os.OpenFile(r.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open log file: %w", err)
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return fmt.Errorf("failed to stat log file: %w", err)
+	}
+	r.file = f
+	r.size = info.Size()
+	return nil
+}
+
+// Write appends p, rotating first if p would push the file past maxBytes
+func (r *rotatingFile) Write(p []byte) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.file == nil {
+		return 0, os.ErrClosed
+	}
+	if r.size > 0 && r.size+int64(len(p)) > r.maxBytes {
+		if err := r.rotate(); err != nil {
+			return 0, err
+		}
+	}
+
+	n, err := r.file.Write(p)
+	r.size += int64(n)
+	return n, err
+}
+
+// rotate shifts path.N-1 -> path.N ... path -> path.1, dropping the oldest,
+// and reopens an empty path. The file is closed before renaming since
+// Windows cannot rename open files.
+func (r *rotatingFile) rotate() error {
+	if err := r.file.Close(); err != nil {
+		return fmt.Errorf("failed to close log file for rotation: %w", err)
+	}
+	r.file = nil
+
+	os.Remove(fmt.Sprintf("%s.%d", r.path, r.maxKeep))
+	for i := r.maxKeep - 1; i >= 1; i-- {
+		os.Rename(fmt.Sprintf("%s.%d", r.path, i), fmt.Sprintf("%s.%d", r.path, i+1))
+	}
+	if err := os.Rename(r.path, r.path+".1"); err != nil {
+		return fmt.Errorf("failed to rotate log file: %w", err)
+	}
+
+	return r.open()
+}
+
+// readCaptainPane returns the text of a WezTerm pane, including recent
+// scrollback
+func readCaptainPane(paneID int) (string, error) {
+	return wezterm.Get().GetPaneText(paneID, -logScrollbackLines, 0)
+}
+
+// captainPaneTitles are the console titles the Captain launch commands set
+var captainPaneTitles = map[string]bool{"CLIAIMONITOR-Captain": true, "Captain": true}
+
+// findCaptainWindowPane finds the pane of a Captain launched in its own
+// WezTerm window by its title, waiting up to ten seconds for it to appear
+func findCaptainWindowPane() (int, bool) {
+	for attempt := 0; attempt < 10; attempt++ {
+		time.Sleep(time.Second)
+		panes, err := wezterm.Get().ListPanes()
+		if err != nil {
+			continue
+		}
+		for _, pane := range panes {
+			if captainPaneTitles[pane.Title] {
+				return pane.PaneID, true
+			}
+		}
+	}
+	return 0, false
+}
+
+// paneLines splits pane text into lines, dropping the blank lines below the
+// cursor that WezTerm pads the screen with
+func paneLines(text string) []string {
+	lines := strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n")
+	for len(lines) > 0 && strings.TrimSpace(lines[len(lines)-1]) == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// newPaneLines returns the lines of cur that were not in prev. Between polls
+// the pane scrolls, so cur starts with some tail of prev; the longest such
+// tail is taken as already logged. With no overlap all of cur is new.
+func newPaneLines(prev, cur []string) []string {
+	for start := 0; start < len(prev); start++ {
+		overlap := len(prev) - start
+		if overlap > len(cur) {
+			continue
+		}
+		matched := true
+		for i := 0; i < overlap; i++ {
+			if prev[start+i] != cur[i] {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return cur[overlap:]
+		}
+	}
+	return cur
+}
+
+// captureLog copies new lines of the Captain pane into the rotating log file
+// every logPollInterval until stop closes. Claude's TUI needs the pane's
+// terminal for its output, so the log is read back from WezTerm instead of
+// being piped off the process.
+func (s *CaptainSupervisor) captureLog(paneID int, stop <-chan struct{}) {
+	log, err := openRotatingFile(s.logFile, s.logMaxBytes, s.logMaxKeep)
+	if err != nil {
+		fmt.Printf("[SUPERVISOR] Warning: Captain log disabled: %v\n", err)
+		return
+	}
+	defer log.Close()
+	fmt.Fprintf(log, "=== Captain started %s (pane %d) ===\n", time.Now().Format(time.RFC3339), paneID)
+
+	ticker := time.NewTicker(s.logPollInterval)
+	defer ticker.Stop()
+
+	var prev []string
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+		}
+
+		text, err := s.paneText(paneID)
+		if err != nil {
+			// The pane may be gone or WezTerm busy; try again next tick
+			continue
+		}
+		cur := paneLines(text)
+		for _, line := range newPaneLines(prev, cur) {
+			fmt.Fprintln(log, line)
+		}
+		prev = cur
+	}
+}
+
+// startLogCapture starts copying paneID's text to the log file, ending the
+// capture of any earlier pane. It does nothing without a LogFile.
+func (s *CaptainSupervisor) startLogCapture(paneID int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.logFile == "" {
+		return
+	}
+	s.stopLogCaptureLocked()
+	s.logStop = make(chan struct{})
+	go s.captureLog(paneID, s.logStop)
+}
+
+// stopLogCaptureLocked ends the running log capture. Callers must hold s.mu.
+func (s *CaptainSupervisor) stopLogCaptureLocked() {
+	if s.logStop != nil {
+		close(s.logStop)
+		s.logStop = nil
+	}
+}
+
+// Size returns the current log file size in bytes
+func (r *rotatingFile) Size() int64 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.size
+}
+
+// Close closes the log file; further writes fail
+func (r *rotatingFile) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.file == nil {
+		return nil
+	}
+	err := r.file.Close()
+	r.file = nil
+	return err
+}
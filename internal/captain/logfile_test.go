@@ -0,0 +1,128 @@
+package captain
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRotatingFileRotates(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "captain.log")
+	r, err := openRotatingFile(path, 10, 2)
+	if err != nil {
+		t.Fatalf("openRotatingFile failed: %v", err)
+	}
+	defer r.Close()
+
+	for _, line := range []string{"first\n", "second\n", "third\n", "fourth\n"} {
+		if _, err := r.Write([]byte(line)); err != nil {
+			t.Fatalf("Write failed: %v", err)
+		}
+	}
+
+	read := func(p string) string {
+		data, err := os.ReadFile(p)
+		if err != nil {
+			t.Fatalf("ReadFile(%s) failed: %v", p, err)
+		}
+		return string(data)
+	}
+
+	if got := read(path); got != "fourth\n" {
+		t.Errorf("Expected current log 'fourth', got %q", got)
+	}
+	if got := read(path + ".1"); got != "third\n" {
+		t.Errorf("Expected .1 to hold 'third', got %q", got)
+	}
+	if got := read(path + ".2"); got != "second\n" {
+		t.Errorf("Expected .2 to hold 'second', got %q", got)
+	}
+	if _, err := os.Stat(path + ".3"); !os.IsNotExist(err) {
+		t.Error("Expected at most 2 rotated files")
+	}
+	if r.Size() != int64(len("fourth\n")) {
+		t.Errorf("Expected size %d, got %d", len("fourth\n"), r.Size())
+	}
+}
+
+func TestSupervisorInfoReportsLogFile(t *testing.T) {
+	dir := t.TempDir()
+	logPath := filepath.Join(dir, "captain.log")
+	os.WriteFile(logPath, []byte(strings.Repeat("x", 42)), 0644)
+
+	s := NewCaptainSupervisor(SupervisorConfig{BasePath: dir, LogFile: logPath})
+	info := s.GetInfo()
+	if info.LogFile != logPath {
+		t.Errorf("Expected log_file %s, got %s", logPath, info.LogFile)
+	}
+	if info.LogSizeBytes != 42 {
+		t.Errorf("Expected log_size_bytes 42, got %d", info.LogSizeBytes)
+	}
+}
+
+func TestNewPaneLines(t *testing.T) {
+	tests := []struct {
+		name      string
+		prev, cur []string
+		want      []string
+	}{
+		{"first capture", nil, []string{"a", "b"}, []string{"a", "b"}},
+		{"unchanged", []string{"a", "b"}, []string{"a", "b"}, []string{}},
+		{"appended", []string{"a", "b"}, []string{"a", "b", "c"}, []string{"c"}},
+		{"scrolled", []string{"a", "b", "c"}, []string{"b", "c", "d", "e"}, []string{"d", "e"}},
+		{"no overlap", []string{"a", "b"}, []string{"x", "y"}, []string{"x", "y"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := newPaneLines(tt.prev, tt.cur)
+			if strings.Join(got, "|") != strings.Join(tt.want, "|") {
+				t.Errorf("newPaneLines = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCaptureLogFromPaneText(t *testing.T) {
+	logPath := filepath.Join(t.TempDir(), "captain.log")
+	screens := make(chan string, 3)
+	screens <- "> hello\n\n\n"
+	screens <- "> hello\nHi, I'm Captain\n\n"
+	screens <- "Hi, I'm Captain\n> status\n"
+
+	s := NewCaptainSupervisor(SupervisorConfig{BasePath: t.TempDir(), LogFile: logPath})
+	s.logPollInterval = time.Millisecond
+	s.paneText = func(paneID int) (string, error) {
+		if paneID != 7 {
+			t.Errorf("Expected pane 7 to be read, got %d", paneID)
+		}
+		select {
+		case text := <-screens:
+			return text, nil
+		default:
+			return "", errors.New("no more screens")
+		}
+	}
+
+	s.startLogCapture(7)
+	deadline := time.Now().Add(5 * time.Second)
+	for len(screens) > 0 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	time.Sleep(20 * time.Millisecond)
+	s.Stop()
+
+	data, err := os.ReadFile(logPath)
+	if err != nil {
+		t.Fatalf("ReadFile failed: %v", err)
+	}
+	lines := strings.Split(strings.TrimSuffix(string(data), "\n"), "\n")
+	if len(lines) != 4 || !strings.HasPrefix(lines[0], "=== Captain started ") {
+		t.Fatalf("Expected a start marker and three lines, got %q", data)
+	}
+	if want := []string{"> hello", "Hi, I'm Captain", "> status"}; strings.Join(lines[1:], "|") != strings.Join(want, "|") {
+		t.Errorf("Expected %q, got %q", want, lines[1:])
+	}
+}
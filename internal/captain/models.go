@@ -0,0 +1,106 @@
+package captain
+
+import (
+	"os"
+	"sort"
+	"strings"
+)
+
+// ModelOverrideEnvPrefix prefixes the per-agent-type model override
+// environment variables, e.g. MODEL_OVERRIDE_SNAKE=claude-opus-4-5
+const ModelOverrideEnvPrefix = "MODEL_OVERRIDE_"
+
+// Default subagent models, used when neither an override nor a config sets one
+const (
+	DefaultOpusModel   = "claude-opus-4-5-20251101"
+	DefaultSonnetModel = "claude-sonnet-4-5-20250929"
+)
+
+// Where an effective model assignment came from
+const (
+	ModelSourceEnv     = "environment"
+	ModelSourceConfig  = "config"
+	ModelSourceDefault = "default"
+)
+
+// captainAgentTypes are the agent types determineMode can pick on its own,
+// whether or not teams.yaml defines them
+var captainAgentTypes = []string{"Snake", "SNTPurple", "OpusRed", "OpusGreen", "SNTGreen", "Planner"}
+
+// ModelAssignment is the model a subagent of one agent type runs with
+type ModelAssignment struct {
+	AgentType string `json:"agent_type"`
+	Model     string `json:"model"`
+	Source    string `json:"source"`  // environment, config or default
+	EnvVar    string `json:"env_var"` // variable that would override it
+}
+
+// ModelOverrideEnvVar returns the environment variable that overrides the
+// model for an agent type: upper-cased, with non-alphanumerics as underscores
+func ModelOverrideEnvVar(agentType string) string {
+	name := strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z':
+			return r - 'a' + 'A'
+		case r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+			return r
+		default:
+			return '_'
+		}
+	}, agentType)
+	return ModelOverrideEnvPrefix + name
+}
+
+// resolveModel picks the model for an agent type: the environment override
+// first, then the agent config, then a default based on the type's name
+func (c *Captain) resolveModel(agentType string) ModelAssignment {
+	assignment := ModelAssignment{
+		AgentType: agentType,
+		EnvVar:    ModelOverrideEnvVar(agentType),
+	}
+
+	if model := strings.TrimSpace(os.Getenv(assignment.EnvVar)); model != "" {
+		assignment.Model = model
+		assignment.Source = ModelSourceEnv
+		return assignment
+	}
+
+	if config, exists := c.configs[agentType]; exists && config.Model != "" {
+		assignment.Model = config.Model
+		assignment.Source = ModelSourceConfig
+		return assignment
+	}
+
+	assignment.Source = ModelSourceDefault
+	agentLower := strings.ToLower(agentType)
+	if strings.HasPrefix(agentLower, "opus") || strings.HasPrefix(agentLower, "snake") {
+		assignment.Model = DefaultOpusModel
+	} else {
+		assignment.Model = DefaultSonnetModel
+	}
+	return assignment
+}
+
+// ModelAssignments returns the effective model for every configured agent
+// type and every type Captain selects itself, sorted by agent type
+func (c *Captain) ModelAssignments() []ModelAssignment {
+	seen := make(map[string]bool, len(c.configs)+len(captainAgentTypes))
+	for name := range c.configs {
+		seen[name] = true
+	}
+	for _, name := range captainAgentTypes {
+		seen[name] = true
+	}
+
+	names := make([]string, 0, len(seen))
+	for name := range seen {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	assignments := make([]ModelAssignment, 0, len(names))
+	for _, name := range names {
+		assignments = append(assignments, c.resolveModel(name))
+	}
+	return assignments
+}
@@ -0,0 +1,52 @@
+package captain
+
+import (
+	"testing"
+
+	"github.com/CLIAIMONITOR/internal/types"
+)
+
+func TestModelOverrideEnvVar(t *testing.T) {
+	tests := map[string]string{
+		"Snake":     "MODEL_OVERRIDE_SNAKE",
+		"SNTGreen":  "MODEL_OVERRIDE_SNTGREEN",
+		"opus-red2": "MODEL_OVERRIDE_OPUS_RED2",
+	}
+	for agentType, want := range tests {
+		if got := ModelOverrideEnvVar(agentType); got != want {
+			t.Errorf("ModelOverrideEnvVar(%q) = %q, want %q", agentType, got, want)
+		}
+	}
+}
+
+func TestResolveModelPrecedence(t *testing.T) {
+	c := NewCaptain(".", nil, nil, map[string]types.AgentConfig{
+		"SNTGreen": {Name: "SNTGreen", Model: "claude-sonnet-4-5"},
+		"Blank":    {Name: "Blank"},
+	})
+
+	if got := c.resolveModel("SNTGreen"); got.Model != "claude-sonnet-4-5" || got.Source != ModelSourceConfig {
+		t.Errorf("expected config model, got %+v", got)
+	}
+	if got := c.resolveModel("Snake"); got.Model != DefaultOpusModel || got.Source != ModelSourceDefault {
+		t.Errorf("expected default opus model, got %+v", got)
+	}
+	if got := c.resolveModel("Blank"); got.Model != DefaultSonnetModel || got.Source != ModelSourceDefault {
+		t.Errorf("expected default for config without a model, got %+v", got)
+	}
+
+	t.Setenv("MODEL_OVERRIDE_SNTGREEN", "claude-opus-4-5")
+	if got := c.getModelForAgent("SNTGreen"); got != "claude-opus-4-5" {
+		t.Errorf("expected environment override, got %q", got)
+	}
+
+	var found bool
+	for _, a := range c.ModelAssignments() {
+		if a.AgentType == "SNTGreen" {
+			found = a.Source == ModelSourceEnv && a.EnvVar == "MODEL_OVERRIDE_SNTGREEN"
+		}
+	}
+	if !found {
+		t.Error("expected SNTGreen assignment from environment")
+	}
+}
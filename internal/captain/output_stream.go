@@ -0,0 +1,70 @@
+package captain
+
+import (
+	"bytes"
+	"io"
+	"os/exec"
+
+	"github.com/CLIAIMONITOR/internal/events"
+)
+
+// OutputStreamTarget is the event bus target subagent_output_chunk events
+// are sent to
+const OutputStreamTarget = "captain_output"
+
+// OutputChunkSize is the most subagent output published in one event
+const OutputChunkSize = 4096
+
+// PublishOutputChunk publishes a piece of a task's live output as a
+// subagent_output_chunk event. Chunks are not persisted, and a subscriber
+// that falls behind misses chunks instead of stalling the task's output.
+func (c *Captain) PublishOutputChunk(taskID, agentID, chunk string) {
+	c.mu.RLock()
+	bus := c.eventBus
+	c.mu.RUnlock()
+	if bus == nil || chunk == "" {
+		return
+	}
+
+	bus.PublishTransient(events.NewEvent(events.EventSubagentOutputChunk, "captain", OutputStreamTarget, events.PriorityLow, map[string]interface{}{
+		"task_id":  taskID,
+		"agent_id": agentID,
+		"chunk":    chunk,
+	}))
+}
+
+// runStreamingOutput runs cmd with stdout and stderr joined through a pipe,
+// publishing output for the task as it arrives. Returns the combined output
+// like CombinedOutput.
+func (c *Captain) runStreamingOutput(cmd *exec.Cmd, taskID, agentID string) ([]byte, error) {
+	pr, pw := io.Pipe()
+	cmd.Stdout = pw
+	cmd.Stderr = pw
+
+	var output bytes.Buffer
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		c.readOutputChunks(pr, &output, taskID, agentID)
+	}()
+
+	err := cmd.Run()
+	pw.Close()
+	<-done
+	return output.Bytes(), err
+}
+
+// readOutputChunks copies r into output until EOF, publishing each read
+func (c *Captain) readOutputChunks(r io.Reader, output *bytes.Buffer, taskID, agentID string) {
+	buf := make([]byte, OutputChunkSize)
+	for {
+		n, err := r.Read(buf)
+		if n > 0 {
+			output.Write(buf[:n])
+			c.PublishOutputChunk(taskID, agentID, string(buf[:n]))
+		}
+		if err != nil {
+			return
+		}
+	}
+}
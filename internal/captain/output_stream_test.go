@@ -0,0 +1,105 @@
+package captain
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/CLIAIMONITOR/internal/events"
+)
+
+// TestOutputStreamHelperProcess is run as a subprocess by
+// TestRunStreamingOutput rather than as a test
+func TestOutputStreamHelperProcess(t *testing.T) {
+	if os.Getenv("CAPTAIN_OUTPUT_HELPER") != "1" {
+		return
+	}
+	fmt.Fprint(os.Stdout, "to stdout\n")
+	fmt.Fprint(os.Stderr, "to stderr\n")
+	os.Exit(3)
+}
+
+func TestRunStreamingOutput(t *testing.T) {
+	bus := events.NewBus(nil)
+	ch := bus.Subscribe(OutputStreamTarget, []events.EventType{events.EventSubagentOutputChunk})
+	c := NewCaptain(".", nil, nil, nil)
+	c.SetEventBus(bus)
+
+	cmd := exec.Command(os.Args[0], "-test.run=^TestOutputStreamHelperProcess$")
+	cmd.Env = append(os.Environ(), "CAPTAIN_OUTPUT_HELPER=1")
+	output, err := c.runStreamingOutput(cmd, "task-1", "team-sntgreen001")
+
+	exitErr, ok := err.(*exec.ExitError)
+	if !ok || exitErr.ExitCode() != 3 {
+		t.Fatalf("Expected exit code 3, got %v", err)
+	}
+	if !strings.Contains(string(output), "to stdout") || !strings.Contains(string(output), "to stderr") {
+		t.Errorf("Expected combined output, got %q", output)
+	}
+
+	var streamed strings.Builder
+	for len(ch) > 0 {
+		event := <-ch
+		if event.Payload["task_id"] != "task-1" || event.Payload["agent_id"] != "team-sntgreen001" {
+			t.Errorf("Unexpected payload: %v", event.Payload)
+		}
+		streamed.WriteString(event.Payload["chunk"].(string))
+	}
+	if streamed.String() != string(output) {
+		t.Errorf("Streamed %q, captured %q", streamed.String(), output)
+	}
+}
+
+func TestReadOutputChunksSplitsLargeOutput(t *testing.T) {
+	bus := events.NewBus(nil)
+	ch := bus.Subscribe(OutputStreamTarget, nil)
+	c := NewCaptain(".", nil, nil, nil)
+	c.SetEventBus(bus)
+
+	input := strings.Repeat("x", OutputChunkSize+10)
+	var output bytes.Buffer
+	c.readOutputChunks(strings.NewReader(input), &output, "task-1", "")
+
+	if output.String() != input {
+		t.Errorf("Expected all output to be captured, got %d bytes", output.Len())
+	}
+	if len(ch) != 2 {
+		t.Fatalf("Expected 2 chunks, got %d", len(ch))
+	}
+	if first := <-ch; len(first.Payload["chunk"].(string)) != OutputChunkSize {
+		t.Errorf("Expected first chunk of %d bytes, got %d", OutputChunkSize, len(first.Payload["chunk"].(string)))
+	}
+}
+
+func TestPublishOutputChunkWithoutBus(t *testing.T) {
+	c := NewCaptain(".", nil, nil, nil)
+	c.PublishOutputChunk("task-1", "", "ignored") // must not panic
+}
+
+func TestReadOutputChunksDoesNotWaitForSlowReaders(t *testing.T) {
+	bus := events.NewBus(nil)
+	ch := bus.Subscribe(OutputStreamTarget, nil) // never drained
+	defer bus.Unsubscribe(OutputStreamTarget, ch)
+	c := NewCaptain(".", nil, nil, nil)
+	c.SetEventBus(bus)
+
+	chunks := events.EventChannelBufferSize * 2
+	input := strings.Repeat("x", OutputChunkSize*chunks)
+	var output bytes.Buffer
+	start := time.Now()
+	c.readOutputChunks(strings.NewReader(input), &output, "task-1", "")
+
+	if output.Len() != len(input) {
+		t.Errorf("Expected all output to be captured, got %d bytes", output.Len())
+	}
+	if elapsed := time.Since(start); elapsed >= events.BackpressureRetryDelay*time.Duration(chunks/2) {
+		t.Errorf("Reading output waited on the full subscriber (%v)", elapsed)
+	}
+	if dropped := bus.DroppedEventCount(); dropped != uint64(chunks-events.EventChannelBufferSize) {
+		t.Errorf("Expected %d dropped chunks, got %d", chunks-events.EventChannelBufferSize, dropped)
+	}
+}
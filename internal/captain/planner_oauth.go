@@ -0,0 +1,348 @@
+package captain
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/CLIAIMONITOR/internal/logger"
+)
+
+// Environment variables used to configure Planner OAuth2
+const (
+	EnvPlannerClientID     = "CLIAIMONITOR_PLANNER_CLIENT_ID"
+	EnvPlannerClientSecret = "CLIAIMONITOR_PLANNER_CLIENT_SECRET"
+	EnvPlannerTokenURL     = "CLIAIMONITOR_PLANNER_TOKEN_URL"
+)
+
+// PlannerTokenRefreshLead is how long before expiry the cached Planner token
+// is refreshed. Short-lived tokens are refreshed halfway through instead.
+const PlannerTokenRefreshLead = time.Minute
+
+// plannerTokenRetryInterval is the wait before retrying a failed background refresh
+const plannerTokenRetryInterval = 30 * time.Second
+
+// ErrPlannerOAuthNotConfigured is returned when no OAuth2Config has been set
+var ErrPlannerOAuthNotConfigured = errors.New("planner OAuth2 not configured")
+
+// OAuth2Config holds client credentials for the Planner API token endpoint
+type OAuth2Config struct {
+	ClientID     string `json:"client_id"`
+	ClientSecret string `json:"-"`
+	TokenURL     string `json:"token_url"`
+}
+
+// PlannerOAuth2ConfigFromEnv reads CLIAIMONITOR_PLANNER_* environment
+// variables. Returns nil unless all three are set.
+func PlannerOAuth2ConfigFromEnv() *OAuth2Config {
+	cfg := &OAuth2Config{
+		ClientID:     os.Getenv(EnvPlannerClientID),
+		ClientSecret: os.Getenv(EnvPlannerClientSecret),
+		TokenURL:     os.Getenv(EnvPlannerTokenURL),
+	}
+	if cfg.ClientID == "" || cfg.ClientSecret == "" || cfg.TokenURL == "" {
+		return nil
+	}
+	return cfg
+}
+
+// plannerToken is a cached access token, persisted to data/planner_token.json
+type plannerToken struct {
+	AccessToken string    `json:"access_token"`
+	Expiry      time.Time `json:"expiry"`
+	RefreshAt   time.Time `json:"refresh_at"`
+}
+
+// fresh reports whether the token can be used without refreshing it first
+func (t *plannerToken) fresh() bool {
+	return t != nil && t.AccessToken != "" && time.Now().Before(t.RefreshAt)
+}
+
+// plannerTokenCache fetches client-credentials tokens for the Planner API
+// and keeps the current one cached, refreshing it before it expires
+type plannerTokenCache struct {
+	cfg    OAuth2Config
+	path   string
+	client *http.Client
+
+	mu       sync.Mutex
+	token    *plannerToken
+	stop     chan struct{}
+	stopOnce sync.Once
+}
+
+// newPlannerTokenCache creates a cache seeded with any token persisted at
+// path by a previous Captain run. An empty path disables persistence.
+func newPlannerTokenCache(cfg OAuth2Config, path string) *plannerTokenCache {
+	tc := &plannerTokenCache{
+		cfg:    cfg,
+		path:   path,
+		client: &http.Client{Timeout: 10 * time.Second},
+		stop:   make(chan struct{}),
+	}
+	tc.token = tc.loadPersisted()
+	return tc
+}
+
+// Token returns the cached token, fetching a new one if it is missing or due
+func (tc *plannerTokenCache) Token(ctx context.Context) (*plannerToken, error) {
+	tc.mu.Lock()
+	defer tc.mu.Unlock()
+	if tc.token.fresh() {
+		return tc.token, nil
+	}
+	return tc.refreshLocked(ctx)
+}
+
+// Refresh fetches a new token regardless of the cached one
+func (tc *plannerTokenCache) Refresh(ctx context.Context) (*plannerToken, error) {
+	tc.mu.Lock()
+	defer tc.mu.Unlock()
+	return tc.refreshLocked(ctx)
+}
+
+// refreshLocked runs the client credentials grant. Caller must hold tc.mu.
+func (tc *plannerTokenCache) refreshLocked(ctx context.Context) (*plannerToken, error) {
+	form := url.Values{
+		"grant_type":    {"client_credentials"},
+		"client_id":     {tc.cfg.ClientID},
+		"client_secret": {tc.cfg.ClientSecret},
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, tc.cfg.TokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create token request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := tc.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to request token: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("token endpoint returned status %d", resp.StatusCode)
+	}
+
+	var body struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int64  `json:"expires_in"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, fmt.Errorf("failed to decode token response: %w", err)
+	}
+	if body.AccessToken == "" {
+		return nil, fmt.Errorf("token response has no access_token")
+	}
+
+	// Tokens without expires_in are assumed to last an hour
+	lifetime := time.Duration(body.ExpiresIn) * time.Second
+	if lifetime <= 0 {
+		lifetime = time.Hour
+	}
+	lead := PlannerTokenRefreshLead
+	if lifetime/2 < lead {
+		lead = lifetime / 2
+	}
+
+	now := time.Now()
+	tc.token = &plannerToken{
+		AccessToken: body.AccessToken,
+		Expiry:      now.Add(lifetime),
+		RefreshAt:   now.Add(lifetime - lead),
+	}
+	tc.persist(tc.token)
+	return tc.token, nil
+}
+
+// persist saves the token to a file only the current user can read so a
+// restarted Captain can reuse it. It is kept out of captain context, which
+// agents can read through the API and MCP tools.
+func (tc *plannerTokenCache) persist(token *plannerToken) {
+	if tc.path == "" {
+		return
+	}
+	data, err := json.Marshal(token)
+	if err != nil {
+		return
+	}
+	if err := os.MkdirAll(filepath.Dir(tc.path), 0755); err != nil {
+		logger.For("captain").Warn("failed to persist Planner token", "path", tc.path, "error", err)
+		return
+	}
+	if err := os.WriteFile(tc.path, data, 0600); err != nil {
+		logger.For("captain").Warn("failed to persist Planner token", "path", tc.path, "error", err)
+	}
+}
+
+// loadPersisted returns the token saved by a previous run if it is still fresh
+func (tc *plannerTokenCache) loadPersisted() *plannerToken {
+	if tc.path == "" {
+		return nil
+	}
+	data, err := os.ReadFile(tc.path)
+	if err != nil {
+		return nil
+	}
+	var token plannerToken
+	if err := json.Unmarshal(data, &token); err != nil || !token.fresh() {
+		return nil
+	}
+	return &token
+}
+
+// nextRefresh returns how long until the cached token is due for refresh
+func (tc *plannerTokenCache) nextRefresh() time.Duration {
+	tc.mu.Lock()
+	defer tc.mu.Unlock()
+	if tc.token == nil {
+		return 0
+	}
+	if wait := time.Until(tc.token.RefreshAt); wait > 0 {
+		return wait
+	}
+	return 0
+}
+
+// run refreshes the token in the background until Stop is called
+func (tc *plannerTokenCache) run() {
+	timer := time.NewTimer(tc.nextRefresh())
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-tc.stop:
+			return
+		case <-timer.C:
+			ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+			_, err := tc.Refresh(ctx)
+			cancel()
+
+			wait := tc.nextRefresh()
+			if err != nil {
+				logger.For("captain").Warn("Planner token refresh failed", "retry_in", plannerTokenRetryInterval, "error", err)
+				wait = plannerTokenRetryInterval
+			}
+			timer.Reset(wait)
+		}
+	}
+}
+
+// Stop ends background refreshing
+func (tc *plannerTokenCache) Stop() {
+	tc.stopOnce.Do(func() { close(tc.stop) })
+}
+
+// plannerTransport adds Planner credentials to every request: a bearer token
+// when OAuth2 is configured, otherwise the static API key
+type plannerTransport struct {
+	base   http.RoundTripper
+	tokens *plannerTokenCache
+	apiKey string
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *plannerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	authed := req.Clone(req.Context())
+	if t.tokens != nil {
+		token, err := t.tokens.Token(req.Context())
+		if err != nil {
+			if req.Body != nil {
+				req.Body.Close()
+			}
+			return nil, fmt.Errorf("planner oauth: %w", err)
+		}
+		authed.Header.Set("Authorization", "Bearer "+token.AccessToken)
+	} else if t.apiKey != "" {
+		authed.Header.Set("X-API-Key", t.apiKey)
+	}
+
+	base := t.base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return base.RoundTrip(authed)
+}
+
+// SetPlannerOAuth2 switches Planner authentication to OAuth2 client
+// credentials and starts refreshing the token in the background
+func (c *Captain) SetPlannerOAuth2(cfg OAuth2Config) {
+	tokens := newPlannerTokenCache(cfg, filepath.Join(c.basePath, "data", "planner_token.json"))
+
+	c.mu.Lock()
+	if c.plannerTokens != nil {
+		c.plannerTokens.Stop()
+	}
+	c.plannerTokens = tokens
+	c.mu.Unlock()
+
+	go tokens.run()
+}
+
+// PlannerHTTPClient returns a client that authenticates requests to the
+// Planner API
+func (c *Captain) PlannerHTTPClient() *http.Client {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return &http.Client{
+		Timeout: 30 * time.Second,
+		Transport: &plannerTransport{
+			tokens: c.plannerTokens,
+			apiKey: c.plannerAPIKey,
+		},
+	}
+}
+
+// SetPlannerProxyURL sets the dashboard endpoint planning agents use to reach
+// the Planner API, so credentials stay in the dashboard process
+func (c *Captain) SetPlannerProxyURL(url string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.plannerProxyURL = url
+}
+
+// PlannerRequest sends a request to the Planner API through
+// PlannerHTTPClient. path, which may carry a query string, is relative to
+// the API base URL.
+func (c *Captain) PlannerRequest(ctx context.Context, method, path string, body io.Reader, contentType string) (*http.Response, error) {
+	c.mu.RLock()
+	base := c.plannerURL
+	c.mu.RUnlock()
+
+	target := strings.TrimSuffix(base, "/") + "/" + strings.TrimPrefix(path, "/")
+	req, err := http.NewRequestWithContext(ctx, method, target, body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create planner request: %w", err)
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	req.Header.Set("Accept", "application/json")
+	return c.PlannerHTTPClient().Do(req)
+}
+
+// RefreshPlannerToken forces a new Planner token and returns its expiry
+func (c *Captain) RefreshPlannerToken(ctx context.Context) (time.Time, error) {
+	c.mu.RLock()
+	tokens := c.plannerTokens
+	c.mu.RUnlock()
+	if tokens == nil {
+		return time.Time{}, ErrPlannerOAuthNotConfigured
+	}
+
+	token, err := tokens.Refresh(ctx)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return token.Expiry, nil
+}
@@ -0,0 +1,160 @@
+package captain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func newTokenServer(t *testing.T, expiresIn int) (*httptest.Server, *int32) {
+	t.Helper()
+	var issued int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil || r.PostForm.Get("grant_type") != "client_credentials" ||
+			r.PostForm.Get("client_id") != "cid" || r.PostForm.Get("client_secret") != "secret" {
+			http.Error(w, "bad request", http.StatusBadRequest)
+			return
+		}
+		n := atomic.AddInt32(&issued, 1)
+		fmt.Fprintf(w, `{"access_token":"token-%d","token_type":"Bearer","expires_in":%d}`, n, expiresIn)
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &issued
+}
+
+func TestPlannerTransportInjectsBearerToken(t *testing.T) {
+	tokenSrv, issued := newTokenServer(t, 3600)
+
+	var gotAuth string
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotAuth = r.Header.Get("Authorization")
+	}))
+	defer api.Close()
+
+	c := NewCaptain(t.TempDir(), nil, nil, nil)
+	c.SetPlannerOAuth2(OAuth2Config{ClientID: "cid", ClientSecret: "secret", TokenURL: tokenSrv.URL})
+	defer c.plannerTokens.Stop()
+
+	client := c.PlannerHTTPClient()
+	for i := 0; i < 2; i++ {
+		resp, err := client.Get(api.URL)
+		if err != nil {
+			t.Fatalf("request failed: %v", err)
+		}
+		resp.Body.Close()
+	}
+
+	if gotAuth != "Bearer token-1" {
+		t.Errorf("Authorization = %q, want cached token-1", gotAuth)
+	}
+	if n := atomic.LoadInt32(issued); n != 1 {
+		t.Errorf("expected 1 token request, got %d", n)
+	}
+
+	expiry, err := c.RefreshPlannerToken(context.Background())
+	if err != nil {
+		t.Fatalf("RefreshPlannerToken failed: %v", err)
+	}
+	if time.Until(expiry) < 59*time.Minute {
+		t.Errorf("unexpected expiry %v", expiry)
+	}
+	resp, err := client.Get(api.URL)
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	resp.Body.Close()
+	if gotAuth != "Bearer token-2" {
+		t.Errorf("Authorization = %q after refresh, want token-2", gotAuth)
+	}
+}
+
+func TestPlannerTokenSurvivesRestart(t *testing.T) {
+	tokenSrv, issued := newTokenServer(t, 3600)
+
+	path := filepath.Join(t.TempDir(), "data", "planner_token.json")
+	cfg := OAuth2Config{ClientID: "cid", ClientSecret: "secret", TokenURL: tokenSrv.URL}
+	first := newPlannerTokenCache(cfg, path)
+	if _, err := first.Token(context.Background()); err != nil {
+		t.Fatalf("Token failed: %v", err)
+	}
+
+	restarted := newPlannerTokenCache(cfg, path)
+	token, err := restarted.Token(context.Background())
+	if err != nil {
+		t.Fatalf("Token failed: %v", err)
+	}
+	if token.AccessToken != "token-1" || atomic.LoadInt32(issued) != 1 {
+		t.Errorf("expected persisted token-1 without a new grant, got %q after %d grants", token.AccessToken, atomic.LoadInt32(issued))
+	}
+}
+
+func TestPlannerTokenBackgroundRefresh(t *testing.T) {
+	// A 1s token is due for refresh after half its lifetime
+	tokenSrv, issued := newTokenServer(t, 1)
+
+	tc := newPlannerTokenCache(OAuth2Config{ClientID: "cid", ClientSecret: "secret", TokenURL: tokenSrv.URL}, "")
+	go tc.run()
+	defer tc.Stop()
+
+	deadline := time.Now().Add(3 * time.Second)
+	for atomic.LoadInt32(issued) < 2 && time.Now().Before(deadline) {
+		time.Sleep(50 * time.Millisecond)
+	}
+	if n := atomic.LoadInt32(issued); n < 2 {
+		t.Errorf("expected background refresh, got %d token requests", n)
+	}
+}
+
+func TestRefreshPlannerTokenNotConfigured(t *testing.T) {
+	c := NewCaptain(".", nil, nil, nil)
+	if _, err := c.RefreshPlannerToken(context.Background()); !errors.Is(err, ErrPlannerOAuthNotConfigured) {
+		t.Errorf("expected ErrPlannerOAuthNotConfigured, got %v", err)
+	}
+}
+
+func TestPlannerRequestUsesAPIKey(t *testing.T) {
+	var gotPath, gotQuery, gotKey string
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath, gotQuery, gotKey = r.URL.Path, r.URL.RawQuery, r.Header.Get("X-API-Key")
+		w.Write([]byte(`{"tasks":[]}`))
+	}))
+	defer api.Close()
+
+	c := NewCaptain(t.TempDir(), nil, nil, nil)
+	c.plannerURL = api.URL + "/api/v1/"
+	c.SetPlannerAPIKey("key-1")
+
+	resp, err := c.PlannerRequest(context.Background(), http.MethodGet, "/tasks?status=pending", nil, "")
+	if err != nil {
+		t.Fatalf("PlannerRequest failed: %v", err)
+	}
+	resp.Body.Close()
+
+	if gotPath != "/api/v1/tasks" || gotQuery != "status=pending" {
+		t.Errorf("request went to %s?%s, want /api/v1/tasks?status=pending", gotPath, gotQuery)
+	}
+	if gotKey != "key-1" {
+		t.Errorf("X-API-Key = %q, want key-1", gotKey)
+	}
+}
+
+func TestPlanningPromptUsesProxy(t *testing.T) {
+	c := NewCaptain(t.TempDir(), nil, nil, nil)
+	c.SetPlannerAPIKey("key-1")
+	c.SetPlannerProxyURL("http://localhost:3000/api/captain/planner")
+
+	prompt := c.buildSubagentPrompt(Mission{ID: "m1", Title: "Plan", TaskType: TaskPlanning}, ModeDecision{})
+	if !strings.Contains(prompt, "API Base: http://localhost:3000/api/captain/planner") {
+		t.Errorf("expected the proxy as API base, got:\n%s", prompt)
+	}
+	if strings.Contains(prompt, "X-API-Key") || strings.Contains(prompt, "key-1") {
+		t.Errorf("prompt should not ask for Planner credentials:\n%s", prompt)
+	}
+}
@@ -0,0 +1,191 @@
+package captain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/CLIAIMONITOR/internal/logger"
+	"github.com/CLIAIMONITOR/internal/memory"
+)
+
+// QueuedMissionTimeout bounds how long a queued mission may execute
+const QueuedMissionTimeout = 30 * time.Minute
+
+// MaxTaskRetries is how many times a task whose agent crashed is re-queued
+// before it is escalated instead
+const MaxTaskRetries = 3
+
+// ErrTaskNotFound is returned when a task ID is not in Captain's queue
+var ErrTaskNotFound = errors.New("task not found in queue")
+
+// ErrTaskNotPending is returned when removing a task that has already started
+var ErrTaskNotPending = errors.New("task is not pending")
+
+// EnqueueMission adds a mission to the orchestration queue as a pending task
+// and returns a copy of it. needsRecon forces a recon pass even when
+// shouldRunRecon would skip it.
+func (c *Captain) EnqueueMission(mission Mission, needsRecon bool) *CaptainTask {
+	now := time.Now()
+	task := &CaptainTask{
+		Mission:    mission,
+		TeamID:     teamKey(mission.TeamID),
+		NeedsRecon: needsRecon || shouldRunRecon(mission),
+		Status:     "pending",
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}
+
+	c.mu.Lock()
+	c.enqueueLocked(task)
+	queued := *task
+	c.mu.Unlock()
+
+	logger.For("captain").Info("queued task", "task_id", mission.ID, "team_id", queued.TeamID, "title", mission.Title)
+	return &queued
+}
+
+// RemoveTask cancels a pending task, drops it from the queue and returns a
+// copy of it. Tasks that have started recon or execution cannot be removed.
+func (c *Captain) RemoveTask(id string) (*CaptainTask, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for team, queue := range c.taskQueues {
+		for i, task := range queue {
+			if task.Mission.ID != id {
+				continue
+			}
+			if task.Status != "pending" {
+				taskCopy := *task
+				return &taskCopy, fmt.Errorf("%w: %s is %s", ErrTaskNotPending, id, task.Status)
+			}
+			task.Status = "cancelled"
+			task.UpdatedAt = time.Now()
+			c.taskQueues[team] = append(queue[:i], queue[i+1:]...)
+			taskCopy := *task
+			return &taskCopy, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %s", ErrTaskNotFound, id)
+}
+
+// RequeueCrashedTask resets a captain task whose agent disconnected while
+// running it. The task goes back to pending, or is escalated once it has been
+// retried MaxTaskRetries times. Returns nil if taskID is not an in-flight
+// captain task.
+func (c *Captain) RequeueCrashedTask(agentID, taskID string) (*memory.CaptainTaskRecord, error) {
+	if c.memDB == nil || taskID == "" {
+		return nil, nil
+	}
+	record, err := c.memDB.RequeueCaptainTask(taskID, MaxTaskRetries)
+	if err != nil || record == nil {
+		return nil, err
+	}
+
+	var queued *CaptainTask
+	c.mu.RLock()
+	for _, task := range c.queuedTasksLocked() {
+		if task.Mission.ID == taskID {
+			queued = task
+			break
+		}
+	}
+	c.mu.RUnlock()
+
+	if record.Status == "escalated" {
+		reason := fmt.Sprintf("Agent %s crashed; task failed %d times", agentID, record.RetryCount)
+		if queued != nil {
+			c.createEscalation(queued, reason)
+			c.setTaskStatus(queued, "escalated")
+		} else {
+			c.createAgentEscalation(agentID, reason, fmt.Sprintf("Task: %s (%s)", record.Title, record.ID))
+		}
+		return record, nil
+	}
+
+	if queued != nil {
+		c.setTaskStatus(queued, "pending")
+	}
+	logger.For("captain").Info("re-queued task after agent crash", "task_id", taskID, "agent_id", agentID, "retry", record.RetryCount, "max_retries", MaxTaskRetries)
+	return record, nil
+}
+
+// TriggerCycle runs an orchestration cycle in the background instead of
+// waiting for the next tick. If a cycle is in progress, the triggered one
+// runs as soon as it finishes.
+func (c *Captain) TriggerCycle() {
+	c.mu.RLock()
+	ctx := c.runCtx
+	c.mu.RUnlock()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	go c.runCycle(ctx)
+}
+
+// startQueuedTask marks a task executing, counts it against its team and
+// runs it in the background
+func (c *Captain) startQueuedTask(ctx context.Context, task *CaptainTask) {
+	c.setTaskStatus(task, "executing")
+	c.trackTeamRunning(task.TeamID, 1)
+	go c.executeQueuedTask(ctx, task)
+}
+
+// executeQueuedTask runs a queued mission and records its final status in
+// the queue and the activity log. Terminal missions end as "spawned",
+// matching recordMissionOutcome. A panic marks the task failed. The caller
+// counts the task against its team, as startQueuedTask does.
+func (c *Captain) executeQueuedTask(ctx context.Context, task *CaptainTask) {
+	defer c.trackTeamRunning(task.TeamID, -1)
+	defer func() {
+		if r := recover(); r != nil {
+			logger.For("captain").Error("queued task panicked", "task_id", task.Mission.ID, "panic", r)
+			c.recordActivity("Captain", "task_panic", fmt.Sprintf("Task %s panicked: %v", task.Mission.ID, r))
+			c.setTaskStatus(task, "failed")
+		}
+	}()
+	missionCtx, cancel := context.WithTimeout(ctx, QueuedMissionTimeout)
+	defer cancel()
+
+	result, err := c.ExecuteMission(missionCtx, task.Mission)
+	if err != nil {
+		logger.For("captain").Warn("queued task failed", "task_id", task.Mission.ID, "error", err)
+		c.recordActivity("Captain", "task_failed", fmt.Sprintf("Task %s failed: %v", task.Mission.ID, err))
+		c.setTaskStatus(task, "failed")
+		return
+	}
+	status := "completed"
+	agentID := "Captain"
+	if result != nil {
+		if result.Status != "" {
+			status = result.Status
+		}
+		if result.AgentID != "" {
+			agentID = result.AgentID
+		}
+	}
+	c.recordActivity(agentID, "task_completed", fmt.Sprintf("Task %s completed: %s", task.Mission.ID, status))
+	c.setTaskStatus(task, status)
+}
+
+// mergeTaskQueue combines the tasks a cycle worked on with the live queue.
+// Tasks removed while the cycle ran are dropped and tasks enqueued while it
+// ran are kept.
+func mergeTaskQueue(cycle, current []*CaptainTask) []*CaptainTask {
+	seen := make(map[*CaptainTask]bool, len(cycle))
+	merged := make([]*CaptainTask, 0, len(cycle)+len(current))
+	for _, task := range cycle {
+		seen[task] = true
+		if task.Status != "cancelled" {
+			merged = append(merged, task)
+		}
+	}
+	for _, task := range current {
+		if !seen[task] {
+			merged = append(merged, task)
+		}
+	}
+	return merged
+}
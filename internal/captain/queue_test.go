@@ -0,0 +1,229 @@
+package captain
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/CLIAIMONITOR/internal/agents"
+	"github.com/CLIAIMONITOR/internal/memory"
+	"github.com/CLIAIMONITOR/internal/types"
+)
+
+// liveTask returns the queued task itself rather than a copy
+func liveTask(t *testing.T, c *Captain, id string) *CaptainTask {
+	t.Helper()
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	for _, task := range c.queuedTasksLocked() {
+		if task.Mission.ID == id {
+			return task
+		}
+	}
+	t.Fatalf("Task %s not queued", id)
+	return nil
+}
+
+func TestRemoveTask(t *testing.T) {
+	c := NewCaptain(".", nil, nil, nil)
+	c.EnqueueMission(Mission{ID: "a", Title: "A"}, false)
+	c.EnqueueMission(Mission{ID: "b", Title: "B"}, true)
+	c.setTaskStatus(liveTask(t, c, "b"), "recon_running")
+
+	task, err := c.RemoveTask("a")
+	if err != nil {
+		t.Fatalf("RemoveTask(a) failed: %v", err)
+	}
+	if task.Status != "cancelled" {
+		t.Errorf("Expected status cancelled, got %s", task.Status)
+	}
+
+	if _, err := c.RemoveTask("b"); !errors.Is(err, ErrTaskNotPending) {
+		t.Errorf("Expected ErrTaskNotPending, got %v", err)
+	}
+	if _, err := c.RemoveTask("a"); !errors.Is(err, ErrTaskNotFound) {
+		t.Errorf("Expected ErrTaskNotFound, got %v", err)
+	}
+	if len(c.GetTaskQueue()) != 1 {
+		t.Errorf("Expected 1 task left, got %d", len(c.GetTaskQueue()))
+	}
+}
+
+func TestMergeTaskQueue(t *testing.T) {
+	kept := &CaptainTask{Mission: Mission{ID: "kept"}, Status: "executing"}
+	removed := &CaptainTask{Mission: Mission{ID: "removed"}, Status: "cancelled"}
+	imported := &CaptainTask{Mission: Mission{ID: "imported"}, Status: "pending"}
+	added := &CaptainTask{Mission: Mission{ID: "added"}, Status: "pending"}
+
+	merged := mergeTaskQueue([]*CaptainTask{kept, removed, imported}, []*CaptainTask{kept, added})
+
+	var ids []string
+	for _, task := range merged {
+		ids = append(ids, task.Mission.ID)
+	}
+	want := []string{"kept", "imported", "added"}
+	if len(ids) != len(want) {
+		t.Fatalf("Expected %v, got %v", want, ids)
+	}
+	for i := range want {
+		if ids[i] != want[i] {
+			t.Errorf("Expected %v, got %v", want, ids)
+			break
+		}
+	}
+}
+
+func TestRequeueCrashedTask(t *testing.T) {
+	db, err := memory.NewMemoryDB(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("Failed to create test database: %v", err)
+	}
+	defer db.Close()
+
+	c := NewCaptain(".", nil, db, nil)
+	c.EnqueueMission(Mission{ID: "crashy", Title: "Crashy"}, false)
+	task := liveTask(t, c, "crashy")
+
+	for attempt := 1; attempt <= MaxTaskRetries; attempt++ {
+		c.recordMission(task.Mission)
+		c.setTaskStatus(task, "executing")
+
+		record, err := c.RequeueCrashedTask("team-sntgreen001", "crashy")
+		if err != nil {
+			t.Fatalf("RequeueCrashedTask failed: %v", err)
+		}
+		if record == nil || record.RetryCount != attempt {
+			t.Fatalf("attempt %d: got %+v", attempt, record)
+		}
+		if attempt < MaxTaskRetries && task.Status != "pending" {
+			t.Errorf("attempt %d: queue status = %s, want pending", attempt, task.Status)
+		}
+	}
+
+	if task.Status != "escalated" {
+		t.Errorf("Expected escalated after %d crashes, got %s", MaxTaskRetries, task.Status)
+	}
+	if escalations := c.GetEscalations(); len(escalations) != 1 || escalations[0].TaskID != "crashy" {
+		t.Errorf("Expected one escalation for crashy, got %+v", escalations)
+	}
+
+	// Tasks Captain does not know about are left alone
+	if record, err := c.RequeueCrashedTask("team-sntgreen001", "unknown"); err != nil || record != nil {
+		t.Errorf("RequeueCrashedTask(unknown) = %+v, %v; want nil, nil", record, err)
+	}
+}
+
+type activityLog struct {
+	mu      sync.Mutex
+	entries []*types.ActivityLog
+}
+
+func (l *activityLog) AddActivity(a *types.ActivityLog) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.entries = append(l.entries, a)
+}
+
+// panicDB panics on any call, since its embedded MemoryDB is nil
+type panicDB struct {
+	memory.MemoryDB
+}
+
+func TestExecuteQueuedTaskRecordsActivity(t *testing.T) {
+	log := &activityLog{}
+	c := NewCaptain(t.TempDir(), nil, nil, nil)
+	c.SetActivityRecorder(log)
+
+	c.EnqueueMission(Mission{ID: "bad", Title: "Plan", TaskType: TaskPlanning}, false)
+	failing := liveTask(t, c, "bad")
+	c.trackTeamRunning(failing.TeamID, 1)
+	c.executeQueuedTask(context.Background(), failing)
+	if failing.Status != "failed" {
+		t.Errorf("Expected failed, got %s", failing.Status)
+	}
+
+	c.memDB = panicDB{}
+	c.EnqueueMission(Mission{ID: "boom", Title: "Fix bug", TaskType: TaskImplementation}, false)
+	panicking := liveTask(t, c, "boom")
+	c.trackTeamRunning(panicking.TeamID, 1)
+	c.executeQueuedTask(context.Background(), panicking)
+	if panicking.Status != "failed" {
+		t.Errorf("Expected failed after panic, got %s", panicking.Status)
+	}
+
+	if len(log.entries) != 2 || log.entries[0].Action != "task_failed" || log.entries[1].Action != "task_panic" {
+		t.Fatalf("Expected task_failed then task_panic, got %+v", log.entries)
+	}
+	if !strings.Contains(log.entries[1].Details, "boom") {
+		t.Errorf("Expected task ID in panic details, got %q", log.entries[1].Details)
+	}
+}
+
+func TestTaskQueueConcurrentAccess(t *testing.T) {
+	c := NewCaptain(t.TempDir(), agents.NewMockSpawner(), nil, nil)
+	for i := 0; i < 10; i++ {
+		// Planning missions without metadata fail as soon as they run
+		c.EnqueueMission(Mission{ID: fmt.Sprintf("task-%d", i), Title: "Plan", TaskType: TaskPlanning}, false)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		c.runCycle(context.Background())
+	}()
+
+	// Read, encode and remove tasks while the cycle and the missions it
+	// started update them; run with -race
+	deadline := time.Now().Add(5 * time.Second)
+	cycleDone := false
+	for i := 0; !cycleDone || c.TeamStatus(DefaultTeamID).Running > 0; i++ {
+		if time.Now().After(deadline) {
+			t.Fatal("Timed out waiting for queued tasks to finish")
+		}
+		for _, task := range c.GetTaskQueue() {
+			if _, err := json.Marshal(task); err != nil {
+				t.Fatalf("Marshal failed: %v", err)
+			}
+		}
+		c.RemoveTask(fmt.Sprintf("task-%d", i%10))
+		select {
+		case <-done:
+			cycleDone = true
+		default:
+		}
+	}
+	for _, task := range c.GetTaskQueue() {
+		if task.Status != "failed" {
+			t.Errorf("Expected %s to have run and failed, got %s", task.Mission.ID, task.Status)
+		}
+	}
+}
+
+func TestGetTaskQueueReturnsCopies(t *testing.T) {
+	c := NewCaptain(".", nil, nil, nil)
+	c.EnqueueMission(Mission{ID: "a", Title: "A"}, false)
+	snapshot := c.GetTaskQueue()[0]
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		c.setTaskStatus(liveTask(t, c, "a"), "executing")
+	}()
+	if _, err := json.Marshal(snapshot); err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	<-done
+
+	if snapshot.Status != "pending" {
+		t.Errorf("A later transition changed a GetTaskQueue result to %s", snapshot.Status)
+	}
+	if got := c.GetTaskQueue()[0].Status; got != "executing" {
+		t.Errorf("Expected the queued task to be executing, got %s", got)
+	}
+}
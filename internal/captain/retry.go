@@ -0,0 +1,119 @@
+package captain
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/CLIAIMONITOR/internal/logger"
+	"github.com/CLIAIMONITOR/internal/memory"
+)
+
+// RetryPolicy controls how often a failed subagent mission is re-run.
+// A zero policy runs the mission once.
+type RetryPolicy struct {
+	MaxAttempts     int           `json:"max_attempts,omitempty"`     // Total attempts, default 1
+	RetryDelay      time.Duration `json:"retry_delay,omitempty"`      // Wait between attempts
+	RetryableErrors []string      `json:"retryable_errors,omitempty"` // Substrings of errors worth retrying; empty retries any failure
+}
+
+// attempts returns MaxAttempts, or 1 if unset
+func (p RetryPolicy) attempts() int {
+	if p.MaxAttempts < 1 {
+		return 1
+	}
+	return p.MaxAttempts
+}
+
+// retryable reports whether a failure message matches RetryableErrors
+func (p RetryPolicy) retryable(message string) bool {
+	if len(p.RetryableErrors) == 0 {
+		return true
+	}
+	message = strings.ToLower(message)
+	for _, pattern := range p.RetryableErrors {
+		if pattern != "" && strings.Contains(message, strings.ToLower(pattern)) {
+			return true
+		}
+	}
+	return false
+}
+
+// attemptFailure returns why an attempt failed, or "" if it succeeded. A
+// result with status "failed" counts as a failure even without an error.
+func attemptFailure(result *SubagentResult, err error) string {
+	if err != nil {
+		return err.Error()
+	}
+	if result != nil && result.Status == "failed" {
+		if result.Error != "" {
+			return result.Error
+		}
+		return "subagent failed"
+	}
+	return ""
+}
+
+// executeWithRetry calls fn until it succeeds, the policy's attempts are
+// used up, a failure does not match RetryableErrors, or ctx is done. The
+// last attempt's result and error are returned.
+func executeWithRetry(ctx context.Context, fn func() (*SubagentResult, error), policy RetryPolicy) (*SubagentResult, error) {
+	maxAttempts := policy.attempts()
+	for attempt := 1; ; attempt++ {
+		result, err := fn()
+		failure := attemptFailure(result, err)
+		if failure == "" || attempt >= maxAttempts || !policy.retryable(failure) {
+			return result, err
+		}
+
+		logger.For("captain").Info("mission attempt failed, retrying", "attempt", attempt, "max_attempts", maxAttempts, "failure", failure, "retry_in", policy.RetryDelay)
+		select {
+		case <-time.After(policy.RetryDelay):
+		case <-ctx.Done():
+			return result, err
+		}
+	}
+}
+
+// Phases an attempt in mission_attempts can belong to
+const (
+	AttemptPhaseRecon   = "recon"
+	AttemptPhaseExecute = "execute"
+)
+
+// attemptOwner returns the task a mission's attempts are recorded under and
+// their phase. Recon missions run for a parent task, so their attempts are
+// listed with that task's own.
+func attemptOwner(mission Mission) (taskID, phase string) {
+	if mission.TaskType != TaskRecon {
+		return mission.ID, AttemptPhaseExecute
+	}
+	if parent := mission.Metadata["parent_task"]; parent != "" {
+		return parent, AttemptPhaseRecon
+	}
+	return mission.ID, AttemptPhaseRecon
+}
+
+// recordMissionAttempt stores one attempt in the mission_attempts table
+func (c *Captain) recordMissionAttempt(mission Mission, attempt int, startedAt time.Time, result *SubagentResult, err error) {
+	if c.memDB == nil {
+		return
+	}
+	taskID, phase := attemptOwner(mission)
+	endedAt := time.Now()
+	record := &memory.MissionAttempt{
+		MissionID: taskID,
+		Phase:     phase,
+		Attempt:   attempt,
+		StartedAt: startedAt,
+		EndedAt:   &endedAt,
+		Status:    "completed",
+	}
+	if failure := attemptFailure(result, err); failure != "" {
+		record.Status = "failed"
+		record.Error = failure
+	}
+	if recordErr := c.memDB.RecordMissionAttempt(record); recordErr != nil {
+		logger.For("captain").Warn("failed to record mission attempt", "task_id", taskID, "phase", phase, "attempt", attempt, "error", recordErr)
+	}
+}
@@ -0,0 +1,155 @@
+package captain
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/CLIAIMONITOR/internal/memory"
+)
+
+func TestExecuteWithRetry(t *testing.T) {
+	ctx := context.Background()
+	failed := &SubagentResult{Status: "failed", Error: "exit status 1: rate limit exceeded"}
+	completed := &SubagentResult{Status: "completed"}
+
+	// Default policy runs once
+	calls := 0
+	result, err := executeWithRetry(ctx, func() (*SubagentResult, error) {
+		calls++
+		return failed, nil
+	}, RetryPolicy{})
+	if calls != 1 || result != failed || err != nil {
+		t.Errorf("Expected one failed attempt, got %d calls, %v, %v", calls, result, err)
+	}
+
+	// Retries until success
+	calls = 0
+	result, err = executeWithRetry(ctx, func() (*SubagentResult, error) {
+		calls++
+		if calls < 3 {
+			return failed, nil
+		}
+		return completed, nil
+	}, RetryPolicy{MaxAttempts: 5, RetryDelay: time.Millisecond})
+	if calls != 3 || result != completed || err != nil {
+		t.Errorf("Expected success on third attempt, got %d calls, %v, %v", calls, result, err)
+	}
+
+	// Stops at MaxAttempts and returns the last error
+	calls = 0
+	_, err = executeWithRetry(ctx, func() (*SubagentResult, error) {
+		calls++
+		return nil, errors.New("connection reset")
+	}, RetryPolicy{MaxAttempts: 2})
+	if calls != 2 || err == nil {
+		t.Errorf("Expected 2 attempts ending in error, got %d calls, %v", calls, err)
+	}
+
+	// Only matching errors are retried
+	calls = 0
+	executeWithRetry(ctx, func() (*SubagentResult, error) {
+		calls++
+		return &SubagentResult{Status: "failed", Error: "invalid prompt"}, nil
+	}, RetryPolicy{MaxAttempts: 3, RetryableErrors: []string{"Rate Limit", "timeout"}})
+	if calls != 1 {
+		t.Errorf("Expected non-retryable failure to stop after 1 attempt, got %d", calls)
+	}
+	calls = 0
+	executeWithRetry(ctx, func() (*SubagentResult, error) {
+		calls++
+		return failed, nil
+	}, RetryPolicy{MaxAttempts: 3, RetryableErrors: []string{"Rate Limit", "timeout"}})
+	if calls != 3 {
+		t.Errorf("Expected retryable failure to use all 3 attempts, got %d", calls)
+	}
+
+	// Cancellation stops the wait between attempts
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	calls = 0
+	executeWithRetry(cancelled, func() (*SubagentResult, error) {
+		calls++
+		return failed, nil
+	}, RetryPolicy{MaxAttempts: 3, RetryDelay: time.Hour})
+	if calls != 1 {
+		t.Errorf("Expected cancelled context to stop retries, got %d calls", calls)
+	}
+}
+
+func TestRecordMissionAttempt(t *testing.T) {
+	db, err := memory.NewMemoryDB(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("Failed to create test database: %v", err)
+	}
+	defer db.Close()
+	c := NewCaptain(".", nil, db, nil)
+
+	started := time.Now()
+	task := Mission{ID: "MAH-7", TaskType: TaskImplementation}
+	c.recordMissionAttempt(task, 1, started, &SubagentResult{Status: "failed", Error: "exit status 2"}, nil)
+	c.recordMissionAttempt(task, 2, started, &SubagentResult{Status: "completed"}, nil)
+	c.recordMissionAttempt(Mission{ID: "MAH-8"}, 1, started, nil, errors.New("failed to write prompt file"))
+
+	attempts, err := db.GetMissionAttempts("MAH-7")
+	if err != nil {
+		t.Fatalf("GetMissionAttempts failed: %v", err)
+	}
+	if len(attempts) != 2 {
+		t.Fatalf("Expected 2 attempts, got %d", len(attempts))
+	}
+	if attempts[0].Attempt != 1 || attempts[0].Phase != AttemptPhaseExecute || attempts[0].Status != "failed" || attempts[0].Error != "exit status 2" {
+		t.Errorf("Unexpected first attempt: %+v", attempts[0])
+	}
+	if attempts[1].Attempt != 2 || attempts[1].Status != "completed" || attempts[1].Error != "" || attempts[1].EndedAt == nil {
+		t.Errorf("Unexpected second attempt: %+v", attempts[1])
+	}
+
+	other, _ := db.GetMissionAttempts("MAH-8")
+	if len(other) != 1 || other[0].Error != "failed to write prompt file" {
+		t.Errorf("Unexpected attempts for MAH-8: %+v", other)
+	}
+	none, _ := db.GetMissionAttempts("MAH-9")
+	if none == nil || len(none) != 0 {
+		t.Errorf("Expected empty attempts, got %v", none)
+	}
+}
+
+func TestRecordMissionAttemptRecon(t *testing.T) {
+	db, err := memory.NewMemoryDB(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("Failed to create test database: %v", err)
+	}
+	defer db.Close()
+	c := NewCaptain(".", nil, db, nil)
+
+	// Recon runs for its parent task, so its attempts are listed with the
+	// task's execution attempts
+	recon := Mission{ID: "recon-MAH-7", TaskType: TaskRecon, Metadata: map[string]string{"parent_task": "MAH-7"}}
+	started := time.Now()
+	c.recordMissionAttempt(recon, 1, started, &SubagentResult{Status: "failed", Error: "timeout"}, nil)
+	c.recordMissionAttempt(recon, 2, started, &SubagentResult{Status: "completed"}, nil)
+	c.recordMissionAttempt(Mission{ID: "MAH-7", TaskType: TaskImplementation}, 1, started, &SubagentResult{Status: "completed"}, nil)
+
+	attempts, err := db.GetMissionAttempts("MAH-7")
+	if err != nil {
+		t.Fatalf("GetMissionAttempts failed: %v", err)
+	}
+	want := []struct {
+		phase   string
+		attempt int
+	}{{AttemptPhaseRecon, 1}, {AttemptPhaseRecon, 2}, {AttemptPhaseExecute, 1}}
+	if len(attempts) != len(want) {
+		t.Fatalf("Expected %d attempts, got %+v", len(want), attempts)
+	}
+	for i, w := range want {
+		if attempts[i].Phase != w.phase || attempts[i].Attempt != w.attempt || attempts[i].MissionID != "MAH-7" {
+			t.Errorf("Attempt %d = %+v, want %s #%d", i, attempts[i], w.phase, w.attempt)
+		}
+	}
+	if none, _ := db.GetMissionAttempts("recon-MAH-7"); len(none) != 0 {
+		t.Errorf("Expected no attempts under the recon mission ID, got %+v", none)
+	}
+}
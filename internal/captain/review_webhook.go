@@ -0,0 +1,155 @@
+package captain
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/CLIAIMONITOR/internal/logger"
+	"github.com/CLIAIMONITOR/internal/memory"
+)
+
+// Environment variables used to configure review completion webhooks
+const (
+	EnvReviewWebhookURL    = "CLIAIMONITOR_REVIEW_WEBHOOK_URL"
+	EnvReviewWebhookSecret = "CLIAIMONITOR_REVIEW_WEBHOOK_SECRET"
+)
+
+// ReviewWebhookMaxAttempts is how many times a queued review webhook is
+// tried before it is left in the outbox for manual inspection
+const ReviewWebhookMaxAttempts = 10
+
+// reviewWebhookRetryBatch limits how many queued webhooks one retry pass sends
+const reviewWebhookRetryBatch = 20
+
+// ReviewWebhookPayload is the JSON body sent when a review board completes.
+// It is signed with the same X-Signature scheme as task webhooks.
+type ReviewWebhookPayload struct {
+	BoardID      int64     `json:"board_id"`
+	AssignmentID int64     `json:"assignment_id"`
+	FinalVerdict string    `json:"final_verdict"`
+	DefectCount  int       `json:"defect_count"`
+	Approved     bool      `json:"approved"`
+	CompletedAt  time.Time `json:"completed_at"`
+}
+
+// NewReviewWebhookPayload builds the payload for a completed board
+func NewReviewWebhookPayload(board *memory.ReviewBoard, defectCount int) ReviewWebhookPayload {
+	completedAt := time.Now().UTC()
+	if board.CompletedAt != nil {
+		completedAt = board.CompletedAt.UTC()
+	}
+	return ReviewWebhookPayload{
+		BoardID:      board.ID,
+		AssignmentID: board.AssignmentID,
+		FinalVerdict: board.FinalVerdict,
+		DefectCount:  defectCount,
+		Approved:     board.FinalVerdict == "approved",
+		CompletedAt:  completedAt,
+	}
+}
+
+// ReviewWebhookDispatcher POSTs signed review completions to an external URL,
+// queueing failed deliveries in the review webhook outbox
+type ReviewWebhookDispatcher struct {
+	url    string
+	secret string
+	client *http.Client
+	outbox memory.MemoryDB
+}
+
+// NewReviewWebhookDispatcher creates a dispatcher for the given URL and
+// secret. outbox may be nil, in which case failed deliveries are dropped.
+func NewReviewWebhookDispatcher(url, secret string, outbox memory.MemoryDB) *ReviewWebhookDispatcher {
+	return &ReviewWebhookDispatcher{
+		url:    url,
+		secret: secret,
+		client: &http.Client{
+			Timeout: 10 * time.Second,
+		},
+		outbox: outbox,
+	}
+}
+
+// NewReviewWebhookDispatcherFromEnv creates a dispatcher from
+// CLIAIMONITOR_REVIEW_WEBHOOK_* environment variables. Returns nil if no
+// webhook URL is configured.
+func NewReviewWebhookDispatcherFromEnv(outbox memory.MemoryDB) *ReviewWebhookDispatcher {
+	url := os.Getenv(EnvReviewWebhookURL)
+	if url == "" {
+		return nil
+	}
+	return NewReviewWebhookDispatcher(url, os.Getenv(EnvReviewWebhookSecret), outbox)
+}
+
+// Enabled reports whether the dispatcher has both a URL and a signing secret.
+// Unsigned payloads are never sent.
+func (d *ReviewWebhookDispatcher) Enabled() bool {
+	return d != nil && d.url != "" && d.secret != ""
+}
+
+// Send POSTs a signed payload and waits for the response. A failed delivery
+// is queued in the outbox.
+func (d *ReviewWebhookDispatcher) Send(payload ReviewWebhookPayload) error {
+	if !d.Enabled() {
+		return fmt.Errorf("review webhook not configured (URL and secret required)")
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal webhook payload: %w", err)
+	}
+
+	sendErr := postSignedWebhook(d.client, d.url, d.secret, body)
+	if sendErr != nil && d.outbox != nil {
+		if err := d.outbox.EnqueueReviewWebhook(payload.BoardID, body, sendErr.Error()); err != nil {
+			logger.For("captain").Warn("failed to queue review webhook", "board_id", payload.BoardID, "error", err)
+		}
+	}
+	return sendErr
+}
+
+// DispatchCompletion sends a review completion in the background so the
+// review board update is never blocked by a slow endpoint
+func (d *ReviewWebhookDispatcher) DispatchCompletion(payload ReviewWebhookPayload) {
+	if !d.Enabled() {
+		return
+	}
+
+	go func() {
+		if err := d.Send(payload); err != nil {
+			logger.For("captain").Warn("review webhook failed", "board_id", payload.BoardID, "error", err)
+		}
+	}()
+}
+
+// RetryPending resends queued review webhooks and returns how many were
+// delivered. The stored body is re-signed, so receivers see the original
+// payload.
+func (d *ReviewWebhookDispatcher) RetryPending() (int, error) {
+	if !d.Enabled() || d.outbox == nil {
+		return 0, nil
+	}
+
+	pending, err := d.outbox.GetPendingReviewWebhooks(ReviewWebhookMaxAttempts, reviewWebhookRetryBatch)
+	if err != nil {
+		return 0, err
+	}
+
+	delivered := 0
+	for _, entry := range pending {
+		if err := postSignedWebhook(d.client, d.url, d.secret, []byte(entry.Payload)); err != nil {
+			if err := d.outbox.RecordReviewWebhookAttempt(entry.ID, err.Error()); err != nil {
+				return delivered, err
+			}
+			continue
+		}
+		if err := d.outbox.MarkReviewWebhookDelivered(entry.ID); err != nil {
+			return delivered, err
+		}
+		delivered++
+	}
+	return delivered, nil
+}
@@ -0,0 +1,92 @@
+package captain
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/CLIAIMONITOR/internal/memory"
+)
+
+func TestReviewWebhookDispatcher_Send(t *testing.T) {
+	var gotSig string
+	var gotBody []byte
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotSig = r.Header.Get(TaskWebhookSignatureHeader)
+		gotBody, _ = io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	completed := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	board := &memory.ReviewBoard{ID: 7, AssignmentID: 3, FinalVerdict: "approved", CompletedAt: &completed}
+
+	d := NewReviewWebhookDispatcher(srv.URL, "s3cret", nil)
+	if err := d.Send(NewReviewWebhookPayload(board, 2)); err != nil {
+		t.Fatalf("Send failed: %v", err)
+	}
+
+	var got map[string]interface{}
+	if err := json.Unmarshal(gotBody, &got); err != nil {
+		t.Fatalf("invalid payload: %v", err)
+	}
+	if got["board_id"] != float64(7) || got["assignment_id"] != float64(3) || got["final_verdict"] != "approved" ||
+		got["defect_count"] != float64(2) || got["approved"] != true || got["completed_at"] != "2026-01-02T03:04:05Z" {
+		t.Errorf("unexpected payload: %s", gotBody)
+	}
+	if !VerifyWebhookSignature("s3cret", gotBody, gotSig) {
+		t.Errorf("signature %q did not verify", gotSig)
+	}
+}
+
+func TestReviewWebhookDispatcher_OutboxRetry(t *testing.T) {
+	db, err := memory.NewMemoryDB(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("Failed to create test database: %v", err)
+	}
+	defer db.Close()
+
+	var up atomic.Bool
+	var lastBody []byte
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !up.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		lastBody, _ = io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	d := NewReviewWebhookDispatcher(srv.URL, "s3cret", db)
+	payload := NewReviewWebhookPayload(&memory.ReviewBoard{ID: 9, FinalVerdict: "rejected"}, 4)
+	if err := d.Send(payload); err == nil {
+		t.Fatal("expected delivery to fail while the endpoint is down")
+	}
+
+	pending, err := db.GetPendingReviewWebhooks(ReviewWebhookMaxAttempts, 10)
+	if err != nil || len(pending) != 1 || pending[0].BoardID != 9 {
+		t.Fatalf("expected queued webhook, got %+v (%v)", pending, err)
+	}
+
+	if n, err := d.RetryPending(); err != nil || n != 0 {
+		t.Errorf("expected no deliveries while down, got %d (%v)", n, err)
+	}
+
+	up.Store(true)
+	if n, err := d.RetryPending(); err != nil || n != 1 {
+		t.Fatalf("expected 1 delivery, got %d (%v)", n, err)
+	}
+	if string(lastBody) != pending[0].Payload {
+		t.Errorf("retry sent %s, want original %s", lastBody, pending[0].Payload)
+	}
+	if pending, _ := db.GetPendingReviewWebhooks(ReviewWebhookMaxAttempts, 10); len(pending) != 0 {
+		t.Errorf("expected empty outbox, got %+v", pending)
+	}
+}
@@ -0,0 +1,220 @@
+package captain
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/CLIAIMONITOR/internal/logger"
+	"github.com/CLIAIMONITOR/internal/memory"
+)
+
+// ScanPolicyContextKey is the captain_context key holding the scan policy as
+// JSON, so it can be changed at runtime through the context API
+const ScanPolicyContextKey = "scan_policy"
+
+// DefaultScanIntervalHours is how often environments are rescanned when no
+// policy has been stored
+const DefaultScanIntervalHours = 24
+
+// ScanFailureBackoff is how long Captain waits before retrying an
+// environment whose scheduled scan failed. It doubles with each consecutive
+// failure, up to the environment's scan interval.
+const ScanFailureBackoff = 15 * time.Minute
+
+// scanFailure tracks consecutive failed scheduled scans of an environment
+type scanFailure struct {
+	count int
+	at    time.Time
+}
+
+// Metadata keys marking a mission as a scheduled environment scan
+const (
+	metaScheduledScan = "scheduled_scan"
+	metaEnvID         = "env_id"
+)
+
+// ScheduledScanPolicy decides how often Captain rescans each environment
+// for drift. Intervals are in hours; zero disables scheduled scans.
+type ScheduledScanPolicy struct {
+	ScanIntervalHours int            `json:"scan_interval_hours"`    // default for every environment
+	Environments      map[string]int `json:"environments,omitempty"` // per-environment overrides by ID
+}
+
+// DefaultScanPolicy returns the daily scan policy
+func DefaultScanPolicy() ScheduledScanPolicy {
+	return ScheduledScanPolicy{ScanIntervalHours: DefaultScanIntervalHours}
+}
+
+// IntervalFor returns the scan interval for an environment, or 0 if its
+// scans are disabled
+func (p ScheduledScanPolicy) IntervalFor(envID string) time.Duration {
+	hours := p.ScanIntervalHours
+	if override, ok := p.Environments[envID]; ok {
+		hours = override
+	}
+	if hours <= 0 {
+		return 0
+	}
+	return time.Duration(hours) * time.Hour
+}
+
+// ScanPolicy loads the stored scan policy, falling back to the default when
+// none is stored or it cannot be parsed
+func (c *Captain) ScanPolicy() ScheduledScanPolicy {
+	if c.memDB == nil {
+		return DefaultScanPolicy()
+	}
+	stored, err := c.memDB.GetContext(ScanPolicyContextKey)
+	if err != nil || stored == nil {
+		return DefaultScanPolicy()
+	}
+	var policy ScheduledScanPolicy
+	if err := json.Unmarshal([]byte(stored.Value), &policy); err != nil {
+		logger.For("captain").Warn("ignoring invalid scan policy context", "key", ScanPolicyContextKey, "error", err)
+		return DefaultScanPolicy()
+	}
+	return policy
+}
+
+// SetScanPolicy stores the scan policy; it applies from the next cycle
+func (c *Captain) SetScanPolicy(policy ScheduledScanPolicy) error {
+	if c.memDB == nil {
+		return fmt.Errorf("memory database not configured")
+	}
+	data, err := json.Marshal(policy)
+	if err != nil {
+		return fmt.Errorf("failed to encode scan policy: %w", err)
+	}
+	return c.memDB.SetContext(ScanPolicyContextKey, string(data), 5, 0)
+}
+
+// queueScheduledScans queues a recon mission for every environment whose
+// last scan is older than its policy interval and returns how many were
+// queued. Environments without a base path cannot be scanned and are skipped.
+func (c *Captain) queueScheduledScans(ctx context.Context, now time.Time) int {
+	reconRepo, ok := c.memDB.(memory.ReconRepository)
+	if !ok {
+		return 0
+	}
+	envs, err := reconRepo.ListEnvironments(ctx)
+	if err != nil {
+		logger.For("captain").Warn("failed to list environments for scheduled scans", "error", err)
+		return 0
+	}
+
+	policy := c.ScanPolicy()
+	queued := 0
+	for _, env := range envs {
+		interval := policy.IntervalFor(env.ID)
+		if env.BasePath == "" || !scanDue(env, interval, now) {
+			continue
+		}
+		if c.scanBackingOff(env.ID, interval, now) || c.scanQueued(env.ID) {
+			continue
+		}
+		c.EnqueueMission(scheduledScanMission(env, now), true)
+		queued++
+	}
+	return queued
+}
+
+// scanDue reports whether env was never scanned or was last scanned at least
+// interval ago. A zero interval means scans are disabled.
+func scanDue(env *memory.Environment, interval time.Duration, now time.Time) bool {
+	if interval <= 0 {
+		return false
+	}
+	return env.LastScanned == nil || now.Sub(*env.LastScanned) >= interval
+}
+
+// scanBackingOff reports whether envID's last scheduled scan failed too
+// recently to retry: within ScanFailureBackoff, doubled for each further
+// consecutive failure and capped at interval
+func (c *Captain) scanBackingOff(envID string, interval time.Duration, now time.Time) bool {
+	c.mu.RLock()
+	failure, ok := c.scanFailures[envID]
+	c.mu.RUnlock()
+	if !ok {
+		return false
+	}
+
+	backoff := ScanFailureBackoff
+	for i := 1; i < failure.count && backoff < interval; i++ {
+		backoff *= 2
+	}
+	if backoff > interval {
+		backoff = interval
+	}
+	return now.Sub(failure.at) < backoff
+}
+
+// recordScanFailure notes a failed scheduled scan so queueScheduledScans
+// backs off instead of requeueing the environment every cycle
+func (c *Captain) recordScanFailure(mission Mission, now time.Time) {
+	envID := mission.Metadata[metaEnvID]
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.scanFailures == nil {
+		c.scanFailures = make(map[string]scanFailure)
+	}
+	failure := c.scanFailures[envID]
+	c.scanFailures[envID] = scanFailure{count: failure.count + 1, at: now}
+}
+
+// scanQueued reports whether a scheduled scan for envID is still waiting for
+// or running recon, so the next cycle does not queue it again
+func (c *Captain) scanQueued(envID string) bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	for _, task := range c.queuedTasksLocked() {
+		if !isScheduledScan(task.Mission) || task.Mission.Metadata[metaEnvID] != envID {
+			continue
+		}
+		if task.Status == "pending" || task.Status == "recon_running" {
+			return true
+		}
+	}
+	return false
+}
+
+// scheduledScanMission builds the recon mission for one environment
+func scheduledScanMission(env *memory.Environment, now time.Time) Mission {
+	return Mission{
+		ID:          fmt.Sprintf("scan-%s-%d", env.ID, now.Unix()),
+		Title:       fmt.Sprintf("Scheduled scan: %s", env.Name),
+		Description: fmt.Sprintf("Scheduled drift scan of environment %s at %s", env.ID, env.BasePath),
+		TaskType:    TaskRecon,
+		ProjectPath: env.BasePath,
+		Priority:    5,
+		Metadata: map[string]string{
+			metaScheduledScan: "true",
+			metaEnvID:         env.ID,
+		},
+		SourceType: SourceInternal,
+		SourceRef:  env.ID,
+	}
+}
+
+// isScheduledScan reports whether a mission was queued by queueScheduledScans
+func isScheduledScan(mission Mission) bool {
+	return mission.Metadata[metaScheduledScan] == "true"
+}
+
+// markEnvironmentScanned records a scheduled scan against its environment so
+// the next one waits a full interval, and clears any failure backoff
+func (c *Captain) markEnvironmentScanned(ctx context.Context, mission Mission) {
+	c.mu.Lock()
+	delete(c.scanFailures, mission.Metadata[metaEnvID])
+	c.mu.Unlock()
+
+	reconRepo, ok := c.memDB.(memory.ReconRepository)
+	if !ok {
+		return
+	}
+	if err := reconRepo.UpdateEnvironmentLastScan(ctx, mission.Metadata[metaEnvID]); err != nil {
+		logger.For("captain").Warn("failed to update environment last scan", "environment_id", mission.Metadata[metaEnvID], "error", err)
+	}
+}
@@ -0,0 +1,159 @@
+package captain
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/CLIAIMONITOR/internal/memory"
+)
+
+func TestScheduledScanPolicy_IntervalFor(t *testing.T) {
+	p := ScheduledScanPolicy{
+		ScanIntervalHours: 24,
+		Environments:      map[string]int{"prod": 6, "archive": 0},
+	}
+
+	tests := []struct {
+		envID string
+		want  time.Duration
+	}{
+		{"dev", 24 * time.Hour},
+		{"prod", 6 * time.Hour},
+		{"archive", 0},
+	}
+	for _, tt := range tests {
+		if got := p.IntervalFor(tt.envID); got != tt.want {
+			t.Errorf("IntervalFor(%q) = %v, want %v", tt.envID, got, tt.want)
+		}
+	}
+}
+
+func TestScanDue(t *testing.T) {
+	now := time.Now()
+	recent := now.Add(-time.Hour)
+	old := now.Add(-48 * time.Hour)
+
+	tests := []struct {
+		name     string
+		last     *time.Time
+		interval time.Duration
+		want     bool
+	}{
+		{"never scanned", nil, 24 * time.Hour, true},
+		{"scanned recently", &recent, 24 * time.Hour, false},
+		{"scan overdue", &old, 24 * time.Hour, true},
+		{"scans disabled", nil, 0, false},
+	}
+	for _, tt := range tests {
+		env := &memory.Environment{ID: "env", LastScanned: tt.last}
+		if got := scanDue(env, tt.interval, now); got != tt.want {
+			t.Errorf("%s: scanDue = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestQueueScheduledScans(t *testing.T) {
+	db, err := memory.NewMemoryDB(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("Failed to create test database: %v", err)
+	}
+	defer db.Close()
+	reconRepo := db.(memory.ReconRepository)
+
+	ctx := context.Background()
+	for _, env := range []*memory.Environment{
+		{ID: "stale", Name: "stale", EnvType: "internal", BasePath: "/repos/stale"},
+		{ID: "fresh", Name: "fresh", EnvType: "internal", BasePath: "/repos/fresh"},
+		{ID: "remote", Name: "remote", EnvType: "customer"},
+	} {
+		if err := reconRepo.RegisterEnvironment(ctx, env); err != nil {
+			t.Fatalf("RegisterEnvironment(%s) failed: %v", env.ID, err)
+		}
+	}
+	if err := reconRepo.UpdateEnvironmentLastScan(ctx, "fresh"); err != nil {
+		t.Fatalf("UpdateEnvironmentLastScan failed: %v", err)
+	}
+
+	c := NewCaptain(".", nil, db, nil)
+	if got := c.queueScheduledScans(ctx, time.Now()); got != 1 {
+		t.Fatalf("Expected 1 scan queued, got %d", got)
+	}
+	queue := c.GetTaskQueue()
+	if len(queue) != 1 || queue[0].Mission.Metadata[metaEnvID] != "stale" || !queue[0].NeedsRecon {
+		t.Fatalf("Expected one recon task for stale, got %+v", queue)
+	}
+	if queue[0].Mission.TaskType != TaskRecon || queue[0].Mission.ProjectPath != "/repos/stale" {
+		t.Errorf("Unexpected scan mission: %+v", queue[0].Mission)
+	}
+
+	// The pending scan is not queued again
+	if got := c.queueScheduledScans(ctx, time.Now()); got != 0 {
+		t.Errorf("Expected no duplicate scan, got %d queued", got)
+	}
+
+	// Disabling the environment through the stored policy stops new scans
+	if err := c.SetScanPolicy(ScheduledScanPolicy{ScanIntervalHours: 24, Environments: map[string]int{"stale": 0}}); err != nil {
+		t.Fatalf("SetScanPolicy failed: %v", err)
+	}
+	if p := c.ScanPolicy(); p.IntervalFor("stale") != 0 {
+		t.Errorf("Expected stored policy to disable stale, got %+v", p)
+	}
+	if _, err := c.RemoveTask(queue[0].Mission.ID); err != nil {
+		t.Fatalf("RemoveTask failed: %v", err)
+	}
+	if got := c.queueScheduledScans(ctx, time.Now()); got != 0 {
+		t.Errorf("Expected disabled environment to be skipped, got %d queued", got)
+	}
+}
+
+func TestScheduledScanFailureBackoff(t *testing.T) {
+	db, err := memory.NewMemoryDB(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("Failed to create test database: %v", err)
+	}
+	defer db.Close()
+
+	ctx := context.Background()
+	env := &memory.Environment{ID: "flaky", Name: "flaky", EnvType: "internal", BasePath: "/repos/flaky"}
+	if err := db.(memory.ReconRepository).RegisterEnvironment(ctx, env); err != nil {
+		t.Fatalf("RegisterEnvironment failed: %v", err)
+	}
+
+	c := NewCaptain(".", nil, db, nil)
+	now := time.Now()
+	if got := c.queueScheduledScans(ctx, now); got != 1 {
+		t.Fatalf("Expected the first scan to be queued, got %d", got)
+	}
+	task := liveTask(t, c, c.GetTaskQueue()[0].Mission.ID)
+
+	// A failed scan is not requeued on the next cycle
+	c.recordScanFailure(task.Mission, now)
+	c.setTaskStatus(task, "failed")
+	if got := c.queueScheduledScans(ctx, now.Add(30*time.Second)); got != 0 {
+		t.Fatalf("Expected failed scan to back off, got %d queued", got)
+	}
+	if got := c.queueScheduledScans(ctx, now.Add(ScanFailureBackoff)); got != 1 {
+		t.Fatalf("Expected retry after %s, got %d queued", ScanFailureBackoff, got)
+	}
+
+	// Each further failure doubles the wait
+	retry := now.Add(ScanFailureBackoff)
+	c.recordScanFailure(task.Mission, retry)
+	if !c.scanBackingOff("flaky", 24*time.Hour, retry.Add(ScanFailureBackoff)) {
+		t.Error("Expected a second failure to back off for twice as long")
+	}
+	if c.scanBackingOff("flaky", 24*time.Hour, retry.Add(2*ScanFailureBackoff)) {
+		t.Error("Expected the doubled backoff to have expired")
+	}
+	if !c.scanBackingOff("flaky", 20*time.Minute, retry.Add(19*time.Minute)) || c.scanBackingOff("flaky", 20*time.Minute, retry.Add(20*time.Minute)) {
+		t.Error("Expected the backoff to be capped at the scan interval")
+	}
+
+	// A successful scan clears the backoff
+	c.markEnvironmentScanned(ctx, task.Mission)
+	if c.scanBackingOff("flaky", 24*time.Hour, retry) {
+		t.Error("Expected a successful scan to clear the backoff")
+	}
+}
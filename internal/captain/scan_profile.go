@@ -0,0 +1,165 @@
+package captain
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/CLIAIMONITOR/internal/memory"
+)
+
+// Environment types a scan profile can be keyed by (memory.Environment.EnvType)
+const (
+	EnvTypeInternal = "internal"
+	EnvTypeCustomer = "customer"
+	EnvTypeTest     = "test"
+)
+
+// metaEnvType carries the scanned environment's type on recon missions
+const metaEnvType = "env_type"
+
+// readOnlyTools are the Claude CLI tools that cannot change the scanned tree
+var readOnlyTools = []string{"Read", "Grep", "Glob", "LS"}
+
+// ScanProfile tailors Snake recon to the kind of environment being scanned:
+// the prompt instructions, which Claude CLI tools the subagent may use and
+// the report format it is asked for
+type ScanProfile struct {
+	EnvType         string   `json:"env_type"`
+	Description     string   `json:"description"`
+	Instructions    []string `json:"instructions"`
+	AllowedTools    []string `json:"allowed_tools,omitempty"`    // Passed as --allowedTools; empty allows all
+	DisallowedTools []string `json:"disallowed_tools,omitempty"` // Passed as --disallowedTools
+	OutputFormat    string   `json:"output_format"`              // yaml or json
+}
+
+// scanProfiles are the built-in profiles keyed by environment type
+var scanProfiles = map[string]ScanProfile{
+	EnvTypeInternal: {
+		EnvType:     EnvTypeInternal,
+		Description: "Our own repositories: full scan including build and test tooling",
+		Instructions: []string{
+			"Observe only - do not modify files",
+			"Prioritize by severity (critical > high > medium > low)",
+			"Include file:line references where applicable",
+			"You may run read-only commands (git log, go vet, linters) to gather evidence",
+		},
+		OutputFormat: "yaml",
+	},
+	EnvTypeCustomer: {
+		EnvType:     EnvTypeCustomer,
+		Description: "Customer code: read-only tools, no command execution",
+		Instructions: []string{
+			"This is customer code: read files only, never run commands or modify anything",
+			"Do not copy secrets, credentials or personal data into findings",
+			"Prioritize by severity (critical > high > medium > low)",
+			"Include file:line references where applicable",
+		},
+		AllowedTools:    readOnlyTools,
+		DisallowedTools: []string{"Bash", "Edit", "MultiEdit", "Write", "NotebookEdit", "WebFetch"},
+		OutputFormat:    "yaml",
+	},
+	EnvTypeTest: {
+		EnvType:     EnvTypeTest,
+		Description: "Test environments: quick scan focused on test health",
+		Instructions: []string{
+			"Observe only - do not modify files",
+			"Focus on test coverage gaps, flaky tests and broken fixtures",
+			"Keep the report short: only high and critical findings need detail",
+		},
+		OutputFormat: "json",
+	},
+}
+
+// ScanProfiles returns the configured scan profiles ordered by environment type
+func ScanProfiles() []ScanProfile {
+	profiles := make([]ScanProfile, 0, len(scanProfiles))
+	for _, profile := range scanProfiles {
+		profiles = append(profiles, profile)
+	}
+	sort.Slice(profiles, func(i, j int) bool { return profiles[i].EnvType < profiles[j].EnvType })
+	return profiles
+}
+
+// ScanProfileFor returns the profile for an environment type, falling back to
+// the internal profile for unknown types
+func ScanProfileFor(envType string) ScanProfile {
+	if profile, ok := scanProfiles[strings.ToLower(envType)]; ok {
+		return profile
+	}
+	return scanProfiles[EnvTypeInternal]
+}
+
+// cliArgs returns the Claude CLI flags enforcing the profile's tool set
+func (p *ScanProfile) cliArgs() []string {
+	var args []string
+	if len(p.AllowedTools) > 0 {
+		args = append(args, "--allowedTools", strings.Join(p.AllowedTools, ","))
+	}
+	if len(p.DisallowedTools) > 0 {
+		args = append(args, "--disallowedTools", strings.Join(p.DisallowedTools, ","))
+	}
+	return args
+}
+
+// reconEnvType looks up the type of the environment a mission scans: the
+// scheduled scan's environment if set, otherwise the one registered for its
+// project path. Unregistered environments are internal.
+func (c *Captain) reconEnvType(ctx context.Context, mission Mission) string {
+	reconRepo, ok := c.memDB.(memory.ReconRepository)
+	if !ok {
+		return EnvTypeInternal
+	}
+	for _, envID := range reconEnvIDs(mission) {
+		if env, err := reconRepo.GetEnvironment(ctx, envID); err == nil && env.EnvType != "" {
+			return env.EnvType
+		}
+	}
+	return EnvTypeInternal
+}
+
+// reconSecurityPolicy looks up the security policy of the environment a
+// mission scans, found the same way as reconEnvType. Unregistered
+// environments get the default policy for envType.
+func (c *Captain) reconSecurityPolicy(ctx context.Context, mission Mission, envType string) memory.SecurityPolicy {
+	if reconRepo, ok := c.memDB.(memory.ReconRepository); ok {
+		for _, envID := range reconEnvIDs(mission) {
+			if policy, err := reconRepo.GetEnvironmentPolicy(ctx, envID); err == nil {
+				return *policy
+			}
+		}
+	}
+	return memory.DefaultSecurityPolicy(envType)
+}
+
+// reconEnvIDs returns the environment IDs a mission may scan, most specific
+// first: the scheduled scan's environment, then its project path's
+func reconEnvIDs(mission Mission) []string {
+	var envIDs []string
+	if envID := mission.Metadata[metaEnvID]; envID != "" {
+		envIDs = append(envIDs, envID)
+	}
+	if mission.ProjectPath != "" {
+		envIDs = append(envIDs, sanitizeEnvID(mission.ProjectPath))
+	}
+	return envIDs
+}
+
+// policyInstructions returns prompt constraints for a security policy
+func policyInstructions(policy *memory.SecurityPolicy) []string {
+	if policy == nil {
+		return nil
+	}
+	var instructions []string
+	if !policy.AllowNetworkScan {
+		instructions = append(instructions, "Do not make network requests or probe hosts, ports or services")
+	}
+	if !policy.AllowFileSystemWrite {
+		instructions = append(instructions, "Do not create, modify or delete any files")
+	}
+	if policy.MaxFindingSeverity != "" {
+		instructions = append(instructions, fmt.Sprintf("Findings more severe than %s are recorded as ignored in this environment", policy.MaxFindingSeverity))
+	}
+	return instructions
+}
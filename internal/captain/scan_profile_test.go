@@ -0,0 +1,119 @@
+package captain
+
+import (
+	"context"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/CLIAIMONITOR/internal/memory"
+)
+
+func TestScanProfileFor(t *testing.T) {
+	if got := ScanProfileFor("Customer").EnvType; got != EnvTypeCustomer {
+		t.Errorf("ScanProfileFor(Customer) = %s, want customer", got)
+	}
+	if got := ScanProfileFor("staging").EnvType; got != EnvTypeInternal {
+		t.Errorf("ScanProfileFor(staging) = %s, want internal fallback", got)
+	}
+
+	var types []string
+	for _, p := range ScanProfiles() {
+		types = append(types, p.EnvType)
+	}
+	if want := []string{EnvTypeCustomer, EnvTypeInternal, EnvTypeTest}; !reflect.DeepEqual(types, want) {
+		t.Errorf("ScanProfiles() env types = %v, want %v", types, want)
+	}
+}
+
+func TestCustomerProfileIsReadOnly(t *testing.T) {
+	profile := ScanProfileFor(EnvTypeCustomer)
+	args := strings.Join(profile.cliArgs(), " ")
+	if !strings.Contains(args, "--allowedTools Read,Grep,Glob,LS") || !strings.Contains(args, "Bash") {
+		t.Errorf("customer cliArgs = %q, want read-only tools and Bash disallowed", args)
+	}
+	internal := ScanProfileFor(EnvTypeInternal)
+	if len(internal.cliArgs()) != 0 {
+		t.Errorf("internal profile should not restrict tools, got %v", internal.cliArgs())
+	}
+
+	c := NewCaptain(".", nil, nil, nil)
+	prompt := c.buildSubagentPrompt(Mission{Title: "Scan", TaskType: TaskRecon}, ModeDecision{Profile: &profile})
+	if !strings.Contains(prompt, "This is customer code") {
+		t.Errorf("prompt missing customer instructions:\n%s", prompt)
+	}
+}
+
+func TestReconEnvType(t *testing.T) {
+	db, err := memory.NewMemoryDB(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("Failed to create test database: %v", err)
+	}
+	defer db.Close()
+	reconRepo := db.(memory.ReconRepository)
+
+	ctx := context.Background()
+	for _, env := range []*memory.Environment{
+		{ID: "acme", Name: "acme", EnvType: EnvTypeCustomer, BasePath: "/repos/acme-app"},
+		{ID: "fixtures", Name: "fixtures", EnvType: EnvTypeTest, BasePath: "/repos/fixtures"},
+	} {
+		if err := reconRepo.RegisterEnvironment(ctx, env); err != nil {
+			t.Fatalf("RegisterEnvironment failed: %v", err)
+		}
+	}
+
+	c := NewCaptain(".", nil, db, nil)
+	tests := []struct {
+		mission Mission
+		want    string
+	}{
+		{Mission{ProjectPath: "/repos/acme-app", Metadata: map[string]string{metaEnvID: "acme"}}, EnvTypeCustomer},
+		{Mission{ProjectPath: "/repos/fixtures"}, EnvTypeTest},
+		{Mission{ProjectPath: "/repos/unknown"}, EnvTypeInternal},
+	}
+	for _, tt := range tests {
+		if got := c.reconEnvType(ctx, tt.mission); got != tt.want {
+			t.Errorf("reconEnvType(%s) = %s, want %s", tt.mission.ProjectPath, got, tt.want)
+		}
+	}
+}
+
+func TestReconSecurityPolicyInPrompt(t *testing.T) {
+	db, err := memory.NewMemoryDB(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("Failed to create test database: %v", err)
+	}
+	defer db.Close()
+	reconRepo := db.(memory.ReconRepository)
+
+	ctx := context.Background()
+	if err := reconRepo.RegisterEnvironment(ctx, &memory.Environment{ID: "acme", Name: "acme", EnvType: EnvTypeCustomer,
+		SecurityPolicy: &memory.SecurityPolicy{AllowFileSystemWrite: true, MaxFindingSeverity: "high"}}); err != nil {
+		t.Fatalf("RegisterEnvironment failed: %v", err)
+	}
+
+	c := NewCaptain(".", nil, db, nil)
+	mission := Mission{Title: "Scan", TaskType: TaskRecon, Metadata: map[string]string{metaEnvID: "acme"}}
+	policy := c.reconSecurityPolicy(ctx, mission, EnvTypeCustomer)
+	if policy.AllowNetworkScan || !policy.AllowFileSystemWrite || policy.MaxFindingSeverity != "high" {
+		t.Fatalf("Unexpected policy: %+v", policy)
+	}
+
+	prompt := c.buildSubagentPrompt(mission, ModeDecision{Policy: &policy})
+	if !strings.Contains(prompt, "## Security Policy") || !strings.Contains(prompt, "Do not make network requests") ||
+		!strings.Contains(prompt, "more severe than high") {
+		t.Errorf("prompt missing policy constraints:\n%s", prompt)
+	}
+	if strings.Contains(prompt, "Do not create, modify or delete") {
+		t.Errorf("prompt forbids writes the policy allows:\n%s", prompt)
+	}
+
+	if got := c.reconSecurityPolicy(ctx, Mission{ProjectPath: "/repos/unknown"}, EnvTypeInternal); got != memory.DefaultSecurityPolicy(EnvTypeInternal) {
+		t.Errorf("Expected default policy for unregistered environment, got %+v", got)
+	}
+	permissive := memory.DefaultSecurityPolicy(EnvTypeInternal)
+	if prompt := c.buildSubagentPrompt(Mission{TaskType: TaskRecon}, ModeDecision{Policy: &permissive}); strings.Contains(prompt, "## Security Policy") {
+		t.Errorf("permissive policy should add no constraints:\n%s", prompt)
+	}
+}
@@ -0,0 +1,149 @@
+package captain
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/CLIAIMONITOR/internal/events"
+	"github.com/CLIAIMONITOR/internal/logger"
+	"github.com/CLIAIMONITOR/internal/memory"
+	"github.com/CLIAIMONITOR/internal/supervisor"
+)
+
+// ScanProgressInterval is how often a running recon scan reports progress
+const ScanProgressInterval = 15 * time.Second
+
+// ScanProgressTarget is the event bus target scan_progress events are sent to
+const ScanProgressTarget = "recon"
+
+// SetEventBus sets the bus Captain publishes scan progress on
+func (c *Captain) SetEventBus(bus *events.Bus) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.eventBus = bus
+}
+
+// ReportScanProgress records how far a scan has got and publishes it as a
+// scan_progress event
+func (c *Captain) ReportScanProgress(ctx context.Context, scanID string, filesScanned int, currentFile string) error {
+	reconRepo, ok := c.memDB.(memory.ReconRepository)
+	if !ok {
+		return fmt.Errorf("memory database does not implement ReconRepository interface")
+	}
+	if err := reconRepo.ReportScanProgress(ctx, scanID, filesScanned, currentFile); err != nil {
+		return err
+	}
+	c.publishScanProgress(ctx, reconRepo, scanID)
+	return nil
+}
+
+// publishScanProgress publishes the stored progress of a scan
+func (c *Captain) publishScanProgress(ctx context.Context, reconRepo memory.ReconRepository, scanID string) {
+	c.mu.RLock()
+	bus := c.eventBus
+	c.mu.RUnlock()
+	if bus == nil {
+		return
+	}
+
+	progress, err := reconRepo.GetScanProgress(ctx, scanID)
+	if err != nil || progress == nil {
+		return
+	}
+	bus.Publish(events.NewEvent(events.EventScanProgress, "captain", ScanProgressTarget, events.PriorityLow, map[string]interface{}{
+		"scan_id":       progress.ScanID,
+		"status":        progress.Status,
+		"files_scanned": progress.FilesScanned,
+		"current_file":  progress.CurrentFile,
+	}))
+}
+
+// startReconScan records a running scan for a recon mission so its progress
+// can be followed. Returns "" when the scan cannot be tracked.
+func (c *Captain) startReconScan(ctx context.Context, mission Mission) string {
+	reconRepo, ok := c.memDB.(memory.ReconRepository)
+	if !ok || mission.ProjectPath == "" {
+		return ""
+	}
+
+	env := reconEnvironment(mission.ProjectPath, mission.Metadata[metaEnvType])
+	if err := reconRepo.RegisterEnvironment(ctx, env); err != nil {
+		logger.For("captain").Warn("failed to register environment for scan tracking", "path", mission.ProjectPath, "error", err)
+		return ""
+	}
+
+	scan := &memory.ReconScan{
+		ID:        fmt.Sprintf("%s-%d", mission.ID, time.Now().Unix()),
+		EnvID:     env.ID,
+		AgentID:   "Snake",
+		ScanType:  "initial",
+		Mission:   mission.Title,
+		StartedAt: time.Now(),
+		Status:    "running",
+	}
+	if err := reconRepo.RecordScan(ctx, scan); err != nil {
+		logger.For("captain").Warn("failed to record running scan", "scan_id", scan.ID, "error", err)
+		return ""
+	}
+	c.publishScanProgress(ctx, reconRepo, scan.ID)
+	return scan.ID
+}
+
+// trackScanProgress re-reports the latest progress of a scan every
+// ScanProgressInterval until the returned function is called. The Snake
+// subagent only returns output when it exits, so until then each report is
+// a heartbeat carrying the last known file count.
+func (c *Captain) trackScanProgress(ctx context.Context, scanID string) (stop func()) {
+	reconRepo, ok := c.memDB.(memory.ReconRepository)
+	if !ok || scanID == "" {
+		return func() {}
+	}
+
+	done := make(chan struct{})
+	go func() {
+		ticker := time.NewTicker(ScanProgressInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				progress, err := reconRepo.GetScanProgress(ctx, scanID)
+				if err != nil || progress == nil {
+					continue
+				}
+				if err := c.ReportScanProgress(ctx, scanID, progress.FilesScanned, progress.CurrentFile); err != nil {
+					logger.For("captain").Warn("failed to report scan progress", "scan_id", scanID, "error", err)
+				}
+			}
+		}
+	}()
+	return func() { close(done) }
+}
+
+// finishReconScan marks a tracked scan completed or failed and publishes
+// its final progress
+func (c *Captain) finishReconScan(ctx context.Context, scanID string, report *supervisor.ReconReport, scanErr error) {
+	reconRepo, ok := c.memDB.(memory.ReconRepository)
+	if !ok || scanID == "" {
+		return
+	}
+
+	status := "completed"
+	if scanErr != nil {
+		status = "failed"
+	}
+	if err := reconRepo.UpdateScanStatus(ctx, scanID, status); err != nil {
+		logger.For("captain").Warn("failed to update scan status", "scan_id", scanID, "status", status, "error", err)
+	}
+
+	if report != nil && report.Summary != nil {
+		if err := c.ReportScanProgress(ctx, scanID, report.Summary.TotalFilesScanned, ""); err == nil {
+			return
+		}
+	}
+	c.publishScanProgress(ctx, reconRepo, scanID)
+}
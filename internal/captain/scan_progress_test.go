@@ -0,0 +1,90 @@
+package captain
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/CLIAIMONITOR/internal/events"
+	"github.com/CLIAIMONITOR/internal/memory"
+	"github.com/CLIAIMONITOR/internal/supervisor"
+)
+
+func TestReconScanProgressLifecycle(t *testing.T) {
+	db, err := memory.NewMemoryDB(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("Failed to create test database: %v", err)
+	}
+	defer db.Close()
+	reconRepo := db.(memory.ReconRepository)
+
+	bus := events.NewBus(nil)
+	ch := bus.Subscribe(ScanProgressTarget, []events.EventType{events.EventScanProgress})
+
+	c := NewCaptain(".", nil, db, nil)
+	c.SetEventBus(bus)
+
+	ctx := context.Background()
+	nextEvent := func() map[string]interface{} {
+		t.Helper()
+		select {
+		case event := <-ch:
+			return event.Payload
+		case <-time.After(time.Second):
+			t.Fatal("Expected a scan_progress event")
+			return nil
+		}
+	}
+
+	scanID := c.startReconScan(ctx, Mission{ID: "recon-m1", Title: "Recon", ProjectPath: "/repos/app"})
+	if scanID == "" {
+		t.Fatal("Expected scan to be tracked")
+	}
+	if payload := nextEvent(); payload["scan_id"] != scanID || payload["status"] != "running" {
+		t.Errorf("Unexpected start event: %v", payload)
+	}
+
+	if err := c.ReportScanProgress(ctx, scanID, 12, "main.go"); err != nil {
+		t.Fatalf("ReportScanProgress failed: %v", err)
+	}
+	if payload := nextEvent(); payload["files_scanned"] != 12 || payload["current_file"] != "main.go" {
+		t.Errorf("Unexpected progress event: %v", payload)
+	}
+
+	report := &supervisor.ReconReport{Summary: &supervisor.ReconSummary{TotalFilesScanned: 40}}
+	c.finishReconScan(ctx, scanID, report, nil)
+	if payload := nextEvent(); payload["status"] != "completed" || payload["files_scanned"] != 40 {
+		t.Errorf("Unexpected completion event: %v", payload)
+	}
+
+	progress, err := reconRepo.GetScanProgress(ctx, scanID)
+	if err != nil || progress == nil || !progress.Done() {
+		t.Fatalf("Expected completed scan progress, got %+v (%v)", progress, err)
+	}
+}
+
+func TestReconScanProgressFailure(t *testing.T) {
+	db, err := memory.NewMemoryDB(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("Failed to create test database: %v", err)
+	}
+	defer db.Close()
+
+	c := NewCaptain(".", nil, db, nil)
+	ctx := context.Background()
+
+	scanID := c.startReconScan(ctx, Mission{ID: "recon-m2", Title: "Recon", ProjectPath: "/repos/app"})
+	c.finishReconScan(ctx, scanID, nil, errors.New("snake recon failed"))
+
+	progress, err := db.(memory.ReconRepository).GetScanProgress(ctx, scanID)
+	if err != nil || progress == nil || progress.Status != "failed" {
+		t.Errorf("Expected failed scan, got %+v (%v)", progress, err)
+	}
+
+	// Without a project path there is nothing to track
+	if id := c.startReconScan(ctx, Mission{ID: "recon-m3"}); id != "" {
+		t.Errorf("Expected untracked scan, got %q", id)
+	}
+}
@@ -0,0 +1,118 @@
+package captain
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/CLIAIMONITOR/internal/supervisor"
+)
+
+// DefaultMaxOutputBytes is how much subagent output Captain keeps when
+// MaxOutputBytes is not set
+const DefaultMaxOutputBytes = 1 << 20
+
+// OutputTruncatedMarker is appended to subagent output cut at MaxOutputBytes
+const OutputTruncatedMarker = "\n[OUTPUT TRUNCATED]"
+
+// maxJSONReportAttempts caps how many candidate objects jsonReportPortion
+// decodes, so output full of braces stays cheap to search
+const maxJSONReportAttempts = 32
+
+// reportKeys are the top-level keys that mark an object as a recon report.
+// At least one must be present for salvaged output to count as a report.
+var reportKeys = []string{"findings", "summary"}
+
+// truncateOutput cuts output to maxBytes and appends OutputTruncatedMarker.
+// A maxBytes of zero or less disables the limit.
+func truncateOutput(output []byte, maxBytes int) ([]byte, bool) {
+	if maxBytes <= 0 || len(output) <= maxBytes {
+		return output, false
+	}
+	truncated := make([]byte, 0, maxBytes+len(OutputTruncatedMarker))
+	truncated = append(truncated, output[:maxBytes]...)
+	truncated = append(truncated, OutputTruncatedMarker...)
+	return truncated, true
+}
+
+// parseTruncatedReport parses the recon report out of truncated output. Only
+// the YAML or JSON portion is parsed, so chatter around it and the partial
+// line at the cut are ignored. Returns nil if no report with findings or a
+// summary can be recovered.
+func parseTruncatedReport(parser supervisor.ReportParser, output string) *supervisor.ReconReport {
+	output = strings.TrimSuffix(output, OutputTruncatedMarker)
+
+	if portion := yamlReportPortion(output); portion != "" && yamlHasReportKeys(portion) {
+		if report, err := parser.ParseYAML([]byte(portion)); err == nil {
+			return report
+		}
+	}
+	if portion := jsonReportPortion(output); portion != "" {
+		if report, err := parser.ParseJSON([]byte(portion)); err == nil {
+			return report
+		}
+	}
+	return nil
+}
+
+// yamlReportPortion returns the output from the snake_report: line to the end
+// of its code fence, or up to the last complete line if the fence was cut off
+func yamlReportPortion(output string) string {
+	start := strings.Index(output, "snake_report:")
+	if start < 0 {
+		return ""
+	}
+	// Start at the beginning of the line to keep its indentation
+	start = strings.LastIndex(output[:start], "\n") + 1
+	portion := output[start:]
+
+	if end := strings.Index(portion, "\n```"); end >= 0 {
+		return portion[:end]
+	}
+	if end := strings.LastIndex(portion, "\n"); end >= 0 {
+		return portion[:end]
+	}
+	return portion
+}
+
+// yamlHasReportKeys reports whether a snake_report block has a findings or
+// summary key
+func yamlHasReportKeys(portion string) bool {
+	for _, line := range strings.Split(portion, "\n") {
+		line = strings.TrimSpace(line)
+		for _, key := range reportKeys {
+			if strings.HasPrefix(line, key+":") {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// jsonReportPortion returns the first complete top-level JSON object in the
+// output that has a findings or summary key. Objects without them are
+// skipped whole, so an object nested inside one is never taken as the report.
+func jsonReportPortion(output string) string {
+	offset := 0
+	for attempt := 0; attempt < maxJSONReportAttempts; attempt++ {
+		i := strings.IndexByte(output[offset:], '{')
+		if i < 0 {
+			return ""
+		}
+		start := offset + i
+
+		var fields map[string]json.RawMessage
+		dec := json.NewDecoder(strings.NewReader(output[start:]))
+		if err := dec.Decode(&fields); err != nil {
+			offset = start + 1
+			continue
+		}
+		end := start + int(dec.InputOffset())
+		for _, key := range reportKeys {
+			if v, ok := fields[key]; ok && string(v) != "null" {
+				return output[start:end]
+			}
+		}
+		offset = end
+	}
+	return ""
+}
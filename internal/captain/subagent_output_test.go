@@ -0,0 +1,101 @@
+package captain
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/CLIAIMONITOR/internal/supervisor"
+)
+
+func TestTruncateOutput(t *testing.T) {
+	out, truncated := truncateOutput([]byte("short"), 10)
+	if truncated || string(out) != "short" {
+		t.Errorf("truncateOutput under limit = %q, %v", out, truncated)
+	}
+
+	out, truncated = truncateOutput([]byte("0123456789abcdef"), 10)
+	if !truncated || string(out) != "0123456789"+OutputTruncatedMarker {
+		t.Errorf("truncateOutput over limit = %q, %v", out, truncated)
+	}
+
+	if _, truncated := truncateOutput([]byte("0123456789abcdef"), 0); truncated {
+		t.Error("zero limit should disable truncation")
+	}
+}
+
+func TestParseTruncatedReportYAML(t *testing.T) {
+	output := "Scanning repository...\n```yaml\nsnake_report:\n  agent_id: \"Snake001\"\n  findings:\n    critical:\n      - id: \"VULN-001\"\n        description: \"SQL injection\"\n    high: []\n  summary:\n    total_files_scanned: 12\n  notes: \"this line is cut off mid-wo" + OutputTruncatedMarker
+
+	// The partial last line makes the raw output unparseable
+	parser := supervisor.NewReportParser()
+	if _, err := parser.ParseYAML([]byte(output)); err == nil {
+		t.Fatal("expected raw truncated output to fail parsing")
+	}
+
+	report := parseTruncatedReport(parser, output)
+	if report == nil {
+		t.Fatal("expected report from truncated YAML output")
+	}
+	if report.AgentID != "Snake001" || len(report.Findings.Critical) != 1 {
+		t.Errorf("unexpected report: %+v", report)
+	}
+}
+
+func TestParseTruncatedReportJSON(t *testing.T) {
+	output := `Here is the report: {"agent_id": "Snake002", "findings": {"high": [{"id": "ARCH-1", "description": "God object"}]}} and some more chatter that runs on and` + OutputTruncatedMarker
+
+	report := parseTruncatedReport(supervisor.NewReportParser(), output)
+	if report == nil {
+		t.Fatal("expected report from truncated JSON output")
+	}
+	if report.AgentID != "Snake002" || len(report.Findings.High) != 1 {
+		t.Errorf("unexpected report: %+v", report)
+	}
+}
+
+func TestParseTruncatedReportNoReport(t *testing.T) {
+	output := strings.Repeat("thinking... ", 50) + OutputTruncatedMarker
+	if report := parseTruncatedReport(supervisor.NewReportParser(), output); report != nil {
+		t.Errorf("expected nil report, got %+v", report)
+	}
+}
+
+func TestParseTruncatedReportSkipsOtherObjects(t *testing.T) {
+	// The first object is unrelated and the report's nested finding must not
+	// be picked up on its own
+	output := `Config: {"verbose": true, "depth": {"max": 3}} Report: {"agent_id": "Snake003", "summary": {"total_files_scanned": 7}, "findings": {"low": [{"id": "DOC-1", "description": "Missing README"}]}} done` + OutputTruncatedMarker
+
+	report := parseTruncatedReport(supervisor.NewReportParser(), output)
+	if report == nil {
+		t.Fatal("expected report after the unrelated object")
+	}
+	if report.AgentID != "Snake003" || len(report.Findings.Low) != 1 {
+		t.Errorf("unexpected report: %+v", report)
+	}
+}
+
+func TestParseTruncatedReportRequiresReportKeys(t *testing.T) {
+	parser := supervisor.NewReportParser()
+
+	// A report cut before its findings is not a report, and neither is an
+	// object nested in it
+	cut := `{"agent_id": "Snake004", "meta": {"id": "x"}, "findings": {"critical": [{"id": "VU` + OutputTruncatedMarker
+	if report := parseTruncatedReport(parser, cut); report != nil {
+		t.Errorf("expected nil report from cut JSON, got %+v", report)
+	}
+
+	yamlCut := "```yaml\nsnake_report:\n  agent_id: \"Snake005\"\n  mission: \"sc" + OutputTruncatedMarker
+	if report := parseTruncatedReport(parser, yamlCut); report != nil {
+		t.Errorf("expected nil report from YAML without findings, got %+v", report)
+	}
+}
+
+func TestJSONReportPortionCapsAttempts(t *testing.T) {
+	output := strings.Repeat("{} ", maxJSONReportAttempts) + `{"findings": {}}`
+	if portion := jsonReportPortion(output); portion != "" {
+		t.Errorf("expected search to stop after %d attempts, got %q", maxJSONReportAttempts, portion)
+	}
+	if portion := jsonReportPortion(output[3:]); portion != `{"findings": {}}` {
+		t.Errorf("expected report within the attempt limit, got %q", portion)
+	}
+}
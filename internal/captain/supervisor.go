@@ -16,12 +16,14 @@ import (
 type CaptainStatus string
 
 const (
-	StatusStarting   CaptainStatus = "starting"
-	StatusRunning    CaptainStatus = "running"
-	StatusCrashed    CaptainStatus = "crashed"
-	StatusRestarting CaptainStatus = "restarting"
-	StatusStopped    CaptainStatus = "stopped"
-	StatusDisabled   CaptainStatus = "disabled" // Crash loop protection triggered
+	StatusStarting     CaptainStatus = "starting"
+	StatusRunning      CaptainStatus = "running"
+	StatusCrashed      CaptainStatus = "crashed"
+	StatusRestarting   CaptainStatus = "restarting"
+	StatusStopped      CaptainStatus = "stopped"
+	StatusDisabled     CaptainStatus = "disabled"     // Crash loop protection triggered
+	StatusUnresponsive CaptainStatus = "unresponsive" // Launched but heartbeats stopped
+	StatusIdle         CaptainStatus = "idle"         // Launched and waiting for input since its last Stop hook
 )
 
 // CaptainSupervisor manages the Captain process lifecycle
@@ -47,11 +49,21 @@ type CaptainSupervisor struct {
 	lastExitCode  int
 	lastExitTime  time.Time
 	startTime     time.Time
+	lastHeartbeat time.Time // last POST /api/captain/terminal/heartbeat
+	lastHookEvent string    // hook event that sent it, empty for MCP calls
 	shutdownChan  chan struct{}
 	shutdownOnce  sync.Once
 
 	// Callbacks
 	onShutdownRequest func() // Called when Captain exits cleanly (code 0)
+
+	// Output capture (optional)
+	logFile         string
+	logMaxBytes     int64
+	logMaxKeep      int
+	logPollInterval time.Duration
+	logStop         chan struct{}                    // closed to end the running capture
+	paneText        func(paneID int) (string, error) // reads the Captain pane; WezTerm by default
 }
 
 // SupervisorConfig holds configuration for the CaptainSupervisor
@@ -60,6 +72,9 @@ type SupervisorConfig struct {
 	ServerPort     int
 	MaxRespawns    int           // Default: 3
 	WindowDuration time.Duration // Default: 1 minute
+	LogFile        string        // Copy the Captain pane's text here when set (both split-pane and new-window launches)
+	LogMaxBytes    int64         // Default: 10MB
+	LogMaxKeep     int           // Default: 3 rotated files
 }
 
 // CaptainInfo provides status information for API responses
@@ -72,6 +87,15 @@ type CaptainInfo struct {
 	RespawnCount int           `json:"respawn_count"`
 	MaxRespawns  int           `json:"max_respawns"`
 	CanRestart   bool          `json:"can_restart"`
+	LogFile      string        `json:"log_file,omitempty"`
+	LogSizeBytes int64         `json:"log_size_bytes,omitempty"`
+
+	// The launcher's view (process or pane spawned and not exited) and the
+	// heartbeat's view are reported separately; Status follows heartbeats
+	IsAliveViaWezTerm   bool       `json:"is_alive_via_wezterm"`
+	IsAliveViaHeartbeat bool       `json:"is_alive_via_heartbeat"`
+	LastHeartbeat       *time.Time `json:"last_heartbeat,omitempty"`
+	LastHookEvent       string     `json:"last_hook_event,omitempty"`
 }
 
 // NewCaptainSupervisor creates a new supervisor instance
@@ -82,6 +106,12 @@ func NewCaptainSupervisor(config SupervisorConfig) *CaptainSupervisor {
 	if config.WindowDuration == 0 {
 		config.WindowDuration = 1 * time.Minute
 	}
+	if config.LogMaxBytes <= 0 {
+		config.LogMaxBytes = DefaultLogMaxBytes
+	}
+	if config.LogMaxKeep <= 0 {
+		config.LogMaxKeep = DefaultLogMaxKeep
+	}
 
 	return &CaptainSupervisor{
 		basePath:       config.BasePath,
@@ -90,6 +120,12 @@ func NewCaptainSupervisor(config SupervisorConfig) *CaptainSupervisor {
 		windowDuration: config.WindowDuration,
 		status:         StatusStopped,
 		shutdownChan:   make(chan struct{}),
+		logFile:        config.LogFile,
+		logMaxBytes:    config.LogMaxBytes,
+		logMaxKeep:     config.LogMaxKeep,
+
+		logPollInterval: LogPollInterval,
+		paneText:        readCaptainPane,
 	}
 }
 
@@ -125,7 +161,10 @@ func (s *CaptainSupervisor) Stop() error {
 		}
 	}
 
+	s.stopLogCaptureLocked()
 	s.status = StatusStopped
+	s.lastHeartbeat = time.Time{}
+	s.lastHookEvent = ""
 	return nil
 }
 
@@ -151,20 +190,35 @@ func (s *CaptainSupervisor) GetInfo() CaptainInfo {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
 
+	now := time.Now()
+	status := s.heartbeatStatus(now)
 	info := CaptainInfo{
-		Status:       s.status,
-		PID:          s.captainPID,
-		LastExitCode: s.lastExitCode,
-		RespawnCount: s.respawnCount,
-		MaxRespawns:  s.maxRespawns,
-		CanRestart:   s.status == StatusDisabled || s.status == StatusCrashed || s.status == StatusStopped,
+		Status:              status,
+		PID:                 s.captainPID,
+		LastExitCode:        s.lastExitCode,
+		RespawnCount:        s.respawnCount,
+		MaxRespawns:         s.maxRespawns,
+		CanRestart:          status == StatusDisabled || status == StatusCrashed || status == StatusStopped || status == StatusUnresponsive,
+		IsAliveViaWezTerm:   s.status == StatusRunning,
+		IsAliveViaHeartbeat: s.aliveViaHeartbeat(now),
+		LastHookEvent:       s.lastHookEvent,
 	}
 
-	if !s.startTime.IsZero() {
-		info.StartTime = &s.startTime
+	// Copy the times out; pointers into s would race with later updates
+	if startTime := s.startTime; !startTime.IsZero() {
+		info.StartTime = &startTime
+	}
+	if lastExitTime := s.lastExitTime; !lastExitTime.IsZero() {
+		info.LastExitTime = &lastExitTime
 	}
-	if !s.lastExitTime.IsZero() {
-		info.LastExitTime = &s.lastExitTime
+	if lastHeartbeat := s.lastHeartbeat; !lastHeartbeat.IsZero() {
+		info.LastHeartbeat = &lastHeartbeat
+	}
+	if s.logFile != "" {
+		info.LogFile = s.logFile
+		if stat, err := os.Stat(s.logFile); err == nil {
+			info.LogSizeBytes = stat.Size()
+		}
 	}
 
 	return info
@@ -264,8 +318,9 @@ func (s *CaptainSupervisor) spawnCaptain() error {
 	}
 
 	settingsFile := filepath.Join(claudeDir, "settings.local.json")
-	settings := map[string]string{
+	settings := map[string]interface{}{
 		"appendSystemPrompt": captainPrompt,
+		"hooks":              heartbeatHooks(s.serverPort),
 	}
 	settingsJSON, _ := json.MarshalIndent(settings, "", "  ")
 	if err := os.WriteFile(settingsFile, settingsJSON, 0644); err != nil {
@@ -351,6 +406,9 @@ func (s *CaptainSupervisor) spawnCaptain() error {
 		s.captainPaneID = captainPaneID
 		s.mu.Unlock()
 
+		if captainPaneID != 0 {
+			s.startLogCapture(captainPaneID)
+		}
 		return nil
 	}
 
@@ -404,6 +462,17 @@ cd /d "%s"
 	// Monitor the process in a goroutine
 	go s.monitorCaptain(cmd)
 
+	// wezterm start does not report the new pane, so find it to log it
+	if s.logFile != "" {
+		go func() {
+			if paneID, ok := findCaptainWindowPane(); ok {
+				s.startLogCapture(paneID)
+			} else {
+				fmt.Println("[SUPERVISOR] Warning: Captain pane not found; Captain log disabled")
+			}
+		}()
+	}
+
 	return nil
 }
 
@@ -0,0 +1,83 @@
+package captain
+
+import (
+	"fmt"
+	"time"
+)
+
+// HeartbeatInterval is how often a working Captain is expected to post to
+// /api/captain/terminal/heartbeat
+const HeartbeatInterval = 30 * time.Second
+
+// HeartbeatTimeout is how long after its last heartbeat Captain is still
+// reported as running. Three missed heartbeats mark it unresponsive.
+const HeartbeatTimeout = 90 * time.Second
+
+// RecordHeartbeat notes that Captain is alive. Heartbeats come from the
+// Captain Claude session itself, through its hooks and MCP calls, and keep
+// arriving when WezTerm restarts or the server loses track of the pane, so
+// they are what GetInfo trusts for the running status.
+func (s *CaptainSupervisor) RecordHeartbeat() {
+	s.RecordHeartbeatEvent("")
+}
+
+// RecordHeartbeatEvent records a heartbeat sent by the named hook event.
+// After a Stop hook Captain is waiting for input and sends nothing more until
+// it is prompted, so heartbeatStatus reports it idle rather than unresponsive.
+func (s *CaptainSupervisor) RecordHeartbeatEvent(event string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.lastHeartbeat = time.Now()
+	s.lastHookEvent = event
+}
+
+// aliveViaHeartbeat reports whether a heartbeat arrived within
+// HeartbeatTimeout. Callers must hold s.mu.
+func (s *CaptainSupervisor) aliveViaHeartbeat(now time.Time) bool {
+	return !s.lastHeartbeat.IsZero() && now.Sub(s.lastHeartbeat) < HeartbeatTimeout
+}
+
+// heartbeatStatus derives the reported status from the launch state and
+// heartbeats. A Captain the launcher thinks is running but which has stopped
+// sending heartbeats is unresponsive, unless the last heartbeat came from the
+// Stop hook: then it finished a response and is idle at its prompt. Before
+// its first heartbeat it is given HeartbeatTimeout to start. Callers must
+// hold s.mu.
+func (s *CaptainSupervisor) heartbeatStatus(now time.Time) CaptainStatus {
+	if s.aliveViaHeartbeat(now) {
+		return StatusRunning
+	}
+	if s.status != StatusRunning {
+		return s.status
+	}
+	if s.lastHeartbeat.IsZero() && now.Sub(s.startTime) < HeartbeatTimeout {
+		return StatusStarting
+	}
+	if s.lastHookEvent == "Stop" {
+		return StatusIdle
+	}
+	return StatusUnresponsive
+}
+
+// heartbeatHookEvents are the Claude Code hook events that post a heartbeat:
+// every prompt, every tool call and every finished response
+var heartbeatHookEvents = []string{"UserPromptSubmit", "PostToolUse", "Stop"}
+
+// heartbeatHooks returns the Claude Code settings "hooks" entry that makes
+// the Captain session post a heartbeat, tagged with the hook event, as it
+// works. The hooks run in the Claude process, so a hung Captain stops
+// sending them even while its console stays open.
+func heartbeatHooks(serverPort int) map[string]interface{} {
+	hooks := make(map[string]interface{}, len(heartbeatHookEvents))
+	for _, event := range heartbeatHookEvents {
+		command := fmt.Sprintf("curl -s -m 5 -X POST \"http://localhost:%d/api/captain/terminal/heartbeat?event=%s\"", serverPort, event)
+		hooks[event] = []map[string]interface{}{{
+			"matcher": "*",
+			"hooks": []map[string]interface{}{{
+				"type":    "command",
+				"command": command,
+			}},
+		}}
+	}
+	return hooks
+}
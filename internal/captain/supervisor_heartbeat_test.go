@@ -0,0 +1,137 @@
+package captain
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCaptainSupervisor_HeartbeatStatus(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		name          string
+		status        CaptainStatus
+		startTime     time.Time
+		lastHeartbeat time.Time
+		want          CaptainStatus
+		wantAlive     bool
+	}{
+		{"fresh heartbeat", StatusRunning, now.Add(-time.Hour), now.Add(-10 * time.Second), StatusRunning, true},
+		{"stale heartbeat", StatusRunning, now.Add(-time.Hour), now.Add(-2 * time.Minute), StatusUnresponsive, false},
+		{"waiting for first heartbeat", StatusRunning, now.Add(-time.Minute), time.Time{}, StatusStarting, false},
+		{"never sent a heartbeat", StatusRunning, now.Add(-time.Hour), time.Time{}, StatusUnresponsive, false},
+		{"heartbeat after pane was lost", StatusCrashed, now.Add(-time.Hour), now.Add(-5 * time.Second), StatusRunning, true},
+		{"stopped", StatusStopped, time.Time{}, time.Time{}, StatusStopped, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewCaptainSupervisor(SupervisorConfig{BasePath: t.TempDir()})
+			s.status = tt.status
+			s.startTime = tt.startTime
+			s.lastHeartbeat = tt.lastHeartbeat
+
+			info := s.GetInfo()
+			if info.Status != tt.want {
+				t.Errorf("Status = %s, want %s", info.Status, tt.want)
+			}
+			if info.IsAliveViaHeartbeat != tt.wantAlive {
+				t.Errorf("IsAliveViaHeartbeat = %v, want %v", info.IsAliveViaHeartbeat, tt.wantAlive)
+			}
+			if info.IsAliveViaWezTerm != (tt.status == StatusRunning) {
+				t.Errorf("IsAliveViaWezTerm = %v for launcher status %s", info.IsAliveViaWezTerm, tt.status)
+			}
+		})
+	}
+}
+
+func TestCaptainSupervisor_RecordHeartbeat(t *testing.T) {
+	s := NewCaptainSupervisor(SupervisorConfig{BasePath: t.TempDir()})
+	if info := s.GetInfo(); info.LastHeartbeat != nil || info.IsAliveViaHeartbeat {
+		t.Fatalf("Expected no heartbeat yet, got %+v", info)
+	}
+
+	s.RecordHeartbeat()
+	info := s.GetInfo()
+	if info.Status != StatusRunning || !info.IsAliveViaHeartbeat || info.LastHeartbeat == nil {
+		t.Errorf("Expected running via heartbeat, got %+v", info)
+	}
+	if info.CanRestart {
+		t.Error("Expected a live Captain not to be restartable")
+	}
+
+	s.Stop()
+	if info := s.GetInfo(); info.Status != StatusStopped || info.IsAliveViaHeartbeat {
+		t.Errorf("Expected Stop to clear the heartbeat, got %+v", info)
+	}
+}
+
+func TestCaptainSupervisor_IdleAfterStopHook(t *testing.T) {
+	s := NewCaptainSupervisor(SupervisorConfig{BasePath: t.TempDir()})
+	s.status = StatusRunning
+	s.startTime = time.Now().Add(-time.Hour)
+	s.RecordHeartbeatEvent("Stop")
+	s.lastHeartbeat = time.Now().Add(-10 * time.Minute)
+
+	info := s.GetInfo()
+	if info.Status != StatusIdle || info.LastHookEvent != "Stop" {
+		t.Errorf("Expected an idle Captain after its Stop hook, got %+v", info)
+	}
+	if info.CanRestart {
+		t.Error("Expected an idle Captain not to be restartable")
+	}
+
+	// Once the launcher has exited, the launcher's status wins
+	s.status = StatusCrashed
+	if info := s.GetInfo(); info.Status != StatusCrashed || !info.CanRestart {
+		t.Errorf("Expected a crashed Captain to be restartable, got %+v", info)
+	}
+
+	// A tool call that never finished is still unresponsive
+	s.status = StatusRunning
+	s.RecordHeartbeatEvent("PostToolUse")
+	s.lastHeartbeat = time.Now().Add(-10 * time.Minute)
+	if info := s.GetInfo(); info.Status != StatusUnresponsive || !info.CanRestart {
+		t.Errorf("Expected a stalled Captain to be unresponsive, got %+v", info)
+	}
+}
+
+func TestHeartbeatHooks(t *testing.T) {
+	data, err := json.Marshal(heartbeatHooks(3000))
+	if err != nil {
+		t.Fatalf("Failed to marshal hooks: %v", err)
+	}
+	var hooks map[string][]struct {
+		Matcher string `json:"matcher"`
+		Hooks   []struct {
+			Type    string `json:"type"`
+			Command string `json:"command"`
+		} `json:"hooks"`
+	}
+	if err := json.Unmarshal(data, &hooks); err != nil {
+		t.Fatalf("Unexpected hooks shape: %v", err)
+	}
+	for _, event := range []string{"UserPromptSubmit", "PostToolUse", "Stop"} {
+		entries := hooks[event]
+		if len(entries) != 1 || len(entries[0].Hooks) != 1 || entries[0].Hooks[0].Type != "command" {
+			t.Fatalf("Expected one command hook for %s, got %+v", event, entries)
+		}
+		if cmd := entries[0].Hooks[0].Command; !strings.Contains(cmd, "http://localhost:3000/api/captain/terminal/heartbeat?event="+event) {
+			t.Errorf("Expected heartbeat URL in %s hook, got %s", event, cmd)
+		}
+	}
+}
+
+func TestGetInfoCopiesTimes(t *testing.T) {
+	s := NewCaptainSupervisor(SupervisorConfig{BasePath: t.TempDir()})
+	s.RecordHeartbeat()
+	info := s.GetInfo()
+	first := *info.LastHeartbeat
+
+	time.Sleep(time.Millisecond)
+	s.RecordHeartbeat()
+	if !info.LastHeartbeat.Equal(first) {
+		t.Error("A later heartbeat changed an earlier GetInfo result")
+	}
+}
@@ -0,0 +1,239 @@
+package captain
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/CLIAIMONITOR/internal/logger"
+)
+
+// DefaultTeamID is the queue for missions submitted without a team
+const DefaultTeamID = "default"
+
+// TeamConcurrencyContextKeyPrefix prefixes the captain_context key holding a
+// team's concurrency limit, e.g. "team_concurrency:blue" = "4", so limits can
+// be changed at runtime through the context API
+const TeamConcurrencyContextKeyPrefix = "team_concurrency:"
+
+// DefaultTeamConcurrency is how many of a team's tasks may be started per
+// cycle, including queued executions still running, when no limit is stored
+const DefaultTeamConcurrency = 2
+
+// TeamQueueStatus summarizes one team's task queue
+type TeamQueueStatus struct {
+	TeamID           string `json:"team_id"`
+	QueueDepth       int    `json:"queue_depth"`
+	Running          int    `json:"running"`
+	ConcurrencyLimit int    `json:"concurrency_limit"`
+	Paused           bool   `json:"paused"`
+}
+
+// teamKey maps a mission's team to its queue, so missions without a team
+// share DefaultTeamID
+func teamKey(teamID string) string {
+	if teamID = strings.TrimSpace(teamID); teamID == "" {
+		return DefaultTeamID
+	}
+	return teamID
+}
+
+// sortedTeamIDs returns the queue keys in a stable order so every cycle
+// visits teams the same way
+func sortedTeamIDs(queues map[string][]*CaptainTask) []string {
+	ids := make([]string, 0, len(queues))
+	for id := range queues {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// queuedTasksLocked flattens the per-team queues, team by team. Callers must
+// hold c.mu.
+func (c *Captain) queuedTasksLocked() []*CaptainTask {
+	all := make([]*CaptainTask, 0)
+	for _, id := range sortedTeamIDs(c.taskQueues) {
+		all = append(all, c.taskQueues[id]...)
+	}
+	return all
+}
+
+// enqueueLocked appends task to its team's queue. Callers must hold c.mu.
+func (c *Captain) enqueueLocked(task *CaptainTask) {
+	if c.taskQueues == nil {
+		c.taskQueues = make(map[string][]*CaptainTask)
+	}
+	id := teamKey(task.TeamID)
+	c.taskQueues[id] = append(c.taskQueues[id], task)
+}
+
+// groupTasksByTeam splits a flat task list into per-team queues, keeping
+// each team's tasks in order
+func groupTasksByTeam(tasks []*CaptainTask) map[string][]*CaptainTask {
+	queues := make(map[string][]*CaptainTask)
+	for _, task := range tasks {
+		id := teamKey(task.TeamID)
+		queues[id] = append(queues[id], task)
+	}
+	return queues
+}
+
+// TeamConcurrency returns the team's concurrency limit from captain_context,
+// falling back to DefaultTeamConcurrency when none is stored or it is invalid
+func (c *Captain) TeamConcurrency(teamID string) int {
+	if c.memDB == nil {
+		return DefaultTeamConcurrency
+	}
+	key := TeamConcurrencyContextKeyPrefix + teamKey(teamID)
+	stored, err := c.memDB.GetContext(key)
+	if err != nil || stored == nil {
+		return DefaultTeamConcurrency
+	}
+	limit, err := strconv.Atoi(strings.TrimSpace(stored.Value))
+	if err != nil || limit < 1 {
+		logger.For("captain").Warn("ignoring invalid team concurrency context", "key", key, "value", stored.Value)
+		return DefaultTeamConcurrency
+	}
+	return limit
+}
+
+// SetTeamConcurrency stores a team's concurrency limit; it applies from the
+// next cycle
+func (c *Captain) SetTeamConcurrency(teamID string, limit int) error {
+	if c.memDB == nil {
+		return fmt.Errorf("memory database not configured")
+	}
+	if limit < 1 {
+		return fmt.Errorf("concurrency limit must be at least 1, got %d", limit)
+	}
+	return c.memDB.SetContext(TeamConcurrencyContextKeyPrefix+teamKey(teamID), strconv.Itoa(limit), 5, 0)
+}
+
+// PauseTeam stops the orchestration cycle from starting or advancing the
+// team's tasks. Tasks already executing run to completion.
+func (c *Captain) PauseTeam(teamID string) TeamQueueStatus {
+	id := teamKey(teamID)
+	c.mu.Lock()
+	if c.pausedTeams == nil {
+		c.pausedTeams = make(map[string]bool)
+	}
+	c.pausedTeams[id] = true
+	c.mu.Unlock()
+
+	logger.For("captain").Info("paused team", "team_id", id)
+	return c.TeamStatus(id)
+}
+
+// ResumeTeam lets the orchestration cycle process the team's queue again
+func (c *Captain) ResumeTeam(teamID string) TeamQueueStatus {
+	id := teamKey(teamID)
+	c.mu.Lock()
+	delete(c.pausedTeams, id)
+	c.mu.Unlock()
+
+	logger.For("captain").Info("resumed team", "team_id", id)
+	return c.TeamStatus(id)
+}
+
+// TeamPaused reports whether the team's queue is paused
+func (c *Captain) TeamPaused(teamID string) bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.pausedTeams[teamKey(teamID)]
+}
+
+// TeamStatus summarizes a single team's queue
+func (c *Captain) TeamStatus(teamID string) TeamQueueStatus {
+	id := teamKey(teamID)
+	c.mu.RLock()
+	status := TeamQueueStatus{
+		TeamID:     id,
+		QueueDepth: countQueued(c.taskQueues[id]),
+		Running:    c.teamRunning[id],
+		Paused:     c.pausedTeams[id],
+	}
+	c.mu.RUnlock()
+
+	status.ConcurrencyLimit = c.TeamConcurrency(id)
+	return status
+}
+
+// TeamStatuses summarizes every team with queued tasks or a pause in place,
+// ordered by team ID
+func (c *Captain) TeamStatuses() []TeamQueueStatus {
+	c.mu.RLock()
+	seen := make(map[string]bool, len(c.taskQueues)+len(c.pausedTeams))
+	for id := range c.taskQueues {
+		seen[id] = true
+	}
+	for id := range c.pausedTeams {
+		seen[id] = true
+	}
+	c.mu.RUnlock()
+
+	ids := make([]string, 0, len(seen))
+	for id := range seen {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	statuses := make([]TeamQueueStatus, 0, len(ids))
+	for _, id := range ids {
+		statuses = append(statuses, c.TeamStatus(id))
+	}
+	return statuses
+}
+
+// teamSlots returns how many more tasks each team may start this cycle:
+// its concurrency limit less the queued executions still running. Paused
+// teams get no slots.
+func (c *Captain) teamSlots(tasks []*CaptainTask) map[string]int {
+	slots := make(map[string]int)
+	for _, task := range tasks {
+		id := teamKey(task.TeamID)
+		if _, ok := slots[id]; ok {
+			continue
+		}
+		if c.TeamPaused(id) {
+			slots[id] = 0
+			continue
+		}
+		c.mu.RLock()
+		running := c.teamRunning[id]
+		c.mu.RUnlock()
+		if free := c.TeamConcurrency(id) - running; free > 0 {
+			slots[id] = free
+		} else {
+			slots[id] = 0
+		}
+	}
+	return slots
+}
+
+// trackTeamRunning adjusts the count of the team's queued executions in flight
+func (c *Captain) trackTeamRunning(teamID string, delta int) {
+	id := teamKey(teamID)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.teamRunning == nil {
+		c.teamRunning = make(map[string]int)
+	}
+	c.teamRunning[id] += delta
+	if c.teamRunning[id] <= 0 {
+		delete(c.teamRunning, id)
+	}
+}
+
+// countQueued counts tasks that still need work from the orchestration cycle
+func countQueued(tasks []*CaptainTask) int {
+	depth := 0
+	for _, task := range tasks {
+		switch task.Status {
+		case "pending", "recon_running", "recon_complete", "analyzing":
+			depth++
+		}
+	}
+	return depth
+}
@@ -0,0 +1,104 @@
+package captain
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/CLIAIMONITOR/internal/memory"
+)
+
+func TestEnqueueMission_TeamQueues(t *testing.T) {
+	c := NewCaptain(".", nil, nil, nil)
+	c.EnqueueMission(Mission{ID: "a", Title: "A"}, false)
+	blue := c.EnqueueMission(Mission{ID: "b", Title: "B", TeamID: "blue"}, false)
+	c.EnqueueMission(Mission{ID: "c", Title: "C", TeamID: "blue"}, false)
+
+	if blue.TeamID != "blue" {
+		t.Errorf("Expected team blue, got %q", blue.TeamID)
+	}
+
+	statuses := c.TeamStatuses()
+	if len(statuses) != 2 {
+		t.Fatalf("Expected 2 teams, got %+v", statuses)
+	}
+	if statuses[0].TeamID != "blue" || statuses[0].QueueDepth != 2 {
+		t.Errorf("Expected blue with depth 2, got %+v", statuses[0])
+	}
+	if statuses[1].TeamID != DefaultTeamID || statuses[1].QueueDepth != 1 {
+		t.Errorf("Expected %s with depth 1, got %+v", DefaultTeamID, statuses[1])
+	}
+
+	if _, err := c.RemoveTask("b"); err != nil {
+		t.Fatalf("RemoveTask(b) failed: %v", err)
+	}
+	if _, err := c.RemoveTask("b"); !errors.Is(err, ErrTaskNotFound) {
+		t.Errorf("Expected ErrTaskNotFound, got %v", err)
+	}
+	if got := c.TeamStatus("blue").QueueDepth; got != 1 {
+		t.Errorf("Expected blue depth 1 after removal, got %d", got)
+	}
+	if got := c.queueDepth(); got != 2 {
+		t.Errorf("queueDepth() = %d, want 2", got)
+	}
+}
+
+func TestTeamSlots(t *testing.T) {
+	db, err := memory.NewMemoryDB(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("Failed to create test database: %v", err)
+	}
+	defer db.Close()
+
+	c := NewCaptain(".", nil, db, nil)
+	if err := c.SetTeamConcurrency("blue", 1); err != nil {
+		t.Fatalf("SetTeamConcurrency failed: %v", err)
+	}
+	tasks := []*CaptainTask{
+		c.EnqueueMission(Mission{ID: "a", Title: "A"}, false),
+		c.EnqueueMission(Mission{ID: "b", Title: "B", TeamID: "blue"}, false),
+	}
+
+	slots := c.teamSlots(tasks)
+	if slots[DefaultTeamID] != DefaultTeamConcurrency || slots["blue"] != 1 {
+		t.Errorf("Unexpected slots %v", slots)
+	}
+
+	c.trackTeamRunning("blue", 1)
+	if slots := c.teamSlots(tasks); slots["blue"] != 0 {
+		t.Errorf("Expected blue to be at its limit, got %v", slots)
+	}
+	c.trackTeamRunning("blue", -1)
+
+	status := c.PauseTeam("")
+	if !status.Paused || status.TeamID != DefaultTeamID {
+		t.Errorf("Expected default team paused, got %+v", status)
+	}
+	if slots := c.teamSlots(tasks); slots[DefaultTeamID] != 0 || slots["blue"] != 1 {
+		t.Errorf("Expected paused team to get no slots, got %v", slots)
+	}
+
+	c.ResumeTeam(DefaultTeamID)
+	if slots := c.teamSlots(tasks); slots[DefaultTeamID] != DefaultTeamConcurrency {
+		t.Errorf("Expected resumed team to get slots back, got %v", slots)
+	}
+}
+
+func TestTeamConcurrency_InvalidContext(t *testing.T) {
+	db, err := memory.NewMemoryDB(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("Failed to create test database: %v", err)
+	}
+	defer db.Close()
+
+	if err := db.SetContext(TeamConcurrencyContextKeyPrefix+"red", "lots", 5, 0); err != nil {
+		t.Fatalf("SetContext failed: %v", err)
+	}
+	c := NewCaptain(".", nil, db, nil)
+	if got := c.TeamConcurrency("red"); got != DefaultTeamConcurrency {
+		t.Errorf("TeamConcurrency(red) = %d, want %d", got, DefaultTeamConcurrency)
+	}
+	if err := c.SetTeamConcurrency("red", 0); err == nil {
+		t.Error("Expected error for a zero concurrency limit")
+	}
+}
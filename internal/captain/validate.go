@@ -0,0 +1,27 @@
+package captain
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/CLIAIMONITOR/internal/types"
+)
+
+// ValidateMission checks that a mission carries every metadata key required
+// for its task type by types.MetadataSchema. Empty values count as missing.
+func ValidateMission(m Mission) error {
+	required := types.MetadataSchema[string(m.TaskType)]
+
+	var missing []string
+	for _, rk := range required {
+		if strings.TrimSpace(m.Metadata[rk.Key]) == "" {
+			missing = append(missing, rk.Key)
+		}
+	}
+
+	if len(missing) > 0 {
+		return fmt.Errorf("mission %s (%s) missing required metadata: %s",
+			m.ID, m.TaskType, strings.Join(missing, ", "))
+	}
+	return nil
+}
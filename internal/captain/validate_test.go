@@ -0,0 +1,30 @@
+package captain
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidateMission(t *testing.T) {
+	complete := map[string]string{
+		"source":               "planner",
+		"estimated_effort":     "2h",
+		"agent_recommendation": "go-developer",
+	}
+
+	if err := ValidateMission(Mission{ID: "m1", TaskType: TaskPlanning, Metadata: complete}); err != nil {
+		t.Errorf("expected complete planning mission to pass, got %v", err)
+	}
+
+	err := ValidateMission(Mission{ID: "m2", TaskType: TaskPlanning, Metadata: map[string]string{"source": "planner", "estimated_effort": " "}})
+	if err == nil {
+		t.Fatal("expected error for incomplete planning mission")
+	}
+	if !strings.Contains(err.Error(), "estimated_effort") || !strings.Contains(err.Error(), "agent_recommendation") {
+		t.Errorf("expected missing keys listed, got %v", err)
+	}
+
+	if err := ValidateMission(Mission{ID: "m3", TaskType: TaskImplementation}); err != nil {
+		t.Errorf("task types without a schema should pass, got %v", err)
+	}
+}
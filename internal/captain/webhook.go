@@ -0,0 +1,140 @@
+package captain
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/CLIAIMONITOR/internal/logger"
+)
+
+// Environment variables used to configure task status webhooks
+const (
+	EnvTaskWebhookURL    = "CLIAIMONITOR_TASK_WEBHOOK_URL"
+	EnvTaskWebhookSecret = "CLIAIMONITOR_TASK_WEBHOOK_SECRET"
+)
+
+// TaskWebhookSignatureHeader carries the HMAC-SHA256 signature of the payload.
+// The format matches GitHub webhooks: "sha256=<hex digest>".
+const TaskWebhookSignatureHeader = "X-Signature"
+
+// TaskWebhookPayload is the JSON body sent on each task status transition
+type TaskWebhookPayload struct {
+	TaskID       string    `json:"task_id"`
+	OldStatus    string    `json:"old_status"`
+	NewStatus    string    `json:"new_status"`
+	Timestamp    time.Time `json:"timestamp"`
+	MissionTitle string    `json:"mission_title"`
+}
+
+// TaskWebhookDispatcher POSTs signed task status transitions to an external URL
+type TaskWebhookDispatcher struct {
+	url    string
+	secret string
+	client *http.Client
+}
+
+// NewTaskWebhookDispatcher creates a dispatcher for the given URL and secret
+func NewTaskWebhookDispatcher(url, secret string) *TaskWebhookDispatcher {
+	return &TaskWebhookDispatcher{
+		url:    url,
+		secret: secret,
+		client: &http.Client{
+			Timeout: 10 * time.Second,
+		},
+	}
+}
+
+// NewTaskWebhookDispatcherFromEnv creates a dispatcher from CLIAIMONITOR_TASK_WEBHOOK_*
+// environment variables. Returns nil if no webhook URL is configured.
+func NewTaskWebhookDispatcherFromEnv() *TaskWebhookDispatcher {
+	url := os.Getenv(EnvTaskWebhookURL)
+	if url == "" {
+		return nil
+	}
+	return NewTaskWebhookDispatcher(url, os.Getenv(EnvTaskWebhookSecret))
+}
+
+// Enabled reports whether the dispatcher has both a URL and a signing secret.
+// Unsigned payloads are never sent.
+func (d *TaskWebhookDispatcher) Enabled() bool {
+	return d != nil && d.url != "" && d.secret != ""
+}
+
+// Send POSTs a signed payload and waits for the response
+func (d *TaskWebhookDispatcher) Send(payload TaskWebhookPayload) error {
+	if !d.Enabled() {
+		return fmt.Errorf("task webhook not configured (URL and secret required)")
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal webhook payload: %w", err)
+	}
+
+	return postSignedWebhook(d.client, d.url, d.secret, body)
+}
+
+// postSignedWebhook POSTs body with its HMAC signature and checks for a 2xx
+func postSignedWebhook(client *http.Client, url, secret string, body []byte) error {
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create webhook request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(TaskWebhookSignatureHeader, SignWebhookPayload(secret, body))
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send webhook: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook returned status %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+// DispatchTransition sends a status transition in the background so the
+// orchestration cycle is never blocked by a slow endpoint
+func (d *TaskWebhookDispatcher) DispatchTransition(task *CaptainTask, oldStatus, newStatus string) {
+	if !d.Enabled() || task == nil || oldStatus == newStatus {
+		return
+	}
+
+	payload := TaskWebhookPayload{
+		TaskID:       task.Mission.ID,
+		OldStatus:    oldStatus,
+		NewStatus:    newStatus,
+		Timestamp:    time.Now().UTC(),
+		MissionTitle: task.Mission.Title,
+	}
+
+	go func() {
+		if err := d.Send(payload); err != nil {
+			logger.For("captain").Warn("task webhook failed", "task_id", payload.TaskID, "error", err)
+		}
+	}()
+}
+
+// SignWebhookPayload returns the "sha256=<hex>" HMAC signature for body
+func SignWebhookPayload(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// VerifyWebhookSignature checks a signature header against body using a
+// constant-time comparison. Provided for receivers written in Go.
+func VerifyWebhookSignature(secret string, body []byte, signature string) bool {
+	expected := SignWebhookPayload(secret, body)
+	return hmac.Equal([]byte(expected), []byte(signature))
+}
@@ -0,0 +1,71 @@
+package captain
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestSignWebhookPayload(t *testing.T) {
+	body := []byte(`{"task_id":"t1"}`)
+	sig := SignWebhookPayload("secret", body)
+
+	if len(sig) != len("sha256=")+64 {
+		t.Fatalf("unexpected signature length: %s", sig)
+	}
+	if !VerifyWebhookSignature("secret", body, sig) {
+		t.Error("signature should verify with the same secret")
+	}
+	if VerifyWebhookSignature("other", body, sig) {
+		t.Error("signature should not verify with a different secret")
+	}
+}
+
+func TestTaskWebhookDispatcher_Send(t *testing.T) {
+	var gotSig string
+	var gotPayload TaskWebhookPayload
+	var gotBody []byte
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotSig = r.Header.Get(TaskWebhookSignatureHeader)
+		gotBody, _ = io.ReadAll(r.Body)
+		json.Unmarshal(gotBody, &gotPayload)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	d := NewTaskWebhookDispatcher(srv.URL, "s3cret")
+	err := d.Send(TaskWebhookPayload{
+		TaskID:       "task-1",
+		OldStatus:    "pending",
+		NewStatus:    "recon_running",
+		MissionTitle: "Scan repo",
+	})
+	if err != nil {
+		t.Fatalf("Send failed: %v", err)
+	}
+
+	if gotPayload.TaskID != "task-1" || gotPayload.NewStatus != "recon_running" {
+		t.Errorf("unexpected payload: %+v", gotPayload)
+	}
+	if !VerifyWebhookSignature("s3cret", gotBody, gotSig) {
+		t.Errorf("signature %q did not verify", gotSig)
+	}
+}
+
+func TestTaskWebhookDispatcher_RequiresSecret(t *testing.T) {
+	d := NewTaskWebhookDispatcher("http://localhost:1", "")
+	if d.Enabled() {
+		t.Error("dispatcher without secret should be disabled")
+	}
+	if err := d.Send(TaskWebhookPayload{TaskID: "t"}); err == nil {
+		t.Error("expected error when secret is empty")
+	}
+
+	var nilDispatcher *TaskWebhookDispatcher
+	if nilDispatcher.Enabled() {
+		t.Error("nil dispatcher should be disabled")
+	}
+}
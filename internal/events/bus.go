@@ -1,10 +1,11 @@
 package events
 
 import (
-	"log"
 	"sync"
 	"sync/atomic"
 	"time"
+
+	"github.com/CLIAIMONITOR/internal/logger"
 )
 
 // Subscription represents a subscription to events
@@ -104,14 +105,43 @@ func (b *Bus) Publish(event *Event) {
 	// Persist to store if available
 	if b.store != nil {
 		if err := b.store.Save(event); err != nil {
-			log.Printf("[EVENTS] ERROR: Failed to persist event to store: type=%s, target=%s, id=%s, error=%v",
-				event.Type, event.Target, event.ID, err)
+			logger.For("events").Error("failed to persist event to store",
+				"type", event.Type, "target", event.Target, "id", event.ID, "error", err)
 		}
 	}
 
 	b.mu.RLock()
 	defer b.mu.RUnlock()
 
+	for _, sub := range b.matchingSubscriptions(event) {
+		b.sendWithBackpressure(sub, event)
+	}
+}
+
+// PublishTransient sends an event to the subscribers of its target without
+// persisting it. Subscribers whose channel is full miss the event rather
+// than slowing the publisher, so it suits high-volume live data such as
+// streamed task output. Catch-all "all" subscribers, such as notification
+// routing, do not receive transient events unless the event targets "all".
+func (b *Bus) PublishTransient(event *Event) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	for _, sub := range b.matchingSubscriptions(event) {
+		if sub.Target == "all" && event.Target != "all" {
+			continue
+		}
+		select {
+		case sub.Ch <- *event:
+		default:
+			atomic.AddUint64(&b.droppedEvents, 1)
+		}
+	}
+}
+
+// matchingSubscriptions returns the subscriptions an event is delivered to.
+// Callers must hold b.mu.
+func (b *Bus) matchingSubscriptions(event *Event) []*Subscription {
 	// Collect all matching subscriptions
 	var targetSubs []*Subscription
 
@@ -132,12 +162,13 @@ func (b *Bus) Publish(event *Event) {
 		}
 	}
 
-	// Send to all matching subscriptions
+	var matched []*Subscription
 	for _, sub := range targetSubs {
 		if b.matchesTypes(event.Type, sub.Types) {
-			b.sendWithBackpressure(sub, event)
+			matched = append(matched, sub)
 		}
 	}
+	return matched
 }
 
 // sendWithBackpressure attempts to send an event to a subscriber with retries.
@@ -157,8 +188,8 @@ func (b *Bus) sendWithBackpressure(sub *Subscription, event *Event) {
 		time.Sleep(BackpressureRetryDelay)
 		select {
 		case sub.Ch <- *event:
-			log.Printf("[EVENTS] Event delivered after %d retry(ies): type=%s, target=%s, id=%s",
-				retry, event.Type, event.Target, event.ID)
+			logger.For("events").Debug("event delivered after retry",
+				"retries", retry, "type", event.Type, "target", event.Target, "id", event.ID)
 			return
 		default:
 			// Still full, continue retrying
@@ -167,8 +198,9 @@ func (b *Bus) sendWithBackpressure(sub *Subscription, event *Event) {
 
 	// All retries exhausted, drop the event
 	dropped := atomic.AddUint64(&b.droppedEvents, 1)
-	log.Printf("[EVENTS] WARNING: Dropped event after %d retries (channel full): type=%s, target=%s, source=%s, id=%s (total dropped: %d)",
-		MaxBackpressureRetries, event.Type, event.Target, event.Source, event.ID, dropped)
+	logger.For("events").Warn("dropped event after retries (channel full)",
+		"retries", MaxBackpressureRetries, "type", event.Type, "target", event.Target,
+		"source", event.Source, "id", event.ID, "total_dropped", dropped)
 }
 
 // GetPendingEvents retrieves pending events from the store for a specific target
@@ -304,3 +304,64 @@ func TestBus_FullChannelNonBlocking(t *testing.T) {
 	// Cleanup
 	bus.Unsubscribe("agent-1", ch)
 }
+
+// countingStore records how many events were saved
+type countingStore struct {
+	saved int
+}
+
+func (s *countingStore) Save(event *Event) error { s.saved++; return nil }
+func (s *countingStore) GetPending(target string, types []EventType) ([]*Event, error) {
+	return nil, nil
+}
+func (s *countingStore) MarkDelivered(eventID string) error { return nil }
+
+func TestBus_PublishTransient(t *testing.T) {
+	store := &countingStore{}
+	bus := NewBus(store)
+	ch := bus.Subscribe("agent-1", []EventType{EventMessage})
+	defer bus.Unsubscribe("agent-1", ch)
+
+	start := time.Now()
+	for i := 0; i < EventChannelBufferSize+5; i++ {
+		bus.PublishTransient(NewEvent(EventMessage, "captain", "agent-1", PriorityLow, map[string]interface{}{
+			"index": i,
+		}))
+	}
+
+	// A full channel drops events straight away instead of retrying
+	if elapsed := time.Since(start); elapsed >= BackpressureRetryDelay {
+		t.Errorf("PublishTransient waited on a full channel (%v)", elapsed)
+	}
+	if store.saved != 0 {
+		t.Errorf("Expected transient events not to be persisted, got %d saved", store.saved)
+	}
+	if len(ch) != EventChannelBufferSize {
+		t.Errorf("Expected %d buffered events, got %d", EventChannelBufferSize, len(ch))
+	}
+	if dropped := bus.DroppedEventCount(); dropped != 5 {
+		t.Errorf("Expected 5 dropped events, got %d", dropped)
+	}
+}
+
+func TestBus_PublishTransientSkipsCatchAll(t *testing.T) {
+	bus := NewBus(nil)
+	all := bus.Subscribe("all", nil)
+	defer bus.Unsubscribe("all", all)
+	target := bus.Subscribe("captain_output", nil)
+	defer bus.Unsubscribe("captain_output", target)
+
+	bus.PublishTransient(NewEvent(EventMessage, "captain", "captain_output", PriorityLow, nil))
+	if len(target) != 1 {
+		t.Errorf("Expected the target subscriber to get the event, got %d", len(target))
+	}
+	if len(all) != 0 {
+		t.Errorf("Expected catch-all subscribers to skip transient events, got %d", len(all))
+	}
+
+	// Persistent events still reach catch-all subscribers
+	bus.Publish(NewEvent(EventMessage, "captain", "captain_output", PriorityLow, nil))
+	if len(all) != 1 {
+		t.Errorf("Expected the catch-all subscriber to get a published event, got %d", len(all))
+	}
+}
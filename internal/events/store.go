@@ -1,14 +1,19 @@
 package events
 
 import (
+	"context"
 	"database/sql"
 	"encoding/json"
 	"fmt"
+	"strings"
 	"time"
 
 	_ "modernc.org/sqlite"
 )
 
+// EventHistoryLimit caps how many events GetEventsByTimeRange returns
+const EventHistoryLimit = 200
+
 // SQLiteStore implements EventStore using SQLite
 type SQLiteStore struct {
 	db *sql.DB
@@ -41,6 +46,7 @@ func (s *SQLiteStore) initSchema() error {
 
 	CREATE INDEX IF NOT EXISTS idx_events_target ON events(target, delivered_at);
 	CREATE INDEX IF NOT EXISTS idx_events_type ON events(type);
+	CREATE INDEX IF NOT EXISTS idx_events_created_target ON events(created_at, target);
 	`
 
 	_, err := s.db.Exec(schema)
@@ -71,7 +77,7 @@ func (s *SQLiteStore) Save(event *Event) error {
 		event.Target,
 		event.Priority,
 		string(payloadJSON),
-		event.CreatedAt,
+		event.CreatedAt.Round(0), // Drop the monotonic reading so range bounds compare cleanly
 	)
 
 	if err != nil {
@@ -181,6 +187,64 @@ func (s *SQLiteStore) GetPending(target string, types []EventType) ([]*Event, er
 	return events, nil
 }
 
+// GetEventsByTimeRange returns delivered and pending events created between
+// from and to, oldest first, up to EventHistoryLimit. A zero from or to
+// leaves that end open. A non-empty target matches events for that target
+// or "all"; empty eventTypes matches every type.
+func (s *SQLiteStore) GetEventsByTimeRange(ctx context.Context, from, to time.Time, target string, eventTypes []EventType) ([]*Event, error) {
+	var conditions []string
+	var args []interface{}
+	// created_at is stored as local time text, so bounds must be local too
+	if !from.IsZero() {
+		conditions = append(conditions, "created_at >= ?")
+		args = append(args, from.Local())
+	}
+	if !to.IsZero() {
+		conditions = append(conditions, "created_at <= ?")
+		args = append(args, to.Local())
+	}
+	if target != "" {
+		conditions = append(conditions, "(target = ? OR target = 'all')")
+		args = append(args, target)
+	}
+	if len(eventTypes) > 0 {
+		conditions = append(conditions, "type IN (?"+strings.Repeat(", ?", len(eventTypes)-1)+")")
+		for _, eventType := range eventTypes {
+			args = append(args, string(eventType))
+		}
+	}
+
+	query := `SELECT id, type, source, target, priority, payload, created_at FROM events`
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+	query += " ORDER BY created_at ASC LIMIT ?"
+	args = append(args, EventHistoryLimit)
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query event history: %w", err)
+	}
+	defer rows.Close()
+
+	events := []*Event{}
+	for rows.Next() {
+		var event Event
+		var payloadJSON string
+		if err := rows.Scan(&event.ID, &event.Type, &event.Source, &event.Target, &event.Priority, &payloadJSON, &event.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan event row: %w", err)
+		}
+		if err := json.Unmarshal([]byte(payloadJSON), &event.Payload); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal payload: %w", err)
+		}
+		events = append(events, &event)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %w", err)
+	}
+	return events, nil
+}
+
 // MarkDelivered marks an event as delivered by setting its delivered_at timestamp
 func (s *SQLiteStore) MarkDelivered(eventID string) error {
 	query := `UPDATE events SET delivered_at = ? WHERE id = ?`
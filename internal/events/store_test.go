@@ -1,6 +1,7 @@
 package events
 
 import (
+	"context"
 	"database/sql"
 	"testing"
 	"time"
@@ -261,3 +262,64 @@ func TestSQLiteStore_Cleanup(t *testing.T) {
 		t.Errorf("expected new event to still exist, but count is %d", count)
 	}
 }
+
+func TestSQLiteStore_GetEventsByTimeRange(t *testing.T) {
+	store := setupTestDB(t)
+	base := time.Now().Add(-time.Hour)
+
+	for i, spec := range []struct {
+		eventType EventType
+		target    string
+	}{
+		{EventMessage, "agent-1"},
+		{EventAlert, "agent-2"},
+		{EventMessage, "all"},
+		{EventTask, "agent-1"},
+		{EventMessage, "agent-1"},
+	} {
+		event := NewEvent(spec.eventType, "captain", spec.target, PriorityNormal, map[string]interface{}{"seq": i})
+		event.CreatedAt = base.Add(time.Duration(i) * 10 * time.Minute)
+		if err := store.Save(event); err != nil {
+			t.Fatalf("Save failed: %v", err)
+		}
+	}
+	// Delivered events are still part of the history
+	all, _ := store.GetPending("agent-1", nil)
+	if err := store.MarkDelivered(all[0].ID); err != nil {
+		t.Fatalf("MarkDelivered failed: %v", err)
+	}
+
+	ctx := context.Background()
+	got, err := store.GetEventsByTimeRange(ctx, time.Time{}, time.Time{}, "", nil)
+	if err != nil {
+		t.Fatalf("GetEventsByTimeRange failed: %v", err)
+	}
+	if len(got) != 5 || got[0].Payload["seq"] != float64(0) || got[4].Payload["seq"] != float64(4) {
+		t.Fatalf("expected all 5 events oldest first, got %d", len(got))
+	}
+
+	// 10m..30m covers seq 1-3; agent-1 also sees the "all" broadcast
+	got, err = store.GetEventsByTimeRange(ctx, base.Add(10*time.Minute), base.Add(30*time.Minute), "agent-1", nil)
+	if err != nil {
+		t.Fatalf("GetEventsByTimeRange failed: %v", err)
+	}
+	if len(got) != 2 || got[0].Target != "all" || got[1].Type != EventTask {
+		t.Errorf("unexpected events for agent-1 in range: %+v", got)
+	}
+
+	got, err = store.GetEventsByTimeRange(ctx, base, time.Time{}, "agent-1", []EventType{EventMessage})
+	if err != nil {
+		t.Fatalf("GetEventsByTimeRange failed: %v", err)
+	}
+	if len(got) != 3 {
+		t.Errorf("expected 3 message events for agent-1, got %d", len(got))
+	}
+
+	got, err = store.GetEventsByTimeRange(ctx, base.Add(2*time.Hour), time.Time{}, "", nil)
+	if err != nil {
+		t.Fatalf("GetEventsByTimeRange failed: %v", err)
+	}
+	if got == nil || len(got) != 0 {
+		t.Errorf("expected empty non-nil slice, got %v", got)
+	}
+}
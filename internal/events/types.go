@@ -11,12 +11,19 @@ type EventType string
 
 // Event type constants
 const (
-	EventMessage      EventType = "message"
-	EventAgentSignal  EventType = "agent_signal"
-	EventAlert        EventType = "alert"
-	EventTask         EventType = "task"
-	EventRecon        EventType = "recon"
-	EventStopApproval EventType = "stop_approval" // Response to stop approval request
+	EventMessage             EventType = "message"
+	EventAgentSignal         EventType = "agent_signal"
+	EventAlert               EventType = "alert"
+	EventTask                EventType = "task"
+	EventRecon               EventType = "recon"
+	EventStopApproval        EventType = "stop_approval"         // Response to stop approval request
+	EventReviewCompleted     EventType = "review_completed"      // Review board reached completed status
+	EventContextExpiring     EventType = "context_expiring"      // High-priority Captain context is about to expire
+	EventScanProgress        EventType = "scan_progress"         // Progress of a running recon scan
+	EventDBIntegrityError    EventType = "db_integrity_error"    // Memory database failed PRAGMA integrity_check
+	EventEscalationResolved  EventType = "escalation_resolved"   // A Captain escalation was resolved by a human
+	EventConfigChanged       EventType = "config_changed"        // teams.yaml was reloaded with changes
+	EventSubagentOutputChunk EventType = "subagent_output_chunk" // Live output from a running Captain task
 )
 
 // Priority constants for events
@@ -60,5 +67,12 @@ func AllEventTypes() []EventType {
 		EventTask,
 		EventRecon,
 		EventStopApproval,
+		EventReviewCompleted,
+		EventContextExpiring,
+		EventScanProgress,
+		EventDBIntegrityError,
+		EventEscalationResolved,
+		EventConfigChanged,
+		EventSubagentOutputChunk,
 	}
 }
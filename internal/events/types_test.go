@@ -18,6 +18,7 @@ func TestEventType_String(t *testing.T) {
 		{"Alert event", EventAlert, "alert"},
 		{"Task event", EventTask, "task"},
 		{"Recon event", EventRecon, "recon"},
+		{"Review completed event", EventReviewCompleted, "review_completed"},
 	}
 
 	for _, tt := range tests {
@@ -151,7 +152,7 @@ func TestNewEvent(t *testing.T) {
 func TestAllEventTypes(t *testing.T) {
 	types := AllEventTypes()
 
-	expectedCount := 6
+	expectedCount := 13
 	if len(types) != expectedCount {
 		t.Errorf("AllEventTypes returned %d types, want %d", len(types), expectedCount)
 	}
@@ -169,6 +170,13 @@ func TestAllEventTypes(t *testing.T) {
 		EventTask,
 		EventRecon,
 		EventStopApproval,
+		EventReviewCompleted,
+		EventContextExpiring,
+		EventScanProgress,
+		EventDBIntegrityError,
+		EventEscalationResolved,
+		EventConfigChanged,
+		EventSubagentOutputChunk,
 	}
 
 	for _, expected := range expectedTypes {
@@ -3,21 +3,20 @@ package handlers
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"net/http"
 	"strings"
 	"time"
 
 	"github.com/CLIAIMONITOR/internal/captain"
 	"github.com/CLIAIMONITOR/internal/persistence"
+	"github.com/CLIAIMONITOR/internal/tasks"
 	"github.com/CLIAIMONITOR/internal/types"
 	"github.com/gorilla/mux"
 )
 
-// MaxPayloadSize defines the maximum size for request payloads (1MB)
-// This prevents DoS attacks via large payloads
-const MaxPayloadSize = 1 * 1024 * 1024 // 1MB
-
 // Mission execution timeout constants
 const (
 	// MissionExecutionTimeout is the timeout for executing a single mission
@@ -28,11 +27,6 @@ const (
 	ReconExecutionTimeout = 15 * time.Minute
 )
 
-// limitRequestSize limits the request body size to prevent DoS via large payloads
-func limitRequestSize(r *http.Request, maxSize int64) {
-	r.Body = http.MaxBytesReader(nil, r.Body, maxSize)
-}
-
 // CaptainHandler handles Captain orchestration endpoints
 type CaptainHandler struct {
 	captain *captain.Captain
@@ -54,9 +48,6 @@ func (h *CaptainHandler) HandleDecideMode(w http.ResponseWriter, r *http.Request
 		return
 	}
 
-	// Limit request size to prevent DoS
-	limitRequestSize(r, MaxPayloadSize)
-
 	var mission captain.Mission
 	if err := json.NewDecoder(r.Body).Decode(&mission); err != nil {
 		http.Error(w, "Invalid request body", http.StatusBadRequest)
@@ -76,14 +67,15 @@ func (h *CaptainHandler) HandleExecuteMission(w http.ResponseWriter, r *http.Req
 		return
 	}
 
-	// Limit request size to prevent DoS
-	limitRequestSize(r, MaxPayloadSize)
-
 	var mission captain.Mission
 	if err := json.NewDecoder(r.Body).Decode(&mission); err != nil {
 		http.Error(w, "Invalid request body", http.StatusBadRequest)
 		return
 	}
+	if err := defaultMissionSource(&mission); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
 
 	// Use context with timeout for subagent execution
 	ctx, cancel := context.WithTimeout(r.Context(), MissionExecutionTimeout)
@@ -106,9 +98,6 @@ func (h *CaptainHandler) HandleExecuteParallel(w http.ResponseWriter, r *http.Re
 		return
 	}
 
-	// Limit request size to prevent DoS
-	limitRequestSize(r, MaxPayloadSize)
-
 	var request struct {
 		Missions []captain.Mission `json:"missions"`
 	}
@@ -121,6 +110,12 @@ func (h *CaptainHandler) HandleExecuteParallel(w http.ResponseWriter, r *http.Re
 		http.Error(w, "No missions provided", http.StatusBadRequest)
 		return
 	}
+	for i := range request.Missions {
+		if err := defaultMissionSource(&request.Missions[i]); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
 
 	// Use context with timeout
 	ctx, cancel := context.WithTimeout(r.Context(), ParallelMissionsTimeout)
@@ -185,6 +180,42 @@ func (h *CaptainHandler) HandleActiveSubagents(w http.ResponseWriter, r *http.Re
 	})
 }
 
+// HandleGetModels lists the effective subagent model per agent type and
+// where it comes from (environment, config or default)
+func (h *CaptainHandler) HandleGetModels(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	models := h.captain.ModelAssignments()
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"models": models,
+		"count":  len(models),
+	})
+}
+
+// HandleClaudeStatus reports whether the Claude CLI used for subagents is
+// accessible. Pass ?refresh=true to re-run the check.
+func (h *CaptainHandler) HandleClaudeStatus(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var status captain.ClaudeStatus
+	if r.URL.Query().Get("refresh") == "true" {
+		status = h.captain.RefreshClaudeStatus(r.Context())
+	} else {
+		status = h.captain.ClaudeStatus(r.Context())
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(status)
+}
+
 // HandleSetAPIKey sets the Planner API key
 func (h *CaptainHandler) HandleSetAPIKey(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
@@ -192,9 +223,6 @@ func (h *CaptainHandler) HandleSetAPIKey(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	// Limit request size to prevent DoS
-	limitRequestSize(r, MaxPayloadSize)
-
 	var request struct {
 		APIKey string `json:"api_key"`
 	}
@@ -216,6 +244,53 @@ func (h *CaptainHandler) HandleSetAPIKey(w http.ResponseWriter, r *http.Request)
 	})
 }
 
+// HandleOAuthRefresh forces a new Planner OAuth2 token
+func (h *CaptainHandler) HandleOAuthRefresh(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	expiry, err := h.captain.RefreshPlannerToken(r.Context())
+	if errors.Is(err, captain.ErrPlannerOAuthNotConfigured) {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"status":     "Token refreshed",
+		"expires_at": expiry,
+	})
+}
+
+// HandlePlannerProxy forwards a request to the Planner API, adding the
+// configured API key or OAuth2 token, so planning agents never hold Planner
+// credentials
+func (h *CaptainHandler) HandlePlannerProxy(w http.ResponseWriter, r *http.Request) {
+	path := mux.Vars(r)["path"]
+	if r.URL.RawQuery != "" {
+		path += "?" + r.URL.RawQuery
+	}
+
+	resp, err := h.captain.PlannerRequest(r.Context(), r.Method, path, r.Body, r.Header.Get("Content-Type"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+	defer resp.Body.Close()
+
+	if ct := resp.Header.Get("Content-Type"); ct != "" {
+		w.Header().Set("Content-Type", ct)
+	}
+	w.WriteHeader(resp.StatusCode)
+	io.Copy(w, resp.Body)
+}
+
 // HandleRecon creates and executes a reconnaissance mission
 func (h *CaptainHandler) HandleRecon(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
@@ -223,9 +298,6 @@ func (h *CaptainHandler) HandleRecon(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Limit request size to prevent DoS
-	limitRequestSize(r, MaxPayloadSize)
-
 	var request struct {
 		ProjectPath string `json:"project_path"`
 		Title       string `json:"title"`
@@ -246,6 +318,7 @@ func (h *CaptainHandler) HandleRecon(w http.ResponseWriter, r *http.Request) {
 		Metadata: map[string]string{
 			"focus": request.Focus,
 		},
+		SourceType: captain.SourceAPI,
 	}
 
 	ctx, cancel := context.WithTimeout(r.Context(), ReconExecutionTimeout)
@@ -261,6 +334,37 @@ func (h *CaptainHandler) HandleRecon(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(result)
 }
 
+// defaultMissionSource marks missions pushed through the API with the "api"
+// source type and rejects unknown source types
+func defaultMissionSource(mission *captain.Mission) error {
+	if mission.SourceType == "" {
+		mission.SourceType = captain.SourceAPI
+	}
+	if !captain.ValidSourceType(mission.SourceType) {
+		return fmt.Errorf("invalid source_type %q: must be api, github, json_file or internal", mission.SourceType)
+	}
+	return nil
+}
+
+// defaultMissionMetadata fills in any metadata the mission's task type
+// requires but the request left out, recommending agentType
+func defaultMissionMetadata(mission *captain.Mission, agentType string) {
+	defaults := map[string]string{
+		"source":               mission.SourceType,
+		"estimated_effort":     "unknown",
+		"agent_recommendation": agentType,
+	}
+	for _, rk := range types.MetadataSchema[string(mission.TaskType)] {
+		if strings.TrimSpace(mission.Metadata[rk.Key]) != "" || defaults[rk.Key] == "" {
+			continue
+		}
+		if mission.Metadata == nil {
+			mission.Metadata = make(map[string]string)
+		}
+		mission.Metadata[rk.Key] = defaults[rk.Key]
+	}
+}
+
 // reconFocusDescriptions maps recon focus areas to detailed descriptions
 var reconFocusDescriptions = map[string]string{
 	"security":     "Focus on security vulnerabilities: OWASP Top 10, command injection, SQL injection, XSS, authentication/authorization issues, secrets in code, input validation.",
@@ -287,14 +391,20 @@ type SubmitTaskRequest struct {
 	ProjectPath string            `json:"project_path,omitempty"`
 	Priority    int               `json:"priority,omitempty"`
 	NeedsRecon  bool              `json:"needs_recon,omitempty"`
+	TaskType    string            `json:"task_type,omitempty"` // inferred from title and description when empty
 	Metadata    map[string]string `json:"metadata,omitempty"`
+	SourceType  string            `json:"source_type,omitempty"` // defaults to "api"
+	SourceRef   string            `json:"source_ref,omitempty"`
+	Immediate   bool              `json:"immediate,omitempty"` // run a cycle now instead of waiting for the next tick
+	TeamID      string            `json:"team_id,omitempty"`   // team queue; defaults to captain.DefaultTeamID
 }
 
 // SubmitTaskResponse is the response after submitting a task
 type SubmitTaskResponse struct {
-	TaskID  string `json:"task_id"`
-	Status  string `json:"status"`
-	Message string `json:"message"`
+	TaskID  string               `json:"task_id"`
+	Status  string               `json:"status"`
+	Message string               `json:"message"`
+	Task    *captain.CaptainTask `json:"task,omitempty"`
 }
 
 // HandleSubmitTask submits a task to Captain
@@ -304,9 +414,6 @@ func (h *CaptainHandler) HandleSubmitTask(w http.ResponseWriter, r *http.Request
 		return
 	}
 
-	// Limit request size to prevent DoS
-	limitRequestSize(r, MaxPayloadSize)
-
 	var req SubmitTaskRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		http.Error(w, "Invalid request body", http.StatusBadRequest)
@@ -322,9 +429,22 @@ func (h *CaptainHandler) HandleSubmitTask(w http.ResponseWriter, r *http.Request
 		http.Error(w, "Description is required", http.StatusBadRequest)
 		return
 	}
+	if req.Priority == 0 {
+		req.Priority = tasks.DefaultPriority
+	}
+	if !tasks.ValidPriority(req.Priority) {
+		http.Error(w, "Priority must be between 1 and 7", http.StatusBadRequest)
+		return
+	}
 
-	// Infer task type based on title/description
-	taskType := inferTaskTypeFromRequest(req.Title, req.Description, req.NeedsRecon)
+	// Use the caller's task type, or infer one from title/description
+	taskType := captain.TaskType(req.TaskType)
+	if taskType == "" {
+		taskType = inferTaskTypeFromRequest(req.Title, req.Description, req.NeedsRecon)
+	} else if !captain.ValidTaskType(taskType) {
+		http.Error(w, fmt.Sprintf("invalid task_type %q: must be recon, analysis, implementation, testing or planning", req.TaskType), http.StatusBadRequest)
+		return
+	}
 
 	// Create mission
 	mission := captain.Mission{
@@ -335,53 +455,47 @@ func (h *CaptainHandler) HandleSubmitTask(w http.ResponseWriter, r *http.Request
 		ProjectPath: req.ProjectPath,
 		Priority:    req.Priority,
 		Metadata:    req.Metadata,
+		SourceType:  req.SourceType,
+		SourceRef:   req.SourceRef,
+		TeamID:      req.TeamID,
+	}
+	if err := defaultMissionSource(&mission); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	// Keyword inference is loose, so a mission it guessed to be planning gets
+	// default metadata; a caller who asked for the type must supply it, and
+	// is told now rather than once the task runs
+	if req.TaskType == "" {
+		defaultMissionMetadata(&mission, h.captain.DecideMode(mission).AgentType)
+	}
+	if err := captain.ValidateMission(mission); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
 	}
 
-	// Execute mission asynchronously
-	ctx, cancel := context.WithTimeout(context.Background(), ParallelMissionsTimeout)
-
-	go func() {
-		defer func() {
-			if r := recover(); r != nil {
-				// Log panic to activity log
-				h.store.AddActivity(&types.ActivityLog{
-					ID:        fmt.Sprintf("activity-%d", time.Now().UnixNano()),
-					AgentID:   "Captain",
-					Action:    "task_panic",
-					Details:   fmt.Sprintf("Task %s panicked: %v", mission.ID, r),
-					Timestamp: time.Now(),
-				})
-			}
-		}()
-		defer cancel() // Cancel when goroutine completes
+	// Queue the mission; the orchestration cycle runs recon and execution
+	task := h.captain.EnqueueMission(mission, req.NeedsRecon)
 
-		result, err := h.captain.ExecuteMission(ctx, mission)
-		if err != nil {
-			// Log error to activity log
-			h.store.AddActivity(&types.ActivityLog{
-				ID:        fmt.Sprintf("activity-%d", time.Now().UnixNano()),
-				AgentID:   "Captain",
-				Action:    "task_failed",
-				Details:   fmt.Sprintf("Task %s failed: %v", mission.ID, err),
-				Timestamp: time.Now(),
-			})
-			return
-		}
+	h.store.AddActivity(&types.ActivityLog{
+		ID:        fmt.Sprintf("activity-%d", time.Now().UnixNano()),
+		AgentID:   "Captain",
+		Action:    "task_queued",
+		Details:   fmt.Sprintf("Task %s queued: %s", mission.ID, mission.Title),
+		Timestamp: time.Now(),
+	})
 
-		// Log successful completion
-		h.store.AddActivity(&types.ActivityLog{
-			ID:        fmt.Sprintf("activity-%d", time.Now().UnixNano()),
-			AgentID:   result.AgentID,
-			Action:    "task_completed",
-			Details:   fmt.Sprintf("Task %s completed: %s", mission.ID, result.Status),
-			Timestamp: time.Now(),
-		})
-	}()
+	message := "Task queued and will run on the next Captain cycle"
+	if req.Immediate {
+		h.captain.TriggerCycle()
+		message = "Task queued and a Captain cycle was started"
+	}
 
 	response := SubmitTaskResponse{
 		TaskID:  mission.ID,
 		Status:  "submitted",
-		Message: "Task submitted successfully and will be executed asynchronously",
+		Message: message,
+		Task:    task,
 	}
 
 	w.Header().Set("Content-Type", "application/json")
@@ -389,13 +503,49 @@ func (h *CaptainHandler) HandleSubmitTask(w http.ResponseWriter, r *http.Request
 	json.NewEncoder(w).Encode(response)
 }
 
+// HandleDeleteTask removes a pending task from Captain's queue before it runs
+func (h *CaptainHandler) HandleDeleteTask(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodDelete {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	taskID := mux.Vars(r)["id"]
+	if taskID == "" {
+		http.Error(w, "Task ID is required", http.StatusBadRequest)
+		return
+	}
+
+	task, err := h.captain.RemoveTask(taskID)
+	switch {
+	case errors.Is(err, captain.ErrTaskNotFound):
+		http.Error(w, "Task not found", http.StatusNotFound)
+		return
+	case errors.Is(err, captain.ErrTaskNotPending):
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusConflict)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"error": "Only pending tasks can be removed",
+			"task":  task,
+		})
+		return
+	case err != nil:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(task)
+}
+
 // CaptainStatusResponse represents Captain's current status
 type CaptainStatusResponse struct {
-	Running      bool      `json:"running"`
-	LastCycle    time.Time `json:"last_cycle"`
-	PendingTasks int       `json:"pending_tasks"`
-	ActiveAgents int       `json:"active_agents"`
-	Escalations  int       `json:"escalations"`
+	Running      bool                      `json:"running"`
+	LastCycle    time.Time                 `json:"last_cycle"`
+	PendingTasks int                       `json:"pending_tasks"`
+	ActiveAgents int                       `json:"active_agents"`
+	Escalations  int                       `json:"escalations"`
+	Teams        []captain.TeamQueueStatus `json:"teams"` // queue depth per team
 }
 
 // HandleGetStatus returns Captain's current status
@@ -414,15 +564,70 @@ func (h *CaptainHandler) HandleGetStatus(w http.ResponseWriter, r *http.Request)
 	response := CaptainStatusResponse{
 		Running:      true,       // Captain is running if we can respond
 		LastCycle:    time.Now(), // Would need to track this in Captain
-		PendingTasks: 0,          // Would need Captain to expose this
 		ActiveAgents: len(state.Agents),
 		Escalations:  escalations,
+		Teams:        h.captain.TeamStatuses(),
+	}
+	for _, team := range response.Teams {
+		response.PendingTasks += team.QueueDepth
 	}
 
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(response)
 }
 
+// HandlePauseTeam stops Captain from starting the team's queued tasks
+func (h *CaptainHandler) HandlePauseTeam(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	teamID := mux.Vars(r)["team_id"]
+	if teamID == "" {
+		http.Error(w, "Team ID is required", http.StatusBadRequest)
+		return
+	}
+
+	status := h.captain.PauseTeam(teamID)
+	h.store.AddActivity(&types.ActivityLog{
+		ID:        fmt.Sprintf("activity-%d", time.Now().UnixNano()),
+		AgentID:   "Captain",
+		Action:    "team_paused",
+		Details:   fmt.Sprintf("Team %s paused with %d queued task(s)", status.TeamID, status.QueueDepth),
+		Timestamp: time.Now(),
+	})
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(status)
+}
+
+// HandleResumeTeam lets Captain process a paused team's queue again
+func (h *CaptainHandler) HandleResumeTeam(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	teamID := mux.Vars(r)["team_id"]
+	if teamID == "" {
+		http.Error(w, "Team ID is required", http.StatusBadRequest)
+		return
+	}
+
+	status := h.captain.ResumeTeam(teamID)
+	h.store.AddActivity(&types.ActivityLog{
+		ID:        fmt.Sprintf("activity-%d", time.Now().UnixNano()),
+		AgentID:   "Captain",
+		Action:    "team_resumed",
+		Details:   fmt.Sprintf("Team %s resumed with %d queued task(s)", status.TeamID, status.QueueDepth),
+		Timestamp: time.Now(),
+	})
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(status)
+}
+
 // ReconRequest is the payload for triggering manual reconnaissance
 type ReconRequest struct {
 	ProjectPath string `json:"project_path"`
@@ -442,9 +647,6 @@ func (h *CaptainHandler) HandleTriggerRecon(w http.ResponseWriter, r *http.Reque
 		return
 	}
 
-	// Limit request size to prevent DoS
-	limitRequestSize(r, MaxPayloadSize)
-
 	var req ReconRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		http.Error(w, "Invalid request body", http.StatusBadRequest)
@@ -474,6 +676,7 @@ func (h *CaptainHandler) HandleTriggerRecon(w http.ResponseWriter, r *http.Reque
 		Metadata: map[string]string{
 			"trigger": "manual",
 		},
+		SourceType: captain.SourceAPI,
 	}
 
 	// Execute asynchronously
@@ -590,9 +793,6 @@ func (h *CaptainHandler) HandleRespondToEscalation(w http.ResponseWriter, r *htt
 		return
 	}
 
-	// Limit request size to prevent DoS
-	limitRequestSize(r, MaxPayloadSize)
-
 	vars := mux.Vars(r)
 	escalationID := vars["id"]
 	if escalationID == "" {
@@ -650,6 +850,53 @@ func (h *CaptainHandler) HandleRespondToEscalation(w http.ResponseWriter, r *htt
 	http.Error(w, "Escalation not found", http.StatusNotFound)
 }
 
+// ResolveEscalationRequest is the payload for resolving a Captain escalation
+type ResolveEscalationRequest struct {
+	Resolution string `json:"resolution"`
+	ResolvedBy string `json:"resolved_by"`
+}
+
+// HandleResolveEscalation resolves one of Captain's own escalations (task
+// approvals, crashed tasks, agent issues) and records who resolved it
+func (h *CaptainHandler) HandleResolveEscalation(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	escalationID := mux.Vars(r)["id"]
+	if escalationID == "" {
+		http.Error(w, "Escalation ID is required", http.StatusBadRequest)
+		return
+	}
+
+	var req ResolveEscalationRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	if req.Resolution == "" || req.ResolvedBy == "" {
+		http.Error(w, "Resolution and resolved_by are required", http.StatusBadRequest)
+		return
+	}
+
+	esc, err := h.captain.ResolveEscalation(escalationID, req.Resolution, req.ResolvedBy)
+	switch {
+	case errors.Is(err, captain.ErrEscalationNotFound):
+		http.Error(w, "Escalation not found", http.StatusNotFound)
+		return
+	case errors.Is(err, captain.ErrEscalationResolved):
+		http.Error(w, "Escalation already resolved", http.StatusConflict)
+		return
+	case err != nil:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(esc)
+}
+
 // inferTaskTypeFromRequest determines task type from request parameters
 func inferTaskTypeFromRequest(title, description string, needsRecon bool) captain.TaskType {
 	if needsRecon {
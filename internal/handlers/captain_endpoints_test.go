@@ -5,18 +5,37 @@ import (
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/CLIAIMONITOR/internal/agents"
 	"github.com/CLIAIMONITOR/internal/captain"
 	"github.com/CLIAIMONITOR/internal/persistence"
 	"github.com/CLIAIMONITOR/internal/types"
 	"github.com/gorilla/mux"
 )
 
+// newFixtureStore loads a copy of the test.json fixture from a temp dir, so
+// test runs don't write state or lock files into the repo
+func newFixtureStore(t *testing.T) *persistence.JSONStore {
+	t.Helper()
+	data, err := os.ReadFile("test.json")
+	if err != nil {
+		t.Fatalf("Failed to read fixture: %v", err)
+	}
+	path := filepath.Join(t.TempDir(), "test.json")
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		t.Fatalf("Failed to copy fixture: %v", err)
+	}
+	return persistence.NewJSONStore(path)
+}
+
 func TestHandleSubmitTask(t *testing.T) {
 	// Create mock store
-	store := persistence.NewJSONStore("test.json")
+	store := newFixtureStore(t)
 	store.Load()
 
 	// Create mock captain (will fail to execute but that's ok for testing the endpoint)
@@ -53,7 +72,7 @@ func TestHandleSubmitTask(t *testing.T) {
 }
 
 func TestHandleGetStatus(t *testing.T) {
-	store := persistence.NewJSONStore("test.json")
+	store := newFixtureStore(t)
 	store.Load()
 
 	// Add a mock agent
@@ -90,7 +109,7 @@ func TestHandleGetStatus(t *testing.T) {
 }
 
 func TestHandleTriggerRecon(t *testing.T) {
-	store := persistence.NewJSONStore("test.json")
+	store := newFixtureStore(t)
 	store.Load()
 
 	cap := captain.NewCaptain(".", nil, nil, nil)
@@ -123,7 +142,7 @@ func TestHandleTriggerRecon(t *testing.T) {
 }
 
 func TestHandleGetEscalations(t *testing.T) {
-	store := persistence.NewJSONStore("test.json")
+	store := newFixtureStore(t)
 	store.Load()
 
 	// Add a pending stop request
@@ -159,7 +178,7 @@ func TestHandleGetEscalations(t *testing.T) {
 }
 
 func TestHandleRespondToEscalation(t *testing.T) {
-	store := persistence.NewJSONStore("test.json")
+	store := newFixtureStore(t)
 	store.Load()
 
 	// Add a pending stop request
@@ -252,7 +271,7 @@ func TestContainsAny(t *testing.T) {
 // Additional edge case tests
 
 func TestHandleSubmitTask_MissingTitle(t *testing.T) {
-	store := persistence.NewJSONStore("test.json")
+	store := newFixtureStore(t)
 	store.Load()
 	cap := captain.NewCaptain(".", nil, nil, nil)
 	handler := NewCaptainHandler(cap, store)
@@ -274,7 +293,7 @@ func TestHandleSubmitTask_MissingTitle(t *testing.T) {
 }
 
 func TestHandleSubmitTask_MissingDescription(t *testing.T) {
-	store := persistence.NewJSONStore("test.json")
+	store := newFixtureStore(t)
 	store.Load()
 	cap := captain.NewCaptain(".", nil, nil, nil)
 	handler := NewCaptainHandler(cap, store)
@@ -295,8 +314,116 @@ func TestHandleSubmitTask_MissingDescription(t *testing.T) {
 	}
 }
 
+func TestHandleSubmitTask_InvalidSourceType(t *testing.T) {
+	store := newFixtureStore(t)
+	store.Load()
+	cap := captain.NewCaptain(".", nil, nil, nil)
+	handler := NewCaptainHandler(cap, store)
+
+	req := SubmitTaskRequest{
+		Title:       "Test Task",
+		Description: "Test description",
+		SourceType:  "email",
+	}
+
+	body, _ := json.Marshal(req)
+	r := httptest.NewRequest(http.MethodPost, "/api/captain/task", bytes.NewReader(body))
+	w := httptest.NewRecorder()
+
+	handler.HandleSubmitTask(w, r)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400, got %d", w.Code)
+	}
+}
+
+func TestHandleSubmitTask_MissingPlanningMetadata(t *testing.T) {
+	store := newFixtureStore(t)
+	store.Load()
+	cap := captain.NewCaptain(".", nil, nil, nil)
+	handler := NewCaptainHandler(cap, store)
+
+	submit := func(taskType string, metadata map[string]string) *httptest.ResponseRecorder {
+		body, _ := json.Marshal(SubmitTaskRequest{
+			Title:       "Plan the release",
+			Description: "Break down the remaining work",
+			TaskType:    taskType,
+			Metadata:    metadata,
+		})
+		r := httptest.NewRequest(http.MethodPost, "/api/captain/task", bytes.NewReader(body))
+		w := httptest.NewRecorder()
+		handler.HandleSubmitTask(w, r)
+		return w
+	}
+
+	w := submit("planning", nil)
+	if w.Code != http.StatusBadRequest || !strings.Contains(w.Body.String(), "estimated_effort") {
+		t.Errorf("Expected 400 listing missing metadata, got %d: %s", w.Code, w.Body.String())
+	}
+	if len(cap.GetTaskQueue()) != 0 {
+		t.Error("Expected rejected task not to be queued")
+	}
+
+	w = submit("planning", map[string]string{
+		"source":               "planner",
+		"estimated_effort":     "2h",
+		"agent_recommendation": "go-developer",
+	})
+	if w.Code != http.StatusCreated {
+		t.Errorf("Expected 201 with complete metadata, got %d: %s", w.Code, w.Body.String())
+	}
+
+	if w := submit("deploy", nil); w.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for an unknown task_type, got %d", w.Code)
+	}
+}
+
+func TestHandleSubmitTask_InferredPlanningDefaults(t *testing.T) {
+	store := newFixtureStore(t)
+	store.Load()
+	cap := captain.NewCaptain(".", nil, nil, nil)
+	handler := NewCaptainHandler(cap, store)
+
+	// "API" makes this an inferred planning mission
+	body, _ := json.Marshal(SubmitTaskRequest{
+		Title:       "Fix API timeout",
+		Description: "Requests to the upstream service hang",
+		Metadata:    map[string]string{"estimated_effort": "1h"},
+	})
+	r := httptest.NewRequest(http.MethodPost, "/api/captain/task", bytes.NewReader(body))
+	w := httptest.NewRecorder()
+	handler.HandleSubmitTask(w, r)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("Expected 201, got %d: %s", w.Code, w.Body.String())
+	}
+
+	queue := cap.GetTaskQueue()
+	if len(queue) != 1 || queue[0].Mission.TaskType != captain.TaskPlanning {
+		t.Fatalf("Expected one queued planning task, got %+v", queue)
+	}
+	metadata := queue[0].Mission.Metadata
+	if metadata["source"] != captain.SourceAPI || metadata["estimated_effort"] != "1h" || metadata["agent_recommendation"] != "Planner" {
+		t.Errorf("Unexpected metadata %v", metadata)
+	}
+}
+
+func TestDefaultMissionSource(t *testing.T) {
+	mission := captain.Mission{ID: "task-1"}
+	if err := defaultMissionSource(&mission); err != nil {
+		t.Fatalf("defaultMissionSource failed: %v", err)
+	}
+	if mission.SourceType != captain.SourceAPI {
+		t.Errorf("Expected source type %q, got %q", captain.SourceAPI, mission.SourceType)
+	}
+
+	imported := captain.Mission{ID: "MAH-1", SourceType: captain.SourceJSONFile}
+	if err := defaultMissionSource(&imported); err != nil || imported.SourceType != captain.SourceJSONFile {
+		t.Errorf("Expected json_file source to be kept, got %q (err %v)", imported.SourceType, err)
+	}
+}
+
 func TestHandleSubmitTask_InvalidJSON(t *testing.T) {
-	store := persistence.NewJSONStore("test.json")
+	store := newFixtureStore(t)
 	store.Load()
 	cap := captain.NewCaptain(".", nil, nil, nil)
 	handler := NewCaptainHandler(cap, store)
@@ -312,7 +439,7 @@ func TestHandleSubmitTask_InvalidJSON(t *testing.T) {
 }
 
 func TestHandleSubmitTask_WrongMethod(t *testing.T) {
-	store := persistence.NewJSONStore("test.json")
+	store := newFixtureStore(t)
 	store.Load()
 	cap := captain.NewCaptain(".", nil, nil, nil)
 	handler := NewCaptainHandler(cap, store)
@@ -328,7 +455,7 @@ func TestHandleSubmitTask_WrongMethod(t *testing.T) {
 }
 
 func TestHandleGetStatus_WrongMethod(t *testing.T) {
-	store := persistence.NewJSONStore("test.json")
+	store := newFixtureStore(t)
 	store.Load()
 	cap := captain.NewCaptain(".", nil, nil, nil)
 	handler := NewCaptainHandler(cap, store)
@@ -344,7 +471,7 @@ func TestHandleGetStatus_WrongMethod(t *testing.T) {
 }
 
 func TestHandleTriggerRecon_MissingProjectPath(t *testing.T) {
-	store := persistence.NewJSONStore("test.json")
+	store := newFixtureStore(t)
 	store.Load()
 	cap := captain.NewCaptain(".", nil, nil, nil)
 	handler := NewCaptainHandler(cap, store)
@@ -365,7 +492,7 @@ func TestHandleTriggerRecon_MissingProjectPath(t *testing.T) {
 }
 
 func TestHandleTriggerRecon_InvalidJSON(t *testing.T) {
-	store := persistence.NewJSONStore("test.json")
+	store := newFixtureStore(t)
 	store.Load()
 	cap := captain.NewCaptain(".", nil, nil, nil)
 	handler := NewCaptainHandler(cap, store)
@@ -381,7 +508,7 @@ func TestHandleTriggerRecon_InvalidJSON(t *testing.T) {
 }
 
 func TestHandleRespondToEscalation_MissingID(t *testing.T) {
-	store := persistence.NewJSONStore("test.json")
+	store := newFixtureStore(t)
 	store.Load()
 	cap := captain.NewCaptain(".", nil, nil, nil)
 	handler := NewCaptainHandler(cap, store)
@@ -408,7 +535,7 @@ func TestHandleRespondToEscalation_MissingID(t *testing.T) {
 }
 
 func TestHandleRespondToEscalation_MissingResponse(t *testing.T) {
-	store := persistence.NewJSONStore("test.json")
+	store := newFixtureStore(t)
 	store.Load()
 	cap := captain.NewCaptain(".", nil, nil, nil)
 	handler := NewCaptainHandler(cap, store)
@@ -432,7 +559,7 @@ func TestHandleRespondToEscalation_MissingResponse(t *testing.T) {
 }
 
 func TestHandleRespondToEscalation_InvalidAction(t *testing.T) {
-	store := persistence.NewJSONStore("test.json")
+	store := newFixtureStore(t)
 	store.Load()
 	cap := captain.NewCaptain(".", nil, nil, nil)
 	handler := NewCaptainHandler(cap, store)
@@ -457,7 +584,7 @@ func TestHandleRespondToEscalation_InvalidAction(t *testing.T) {
 }
 
 func TestHandleRespondToEscalation_NotFound(t *testing.T) {
-	store := persistence.NewJSONStore("test.json")
+	store := newFixtureStore(t)
 	store.Load()
 	cap := captain.NewCaptain(".", nil, nil, nil)
 	handler := NewCaptainHandler(cap, store)
@@ -482,7 +609,7 @@ func TestHandleRespondToEscalation_NotFound(t *testing.T) {
 }
 
 func TestHandleSetAPIKey_Success(t *testing.T) {
-	store := persistence.NewJSONStore("test.json")
+	store := newFixtureStore(t)
 	store.Load()
 	cap := captain.NewCaptain(".", nil, nil, nil)
 	handler := NewCaptainHandler(cap, store)
@@ -512,7 +639,7 @@ func TestHandleSetAPIKey_Success(t *testing.T) {
 }
 
 func TestHandleSetAPIKey_Empty(t *testing.T) {
-	store := persistence.NewJSONStore("test.json")
+	store := newFixtureStore(t)
 	store.Load()
 	cap := captain.NewCaptain(".", nil, nil, nil)
 	handler := NewCaptainHandler(cap, store)
@@ -535,7 +662,7 @@ func TestHandleSetAPIKey_Empty(t *testing.T) {
 }
 
 func TestHandleSetAPIKey_WrongMethod(t *testing.T) {
-	store := persistence.NewJSONStore("test.json")
+	store := newFixtureStore(t)
 	store.Load()
 	cap := captain.NewCaptain(".", nil, nil, nil)
 	handler := NewCaptainHandler(cap, store)
@@ -551,7 +678,7 @@ func TestHandleSetAPIKey_WrongMethod(t *testing.T) {
 }
 
 func TestHandleActiveSubagents(t *testing.T) {
-	store := persistence.NewJSONStore("test.json")
+	store := newFixtureStore(t)
 	store.Load()
 	cap := captain.NewCaptain(".", nil, nil, nil)
 	handler := NewCaptainHandler(cap, store)
@@ -577,7 +704,7 @@ func TestHandleActiveSubagents(t *testing.T) {
 }
 
 func TestHandleActiveSubagents_WrongMethod(t *testing.T) {
-	store := persistence.NewJSONStore("test.json")
+	store := newFixtureStore(t)
 	store.Load()
 	cap := captain.NewCaptain(".", nil, nil, nil)
 	handler := NewCaptainHandler(cap, store)
@@ -620,7 +747,7 @@ func TestBuildReconDescription(t *testing.T) {
 // Additional tests for Captain handlers
 
 func TestHandleDecideMode_ValidMission(t *testing.T) {
-	store := persistence.NewJSONStore("test.json")
+	store := newFixtureStore(t)
 	store.Load()
 	cap := captain.NewCaptain(".", nil, nil, nil)
 	handler := NewCaptainHandler(cap, store)
@@ -652,7 +779,7 @@ func TestHandleDecideMode_ValidMission(t *testing.T) {
 }
 
 func TestHandleDecideMode_InvalidJSON(t *testing.T) {
-	store := persistence.NewJSONStore("test.json")
+	store := newFixtureStore(t)
 	store.Load()
 	cap := captain.NewCaptain(".", nil, nil, nil)
 	handler := NewCaptainHandler(cap, store)
@@ -668,7 +795,7 @@ func TestHandleDecideMode_InvalidJSON(t *testing.T) {
 }
 
 func TestHandleDecideMode_WrongMethod(t *testing.T) {
-	store := persistence.NewJSONStore("test.json")
+	store := newFixtureStore(t)
 	store.Load()
 	cap := captain.NewCaptain(".", nil, nil, nil)
 	handler := NewCaptainHandler(cap, store)
@@ -684,7 +811,7 @@ func TestHandleDecideMode_WrongMethod(t *testing.T) {
 }
 
 func TestHandleExecuteMission_ValidMission(t *testing.T) {
-	store := persistence.NewJSONStore("test.json")
+	store := newFixtureStore(t)
 	store.Load()
 	cap := captain.NewCaptain(".", nil, nil, nil)
 	handler := NewCaptainHandler(cap, store)
@@ -710,7 +837,7 @@ func TestHandleExecuteMission_ValidMission(t *testing.T) {
 }
 
 func TestHandleExecuteMission_WrongMethod(t *testing.T) {
-	store := persistence.NewJSONStore("test.json")
+	store := newFixtureStore(t)
 	store.Load()
 	cap := captain.NewCaptain(".", nil, nil, nil)
 	handler := NewCaptainHandler(cap, store)
@@ -726,7 +853,7 @@ func TestHandleExecuteMission_WrongMethod(t *testing.T) {
 }
 
 func TestHandleExecuteParallel_ValidMissions(t *testing.T) {
-	store := persistence.NewJSONStore("test.json")
+	store := newFixtureStore(t)
 	store.Load()
 	cap := captain.NewCaptain(".", nil, nil, nil)
 	handler := NewCaptainHandler(cap, store)
@@ -764,7 +891,7 @@ func TestHandleExecuteParallel_ValidMissions(t *testing.T) {
 }
 
 func TestHandleExecuteParallel_NoMissions(t *testing.T) {
-	store := persistence.NewJSONStore("test.json")
+	store := newFixtureStore(t)
 	store.Load()
 	cap := captain.NewCaptain(".", nil, nil, nil)
 	handler := NewCaptainHandler(cap, store)
@@ -785,7 +912,7 @@ func TestHandleExecuteParallel_NoMissions(t *testing.T) {
 }
 
 func TestHandleImportTasks(t *testing.T) {
-	store := persistence.NewJSONStore("test.json")
+	store := newFixtureStore(t)
 	store.Load()
 	cap := captain.NewCaptain(".", nil, nil, nil)
 	handler := NewCaptainHandler(cap, store)
@@ -802,7 +929,7 @@ func TestHandleImportTasks(t *testing.T) {
 }
 
 func TestHandleImportTasks_WrongMethod(t *testing.T) {
-	store := persistence.NewJSONStore("test.json")
+	store := newFixtureStore(t)
 	store.Load()
 	cap := captain.NewCaptain(".", nil, nil, nil)
 	handler := NewCaptainHandler(cap, store)
@@ -818,7 +945,7 @@ func TestHandleImportTasks_WrongMethod(t *testing.T) {
 }
 
 func TestHandleRecon_ValidRequest(t *testing.T) {
-	store := persistence.NewJSONStore("test.json")
+	store := newFixtureStore(t)
 	store.Load()
 	cap := captain.NewCaptain(".", nil, nil, nil)
 	handler := NewCaptainHandler(cap, store)
@@ -846,7 +973,7 @@ func TestHandleRecon_ValidRequest(t *testing.T) {
 }
 
 func TestHandleRecon_WrongMethod(t *testing.T) {
-	store := persistence.NewJSONStore("test.json")
+	store := newFixtureStore(t)
 	store.Load()
 	cap := captain.NewCaptain(".", nil, nil, nil)
 	handler := NewCaptainHandler(cap, store)
@@ -860,3 +987,129 @@ func TestHandleRecon_WrongMethod(t *testing.T) {
 		t.Errorf("Expected status 405, got %d", w.Code)
 	}
 }
+
+// taskStatus returns the status of a task in Captain's queue
+func taskStatus(c *captain.Captain, id string) string {
+	for _, task := range c.GetTaskQueue() {
+		if task.Mission.ID == id {
+			return task.Status
+		}
+	}
+	return ""
+}
+
+func TestHandleDeleteTask(t *testing.T) {
+	store := newFixtureStore(t)
+	store.Load()
+	cap := captain.NewCaptain(t.TempDir(), agents.NewMockSpawner(), nil, nil)
+	handler := NewCaptainHandler(cap, store)
+
+	// task-pending's team is paused so only task-running starts; a planning
+	// mission without metadata fails as soon as it runs
+	cap.PauseTeam("held")
+	cap.EnqueueMission(captain.Mission{ID: "task-pending", Title: "Pending", TeamID: "held"}, false)
+	cap.EnqueueMission(captain.Mission{ID: "task-running", Title: "Running", TaskType: captain.TaskPlanning}, false)
+	cap.TriggerCycle()
+	deadline := time.Now().Add(5 * time.Second)
+	for taskStatus(cap, "task-running") == "pending" && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	router := mux.NewRouter()
+	router.HandleFunc("/api/captain/tasks/{id}", handler.HandleDeleteTask).Methods("DELETE")
+
+	tests := []struct {
+		id   string
+		want int
+	}{
+		{"task-pending", http.StatusOK},
+		{"task-pending", http.StatusNotFound},
+		{"task-running", http.StatusConflict},
+		{"missing", http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		r := httptest.NewRequest(http.MethodDelete, "/api/captain/tasks/"+tt.id, nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, r)
+		if w.Code != tt.want {
+			t.Errorf("DELETE %s: expected status %d, got %d", tt.id, tt.want, w.Code)
+		}
+	}
+
+	if queue := cap.GetTaskQueue(); len(queue) != 1 || queue[0].Mission.ID != "task-running" {
+		t.Errorf("Expected only task-running in queue, got %d tasks", len(queue))
+	}
+}
+
+func TestHandleResolveEscalation_Validation(t *testing.T) {
+	store := newFixtureStore(t)
+	store.Load()
+	cap := captain.NewCaptain(".", nil, nil, nil)
+	handler := NewCaptainHandler(cap, store)
+
+	router := mux.NewRouter()
+	router.HandleFunc("/api/captain/escalations/{id}/resolve", handler.HandleResolveEscalation).Methods("POST")
+
+	tests := []struct {
+		name   string
+		body   string
+		status int
+	}{
+		{"missing resolved_by", `{"resolution": "approved"}`, http.StatusBadRequest},
+		{"invalid json", `{invalid`, http.StatusBadRequest},
+		{"unknown escalation", `{"resolution": "approved", "resolved_by": "alice"}`, http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodPost, "/api/captain/escalations/esc-missing/resolve", bytes.NewReader([]byte(tt.body)))
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, r)
+			if w.Code != tt.status {
+				t.Errorf("Expected status %d, got %d", tt.status, w.Code)
+			}
+		})
+	}
+}
+
+func TestHandlePauseResumeTeam(t *testing.T) {
+	store := newFixtureStore(t)
+	store.Load()
+	cap := captain.NewCaptain(".", nil, nil, nil)
+	cap.EnqueueMission(captain.Mission{ID: "task-1", Title: "Blue task", TeamID: "blue"}, false)
+	handler := NewCaptainHandler(cap, store)
+
+	r := httptest.NewRequest(http.MethodPost, "/api/captain/teams/blue/pause", nil)
+	r = mux.SetURLVars(r, map[string]string{"team_id": "blue"})
+	w := httptest.NewRecorder()
+	handler.HandlePauseTeam(w, r)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", w.Code)
+	}
+	var paused captain.TeamQueueStatus
+	json.NewDecoder(w.Body).Decode(&paused)
+	if !paused.Paused || paused.QueueDepth != 1 {
+		t.Errorf("Expected paused team with depth 1, got %+v", paused)
+	}
+
+	r = httptest.NewRequest(http.MethodGet, "/api/captain/status", nil)
+	w = httptest.NewRecorder()
+	handler.HandleGetStatus(w, r)
+
+	var status CaptainStatusResponse
+	json.NewDecoder(w.Body).Decode(&status)
+	if status.PendingTasks != 1 || len(status.Teams) != 1 || status.Teams[0].TeamID != "blue" || !status.Teams[0].Paused {
+		t.Errorf("Expected paused blue team in status, got %+v", status)
+	}
+
+	r = httptest.NewRequest(http.MethodPost, "/api/captain/teams/blue/resume", nil)
+	r = mux.SetURLVars(r, map[string]string{"team_id": "blue"})
+	w = httptest.NewRecorder()
+	handler.HandleResumeTeam(w, r)
+
+	var resumed captain.TeamQueueStatus
+	json.NewDecoder(w.Body).Decode(&resumed)
+	if resumed.Paused {
+		t.Errorf("Expected team resumed, got %+v", resumed)
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"net/http"
 
 	"github.com/CLIAIMONITOR/internal/agents"
+	"github.com/CLIAIMONITOR/internal/logger"
 	"github.com/CLIAIMONITOR/internal/memory"
 	"github.com/CLIAIMONITOR/internal/supervisor"
 	"github.com/CLIAIMONITOR/internal/types"
@@ -93,7 +94,7 @@ func (h *CoordinationHandler) handleAnalyzeReport(w http.ResponseWriter, r *http
 	}
 
 	// Store report in recon repository
-	if err := h.storeReconReport(report); err != nil {
+	if err := h.storeReconReport(r.Context(), report); err != nil {
 		respondError(w, http.StatusInternalServerError, "Failed to store report: "+err.Error())
 		return
 	}
@@ -121,9 +122,6 @@ func (h *CoordinationHandler) handleAnalyzeReport(w http.ResponseWriter, r *http
 
 // handleDispatch executes an action plan by spawning agents
 func (h *CoordinationHandler) handleDispatch(w http.ResponseWriter, r *http.Request) {
-	// Limit request size to prevent DoS
-	limitRequestSize(r, MaxPayloadSize)
-
 	var req struct {
 		PlanID string `json:"plan_id"`
 	}
@@ -278,7 +276,7 @@ func (h *CoordinationHandler) handleGetPlan(w http.ResponseWriter, r *http.Reque
 
 // Helper functions
 
-func (h *CoordinationHandler) storeReconReport(report *supervisor.ReconReport) error {
+func (h *CoordinationHandler) storeReconReport(ctx context.Context, report *supervisor.ReconReport) error {
 	// If reconRepo is not available, skip storage but don't error
 	if h.reconRepo == nil {
 		// Just store as learning for now
@@ -294,9 +292,9 @@ func (h *CoordinationHandler) storeReconReport(report *supervisor.ReconReport) e
 	}
 
 	// Register environment (upsert)
-	ctx := context.Background()
 	if err := h.reconRepo.RegisterEnvironment(ctx, env); err != nil {
 		// Log error but continue - environment might already exist
+		logger.FromContext(ctx, logger.For("coordination")).Warn("failed to register environment", "env_id", env.ID, "error", err)
 	}
 
 	// Create scan record
@@ -394,7 +392,8 @@ func (h *CoordinationHandler) storeReconReport(report *supervisor.ReconReport) e
 
 	// Save all findings
 	if len(findings) > 0 {
-		if err := h.reconRepo.SaveFindings(ctx, findings); err != nil {
+		strategy, _ := memory.ReconDuplicateStrategy(h.memDB)
+		if err := h.reconRepo.SaveFindings(ctx, findings, strategy); err != nil {
 			return err
 		}
 	}
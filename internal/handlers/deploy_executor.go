@@ -0,0 +1,92 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/CLIAIMONITOR/internal/logger"
+	"github.com/CLIAIMONITOR/internal/memory"
+)
+
+// DefaultScriptTimeout bounds how long a deployment script may run
+const DefaultScriptTimeout = 10 * time.Minute
+
+// EnvDeployExecutor selects the deployment executor: "script" runs
+// deployment plans as scripts, anything else is a dry run
+const EnvDeployExecutor = "CLIAIMONITOR_DEPLOY_EXECUTOR"
+
+// DeploymentExecutor runs an approved deployment and returns its output
+type DeploymentExecutor interface {
+	Execute(ctx context.Context, deployment *memory.Deployment) (string, error)
+}
+
+// DeploymentExecutorFromEnv returns the executor named by
+// CLIAIMONITOR_DEPLOY_EXECUTOR. Without it deployments are dry runs.
+func DeploymentExecutorFromEnv() DeploymentExecutor {
+	if strings.EqualFold(strings.TrimSpace(os.Getenv(EnvDeployExecutor)), "script") {
+		return NewScriptExecutor()
+	}
+	return DryRunExecutor{}
+}
+
+// DryRunExecutor logs the deployment plan without running anything
+type DryRunExecutor struct{}
+
+// Execute logs the plan and reports success
+func (DryRunExecutor) Execute(ctx context.Context, deployment *memory.Deployment) (string, error) {
+	logger.For("supervisor").Info("dry run deployment",
+		"deployment_id", deployment.ID, "repo_id", deployment.RepoID, "plan", deployment.DeploymentPlan)
+	return fmt.Sprintf("dry run: deployment %d not executed", deployment.ID), nil
+}
+
+// ScriptExecutor treats the deployment plan as the path of a script and runs
+// it with Shell. The repo is Windows-first, so the default is PowerShell.
+type ScriptExecutor struct {
+	Shell     string
+	ShellArgs []string
+	Timeout   time.Duration
+}
+
+// NewScriptExecutor creates a script executor that runs plans with PowerShell
+func NewScriptExecutor() *ScriptExecutor {
+	return &ScriptExecutor{
+		Shell:     "powershell.exe",
+		ShellArgs: []string{"-NoProfile", "-NonInteractive", "-File"},
+		Timeout:   DefaultScriptTimeout,
+	}
+}
+
+// Execute runs the script named by the deployment plan and returns its
+// combined output. A non-zero exit is returned as an error along with the
+// output collected so far.
+func (e *ScriptExecutor) Execute(ctx context.Context, deployment *memory.Deployment) (string, error) {
+	scriptPath := strings.TrimSpace(deployment.DeploymentPlan)
+	if scriptPath == "" {
+		return "", fmt.Errorf("deployment %d has no script path", deployment.ID)
+	}
+	info, err := os.Stat(scriptPath)
+	if err != nil {
+		return "", fmt.Errorf("deployment script not found: %w", err)
+	}
+	if info.IsDir() {
+		return "", fmt.Errorf("deployment script %s is a directory", scriptPath)
+	}
+
+	if e.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, e.Timeout)
+		defer cancel()
+	}
+
+	args := append(append([]string{}, e.ShellArgs...), scriptPath)
+	cmd := exec.CommandContext(ctx, e.Shell, args...)
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return string(output), fmt.Errorf("deployment script failed: %w", err)
+	}
+	return string(output), nil
+}
@@ -1,7 +1,9 @@
 package handlers
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"net/http"
 	"strconv"
 
@@ -42,6 +44,7 @@ type SupervisorHandler struct {
 	scanner  *supervisor.Scanner
 	planner  *supervisor.Planner
 	executor *supervisor.Executor
+	deployer DeploymentExecutor
 }
 
 // NewSupervisorHandler creates a new supervisor handler
@@ -63,6 +66,13 @@ func NewSupervisorHandlerWithExecutor(memDB memory.MemoryDB, spawner *agents.Pro
 	}
 }
 
+// WithExecutor sets the backend used to execute deployments that are not
+// handled by the agent spawner
+func (h *SupervisorHandler) WithExecutor(exec DeploymentExecutor) *SupervisorHandler {
+	h.deployer = exec
+	return h
+}
+
 // RegisterRoutes registers supervisor API routes on the given router
 func (h *SupervisorHandler) RegisterRoutes(r *mux.Router) {
 	// Supervisor API routes
@@ -73,6 +83,7 @@ func (h *SupervisorHandler) RegisterRoutes(r *mux.Router) {
 	r.HandleFunc("/supervisor/tasks", h.handleGetTasks).Methods("GET")
 	r.HandleFunc("/supervisor/tasks/{id}", h.handleGetTask).Methods("GET")
 	r.HandleFunc("/supervisor/tasks/{id}/status", h.handleUpdateTaskStatus).Methods("PUT")
+	r.HandleFunc("/supervisor/tasks/{id}/dependencies", h.handleGetTaskDependencies).Methods("GET")
 	r.HandleFunc("/supervisor/deployments", h.handleGetDeployments).Methods("GET")
 	r.HandleFunc("/supervisor/deployments/{id}", h.handleGetDeployment).Methods("GET")
 	r.HandleFunc("/supervisor/deployments/{id}/status", h.handleUpdateDeploymentStatus).Methods("PUT")
@@ -81,9 +92,6 @@ func (h *SupervisorHandler) RegisterRoutes(r *mux.Router) {
 
 // handleDiscoverRepo discovers a new repository
 func (h *SupervisorHandler) handleDiscoverRepo(w http.ResponseWriter, r *http.Request) {
-	// Limit request size to prevent DoS
-	limitRequestSize(r, MaxPayloadSize)
-
 	var req struct {
 		Path string `json:"path"`
 	}
@@ -246,11 +254,90 @@ func (h *SupervisorHandler) handleGetTask(w http.ResponseWriter, r *http.Request
 	respondJSON(w, task)
 }
 
+// handleGetTaskDependencies returns the transitive dependency graph of a
+// task as an adjacency list, flagging any dependency cycle found
+func (h *SupervisorHandler) handleGetTaskDependencies(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	taskID := vars["id"]
+
+	if _, err := h.memDB.GetTask(taskID); err != nil {
+		respondError(w, http.StatusNotFound, "Task not found")
+		return
+	}
+
+	graph, err := buildDependencyGraph(h.memDB, taskID)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	respondJSON(w, graph)
+}
+
+// DependencyGraph is the transitive dependency tree of a task. Dependencies
+// maps every reachable task to the tasks it directly depends on.
+type DependencyGraph struct {
+	TaskID       string              `json:"task_id"`
+	Dependencies map[string][]string `json:"dependencies"`
+	HasCycle     bool                `json:"has_cycle"`
+	Cycle        []string            `json:"cycle,omitempty"` // First cycle found, starting and ending at the same task
+}
+
+// buildDependencyGraph walks the dependencies of taskID depth-first. A task
+// reached again while still on the walk's path closes a cycle; the walk
+// records it and carries on, so cyclic graphs still terminate.
+func buildDependencyGraph(memDB memory.MemoryDB, taskID string) (*DependencyGraph, error) {
+	graph := &DependencyGraph{
+		TaskID:       taskID,
+		Dependencies: make(map[string][]string),
+	}
+	onPath := make(map[string]int) // task ID -> index in path
+	var path []string
+
+	var visit func(id string) error
+	visit = func(id string) error {
+		if idx, ok := onPath[id]; ok {
+			if !graph.HasCycle {
+				graph.HasCycle = true
+				graph.Cycle = append(append([]string{}, path[idx:]...), id)
+			}
+			return nil
+		}
+		if _, done := graph.Dependencies[id]; done {
+			return nil
+		}
+
+		deps, err := memDB.GetTaskDependencies(id)
+		if err != nil {
+			return err
+		}
+		children := make([]string, 0, len(deps))
+		for _, dep := range deps {
+			children = append(children, dep.DependsOn)
+		}
+
+		onPath[id] = len(path)
+		path = append(path, id)
+		for _, child := range children {
+			if err := visit(child); err != nil {
+				return err
+			}
+		}
+		path = path[:len(path)-1]
+		delete(onPath, id)
+
+		graph.Dependencies[id] = children
+		return nil
+	}
+
+	if err := visit(taskID); err != nil {
+		return nil, err
+	}
+	return graph, nil
+}
+
 // handleUpdateTaskStatus updates the status of a task
 func (h *SupervisorHandler) handleUpdateTaskStatus(w http.ResponseWriter, r *http.Request) {
-	// Limit request size to prevent DoS
-	limitRequestSize(r, MaxPayloadSize)
-
 	vars := mux.Vars(r)
 	taskID := vars["id"]
 
@@ -337,9 +424,6 @@ func (h *SupervisorHandler) handleGetDeployment(w http.ResponseWriter, r *http.R
 
 // handleUpdateDeploymentStatus updates the status of a deployment
 func (h *SupervisorHandler) handleUpdateDeploymentStatus(w http.ResponseWriter, r *http.Request) {
-	// Limit request size to prevent DoS
-	limitRequestSize(r, MaxPayloadSize)
-
 	vars := mux.Vars(r)
 	idStr := vars["id"]
 
@@ -389,19 +473,56 @@ func (h *SupervisorHandler) handleExecuteDeployment(w http.ResponseWriter, r *ht
 		return
 	}
 
-	// Check if executor is available
-	if h.executor == nil {
-		respondError(w, http.StatusServiceUnavailable, "Executor not configured - handler created without spawner")
+	// Agent spawning takes precedence over a pluggable backend
+	if h.executor != nil {
+		result, err := h.executor.ExecutePlan(id)
+		if err != nil {
+			respondError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		respondJSON(w, result)
+		return
+	}
+
+	if h.deployer == nil {
+		respondError(w, http.StatusServiceUnavailable, "executor not configured")
 		return
 	}
 
-	result, err := h.executor.ExecutePlan(id)
+	deployment, err := h.memDB.GetDeployment(id)
 	if err != nil {
+		respondError(w, http.StatusNotFound, "Deployment not found")
+		return
+	}
+	// Claim the deployment atomically so concurrent requests cannot both run it
+	if err := h.memDB.StartDeployment(id); err != nil {
+		if errors.Is(err, memory.ErrDeploymentNotStartable) {
+			respondError(w, http.StatusConflict, "deployment status must be 'proposed' or 'approved'")
+			return
+		}
 		respondError(w, http.StatusInternalServerError, err.Error())
 		return
 	}
 
-	respondJSON(w, result)
+	output, execErr := h.deployer.Execute(context.WithoutCancel(r.Context()), deployment)
+	status := "completed"
+	if execErr != nil {
+		status = "failed"
+	}
+	if err := h.memDB.UpdateDeploymentStatus(id, status); err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	resp := map[string]interface{}{
+		"deployment_id": id,
+		"status":        status,
+		"output":        output,
+	}
+	if execErr != nil {
+		resp["error"] = execErr.Error()
+	}
+	respondJSON(w, resp)
 }
 
 // Helper functions
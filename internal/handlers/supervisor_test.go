@@ -2,11 +2,14 @@ package handlers
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"path/filepath"
+	"reflect"
+	"strings"
 	"testing"
 
 	"github.com/CLIAIMONITOR/internal/memory"
@@ -824,9 +827,69 @@ func TestExecuteDeploymentNotConfigured(t *testing.T) {
 
 	router.ServeHTTP(rr, req)
 
-	// Should return error since executor not configured
-	if rr.Code != http.StatusServiceUnavailable && rr.Code != http.StatusInternalServerError {
-		t.Logf("Got status %d (executor may not be configured)", rr.Code)
+	if rr.Code != http.StatusServiceUnavailable {
+		t.Fatalf("Expected status 503, got %d: %s", rr.Code, rr.Body.String())
+	}
+	if !strings.Contains(rr.Body.String(), "executor not configured") {
+		t.Errorf("Expected 'executor not configured' error, got %s", rr.Body.String())
+	}
+}
+
+func TestExecuteDeploymentDryRun(t *testing.T) {
+	handler, cleanup := setupTestHandler(t)
+	defer cleanup()
+
+	router := setupTestRouter(handler.WithExecutor(DryRunExecutor{}))
+
+	repo, _ := handler.memDB.DiscoverRepo(".")
+	deployment := &memory.Deployment{
+		RepoID:         repo.ID,
+		DeploymentPlan: `{"test": true}`,
+		Status:         "approved",
+	}
+	handler.memDB.CreateDeployment(deployment)
+
+	req := httptest.NewRequest("POST", fmt.Sprintf("/api/supervisor/deployments/%d/execute", deployment.ID), nil)
+	rr := httptest.NewRecorder()
+
+	router.ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", rr.Code, rr.Body.String())
+	}
+
+	stored, err := handler.memDB.GetDeployment(deployment.ID)
+	if err != nil {
+		t.Fatalf("GetDeployment failed: %v", err)
+	}
+	if stored.Status != "completed" {
+		t.Errorf("Expected status 'completed', got '%s'", stored.Status)
+	}
+
+	// A completed deployment cannot be executed again
+	rr = httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest("POST", fmt.Sprintf("/api/supervisor/deployments/%d/execute", deployment.ID), nil))
+	if rr.Code != http.StatusConflict {
+		t.Errorf("Expected status 409 on re-execute, got %d", rr.Code)
+	}
+}
+
+func TestDeploymentExecutorFromEnv(t *testing.T) {
+	t.Setenv(EnvDeployExecutor, "")
+	if _, ok := DeploymentExecutorFromEnv().(DryRunExecutor); !ok {
+		t.Error("Expected dry run executor by default")
+	}
+	t.Setenv(EnvDeployExecutor, "Script")
+	if _, ok := DeploymentExecutorFromEnv().(*ScriptExecutor); !ok {
+		t.Error("Expected script executor")
+	}
+}
+
+func TestScriptExecutorMissingScript(t *testing.T) {
+	exec := NewScriptExecutor()
+	_, err := exec.Execute(context.Background(), &memory.Deployment{ID: 1, DeploymentPlan: filepath.Join(t.TempDir(), "missing.ps1")})
+	if err == nil {
+		t.Error("Expected error for missing script")
 	}
 }
 
@@ -884,3 +947,105 @@ func TestGetTasksWithStatusFilter(t *testing.T) {
 		t.Errorf("Expected 2 pending tasks, got %v", count)
 	}
 }
+
+// createDependencyTasks creates workflow tasks with the given IDs and
+// dependency edges (task -> depends on)
+func createDependencyTasks(t *testing.T, handler *SupervisorHandler, ids []string, edges [][2]string) {
+	t.Helper()
+	repo, _ := handler.memDB.DiscoverRepo(".")
+	for _, id := range ids {
+		if err := handler.memDB.CreateTask(&memory.WorkflowTask{ID: id, RepoID: repo.ID, SourceFile: "test.yaml", Title: id, Status: "pending"}); err != nil {
+			t.Fatalf("CreateTask(%s) failed: %v", id, err)
+		}
+	}
+	for _, e := range edges {
+		if err := handler.memDB.AddTaskDependency(e[0], e[1]); err != nil {
+			t.Fatalf("AddTaskDependency(%s, %s) failed: %v", e[0], e[1], err)
+		}
+	}
+}
+
+func TestGetTaskDependencies(t *testing.T) {
+	handler, cleanup := setupTestHandler(t)
+	defer cleanup()
+
+	router := setupTestRouter(handler)
+
+	// DEP-1 -> DEP-2, DEP-3; both depend on DEP-4 (a diamond, not a cycle)
+	createDependencyTasks(t, handler, []string{"DEP-1", "DEP-2", "DEP-3", "DEP-4"}, [][2]string{
+		{"DEP-1", "DEP-2"}, {"DEP-1", "DEP-3"}, {"DEP-2", "DEP-4"}, {"DEP-3", "DEP-4"},
+	})
+
+	req := httptest.NewRequest("GET", "/api/supervisor/tasks/DEP-1/dependencies", nil)
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", rr.Code, rr.Body.String())
+	}
+	var graph DependencyGraph
+	json.NewDecoder(rr.Body).Decode(&graph)
+	want := map[string][]string{
+		"DEP-1": {"DEP-2", "DEP-3"},
+		"DEP-2": {"DEP-4"},
+		"DEP-3": {"DEP-4"},
+		"DEP-4": {},
+	}
+	if !reflect.DeepEqual(graph.Dependencies, want) {
+		t.Errorf("Expected dependencies %v, got %v", want, graph.Dependencies)
+	}
+	if graph.HasCycle {
+		t.Errorf("Diamond reported as a cycle: %v", graph.Cycle)
+	}
+}
+
+func TestGetTaskDependenciesCycle(t *testing.T) {
+	handler, cleanup := setupTestHandler(t)
+	defer cleanup()
+
+	router := setupTestRouter(handler)
+
+	// CYC-1 -> CYC-2 -> CYC-3 -> CYC-1
+	createDependencyTasks(t, handler, []string{"CYC-1", "CYC-2", "CYC-3"}, [][2]string{
+		{"CYC-1", "CYC-2"}, {"CYC-2", "CYC-3"}, {"CYC-3", "CYC-1"},
+	})
+
+	req := httptest.NewRequest("GET", "/api/supervisor/tasks/CYC-1/dependencies", nil)
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", rr.Code, rr.Body.String())
+	}
+	var graph DependencyGraph
+	json.NewDecoder(rr.Body).Decode(&graph)
+	if !graph.HasCycle {
+		t.Fatal("Expected cycle to be detected")
+	}
+	if want := []string{"CYC-1", "CYC-2", "CYC-3", "CYC-1"}; !reflect.DeepEqual(graph.Cycle, want) {
+		t.Errorf("Expected cycle %v, got %v", want, graph.Cycle)
+	}
+	if len(graph.Dependencies) != 3 {
+		t.Errorf("Expected all 3 tasks in the graph, got %v", graph.Dependencies)
+	}
+
+	// Self-dependencies are rejected outright
+	if err := handler.memDB.AddTaskDependency("CYC-1", "CYC-1"); err == nil {
+		t.Error("Expected self-dependency to be rejected")
+	}
+}
+
+func TestGetTaskDependenciesNotFound(t *testing.T) {
+	handler, cleanup := setupTestHandler(t)
+	defer cleanup()
+
+	router := setupTestRouter(handler)
+
+	req := httptest.NewRequest("GET", "/api/supervisor/tasks/NOPE-1/dependencies", nil)
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusNotFound {
+		t.Errorf("Expected status 404, got %d", rr.Code)
+	}
+}
@@ -3,30 +3,35 @@ package handlers
 
 import (
 	"encoding/json"
-	"log"
 	"net/http"
 	"strconv"
 	"time"
 
+	"github.com/CLIAIMONITOR/internal/logger"
 	"github.com/CLIAIMONITOR/internal/tasks"
 	"github.com/gorilla/mux"
 )
 
 // TasksHandler handles task-related HTTP endpoints
 type TasksHandler struct {
-	queue *tasks.Queue
+	queue tasks.TaskQueue
 	store *tasks.Store
 }
 
-// NewTasksHandler creates a new tasks handler
-func NewTasksHandler(queue *tasks.Queue, store *tasks.Store) *TasksHandler {
+// NewTasksHandler creates a new tasks handler. store may be nil when the
+// queue persists tasks itself.
+func NewTasksHandler(queue tasks.TaskQueue, store *tasks.Store) *TasksHandler {
 	return &TasksHandler{
 		queue: queue,
 		store: store,
 	}
 }
 
-// HandleList returns all tasks with pagination support
+// HandleList returns tasks newest first, paginated with an opaque cursor.
+// Pass the response's next_cursor as ?cursor= to fetch the following page.
+//
+// Deprecated: the offset parameter still returns tasks in priority order
+// for older clients; new callers should use cursor.
 func (h *TasksHandler) HandleList(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
 		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
@@ -36,18 +41,12 @@ func (h *TasksHandler) HandleList(w http.ResponseWriter, r *http.Request) {
 	// Parse pagination parameters
 	query := r.URL.Query()
 	limit := 100 // default
-	offset := 0
 
 	if l := query.Get("limit"); l != "" {
 		if parsed, err := strconv.Atoi(l); err == nil && parsed > 0 && parsed <= 1000 {
 			limit = parsed
 		}
 	}
-	if o := query.Get("offset"); o != "" {
-		if parsed, err := strconv.Atoi(o); err == nil && parsed >= 0 {
-			offset = parsed
-		}
-	}
 
 	// Filter by status if provided
 	status := query.Get("status")
@@ -58,25 +57,53 @@ func (h *TasksHandler) HandleList(w http.ResponseWriter, r *http.Request) {
 	} else {
 		taskList = h.queue.All()
 	}
-
-	// Apply pagination
 	total := len(taskList)
-	if offset < len(taskList) {
-		end := offset + limit
-		if end > len(taskList) {
-			end = len(taskList)
+
+	// Legacy offset pagination (deprecated)
+	if o := query.Get("offset"); o != "" && query.Get("cursor") == "" {
+		offset := 0
+		if parsed, err := strconv.Atoi(o); err == nil && parsed >= 0 {
+			offset = parsed
 		}
-		taskList = taskList[offset:end]
-	} else {
-		taskList = []*tasks.Task{}
+		if offset < len(taskList) {
+			end := offset + limit
+			if end > len(taskList) {
+				end = len(taskList)
+			}
+			taskList = taskList[offset:end]
+		} else {
+			taskList = []*tasks.Task{}
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Deprecation", "true")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"tasks":  taskList,
+			"total":  total,
+			"limit":  limit,
+			"offset": offset,
+		})
+		return
+	}
+
+	var cursor *tasks.Cursor
+	if c := query.Get("cursor"); c != "" {
+		parsed, err := tasks.DecodeCursor(c)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		cursor = parsed
 	}
 
+	page, nextCursor := tasks.PageByCreated(taskList, cursor, limit)
+
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(map[string]interface{}{
-		"tasks":  taskList,
-		"total":  total,
-		"limit":  limit,
-		"offset": offset,
+		"tasks":       page,
+		"total":       total,
+		"limit":       limit,
+		"next_cursor": nextCursor,
 	})
 }
 
@@ -87,9 +114,6 @@ func (h *TasksHandler) HandleCreate(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Limit request size to prevent DoS
-	limitRequestSize(r, MaxPayloadSize)
-
 	var req struct {
 		Title       string `json:"title"`
 		Description string `json:"description"`
@@ -102,6 +126,10 @@ func (h *TasksHandler) HandleCreate(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if req.Priority == 0 {
+		req.Priority = tasks.DefaultPriority
+	}
+
 	task := tasks.NewTask(req.Title, req.Description, req.Priority)
 	if req.Repo != "" {
 		task.Repo = req.Repo
@@ -112,11 +140,15 @@ func (h *TasksHandler) HandleCreate(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	h.queue.Add(task)
+	if err := h.queue.Add(task); err != nil {
+		logger.For("tasks").Error("failed to add task", "task_id", task.ID, "error", err)
+		http.Error(w, "Failed to save task", http.StatusInternalServerError)
+		return
+	}
 
 	if h.store != nil {
 		if err := h.store.Save(task); err != nil {
-			log.Printf("[TASKS] Failed to persist task %s: %v", task.ID, err)
+			logger.For("tasks").Error("failed to persist task", "task_id", task.ID, "error", err)
 			// Task is already in memory queue, continue
 		}
 	}
@@ -159,9 +191,6 @@ func (h *TasksHandler) HandleUpdate(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Limit request size to prevent DoS
-	limitRequestSize(r, MaxPayloadSize)
-
 	vars := mux.Vars(r)
 	id := vars["id"]
 
@@ -188,6 +217,10 @@ func (h *TasksHandler) HandleUpdate(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if updates.Priority != nil {
+		if !tasks.ValidPriority(*updates.Priority) {
+			http.Error(w, "priority must be between 1 and 7", http.StatusBadRequest)
+			return
+		}
 		task.Priority = *updates.Priority
 	}
 	if updates.Status != nil {
@@ -198,11 +231,15 @@ func (h *TasksHandler) HandleUpdate(w http.ResponseWriter, r *http.Request) {
 	}
 
 	task.UpdatedAt = time.Now()
-	h.queue.Update(task)
+	if _, err := h.queue.Update(task); err != nil {
+		logger.For("tasks").Error("failed to update task", "task_id", task.ID, "error", err)
+		http.Error(w, "Failed to save task", http.StatusInternalServerError)
+		return
+	}
 
 	if h.store != nil {
 		if err := h.store.Save(task); err != nil {
-			log.Printf("[TASKS] Failed to persist updated task %s: %v", task.ID, err)
+			logger.For("tasks").Error("failed to persist updated task", "task_id", task.ID, "error", err)
 			// Task is already in memory queue, continue
 		}
 	}
@@ -227,14 +264,20 @@ func (h *TasksHandler) HandleDelete(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if !h.queue.Remove(id) {
+	removed, err := h.queue.Remove(id)
+	if err != nil {
+		logger.For("tasks").Error("failed to delete task", "task_id", id, "error", err)
+		http.Error(w, "Failed to delete task", http.StatusInternalServerError)
+		return
+	}
+	if !removed {
 		http.Error(w, "Task not found", http.StatusNotFound)
 		return
 	}
 
 	if h.store != nil {
 		if err := h.store.Delete(id); err != nil {
-			log.Printf("[TASKS] Failed to delete task %s from store: %v", id, err)
+			logger.For("tasks").Error("failed to delete task from store", "task_id", id, "error", err)
 			// Task already removed from memory queue, continue
 		}
 	}
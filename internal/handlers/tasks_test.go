@@ -4,9 +4,11 @@ package handlers
 import (
 	"bytes"
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/CLIAIMONITOR/internal/tasks"
 	"github.com/gorilla/mux"
@@ -14,8 +16,10 @@ import (
 
 func TestTasksListHandler(t *testing.T) {
 	queue := tasks.NewQueue()
-	queue.Add(tasks.NewTask("Task 1", "Desc", 3))
-	queue.Add(tasks.NewTask("Task 2", "Desc", 1))
+	older := tasks.NewTask("Task 1", "Desc", 1)
+	older.CreatedAt = time.Now().Add(-time.Minute)
+	queue.Add(older)
+	queue.Add(tasks.NewTask("Task 2", "Desc", 3))
 
 	handler := NewTasksHandler(queue, nil)
 
@@ -38,9 +42,88 @@ func TestTasksListHandler(t *testing.T) {
 		t.Errorf("expected 2 tasks, got %d", response.Total)
 	}
 
-	// Should be priority-sorted (1 before 3)
-	if response.Tasks[0].Priority != 1 {
-		t.Error("tasks should be priority sorted")
+	// Should be newest first regardless of priority
+	if response.Tasks[0].Title != "Task 2" {
+		t.Error("tasks should be sorted newest first")
+	}
+}
+
+func TestTasksListHandler_Cursor(t *testing.T) {
+	queue := tasks.NewQueue()
+	base := time.Now()
+	for i := 0; i < 5; i++ {
+		task := tasks.NewTask(fmt.Sprintf("Task %d", i), "Desc", 1+i%3)
+		task.CreatedAt = base.Add(time.Duration(i) * time.Second)
+		queue.Add(task)
+	}
+	handler := NewTasksHandler(queue, nil)
+
+	var titles []string
+	cursor := ""
+	for pages := 0; pages < 5; pages++ {
+		url := "/api/tasks?limit=2"
+		if cursor != "" {
+			url += "&cursor=" + cursor
+		}
+		w := httptest.NewRecorder()
+		handler.HandleList(w, httptest.NewRequest("GET", url, nil))
+		if w.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+		}
+
+		var response struct {
+			Tasks      []*tasks.Task `json:"tasks"`
+			NextCursor string        `json:"next_cursor"`
+		}
+		json.NewDecoder(w.Body).Decode(&response)
+		for _, task := range response.Tasks {
+			titles = append(titles, task.Title)
+		}
+		if response.NextCursor == "" {
+			break
+		}
+		cursor = response.NextCursor
+	}
+
+	expected := []string{"Task 4", "Task 3", "Task 2", "Task 1", "Task 0"}
+	if fmt.Sprint(titles) != fmt.Sprint(expected) {
+		t.Errorf("expected %v across pages, got %v", expected, titles)
+	}
+}
+
+func TestTasksListHandler_InvalidCursor(t *testing.T) {
+	handler := NewTasksHandler(tasks.NewQueue(), nil)
+
+	w := httptest.NewRecorder()
+	handler.HandleList(w, httptest.NewRequest("GET", "/api/tasks?cursor=not-a-cursor!", nil))
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected 400, got %d", w.Code)
+	}
+}
+
+func TestTasksListHandler_DeprecatedOffset(t *testing.T) {
+	queue := tasks.NewQueue()
+	queue.Add(tasks.NewTask("Task 1", "Desc", 3))
+	queue.Add(tasks.NewTask("Task 2", "Desc", 1))
+	handler := NewTasksHandler(queue, nil)
+
+	w := httptest.NewRecorder()
+	handler.HandleList(w, httptest.NewRequest("GET", "/api/tasks?offset=0", nil))
+
+	if w.Header().Get("Deprecation") != "true" {
+		t.Error("offset pagination should set the Deprecation header")
+	}
+
+	var response struct {
+		Tasks  []*tasks.Task `json:"tasks"`
+		Offset int           `json:"offset"`
+	}
+	json.NewDecoder(w.Body).Decode(&response)
+
+	// Offset mode keeps the legacy priority ordering (1 before 3)
+	if len(response.Tasks) != 2 || response.Tasks[0].Priority != 1 {
+		t.Error("offset pagination should stay priority sorted")
 	}
 }
 
@@ -64,6 +147,25 @@ func TestTasksCreateHandler(t *testing.T) {
 	}
 }
 
+func TestTasksCreateHandler_PersistFailure(t *testing.T) {
+	queue, err := tasks.NewMemorySQLiteQueue()
+	if err != nil {
+		t.Fatalf("NewMemorySQLiteQueue() error = %v", err)
+	}
+	queue.Close() // every write now fails
+	handler := NewTasksHandler(queue, nil)
+
+	body := bytes.NewBufferString(`{"title":"New task","description":"Test","priority":2}`)
+	req := httptest.NewRequest("POST", "/api/tasks", body)
+	w := httptest.NewRecorder()
+
+	handler.HandleCreate(w, req)
+
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("expected 500 when the task cannot be saved, got %d: %s", w.Code, w.Body.String())
+	}
+}
+
 // Additional comprehensive tests for TasksHandler
 
 func TestTasksListHandler_WithStatusFilter(t *testing.T) {
@@ -227,7 +329,7 @@ func TestTasksDeleteHandler(t *testing.T) {
 	initialLen := queue.Len()
 
 	// Delete task
-	removed := queue.Remove(task.ID)
+	removed, _ := queue.Remove(task.ID)
 	if !removed {
 		t.Error("expected task to be removed")
 	}
@@ -241,7 +343,7 @@ func TestTasksDeleteHandler_NotFound(t *testing.T) {
 	queue := tasks.NewQueue()
 
 	// Try to delete non-existent task
-	removed := queue.Remove("nonexistent")
+	removed, _ := queue.Remove("nonexistent")
 	if removed {
 		t.Error("expected remove to fail for non-existent task")
 	}
@@ -0,0 +1,36 @@
+package instance
+
+// Exit codes for the -status and -stop commands, for use from shell scripts
+const (
+	// ExitOK: -status found a responding instance / -stop stopped it
+	ExitOK = 0
+	// ExitDegraded: -status found an instance that is not responding /
+	// -stop could not stop the instance within the timeout
+	ExitDegraded = 1
+	// ExitNotRunning: no instance was found
+	ExitNotRunning = 2
+)
+
+// StatusExitCode maps the result of CheckExistingInstance to a -status exit code
+func StatusExitCode(info *InstanceInfo) int {
+	switch {
+	case info == nil:
+		return ExitNotRunning
+	case !info.IsResponding:
+		return ExitDegraded
+	default:
+		return ExitOK
+	}
+}
+
+// StopExitCode maps the outcome of a stop attempt to a -stop exit code
+func StopExitCode(info *InstanceInfo, stopped bool) int {
+	switch {
+	case info == nil:
+		return ExitNotRunning
+	case !stopped:
+		return ExitDegraded
+	default:
+		return ExitOK
+	}
+}
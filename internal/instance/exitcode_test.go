@@ -0,0 +1,46 @@
+package instance
+
+import "testing"
+
+func TestStatusExitCode(t *testing.T) {
+	tests := []struct {
+		name string
+		info *InstanceInfo
+		want int
+	}{
+		{"no instance", nil, ExitNotRunning},
+		{"degraded", &InstanceInfo{PID: 1, IsRunning: true, IsResponding: false}, ExitDegraded},
+		{"running", &InstanceInfo{PID: 1, IsRunning: true, IsResponding: true}, ExitOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := StatusExitCode(tt.info); got != tt.want {
+				t.Errorf("StatusExitCode() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStopExitCode(t *testing.T) {
+	running := &InstanceInfo{PID: 1, IsRunning: true, IsResponding: true}
+
+	tests := []struct {
+		name    string
+		info    *InstanceInfo
+		stopped bool
+		want    int
+	}{
+		{"no instance", nil, false, ExitNotRunning},
+		{"timeout", running, false, ExitDegraded},
+		{"stopped", running, true, ExitOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := StopExitCode(tt.info, tt.stopped); got != tt.want {
+				t.Errorf("StopExitCode() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
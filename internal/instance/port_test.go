@@ -3,6 +3,9 @@ package instance
 import (
 	"net"
 	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
 	"testing"
 	"time"
 )
@@ -161,9 +164,26 @@ func TestHealthCheck_WrongStatusCode(t *testing.T) {
 	}
 }
 
+// httptestPort returns the port an httptest server is listening on
+func httptestPort(t *testing.T, server *httptest.Server) int {
+	t.Helper()
+	u, err := url.Parse(server.URL)
+	if err != nil {
+		t.Fatalf("Failed to parse server URL: %v", err)
+	}
+	port, err := strconv.Atoi(u.Port())
+	if err != nil {
+		t.Fatalf("Failed to parse server port: %v", err)
+	}
+	return port
+}
+
 func TestSendShutdownRequest_NoServer(t *testing.T) {
-	// Test shutdown request on a port with no server
-	port := 22003
+	// Grab a port that was just in use so nothing is listening on it
+	server := httptest.NewServer(http.NotFoundHandler())
+	port := httptestPort(t, server)
+	server.Close()
+
 	err := SendShutdownRequest(port)
 
 	if err == nil {
@@ -171,6 +191,23 @@ func TestSendShutdownRequest_NoServer(t *testing.T) {
 	}
 }
 
+func TestSendShutdownRequest_ErrorStatus(t *testing.T) {
+	var gotMethod, gotPath string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotMethod, gotPath = r.Method, r.URL.Path
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	err := SendShutdownRequest(httptestPort(t, server))
+	if err == nil {
+		t.Error("SendShutdownRequest should fail when server returns non-200 status")
+	}
+	if gotMethod != http.MethodPost || gotPath != "/api/shutdown" {
+		t.Errorf("Expected POST /api/shutdown, got %s %s", gotMethod, gotPath)
+	}
+}
+
 func TestSendShutdownRequest_WithServer(t *testing.T) {
 	// Start a server that responds to shutdown requests
 	port := 22004
@@ -2,7 +2,9 @@ package instance
 
 import (
 	"bufio"
+	"errors"
 	"fmt"
+	"io"
 	"os"
 	"os/exec"
 	"strconv"
@@ -10,21 +12,74 @@ import (
 	"time"
 )
 
+// NonInteractiveStrategy selects how a port conflict is resolved when there
+// is no terminal to prompt (Docker, CI, services)
+type NonInteractiveStrategy string
+
+const (
+	// FailFast refuses to start while another instance holds the port (default)
+	FailFast NonInteractiveStrategy = "fail-fast"
+	// UseNextAvailablePort starts on the next free port instead
+	UseNextAvailablePort NonInteractiveStrategy = "next-port"
+	// KillExisting force-kills the running instance and takes its port
+	KillExisting NonInteractiveStrategy = "kill"
+
+	// connectExisting opens the existing dashboard and exits. Only reachable
+	// via the legacy CLIAIMONITOR_ON_CONFLICT=connect setting.
+	connectExisting NonInteractiveStrategy = "connect"
+)
+
+// ParseNonInteractiveStrategy parses a --conflict-strategy value. The legacy
+// CLIAIMONITOR_ON_CONFLICT values exit, port, kill and connect are accepted too.
+func ParseNonInteractiveStrategy(s string) (NonInteractiveStrategy, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "", string(FailFast), "exit":
+		return FailFast, nil
+	case string(UseNextAvailablePort), "port":
+		return UseNextAvailablePort, nil
+	case string(KillExisting):
+		return KillExisting, nil
+	case string(connectExisting):
+		return connectExisting, nil
+	default:
+		return "", fmt.Errorf("unknown conflict strategy %q (expected fail-fast, next-port or kill)", s)
+	}
+}
+
+// DefaultConflictTimeout is how long the interactive prompt waits for a
+// choice before the non-interactive strategy is applied
+const DefaultConflictTimeout = 30 * time.Second
+
 // ConflictResolver handles conflicts when an instance is already running
 type ConflictResolver struct {
 	instanceMgr *InstanceManager
 	interactive bool
+	strategy    NonInteractiveStrategy
+	input       io.Reader
 }
 
-// NewConflictResolver creates a new conflict resolver
+// NewConflictResolver creates a new conflict resolver. The non-interactive
+// strategy defaults to CLIAIMONITOR_ON_CONFLICT, falling back to FailFast.
 func NewConflictResolver(instanceMgr *InstanceManager, interactive bool) *ConflictResolver {
+	strategy, err := ParseNonInteractiveStrategy(os.Getenv("CLIAIMONITOR_ON_CONFLICT"))
+	if err != nil {
+		strategy = FailFast
+	}
 	return &ConflictResolver{
 		instanceMgr: instanceMgr,
 		interactive: interactive,
+		strategy:    strategy,
+		input:       os.Stdin,
 	}
 }
 
-// Resolve handles the conflict resolution process
+// SetNonInteractiveStrategy overrides the strategy used when not interactive
+func (r *ConflictResolver) SetNonInteractiveStrategy(strategy NonInteractiveStrategy) {
+	r.strategy = strategy
+}
+
+// Resolve handles the conflict resolution process: interactive sessions are
+// prompted, otherwise the configured NonInteractiveStrategy is applied.
 // May exit the process (for connect/exit options)
 // Returns error if resolution fails, nil if resolved successfully
 func (r *ConflictResolver) Resolve(info *InstanceInfo) error {
@@ -34,65 +89,113 @@ func (r *ConflictResolver) Resolve(info *InstanceInfo) error {
 	return r.handleInteractive(info)
 }
 
+// ResolveWithTimeout is like Resolve, but gives up on the interactive prompt
+// after timeout and applies the non-interactive strategy instead, so a
+// misdetected terminal (e.g. in Docker) cannot block startup forever. A
+// timeout of zero or less waits indefinitely.
+func (r *ConflictResolver) ResolveWithTimeout(info *InstanceInfo, timeout time.Duration) error {
+	if !r.interactive || timeout <= 0 {
+		return r.Resolve(info)
+	}
+
+	r.displayConflictInfo(info)
+
+	type result struct {
+		choice int
+		err    error
+	}
+	// Buffered so the reader goroutine can finish after a timeout. A read
+	// blocked on stdin cannot be cancelled; it is abandoned instead.
+	done := make(chan result, 1)
+	go func() {
+		choice, err := r.readChoice(bufio.NewReader(r.input))
+		done <- result{choice, err}
+	}()
+
+	select {
+	case res := <-done:
+		if res.err != nil {
+			fmt.Fprintf(os.Stderr, "Error reading input: %v\n", res.err)
+			return r.handleNonInteractive(info)
+		}
+		return r.applyChoice(info, res.choice)
+	case <-time.After(timeout):
+		fmt.Printf("\nNo choice made within %s.\n", timeout)
+		return r.handleNonInteractive(info)
+	}
+}
+
 // handleInteractive presents the user with options and processes their choice
 func (r *ConflictResolver) handleInteractive(info *InstanceInfo) error {
 	r.displayConflictInfo(info)
 
-	reader := bufio.NewReader(os.Stdin)
+	choice, err := r.readChoice(bufio.NewReader(r.input))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error reading input: %v\n", err)
+		return r.handleNonInteractive(info)
+	}
+	return r.applyChoice(info, choice)
+}
 
+// readChoice prompts until the user enters a valid choice. Returns an error
+// only if input is closed.
+func (r *ConflictResolver) readChoice(reader *bufio.Reader) (int, error) {
 	for {
 		choice, err := r.promptUser(reader)
+		if errors.Is(err, io.EOF) {
+			return 0, err
+		}
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "Error reading input: %v\n", err)
 			continue
 		}
-
-		switch choice {
-		case 1:
-			// Connect to existing
-			return r.connectToExisting(info)
-		case 2:
-			// Stop existing gracefully
-			return r.stopExisting(info, false)
-		case 3:
-			// Use different port
-			return r.useDifferentPort(info)
-		case 4:
-			// Force kill
-			return r.stopExisting(info, true)
-		case 5:
-			// Exit
-			fmt.Println("\nCanceling startup.")
-			os.Exit(0)
-		default:
+		if choice < 1 || choice > 5 {
 			fmt.Println("Invalid choice. Please enter 1-5.")
+			continue
 		}
+		return choice, nil
 	}
 }
 
-// handleNonInteractive handles conflict resolution for non-interactive environments
-func (r *ConflictResolver) handleNonInteractive(info *InstanceInfo) error {
-	strategy := os.Getenv("CLIAIMONITOR_ON_CONFLICT")
-	if strategy == "" {
-		strategy = "exit" // Safe default
+// applyChoice carries out a menu choice from displayConflictInfo
+func (r *ConflictResolver) applyChoice(info *InstanceInfo, choice int) error {
+	switch choice {
+	case 1:
+		// Connect to existing
+		return r.connectToExisting(info)
+	case 2:
+		// Stop existing gracefully
+		return r.stopExisting(info, false)
+	case 3:
+		// Use different port
+		return r.useDifferentPort(info)
+	case 4:
+		// Force kill
+		return r.stopExisting(info, true)
+	case 5:
+		// Exit
+		fmt.Println("\nCanceling startup.")
+		os.Exit(0)
 	}
+	return fmt.Errorf("invalid choice: %d", choice)
+}
 
-	fmt.Printf("Port %d is in use (PID %d). Conflict strategy: %s\n", info.Port, info.PID, strategy)
+// handleNonInteractive handles conflict resolution for non-interactive environments
+func (r *ConflictResolver) handleNonInteractive(info *InstanceInfo) error {
+	fmt.Printf("Port %d is in use (PID %d). Conflict strategy: %s\n", info.Port, info.PID, r.strategy)
 
-	switch strategy {
-	case "exit":
-		fmt.Fprintf(os.Stderr, "Another instance is running on port %d (PID %d)\n", info.Port, info.PID)
-		fmt.Fprintf(os.Stderr, "Set CLIAIMONITOR_ON_CONFLICT to 'kill', 'port', or 'connect' to change behavior\n")
-		os.Exit(1)
-		return nil
-	case "kill":
+	switch r.strategy {
+	case FailFast:
+		return fmt.Errorf("another instance is running on port %d (PID %d); "+
+			"use --conflict-strategy=next-port or --conflict-strategy=kill to change behavior", info.Port, info.PID)
+	case KillExisting:
 		return r.stopExisting(info, true)
-	case "port":
+	case UseNextAvailablePort:
 		return r.useDifferentPort(info)
-	case "connect":
+	case connectExisting:
 		return r.connectToExisting(info)
 	default:
-		return fmt.Errorf("unknown conflict strategy: %s", strategy)
+		return fmt.Errorf("unknown conflict strategy: %s", r.strategy)
 	}
 }
 
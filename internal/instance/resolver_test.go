@@ -0,0 +1,81 @@
+package instance
+
+import (
+	"io"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseNonInteractiveStrategy(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    NonInteractiveStrategy
+		wantErr bool
+	}{
+		{"", FailFast, false},
+		{"fail-fast", FailFast, false},
+		{"exit", FailFast, false},
+		{"next-port", UseNextAvailablePort, false},
+		{"port", UseNextAvailablePort, false},
+		{"KILL", KillExisting, false},
+		{"connect", connectExisting, false},
+		{"restart", "", true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseNonInteractiveStrategy(tt.input)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseNonInteractiveStrategy(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+		}
+		if got != tt.want {
+			t.Errorf("ParseNonInteractiveStrategy(%q) = %q, want %q", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestConflictResolverDefaultsToFailFast(t *testing.T) {
+	t.Setenv("CLIAIMONITOR_ON_CONFLICT", "")
+
+	r := NewConflictResolver(nil, false)
+	if r.strategy != FailFast {
+		t.Errorf("expected FailFast default, got %q", r.strategy)
+	}
+
+	err := r.Resolve(&InstanceInfo{PID: 1234, Port: 3000, IsRunning: true})
+	if err == nil {
+		t.Error("expected FailFast to return an error")
+	}
+}
+
+func TestResolveWithTimeoutAppliesStrategy(t *testing.T) {
+	t.Setenv("CLIAIMONITOR_ON_CONFLICT", "")
+
+	// Nothing is ever written, so the prompt blocks like a misdetected terminal
+	pr, pw := io.Pipe()
+	defer pw.Close()
+
+	r := NewConflictResolver(nil, true)
+	r.input = pr
+
+	start := time.Now()
+	err := r.ResolveWithTimeout(&InstanceInfo{PID: 1234, Port: 3000, IsRunning: true}, 50*time.Millisecond)
+	if err == nil || !strings.Contains(err.Error(), "another instance is running") {
+		t.Errorf("expected FailFast error after timeout, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("ResolveWithTimeout took %s, expected it to time out", elapsed)
+	}
+}
+
+func TestResolveWithTimeoutClosedInput(t *testing.T) {
+	t.Setenv("CLIAIMONITOR_ON_CONFLICT", "")
+
+	r := NewConflictResolver(nil, true)
+	r.input = strings.NewReader("")
+
+	err := r.ResolveWithTimeout(&InstanceInfo{PID: 1234, Port: 3000, IsRunning: true}, time.Minute)
+	if err == nil {
+		t.Error("expected closed input to fall back to FailFast")
+	}
+}
@@ -0,0 +1,130 @@
+// Package logger provides structured logging built on log/slog.
+//
+// Components tag their records with a "component" group instead of the
+// ad-hoc "[COMPONENT]" string prefixes used previously, so log output can be
+// filtered by component in both text and JSON formats.
+package logger
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+)
+
+// Supported output formats
+const (
+	FormatText = "text" // Human-readable key=value output for development
+	FormatJSON = "json" // One JSON object per line for production log shipping
+)
+
+// NewLogger creates a logger writing to stderr at the given level and format
+func NewLogger(level slog.Level, format string) *slog.Logger {
+	return NewLoggerWithWriter(os.Stderr, level, format)
+}
+
+// NewLoggerWithWriter creates a logger writing to w. Unknown formats fall back to text.
+func NewLoggerWithWriter(w io.Writer, level slog.Level, format string) *slog.Logger {
+	opts := &slog.HandlerOptions{Level: level}
+
+	var handler slog.Handler
+	switch strings.ToLower(format) {
+	case FormatJSON:
+		handler = slog.NewJSONHandler(w, opts)
+	default:
+		handler = slog.NewTextHandler(w, opts)
+	}
+
+	return slog.New(handler)
+}
+
+// ParseLevel converts a level name (debug, info, warn, error) to a slog.Level
+func ParseLevel(name string) (slog.Level, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "debug":
+		return slog.LevelDebug, nil
+	case "", "info":
+		return slog.LevelInfo, nil
+	case "warn", "warning":
+		return slog.LevelWarn, nil
+	case "error":
+		return slog.LevelError, nil
+	default:
+		return slog.LevelInfo, fmt.Errorf("unknown log level: %s", name)
+	}
+}
+
+// ValidFormat reports whether format is a supported output format
+func ValidFormat(format string) bool {
+	switch strings.ToLower(format) {
+	case FormatText, FormatJSON:
+		return true
+	}
+	return false
+}
+
+// WithComponent returns a child of l whose records carry a component group
+func WithComponent(l *slog.Logger, component string) *slog.Logger {
+	if l == nil {
+		l = slog.Default()
+	}
+	return l.With(slog.Group("component", slog.String("name", component)))
+}
+
+// For returns the process-wide default logger tagged with a component group.
+// It is resolved at call time so it always reflects slog.SetDefault.
+func For(component string) *slog.Logger {
+	return WithComponent(slog.Default(), component)
+}
+
+// requestIDKey is the context key for the per-request correlation ID
+type requestIDKey struct{}
+
+// WithRequestID returns a copy of ctx carrying the request correlation ID
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// RequestID returns the correlation ID stored in ctx, or "" if there is none
+func RequestID(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+// traceIDKey is the context key for the distributed trace ID
+type traceIDKey struct{}
+
+// WithTraceID returns a copy of ctx carrying a trace ID. Unlike the request
+// ID, a trace ID can be supplied by the caller and spans several requests.
+func WithTraceID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, traceIDKey{}, id)
+}
+
+// TraceID returns the trace ID stored in ctx, or "" if there is none
+func TraceID(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	id, _ := ctx.Value(traceIDKey{}).(string)
+	return id
+}
+
+// FromContext returns l tagged with the request and trace IDs carried by ctx,
+// so every record logged while handling a request can be correlated
+func FromContext(ctx context.Context, l *slog.Logger) *slog.Logger {
+	if l == nil {
+		l = slog.Default()
+	}
+	if id := RequestID(ctx); id != "" {
+		l = l.With("request_id", id)
+	}
+	if id := TraceID(ctx); id != "" {
+		l = l.With("trace_id", id)
+	}
+	return l
+}
@@ -0,0 +1,116 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestParseLevel(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    slog.Level
+		wantErr bool
+	}{
+		{"debug", slog.LevelDebug, false},
+		{"INFO", slog.LevelInfo, false},
+		{"", slog.LevelInfo, false},
+		{"warn", slog.LevelWarn, false},
+		{"warning", slog.LevelWarn, false},
+		{"error", slog.LevelError, false},
+		{"verbose", slog.LevelInfo, true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseLevel(tt.input)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseLevel(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+		}
+		if got != tt.want {
+			t.Errorf("ParseLevel(%q) = %v, want %v", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestNewLogger_JSONWithComponent(t *testing.T) {
+	var buf bytes.Buffer
+	l := WithComponent(NewLoggerWithWriter(&buf, slog.LevelInfo, FormatJSON), "spawner")
+
+	l.Info("agent spawned", "agent_id", "team-sntgreen001")
+	l.Debug("filtered out")
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 1 {
+		t.Fatalf("Expected 1 line (debug filtered), got %d: %q", len(lines), buf.String())
+	}
+
+	var record map[string]interface{}
+	if err := json.Unmarshal([]byte(lines[0]), &record); err != nil {
+		t.Fatalf("Output is not JSON: %v", err)
+	}
+
+	component, ok := record["component"].(map[string]interface{})
+	if !ok || component["name"] != "spawner" {
+		t.Errorf("Expected component group with name=spawner, got %v", record["component"])
+	}
+	if record["agent_id"] != "team-sntgreen001" {
+		t.Errorf("Expected agent_id attribute, got %v", record["agent_id"])
+	}
+}
+
+func TestNewLogger_TextDefault(t *testing.T) {
+	var buf bytes.Buffer
+	l := NewLoggerWithWriter(&buf, slog.LevelInfo, "unknown")
+	l.Info("hello", "key", "value")
+
+	if !strings.Contains(buf.String(), "key=value") {
+		t.Errorf("Expected text output, got %q", buf.String())
+	}
+}
+
+func TestFromContext_RequestID(t *testing.T) {
+	var buf bytes.Buffer
+	base := NewLoggerWithWriter(&buf, slog.LevelInfo, FormatJSON)
+
+	ctx := WithRequestID(context.Background(), "req-123")
+	if got := RequestID(ctx); got != "req-123" {
+		t.Fatalf("RequestID() = %q, want req-123", got)
+	}
+	FromContext(ctx, base).Info("handled")
+
+	var record map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &record); err != nil {
+		t.Fatalf("invalid JSON output: %v", err)
+	}
+	if record["request_id"] != "req-123" {
+		t.Errorf("request_id = %v, want req-123", record["request_id"])
+	}
+
+	buf.Reset()
+	FromContext(context.Background(), base).Info("no request")
+	if strings.Contains(buf.String(), "request_id") {
+		t.Errorf("expected no request_id without one in context, got %s", buf.String())
+	}
+}
+
+func TestFromContext_TraceID(t *testing.T) {
+	var buf bytes.Buffer
+	base := NewLoggerWithWriter(&buf, slog.LevelInfo, FormatJSON)
+
+	ctx := WithTraceID(WithRequestID(context.Background(), "req-123"), "trace-abc")
+	if got := TraceID(ctx); got != "trace-abc" {
+		t.Fatalf("TraceID() = %q, want trace-abc", got)
+	}
+	FromContext(ctx, base).Info("handled")
+
+	var record map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &record); err != nil {
+		t.Fatalf("invalid JSON output: %v", err)
+	}
+	if record["trace_id"] != "trace-abc" || record["request_id"] != "req-123" {
+		t.Errorf("record = %v, want trace_id and request_id", record)
+	}
+}
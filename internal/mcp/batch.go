@@ -0,0 +1,102 @@
+package mcp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+
+	"github.com/CLIAIMONITOR/internal/types"
+)
+
+// MaxBatchConcurrency bounds how many requests of one batch run at once
+const MaxBatchConcurrency = 10
+
+// errInvalidRequest is returned for well-formed JSON that is not a request
+var errInvalidRequest = NewRPCError(CodeInvalidRequest, "Invalid request: jsonrpc must be \"2.0\" and method is required", nil)
+
+// isBatch reports whether body is a JSON array of requests
+func isBatch(body []byte) bool {
+	trimmed := bytes.TrimLeft(body, " \t\r\n")
+	return len(trimmed) > 0 && trimmed[0] == '['
+}
+
+// validRequest reports whether req is a JSON-RPC 2.0 request object
+func validRequest(req *types.MCPRequest) bool {
+	return req.JSONRPC == "2.0" && req.Method != ""
+}
+
+// serveBatch handles a JSON-RPC batch. Requests run concurrently, up to
+// MaxBatchConcurrency at a time, and responses keep the batch order.
+// Notifications produce no response; a batch of only notifications returns
+// 202. A batch mixing successes and errors returns 207 Multi-Status.
+func (s *Server) serveBatch(w http.ResponseWriter, ctx context.Context, agentID string, body []byte) {
+	var items []json.RawMessage
+	if err := json.Unmarshal(body, &items); err != nil {
+		s.sendJSONError(w, nil, NewRPCError(CodeParseError, "Parse error", nil))
+		return
+	}
+	if len(items) == 0 {
+		s.sendJSONError(w, nil, NewRPCError(CodeInvalidRequest, "Invalid request: empty batch", nil))
+		return
+	}
+
+	responses := make([]*types.MCPResponse, len(items))
+	sem := make(chan struct{}, MaxBatchConcurrency)
+	var wg sync.WaitGroup
+	for i, item := range items {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(idx int, raw json.RawMessage) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			responses[idx] = s.handleBatchItem(ctx, agentID, raw)
+		}(i, item)
+	}
+	wg.Wait()
+
+	results := make([]types.MCPResponse, 0, len(responses))
+	failed := 0
+	for _, resp := range responses {
+		if resp == nil {
+			continue
+		}
+		if resp.Error != nil {
+			failed++
+		}
+		results = append(results, *resp)
+	}
+
+	if len(results) == 0 {
+		w.WriteHeader(http.StatusAccepted)
+		return
+	}
+
+	status := http.StatusOK
+	if failed > 0 && failed < len(results) {
+		status = http.StatusMultiStatus
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(results)
+}
+
+// handleBatchItem processes one batch entry, returning nil for notifications
+func (s *Server) handleBatchItem(ctx context.Context, agentID string, raw json.RawMessage) *types.MCPResponse {
+	var req types.MCPRequest
+	if err := json.Unmarshal(raw, &req); err != nil {
+		resp := errorResponse(nil, errInvalidRequest)
+		return &resp
+	}
+	if !validRequest(&req) {
+		resp := errorResponse(req.ID, errInvalidRequest)
+		return &resp
+	}
+
+	resp := s.handleRequest(ctx, agentID, &req)
+	if req.ID == nil {
+		return nil
+	}
+	return &resp
+}
@@ -0,0 +1,132 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/CLIAIMONITOR/internal/types"
+)
+
+// newBatchTestServer returns a server with an "echo" tool that succeeds and
+// a "fail" tool whose handler returns a plain error
+func newBatchTestServer() *Server {
+	s := NewServer()
+	s.RegisterTool(ToolDefinition{
+		Name: "echo",
+		Handler: func(ctx context.Context, agentID string, params map[string]interface{}) (interface{}, error) {
+			return params, nil
+		},
+	})
+	s.RegisterTool(ToolDefinition{
+		Name: "fail",
+		Handler: func(ctx context.Context, agentID string, params map[string]interface{}) (interface{}, error) {
+			return nil, errors.New("tool exploded")
+		},
+	})
+	return s
+}
+
+// postMCP sends body to the server's MCP endpoint as agent-1
+func postMCP(s *Server, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/mcp", strings.NewReader(body))
+	req.Header.Set("X-Agent-ID", "agent-1")
+	rec := httptest.NewRecorder()
+	s.ServeHTTP(rec, req)
+	return rec
+}
+
+// decodeBatch decodes a batch response body
+func decodeBatch(t *testing.T, rec *httptest.ResponseRecorder) []types.MCPResponse {
+	t.Helper()
+	var responses []types.MCPResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &responses); err != nil {
+		t.Fatalf("expected a batch response, got %q: %v", rec.Body.String(), err)
+	}
+	return responses
+}
+
+func TestServeBatchMixed(t *testing.T) {
+	s := newBatchTestServer()
+	rec := postMCP(s, `[
+		{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"echo","arguments":{}}},
+		{"jsonrpc":"2.0","method":"tools/call","params":{"name":"echo","arguments":{}}},
+		{"jsonrpc":"2.0","id":2,"method":"tools/call","params":{"name":"fail","arguments":{}}}
+	]`)
+
+	if rec.Code != http.StatusMultiStatus {
+		t.Errorf("status = %d, want %d", rec.Code, http.StatusMultiStatus)
+	}
+	responses := decodeBatch(t, rec)
+	if len(responses) != 2 {
+		t.Fatalf("expected a response per request with an id, got %+v", responses)
+	}
+	if responses[0].ID != float64(1) || responses[0].Error != nil || responses[0].Result == nil {
+		t.Errorf("first response = %+v, want a result for id 1", responses[0])
+	}
+	if responses[1].ID != float64(2) || responses[1].Error == nil || responses[1].Error.Code != int(CodeToolError) {
+		t.Errorf("second response = %+v, want a tool error for id 2", responses[1])
+	}
+
+	// A batch where every request succeeds is plain 200
+	rec = postMCP(s, `[{"jsonrpc":"2.0","id":1,"method":"initialize"},{"jsonrpc":"2.0","id":2,"method":"tools/list"}]`)
+	if rec.Code != http.StatusOK || len(decodeBatch(t, rec)) != 2 {
+		t.Errorf("status = %d, body %q; want 200 with two responses", rec.Code, rec.Body.String())
+	}
+}
+
+func TestServeBatchOnlyNotifications(t *testing.T) {
+	rec := postMCP(newBatchTestServer(), `[
+		{"jsonrpc":"2.0","method":"tools/call","params":{"name":"echo","arguments":{}}},
+		{"jsonrpc":"2.0","method":"initialize"}
+	]`)
+
+	if rec.Code != http.StatusAccepted {
+		t.Errorf("status = %d, want %d", rec.Code, http.StatusAccepted)
+	}
+	if rec.Body.Len() != 0 {
+		t.Errorf("expected an empty body, got %q", rec.Body.String())
+	}
+}
+
+func TestServeBatchEmpty(t *testing.T) {
+	rec := postMCP(newBatchTestServer(), ` [ ] `)
+
+	var resp types.MCPResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("expected a single error response, got %q: %v", rec.Body.String(), err)
+	}
+	if resp.ID != nil || resp.Error == nil || resp.Error.Code != int(CodeInvalidRequest) {
+		t.Errorf("response = %+v, want invalid request with a null id", resp)
+	}
+}
+
+func TestServeBatchInvalidMembers(t *testing.T) {
+	rec := postMCP(newBatchTestServer(), `[
+		1,
+		{"jsonrpc":"1.0","id":3,"method":"initialize"},
+		{"jsonrpc":"2.0","id":4},
+		{"jsonrpc":"2.0","id":5,"method":"initialize"}
+	]`)
+
+	if rec.Code != http.StatusMultiStatus {
+		t.Errorf("status = %d, want %d", rec.Code, http.StatusMultiStatus)
+	}
+	responses := decodeBatch(t, rec)
+	if len(responses) != 4 {
+		t.Fatalf("expected a response per member, got %+v", responses)
+	}
+	wantIDs := []interface{}{nil, float64(3), float64(4)}
+	for i, want := range wantIDs {
+		if responses[i].ID != want || responses[i].Error == nil || responses[i].Error.Code != int(CodeInvalidRequest) {
+			t.Errorf("response %d = %+v, want invalid request with id %v", i, responses[i], want)
+		}
+	}
+	if responses[3].ID != float64(5) || responses[3].Error != nil {
+		t.Errorf("valid member response = %+v, want a result for id 5", responses[3])
+	}
+}
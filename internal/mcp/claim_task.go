@@ -0,0 +1,96 @@
+package mcp
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/CLIAIMONITOR/internal/tasks"
+)
+
+// ClaimTaskInterval is the minimum time between an agent's claim_task calls,
+// so idle agents cannot poll the task store in a tight loop
+const ClaimTaskInterval = 5 * time.Second
+
+// TaskClaimer atomically assigns the next available task to an agent
+type TaskClaimer interface {
+	ClaimNextTask(agentID string, statuses []tasks.TaskStatus) (*tasks.Task, error)
+}
+
+// claimRateLimiter allows each agent one claim per ClaimTaskInterval
+type claimRateLimiter struct {
+	mu       sync.Mutex
+	lastCall map[string]time.Time
+	now      func() time.Time
+}
+
+// allow records a call for agentID and reports whether it is permitted;
+// when it is not, it returns how long the agent must wait
+func (l *claimRateLimiter) allow(agentID string) (bool, time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	if last, ok := l.lastCall[agentID]; ok {
+		if wait := ClaimTaskInterval - now.Sub(last); wait > 0 {
+			return false, wait
+		}
+	}
+	l.lastCall[agentID] = now
+	return true, 0
+}
+
+// RegisterClaimTaskTool registers the claim_task tool, which lets competing
+// agents take the next task without two of them getting the same one
+func RegisterClaimTaskTool(s *Server, claimer TaskClaimer) {
+	registerClaimTaskTool(s, claimer, time.Now)
+}
+
+func registerClaimTaskTool(s *Server, claimer TaskClaimer, now func() time.Time) {
+	limiter := &claimRateLimiter{lastCall: make(map[string]time.Time), now: now}
+
+	s.RegisterTool(ToolDefinition{
+		Name:        "claim_task",
+		Description: "Atomically claim the highest-priority available task and move it to in_progress. Safe when several agents claim at once: each task goes to exactly one agent. Limited to one call per 5 seconds per agent.",
+		Parameters: map[string]ParameterDef{
+			"statuses": {
+				Type:        "array",
+				Description: "Task statuses to claim from (default: ['pending'])",
+				Required:    false,
+			},
+		},
+		Handler: func(ctx context.Context, agentID string, params map[string]interface{}) (interface{}, error) {
+			if ok, wait := limiter.allow(agentID); !ok {
+				retryAfter := int(math.Ceil(wait.Seconds()))
+				return nil, NewRPCError(CodeRateLimited, "claim_task is limited to one call per 5 seconds", map[string]interface{}{
+					"retry_after_seconds": retryAfter,
+				})
+			}
+
+			var statuses []tasks.TaskStatus
+			if raw, ok := params["statuses"].([]interface{}); ok {
+				for _, st := range raw {
+					if status, ok := st.(string); ok {
+						statuses = append(statuses, tasks.TaskStatus(status))
+					}
+				}
+			}
+
+			task, err := claimer.ClaimNextTask(agentID, statuses)
+			if err != nil {
+				return nil, err
+			}
+			if task == nil {
+				return map[string]interface{}{
+					"claimed": false,
+					"reason":  "no_tasks_available",
+				}, nil
+			}
+			return map[string]interface{}{
+				"claimed": true,
+				"task":    task,
+			}, nil
+		},
+	})
+}
@@ -0,0 +1,110 @@
+package mcp
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// GenerateMarkdownDocs renders a Markdown reference for tools, in the order
+// given: each tool's description, a parameter table and an example
+// tools/call request body
+func GenerateMarkdownDocs(tools []ToolDefinition) string {
+	var sb strings.Builder
+	sb.WriteString("# MCP Tools Reference\n\n")
+	sb.WriteString(fmt.Sprintf("%d tools are available on the CLIAIMONITOR MCP server.\n", len(tools)))
+
+	for _, tool := range tools {
+		sb.WriteString(fmt.Sprintf("\n## %s\n\n", tool.Name))
+		if tool.Description != "" {
+			sb.WriteString(tool.Description + "\n\n")
+		}
+
+		names := sortedParameterNames(tool.Parameters)
+		if len(names) == 0 {
+			sb.WriteString("No parameters.\n\n")
+		} else {
+			sb.WriteString("| Parameter | Type | Required | Description |\n")
+			sb.WriteString("|-----------|------|----------|-------------|\n")
+			for _, name := range names {
+				def := tool.Parameters[name]
+				required := "no"
+				if def.Required {
+					required = "yes"
+				}
+				sb.WriteString(fmt.Sprintf("| `%s` | %s | %s | %s |\n", name, jsonSchemaType(def.Type), required, markdownCell(def.Description)))
+			}
+			sb.WriteString("\n")
+		}
+
+		sb.WriteString("Example call:\n\n```json\n")
+		sb.WriteString(exampleCallBody(tool))
+		sb.WriteString("\n```\n")
+	}
+	return sb.String()
+}
+
+// sortedParameterNames returns the parameter names, required ones first,
+// each group alphabetical
+func sortedParameterNames(params map[string]ParameterDef) []string {
+	names := make([]string, 0, len(params))
+	for name := range params {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		ri, rj := params[names[i]].Required, params[names[j]].Required
+		if ri != rj {
+			return ri
+		}
+		return names[i] < names[j]
+	})
+	return names
+}
+
+// markdownCell escapes text for use in a Markdown table cell
+func markdownCell(s string) string {
+	s = strings.ReplaceAll(s, "|", `\|`)
+	return strings.ReplaceAll(s, "\n", " ")
+}
+
+// exampleCallBody returns a JSON-RPC tools/call request for the tool with a
+// placeholder value for every parameter
+func exampleCallBody(tool ToolDefinition) string {
+	arguments := make(map[string]interface{}, len(tool.Parameters))
+	for name, def := range tool.Parameters {
+		arguments[name] = exampleValue(name, def)
+	}
+	body := map[string]interface{}{
+		"jsonrpc": "2.0",
+		"id":      1,
+		"method":  "tools/call",
+		"params": map[string]interface{}{
+			"name":      tool.Name,
+			"arguments": arguments,
+		},
+	}
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	enc.Encode(body)
+	return strings.TrimSuffix(buf.String(), "\n")
+}
+
+// exampleValue returns a placeholder of the parameter's JSON Schema type
+func exampleValue(name string, def ParameterDef) interface{} {
+	switch jsonSchemaType(def.Type) {
+	case "number", "integer":
+		return 1
+	case "boolean":
+		return true
+	case "array":
+		return []interface{}{}
+	case "object":
+		return map[string]interface{}{}
+	default:
+		return "<" + name + ">"
+	}
+}
@@ -0,0 +1,78 @@
+package mcp
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/CLIAIMONITOR/internal/types"
+)
+
+// ErrorCode is a JSON-RPC error code as used by the MCP specification
+type ErrorCode int
+
+// Standard JSON-RPC 2.0 error codes
+const (
+	CodeParseError     ErrorCode = -32700 // Invalid JSON was received
+	CodeInvalidRequest ErrorCode = -32600 // JSON is not a valid request object
+	CodeMethodNotFound ErrorCode = -32601 // Method does not exist
+	CodeInvalidParams  ErrorCode = -32602 // Invalid method or tool parameters
+	CodeInternalError  ErrorCode = -32603 // Internal JSON-RPC error
+)
+
+// Application error codes, from the implementation-defined -32000 to -32099
+// server error range
+const (
+	CodeServerErrorMax  ErrorCode = -32000
+	CodeServerErrorMin  ErrorCode = -32099
+	CodeToolError       ErrorCode = -32000 // A tool handler failed
+	CodeToolUnavailable ErrorCode = -32001 // A tool's backing service is not configured
+	CodeRateLimited     ErrorCode = -32029 // The caller is sending requests too quickly
+)
+
+// IsServerError reports whether c is in the application error range
+func (c ErrorCode) IsServerError() bool {
+	return c >= CodeServerErrorMin && c <= CodeServerErrorMax
+}
+
+// RPCError is a JSON-RPC error object. Tool handlers may return one as their
+// error to choose the code the client sees; any other error is reported as
+// CodeToolError.
+type RPCError struct {
+	Code    ErrorCode
+	Message string
+	Data    interface{}
+}
+
+// NewRPCError creates an RPC error with optional data
+func NewRPCError(code ErrorCode, msg string, data interface{}) RPCError {
+	return RPCError{Code: code, Message: msg, Data: data}
+}
+
+// Error implements the error interface
+func (e RPCError) Error() string {
+	return fmt.Sprintf("rpc error %d: %s", e.Code, e.Message)
+}
+
+// toMCP converts e to the wire representation used in responses
+func (e RPCError) toMCP() *types.MCPError {
+	return &types.MCPError{Code: int(e.Code), Message: e.Message, Data: e.Data}
+}
+
+// asRPCError returns err as an RPCError, wrapping errors that carry no code
+// with fallback
+func asRPCError(err error, fallback ErrorCode) RPCError {
+	var rpcErr RPCError
+	if errors.As(err, &rpcErr) {
+		return rpcErr
+	}
+	return NewRPCError(fallback, err.Error(), nil)
+}
+
+// errorResponse builds a JSON-RPC response carrying e
+func errorResponse(id interface{}, e RPCError) types.MCPResponse {
+	return types.MCPResponse{
+		JSONRPC: "2.0",
+		ID:      id,
+		Error:   e.toMCP(),
+	}
+}
@@ -0,0 +1,79 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/CLIAIMONITOR/internal/types"
+)
+
+// callTool posts a single tools/call request with params and decodes the response
+func callTool(t *testing.T, s *Server, params string) types.MCPResponse {
+	t.Helper()
+	rec := postMCP(s, `{"jsonrpc":"2.0","id":1,"method":"tools/call","params":`+params+`}`)
+	var resp types.MCPResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("expected a JSON-RPC response, got %q: %v", rec.Body.String(), err)
+	}
+	return resp
+}
+
+func TestServeHTTPErrorCodes(t *testing.T) {
+	s := newBatchTestServer()
+	s.RegisterTool(ToolDefinition{
+		Name: "limited",
+		Handler: func(ctx context.Context, agentID string, params map[string]interface{}) (interface{}, error) {
+			return nil, NewRPCError(CodeRateLimited, "slow down", map[string]interface{}{"retry_after_seconds": 5})
+		},
+	})
+
+	tests := []struct {
+		name     string
+		params   string
+		wantCode ErrorCode
+		wantData map[string]interface{}
+	}{
+		{"unknown tool", `{"name":"missing","arguments":{}}`, CodeInvalidParams, nil},
+		{"params not an object", `["echo"]`, CodeInvalidParams, nil},
+		{"no tool name", `{"arguments":{}}`, CodeInvalidParams, nil},
+		{"handler error", `{"name":"fail","arguments":{}}`, CodeToolError, nil},
+		{"handler RPC error", `{"name":"limited","arguments":{}}`, CodeRateLimited, map[string]interface{}{"retry_after_seconds": float64(5)}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := callTool(t, s, tt.params)
+			if resp.Error == nil {
+				t.Fatalf("expected an error, got %+v", resp)
+			}
+			if resp.Error.Code != int(tt.wantCode) {
+				t.Errorf("code = %d, want %d", resp.Error.Code, tt.wantCode)
+			}
+			if tt.wantData == nil {
+				if resp.Error.Data != nil {
+					t.Errorf("data = %v, want none", resp.Error.Data)
+				}
+				return
+			}
+			data, ok := resp.Error.Data.(map[string]interface{})
+			if !ok || len(data) != len(tt.wantData) {
+				t.Fatalf("data = %v, want %v", resp.Error.Data, tt.wantData)
+			}
+			for k, v := range tt.wantData {
+				if data[k] != v {
+					t.Errorf("data[%s] = %v, want %v", k, data[k], v)
+				}
+			}
+		})
+	}
+}
+
+func TestServeHTTPErrorMessages(t *testing.T) {
+	s := newBatchTestServer()
+	if resp := callTool(t, s, `{"name":"missing","arguments":{}}`); resp.Error == nil || resp.Error.Message != "unknown tool: missing" {
+		t.Errorf("unknown tool error = %+v", resp.Error)
+	}
+	if resp := callTool(t, s, `{"name":"fail","arguments":{}}`); resp.Error == nil || resp.Error.Message != "tool exploded" {
+		t.Errorf("handler error = %+v", resp.Error)
+	}
+}
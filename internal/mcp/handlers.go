@@ -2,6 +2,7 @@ package mcp
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -13,17 +14,21 @@ import (
 )
 
 // ToolCallbacks interface for tool handlers to call back into services
-// SIMPLIFIED: Only callbacks for tools we actually use
+// SIMPLIFIED: Only callbacks for tools we actually use. Callbacks that write
+// to storage receive the tool call's context, which carries its trace ID.
 type ToolCallbacks struct {
 	// Captain context callbacks (for session persistence)
-	OnSaveContext   func(key, value string, priority, maxAgeHours int) (interface{}, error)
-	OnGetAllContext func() (interface{}, error)
-	OnLogSession    func(sessionID, eventType, summary, details, agentID string) (interface{}, error)
+	OnSaveContext   func(ctx context.Context, key, value string, priority, maxAgeHours int) (interface{}, error)
+	OnGetAllContext func(ctx context.Context) (interface{}, error)
+	OnLogSession    func(ctx context.Context, sessionID, eventType, summary, details, agentID string) (interface{}, error)
 
 	// Captain messages callbacks (human -> Captain chat)
 	OnGetCaptainMessages  func() (interface{}, error)
 	OnMarkMessagesRead    func(ids []string) (interface{}, error)
 	OnSendCaptainResponse func(text string) (interface{}, error)
+
+	// Agent registration callback (agent announces itself after spawn)
+	OnRegisterAgent func(ctx context.Context, agentID, role string, capabilities []string) (interface{}, error)
 }
 
 // RegisterDefaultTools registers all standard MCP tools
@@ -32,6 +37,9 @@ func RegisterDefaultTools(s *Server, callbacks ToolCallbacks) {
 	// Context persistence tools
 	registerContextTools(s, callbacks)
 
+	// Agent registration tools
+	registerAgentTools(s, callbacks)
+
 	// WezTerm control tools
 	registerWezTermTools(s)
 }
@@ -48,9 +56,9 @@ func registerContextTools(s *Server, callbacks ToolCallbacks) {
 			"priority":      {Type: "number", Description: "Priority 1-10, higher = more important to preserve (default: 5)", Required: false},
 			"max_age_hours": {Type: "number", Description: "Auto-expire after this many hours, 0 = never expire (default: 24)", Required: false},
 		},
-		Handler: func(agentID string, params map[string]interface{}) (interface{}, error) {
+		Handler: func(ctx context.Context, agentID string, params map[string]interface{}) (interface{}, error) {
 			if callbacks.OnSaveContext == nil {
-				return map[string]interface{}{"error": "Context persistence not configured"}, nil
+				return nil, NewRPCError(CodeToolUnavailable, "Context persistence not configured", nil)
 			}
 			key, _ := params["key"].(string)
 			value, _ := params["value"].(string)
@@ -62,7 +70,7 @@ func registerContextTools(s *Server, callbacks ToolCallbacks) {
 			if m, ok := params["max_age_hours"].(float64); ok {
 				maxAgeHours = int(m)
 			}
-			return callbacks.OnSaveContext(key, value, priority, maxAgeHours)
+			return callbacks.OnSaveContext(ctx, key, value, priority, maxAgeHours)
 		},
 	})
 
@@ -71,11 +79,11 @@ func registerContextTools(s *Server, callbacks ToolCallbacks) {
 		Name:        "get_all_context",
 		Description: "Get all saved context entries from memory.db. Use this at startup to restore session state.",
 		Parameters:  map[string]ParameterDef{},
-		Handler: func(agentID string, params map[string]interface{}) (interface{}, error) {
+		Handler: func(ctx context.Context, agentID string, params map[string]interface{}) (interface{}, error) {
 			if callbacks.OnGetAllContext == nil {
-				return map[string]interface{}{"error": "Context persistence not configured"}, nil
+				return nil, NewRPCError(CodeToolUnavailable, "Context persistence not configured", nil)
 			}
-			return callbacks.OnGetAllContext()
+			return callbacks.OnGetAllContext(ctx)
 		},
 	})
 
@@ -88,14 +96,14 @@ func registerContextTools(s *Server, callbacks ToolCallbacks) {
 			"summary":    {Type: "string", Description: "Brief summary of the event", Required: true},
 			"details":    {Type: "string", Description: "Optional detailed information", Required: false},
 		},
-		Handler: func(agentID string, params map[string]interface{}) (interface{}, error) {
+		Handler: func(ctx context.Context, agentID string, params map[string]interface{}) (interface{}, error) {
 			if callbacks.OnLogSession == nil {
-				return map[string]interface{}{"error": "Session logging not configured"}, nil
+				return nil, NewRPCError(CodeToolUnavailable, "Session logging not configured", nil)
 			}
 			eventType, _ := params["event_type"].(string)
 			summary, _ := params["summary"].(string)
 			details, _ := params["details"].(string)
-			return callbacks.OnLogSession(agentID, eventType, summary, details, agentID)
+			return callbacks.OnLogSession(ctx, agentID, eventType, summary, details, agentID)
 		},
 	})
 
@@ -104,7 +112,7 @@ func registerContextTools(s *Server, callbacks ToolCallbacks) {
 		Name:        "get_captain_messages",
 		Description: "Get unread messages from human sent via dashboard chat. Captain should poll this periodically.",
 		Parameters:  map[string]ParameterDef{},
-		Handler: func(agentID string, params map[string]interface{}) (interface{}, error) {
+		Handler: func(ctx context.Context, agentID string, params map[string]interface{}) (interface{}, error) {
 			if callbacks.OnGetCaptainMessages == nil {
 				return map[string]interface{}{"messages": []interface{}{}, "count": 0}, nil
 			}
@@ -119,9 +127,9 @@ func registerContextTools(s *Server, callbacks ToolCallbacks) {
 		Parameters: map[string]ParameterDef{
 			"message_ids": {Type: "array", Description: "Array of message IDs to mark as read", Required: true},
 		},
-		Handler: func(agentID string, params map[string]interface{}) (interface{}, error) {
+		Handler: func(ctx context.Context, agentID string, params map[string]interface{}) (interface{}, error) {
 			if callbacks.OnMarkMessagesRead == nil {
-				return map[string]interface{}{"error": "Message marking not configured"}, nil
+				return nil, NewRPCError(CodeToolUnavailable, "Message marking not configured", nil)
 			}
 			var ids []string
 			if idsRaw, ok := params["message_ids"].([]interface{}); ok {
@@ -142,19 +150,57 @@ func registerContextTools(s *Server, callbacks ToolCallbacks) {
 		Parameters: map[string]ParameterDef{
 			"text": {Type: "string", Description: "The response message to send to the dashboard", Required: true},
 		},
-		Handler: func(agentID string, params map[string]interface{}) (interface{}, error) {
+		Handler: func(ctx context.Context, agentID string, params map[string]interface{}) (interface{}, error) {
 			if callbacks.OnSendCaptainResponse == nil {
-				return map[string]interface{}{"error": "Captain response not configured"}, nil
+				return nil, NewRPCError(CodeToolUnavailable, "Captain response not configured", nil)
 			}
 			text, _ := params["text"].(string)
 			if text == "" {
-				return map[string]interface{}{"error": "text parameter required"}, nil
+				return nil, NewRPCError(CodeInvalidParams, "text parameter required", nil)
 			}
 			return callbacks.OnSendCaptainResponse(text)
 		},
 	})
 }
 
+// registerAgentTools adds the register_agent handshake spawned agents call on startup
+func registerAgentTools(s *Server, callbacks ToolCallbacks) {
+	// register_agent - Agent announces itself and declares what it can do
+	s.RegisterTool(ToolDefinition{
+		Name:        "register_agent",
+		Description: "Register this agent with the dashboard. Call this first after starting, declaring your role and capabilities.",
+		Parameters: map[string]ParameterDef{
+			"agent_id":     {Type: "string", Description: "Your agent ID (defaults to the ID of this MCP connection)", Required: false},
+			"role":         {Type: "string", Description: "Your role (e.g., 'CodeImplementer', 'Reviewer')", Required: true},
+			"capabilities": {Type: "array", Description: "Array of capability names (e.g., 'go', 'testing', 'security-review')", Required: false},
+		},
+		Handler: func(ctx context.Context, agentID string, params map[string]interface{}) (interface{}, error) {
+			if callbacks.OnRegisterAgent == nil {
+				return nil, NewRPCError(CodeToolUnavailable, "Agent registration not configured", nil)
+			}
+			if id, ok := params["agent_id"].(string); ok && id != "" {
+				agentID = id
+			}
+			if agentID == "" {
+				return nil, NewRPCError(CodeInvalidParams, "agent_id parameter required", nil)
+			}
+			role, _ := params["role"].(string)
+			if role == "" {
+				return nil, NewRPCError(CodeInvalidParams, "role parameter required", nil)
+			}
+			var capabilities []string
+			if capsRaw, ok := params["capabilities"].([]interface{}); ok {
+				for _, c := range capsRaw {
+					if s, ok := c.(string); ok && s != "" {
+						capabilities = append(capabilities, s)
+					}
+				}
+			}
+			return callbacks.OnRegisterAgent(ctx, agentID, role, capabilities)
+		},
+	})
+}
+
 // RegisterWaitForEventsTool registers the wait_for_events tool for real-time event polling
 func RegisterWaitForEventsTool(s *Server, bus *events.Bus) {
 	s.RegisterTool(ToolDefinition{
@@ -172,7 +218,7 @@ func RegisterWaitForEventsTool(s *Server, bus *events.Bus) {
 				Required:    false,
 			},
 		},
-		Handler: func(agentID string, params map[string]interface{}) (interface{}, error) {
+		Handler: func(ctx context.Context, agentID string, params map[string]interface{}) (interface{}, error) {
 			timeout := 60.0
 			if t, ok := params["timeout_seconds"].(float64); ok {
 				timeout = t
@@ -239,7 +285,7 @@ func RegisterSendToAgentTool(s *Server, bus *events.Bus) {
 			"content":      {Type: "string", Description: "Message content or task description", Required: true},
 			"branch_name":  {Type: "string", Description: "Git branch name for task work", Required: false},
 		},
-		Handler: func(agentID string, params map[string]interface{}) (interface{}, error) {
+		Handler: func(ctx context.Context, agentID string, params map[string]interface{}) (interface{}, error) {
 			targetAgent, _ := params["target_agent"].(string)
 			messageType, _ := params["message_type"].(string)
 			content, _ := params["content"].(string)
@@ -247,13 +293,13 @@ func RegisterSendToAgentTool(s *Server, bus *events.Bus) {
 			branchName, _ := params["branch_name"].(string)
 
 			if targetAgent == "" {
-				return map[string]interface{}{"error": "target_agent is required"}, nil
+				return nil, NewRPCError(CodeInvalidParams, "target_agent is required", nil)
 			}
 			if messageType == "" {
-				return map[string]interface{}{"error": "message_type is required"}, nil
+				return nil, NewRPCError(CodeInvalidParams, "message_type is required", nil)
 			}
 			if content == "" {
-				return map[string]interface{}{"error": "content is required"}, nil
+				return nil, NewRPCError(CodeInvalidParams, "content is required", nil)
 			}
 
 			payload := map[string]interface{}{
@@ -297,7 +343,7 @@ func registerWezTermTools(s *Server) {
 		Name:        "wezterm_list_panes",
 		Description: "List all panes in WezTerm with their IDs, titles, and working directories.",
 		Parameters:  map[string]ParameterDef{},
-		Handler: func(agentID string, params map[string]interface{}) (interface{}, error) {
+		Handler: func(ctx context.Context, agentID string, params map[string]interface{}) (interface{}, error) {
 			panes, err := wezterm.Get().ListPanes()
 			if err != nil {
 				return map[string]interface{}{"success": false, "error": err.Error()}, nil
@@ -315,18 +361,18 @@ func registerWezTermTools(s *Server) {
 			"text":    {Type: "string", Description: "Text or command to send to the pane", Required: true},
 			"execute": {Type: "boolean", Description: "If true, append Enter key (CR+LF) to execute the command. Default: false", Required: false},
 		},
-		Handler: func(agentID string, params map[string]interface{}) (interface{}, error) {
+		Handler: func(ctx context.Context, agentID string, params map[string]interface{}) (interface{}, error) {
 			paneIDStr, _ := params["pane_id"].(string)
 			text, _ := params["text"].(string)
 			execute, _ := params["execute"].(bool)
 
 			if paneIDStr == "" || text == "" {
-				return map[string]interface{}{"error": "pane_id and text are required"}, nil
+				return nil, NewRPCError(CodeInvalidParams, "pane_id and text are required", nil)
 			}
 
 			paneID, err := strconv.Atoi(paneIDStr)
 			if err != nil {
-				return map[string]interface{}{"success": false, "error": fmt.Sprintf("invalid pane_id: %s", paneIDStr)}, nil
+				return nil, NewRPCError(CodeInvalidParams, fmt.Sprintf("invalid pane_id: %s", paneIDStr), nil)
 			}
 
 			if err := wezterm.Get().SendText(paneID, text, execute); err != nil {
@@ -344,15 +390,15 @@ func registerWezTermTools(s *Server) {
 		Parameters: map[string]ParameterDef{
 			"pane_id": {Type: "string", Description: "Pane ID to close", Required: true},
 		},
-		Handler: func(agentID string, params map[string]interface{}) (interface{}, error) {
+		Handler: func(ctx context.Context, agentID string, params map[string]interface{}) (interface{}, error) {
 			paneIDStr, _ := params["pane_id"].(string)
 			if paneIDStr == "" {
-				return map[string]interface{}{"error": "pane_id is required"}, nil
+				return nil, NewRPCError(CodeInvalidParams, "pane_id is required", nil)
 			}
 
 			paneID, err := strconv.Atoi(paneIDStr)
 			if err != nil {
-				return map[string]interface{}{"success": false, "error": fmt.Sprintf("invalid pane_id: %s", paneIDStr)}, nil
+				return nil, NewRPCError(CodeInvalidParams, fmt.Sprintf("invalid pane_id: %s", paneIDStr), nil)
 			}
 
 			if err := wezterm.Get().GracefulKillPane(paneID); err != nil {
@@ -370,10 +416,10 @@ func registerWezTermTools(s *Server) {
 		Parameters: map[string]ParameterDef{
 			"pane_ids": {Type: "array", Description: "Array of pane IDs to close (e.g., [2, 3, 4])", Required: true},
 		},
-		Handler: func(agentID string, params map[string]interface{}) (interface{}, error) {
+		Handler: func(ctx context.Context, agentID string, params map[string]interface{}) (interface{}, error) {
 			paneIDsRaw, ok := params["pane_ids"].([]interface{})
 			if !ok || len(paneIDsRaw) == 0 {
-				return map[string]interface{}{"error": "pane_ids array is required"}, nil
+				return nil, NewRPCError(CodeInvalidParams, "pane_ids array is required", nil)
 			}
 
 			var paneIDs []int
@@ -386,10 +432,10 @@ func registerWezTermTools(s *Server) {
 					var err error
 					paneID, err = strconv.Atoi(v)
 					if err != nil {
-						return map[string]interface{}{"success": false, "error": fmt.Sprintf("invalid pane_id: %v", idRaw)}, nil
+						return nil, NewRPCError(CodeInvalidParams, fmt.Sprintf("invalid pane_id: %v", idRaw), nil)
 					}
 				default:
-					return map[string]interface{}{"success": false, "error": fmt.Sprintf("invalid pane_id type: %T", idRaw)}, nil
+					return nil, NewRPCError(CodeInvalidParams, fmt.Sprintf("invalid pane_id type: %T", idRaw), nil)
 				}
 				paneIDs = append(paneIDs, paneID)
 			}
@@ -424,15 +470,15 @@ func registerWezTermTools(s *Server) {
 		Parameters: map[string]ParameterDef{
 			"pane_id": {Type: "string", Description: "Pane ID to focus", Required: true},
 		},
-		Handler: func(agentID string, params map[string]interface{}) (interface{}, error) {
+		Handler: func(ctx context.Context, agentID string, params map[string]interface{}) (interface{}, error) {
 			paneIDStr, _ := params["pane_id"].(string)
 			if paneIDStr == "" {
-				return map[string]interface{}{"error": "pane_id is required"}, nil
+				return nil, NewRPCError(CodeInvalidParams, "pane_id is required", nil)
 			}
 
 			paneID, err := strconv.Atoi(paneIDStr)
 			if err != nil {
-				return map[string]interface{}{"success": false, "error": fmt.Sprintf("invalid pane_id: %s", paneIDStr)}, nil
+				return nil, NewRPCError(CodeInvalidParams, fmt.Sprintf("invalid pane_id: %s", paneIDStr), nil)
 			}
 
 			if err := wezterm.Get().FocusPane(paneID); err != nil {
@@ -452,15 +498,15 @@ func registerWezTermTools(s *Server) {
 			"start_line": {Type: "number", Description: "Starting line number (0 = first line of screen, negative = scrollback). Default: -50", Required: false},
 			"end_line":   {Type: "number", Description: "Ending line number. Default: bottom of screen", Required: false},
 		},
-		Handler: func(agentID string, params map[string]interface{}) (interface{}, error) {
+		Handler: func(ctx context.Context, agentID string, params map[string]interface{}) (interface{}, error) {
 			paneIDStr, _ := params["pane_id"].(string)
 			if paneIDStr == "" {
-				return map[string]interface{}{"error": "pane_id is required"}, nil
+				return nil, NewRPCError(CodeInvalidParams, "pane_id is required", nil)
 			}
 
 			paneID, err := strconv.Atoi(paneIDStr)
 			if err != nil {
-				return map[string]interface{}{"success": false, "error": fmt.Sprintf("invalid pane_id: %s", paneIDStr)}, nil
+				return nil, NewRPCError(CodeInvalidParams, fmt.Sprintf("invalid pane_id: %s", paneIDStr), nil)
 			}
 
 			startLine := -50
@@ -490,13 +536,13 @@ func registerWezTermTools(s *Server) {
 			"project_path": {Type: "string", Description: "Working directory path for the agent", Required: true},
 			"task":         {Type: "string", Description: "Initial task/prompt for the agent to work on", Required: true},
 		},
-		Handler: func(agentID string, params map[string]interface{}) (interface{}, error) {
+		Handler: func(ctx context.Context, agentID string, params map[string]interface{}) (interface{}, error) {
 			configName, _ := params["config_name"].(string)
 			projectPath, _ := params["project_path"].(string)
 			task, _ := params["task"].(string)
 
 			if configName == "" || projectPath == "" || task == "" {
-				return map[string]interface{}{"success": false, "error": "config_name, project_path, and task are all required"}, nil
+				return nil, NewRPCError(CodeInvalidParams, "config_name, project_path, and task are all required", nil)
 			}
 
 			reqBody := map[string]string{
@@ -0,0 +1,47 @@
+package mcp
+
+import (
+	"context"
+	"testing"
+)
+
+func TestRegisterAgentTool(t *testing.T) {
+	var gotID, gotRole string
+	var gotCaps []string
+	s := NewServer()
+	registerAgentTools(s, ToolCallbacks{
+		OnRegisterAgent: func(ctx context.Context, agentID, role string, capabilities []string) (interface{}, error) {
+			gotID, gotRole, gotCaps = agentID, role, capabilities
+			return map[string]interface{}{"success": true}, nil
+		},
+	})
+
+	resp := callTool(t, s, `{"name":"register_agent","arguments":{"role":"Reviewer","capabilities":["go","",7,"testing"]}}`)
+	if resp.Error != nil {
+		t.Fatalf("register_agent failed: %+v", resp.Error)
+	}
+	if gotID != "agent-1" || gotRole != "Reviewer" {
+		t.Errorf("callback got agent %q role %q, want agent-1 Reviewer", gotID, gotRole)
+	}
+	if len(gotCaps) != 2 || gotCaps[0] != "go" || gotCaps[1] != "testing" {
+		t.Errorf("capabilities = %v, want [go testing]", gotCaps)
+	}
+
+	// An explicit agent_id overrides the connection's ID
+	callTool(t, s, `{"name":"register_agent","arguments":{"agent_id":"team-sntgreen001","role":"Reviewer"}}`)
+	if gotID != "team-sntgreen001" {
+		t.Errorf("callback got agent %q, want team-sntgreen001", gotID)
+	}
+
+	if resp := callTool(t, s, `{"name":"register_agent","arguments":{}}`); resp.Error == nil || resp.Error.Code != int(CodeInvalidParams) {
+		t.Errorf("missing role error = %+v, want invalid params", resp.Error)
+	}
+}
+
+func TestRegisterAgentToolUnconfigured(t *testing.T) {
+	s := NewServer()
+	registerAgentTools(s, ToolCallbacks{})
+	if resp := callTool(t, s, `{"name":"register_agent","arguments":{"role":"Reviewer"}}`); resp.Error == nil || resp.Error.Code != int(CodeToolUnavailable) {
+		t.Errorf("error = %+v, want tool unavailable", resp.Error)
+	}
+}
@@ -0,0 +1,116 @@
+package mcp
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/CLIAIMONITOR/internal/tasks"
+	_ "modernc.org/sqlite"
+)
+
+// newClaimTestStore creates a task store backed by a temporary SQLite file
+func newClaimTestStore(t *testing.T) *tasks.Store {
+	t.Helper()
+	// Busy timeout lets concurrent claims wait for the write lock
+	db, err := sql.Open("sqlite", filepath.Join(t.TempDir(), "tasks.db")+"?_pragma=busy_timeout(5000)")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	store := tasks.NewStore(db)
+	if err := store.Init(); err != nil {
+		t.Fatal(err)
+	}
+	return store
+}
+
+// TestClaimTask_ConcurrentAgents tests that two agents claiming at the same
+// time never both get the single pending task
+func TestClaimTask_ConcurrentAgents(t *testing.T) {
+	store := newClaimTestStore(t)
+	if err := store.Save(tasks.NewTask("Fix login", "", tasks.DefaultPriority)); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	server := NewServer()
+	RegisterClaimTaskTool(server, store)
+
+	agents := []string{"team-sntgreen001", "team-sntpurple001"}
+	results := make([]map[string]interface{}, len(agents))
+	errs := make([]error, len(agents))
+
+	var wg sync.WaitGroup
+	start := make(chan struct{})
+	for i, agentID := range agents {
+		wg.Add(1)
+		go func(i int, agentID string) {
+			defer wg.Done()
+			<-start
+			result, err := server.tools.Execute(context.Background(), "claim_task", agentID, map[string]interface{}{})
+			errs[i] = err
+			results[i], _ = result.(map[string]interface{})
+		}(i, agentID)
+	}
+	close(start)
+	wg.Wait()
+
+	claimed := 0
+	for i, result := range results {
+		if errs[i] != nil {
+			t.Fatalf("claim_task for %s failed: %v", agents[i], errs[i])
+		}
+		if result["claimed"] == true {
+			claimed++
+			task := result["task"].(*tasks.Task)
+			if task.AssignedTo != agents[i] || task.Status != tasks.StatusInProgress {
+				t.Errorf("Unexpected claimed task: %+v", task)
+			}
+		} else if result["reason"] != "no_tasks_available" {
+			t.Errorf("Unexpected result for %s: %v", agents[i], result)
+		}
+	}
+	if claimed != 1 {
+		t.Errorf("Expected exactly one agent to claim the task, got %d", claimed)
+	}
+}
+
+// TestClaimTask_RateLimited tests that an agent may claim once per ClaimTaskInterval
+func TestClaimTask_RateLimited(t *testing.T) {
+	store := newClaimTestStore(t)
+	now := time.Now()
+	server := NewServer()
+	registerClaimTaskTool(server, store, func() time.Time { return now })
+
+	claim := func(agentID string) (interface{}, error) {
+		return server.tools.Execute(context.Background(), "claim_task", agentID, map[string]interface{}{})
+	}
+
+	if _, err := claim("team-sntgreen001"); err != nil {
+		t.Fatalf("First claim failed: %v", err)
+	}
+
+	now = now.Add(2 * time.Second)
+	_, err := claim("team-sntgreen001")
+	var rpcErr RPCError
+	if !errors.As(err, &rpcErr) || rpcErr.Code != CodeRateLimited {
+		t.Fatalf("Expected rate limit error, got %v", err)
+	}
+	if data, _ := rpcErr.Data.(map[string]interface{}); data["retry_after_seconds"] != 3 {
+		t.Errorf("Expected retry_after_seconds 3, got %v", rpcErr.Data)
+	}
+
+	if _, err := claim("team-sntpurple001"); err != nil {
+		t.Errorf("Other agents should not be limited: %v", err)
+	}
+
+	now = now.Add(3 * time.Second)
+	if _, err := claim("team-sntgreen001"); err != nil {
+		t.Errorf("Claim after interval failed: %v", err)
+	}
+}
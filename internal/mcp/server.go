@@ -1,24 +1,46 @@
 package mcp
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
+	"os"
+	"runtime/debug"
+	"strconv"
 
+	"github.com/CLIAIMONITOR/internal/logger"
 	"github.com/CLIAIMONITOR/internal/types"
+	"github.com/google/uuid"
 )
 
+// TraceIDHeader carries a distributed trace ID. Agents may send one to tie
+// several tool calls together; otherwise each request gets a new UUID. The
+// ID is echoed on the response and passed to tool handlers in their context.
+const TraceIDHeader = "X-Trace-ID"
+
+// MaxTraceIDLength is the longest caller-supplied trace ID accepted; longer
+// ones are replaced with a generated ID
+const MaxTraceIDLength = 128
+
+// PanicOnToolErrorEnv, when true, makes a panicking tool handler re-panic
+// after it is logged, so test environments surface the failure
+const PanicOnToolErrorEnv = "MCP_PANIC_ON_TOOL_ERROR"
+
 // Server implements MCP over HTTP (POST-only JSON-RPC)
 type Server struct {
-	tools      *ToolRegistry
-	onToolCall func(agentID string, toolName string)
+	tools            *ToolRegistry
+	onToolCall       func(agentID string, toolName string)
+	panicOnToolError bool
 }
 
 // NewServer creates a new MCP server
 func NewServer() *Server {
+	panicOnToolError, _ := strconv.ParseBool(os.Getenv(PanicOnToolErrorEnv))
 	return &Server{
-		tools: NewToolRegistry(),
+		tools:            NewToolRegistry(),
+		panicOnToolError: panicOnToolError,
 	}
 }
 
@@ -32,6 +54,29 @@ func (s *Server) RegisterTool(tool ToolDefinition) {
 	s.tools.Register(tool)
 }
 
+// DeregisterTool removes a tool at runtime, returning false if it was not
+// registered. Calls already running finish; later calls get "unknown tool".
+func (s *Server) DeregisterTool(name string) bool {
+	return s.tools.Deregister(name)
+}
+
+// ReplaceToolHandler swaps a registered tool's handler at runtime, returning
+// false if the tool is not registered
+func (s *Server) ReplaceToolHandler(name string, handler ToolHandler) bool {
+	return s.tools.ReplaceHandler(name, handler)
+}
+
+// ListTools returns the registered tools as MCP tool descriptions, sorted
+// by name
+func (s *Server) ListTools() []map[string]interface{} {
+	return s.tools.List()
+}
+
+// ToolDefinitions returns the registered tool definitions sorted by name
+func (s *Server) ToolDefinitions() []ToolDefinition {
+	return s.tools.Definitions()
+}
+
 // ServeHTTP handles MCP requests (POST-only JSON-RPC)
 func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	// Get agent ID from header (required)
@@ -49,6 +94,13 @@ func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	traceID := r.Header.Get(TraceIDHeader)
+	if traceID == "" || len(traceID) > MaxTraceIDLength {
+		traceID = uuid.New().String()
+	}
+	w.Header().Set(TraceIDHeader, traceID)
+	ctx := logger.WithTraceID(r.Context(), traceID)
+
 	// Read request body
 	body, err := io.ReadAll(r.Body)
 	if err != nil {
@@ -56,15 +108,25 @@ func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// A JSON array is a batch of requests
+	if isBatch(body) {
+		s.serveBatch(w, ctx, agentID, body)
+		return
+	}
+
 	// Parse JSON-RPC request
 	var req types.MCPRequest
 	if err := json.Unmarshal(body, &req); err != nil {
-		s.sendJSONError(w, nil, -32700, "Parse error")
+		s.sendJSONError(w, nil, NewRPCError(CodeParseError, "Parse error", nil))
+		return
+	}
+	if !validRequest(&req) {
+		s.sendJSONError(w, req.ID, errInvalidRequest)
 		return
 	}
 
 	// Handle request
-	resp := s.handleRequest(agentID, &req)
+	resp := s.handleRequest(ctx, agentID, &req)
 
 	// If request is a notification (no ID), return 202 Accepted
 	if req.ID == nil {
@@ -79,38 +141,24 @@ func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 }
 
 // sendJSONError sends a JSON-RPC error response
-func (s *Server) sendJSONError(w http.ResponseWriter, id interface{}, code int, message string) {
-	resp := types.MCPResponse{
-		JSONRPC: "2.0",
-		ID:      id,
-		Error: &types.MCPError{
-			Code:    code,
-			Message: message,
-		},
-	}
+func (s *Server) sendJSONError(w http.ResponseWriter, id interface{}, rpcErr RPCError) {
+	resp := errorResponse(id, rpcErr)
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
 	json.NewEncoder(w).Encode(resp)
 }
 
 // handleRequest processes an MCP request
-func (s *Server) handleRequest(agentID string, req *types.MCPRequest) types.MCPResponse {
+func (s *Server) handleRequest(ctx context.Context, agentID string, req *types.MCPRequest) types.MCPResponse {
 	switch req.Method {
 	case "initialize":
 		return s.handleInitialize(req)
 	case "tools/list":
 		return s.handleToolsList(req)
 	case "tools/call":
-		return s.handleToolsCall(agentID, req)
+		return s.handleToolsCall(ctx, agentID, req)
 	default:
-		return types.MCPResponse{
-			JSONRPC: "2.0",
-			ID:      req.ID,
-			Error: &types.MCPError{
-				Code:    -32601,
-				Message: fmt.Sprintf("Method not found: %s", req.Method),
-			},
-		}
+		return errorResponse(req.ID, NewRPCError(CodeMethodNotFound, fmt.Sprintf("Method not found: %s", req.Method), nil))
 	}
 }
 
@@ -146,18 +194,11 @@ func (s *Server) handleToolsList(req *types.MCPRequest) types.MCPResponse {
 }
 
 // handleToolsCall executes a tool
-func (s *Server) handleToolsCall(agentID string, req *types.MCPRequest) types.MCPResponse {
+func (s *Server) handleToolsCall(ctx context.Context, agentID string, req *types.MCPRequest) types.MCPResponse {
 	// Parse params
 	params, ok := req.Params.(map[string]interface{})
 	if !ok {
-		return types.MCPResponse{
-			JSONRPC: "2.0",
-			ID:      req.ID,
-			Error: &types.MCPError{
-				Code:    -32602,
-				Message: "Invalid params",
-			},
-		}
+		return errorResponse(req.ID, NewRPCError(CodeInvalidParams, "Invalid params", nil))
 	}
 
 	toolName, _ := params["name"].(string)
@@ -169,27 +210,15 @@ func (s *Server) handleToolsCall(agentID string, req *types.MCPRequest) types.MC
 	}
 
 	if toolName == "" {
-		return types.MCPResponse{
-			JSONRPC: "2.0",
-			ID:      req.ID,
-			Error: &types.MCPError{
-				Code:    -32602,
-				Message: "Tool name required",
-			},
-		}
+		return errorResponse(req.ID, NewRPCError(CodeInvalidParams, "Tool name required", nil))
 	}
 
 	// Execute tool
-	result, err := s.tools.Execute(toolName, agentID, toolArgs)
+	result, err := s.executeTool(ctx, toolName, agentID, toolArgs)
 	if err != nil {
-		return types.MCPResponse{
-			JSONRPC: "2.0",
-			ID:      req.ID,
-			Error: &types.MCPError{
-				Code:    -32000,
-				Message: err.Error(),
-			},
-		}
+		rpcErr := asRPCError(err, CodeToolError)
+		logger.FromContext(ctx, logger.For("mcp")).Warn("tool call failed", "agent_id", agentID, "tool", toolName, "code", int(rpcErr.Code), "error", rpcErr.Message)
+		return errorResponse(req.ID, rpcErr)
 	}
 
 	// Format result as text content
@@ -211,3 +240,21 @@ func (s *Server) handleToolsCall(agentID string, req *types.MCPRequest) types.MC
 		},
 	}
 }
+
+// executeTool runs a tool, reporting a handler panic as CodeInternalError
+// instead of taking down the connection. The panic and its stack are logged;
+// with MCP_PANIC_ON_TOOL_ERROR set the panic is re-raised afterwards.
+func (s *Server) executeTool(ctx context.Context, name, agentID string, args map[string]interface{}) (result interface{}, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			logger.FromContext(ctx, logger.For("mcp")).Error("tool handler panicked",
+				"agent_id", agentID, "tool", name, "panic", fmt.Sprint(r), "stack", string(debug.Stack()))
+			if s.panicOnToolError {
+				panic(r)
+			}
+			result = nil
+			err = NewRPCError(CodeInternalError, fmt.Sprintf("tool %s panicked: %v", name, r), nil)
+		}
+	}()
+	return s.tools.Execute(ctx, name, agentID, args)
+}
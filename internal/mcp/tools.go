@@ -1,15 +1,26 @@
 package mcp
 
 import (
+	"context"
 	"fmt"
+	"sort"
+	"strings"
+	"sync"
 )
 
-// ToolHandler processes a tool call and returns result
-type ToolHandler func(agentID string, params map[string]interface{}) (interface{}, error)
+// ToolHandler processes a tool call and returns result. ctx carries the
+// call's trace ID (see TraceIDHeader) and is cancelled if the caller goes away.
+type ToolHandler func(ctx context.Context, agentID string, params map[string]interface{}) (interface{}, error)
 
-// ToolRegistry manages available MCP tools
+// ToolRegistry manages available MCP tools. Tools can be added, removed
+// and have their handlers replaced while the server is running.
 type ToolRegistry struct {
+	mu    sync.RWMutex
 	tools map[string]ToolDefinition
+
+	// schemaMu guards schema, the tools/list result built on first use
+	schemaMu sync.Mutex
+	schema   []map[string]interface{}
 }
 
 // ToolDefinition describes an MCP tool
@@ -36,31 +47,108 @@ func NewToolRegistry() *ToolRegistry {
 
 // Register adds a tool to the registry
 func (r *ToolRegistry) Register(tool ToolDefinition) {
+	r.mu.Lock()
 	r.tools[tool.Name] = tool
+	r.mu.Unlock()
+	r.invalidateSchema()
+}
+
+// Deregister removes a tool, returning false if it was not registered
+func (r *ToolRegistry) Deregister(name string) bool {
+	r.mu.Lock()
+	_, ok := r.tools[name]
+	delete(r.tools, name)
+	r.mu.Unlock()
+
+	if ok {
+		r.invalidateSchema()
+	}
+	return ok
+}
+
+// ReplaceHandler swaps the handler of a registered tool, returning false if
+// the tool is not registered or handler is nil
+func (r *ToolRegistry) ReplaceHandler(name string, handler ToolHandler) bool {
+	if handler == nil {
+		return false
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	tool, ok := r.tools[name]
+	if !ok {
+		return false
+	}
+	tool.Handler = handler
+	r.tools[name] = tool
+	return true
+}
+
+// invalidateSchema drops the cached tools/list result
+func (r *ToolRegistry) invalidateSchema() {
+	r.schemaMu.Lock()
+	r.schema = nil
+	r.schemaMu.Unlock()
 }
 
 // Get returns a tool by name
 func (r *ToolRegistry) Get(name string) (ToolDefinition, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
 	tool, ok := r.tools[name]
 	return tool, ok
 }
 
-// List returns all tool definitions (for MCP tools/list)
+// List returns all tool definitions sorted by name (for MCP tools/list).
+// The result is built once and reused until the set of tools changes.
 func (r *ToolRegistry) List() []map[string]interface{} {
-	var tools []map[string]interface{}
+	r.schemaMu.Lock()
+	defer r.schemaMu.Unlock()
+
+	if r.schema == nil {
+		r.schema = r.buildSchema()
+	}
+	return r.schema
+}
+
+// Definitions returns the registered tool definitions sorted by name
+func (r *ToolRegistry) Definitions() []ToolDefinition {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	defs := make([]ToolDefinition, 0, len(r.tools))
 	for _, tool := range r.tools {
+		defs = append(defs, tool)
+	}
+	sort.Slice(defs, func(i, j int) bool { return defs[i].Name < defs[j].Name })
+	return defs
+}
+
+// buildSchema converts the registered tools to MCP tool descriptions with a
+// JSON Schema inputSchema
+func (r *ToolRegistry) buildSchema() []map[string]interface{} {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	names := make([]string, 0, len(r.tools))
+	for name := range r.tools {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	tools := make([]map[string]interface{}, 0, len(names))
+	for _, toolName := range names {
+		tool := r.tools[toolName]
 		params := make(map[string]interface{})
 		required := []string{}
 
 		for name, def := range tool.Parameters {
-			params[name] = map[string]interface{}{
-				"type":        def.Type,
-				"description": def.Description,
-			}
+			params[name] = parameterSchema(def)
 			if def.Required {
 				required = append(required, name)
 			}
 		}
+		sort.Strings(required)
 
 		tools = append(tools, map[string]interface{}{
 			"name":        tool.Name,
@@ -75,13 +163,45 @@ func (r *ToolRegistry) List() []map[string]interface{} {
 	return tools
 }
 
+// parameterSchema returns the JSON Schema for one tool parameter
+func parameterSchema(def ParameterDef) map[string]interface{} {
+	schema := map[string]interface{}{
+		"type":        jsonSchemaType(def.Type),
+		"description": def.Description,
+	}
+	if schema["type"] == "array" {
+		// Element types are not declared, so accept any item
+		schema["items"] = map[string]interface{}{}
+	}
+	return schema
+}
+
+// jsonSchemaType maps a ParameterDef type to a JSON Schema type. Unknown
+// types fall back to string.
+func jsonSchemaType(t string) string {
+	switch strings.ToLower(strings.TrimSpace(t)) {
+	case "number", "float", "float64", "double":
+		return "number"
+	case "integer", "int", "int64":
+		return "integer"
+	case "boolean", "bool":
+		return "boolean"
+	case "array", "list", "[]string":
+		return "array"
+	case "object", "map":
+		return "object"
+	default:
+		return "string"
+	}
+}
+
 // Execute runs a tool by name
-func (r *ToolRegistry) Execute(name string, agentID string, params map[string]interface{}) (interface{}, error) {
-	tool, ok := r.tools[name]
+func (r *ToolRegistry) Execute(ctx context.Context, name string, agentID string, params map[string]interface{}) (interface{}, error) {
+	tool, ok := r.Get(name)
 	if !ok {
-		return nil, fmt.Errorf("unknown tool: %s", name)
+		return nil, NewRPCError(CodeInvalidParams, fmt.Sprintf("unknown tool: %s", name), nil)
 	}
-	return tool.Handler(agentID, params)
+	return tool.Handler(ctx, agentID, params)
 }
 
 // Tool represents a tool definition with JSON schema
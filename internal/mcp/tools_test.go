@@ -1,8 +1,16 @@
 package mcp
 
 import (
+	"context"
+	"encoding/json"
 	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
+
+	"github.com/CLIAIMONITOR/internal/logger"
+	"github.com/CLIAIMONITOR/internal/types"
 )
 
 func TestNewToolRegistry(t *testing.T) {
@@ -24,7 +32,7 @@ func TestRegisterAndGet(t *testing.T) {
 		Parameters: map[string]ParameterDef{
 			"param1": {Type: "string", Description: "First param", Required: true},
 		},
-		Handler: func(agentID string, params map[string]interface{}) (interface{}, error) {
+		Handler: func(ctx context.Context, agentID string, params map[string]interface{}) (interface{}, error) {
 			return "success", nil
 		},
 	}
@@ -105,7 +113,7 @@ func TestExecute(t *testing.T) {
 
 	r.Register(ToolDefinition{
 		Name: "echo_tool",
-		Handler: func(agentID string, params map[string]interface{}) (interface{}, error) {
+		Handler: func(ctx context.Context, agentID string, params map[string]interface{}) (interface{}, error) {
 			return map[string]interface{}{
 				"agent_id": agentID,
 				"message":  params["message"],
@@ -113,7 +121,7 @@ func TestExecute(t *testing.T) {
 		},
 	})
 
-	result, err := r.Execute("echo_tool", "TestAgent", map[string]interface{}{
+	result, err := r.Execute(context.Background(), "echo_tool", "TestAgent", map[string]interface{}{
 		"message": "hello",
 	})
 
@@ -133,7 +141,7 @@ func TestExecute(t *testing.T) {
 func TestExecuteUnknownTool(t *testing.T) {
 	r := NewToolRegistry()
 
-	_, err := r.Execute("nonexistent", "Agent1", nil)
+	_, err := r.Execute(context.Background(), "nonexistent", "Agent1", nil)
 	if err == nil {
 		t.Error("expected error for unknown tool")
 	}
@@ -144,12 +152,12 @@ func TestExecuteHandlerError(t *testing.T) {
 
 	r.Register(ToolDefinition{
 		Name: "error_tool",
-		Handler: func(agentID string, params map[string]interface{}) (interface{}, error) {
+		Handler: func(ctx context.Context, agentID string, params map[string]interface{}) (interface{}, error) {
 			return nil, errors.New("handler error")
 		},
 	})
 
-	_, err := r.Execute("error_tool", "Agent1", nil)
+	_, err := r.Execute(context.Background(), "error_tool", "Agent1", nil)
 	if err == nil {
 		t.Error("expected error from handler")
 	}
@@ -207,3 +215,225 @@ func TestListIncludesParameterDetails(t *testing.T) {
 		t.Errorf("description = %v, want 'A text parameter'", textParam["description"])
 	}
 }
+
+func TestToolRegistryListSchemaTypes(t *testing.T) {
+	r := NewToolRegistry()
+	r.Register(ToolDefinition{
+		Name:        "typed",
+		Description: "Typed params",
+		Parameters: map[string]ParameterDef{
+			"ids":   {Type: "array", Description: "IDs", Required: true},
+			"flag":  {Type: "bool", Description: "Flag"},
+			"count": {Type: "int", Description: "Count", Required: true},
+			"other": {Type: "whatever", Description: "Unknown type"},
+		},
+	})
+
+	props := r.List()[0]["inputSchema"].(map[string]interface{})["properties"].(map[string]interface{})
+	want := map[string]string{"ids": "array", "flag": "boolean", "count": "integer", "other": "string"}
+	for name, typ := range want {
+		if got := props[name].(map[string]interface{})["type"]; got != typ {
+			t.Errorf("%s type = %v, want %s", name, got, typ)
+		}
+	}
+	if _, ok := props["ids"].(map[string]interface{})["items"]; !ok {
+		t.Error("array parameter should declare items")
+	}
+
+	required := r.List()[0]["inputSchema"].(map[string]interface{})["required"].([]string)
+	if len(required) != 2 || required[0] != "count" || required[1] != "ids" {
+		t.Errorf("required = %v, want [count ids]", required)
+	}
+}
+
+func TestToolRegistryListCache(t *testing.T) {
+	r := NewToolRegistry()
+	r.Register(ToolDefinition{Name: "b_tool"})
+
+	first := r.List()
+	if second := r.List(); &first[0] != &second[0] {
+		t.Error("expected cached schema to be reused")
+	}
+
+	// Registering a tool invalidates the cache
+	r.Register(ToolDefinition{Name: "a_tool"})
+	list := r.List()
+	if len(list) != 2 || list[0]["name"] != "a_tool" || list[1]["name"] != "b_tool" {
+		t.Errorf("expected tools sorted by name, got %v", list)
+	}
+}
+
+func TestDeregisterTool(t *testing.T) {
+	s := NewServer()
+	s.RegisterTool(ToolDefinition{Name: "spawn_agent", Handler: func(ctx context.Context, agentID string, params map[string]interface{}) (interface{}, error) {
+		return "spawned", nil
+	}})
+	s.RegisterTool(ToolDefinition{Name: "ping"})
+	if len(s.ListTools()) != 2 {
+		t.Fatalf("expected 2 tools, got %v", s.ListTools())
+	}
+
+	if !s.DeregisterTool("spawn_agent") {
+		t.Error("expected DeregisterTool to report the tool was removed")
+	}
+	if s.DeregisterTool("spawn_agent") {
+		t.Error("expected second DeregisterTool to return false")
+	}
+	if list := s.ListTools(); len(list) != 1 || list[0]["name"] != "ping" {
+		t.Errorf("expected only ping to remain listed, got %v", list)
+	}
+	if _, err := s.tools.Execute(context.Background(), "spawn_agent", "agent-1", nil); err == nil {
+		t.Error("expected calling a deregistered tool to fail")
+	}
+}
+
+func TestReplaceToolHandler(t *testing.T) {
+	s := NewServer()
+	s.RegisterTool(ToolDefinition{Name: "spawn_agent", Description: "Spawn", Handler: func(ctx context.Context, agentID string, params map[string]interface{}) (interface{}, error) {
+		return "spawned", nil
+	}})
+
+	maintenance := func(ctx context.Context, agentID string, params map[string]interface{}) (interface{}, error) {
+		return nil, errors.New("maintenance window")
+	}
+	if !s.ReplaceToolHandler("spawn_agent", maintenance) {
+		t.Fatal("expected ReplaceToolHandler to succeed")
+	}
+	if _, err := s.tools.Execute(context.Background(), "spawn_agent", "agent-1", nil); err == nil || err.Error() != "maintenance window" {
+		t.Errorf("expected replaced handler to run, got %v", err)
+	}
+	if tool, _ := s.tools.Get("spawn_agent"); tool.Description != "Spawn" {
+		t.Errorf("expected definition to be kept, got %+v", tool)
+	}
+
+	if s.ReplaceToolHandler("missing", maintenance) {
+		t.Error("expected ReplaceToolHandler to fail for unknown tool")
+	}
+	if s.ReplaceToolHandler("spawn_agent", nil) {
+		t.Error("expected ReplaceToolHandler to reject a nil handler")
+	}
+}
+
+func TestServeHTTPTraceID(t *testing.T) {
+	s := NewServer()
+	var seen []string
+	s.RegisterTool(ToolDefinition{
+		Name: "trace_tool",
+		Handler: func(ctx context.Context, agentID string, params map[string]interface{}) (interface{}, error) {
+			seen = append(seen, logger.TraceID(ctx))
+			return "ok", nil
+		},
+	})
+
+	body := `{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"trace_tool","arguments":{}}}`
+	call := func(traceID string) string {
+		req := httptest.NewRequest(http.MethodPost, "/mcp", strings.NewReader(body))
+		req.Header.Set("X-Agent-ID", "agent-1")
+		if traceID != "" {
+			req.Header.Set(TraceIDHeader, traceID)
+		}
+		rec := httptest.NewRecorder()
+		s.ServeHTTP(rec, req)
+		return rec.Header().Get(TraceIDHeader)
+	}
+
+	if got := call("trace-123"); got != "trace-123" {
+		t.Errorf("response trace ID = %q, want trace-123", got)
+	}
+	generated := call("")
+	if generated == "" {
+		t.Error("expected a generated trace ID")
+	}
+
+	if len(seen) != 2 || seen[0] != "trace-123" || seen[1] != generated {
+		t.Errorf("tool handler saw trace IDs %v, want [trace-123 %s]", seen, generated)
+	}
+}
+
+func TestServeHTTPToolPanic(t *testing.T) {
+	panicky := ToolDefinition{
+		Name: "panic_tool",
+		Handler: func(ctx context.Context, agentID string, params map[string]interface{}) (interface{}, error) {
+			var m map[string]interface{}
+			m["boom"] = true // nil map write
+			return nil, nil
+		},
+	}
+	body := `{"jsonrpc":"2.0","id":7,"method":"tools/call","params":{"name":"panic_tool","arguments":{}}}`
+	call := func(s *Server) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/mcp", strings.NewReader(body))
+		req.Header.Set("X-Agent-ID", "agent-1")
+		rec := httptest.NewRecorder()
+		s.ServeHTTP(rec, req)
+		return rec
+	}
+
+	s := NewServer()
+	s.RegisterTool(panicky)
+	rec := call(s)
+
+	var resp types.MCPResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("expected a JSON-RPC response, got %q: %v", rec.Body.String(), err)
+	}
+	if resp.Error == nil || resp.Error.Code != int(CodeInternalError) {
+		t.Fatalf("expected internal error response, got %+v", resp)
+	}
+	if !strings.Contains(resp.Error.Message, "panic_tool panicked") {
+		t.Errorf("error message = %q", resp.Error.Message)
+	}
+
+	t.Setenv(PanicOnToolErrorEnv, "true")
+	strict := NewServer()
+	strict.RegisterTool(panicky)
+	defer func() {
+		if recover() == nil {
+			t.Error("expected the panic to be re-raised with " + PanicOnToolErrorEnv + " set")
+		}
+	}()
+	call(strict)
+}
+
+func TestGenerateMarkdownDocs(t *testing.T) {
+	docs := GenerateMarkdownDocs([]ToolDefinition{
+		{
+			Name:        "claim_task",
+			Description: "Claim a pending task",
+			Parameters: map[string]ParameterDef{
+				"task_id":  {Type: "string", Description: "Task to claim", Required: true},
+				"priority": {Type: "int", Description: "Minimum priority"},
+				"dry_run":  {Type: "bool", Description: "Validate only | no claim"},
+			},
+		},
+		{Name: "ping", Description: "Health check"},
+	})
+
+	for _, want := range []string{
+		"## claim_task",
+		"Claim a pending task",
+		"| `task_id` | string | yes | Task to claim |",
+		"| `priority` | integer | no | Minimum priority |",
+		`| ` + "`dry_run`" + ` | boolean | no | Validate only \| no claim |`,
+		`"method": "tools/call"`,
+		`"task_id": "<task_id>"`,
+		`"priority": 1`,
+		"## ping",
+		"No parameters.",
+	} {
+		if !strings.Contains(docs, want) {
+			t.Errorf("Docs missing %q:\n%s", want, docs)
+		}
+	}
+	if strings.Index(docs, "`task_id`") > strings.Index(docs, "`dry_run`") {
+		t.Error("Required parameters should be listed first")
+	}
+
+	// Every example body must be a valid tools/call request
+	for _, block := range strings.Split(docs, "```json\n")[1:] {
+		var req types.MCPRequest
+		body := block[:strings.Index(block, "\n```")]
+		if err := json.Unmarshal([]byte(body), &req); err != nil || req.Method != "tools/call" {
+			t.Errorf("Invalid example body %q: %v", body, err)
+		}
+	}
+}
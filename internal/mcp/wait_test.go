@@ -1,6 +1,7 @@
 package mcp
 
 import (
+	"context"
 	"testing"
 	"time"
 
@@ -37,7 +38,7 @@ func TestWaitForEvents_ReceivesEvent(t *testing.T) {
 		"timeout_seconds": float64(5),
 	}
 
-	result, err := server.tools.Execute("wait_for_events", agentID, params)
+	result, err := server.tools.Execute(context.Background(), "wait_for_events", agentID, params)
 	if err != nil {
 		t.Fatalf("Expected no error, got: %v", err)
 	}
@@ -76,7 +77,7 @@ func TestWaitForEvents_Timeout(t *testing.T) {
 	}
 
 	start := time.Now()
-	result, err := server.tools.Execute("wait_for_events", agentID, params)
+	result, err := server.tools.Execute(context.Background(), "wait_for_events", agentID, params)
 	elapsed := time.Since(start)
 
 	if err != nil {
@@ -145,7 +146,7 @@ func TestWaitForEvents_FilterByType(t *testing.T) {
 		"event_types":     []interface{}{"task"}, // Filter for task events only
 	}
 
-	result, err := server.tools.Execute("wait_for_events", agentID, params)
+	result, err := server.tools.Execute(context.Background(), "wait_for_events", agentID, params)
 	if err != nil {
 		t.Fatalf("Expected no error, got: %v", err)
 	}
@@ -206,7 +207,7 @@ func TestWaitForEvents_TimeoutClamping(t *testing.T) {
 			}
 
 			start := time.Now()
-			_, err := server.tools.Execute("wait_for_events", "test-agent", params)
+			_, err := server.tools.Execute(context.Background(), "wait_for_events", "test-agent", params)
 			elapsed := time.Since(start)
 
 			if err != nil {
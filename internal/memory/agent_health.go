@@ -0,0 +1,158 @@
+package memory
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Agent health score weights; the score ranges from 0 to 100
+const (
+	HealthHeartbeatPoints  = 40.0
+	HealthCompletionPoints = 30.0
+	HealthDefectPoints     = 30.0
+)
+
+// HealthHeartbeatWindow is how long after the last heartbeat the heartbeat
+// points decay to zero
+const HealthHeartbeatWindow = 10 * time.Minute
+
+// HealthMaxDefectDensity is the defects-per-submission rate at which an
+// agent's defect points reach zero
+const HealthMaxDefectDensity = 3.0
+
+// AgentHealthCacheTTL is how long the task completion and defect parts of a
+// health score are reused before they are recomputed
+const AgentHealthCacheTTL = 5 * time.Minute
+
+// AgentHealthScore is an agent's health score with its breakdown
+type AgentHealthScore struct {
+	AgentID         string     `json:"agent_id"`
+	Score           float64    `json:"score"`
+	HeartbeatScore  float64    `json:"heartbeat_score"`  // 0-40, from heartbeat recency
+	CompletionScore float64    `json:"completion_score"` // 0-30, from captain task completion rate
+	DefectScore     float64    `json:"defect_score"`     // 0-30, inverted defect density
+	LastSeen        *time.Time `json:"last_seen,omitempty"`
+	TasksFinished   int        `json:"tasks_finished"`
+	TasksCompleted  int        `json:"tasks_completed"`
+	DefectDensity   float64    `json:"defect_density"`
+	ComputedAt      time.Time  `json:"computed_at"` // When the completion and defect parts were computed
+}
+
+// ComputeAgentHealthScore scores an agent from heartbeat recency (lastSeen,
+// zero if unknown or the process is gone), the completion rate of the
+// captain tasks it finished and the defect density of its reviewed work.
+// Agents without task or review history get full points for that part. The
+// completion and defect parts are cached for AgentHealthCacheTTL.
+func (m *SQLiteMemoryDB) ComputeAgentHealthScore(agentID string, lastSeen time.Time) (*AgentHealthScore, error) {
+	now := time.Now()
+	health, err := m.cachedAgentHealth(agentID, now)
+	if err != nil {
+		return nil, err
+	}
+	if health == nil {
+		if health, err = m.aggregateAgentHealth(agentID, now); err != nil {
+			return nil, err
+		}
+	}
+
+	health.HeartbeatScore = heartbeatScore(lastSeen, now)
+	if !lastSeen.IsZero() {
+		health.LastSeen = &lastSeen
+	}
+	health.Score = health.HeartbeatScore + health.CompletionScore + health.DefectScore
+	return health, nil
+}
+
+// heartbeatScore decays linearly from full points at lastSeen to zero after
+// HealthHeartbeatWindow
+func heartbeatScore(lastSeen, now time.Time) float64 {
+	if lastSeen.IsZero() {
+		return 0
+	}
+	age := now.Sub(lastSeen)
+	if age <= 0 {
+		return HealthHeartbeatPoints
+	}
+	if age >= HealthHeartbeatWindow {
+		return 0
+	}
+	return HealthHeartbeatPoints * (1 - float64(age)/float64(HealthHeartbeatWindow))
+}
+
+// cachedAgentHealth returns the cached completion and defect parts if they
+// are younger than AgentHealthCacheTTL, or nil
+func (m *SQLiteMemoryDB) cachedAgentHealth(agentID string, now time.Time) (*AgentHealthScore, error) {
+	h := &AgentHealthScore{AgentID: agentID}
+	err := m.db.QueryRow(`
+		SELECT completion_score, defect_score, tasks_finished, tasks_completed, defect_density, computed_at
+		FROM agent_health_cache
+		WHERE agent_id = ? AND computed_at > ?
+	`, agentID, now.Add(-AgentHealthCacheTTL).UTC().Format("2006-01-02 15:04:05")).Scan(
+		&h.CompletionScore, &h.DefectScore, &h.TasksFinished, &h.TasksCompleted, &h.DefectDensity, &h.ComputedAt,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read agent health cache: %w", err)
+	}
+	return h, nil
+}
+
+// aggregateAgentHealth computes the completion and defect parts of an
+// agent's health score and caches them
+func (m *SQLiteMemoryDB) aggregateAgentHealth(agentID string, now time.Time) (*AgentHealthScore, error) {
+	h := &AgentHealthScore{
+		AgentID:         agentID,
+		CompletionScore: HealthCompletionPoints,
+		DefectScore:     HealthDefectPoints,
+		ComputedAt:      now.UTC().Truncate(time.Second),
+	}
+
+	err := m.db.QueryRow(`
+		SELECT COUNT(*), COALESCE(SUM(status = 'completed'), 0)
+		FROM captain_tasks
+		WHERE assigned_to = ? AND status IN ('completed', 'failed', 'escalated')
+	`, agentID).Scan(&h.TasksFinished, &h.TasksCompleted)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count agent tasks: %w", err)
+	}
+	if h.TasksFinished > 0 {
+		h.CompletionScore = HealthCompletionPoints * float64(h.TasksCompleted) / float64(h.TasksFinished)
+	}
+
+	var submissions, defects int
+	err = m.db.QueryRow(`
+		SELECT COALESCE(total_submissions, 0), COALESCE(total_defects_received, 0)
+		FROM agent_quality_scores WHERE agent_id = ?
+	`, agentID).Scan(&submissions, &defects)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("failed to read agent defects: %w", err)
+	}
+	if submissions > 0 {
+		h.DefectDensity = float64(defects) / float64(submissions)
+		h.DefectScore = HealthDefectPoints * (1 - h.DefectDensity/HealthMaxDefectDensity)
+		if h.DefectScore < 0 {
+			h.DefectScore = 0
+		}
+	}
+
+	_, err = m.db.Exec(`
+		INSERT INTO agent_health_cache (agent_id, completion_score, defect_score, tasks_finished, tasks_completed, defect_density, computed_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(agent_id) DO UPDATE SET
+			completion_score = excluded.completion_score,
+			defect_score = excluded.defect_score,
+			tasks_finished = excluded.tasks_finished,
+			tasks_completed = excluded.tasks_completed,
+			defect_density = excluded.defect_density,
+			computed_at = excluded.computed_at
+	`, agentID, h.CompletionScore, h.DefectScore, h.TasksFinished, h.TasksCompleted, h.DefectDensity,
+		h.ComputedAt.Format("2006-01-02 15:04:05"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to cache agent health: %w", err)
+	}
+	return h, nil
+}
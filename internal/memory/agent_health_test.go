@@ -0,0 +1,95 @@
+package memory
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestComputeAgentHealthScore(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	// No history: full completion and defect points, heartbeat decides the rest
+	fresh, err := db.ComputeAgentHealthScore("agent-new", time.Now())
+	if err != nil {
+		t.Fatalf("ComputeAgentHealthScore failed: %v", err)
+	}
+	if math.Abs(fresh.Score-100) > 0.5 {
+		t.Errorf("Expected score ~100 for fresh agent, got %.2f (%+v)", fresh.Score, fresh)
+	}
+	gone, err := db.ComputeAgentHealthScore("agent-new", time.Time{})
+	if err != nil {
+		t.Fatalf("ComputeAgentHealthScore failed: %v", err)
+	}
+	if gone.HeartbeatScore != 0 || gone.Score != 60 || gone.LastSeen != nil {
+		t.Errorf("Expected 60 without heartbeat, got %+v", gone)
+	}
+
+	// 3 of 4 finished tasks completed; pending tasks are ignored
+	statuses := map[string]string{"t1": "completed", "t2": "completed", "t3": "completed", "t4": "failed", "t5": "pending"}
+	for id, status := range statuses {
+		if err := db.RecordCaptainTask(&CaptainTaskRecord{ID: id, Title: id, TaskType: "implementation"}); err != nil {
+			t.Fatalf("RecordCaptainTask failed: %v", err)
+		}
+		if err := db.AssignCaptainTask(id, "agent-01"); err != nil {
+			t.Fatalf("AssignCaptainTask failed: %v", err)
+		}
+		if err := db.UpdateCaptainTaskStatus(id, status); err != nil {
+			t.Fatalf("UpdateCaptainTaskStatus failed: %v", err)
+		}
+	}
+
+	// 3 defects over 2 submissions = 1.5 per submission, half the defect points
+	score, err := db.GetOrCreateQualityScore("agent-01", "author")
+	if err != nil {
+		t.Fatalf("GetOrCreateQualityScore failed: %v", err)
+	}
+	score.TotalSubmissions = 2
+	score.TotalDefectsReceived = 3
+	if err := db.UpdateQualityScore(score); err != nil {
+		t.Fatalf("UpdateQualityScore failed: %v", err)
+	}
+
+	health, err := db.ComputeAgentHealthScore("agent-01", time.Now().Add(-HealthHeartbeatWindow/2))
+	if err != nil {
+		t.Fatalf("ComputeAgentHealthScore failed: %v", err)
+	}
+	if health.TasksFinished != 4 || health.TasksCompleted != 3 || health.CompletionScore != 22.5 {
+		t.Errorf("Unexpected completion breakdown: %+v", health)
+	}
+	if health.DefectDensity != 1.5 || health.DefectScore != 15 {
+		t.Errorf("Unexpected defect breakdown: %+v", health)
+	}
+	if math.Abs(health.HeartbeatScore-20) > 0.5 {
+		t.Errorf("Expected heartbeat score ~20, got %.2f", health.HeartbeatScore)
+	}
+	if math.Abs(health.Score-57.5) > 0.5 {
+		t.Errorf("Expected score ~57.5, got %.2f", health.Score)
+	}
+
+	// New history is not seen until the cached parts expire
+	if err := db.UpdateCaptainTaskStatus("t5", "failed"); err != nil {
+		t.Fatalf("UpdateCaptainTaskStatus failed: %v", err)
+	}
+	cached, err := db.ComputeAgentHealthScore("agent-01", time.Now())
+	if err != nil {
+		t.Fatalf("ComputeAgentHealthScore failed: %v", err)
+	}
+	if cached.TasksFinished != 4 || !cached.ComputedAt.Equal(health.ComputedAt) {
+		t.Errorf("Expected cached breakdown, got %+v", cached)
+	}
+
+	sqlDB := db.(*SQLiteMemoryDB)
+	expired := time.Now().Add(-AgentHealthCacheTTL - time.Minute).UTC().Format("2006-01-02 15:04:05")
+	if _, err := sqlDB.db.Exec(`UPDATE agent_health_cache SET computed_at = ? WHERE agent_id = ?`, expired, "agent-01"); err != nil {
+		t.Fatalf("Failed to expire cache: %v", err)
+	}
+	recomputed, err := db.ComputeAgentHealthScore("agent-01", time.Now())
+	if err != nil {
+		t.Fatalf("ComputeAgentHealthScore failed: %v", err)
+	}
+	if recomputed.TasksFinished != 5 || recomputed.CompletionScore != 18 {
+		t.Errorf("Expected recomputed breakdown after TTL, got %+v", recomputed)
+	}
+}
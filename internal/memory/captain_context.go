@@ -1,9 +1,14 @@
 package memory
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
+	"strconv"
+	"strings"
 	"time"
+
+	"github.com/CLIAIMONITOR/internal/logger"
 )
 
 // SetContext stores or updates a context entry
@@ -91,13 +96,54 @@ func (m *SQLiteMemoryDB) CleanExpiredContext() (int, error) {
 	return int(count), nil
 }
 
+// ContextExpiryWarningHoursKey is the context entry holding how many hours
+// before expiry high-priority context is announced
+const ContextExpiryWarningHoursKey = "context_expiry_warning_hours"
+
+// DefaultContextExpiryWarningHours applies when no warning window is stored
+const DefaultContextExpiryWarningHours = 2
+
+// GetExpiringContext returns entries with a max age that expire within the
+// next withinHours hours, soonest first. Entries already past expiry but not
+// yet cleaned are included.
+func (m *SQLiteMemoryDB) GetExpiringContext(withinHours int) ([]*CaptainContext, error) {
+	query := `
+		SELECT id, context_key, context_value, priority, max_age_hours, created_at, updated_at
+		FROM captain_context
+		WHERE max_age_hours > 0
+		AND datetime(updated_at, '+' || max_age_hours || ' hours') <= datetime('now', ?)
+		ORDER BY datetime(updated_at, '+' || max_age_hours || ' hours') ASC
+	`
+	return m.queryContextEntriesWithArg(query, fmt.Sprintf("+%d hours", withinHours))
+}
+
+// GetContextExpiryWarningHours returns the stored expiry warning window,
+// falling back to DefaultContextExpiryWarningHours when unset or invalid
+func (m *SQLiteMemoryDB) GetContextExpiryWarningHours() int {
+	entry, err := m.GetContext(ContextExpiryWarningHoursKey)
+	if err != nil || entry == nil {
+		return DefaultContextExpiryWarningHours
+	}
+	hours, err := strconv.Atoi(strings.TrimSpace(entry.Value))
+	if err != nil || hours <= 0 {
+		return DefaultContextExpiryWarningHours
+	}
+	return hours
+}
+
 // LogSessionEvent records a significant event in the session log
 func (m *SQLiteMemoryDB) LogSessionEvent(sessionID, eventType, summary, details, agentID string) error {
+	return m.LogSessionEventContext(context.Background(), sessionID, eventType, summary, details, agentID)
+}
+
+// LogSessionEventContext records a session log event tagged with the trace
+// ID carried by ctx, if any
+func (m *SQLiteMemoryDB) LogSessionEventContext(ctx context.Context, sessionID, eventType, summary, details, agentID string) error {
 	query := `
-		INSERT INTO captain_session_log (session_id, event_type, summary, details, agent_id)
-		VALUES (?, ?, ?, ?, ?)
+		INSERT INTO captain_session_log (session_id, event_type, summary, details, agent_id, trace_id)
+		VALUES (?, ?, ?, ?, ?, ?)
 	`
-	_, err := m.db.Exec(query, sessionID, eventType, summary, details, agentID)
+	_, err := m.db.ExecContext(ctx, query, sessionID, eventType, summary, details, agentID, nullString(logger.TraceID(ctx)))
 	if err != nil {
 		return fmt.Errorf("failed to log session event: %w", err)
 	}
@@ -107,7 +153,7 @@ func (m *SQLiteMemoryDB) LogSessionEvent(sessionID, eventType, summary, details,
 // GetSessionLog retrieves log entries for a specific session
 func (m *SQLiteMemoryDB) GetSessionLog(sessionID string, limit int) ([]*SessionLogEntry, error) {
 	query := `
-		SELECT id, session_id, event_type, summary, details, agent_id, created_at
+		SELECT id, session_id, event_type, summary, details, agent_id, COALESCE(trace_id, ''), created_at
 		FROM captain_session_log
 		WHERE session_id = ?
 		ORDER BY created_at DESC
@@ -119,7 +165,7 @@ func (m *SQLiteMemoryDB) GetSessionLog(sessionID string, limit int) ([]*SessionL
 // GetRecentSessionLog retrieves the most recent log entries across all sessions
 func (m *SQLiteMemoryDB) GetRecentSessionLog(limit int) ([]*SessionLogEntry, error) {
 	query := `
-		SELECT id, session_id, event_type, summary, details, agent_id, created_at
+		SELECT id, session_id, event_type, summary, details, agent_id, COALESCE(trace_id, ''), created_at
 		FROM captain_session_log
 		ORDER BY created_at DESC
 		LIMIT ?
@@ -193,7 +239,7 @@ func (m *SQLiteMemoryDB) scanLogRows(rows *sql.Rows) ([]*SessionLogEntry, error)
 		var details sql.NullString
 		err := rows.Scan(
 			&entry.ID, &entry.SessionID, &entry.EventType, &entry.Summary,
-			&details, &agentID, &entry.CreatedAt,
+			&details, &agentID, &entry.TraceID, &entry.CreatedAt,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan log row: %w", err)
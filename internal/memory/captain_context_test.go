@@ -0,0 +1,72 @@
+package memory
+
+import (
+	"testing"
+	"time"
+)
+
+func TestGetExpiringContext(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	entries := []struct {
+		key         string
+		maxAgeHours int
+	}{
+		{"soon", 1},
+		{"later", 24},
+		{"forever", 0},
+	}
+	for _, e := range entries {
+		if err := db.SetContext(e.key, "value", 8, e.maxAgeHours); err != nil {
+			t.Fatalf("SetContext(%s) failed: %v", e.key, err)
+		}
+	}
+
+	expiring, err := db.GetExpiringContext(2)
+	if err != nil {
+		t.Fatalf("GetExpiringContext failed: %v", err)
+	}
+	if len(expiring) != 1 || expiring[0].Key != "soon" {
+		t.Fatalf("Expected only 'soon' to expire within 2h, got %v", expiring)
+	}
+
+	expiresAt, ok := expiring[0].ExpiresAt()
+	if !ok {
+		t.Fatal("Expected 'soon' to have an expiry")
+	}
+	if remaining := time.Until(expiresAt); remaining <= 0 || remaining > time.Hour {
+		t.Errorf("Expected expiry within the hour, got %v", remaining)
+	}
+
+	all, err := db.GetExpiringContext(48)
+	if err != nil {
+		t.Fatalf("GetExpiringContext failed: %v", err)
+	}
+	if len(all) != 2 || all[0].Key != "soon" || all[1].Key != "later" {
+		t.Errorf("Expected soon then later within 48h, got %v", all)
+	}
+}
+
+func TestGetContextExpiryWarningHours(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	if got := db.GetContextExpiryWarningHours(); got != DefaultContextExpiryWarningHours {
+		t.Errorf("Expected default %d, got %d", DefaultContextExpiryWarningHours, got)
+	}
+
+	if err := db.SetContext(ContextExpiryWarningHoursKey, "6", 5, 0); err != nil {
+		t.Fatalf("SetContext failed: %v", err)
+	}
+	if got := db.GetContextExpiryWarningHours(); got != 6 {
+		t.Errorf("Expected 6, got %d", got)
+	}
+
+	if err := db.SetContext(ContextExpiryWarningHoursKey, "soon", 5, 0); err != nil {
+		t.Fatalf("SetContext failed: %v", err)
+	}
+	if got := db.GetContextExpiryWarningHours(); got != DefaultContextExpiryWarningHours {
+		t.Errorf("Expected default for invalid value, got %d", got)
+	}
+}
@@ -0,0 +1,70 @@
+package memory
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// SaveCaptainEscalation inserts an escalation or updates its resolution if it
+// is already stored. A zero CreatedAt is stored as now.
+func (m *SQLiteMemoryDB) SaveCaptainEscalation(esc *CaptainEscalationRecord) error {
+	createdAt := esc.CreatedAt
+	if createdAt.IsZero() {
+		createdAt = time.Now()
+	}
+	var resolvedAt sql.NullString
+	if esc.ResolvedAt != nil {
+		resolvedAt = nullString(esc.ResolvedAt.UTC().Format("2006-01-02 15:04:05"))
+	}
+
+	query := `
+		INSERT INTO captain_escalations (id, task_id, agent_id, reason, context, question, resolution, resolved_by, created_at, resolved_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(id) DO UPDATE SET
+			resolution = excluded.resolution,
+			resolved_by = excluded.resolved_by,
+			resolved_at = excluded.resolved_at
+	`
+	_, err := m.db.Exec(query,
+		esc.ID, nullString(esc.TaskID), nullString(esc.AgentID), esc.Reason,
+		nullString(esc.Context), nullString(esc.Question),
+		nullString(esc.Resolution), nullString(esc.ResolvedBy),
+		createdAt.UTC().Format("2006-01-02 15:04:05"), resolvedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to save captain escalation %s: %w", esc.ID, err)
+	}
+	return nil
+}
+
+// GetCaptainEscalations returns all stored escalations, oldest first
+func (m *SQLiteMemoryDB) GetCaptainEscalations() ([]*CaptainEscalationRecord, error) {
+	query := `
+		SELECT id, COALESCE(task_id, ''), COALESCE(agent_id, ''), reason,
+			COALESCE(context, ''), COALESCE(question, ''),
+			COALESCE(resolution, ''), COALESCE(resolved_by, ''), created_at, resolved_at
+		FROM captain_escalations
+		ORDER BY created_at, id
+	`
+	rows, err := m.db.Query(query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query captain escalations: %w", err)
+	}
+	defer rows.Close()
+
+	records := []*CaptainEscalationRecord{}
+	for rows.Next() {
+		r := &CaptainEscalationRecord{}
+		var resolvedAt sql.NullTime
+		if err := rows.Scan(&r.ID, &r.TaskID, &r.AgentID, &r.Reason, &r.Context, &r.Question,
+			&r.Resolution, &r.ResolvedBy, &r.CreatedAt, &resolvedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan captain escalation: %w", err)
+		}
+		if resolvedAt.Valid {
+			r.ResolvedAt = &resolvedAt.Time
+		}
+		records = append(records, r)
+	}
+	return records, rows.Err()
+}
@@ -0,0 +1,58 @@
+package memory
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCaptainEscalations(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	created := time.Now().Add(-time.Hour)
+	esc := &CaptainEscalationRecord{
+		ID:        "esc-1",
+		TaskID:    "task-1",
+		Reason:    "needs approval",
+		Context:   "Task: deploy",
+		Question:  "Proceed?",
+		CreatedAt: created,
+	}
+	if err := db.SaveCaptainEscalation(esc); err != nil {
+		t.Fatalf("SaveCaptainEscalation failed: %v", err)
+	}
+	if err := db.SaveCaptainEscalation(&CaptainEscalationRecord{ID: "esc-2", AgentID: "agent-1", Reason: "stuck"}); err != nil {
+		t.Fatalf("SaveCaptainEscalation failed: %v", err)
+	}
+
+	resolvedAt := time.Now()
+	esc.Resolution = "approved"
+	esc.ResolvedBy = "alice"
+	esc.ResolvedAt = &resolvedAt
+	esc.Reason = "ignored on update"
+	if err := db.SaveCaptainEscalation(esc); err != nil {
+		t.Fatalf("SaveCaptainEscalation (resolve) failed: %v", err)
+	}
+
+	records, err := db.GetCaptainEscalations()
+	if err != nil {
+		t.Fatalf("GetCaptainEscalations failed: %v", err)
+	}
+	if len(records) != 2 || records[0].ID != "esc-1" || records[1].ID != "esc-2" {
+		t.Fatalf("Unexpected escalations: %+v", records)
+	}
+
+	got := records[0]
+	if got.TaskID != "task-1" || got.Reason != "needs approval" || got.Question != "Proceed?" {
+		t.Errorf("Escalation fields not stored: %+v", got)
+	}
+	if got.Resolution != "approved" || got.ResolvedBy != "alice" || got.ResolvedAt == nil {
+		t.Errorf("Resolution not stored: %+v", got)
+	}
+	if got.ResolvedAt != nil && got.ResolvedAt.Sub(resolvedAt).Abs() > time.Second {
+		t.Errorf("ResolvedAt = %v, want %v", got.ResolvedAt, resolvedAt)
+	}
+	if records[1].ResolvedAt != nil || records[1].AgentID != "agent-1" {
+		t.Errorf("Unresolved escalation: %+v", records[1])
+	}
+}
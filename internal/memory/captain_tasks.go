@@ -0,0 +1,160 @@
+package memory
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// RecordCaptainTask stores a mission's provenance, replacing any earlier record
+// with the same ID so re-executed missions keep a single row
+func (m *SQLiteMemoryDB) RecordCaptainTask(task *CaptainTaskRecord) error {
+	if task.SourceType == "" {
+		task.SourceType = "internal"
+	}
+	if task.Status == "" {
+		task.Status = "executing"
+	}
+
+	query := `
+		INSERT INTO captain_tasks (id, title, task_type, source_type, source_ref, status)
+		VALUES (?, ?, ?, ?, ?, ?)
+		ON CONFLICT(id) DO UPDATE SET
+			title = excluded.title,
+			task_type = excluded.task_type,
+			source_type = excluded.source_type,
+			source_ref = excluded.source_ref,
+			status = excluded.status,
+			updated_at = CURRENT_TIMESTAMP
+	`
+	_, err := m.db.Exec(query, task.ID, task.Title, task.TaskType, task.SourceType, nullString(task.SourceRef), task.Status)
+	if err != nil {
+		return fmt.Errorf("failed to record captain task %s: %w", task.ID, err)
+	}
+	return nil
+}
+
+// UpdateCaptainTaskStatus sets the status of a recorded captain task
+func (m *SQLiteMemoryDB) UpdateCaptainTaskStatus(id, status string) error {
+	_, err := m.db.Exec(`UPDATE captain_tasks SET status = ?, updated_at = CURRENT_TIMESTAMP WHERE id = ?`, status, id)
+	if err != nil {
+		return fmt.Errorf("failed to update captain task %s: %w", id, err)
+	}
+	return nil
+}
+
+// AssignCaptainTask records the agent running a captain task
+func (m *SQLiteMemoryDB) AssignCaptainTask(id, agentID string) error {
+	_, err := m.db.Exec(`UPDATE captain_tasks SET assigned_to = ?, updated_at = CURRENT_TIMESTAMP WHERE id = ?`, nullString(agentID), id)
+	if err != nil {
+		return fmt.Errorf("failed to assign captain task %s: %w", id, err)
+	}
+	return nil
+}
+
+// RequeueCaptainTask puts an in-flight captain task back to pending after its
+// agent was lost, clearing assigned_to and incrementing retry_count. Once
+// retry_count reaches maxRetries the task is marked escalated instead.
+// Returns nil if the task is not executing or spawned.
+func (m *SQLiteMemoryDB) RequeueCaptainTask(id string, maxRetries int) (*CaptainTaskRecord, error) {
+	query := `
+		UPDATE captain_tasks
+		SET retry_count = retry_count + 1,
+			status = CASE WHEN retry_count + 1 >= ? THEN 'escalated' ELSE 'pending' END,
+			assigned_to = NULL,
+			updated_at = CURRENT_TIMESTAMP
+		WHERE id = ? AND status IN ('executing', 'spawned')
+		RETURNING id, title, task_type, source_type, COALESCE(source_ref, ''), status, retry_count, created_at, updated_at
+	`
+	r := &CaptainTaskRecord{}
+	err := m.db.QueryRow(query, maxRetries, id).Scan(
+		&r.ID, &r.Title, &r.TaskType, &r.SourceType, &r.SourceRef, &r.Status, &r.RetryCount, &r.CreatedAt, &r.UpdatedAt,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to requeue captain task %s: %w", id, err)
+	}
+	return r, nil
+}
+
+// GetInFlightCaptainTaskIDs returns the captain tasks assigned to agentID
+// that are still executing or spawned
+func (m *SQLiteMemoryDB) GetInFlightCaptainTaskIDs(agentID string) ([]string, error) {
+	rows, err := m.db.Query(`
+		SELECT id FROM captain_tasks
+		WHERE assigned_to = ? AND status IN ('executing', 'spawned')
+		ORDER BY created_at, id
+	`, agentID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query in-flight captain tasks: %w", err)
+	}
+	defer rows.Close()
+
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("failed to scan captain task: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+// GetCaptainTasks returns the most recent captain tasks, optionally filtered by source type
+func (m *SQLiteMemoryDB) GetCaptainTasks(sourceType string, limit int) ([]*CaptainTaskRecord, error) {
+	query := `
+		SELECT id, title, task_type, source_type, COALESCE(source_ref, ''), status,
+			COALESCE(assigned_to, ''), retry_count, created_at, updated_at
+		FROM captain_tasks
+		WHERE (? = '' OR source_type = ?)
+		ORDER BY created_at DESC, id
+		LIMIT ?
+	`
+	rows, err := m.db.Query(query, sourceType, sourceType, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query captain tasks: %w", err)
+	}
+	defer rows.Close()
+
+	var records []*CaptainTaskRecord
+	for rows.Next() {
+		r := &CaptainTaskRecord{}
+		if err := rows.Scan(&r.ID, &r.Title, &r.TaskType, &r.SourceType, &r.SourceRef, &r.Status,
+			&r.AssignedTo, &r.RetryCount, &r.CreatedAt, &r.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan captain task: %w", err)
+		}
+		records = append(records, r)
+	}
+	return records, rows.Err()
+}
+
+// GetCaptainTaskSourceCounts returns the number of captain tasks per source type
+// created at or after since
+func (m *SQLiteMemoryDB) GetCaptainTaskSourceCounts(since time.Time) (map[string]int, error) {
+	query := `
+		SELECT source_type, COUNT(*)
+		FROM captain_tasks
+		WHERE created_at >= ?
+		GROUP BY source_type
+	`
+	rows, err := m.db.Query(query, since.UTC().Format("2006-01-02 15:04:05"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to count captain task sources: %w", err)
+	}
+	defer rows.Close()
+
+	counts := make(map[string]int)
+	for rows.Next() {
+		var sourceType string
+		var count int
+		if err := rows.Scan(&sourceType, &count); err != nil {
+			return nil, fmt.Errorf("failed to scan captain task source count: %w", err)
+		}
+		counts[sourceType] = count
+	}
+	return counts, rows.Err()
+}
@@ -0,0 +1,110 @@
+package memory
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCaptainTaskProvenance(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	records := []*CaptainTaskRecord{
+		{ID: "MAH-1", Title: "Planner task", TaskType: "implementation", SourceType: "json_file", SourceRef: "data/pending_tasks.json"},
+		{ID: "task-1", Title: "Dashboard task", TaskType: "analysis", SourceType: "api"},
+		{ID: "task-2", Title: "Issue task", TaskType: "implementation", SourceType: "github", SourceRef: "https://github.com/org/repo/issues/7"},
+		{ID: "recon-task-2", Title: "Recon", TaskType: "recon"},
+	}
+	for _, r := range records {
+		if err := db.RecordCaptainTask(r); err != nil {
+			t.Fatalf("RecordCaptainTask(%s) failed: %v", r.ID, err)
+		}
+	}
+
+	if err := db.UpdateCaptainTaskStatus("task-2", "completed"); err != nil {
+		t.Fatalf("UpdateCaptainTaskStatus failed: %v", err)
+	}
+
+	github, err := db.GetCaptainTasks("github", 10)
+	if err != nil {
+		t.Fatalf("GetCaptainTasks failed: %v", err)
+	}
+	if len(github) != 1 || github[0].SourceRef != "https://github.com/org/repo/issues/7" || github[0].Status != "completed" {
+		t.Fatalf("Unexpected github tasks: %+v", github)
+	}
+
+	all, err := db.GetCaptainTasks("", 10)
+	if err != nil {
+		t.Fatalf("GetCaptainTasks failed: %v", err)
+	}
+	if len(all) != 4 {
+		t.Fatalf("Expected 4 tasks, got %d", len(all))
+	}
+
+	counts, err := db.GetCaptainTaskSourceCounts(time.Now().Add(-7 * 24 * time.Hour))
+	if err != nil {
+		t.Fatalf("GetCaptainTaskSourceCounts failed: %v", err)
+	}
+	expected := map[string]int{"json_file": 1, "api": 1, "github": 1, "internal": 1}
+	for source, want := range expected {
+		if counts[source] != want {
+			t.Errorf("Expected %d %s tasks, got %d", want, source, counts[source])
+		}
+	}
+
+	future, err := db.GetCaptainTaskSourceCounts(time.Now().Add(time.Hour))
+	if err != nil {
+		t.Fatalf("GetCaptainTaskSourceCounts failed: %v", err)
+	}
+	if len(future) != 0 {
+		t.Errorf("Expected no tasks after a future cutoff, got %v", future)
+	}
+}
+
+func TestRequeueCaptainTask(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	if err := db.RecordCaptainTask(&CaptainTaskRecord{ID: "task-1", Title: "Crashy", TaskType: "implementation"}); err != nil {
+		t.Fatalf("RecordCaptainTask failed: %v", err)
+	}
+
+	const maxRetries = 3
+	for attempt := 1; attempt <= maxRetries; attempt++ {
+		if err := db.AssignCaptainTask("task-1", "team-sntgreen001"); err != nil {
+			t.Fatalf("AssignCaptainTask failed: %v", err)
+		}
+		if err := db.UpdateCaptainTaskStatus("task-1", "spawned"); err != nil {
+			t.Fatalf("UpdateCaptainTaskStatus failed: %v", err)
+		}
+
+		record, err := db.RequeueCaptainTask("task-1", maxRetries)
+		if err != nil {
+			t.Fatalf("RequeueCaptainTask failed: %v", err)
+		}
+		want := "pending"
+		if attempt == maxRetries {
+			want = "escalated"
+		}
+		if record == nil || record.Status != want || record.RetryCount != attempt {
+			t.Fatalf("attempt %d: got %+v, want status=%s retry_count=%d", attempt, record, want, attempt)
+		}
+	}
+
+	all, err := db.GetCaptainTasks("", 10)
+	if err != nil {
+		t.Fatalf("GetCaptainTasks failed: %v", err)
+	}
+	if len(all) != 1 || all[0].AssignedTo != "" || all[0].RetryCount != maxRetries {
+		t.Errorf("Unexpected task after requeues: %+v", all[0])
+	}
+
+	// Escalated tasks are no longer in flight
+	record, err := db.RequeueCaptainTask("task-1", maxRetries)
+	if err != nil || record != nil {
+		t.Errorf("RequeueCaptainTask on escalated task = %+v, %v; want nil, nil", record, err)
+	}
+	if record, err := db.RequeueCaptainTask("missing", maxRetries); err != nil || record != nil {
+		t.Errorf("RequeueCaptainTask on missing task = %+v, %v; want nil, nil", record, err)
+	}
+}
@@ -0,0 +1,63 @@
+package memory
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// DefaultConfigChangesLimit is how many config changes GetConfigChanges
+// returns when limit is not positive
+const DefaultConfigChangesLimit = 50
+
+// RecordConfigChanges stores the changes from one teams.yaml reload. Changes
+// without a ChangedAt are stamped with the current time.
+func (m *SQLiteMemoryDB) RecordConfigChanges(changes []*ConfigChange) error {
+	if len(changes) == 0 {
+		return nil
+	}
+	now := time.Now()
+	return m.withTx(func(tx *sql.Tx) error {
+		for _, c := range changes {
+			if c.ChangedAt.IsZero() {
+				c.ChangedAt = now
+			}
+			result, err := tx.Exec(`
+				INSERT INTO config_changes (changed_at, agent_name, field, old_value, new_value)
+				VALUES (?, ?, ?, ?, ?)
+			`, c.ChangedAt.UTC().Format("2006-01-02 15:04:05"), c.AgentName, c.Field, c.OldValue, c.NewValue)
+			if err != nil {
+				return fmt.Errorf("failed to record config change %s.%s: %w", c.AgentName, c.Field, err)
+			}
+			c.ID, _ = result.LastInsertId()
+		}
+		return nil
+	})
+}
+
+// GetConfigChanges returns the most recent config changes, newest first
+func (m *SQLiteMemoryDB) GetConfigChanges(limit int) ([]*ConfigChange, error) {
+	if limit <= 0 {
+		limit = DefaultConfigChangesLimit
+	}
+	rows, err := m.db.Query(`
+		SELECT id, changed_at, agent_name, field, COALESCE(old_value, ''), COALESCE(new_value, '')
+		FROM config_changes
+		ORDER BY changed_at DESC, id DESC
+		LIMIT ?
+	`, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query config changes: %w", err)
+	}
+	defer rows.Close()
+
+	changes := []*ConfigChange{}
+	for rows.Next() {
+		c := &ConfigChange{}
+		if err := rows.Scan(&c.ID, &c.ChangedAt, &c.AgentName, &c.Field, &c.OldValue, &c.NewValue); err != nil {
+			return nil, fmt.Errorf("failed to scan config change: %w", err)
+		}
+		changes = append(changes, c)
+	}
+	return changes, rows.Err()
+}
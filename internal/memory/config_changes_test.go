@@ -0,0 +1,58 @@
+package memory
+
+import (
+	"testing"
+	"time"
+)
+
+func TestConfigChanges(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	empty, err := db.GetConfigChanges(10)
+	if err != nil {
+		t.Fatalf("GetConfigChanges failed: %v", err)
+	}
+	if len(empty) != 0 {
+		t.Fatalf("Expected no config changes, got %d", len(empty))
+	}
+
+	first := time.Date(2025, 6, 1, 10, 0, 0, 0, time.UTC)
+	if err := db.RecordConfigChanges([]*ConfigChange{
+		{ChangedAt: first, AgentName: "Engineer", Field: "model", OldValue: "claude-sonnet-4", NewValue: "claude-opus-4"},
+	}); err != nil {
+		t.Fatalf("RecordConfigChanges failed: %v", err)
+	}
+	second := []*ConfigChange{
+		{ChangedAt: first.Add(time.Hour), AgentName: "Security", Field: "agent", NewValue: "added"},
+		{ChangedAt: first.Add(time.Hour), AgentName: "Engineer", Field: "color", OldValue: "#00ff00", NewValue: "#0000ff"},
+	}
+	if err := db.RecordConfigChanges(second); err != nil {
+		t.Fatalf("RecordConfigChanges failed: %v", err)
+	}
+	if second[0].ID == 0 || second[1].ID == 0 {
+		t.Error("Expected recorded changes to be assigned IDs")
+	}
+
+	changes, err := db.GetConfigChanges(0)
+	if err != nil {
+		t.Fatalf("GetConfigChanges failed: %v", err)
+	}
+	if len(changes) != 3 {
+		t.Fatalf("Expected 3 config changes, got %d", len(changes))
+	}
+	if changes[0].Field != "color" || changes[1].Field != "agent" || changes[2].Field != "model" {
+		t.Errorf("Expected newest first, got %s, %s, %s", changes[0].Field, changes[1].Field, changes[2].Field)
+	}
+	if !changes[2].ChangedAt.Equal(first) || changes[2].OldValue != "claude-sonnet-4" || changes[2].NewValue != "claude-opus-4" {
+		t.Errorf("Unexpected oldest change: %+v", changes[2])
+	}
+
+	limited, err := db.GetConfigChanges(1)
+	if err != nil {
+		t.Fatalf("GetConfigChanges failed: %v", err)
+	}
+	if len(limited) != 1 || limited[0].ID != second[1].ID {
+		t.Errorf("Expected only the newest change with limit 1, got %+v", limited)
+	}
+}
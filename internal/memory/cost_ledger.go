@@ -0,0 +1,78 @@
+package memory
+
+import (
+	"fmt"
+	"time"
+)
+
+// RecordAgentCost appends an entry to the cost ledger
+func (m *SQLiteMemoryDB) RecordAgentCost(entry *AgentCostEntry) error {
+	if entry.RecordedAt.IsZero() {
+		entry.RecordedAt = time.Now()
+	}
+
+	query := `
+		INSERT INTO agent_cost_ledger (agent_id, repo_id, task_id, tokens, cost, recorded_at)
+		VALUES (?, ?, ?, ?, ?, ?)
+	`
+	result, err := m.db.Exec(query,
+		entry.AgentID,
+		entry.RepoID,
+		entry.TaskID,
+		entry.Tokens,
+		entry.Cost,
+		entry.RecordedAt.UTC().Format("2006-01-02 15:04:05"),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to record agent cost: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("failed to get agent cost ID: %w", err)
+	}
+	entry.ID = id
+	return nil
+}
+
+// GetCostByRepo returns the total cost attributed to a repository since the
+// given time
+func (m *SQLiteMemoryDB) GetCostByRepo(repoID string, since time.Time) (float64, error) {
+	var total float64
+	err := m.db.QueryRow(`
+		SELECT COALESCE(SUM(cost), 0)
+		FROM agent_cost_ledger
+		WHERE COALESCE(repo_id, '') = ? AND recorded_at >= ?
+	`, repoID, since.UTC().Format("2006-01-02 15:04:05")).Scan(&total)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get cost for repo: %w", err)
+	}
+	return total, nil
+}
+
+// GetRepoCosts returns ledger totals per repository since the given time,
+// most expensive first
+func (m *SQLiteMemoryDB) GetRepoCosts(since time.Time) ([]*RepoCost, error) {
+	query := `
+		SELECT COALESCE(repo_id, '') AS repo, SUM(tokens), SUM(cost)
+		FROM agent_cost_ledger
+		WHERE recorded_at >= ?
+		GROUP BY repo
+		ORDER BY SUM(cost) DESC
+	`
+	rows, err := m.db.Query(query, since.UTC().Format("2006-01-02 15:04:05"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query repo costs: %w", err)
+	}
+	defer rows.Close()
+
+	var costs []*RepoCost
+	for rows.Next() {
+		c := &RepoCost{}
+		if err := rows.Scan(&c.RepoID, &c.Tokens, &c.Cost); err != nil {
+			return nil, fmt.Errorf("failed to scan repo cost: %w", err)
+		}
+		costs = append(costs, c)
+	}
+	return costs, rows.Err()
+}
@@ -0,0 +1,56 @@
+package memory
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestCostByRepo(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	now := time.Now()
+	entries := []*AgentCostEntry{
+		{AgentID: "a1", RepoID: "repo-a", TaskID: "t1", Tokens: 500, Cost: 0.0015, RecordedAt: now},
+		{AgentID: "a2", RepoID: "repo-a", Tokens: 500, Cost: 0.0015, RecordedAt: now.Add(-time.Hour)},
+		{AgentID: "a1", RepoID: "repo-b", Tokens: 2000, Cost: 0.006, RecordedAt: now},
+		{AgentID: "a3", Tokens: 500, Cost: 0.0015, RecordedAt: now},
+		{AgentID: "a1", RepoID: "repo-a", Tokens: 9000, Cost: 0.027, RecordedAt: now.AddDate(0, 0, -40)},
+	}
+	for _, e := range entries {
+		if err := db.RecordAgentCost(e); err != nil {
+			t.Fatalf("RecordAgentCost failed: %v", err)
+		}
+		if e.ID == 0 {
+			t.Error("Expected ledger entry ID to be set")
+		}
+	}
+
+	since := now.AddDate(0, 0, -30)
+	cost, err := db.GetCostByRepo("repo-a", since)
+	if err != nil {
+		t.Fatalf("GetCostByRepo failed: %v", err)
+	}
+	if math.Abs(cost-0.003) > 1e-9 {
+		t.Errorf("Expected repo-a cost 0.003 over 30 days, got %f", cost)
+	}
+
+	if cost, err := db.GetCostByRepo("unknown", since); err != nil || cost != 0 {
+		t.Errorf("Expected zero cost for unknown repo, got %f (%v)", cost, err)
+	}
+
+	costs, err := db.GetRepoCosts(since)
+	if err != nil {
+		t.Fatalf("GetRepoCosts failed: %v", err)
+	}
+	if len(costs) != 3 {
+		t.Fatalf("Expected 3 repo totals, got %d: %+v", len(costs), costs)
+	}
+	if costs[0].RepoID != "repo-b" || costs[1].RepoID != "repo-a" || costs[1].Tokens != 1000 {
+		t.Errorf("Expected repo-b then repo-a, got %+v, %+v", costs[0], costs[1])
+	}
+	if costs[2].RepoID != "" || costs[2].Tokens != 500 {
+		t.Errorf("Expected unattributed spend last, got %+v", costs[2])
+	}
+}
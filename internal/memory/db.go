@@ -4,9 +4,12 @@ import (
 	"crypto/sha256"
 	"database/sql"
 	_ "embed"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"sync"
+	"time"
 
 	_ "modernc.org/sqlite"
 )
@@ -56,10 +59,81 @@ var migration013 string
 //go:embed migrations/014_agent_panes.sql
 var migration014 string
 
+//go:embed migrations/015_captain_tasks.sql
+var migration015 string
+
+//go:embed migrations/016_orchestrator_metrics.sql
+var migration016 string
+
+//go:embed migrations/017_review_risk_level.sql
+var migration017 string
+
+//go:embed migrations/018_model_experiments.sql
+var migration018 string
+
+//go:embed migrations/019_agent_cost_ledger.sql
+var migration019 string
+
+//go:embed migrations/020_scan_progress.sql
+var migration020 string
+
+//go:embed migrations/021_review_webhook_outbox.sql
+var migration021 string
+
+//go:embed migrations/022_trace_ids.sql
+var migration022 string
+
+//go:embed migrations/023_reviewer_slots.sql
+var migration023 string
+
+//go:embed migrations/024_captain_task_retries.sql
+var migration024 string
+
+//go:embed migrations/025_session_stats_snapshots.sql
+var migration025 string
+
+//go:embed migrations/026_quality_score_history.sql
+var migration026 string
+
+//go:embed migrations/027_captain_escalations.sql
+var migration027 string
+
+//go:embed migrations/028_agent_health_cache.sql
+var migration028 string
+
+//go:embed migrations/029_mission_attempts.sql
+var migration029 string
+
+//go:embed migrations/030_config_changes.sql
+var migration030 string
+
+//go:embed migrations/031_task_dependencies.sql
+var migration031 string
+
+//go:embed migrations/032_environment_security_policy.sql
+var migration032 string
+
+//go:embed migrations/033_mission_attempt_phase.sql
+var migration033 string
+
 // SQLiteMemoryDB is the concrete implementation of MemoryDB using SQLite
 type SQLiteMemoryDB struct {
 	db   *sql.DB
 	path string
+
+	// reviewCompletedHook is called when a review board transitions to completed
+	reviewCompletedHook func(board *ReviewBoard)
+
+	// Review board size limit and reviewer selection threshold
+	reviewBoardConfig ReviewBoardConfig
+
+	// Shared so round robin positions carry across DispatchBoard calls
+	roundRobin *RoundRobinStrategy
+
+	// Last integrity_check result, cached between health checks
+	integrityMu        sync.Mutex
+	integrityCheckedAt time.Time
+	integrityProblems  []string
 }
 
 // NewMemoryDB creates a new memory database instance
@@ -71,8 +145,10 @@ func NewMemoryDB(path string) (MemoryDB, error) {
 		return nil, fmt.Errorf("failed to create memory db directory: %w", err)
 	}
 
-	// Open database
-	db, err := sql.Open("sqlite", path+"?_journal_mode=WAL&_busy_timeout=5000&_foreign_keys=on")
+	// Open database. modernc sqlite only applies pragmas given as _pragma, so
+	// every pooled connection waits on a locked database instead of failing
+	// with SQLITE_BUSY.
+	db, err := sql.Open("sqlite", path+"?_pragma=busy_timeout(5000)&_pragma=journal_mode(WAL)&_foreign_keys=on")
 	if err != nil {
 		return nil, fmt.Errorf("failed to open memory db: %w", err)
 	}
@@ -82,8 +158,10 @@ func NewMemoryDB(path string) (MemoryDB, error) {
 	db.SetMaxIdleConns(5)
 
 	memDB := &SQLiteMemoryDB{
-		db:   db,
-		path: path,
+		db:                db,
+		path:              path,
+		reviewBoardConfig: DefaultReviewBoardConfig(),
+		roundRobin:        &RoundRobinStrategy{},
 	}
 
 	// Run migrations
@@ -227,6 +305,158 @@ func (m *SQLiteMemoryDB) migrate() error {
 		fmt.Println("[MIGRATION] Successfully migrated to schema v15")
 	}
 
+	if version < 16 {
+		fmt.Println("[MIGRATION] Running migration to v16: Add captain task provenance")
+		if _, err := m.db.Exec(migration015); err != nil {
+			return fmt.Errorf("failed to run migration 015: %w", err)
+		}
+		fmt.Println("[MIGRATION] Successfully migrated to schema v16")
+	}
+
+	if version < 17 {
+		fmt.Println("[MIGRATION] Running migration to v17: Add orchestrator metrics")
+		if _, err := m.db.Exec(migration016); err != nil {
+			return fmt.Errorf("failed to run migration 016: %w", err)
+		}
+		fmt.Println("[MIGRATION] Successfully migrated to schema v17")
+	}
+
+	if version < 18 {
+		fmt.Println("[MIGRATION] Running migration to v18: Derive review board risk level from defects")
+		if _, err := m.db.Exec(migration017); err != nil {
+			return fmt.Errorf("failed to run migration 017: %w", err)
+		}
+		fmt.Println("[MIGRATION] Successfully migrated to schema v18")
+	}
+
+	if version < 19 {
+		fmt.Println("[MIGRATION] Running migration to v19: Add model A/B experiments")
+		if _, err := m.db.Exec(migration018); err != nil {
+			return fmt.Errorf("failed to run migration 018: %w", err)
+		}
+		fmt.Println("[MIGRATION] Successfully migrated to schema v19")
+	}
+
+	if version < 20 {
+		fmt.Println("[MIGRATION] Running migration to v20: Add agent cost ledger")
+		if _, err := m.db.Exec(migration019); err != nil {
+			return fmt.Errorf("failed to run migration 019: %w", err)
+		}
+		fmt.Println("[MIGRATION] Successfully migrated to schema v20")
+	}
+
+	if version < 21 {
+		fmt.Println("[MIGRATION] Running migration to v21: Add recon scan progress")
+		if _, err := m.db.Exec(migration020); err != nil {
+			return fmt.Errorf("failed to run migration 020: %w", err)
+		}
+		fmt.Println("[MIGRATION] Successfully migrated to schema v21")
+	}
+
+	if version < 22 {
+		fmt.Println("[MIGRATION] Running migration to v22: Add review webhook outbox")
+		if _, err := m.db.Exec(migration021); err != nil {
+			return fmt.Errorf("failed to run migration 021: %w", err)
+		}
+		fmt.Println("[MIGRATION] Successfully migrated to schema v22")
+	}
+
+	if version < 23 {
+		fmt.Println("[MIGRATION] Running migration to v23: Add trace IDs")
+		if _, err := m.db.Exec(migration022); err != nil {
+			return fmt.Errorf("failed to run migration 022: %w", err)
+		}
+		fmt.Println("[MIGRATION] Successfully migrated to schema v23")
+	}
+
+	if version < 24 {
+		fmt.Println("[MIGRATION] Running migration to v24: Add reviewer slot status")
+		if _, err := m.db.Exec(migration023); err != nil {
+			return fmt.Errorf("failed to run migration 023: %w", err)
+		}
+		fmt.Println("[MIGRATION] Successfully migrated to schema v24")
+	}
+
+	if version < 25 {
+		fmt.Println("[MIGRATION] Running migration to v25: Add captain task retries")
+		if _, err := m.db.Exec(migration024); err != nil {
+			return fmt.Errorf("failed to run migration 024: %w", err)
+		}
+		fmt.Println("[MIGRATION] Successfully migrated to schema v25")
+	}
+
+	if version < 26 {
+		fmt.Println("[MIGRATION] Running migration to v26: Add session stats snapshots")
+		if _, err := m.db.Exec(migration025); err != nil {
+			return fmt.Errorf("failed to run migration 025: %w", err)
+		}
+		fmt.Println("[MIGRATION] Successfully migrated to schema v26")
+	}
+
+	if version < 27 {
+		fmt.Println("[MIGRATION] Running migration to v27: Add quality score history")
+		if _, err := m.db.Exec(migration026); err != nil {
+			return fmt.Errorf("failed to run migration 026: %w", err)
+		}
+		fmt.Println("[MIGRATION] Successfully migrated to schema v27")
+	}
+
+	if version < 28 {
+		fmt.Println("[MIGRATION] Running migration to v28: Add captain escalations")
+		if _, err := m.db.Exec(migration027); err != nil {
+			return fmt.Errorf("failed to run migration 027: %w", err)
+		}
+		fmt.Println("[MIGRATION] Successfully migrated to schema v28")
+	}
+
+	if version < 29 {
+		fmt.Println("[MIGRATION] Running migration to v29: Add agent health cache")
+		if _, err := m.db.Exec(migration028); err != nil {
+			return fmt.Errorf("failed to run migration 028: %w", err)
+		}
+		fmt.Println("[MIGRATION] Successfully migrated to schema v29")
+	}
+
+	if version < 30 {
+		fmt.Println("[MIGRATION] Running migration to v30: Add mission attempts")
+		if _, err := m.db.Exec(migration029); err != nil {
+			return fmt.Errorf("failed to run migration 029: %w", err)
+		}
+		fmt.Println("[MIGRATION] Successfully migrated to schema v30")
+	}
+
+	if version < 31 {
+		fmt.Println("[MIGRATION] Running migration to v31: Add config changes")
+		if _, err := m.db.Exec(migration030); err != nil {
+			return fmt.Errorf("failed to run migration 030: %w", err)
+		}
+		fmt.Println("[MIGRATION] Successfully migrated to schema v31")
+	}
+
+	if version < 32 {
+		fmt.Println("[MIGRATION] Running migration to v32: Add task dependencies")
+		if _, err := m.db.Exec(migration031); err != nil {
+			return fmt.Errorf("failed to run migration 031: %w", err)
+		}
+		fmt.Println("[MIGRATION] Successfully migrated to schema v32")
+	}
+
+	if version < 33 {
+		fmt.Println("[MIGRATION] Running migration to v33: Add environment security policy")
+		if _, err := m.db.Exec(migration032); err != nil {
+			return fmt.Errorf("failed to run migration 032: %w", err)
+		}
+		fmt.Println("[MIGRATION] Successfully migrated to schema v33")
+	}
+
+	if version < 34 {
+		fmt.Println("[MIGRATION] Running migration to v34: Add mission attempt phase")
+		if _, err := m.db.Exec(migration033); err != nil {
+			return fmt.Errorf("failed to run migration 033: %w", err)
+		}
+		fmt.Println("[MIGRATION] Successfully migrated to schema v34")
+	}
+
 	return nil
 }
 
@@ -292,11 +522,40 @@ func (m *SQLiteMemoryDB) Health() (*HealthStatus, error) {
 		status.DBSizeBytes = fileInfo.Size()
 	}
 
+	// Checkpoint the WAL so it cannot grow without bound
+	if size, frames, err := m.checkpointWAL(); err == nil {
+		status.WALSizeBytes = size
+		status.WALFrames = frames
+	}
+
+	// Check integrity (cached between calls; see integrityCheckInterval)
+	problems, err := m.checkIntegrity(false)
+	switch {
+	case err != nil:
+		status.Integrity = IntegrityError
+		status.IntegrityErrors = []string{err.Error()}
+	case len(problems) > 0:
+		status.Integrity = IntegrityError
+		status.IntegrityErrors = problems
+	default:
+		status.Integrity = IntegrityOK
+	}
+
 	return status, nil
 }
 
 // Transaction helpers
 
+// ErrRollbackFailed is joined with the original error when a transaction
+// could not be rolled back. Check with errors.Is.
+var ErrRollbackFailed = errors.New("transaction rollback failed")
+
+// txFinisher is the part of *sql.Tx used to end a transaction
+type txFinisher interface {
+	Commit() error
+	Rollback() error
+}
+
 // withTx executes a function within a transaction
 func (m *SQLiteMemoryDB) withTx(fn func(*sql.Tx) error) error {
 	tx, err := m.db.Begin()
@@ -304,9 +563,17 @@ func (m *SQLiteMemoryDB) withTx(fn func(*sql.Tx) error) error {
 		return fmt.Errorf("failed to begin transaction: %w", err)
 	}
 
-	if err := fn(tx); err != nil {
-		tx.Rollback()
-		return err
+	return finishTx(tx, fn(tx))
+}
+
+// finishTx commits on success or rolls back on fnErr. A failed rollback is
+// joined with fnErr so neither error is lost.
+func finishTx(tx txFinisher, fnErr error) error {
+	if fnErr != nil {
+		if rbErr := tx.Rollback(); rbErr != nil {
+			return errors.Join(fnErr, fmt.Errorf("%w: %w", ErrRollbackFailed, rbErr))
+		}
+		return fnErr
 	}
 
 	if err := tx.Commit(); err != nil {
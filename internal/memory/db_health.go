@@ -0,0 +1,126 @@
+package memory
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+// Integrity check results reported in HealthStatus.Integrity
+const (
+	IntegrityOK    = "ok"
+	IntegrityError = "error"
+)
+
+// integrityCheckInterval limits how often Health runs the full
+// PRAGMA integrity_check, which reads the whole database
+const integrityCheckInterval = time.Minute
+
+// integrityCheckMaxErrors caps the problems integrity_check reports
+const integrityCheckMaxErrors = 100
+
+// ErrDatabaseCorrupt is returned by RepairDatabase when the corruption goes
+// beyond indexes and the database has to be restored from a backup
+var ErrDatabaseCorrupt = errors.New("memory database corruption is not repairable")
+
+// checkIntegrity returns the problems reported by PRAGMA integrity_check, or
+// none if the database is intact. Results are cached for
+// integrityCheckInterval unless force is set.
+func (m *SQLiteMemoryDB) checkIntegrity(force bool) ([]string, error) {
+	m.integrityMu.Lock()
+	defer m.integrityMu.Unlock()
+
+	if !force && !m.integrityCheckedAt.IsZero() && time.Since(m.integrityCheckedAt) < integrityCheckInterval {
+		return m.integrityProblems, nil
+	}
+
+	rows, err := m.db.Query(fmt.Sprintf("PRAGMA integrity_check(%d)", integrityCheckMaxErrors))
+	if err != nil {
+		return nil, fmt.Errorf("failed to run integrity check: %w", err)
+	}
+	defer rows.Close()
+
+	var problems []string
+	for rows.Next() {
+		var line string
+		if err := rows.Scan(&line); err != nil {
+			return nil, fmt.Errorf("failed to scan integrity check: %w", err)
+		}
+		if line != "ok" {
+			problems = append(problems, line)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read integrity check: %w", err)
+	}
+
+	m.integrityProblems = problems
+	m.integrityCheckedAt = time.Now()
+	return problems, nil
+}
+
+// checkpointWAL folds the write-ahead log back into the database and
+// truncates it. Returns the WAL size in bytes and frame count beforehand.
+func (m *SQLiteMemoryDB) checkpointWAL() (sizeBytes int64, frames int, err error) {
+	if info, statErr := os.Stat(m.path + "-wal"); statErr == nil {
+		sizeBytes = info.Size()
+	}
+
+	var busy, logFrames, checkpointed int
+	if err := m.db.QueryRow("PRAGMA wal_checkpoint(TRUNCATE)").Scan(&busy, &logFrames, &checkpointed); err != nil {
+		return sizeBytes, 0, fmt.Errorf("failed to checkpoint WAL: %w", err)
+	}
+	// Both counts are -1 when the database is not in WAL mode
+	if logFrames > 0 {
+		frames = logFrames
+	}
+	return sizeBytes, frames, nil
+}
+
+// indexOnlyProblems reports whether every integrity problem concerns an
+// index, which REINDEX can rebuild from the table data
+func indexOnlyProblems(problems []string) bool {
+	if len(problems) == 0 {
+		return false
+	}
+	for _, p := range problems {
+		if !strings.Contains(strings.ToLower(p), "index") {
+			return false
+		}
+	}
+	return true
+}
+
+// RepairDatabase rebuilds indexes and compacts the database when
+// integrity_check reports only index problems. Other corruption returns
+// ErrDatabaseCorrupt without touching the file.
+func (m *SQLiteMemoryDB) RepairDatabase() error {
+	problems, err := m.checkIntegrity(true)
+	if err != nil {
+		return err
+	}
+	if len(problems) == 0 {
+		return nil
+	}
+	if !indexOnlyProblems(problems) {
+		return fmt.Errorf("%w: %s", ErrDatabaseCorrupt, problems[0])
+	}
+
+	if _, err := m.db.Exec("REINDEX"); err != nil {
+		return fmt.Errorf("failed to reindex: %w", err)
+	}
+	if _, err := m.db.Exec("VACUUM"); err != nil {
+		return fmt.Errorf("failed to vacuum: %w", err)
+	}
+
+	problems, err = m.checkIntegrity(true)
+	if err != nil {
+		return err
+	}
+	if len(problems) > 0 {
+		return fmt.Errorf("repair did not resolve integrity problems: %s", problems[0])
+	}
+	return nil
+}
@@ -0,0 +1,42 @@
+package memory
+
+import "testing"
+
+func TestHealthIntegrity(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	health, err := db.Health()
+	if err != nil {
+		t.Fatalf("Health failed: %v", err)
+	}
+	if health.Integrity != IntegrityOK || len(health.IntegrityErrors) != 0 {
+		t.Errorf("Integrity = %q %v, want ok", health.Integrity, health.IntegrityErrors)
+	}
+	if health.WALSizeBytes < 0 || health.WALFrames < 0 {
+		t.Errorf("Unexpected WAL stats: size=%d frames=%d", health.WALSizeBytes, health.WALFrames)
+	}
+
+	// A healthy database needs no repair
+	if err := db.RepairDatabase(); err != nil {
+		t.Errorf("RepairDatabase failed: %v", err)
+	}
+}
+
+func TestIndexOnlyProblems(t *testing.T) {
+	tests := []struct {
+		problems []string
+		want     bool
+	}{
+		{nil, false},
+		{[]string{"row 3 missing from index idx_tasks_status"}, true},
+		{[]string{"wrong # of entries in index idx_agents_role", "row 1 missing from index idx_agents_role"}, true},
+		{[]string{"row 1 missing from index idx_a", "Page 12: btreeInitPage() returns error code 11"}, false},
+		{[]string{"database disk image is malformed"}, false},
+	}
+	for _, tt := range tests {
+		if got := indexOnlyProblems(tt.problems); got != tt.want {
+			t.Errorf("indexOnlyProblems(%v) = %v, want %v", tt.problems, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,92 @@
+package memory
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+// fakeTx lets tests control Commit/Rollback outcomes
+type fakeTx struct {
+	commitErr   error
+	rollbackErr error
+	committed   bool
+	rolledBack  bool
+}
+
+func (f *fakeTx) Commit() error {
+	f.committed = true
+	return f.commitErr
+}
+
+func (f *fakeTx) Rollback() error {
+	f.rolledBack = true
+	return f.rollbackErr
+}
+
+func TestFinishTx_RollbackFailureKeepsBothErrors(t *testing.T) {
+	fnErr := errors.New("insert failed")
+	tx := &fakeTx{rollbackErr: errors.New("connection lost")}
+
+	err := finishTx(tx, fnErr)
+	if err == nil {
+		t.Fatal("expected error")
+	}
+	if !tx.rolledBack || tx.committed {
+		t.Errorf("expected rollback only, got rolledBack=%v committed=%v", tx.rolledBack, tx.committed)
+	}
+	if !errors.Is(err, fnErr) {
+		t.Errorf("expected original error to be preserved: %v", err)
+	}
+	if !errors.Is(err, ErrRollbackFailed) {
+		t.Errorf("expected ErrRollbackFailed: %v", err)
+	}
+	if !strings.Contains(err.Error(), "insert failed") || !strings.Contains(err.Error(), "connection lost") {
+		t.Errorf("expected both messages in %q", err.Error())
+	}
+}
+
+func TestFinishTx_RollbackSucceeds(t *testing.T) {
+	fnErr := errors.New("insert failed")
+	tx := &fakeTx{}
+
+	err := finishTx(tx, fnErr)
+	if err != fnErr {
+		t.Errorf("expected original error unchanged, got %v", err)
+	}
+	if errors.Is(err, ErrRollbackFailed) {
+		t.Error("did not expect ErrRollbackFailed")
+	}
+}
+
+func TestFinishTx_Commit(t *testing.T) {
+	tx := &fakeTx{}
+	if err := finishTx(tx, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !tx.committed || tx.rolledBack {
+		t.Errorf("expected commit only, got committed=%v rolledBack=%v", tx.committed, tx.rolledBack)
+	}
+}
+
+func TestNewMemoryDBAppliesPragmas(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+	sqlDB := db.(*SQLiteMemoryDB).DB()
+
+	var timeout int
+	if err := sqlDB.QueryRow("PRAGMA busy_timeout").Scan(&timeout); err != nil {
+		t.Fatalf("PRAGMA busy_timeout failed: %v", err)
+	}
+	if timeout != 5000 {
+		t.Errorf("Expected busy_timeout 5000, got %d", timeout)
+	}
+
+	var mode string
+	if err := sqlDB.QueryRow("PRAGMA journal_mode").Scan(&mode); err != nil {
+		t.Fatalf("PRAGMA journal_mode failed: %v", err)
+	}
+	if mode != "wal" {
+		t.Errorf("Expected journal_mode wal, got %q", mode)
+	}
+}
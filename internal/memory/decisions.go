@@ -2,9 +2,19 @@ package memory
 
 import (
 	"database/sql"
+	"errors"
 	"fmt"
 )
 
+var (
+	// ErrDeploymentNotFound is returned when no deployment has the given ID
+	ErrDeploymentNotFound = errors.New("deployment not found")
+
+	// ErrDeploymentNotStartable is returned by StartDeployment when the
+	// deployment is no longer proposed or approved
+	ErrDeploymentNotStartable = errors.New("deployment is not proposed or approved")
+)
+
 // StoreDecision stores a human decision
 func (m *SQLiteMemoryDB) StoreDecision(decision *HumanDecision) error {
 	result, err := m.db.Exec(`
@@ -151,6 +161,37 @@ func (m *SQLiteMemoryDB) GetRecentDeployments(repoID string, limit int) ([]*Depl
 	return scanDeployments(rows)
 }
 
+// StartDeployment moves a proposed or approved deployment to executing in a
+// single conditional UPDATE, so only one caller can start it
+func (m *SQLiteMemoryDB) StartDeployment(deploymentID int64) error {
+	result, err := m.db.Exec(`
+		UPDATE deployments
+		SET status = 'executing', executed_at = CURRENT_TIMESTAMP
+		WHERE id = ? AND status IN ('proposed', 'approved')`,
+		deploymentID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to start deployment: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to check affected rows: %w", err)
+	}
+	if rows > 0 {
+		return nil
+	}
+
+	var exists int
+	err = m.db.QueryRow(`SELECT 1 FROM deployments WHERE id = ?`, deploymentID).Scan(&exists)
+	if errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("%w: %d", ErrDeploymentNotFound, deploymentID)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to check deployment: %w", err)
+	}
+	return fmt.Errorf("%w: %d", ErrDeploymentNotStartable, deploymentID)
+}
+
 // UpdateDeploymentStatus updates the status of a deployment
 func (m *SQLiteMemoryDB) UpdateDeploymentStatus(deploymentID int64, status string) error {
 	query := `
@@ -0,0 +1,144 @@
+package memory
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+var (
+	// ErrDefectCategoryNotFound is returned when a defect category code does
+	// not exist
+	ErrDefectCategoryNotFound = errors.New("defect category not found")
+
+	// ErrDefectCategoryExists is returned when creating a category whose code
+	// is already taken
+	ErrDefectCategoryExists = errors.New("defect category already exists")
+
+	// ErrDefectCategoryInUse is returned when deleting a category that review
+	// defects still reference
+	ErrDefectCategoryInUse = errors.New("defect category is referenced by review defects")
+
+	// ErrInvalidDefectCategory is returned for a category or update with
+	// missing or invalid fields
+	ErrInvalidDefectCategory = errors.New("invalid defect category")
+)
+
+// DefectSeverities are the valid default_severity values, most severe first
+var DefectSeverities = []string{"critical", "high", "medium", "low", "info"}
+
+// defectCategoryColumns are the columns UpdateDefectCategory may change
+var defectCategoryColumns = map[string]bool{
+	"name":             true,
+	"category_type":    true,
+	"description":      true,
+	"default_severity": true,
+}
+
+// validDefectSeverity reports whether s is one of DefectSeverities
+func validDefectSeverity(s string) bool {
+	for _, sev := range DefectSeverities {
+		if s == sev {
+			return true
+		}
+	}
+	return false
+}
+
+// CreateDefectCategory adds a custom defect category. DefaultSeverity
+// defaults to "medium", matching SeedDefectCategories.
+func (m *SQLiteMemoryDB) CreateDefectCategory(category *DefectCategory) error {
+	category.Code = strings.TrimSpace(category.Code)
+	if category.Code == "" || category.Name == "" || category.CategoryType == "" {
+		return fmt.Errorf("%w: code, name and category_type are required", ErrInvalidDefectCategory)
+	}
+	if category.DefaultSeverity == "" {
+		category.DefaultSeverity = "medium"
+	}
+	if !validDefectSeverity(category.DefaultSeverity) {
+		return fmt.Errorf("%w: default_severity must be one of %s", ErrInvalidDefectCategory, strings.Join(DefectSeverities, ", "))
+	}
+
+	_, err := m.db.Exec(`
+		INSERT INTO defect_categories (code, name, category_type, description, default_severity)
+		VALUES (?, ?, ?, ?, ?)
+	`, category.Code, category.Name, category.CategoryType, category.Description, category.DefaultSeverity)
+	if isUniqueViolation(err) {
+		return fmt.Errorf("%w: %s", ErrDefectCategoryExists, category.Code)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to create defect category: %w", err)
+	}
+	return nil
+}
+
+// UpdateDefectCategory changes the given columns of a defect category.
+// updates is keyed by column name: name, category_type, description or
+// default_severity; the code itself cannot be changed.
+func (m *SQLiteMemoryDB) UpdateDefectCategory(code string, updates map[string]interface{}) error {
+	if len(updates) == 0 {
+		return fmt.Errorf("%w: no fields to update", ErrInvalidDefectCategory)
+	}
+
+	columns := make([]string, 0, len(updates))
+	for column := range updates {
+		columns = append(columns, column)
+	}
+	sort.Strings(columns)
+
+	sets := make([]string, 0, len(columns))
+	args := make([]interface{}, 0, len(columns)+1)
+	for _, column := range columns {
+		if !defectCategoryColumns[column] {
+			return fmt.Errorf("%w: %s cannot be updated", ErrInvalidDefectCategory, column)
+		}
+		value, ok := updates[column].(string)
+		if !ok {
+			return fmt.Errorf("%w: %s must be a string", ErrInvalidDefectCategory, column)
+		}
+		switch {
+		case column == "default_severity" && !validDefectSeverity(value):
+			return fmt.Errorf("%w: default_severity must be one of %s", ErrInvalidDefectCategory, strings.Join(DefectSeverities, ", "))
+		case (column == "name" || column == "category_type") && value == "":
+			return fmt.Errorf("%w: %s cannot be empty", ErrInvalidDefectCategory, column)
+		}
+		sets = append(sets, column+" = ?")
+		args = append(args, value)
+	}
+	args = append(args, code)
+
+	result, err := m.db.Exec(`UPDATE defect_categories SET `+strings.Join(sets, ", ")+` WHERE code = ?`, args...)
+	if err != nil {
+		return fmt.Errorf("failed to update defect category: %w", err)
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return fmt.Errorf("%w: %s", ErrDefectCategoryNotFound, code)
+	}
+	return nil
+}
+
+// DeleteDefectCategory removes a defect category. Categories that any
+// review_defects row still references are kept and ErrDefectCategoryInUse is
+// returned.
+func (m *SQLiteMemoryDB) DeleteDefectCategory(code string) error {
+	return m.withTx(func(tx *sql.Tx) error {
+		var refs int
+		if err := tx.QueryRow(`SELECT COUNT(*) FROM review_defects WHERE category = ?`, code).Scan(&refs); err != nil {
+			return fmt.Errorf("failed to check defect category references: %w", err)
+		}
+		if refs > 0 {
+			return fmt.Errorf("%w: %s is used by %d defect(s)", ErrDefectCategoryInUse, code, refs)
+		}
+
+		result, err := tx.Exec(`DELETE FROM defect_categories WHERE code = ?`, code)
+		if err != nil {
+			return fmt.Errorf("failed to delete defect category: %w", err)
+		}
+		if n, _ := result.RowsAffected(); n == 0 {
+			return fmt.Errorf("%w: %s", ErrDefectCategoryNotFound, code)
+		}
+		return nil
+	})
+}
@@ -0,0 +1,89 @@
+package memory
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestDefectCategoryCRUD(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	category := &DefectCategory{Code: "I18N", Name: "Localization", CategoryType: "custom", Description: "Hardcoded user-facing strings"}
+	if err := db.CreateDefectCategory(category); err != nil {
+		t.Fatalf("CreateDefectCategory failed: %v", err)
+	}
+	if category.DefaultSeverity != "medium" {
+		t.Errorf("Expected default severity medium, got %q", category.DefaultSeverity)
+	}
+	if err := db.CreateDefectCategory(&DefectCategory{Code: "I18N", Name: "Dup", CategoryType: "custom"}); !errors.Is(err, ErrDefectCategoryExists) {
+		t.Errorf("Expected ErrDefectCategoryExists, got %v", err)
+	}
+	if err := db.CreateDefectCategory(&DefectCategory{Code: "BAD", Name: "Bad", CategoryType: "custom", DefaultSeverity: "urgent"}); !errors.Is(err, ErrInvalidDefectCategory) {
+		t.Errorf("Expected ErrInvalidDefectCategory for unknown severity, got %v", err)
+	}
+
+	if err := db.UpdateDefectCategory("I18N", map[string]interface{}{"default_severity": "low", "name": "Internationalization"}); err != nil {
+		t.Fatalf("UpdateDefectCategory failed: %v", err)
+	}
+	categories, err := db.GetDefectCategories()
+	if err != nil {
+		t.Fatalf("GetDefectCategories failed: %v", err)
+	}
+	var updated *DefectCategory
+	for _, c := range categories {
+		if c.Code == "I18N" {
+			updated = c
+		}
+	}
+	if updated == nil || updated.Name != "Internationalization" || updated.DefaultSeverity != "low" {
+		t.Errorf("Expected updated category, got %+v", updated)
+	}
+
+	for name, updates := range map[string]map[string]interface{}{
+		"code column":  {"code": "L10N"},
+		"non-string":   {"name": 42},
+		"empty name":   {"name": ""},
+		"no fields":    {},
+		"bad severity": {"default_severity": "urgent"},
+	} {
+		if err := db.UpdateDefectCategory("I18N", updates); !errors.Is(err, ErrInvalidDefectCategory) {
+			t.Errorf("%s: expected ErrInvalidDefectCategory, got %v", name, err)
+		}
+	}
+	if err := db.UpdateDefectCategory("MISSING", map[string]interface{}{"name": "x"}); !errors.Is(err, ErrDefectCategoryNotFound) {
+		t.Errorf("Expected ErrDefectCategoryNotFound, got %v", err)
+	}
+
+	if err := db.DeleteDefectCategory("I18N"); err != nil {
+		t.Fatalf("DeleteDefectCategory failed: %v", err)
+	}
+	if err := db.DeleteDefectCategory("I18N"); !errors.Is(err, ErrDefectCategoryNotFound) {
+		t.Errorf("Expected ErrDefectCategoryNotFound, got %v", err)
+	}
+}
+
+func TestDeleteDefectCategory_InUse(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	assignment := &TaskAssignment{TaskID: "TASK-CAT", AssignedTo: "sgt-green", AssignedBy: "captain", AssignmentType: "review", Status: "in_progress"}
+	if err := db.CreateAssignment(assignment); err != nil {
+		t.Fatalf("CreateAssignment failed: %v", err)
+	}
+	board := &ReviewBoard{AssignmentID: assignment.ID, ReviewerCount: 2, Status: "in_progress", RiskLevel: "medium"}
+	if err := db.CreateReviewBoard(board); err != nil {
+		t.Fatalf("CreateReviewBoard failed: %v", err)
+	}
+	defect := &ReviewDefect{BoardID: board.ID, ReviewerID: "r1", Category: "LOGIC", Severity: "high", Title: "bug", Description: "bug", Status: "open"}
+	if err := db.CreateDefect(defect); err != nil {
+		t.Fatalf("CreateDefect failed: %v", err)
+	}
+
+	if err := db.DeleteDefectCategory("LOGIC"); !errors.Is(err, ErrDefectCategoryInUse) {
+		t.Errorf("Expected ErrDefectCategoryInUse, got %v", err)
+	}
+	if err := db.DeleteDefectCategory("STYLE"); err != nil {
+		t.Errorf("Expected unreferenced category to be deletable, got %v", err)
+	}
+}
@@ -0,0 +1,125 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// DefectPatternAuthorMin is how many defects of one category an author must
+// receive to be listed as a frequent author of that pattern
+const DefectPatternAuthorMin = 3
+
+// DefectPattern summarizes one defect category across all review boards
+type DefectPattern struct {
+	Category        string    `json:"category"`
+	OccurrenceCount int       `json:"occurrence_count"`
+	AffectedBoards  int       `json:"affected_boards"`
+	AvgSeverity     float64   `json:"avg_severity"` // info=0, low=1, medium=2, high=3, critical=4
+	FirstSeen       time.Time `json:"first_seen"`
+	LastSeen        time.Time `json:"last_seen"`
+	FrequentAuthors []string  `json:"frequent_authors"` // authors with DefectPatternAuthorMin+ defects in this category
+}
+
+// GetDefectPatterns returns the most frequent defect categories across review
+// boards, most common first
+func (m *SQLiteMemoryDB) GetDefectPatterns(ctx context.Context, limit int) ([]*DefectPattern, error) {
+	query := `
+		SELECT category,
+		       COUNT(*),
+		       COUNT(DISTINCT board_id),
+		       AVG(CASE severity
+		           WHEN 'critical' THEN 4
+		           WHEN 'high' THEN 3
+		           WHEN 'medium' THEN 2
+		           WHEN 'low' THEN 1
+		           ELSE 0 END),
+		       MIN(created_at),
+		       MAX(created_at)
+		FROM review_defects
+		GROUP BY category
+		ORDER BY COUNT(*) DESC, category
+		LIMIT ?
+	`
+	rows, err := m.db.QueryContext(ctx, query, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query defect patterns: %w", err)
+	}
+	defer rows.Close()
+
+	var patterns []*DefectPattern
+	byCategory := make(map[string]*DefectPattern)
+	for rows.Next() {
+		p := &DefectPattern{FrequentAuthors: []string{}}
+		var firstSeen, lastSeen string
+		if err := rows.Scan(&p.Category, &p.OccurrenceCount, &p.AffectedBoards, &p.AvgSeverity, &firstSeen, &lastSeen); err != nil {
+			return nil, fmt.Errorf("failed to scan defect pattern: %w", err)
+		}
+		// Aggregates lose the DATETIME column type, so parse the text form
+		p.FirstSeen = parseDBTime(firstSeen)
+		p.LastSeen = parseDBTime(lastSeen)
+		patterns = append(patterns, p)
+		byCategory[p.Category] = p
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if len(patterns) == 0 {
+		return patterns, nil
+	}
+
+	if err := m.loadDefectPatternAuthors(ctx, byCategory); err != nil {
+		return nil, err
+	}
+	return patterns, nil
+}
+
+// loadDefectPatternAuthors fills FrequentAuthors for the given patterns. The
+// author of a defect is the agent the reviewed assignment belongs to.
+func (m *SQLiteMemoryDB) loadDefectPatternAuthors(ctx context.Context, byCategory map[string]*DefectPattern) error {
+	placeholders := make([]string, 0, len(byCategory))
+	args := make([]interface{}, 0, len(byCategory)+1)
+	for category := range byCategory {
+		placeholders = append(placeholders, "?")
+		args = append(args, category)
+	}
+	args = append(args, DefectPatternAuthorMin)
+
+	query := `
+		SELECT rd.category, ta.assigned_to
+		FROM review_defects rd
+		JOIN review_boards rb ON rd.board_id = rb.id
+		JOIN task_assignments ta ON rb.assignment_id = ta.id
+		WHERE rd.category IN (` + strings.Join(placeholders, ", ") + `)
+		GROUP BY rd.category, ta.assigned_to
+		HAVING COUNT(*) >= ?
+		ORDER BY COUNT(*) DESC, ta.assigned_to
+	`
+	rows, err := m.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("failed to query defect pattern authors: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var category, author string
+		if err := rows.Scan(&category, &author); err != nil {
+			return fmt.Errorf("failed to scan defect pattern author: %w", err)
+		}
+		p := byCategory[category]
+		p.FrequentAuthors = append(p.FrequentAuthors, author)
+	}
+	return rows.Err()
+}
+
+// parseDBTime parses a timestamp SQLite returned as text, returning the zero
+// time if it is not in a known layout
+func parseDBTime(s string) time.Time {
+	for _, layout := range []string{"2006-01-02 15:04:05", time.RFC3339Nano, "2006-01-02 15:04:05.999999999-07:00"} {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t
+		}
+	}
+	return time.Time{}
+}
@@ -0,0 +1,62 @@
+package memory
+
+import (
+	"context"
+	"testing"
+)
+
+func TestGetDefectPatterns(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	newBoard := func(author string) int64 {
+		assignment := &TaskAssignment{TaskID: "TASK-" + author, AssignedTo: author, AssignedBy: "captain", AssignmentType: "review", Status: "in_progress"}
+		if err := db.CreateAssignment(assignment); err != nil {
+			t.Fatalf("CreateAssignment failed: %v", err)
+		}
+		board := &ReviewBoard{AssignmentID: assignment.ID, ReviewerCount: 1, Status: "in_progress", RiskLevel: "low"}
+		if err := db.CreateReviewBoard(board); err != nil {
+			t.Fatalf("CreateReviewBoard failed: %v", err)
+		}
+		return board.ID
+	}
+	addDefect := func(boardID int64, category, severity string) {
+		d := &ReviewDefect{BoardID: boardID, ReviewerID: "r1", Category: category, Severity: severity, Title: category, Description: category, Status: "open"}
+		if err := db.CreateDefect(d); err != nil {
+			t.Fatalf("CreateDefect failed: %v", err)
+		}
+	}
+
+	boardA := newBoard("agent-a")
+	boardB := newBoard("agent-b")
+	addDefect(boardA, "LOGIC", "high")
+	addDefect(boardA, "LOGIC", "medium")
+	addDefect(boardA, "LOGIC", "low")
+	addDefect(boardB, "LOGIC", "high")
+	addDefect(boardB, "STYLE", "info")
+
+	patterns, err := db.GetDefectPatterns(context.Background(), 10)
+	if err != nil {
+		t.Fatalf("GetDefectPatterns failed: %v", err)
+	}
+	if len(patterns) != 2 {
+		t.Fatalf("Expected 2 patterns, got %d", len(patterns))
+	}
+
+	logic := patterns[0]
+	if logic.Category != "LOGIC" || logic.OccurrenceCount != 4 || logic.AffectedBoards != 2 {
+		t.Errorf("Unexpected top pattern: %+v", logic)
+	}
+	if logic.AvgSeverity != 2.25 {
+		t.Errorf("Expected avg severity 2.25, got %f", logic.AvgSeverity)
+	}
+	if logic.FirstSeen.IsZero() || logic.LastSeen.Before(logic.FirstSeen) {
+		t.Errorf("Unexpected first/last seen: %v / %v", logic.FirstSeen, logic.LastSeen)
+	}
+	if len(logic.FrequentAuthors) != 1 || logic.FrequentAuthors[0] != "agent-a" {
+		t.Errorf("Expected agent-a as the only frequent LOGIC author, got %v", logic.FrequentAuthors)
+	}
+	if len(patterns[1].FrequentAuthors) != 0 {
+		t.Errorf("Expected no frequent STYLE authors, got %v", patterns[1].FrequentAuthors)
+	}
+}
@@ -0,0 +1,261 @@
+package memory
+
+import (
+	"bufio"
+	"context"
+	"database/sql"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// Lines framing a SQL dump, in the same layout as the sqlite3 .dump command
+const (
+	dumpHeader = "PRAGMA foreign_keys=OFF;\nBEGIN TRANSACTION;\n"
+	dumpFooter = "COMMIT;\n"
+)
+
+// sqlQueryer is satisfied by *sql.DB, *sql.Conn and *sql.Tx
+type sqlQueryer interface {
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+}
+
+// schemaObject is a row of sqlite_master
+type schemaObject struct {
+	objType string
+	name    string
+	sql     string
+	virtual bool
+}
+
+// quoteIdent quotes a table or column name for use in SQL
+func quoteIdent(name string) string {
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
+
+// schemaObjects lists user tables, indexes, views and triggers in creation
+// order. Internal sqlite_ tables, automatic indexes and virtual table shadow
+// tables are left out, since SQLite creates them itself.
+func schemaObjects(ctx context.Context, q sqlQueryer) ([]schemaObject, error) {
+	rows, err := q.QueryContext(ctx, `
+		SELECT type, name, sql FROM sqlite_master
+		WHERE sql IS NOT NULL AND name NOT LIKE 'sqlite_%'
+		ORDER BY rowid
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read schema: %w", err)
+	}
+	defer rows.Close()
+
+	var all []schemaObject
+	for rows.Next() {
+		var obj schemaObject
+		if err := rows.Scan(&obj.objType, &obj.name, &obj.sql); err != nil {
+			return nil, fmt.Errorf("failed to scan schema: %w", err)
+		}
+		obj.virtual = obj.objType == "table" && strings.HasPrefix(strings.ToUpper(obj.sql), "CREATE VIRTUAL TABLE")
+		all = append(all, obj)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	var virtualTables []string
+	for _, obj := range all {
+		if obj.virtual {
+			virtualTables = append(virtualTables, obj.name+"_")
+		}
+	}
+
+	objects := make([]schemaObject, 0, len(all))
+	for _, obj := range all {
+		shadow := false
+		if obj.objType == "table" && !obj.virtual {
+			for _, prefix := range virtualTables {
+				if strings.HasPrefix(obj.name, prefix) {
+					shadow = true
+					break
+				}
+			}
+		}
+		if !shadow {
+			objects = append(objects, obj)
+		}
+	}
+	return objects, nil
+}
+
+// DumpSQL writes the whole database as SQL statements, like sqlite3 .dump.
+// Full-text indexes are rebuilt from their content tables on restore
+// rather than dumped.
+func (m *SQLiteMemoryDB) DumpSQL(w io.Writer) error {
+	ctx := context.Background()
+
+	// Read from a single transaction so the dump is consistent
+	tx, err := m.db.BeginTx(ctx, &sql.TxOptions{ReadOnly: true})
+	if err != nil {
+		return fmt.Errorf("failed to begin dump: %w", err)
+	}
+	defer tx.Rollback()
+
+	objects, err := schemaObjects(ctx, tx)
+	if err != nil {
+		return err
+	}
+
+	bw := bufio.NewWriter(w)
+	bw.WriteString(dumpHeader)
+
+	// Tables and their rows first, then indexes, views and triggers, so
+	// triggers do not fire while rows are restored
+	for _, obj := range objects {
+		if obj.objType != "table" {
+			continue
+		}
+		fmt.Fprintf(bw, "%s;\n", obj.sql)
+		if obj.virtual {
+			continue
+		}
+		if err := dumpTableRows(ctx, tx, bw, obj.name); err != nil {
+			return err
+		}
+	}
+	if err := dumpTableRows(ctx, tx, bw, "sqlite_sequence"); err != nil {
+		return err
+	}
+	for _, obj := range objects {
+		if obj.objType != "table" {
+			fmt.Fprintf(bw, "%s;\n", obj.sql)
+		}
+	}
+	for _, obj := range objects {
+		if obj.virtual && strings.Contains(strings.ToLower(obj.sql), "fts5") {
+			fmt.Fprintf(bw, "INSERT INTO %s(%s) VALUES('rebuild');\n", quoteIdent(obj.name), quoteIdent(obj.name))
+		}
+	}
+
+	bw.WriteString(dumpFooter)
+	return bw.Flush()
+}
+
+// dumpTableRows writes an INSERT statement for every row of table, using
+// SQLite's quote() so every value round-trips exactly
+func dumpTableRows(ctx context.Context, q sqlQueryer, w io.Writer, table string) error {
+	columns, err := tableColumns(ctx, q, table)
+	if err != nil || len(columns) == 0 {
+		// sqlite_sequence only exists once an AUTOINCREMENT table has rows
+		return err
+	}
+
+	quoted := make([]string, len(columns))
+	for i, col := range columns {
+		quoted[i] = "quote(" + quoteIdent(col) + ")"
+	}
+	rows, err := q.QueryContext(ctx, fmt.Sprintf("SELECT %s FROM %s", strings.Join(quoted, " || ',' || "), quoteIdent(table)))
+	if err != nil {
+		return fmt.Errorf("failed to dump table %s: %w", table, err)
+	}
+	defer rows.Close()
+
+	if table == "sqlite_sequence" {
+		fmt.Fprintln(w, "DELETE FROM sqlite_sequence;")
+	}
+	for rows.Next() {
+		var values string
+		if err := rows.Scan(&values); err != nil {
+			return fmt.Errorf("failed to dump table %s: %w", table, err)
+		}
+		fmt.Fprintf(w, "INSERT INTO %s VALUES(%s);\n", quoteIdent(table), values)
+	}
+	return rows.Err()
+}
+
+// tableColumns returns a table's column names in declaration order
+func tableColumns(ctx context.Context, q sqlQueryer, table string) ([]string, error) {
+	rows, err := q.QueryContext(ctx, fmt.Sprintf("PRAGMA table_info(%s)", quoteIdent(table)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read columns of %s: %w", table, err)
+	}
+	defer rows.Close()
+
+	var columns []string
+	for rows.Next() {
+		var (
+			cid, notNull, pk int
+			name, colType    string
+			dflt             sql.NullString
+		)
+		if err := rows.Scan(&cid, &name, &colType, &notNull, &dflt, &pk); err != nil {
+			return nil, fmt.Errorf("failed to read columns of %s: %w", table, err)
+		}
+		columns = append(columns, name)
+	}
+	return columns, rows.Err()
+}
+
+// RestoreSQL replaces the whole database with the contents of a dump
+// written by DumpSQL, then migrates it to the current schema version. The
+// replacement happens in one transaction, so a bad dump leaves the
+// database unchanged.
+func (m *SQLiteMemoryDB) RestoreSQL(dump string) error {
+	if !strings.HasPrefix(dump, dumpHeader) || !strings.HasSuffix(dump, dumpFooter) {
+		return fmt.Errorf("not a memory database dump")
+	}
+	body := strings.TrimSuffix(strings.TrimPrefix(dump, dumpHeader), dumpFooter)
+
+	ctx := context.Background()
+	conn, err := m.db.Conn(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get connection: %w", err)
+	}
+	defer conn.Close()
+
+	// foreign_keys cannot change inside a transaction. Put back whatever
+	// this pooled connection had before.
+	var foreignKeys int
+	if err := conn.QueryRowContext(ctx, "PRAGMA foreign_keys").Scan(&foreignKeys); err != nil {
+		return fmt.Errorf("failed to read foreign keys setting: %w", err)
+	}
+	if _, err := conn.ExecContext(ctx, "PRAGMA foreign_keys=OFF"); err != nil {
+		return fmt.Errorf("failed to disable foreign keys: %w", err)
+	}
+	defer conn.ExecContext(ctx, fmt.Sprintf("PRAGMA foreign_keys=%d", foreignKeys))
+
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin restore: %w", err)
+	}
+	defer tx.Rollback()
+
+	objects, err := schemaObjects(ctx, tx)
+	if err != nil {
+		return err
+	}
+	// Views first since they may depend on tables; dropping a table drops
+	// its indexes and triggers
+	for _, objType := range []string{"view", "table"} {
+		for _, obj := range objects {
+			if obj.objType != objType {
+				continue
+			}
+			if _, err := tx.ExecContext(ctx, fmt.Sprintf("DROP %s IF EXISTS %s", strings.ToUpper(objType), quoteIdent(obj.name))); err != nil {
+				return fmt.Errorf("failed to drop %s %s: %w", objType, obj.name, err)
+			}
+		}
+	}
+
+	if _, err := tx.ExecContext(ctx, body); err != nil {
+		return fmt.Errorf("failed to restore dump: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit restore: %w", err)
+	}
+
+	// Cached integrity results describe the old contents
+	m.integrityMu.Lock()
+	m.integrityCheckedAt = time.Time{}
+	m.integrityMu.Unlock()
+
+	return m.migrate()
+}
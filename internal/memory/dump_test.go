@@ -0,0 +1,82 @@
+package memory
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestDumpAndRestoreSQL(t *testing.T) {
+	src, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	if err := src.SetContext("mission", "it's a 'quoted'\nmultiline value", 5, 0); err != nil {
+		t.Fatalf("SetContext failed: %v", err)
+	}
+	if err := src.CreateDocument(&Document{DocType: "report", Title: "Recon findings", Content: "SQL injection in login", Format: "markdown", Status: "active", Version: 1}); err != nil {
+		t.Fatalf("CreateDocument failed: %v", err)
+	}
+
+	var dump bytes.Buffer
+	if err := src.(*SQLiteMemoryDB).DumpSQL(&dump); err != nil {
+		t.Fatalf("DumpSQL failed: %v", err)
+	}
+	if !strings.HasPrefix(dump.String(), dumpHeader) || !strings.HasSuffix(dump.String(), dumpFooter) {
+		t.Fatalf("dump not framed like sqlite3 .dump:\n%s", dump.String())
+	}
+
+	dst, err := NewMemoryDB(filepath.Join(t.TempDir(), "restored.db"))
+	if err != nil {
+		t.Fatalf("NewMemoryDB failed: %v", err)
+	}
+	defer dst.Close()
+	if err := dst.SetContext("stale", "replaced by restore", 1, 0); err != nil {
+		t.Fatalf("SetContext failed: %v", err)
+	}
+
+	if err := dst.(*SQLiteMemoryDB).RestoreSQL(dump.String()); err != nil {
+		t.Fatalf("RestoreSQL failed: %v", err)
+	}
+
+	ctx, err := dst.GetContext("mission")
+	if err != nil || ctx == nil || ctx.Value != "it's a 'quoted'\nmultiline value" {
+		t.Errorf("restored context = %+v, %v", ctx, err)
+	}
+	if stale, _ := dst.GetContext("stale"); stale != nil {
+		t.Error("restore should replace existing rows")
+	}
+
+	// The full-text index is rebuilt from the restored documents
+	docs, err := dst.SearchDocuments("injection", 10)
+	if err != nil || len(docs) != 1 {
+		t.Errorf("SearchDocuments after restore = %d docs, %v", len(docs), err)
+	}
+
+	health, err := dst.Health()
+	if err != nil || health.SchemaVersion == 0 || health.Integrity != IntegrityOK {
+		t.Errorf("restored database unhealthy: %+v, %v", health, err)
+	}
+}
+
+func TestRestoreSQLRejectsInvalidDump(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	if err := db.SetContext("keep", "value", 1, 0); err != nil {
+		t.Fatalf("SetContext failed: %v", err)
+	}
+
+	sqlite := db.(*SQLiteMemoryDB)
+	if err := sqlite.RestoreSQL("DROP TABLE captain_context;"); err == nil {
+		t.Error("expected error for unframed dump")
+	}
+	if err := sqlite.RestoreSQL(dumpHeader + "CREATE TABLE broken (;\n" + dumpFooter); err == nil {
+		t.Error("expected error for invalid SQL")
+	}
+
+	// A failed restore leaves the database untouched
+	if keep, err := db.GetContext("keep"); err != nil || keep == nil {
+		t.Errorf("context lost after failed restore: %+v, %v", keep, err)
+	}
+}
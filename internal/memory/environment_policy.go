@@ -0,0 +1,135 @@
+package memory
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrInvalidSecurityPolicy is returned when a security policy has an unknown
+// max finding severity
+var ErrInvalidSecurityPolicy = errors.New("invalid security policy")
+
+// SecurityPolicy limits what recon may do in an environment. Customer
+// environments default to no network scans or file writes.
+type SecurityPolicy struct {
+	AllowNetworkScan     bool   `json:"allow_network_scan"`
+	AllowFileSystemWrite bool   `json:"allow_file_system_write"`
+	MaxFindingSeverity   string `json:"max_finding_severity,omitempty"` // More severe findings are saved as ignored; "" keeps all
+}
+
+// DefaultSecurityPolicy returns the policy for an environment type with no
+// stored policy
+func DefaultSecurityPolicy(envType string) SecurityPolicy {
+	if envType == "customer" {
+		return SecurityPolicy{}
+	}
+	return SecurityPolicy{AllowNetworkScan: true, AllowFileSystemWrite: true}
+}
+
+// Validate checks MaxFindingSeverity is empty or one of DefectSeverities
+func (p SecurityPolicy) Validate() error {
+	if p.MaxFindingSeverity != "" && severityRank(p.MaxFindingSeverity) < 0 {
+		return fmt.Errorf("%w: max_finding_severity must be one of %s", ErrInvalidSecurityPolicy, strings.Join(DefectSeverities, ", "))
+	}
+	return nil
+}
+
+// Suppresses reports whether a finding of severity is above
+// MaxFindingSeverity and so should be saved as ignored
+func (p SecurityPolicy) Suppresses(severity string) bool {
+	if p.MaxFindingSeverity == "" {
+		return false
+	}
+	rank := severityRank(severity)
+	return rank >= 0 && rank < severityRank(p.MaxFindingSeverity)
+}
+
+// severityRank returns the index of severity in DefectSeverities, where
+// lower is more severe, or -1 if it is unknown
+func severityRank(severity string) int {
+	for i, s := range DefectSeverities {
+		if s == severity {
+			return i
+		}
+	}
+	return -1
+}
+
+// decodeSecurityPolicy parses a stored security_policy column; NULL or empty
+// yields nil
+func decodeSecurityPolicy(raw sql.NullString) (*SecurityPolicy, error) {
+	if !raw.Valid || raw.String == "" {
+		return nil, nil
+	}
+	var policy SecurityPolicy
+	if err := json.Unmarshal([]byte(raw.String), &policy); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal security policy: %w", err)
+	}
+	return &policy, nil
+}
+
+// encodeSecurityPolicy formats a policy for the security_policy column
+func encodeSecurityPolicy(policy *SecurityPolicy) (sql.NullString, error) {
+	if policy == nil {
+		return sql.NullString{}, nil
+	}
+	data, err := json.Marshal(policy)
+	if err != nil {
+		return sql.NullString{}, fmt.Errorf("failed to marshal security policy: %w", err)
+	}
+	return sql.NullString{String: string(data), Valid: true}, nil
+}
+
+// GetEnvironmentPolicy returns the environment's stored security policy, or
+// the default for its type when none is stored
+func (m *SQLiteMemoryDB) GetEnvironmentPolicy(ctx context.Context, envID string) (*SecurityPolicy, error) {
+	return environmentPolicy(ctx, m.db.QueryRowContext, envID)
+}
+
+// environmentPolicy loads an environment's effective policy with queryRow,
+// so it can run inside a transaction
+func environmentPolicy(ctx context.Context, queryRow func(context.Context, string, ...interface{}) *sql.Row, envID string) (*SecurityPolicy, error) {
+	var envType string
+	var raw sql.NullString
+	err := queryRow(ctx, `SELECT env_type, security_policy FROM environments WHERE id = ?`, envID).Scan(&envType, &raw)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("%w: %s", ErrEnvironmentNotFound, envID)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get security policy: %w", err)
+	}
+
+	policy, err := decodeSecurityPolicy(raw)
+	if err != nil {
+		return nil, err
+	}
+	if policy == nil {
+		defaults := DefaultSecurityPolicy(envType)
+		policy = &defaults
+	}
+	return policy, nil
+}
+
+// SetEnvironmentPolicy stores the environment's security policy
+func (m *SQLiteMemoryDB) SetEnvironmentPolicy(ctx context.Context, envID string, policy SecurityPolicy) error {
+	if err := policy.Validate(); err != nil {
+		return err
+	}
+	raw, err := encodeSecurityPolicy(&policy)
+	if err != nil {
+		return err
+	}
+
+	result, err := m.db.ExecContext(ctx, `UPDATE environments SET security_policy = ? WHERE id = ?`, raw, envID)
+	if err != nil {
+		return fmt.Errorf("failed to set security policy: %w", err)
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return fmt.Errorf("%w: %s", ErrEnvironmentNotFound, envID)
+	}
+	return nil
+}
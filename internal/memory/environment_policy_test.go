@@ -0,0 +1,92 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestEnvironmentPolicy(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+	sqliteDB := db.(*SQLiteMemoryDB)
+	ctx := context.Background()
+
+	sqliteDB.RegisterEnvironment(ctx, &Environment{ID: "acme", Name: "Acme", EnvType: "customer"})
+	sqliteDB.RegisterEnvironment(ctx, &Environment{ID: "ours", Name: "Ours", EnvType: "internal"})
+
+	policy, err := sqliteDB.GetEnvironmentPolicy(ctx, "acme")
+	if err != nil {
+		t.Fatalf("GetEnvironmentPolicy failed: %v", err)
+	}
+	if policy.AllowNetworkScan || policy.AllowFileSystemWrite {
+		t.Errorf("Expected restrictive customer default, got %+v", policy)
+	}
+	if policy, _ := sqliteDB.GetEnvironmentPolicy(ctx, "ours"); !policy.AllowNetworkScan || !policy.AllowFileSystemWrite {
+		t.Errorf("Expected permissive internal default, got %+v", policy)
+	}
+
+	want := SecurityPolicy{AllowNetworkScan: true, MaxFindingSeverity: "medium"}
+	if err := sqliteDB.SetEnvironmentPolicy(ctx, "acme", want); err != nil {
+		t.Fatalf("SetEnvironmentPolicy failed: %v", err)
+	}
+	if policy, _ := sqliteDB.GetEnvironmentPolicy(ctx, "acme"); *policy != want {
+		t.Errorf("GetEnvironmentPolicy = %+v, want %+v", policy, want)
+	}
+
+	// Re-registering without a policy, as Captain does before each scan, keeps it
+	sqliteDB.RegisterEnvironment(ctx, &Environment{ID: "acme", Name: "Acme Corp", EnvType: "customer"})
+	env, err := sqliteDB.GetEnvironment(ctx, "acme")
+	if err != nil {
+		t.Fatalf("GetEnvironment failed: %v", err)
+	}
+	if env.SecurityPolicy == nil || *env.SecurityPolicy != want {
+		t.Errorf("Expected policy to survive re-registration, got %+v", env.SecurityPolicy)
+	}
+
+	if err := sqliteDB.SetEnvironmentPolicy(ctx, "acme", SecurityPolicy{MaxFindingSeverity: "severe"}); !errors.Is(err, ErrInvalidSecurityPolicy) {
+		t.Errorf("Expected ErrInvalidSecurityPolicy, got %v", err)
+	}
+	if err := sqliteDB.SetEnvironmentPolicy(ctx, "missing", want); !errors.Is(err, ErrEnvironmentNotFound) {
+		t.Errorf("Expected ErrEnvironmentNotFound on set, got %v", err)
+	}
+	if _, err := sqliteDB.GetEnvironmentPolicy(ctx, "missing"); !errors.Is(err, ErrEnvironmentNotFound) {
+		t.Errorf("Expected ErrEnvironmentNotFound on get, got %v", err)
+	}
+}
+
+func TestSaveFindings_MaxFindingSeverity(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+	sqliteDB := db.(*SQLiteMemoryDB)
+	ctx := context.Background()
+
+	sqliteDB.RegisterEnvironment(ctx, &Environment{ID: "acme", Name: "Acme", EnvType: "customer",
+		SecurityPolicy: &SecurityPolicy{MaxFindingSeverity: "medium"}})
+	sqliteDB.RecordScan(ctx, &ReconScan{ID: "scan-1", EnvID: "acme", AgentID: "Snake001", ScanType: "targeted", StartedAt: time.Now(), Status: "completed"})
+
+	findings := []*ReconFinding{
+		{ID: "F-CRIT", ScanID: "scan-1", EnvID: "acme", FindingType: "security", Severity: "critical", Title: "c", Description: "d", Status: "open"},
+		{ID: "F-HIGH", ScanID: "scan-1", EnvID: "acme", FindingType: "security", Severity: "high", Title: "h", Description: "d", Status: "open"},
+		{ID: "F-MED", ScanID: "scan-1", EnvID: "acme", FindingType: "security", Severity: "medium", Title: "m", Description: "d", Status: "open"},
+		{ID: "F-LOW", ScanID: "scan-1", EnvID: "acme", FindingType: "security", Severity: "low", Title: "l", Description: "d", Status: "open"},
+	}
+	if err := sqliteDB.SaveFindings(ctx, findings, DefaultDuplicateStrategy); err != nil {
+		t.Fatalf("SaveFindings failed: %v", err)
+	}
+
+	want := map[string]string{"F-CRIT": "ignored", "F-HIGH": "ignored", "F-MED": "open", "F-LOW": "open"}
+	for id, status := range want {
+		finding, err := sqliteDB.GetFinding(ctx, id)
+		if err != nil {
+			t.Fatalf("GetFinding(%s) failed: %v", id, err)
+		}
+		if finding.Status != status {
+			t.Errorf("%s status = %s, want %s", id, finding.Status, status)
+		}
+	}
+	if findings[0].Status != "open" {
+		t.Error("SaveFindings should not modify the caller's findings")
+	}
+}
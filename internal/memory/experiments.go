@@ -0,0 +1,403 @@
+package memory
+
+import (
+	"database/sql"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Model experiment statuses
+const (
+	ExperimentStatusActive    = "active"
+	ExperimentStatusCompleted = "completed"
+)
+
+// ModelExperiment compares author quality scores produced by two models.
+// SampleSize is the number of scores wanted per model; once both arms reach
+// it the experiment is completed and stops recording. Zero means open-ended.
+type ModelExperiment struct {
+	ExperimentID    string     `json:"experiment_id"`
+	ControlModel    string     `json:"control_model"`
+	TreatmentModel  string     `json:"treatment_model"`
+	Status          string     `json:"status"`
+	StartedAt       time.Time  `json:"started_at"`
+	CompletedAt     *time.Time `json:"completed_at,omitempty"`
+	SampleSize      int        `json:"sample_size"`
+	ControlScores   []float64  `json:"control_scores"`
+	TreatmentScores []float64  `json:"treatment_scores"`
+}
+
+// ExperimentStats summarises a model experiment with a Welch's t-test of
+// treatment against control. TStat, DegreesOfFreedom and PValue are zero
+// until each arm has at least two scores.
+type ExperimentStats struct {
+	ControlN         int     `json:"control_n"`
+	TreatmentN       int     `json:"treatment_n"`
+	ControlMean      float64 `json:"control_mean"`
+	TreatmentMean    float64 `json:"treatment_mean"`
+	ControlStdDev    float64 `json:"control_stddev"`
+	TreatmentStdDev  float64 `json:"treatment_stddev"`
+	MeanDifference   float64 `json:"mean_difference"`
+	TStat            float64 `json:"t_stat"`
+	DegreesOfFreedom float64 `json:"degrees_of_freedom"`
+	PValue           float64 `json:"p_value"`
+	Significant      bool    `json:"significant"`
+}
+
+// ExperimentSignificanceLevel is the two-tailed p-value below which a
+// difference is reported as significant
+const ExperimentSignificanceLevel = 0.05
+
+// CreateModelExperiment starts a new active experiment, generating an ID if
+// none is set
+func (m *SQLiteMemoryDB) CreateModelExperiment(exp *ModelExperiment) error {
+	if exp.ControlModel == "" || exp.TreatmentModel == "" {
+		return fmt.Errorf("control and treatment models are required")
+	}
+	if exp.ControlModel == exp.TreatmentModel {
+		return fmt.Errorf("control and treatment models must differ")
+	}
+	if exp.SampleSize < 0 {
+		return fmt.Errorf("sample size must not be negative")
+	}
+	if exp.ExperimentID == "" {
+		exp.ExperimentID = uuid.New().String()
+	}
+	if exp.StartedAt.IsZero() {
+		exp.StartedAt = time.Now()
+	}
+	exp.Status = ExperimentStatusActive
+
+	_, err := m.db.Exec(`
+		INSERT INTO model_experiments (id, control_model, treatment_model, sample_size, status, started_at)
+		VALUES (?, ?, ?, ?, ?, ?)
+	`, exp.ExperimentID, exp.ControlModel, exp.TreatmentModel, exp.SampleSize, exp.Status, exp.StartedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create model experiment: %w", err)
+	}
+	return nil
+}
+
+// GetModelExperiment returns an experiment with its recorded scores, or nil
+// if it does not exist
+func (m *SQLiteMemoryDB) GetModelExperiment(experimentID string) (*ModelExperiment, error) {
+	var exp ModelExperiment
+	var completedAt sql.NullTime
+	err := m.db.QueryRow(`
+		SELECT id, control_model, treatment_model, sample_size, status, started_at, completed_at
+		FROM model_experiments
+		WHERE id = ?
+	`, experimentID).Scan(
+		&exp.ExperimentID, &exp.ControlModel, &exp.TreatmentModel, &exp.SampleSize,
+		&exp.Status, &exp.StartedAt, &completedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get model experiment: %w", err)
+	}
+	if completedAt.Valid {
+		exp.CompletedAt = &completedAt.Time
+	}
+
+	if err := m.loadExperimentScores(&exp); err != nil {
+		return nil, err
+	}
+	return &exp, nil
+}
+
+// GetActiveModelExperiments returns all experiments still recording scores
+func (m *SQLiteMemoryDB) GetActiveModelExperiments() ([]*ModelExperiment, error) {
+	rows, err := m.db.Query(`
+		SELECT id FROM model_experiments
+		WHERE status = ?
+		ORDER BY started_at
+	`, ExperimentStatusActive)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query active experiments: %w", err)
+	}
+
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("failed to scan experiment ID: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	experiments := make([]*ModelExperiment, 0, len(ids))
+	for _, id := range ids {
+		exp, err := m.GetModelExperiment(id)
+		if err != nil {
+			return nil, err
+		}
+		if exp != nil {
+			experiments = append(experiments, exp)
+		}
+	}
+	return experiments, nil
+}
+
+// RecordExperimentResult adds a quality score for one arm of an active
+// experiment. model must be the experiment's control or treatment model.
+// The experiment is completed once both arms reach its sample size.
+func (m *SQLiteMemoryDB) RecordExperimentResult(experimentID, model string, score float64) error {
+	exp, err := m.GetModelExperiment(experimentID)
+	if err != nil {
+		return err
+	}
+	if exp == nil {
+		return fmt.Errorf("model experiment %s not found", experimentID)
+	}
+	if exp.Status != ExperimentStatusActive {
+		return fmt.Errorf("model experiment %s is %s", experimentID, exp.Status)
+	}
+	if model != exp.ControlModel && model != exp.TreatmentModel {
+		return fmt.Errorf("model %s is not part of experiment %s", model, experimentID)
+	}
+
+	if _, err := m.db.Exec(`
+		INSERT INTO model_experiment_results (experiment_id, model, score)
+		VALUES (?, ?, ?)
+	`, experimentID, model, score); err != nil {
+		return fmt.Errorf("failed to record experiment result: %w", err)
+	}
+
+	if model == exp.ControlModel {
+		exp.ControlScores = append(exp.ControlScores, score)
+	} else {
+		exp.TreatmentScores = append(exp.TreatmentScores, score)
+	}
+	if exp.SampleSize > 0 && len(exp.ControlScores) >= exp.SampleSize && len(exp.TreatmentScores) >= exp.SampleSize {
+		if _, err := m.db.Exec(`
+			UPDATE model_experiments SET status = ?, completed_at = CURRENT_TIMESTAMP
+			WHERE id = ?
+		`, ExperimentStatusCompleted, experimentID); err != nil {
+			return fmt.Errorf("failed to complete experiment: %w", err)
+		}
+	}
+	return nil
+}
+
+// loadExperimentScores fills both score slices from model_experiment_results
+func (m *SQLiteMemoryDB) loadExperimentScores(exp *ModelExperiment) error {
+	rows, err := m.db.Query(`
+		SELECT model, score FROM model_experiment_results
+		WHERE experiment_id = ?
+		ORDER BY id
+	`, exp.ExperimentID)
+	if err != nil {
+		return fmt.Errorf("failed to query experiment results: %w", err)
+	}
+	defer rows.Close()
+
+	exp.ControlScores = []float64{}
+	exp.TreatmentScores = []float64{}
+	for rows.Next() {
+		var model string
+		var score float64
+		if err := rows.Scan(&model, &score); err != nil {
+			return fmt.Errorf("failed to scan experiment result: %w", err)
+		}
+		switch model {
+		case exp.ControlModel:
+			exp.ControlScores = append(exp.ControlScores, score)
+		case exp.TreatmentModel:
+			exp.TreatmentScores = append(exp.TreatmentScores, score)
+		}
+	}
+	return rows.Err()
+}
+
+// recordAuthorExperimentScores records an author's quality score in every
+// active experiment that includes the author's model
+func (m *SQLiteMemoryDB) recordAuthorExperimentScores(authorID string, score float64) error {
+	model, err := m.agentModel(authorID)
+	if err != nil || model == "" {
+		return err
+	}
+
+	experiments, err := m.GetActiveModelExperiments()
+	if err != nil {
+		return err
+	}
+	for _, exp := range experiments {
+		if model != exp.ControlModel && model != exp.TreatmentModel {
+			continue
+		}
+		if err := m.RecordExperimentResult(exp.ExperimentID, model, score); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// agentModel returns the model an agent ran on, preferring its most recent
+// metrics_history entry, which the live agent reports, and falling back to
+// agent_control, which keeps the model it was registered with. It returns ""
+// when the model is unknown.
+func (m *SQLiteMemoryDB) agentModel(agentID string) (string, error) {
+	var model sql.NullString
+	err := m.db.QueryRow(`
+		SELECT model FROM metrics_history
+		WHERE agent_id = ?
+		ORDER BY recorded_at DESC, id DESC
+		LIMIT 1
+	`, agentID).Scan(&model)
+	if err != nil && err != sql.ErrNoRows {
+		return "", fmt.Errorf("failed to get agent model from metrics: %w", err)
+	}
+	if model.Valid && model.String != "" {
+		return model.String, nil
+	}
+
+	err = m.db.QueryRow(`SELECT model FROM agent_control WHERE agent_id = ?`, agentID).Scan(&model)
+	if err == sql.ErrNoRows {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to get agent model: %w", err)
+	}
+	return model.String, nil
+}
+
+// CalculateExperimentStats runs a two-tailed Welch's t-test of the
+// experiment's treatment scores against its control scores
+func CalculateExperimentStats(exp *ModelExperiment) *ExperimentStats {
+	stats := &ExperimentStats{
+		ControlN:   len(exp.ControlScores),
+		TreatmentN: len(exp.TreatmentScores),
+	}
+	var controlVar, treatmentVar float64
+	stats.ControlMean, controlVar = meanVariance(exp.ControlScores)
+	stats.TreatmentMean, treatmentVar = meanVariance(exp.TreatmentScores)
+	stats.ControlStdDev = math.Sqrt(controlVar)
+	stats.TreatmentStdDev = math.Sqrt(treatmentVar)
+	stats.MeanDifference = stats.TreatmentMean - stats.ControlMean
+
+	if stats.ControlN < 2 || stats.TreatmentN < 2 {
+		return stats
+	}
+
+	seC := controlVar / float64(stats.ControlN)
+	seT := treatmentVar / float64(stats.TreatmentN)
+	se := seC + seT
+	if se == 0 {
+		// Identical constant scores in both arms carry no evidence of a difference
+		if stats.MeanDifference == 0 {
+			stats.PValue = 1
+		}
+		return stats
+	}
+
+	stats.TStat = stats.MeanDifference / math.Sqrt(se)
+	stats.DegreesOfFreedom = se * se /
+		(seC*seC/float64(stats.ControlN-1) + seT*seT/float64(stats.TreatmentN-1))
+	stats.PValue = studentTTwoTailed(stats.TStat, stats.DegreesOfFreedom)
+	stats.Significant = stats.PValue < ExperimentSignificanceLevel
+	return stats
+}
+
+// meanVariance returns the mean and sample variance of values
+func meanVariance(values []float64) (mean, variance float64) {
+	if len(values) == 0 {
+		return 0, 0
+	}
+	for _, v := range values {
+		mean += v
+	}
+	mean /= float64(len(values))
+	if len(values) < 2 {
+		return mean, 0
+	}
+	for _, v := range values {
+		variance += (v - mean) * (v - mean)
+	}
+	return mean, variance / float64(len(values)-1)
+}
+
+// studentTTwoTailed returns P(|T| >= |t|) for Student's t with df degrees of
+// freedom, via the regularized incomplete beta function
+func studentTTwoTailed(t, df float64) float64 {
+	return regularizedIncompleteBeta(df/2, 0.5, df/(df+t*t))
+}
+
+// regularizedIncompleteBeta evaluates I_x(a, b) using the continued fraction
+// expansion (Numerical Recipes, betai/betacf)
+func regularizedIncompleteBeta(a, b, x float64) float64 {
+	if x <= 0 {
+		return 0
+	}
+	if x >= 1 {
+		return 1
+	}
+	lga, _ := math.Lgamma(a)
+	lgb, _ := math.Lgamma(b)
+	lgab, _ := math.Lgamma(a + b)
+	front := math.Exp(lgab - lga - lgb + a*math.Log(x) + b*math.Log(1-x))
+	if x < (a+1)/(a+b+2) {
+		return front * betaContinuedFraction(a, b, x) / a
+	}
+	return 1 - front*betaContinuedFraction(b, a, 1-x)/b
+}
+
+// betaContinuedFraction evaluates the continued fraction for the incomplete
+// beta function with the modified Lentz method
+func betaContinuedFraction(a, b, x float64) float64 {
+	const (
+		maxIterations = 200
+		epsilon       = 1e-12
+		tiny          = 1e-300
+	)
+
+	c := 1.0
+	d := 1 - (a+b)*x/(a+1)
+	if math.Abs(d) < tiny {
+		d = tiny
+	}
+	d = 1 / d
+	h := d
+	for i := 1; i <= maxIterations; i++ {
+		m := float64(i)
+		m2 := 2 * m
+
+		num := m * (b - m) * x / ((a + m2 - 1) * (a + m2))
+		d = 1 + num*d
+		if math.Abs(d) < tiny {
+			d = tiny
+		}
+		c = 1 + num/c
+		if math.Abs(c) < tiny {
+			c = tiny
+		}
+		d = 1 / d
+		h *= d * c
+
+		num = -(a + m) * (a + b + m) * x / ((a + m2) * (a + m2 + 1))
+		d = 1 + num*d
+		if math.Abs(d) < tiny {
+			d = tiny
+		}
+		c = 1 + num/c
+		if math.Abs(c) < tiny {
+			c = tiny
+		}
+		d = 1 / d
+		delta := d * c
+		h *= delta
+		if math.Abs(delta-1) < epsilon {
+			break
+		}
+	}
+	return h
+}
@@ -0,0 +1,183 @@
+package memory
+
+import (
+	"math"
+	"testing"
+)
+
+func TestModelExperimentLifecycle(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	exp := &ModelExperiment{ControlModel: "claude-sonnet-4-5", TreatmentModel: "claude-haiku", SampleSize: 2}
+	if err := db.CreateModelExperiment(exp); err != nil {
+		t.Fatalf("CreateModelExperiment failed: %v", err)
+	}
+	if exp.ExperimentID == "" || exp.Status != ExperimentStatusActive {
+		t.Fatalf("Expected generated ID and active status, got %+v", exp)
+	}
+
+	if err := db.RecordExperimentResult(exp.ExperimentID, "claude-opus", 50); err == nil {
+		t.Error("Expected error recording a model outside the experiment")
+	}
+
+	for _, r := range []struct {
+		model string
+		score float64
+	}{
+		{"claude-sonnet-4-5", 80}, {"claude-haiku", 70}, {"claude-sonnet-4-5", 90}, {"claude-haiku", 60},
+	} {
+		if err := db.RecordExperimentResult(exp.ExperimentID, r.model, r.score); err != nil {
+			t.Fatalf("RecordExperimentResult failed: %v", err)
+		}
+	}
+
+	got, err := db.GetModelExperiment(exp.ExperimentID)
+	if err != nil {
+		t.Fatalf("GetModelExperiment failed: %v", err)
+	}
+	if len(got.ControlScores) != 2 || len(got.TreatmentScores) != 2 {
+		t.Fatalf("Expected 2 scores per arm, got %v / %v", got.ControlScores, got.TreatmentScores)
+	}
+	if got.Status != ExperimentStatusCompleted || got.CompletedAt == nil {
+		t.Errorf("Expected experiment completed after reaching sample size, got %s", got.Status)
+	}
+	if err := db.RecordExperimentResult(exp.ExperimentID, "claude-haiku", 65); err == nil {
+		t.Error("Expected error recording into a completed experiment")
+	}
+
+	missing, err := db.GetModelExperiment("does-not-exist")
+	if err != nil || missing != nil {
+		t.Errorf("Expected nil experiment for unknown ID, got %v, %v", missing, err)
+	}
+}
+
+func TestRecordAuthorExperimentScores(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+	sqlDB := db.(*SQLiteMemoryDB)
+
+	exp := &ModelExperiment{ControlModel: "claude-sonnet-4-5", TreatmentModel: "claude-haiku"}
+	if err := db.CreateModelExperiment(exp); err != nil {
+		t.Fatalf("CreateModelExperiment failed: %v", err)
+	}
+	if err := db.RecordMetricsHistory("agent-haiku", "claude-haiku", 1000, 0.01, ""); err != nil {
+		t.Fatalf("RecordMetricsHistory failed: %v", err)
+	}
+
+	if err := sqlDB.recordAuthorExperimentScores("agent-haiku", 72.5); err != nil {
+		t.Fatalf("recordAuthorExperimentScores failed: %v", err)
+	}
+	// Agents with no known model are skipped
+	if err := sqlDB.recordAuthorExperimentScores("agent-unknown", 10); err != nil {
+		t.Fatalf("recordAuthorExperimentScores failed for unknown agent: %v", err)
+	}
+
+	got, err := db.GetModelExperiment(exp.ExperimentID)
+	if err != nil {
+		t.Fatalf("GetModelExperiment failed: %v", err)
+	}
+	if len(got.ControlScores) != 0 || len(got.TreatmentScores) != 1 || got.TreatmentScores[0] != 72.5 {
+		t.Errorf("Expected one treatment score of 72.5, got %v / %v", got.ControlScores, got.TreatmentScores)
+	}
+}
+
+func TestUpdateQualityScoresRecordsPerReviewScore(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	exp := &ModelExperiment{ControlModel: "claude-sonnet-4-5", TreatmentModel: "claude-haiku"}
+	if err := db.CreateModelExperiment(exp); err != nil {
+		t.Fatalf("CreateModelExperiment failed: %v", err)
+	}
+	if err := db.RecordMetricsHistory("agent-haiku", "claude-haiku", 1000, 0.01, ""); err != nil {
+		t.Fatalf("RecordMetricsHistory failed: %v", err)
+	}
+	assignment := &TaskAssignment{TaskID: "TASK-1", AssignedTo: "agent-haiku", AssignedBy: "captain", AssignmentType: "review", Status: "in_progress"}
+	if err := db.CreateAssignment(assignment); err != nil {
+		t.Fatalf("CreateAssignment failed: %v", err)
+	}
+	board := &ReviewBoard{AssignmentID: assignment.ID, ReviewerCount: 1, Status: "in_progress", RiskLevel: "low"}
+	if err := db.CreateReviewBoard(board); err != nil {
+		t.Fatalf("CreateReviewBoard failed: %v", err)
+	}
+
+	// Each review contributes its own score, not the author's running average
+	for _, consensus := range []*ConsensusResult{{Approved: true}, {Approved: false, TotalDefects: 2}} {
+		if err := db.UpdateQualityScoresAfterReview(board.ID, consensus); err != nil {
+			t.Fatalf("UpdateQualityScoresAfterReview failed: %v", err)
+		}
+	}
+
+	got, err := db.GetModelExperiment(exp.ExperimentID)
+	if err != nil {
+		t.Fatalf("GetModelExperiment failed: %v", err)
+	}
+	if len(got.TreatmentScores) != 2 || got.TreatmentScores[0] != 100 || got.TreatmentScores[1] != 24 {
+		t.Errorf("Expected treatment scores [100 24], got %v", got.TreatmentScores)
+	}
+}
+
+func TestAgentModelPrefersMetrics(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+	sqlDB := db.(*SQLiteMemoryDB)
+
+	if _, err := sqlDB.db.Exec(`INSERT INTO agent_control (agent_id, config_name, model) VALUES (?, ?, ?)`,
+		"agent-1", "SNTGreen", "claude-sonnet-4-5"); err != nil {
+		t.Fatalf("Failed to insert agent_control row: %v", err)
+	}
+	if model, err := sqlDB.agentModel("agent-1"); err != nil || model != "claude-sonnet-4-5" {
+		t.Errorf("Expected agent_control model without metrics, got %q, %v", model, err)
+	}
+
+	if err := db.RecordMetricsHistory("agent-1", "claude-haiku", 1000, 0.01, ""); err != nil {
+		t.Fatalf("RecordMetricsHistory failed: %v", err)
+	}
+	if model, err := sqlDB.agentModel("agent-1"); err != nil || model != "claude-haiku" {
+		t.Errorf("Expected the reported model to win, got %q, %v", model, err)
+	}
+}
+
+func TestReviewQualityScore(t *testing.T) {
+	tests := []struct {
+		consensus ConsensusResult
+		want      float64
+	}{
+		{ConsensusResult{Approved: true}, 100},
+		{ConsensusResult{Approved: true, TotalDefects: 5}, 85},
+		{ConsensusResult{Approved: false, TotalDefects: 2}, 24},
+		{ConsensusResult{Approved: false, TotalDefects: 20}, 0},
+	}
+	for _, tt := range tests {
+		if got := reviewQualityScore(&tt.consensus); math.Abs(got-tt.want) > 1e-9 {
+			t.Errorf("reviewQualityScore(%+v) = %v, want %v", tt.consensus, got, tt.want)
+		}
+	}
+}
+
+func TestCalculateExperimentStats(t *testing.T) {
+	stats := CalculateExperimentStats(&ModelExperiment{
+		ControlScores:   []float64{1, 2, 3, 4, 5},
+		TreatmentScores: []float64{3, 4, 5, 6, 7},
+	})
+
+	if stats.ControlMean != 3 || stats.TreatmentMean != 5 || stats.MeanDifference != 2 {
+		t.Errorf("Unexpected means: %+v", stats)
+	}
+	if math.Abs(stats.TStat-2) > 1e-9 || math.Abs(stats.DegreesOfFreedom-8) > 1e-9 {
+		t.Errorf("Expected t=2, df=8, got t=%f, df=%f", stats.TStat, stats.DegreesOfFreedom)
+	}
+	// Two-tailed p for t=2 with 8 degrees of freedom
+	if math.Abs(stats.PValue-0.0805) > 1e-3 {
+		t.Errorf("Expected p ~0.0805, got %f", stats.PValue)
+	}
+	if stats.Significant {
+		t.Error("Expected difference not to be significant at 0.05")
+	}
+
+	small := CalculateExperimentStats(&ModelExperiment{ControlScores: []float64{1}, TreatmentScores: []float64{2, 3}})
+	if small.TStat != 0 || small.PValue != 0 {
+		t.Errorf("Expected no test with a single control score, got %+v", small)
+	}
+}
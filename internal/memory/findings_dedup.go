@@ -0,0 +1,114 @@
+package memory
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// DuplicateStrategy decides what SaveFindings does when a finding's ID is
+// already stored, e.g. when a rescan reports the same issue again
+type DuplicateStrategy string
+
+const (
+	// DuplicateIgnore keeps the stored finding unchanged
+	DuplicateIgnore DuplicateStrategy = "ignore"
+	// DuplicateUpdateSeverity raises the stored severity when the new one is
+	// higher and leaves everything else as is
+	DuplicateUpdateSeverity DuplicateStrategy = "update_severity"
+	// DuplicateMergeRecommendations appends the new recommendation to the
+	// stored one, separated by RecommendationSeparator, unless it is already
+	// there
+	DuplicateMergeRecommendations DuplicateStrategy = "merge_recommendations"
+	// DuplicateReplaceAll overwrites every field with the new finding
+	DuplicateReplaceAll DuplicateStrategy = "replace_all"
+)
+
+// DefaultDuplicateStrategy is used when no strategy is stored in context
+const DefaultDuplicateStrategy = DuplicateIgnore
+
+// ReconDuplicateStrategyKey is the captain_context key holding the
+// DuplicateStrategy recon reports are saved with
+const ReconDuplicateStrategyKey = "recon_duplicate_strategy"
+
+// RecommendationSeparator joins recommendations merged by
+// DuplicateMergeRecommendations
+const RecommendationSeparator = "\n\n---\n\n"
+
+// ErrUnknownDuplicateStrategy is returned for a strategy name that is not
+// one of the DuplicateStrategy constants
+var ErrUnknownDuplicateStrategy = errors.New("unknown duplicate strategy")
+
+// ParseDuplicateStrategy validates a strategy name
+func ParseDuplicateStrategy(s string) (DuplicateStrategy, error) {
+	switch strategy := DuplicateStrategy(strings.ToLower(strings.TrimSpace(s))); strategy {
+	case DuplicateIgnore, DuplicateUpdateSeverity, DuplicateMergeRecommendations, DuplicateReplaceAll:
+		return strategy, nil
+	}
+	return "", fmt.Errorf("%w: %q", ErrUnknownDuplicateStrategy, s)
+}
+
+// ReconDuplicateStrategy reads the strategy stored under
+// ReconDuplicateStrategyKey. It returns DefaultDuplicateStrategy when none is
+// stored, and DefaultDuplicateStrategy with an error when the stored value
+// is invalid.
+func ReconDuplicateStrategy(db MemoryDB) (DuplicateStrategy, error) {
+	if db == nil {
+		return DefaultDuplicateStrategy, nil
+	}
+	stored, err := db.GetContext(ReconDuplicateStrategyKey)
+	if err != nil {
+		return DefaultDuplicateStrategy, err
+	}
+	if stored == nil || strings.TrimSpace(stored.Value) == "" {
+		return DefaultDuplicateStrategy, nil
+	}
+	strategy, err := ParseDuplicateStrategy(stored.Value)
+	if err != nil {
+		return DefaultDuplicateStrategy, err
+	}
+	return strategy, nil
+}
+
+// severityRankSQL ranks a severity column for comparison; lower is more
+// severe and unknown severities rank last
+func severityRankSQL(column string) string {
+	return fmt.Sprintf(`CASE %s WHEN 'critical' THEN 0 WHEN 'high' THEN 1 WHEN 'medium' THEN 2 WHEN 'low' THEN 3 WHEN 'info' THEN 4 ELSE 5 END`, column)
+}
+
+// conflictClause returns the ON CONFLICT clause of the recon_findings insert
+// that applies the strategy
+func (s DuplicateStrategy) conflictClause() (string, error) {
+	switch s {
+	case DuplicateIgnore:
+		return `ON CONFLICT(id) DO NOTHING`, nil
+	case DuplicateUpdateSeverity:
+		return fmt.Sprintf(`ON CONFLICT(id) DO UPDATE SET
+				severity = excluded.severity,
+				updated_at = CURRENT_TIMESTAMP
+			WHERE %s < %s`, severityRankSQL("excluded.severity"), severityRankSQL("recon_findings.severity")), nil
+	case DuplicateMergeRecommendations:
+		return fmt.Sprintf(`ON CONFLICT(id) DO UPDATE SET
+				recommendation = CASE
+					WHEN COALESCE(recon_findings.recommendation, '') = '' THEN excluded.recommendation
+					ELSE recon_findings.recommendation || '%s' || excluded.recommendation
+				END,
+				updated_at = CURRENT_TIMESTAMP
+			WHERE COALESCE(excluded.recommendation, '') != ''
+				AND instr(COALESCE(recon_findings.recommendation, ''), excluded.recommendation) = 0`, RecommendationSeparator), nil
+	case DuplicateReplaceAll:
+		return `ON CONFLICT(id) DO UPDATE SET
+				scan_id = excluded.scan_id,
+				env_id = excluded.env_id,
+				finding_type = excluded.finding_type,
+				severity = excluded.severity,
+				title = excluded.title,
+				description = excluded.description,
+				location = excluded.location,
+				recommendation = excluded.recommendation,
+				status = excluded.status,
+				metadata = excluded.metadata,
+				updated_at = CURRENT_TIMESTAMP`, nil
+	}
+	return "", fmt.Errorf("%w: %q", ErrUnknownDuplicateStrategy, string(s))
+}
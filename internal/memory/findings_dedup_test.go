@@ -0,0 +1,132 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestSaveFindings_DuplicateStrategies(t *testing.T) {
+	stored := ReconFinding{ID: "VULN-001", ScanID: "scan-1", EnvID: "env-dedup", FindingType: "security", Severity: "medium",
+		Title: "Hardcoded secret", Description: "API key in source", Location: "cmd/main.go:12", Recommendation: "Rotate the key", Status: "open"}
+
+	tests := []struct {
+		name           string
+		strategy       DuplicateStrategy
+		dup            func(f ReconFinding) ReconFinding
+		severity       string
+		title          string
+		recommendation string
+		scanID         string
+	}{
+		{
+			name:     "ignore keeps the stored finding",
+			strategy: DuplicateIgnore,
+			dup: func(f ReconFinding) ReconFinding {
+				f.ScanID, f.Severity, f.Title, f.Recommendation = "scan-2", "critical", "Secret in repo", "Use a vault"
+				return f
+			},
+			severity: "medium", title: "Hardcoded secret", recommendation: "Rotate the key", scanID: "scan-1",
+		},
+		{
+			name:     "update severity raises it",
+			strategy: DuplicateUpdateSeverity,
+			dup: func(f ReconFinding) ReconFinding {
+				f.Severity, f.Title = "critical", "Secret in repo"
+				return f
+			},
+			severity: "critical", title: "Hardcoded secret", recommendation: "Rotate the key", scanID: "scan-1",
+		},
+		{
+			name:     "update severity never lowers it",
+			strategy: DuplicateUpdateSeverity,
+			dup: func(f ReconFinding) ReconFinding {
+				f.Severity = "low"
+				return f
+			},
+			severity: "medium", title: "Hardcoded secret", recommendation: "Rotate the key", scanID: "scan-1",
+		},
+		{
+			name:     "merge recommendations appends new advice",
+			strategy: DuplicateMergeRecommendations,
+			dup: func(f ReconFinding) ReconFinding {
+				f.Severity, f.Recommendation = "high", "Use a vault"
+				return f
+			},
+			severity: "medium", title: "Hardcoded secret", recommendation: "Rotate the key" + RecommendationSeparator + "Use a vault", scanID: "scan-1",
+		},
+		{
+			name:     "merge recommendations skips repeated advice",
+			strategy: DuplicateMergeRecommendations,
+			dup: func(f ReconFinding) ReconFinding {
+				return f
+			},
+			severity: "medium", title: "Hardcoded secret", recommendation: "Rotate the key", scanID: "scan-1",
+		},
+		{
+			name:     "replace all overwrites every field",
+			strategy: DuplicateReplaceAll,
+			dup: func(f ReconFinding) ReconFinding {
+				f.ScanID, f.Severity, f.Title, f.Recommendation = "scan-2", "low", "Secret in repo", "Use a vault"
+				return f
+			},
+			severity: "low", title: "Secret in repo", recommendation: "Use a vault", scanID: "scan-2",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, cleanup := setupTestDB(t)
+			defer cleanup()
+			sqliteDB := db.(*SQLiteMemoryDB)
+			ctx := context.Background()
+
+			sqliteDB.RegisterEnvironment(ctx, &Environment{ID: "env-dedup", Name: "Dedup", EnvType: "test"})
+			for _, scanID := range []string{"scan-1", "scan-2"} {
+				sqliteDB.RecordScan(ctx, &ReconScan{ID: scanID, EnvID: "env-dedup", AgentID: "Snake001", ScanType: "initial", StartedAt: time.Now(), Status: "completed"})
+			}
+
+			original := stored
+			if err := sqliteDB.SaveFindings(ctx, []*ReconFinding{&original}, tt.strategy); err != nil {
+				t.Fatalf("SaveFindings failed: %v", err)
+			}
+			dup := tt.dup(stored)
+			if err := sqliteDB.SaveFindings(ctx, []*ReconFinding{&dup}, tt.strategy); err != nil {
+				t.Fatalf("SaveFindings of duplicate failed: %v", err)
+			}
+
+			got, err := sqliteDB.GetFinding(ctx, "VULN-001")
+			if err != nil {
+				t.Fatalf("GetFinding failed: %v", err)
+			}
+			if got.Severity != tt.severity || got.Title != tt.title || got.Recommendation != tt.recommendation || got.ScanID != tt.scanID {
+				t.Errorf("got severity=%q title=%q recommendation=%q scan=%q; want %q %q %q %q",
+					got.Severity, got.Title, got.Recommendation, got.ScanID, tt.severity, tt.title, tt.recommendation, tt.scanID)
+			}
+		})
+	}
+}
+
+func TestReconDuplicateStrategy(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	if got, err := ReconDuplicateStrategy(db); err != nil || got != DefaultDuplicateStrategy {
+		t.Errorf("unset strategy = %q, %v; want %q", got, err, DefaultDuplicateStrategy)
+	}
+
+	db.SetContext(ReconDuplicateStrategyKey, "Merge_Recommendations", 5, 0)
+	if got, err := ReconDuplicateStrategy(db); err != nil || got != DuplicateMergeRecommendations {
+		t.Errorf("stored strategy = %q, %v; want %q", got, err, DuplicateMergeRecommendations)
+	}
+
+	db.SetContext(ReconDuplicateStrategyKey, "newest_wins", 5, 0)
+	if got, err := ReconDuplicateStrategy(db); !errors.Is(err, ErrUnknownDuplicateStrategy) || got != DefaultDuplicateStrategy {
+		t.Errorf("invalid strategy = %q, %v; want default and ErrUnknownDuplicateStrategy", got, err)
+	}
+
+	if err := db.(*SQLiteMemoryDB).SaveFindings(context.Background(), nil, "newest_wins"); !errors.Is(err, ErrUnknownDuplicateStrategy) {
+		t.Errorf("SaveFindings with unknown strategy = %v", err)
+	}
+}
@@ -0,0 +1,252 @@
+package memory
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Finding export formats accepted by ExportFindings
+const (
+	ExportFormatCSV   = "csv"
+	ExportFormatSARIF = "sarif"
+)
+
+// Content types returned by ExportFindings
+const (
+	ContentTypeCSV   = "text/csv; charset=utf-8"
+	ContentTypeSARIF = "application/sarif+json"
+)
+
+// SARIFVersion and SARIFSchema identify the SARIF document ExportFindings
+// produces
+const (
+	SARIFVersion = "2.1.0"
+	SARIFSchema  = "https://json.schemastore.org/sarif-2.1.0.json"
+)
+
+// sarifToolName names the recon scanner in exported SARIF runs
+const sarifToolName = "CLIAIMONITOR Recon"
+
+// ErrUnsupportedExportFormat is returned by ExportFindings for a format
+// other than csv or sarif
+var ErrUnsupportedExportFormat = errors.New("unsupported export format")
+
+// FindingsCSVHeader is the header row of a CSV findings export, one column
+// per ReconFinding field
+var FindingsCSVHeader = []string{
+	"id", "scan_id", "env_id", "finding_type", "severity", "title", "description",
+	"location", "recommendation", "status", "resolved_at", "resolved_by",
+	"resolution_notes", "metadata", "discovered_at", "updated_at",
+}
+
+// ExportFindings renders every finding of a recon environment as csv or
+// sarif, returning the document and its content type
+func (m *SQLiteMemoryDB) ExportFindings(ctx context.Context, envID string, format string) ([]byte, string, error) {
+	format = strings.ToLower(format)
+	if format != ExportFormatCSV && format != ExportFormatSARIF {
+		return nil, "", fmt.Errorf("%w: %q (want csv or sarif)", ErrUnsupportedExportFormat, format)
+	}
+	if _, err := m.GetEnvironment(ctx, envID); err != nil {
+		return nil, "", err
+	}
+
+	findings, err := m.GetFindingsByEnvironment(ctx, envID)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if format == ExportFormatCSV {
+		data, err := findingsCSV(findings)
+		return data, ContentTypeCSV, err
+	}
+	data, err := findingsSARIF(findings)
+	return data, ContentTypeSARIF, err
+}
+
+// findingsCSV writes findings under FindingsCSVHeader. Times are RFC 3339
+// and metadata is JSON.
+func findingsCSV(findings []*ReconFinding) ([]byte, error) {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	if err := w.Write(FindingsCSVHeader); err != nil {
+		return nil, err
+	}
+
+	formatTime := func(t *time.Time) string {
+		if t == nil || t.IsZero() {
+			return ""
+		}
+		return t.UTC().Format(time.RFC3339)
+	}
+	for _, f := range findings {
+		metadata := ""
+		if len(f.Metadata) > 0 {
+			data, err := json.Marshal(f.Metadata)
+			if err != nil {
+				return nil, fmt.Errorf("failed to encode metadata of finding %s: %w", f.ID, err)
+			}
+			metadata = string(data)
+		}
+		record := []string{
+			f.ID, f.ScanID, f.EnvID, f.FindingType, f.Severity, f.Title, f.Description,
+			f.Location, f.Recommendation, f.Status, formatTime(f.ResolvedAt), f.ResolvedBy,
+			f.ResolutionNotes, metadata, formatTime(&f.DiscoveredAt), formatTime(&f.UpdatedAt),
+		}
+		if err := w.Write(record); err != nil {
+			return nil, err
+		}
+	}
+
+	w.Flush()
+	return buf.Bytes(), w.Error()
+}
+
+// Minimal SARIF 2.1.0 object model: one run whose rules are finding types
+type sarifLog struct {
+	Version string     `json:"version"`
+	Schema  string     `json:"$schema"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name  string      `json:"name"`
+	Rules []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID               string       `json:"id"`
+	ShortDescription sarifMessage `json:"shortDescription"`
+}
+
+type sarifResult struct {
+	RuleID     string                 `json:"ruleId"`
+	RuleIndex  int                    `json:"ruleIndex"`
+	Level      string                 `json:"level"`
+	Message    sarifMessage           `json:"message"`
+	Locations  []sarifLocation        `json:"locations,omitempty"`
+	Properties map[string]interface{} `json:"properties,omitempty"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           *sarifRegion          `json:"region,omitempty"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+type sarifRegion struct {
+	StartLine int `json:"startLine"`
+}
+
+// findingsSARIF renders findings as a SARIF 2.1.0 log with one rule per
+// finding type
+func findingsSARIF(findings []*ReconFinding) ([]byte, error) {
+	ruleIndex := make(map[string]int)
+	for _, f := range findings {
+		ruleIndex[f.FindingType] = 0
+	}
+	ruleIDs := make([]string, 0, len(ruleIndex))
+	for id := range ruleIndex {
+		ruleIDs = append(ruleIDs, id)
+	}
+	sort.Strings(ruleIDs)
+	rules := make([]sarifRule, len(ruleIDs))
+	for i, id := range ruleIDs {
+		ruleIndex[id] = i
+		rules[i] = sarifRule{ID: id, ShortDescription: sarifMessage{Text: id + " finding"}}
+	}
+
+	results := make([]sarifResult, 0, len(findings))
+	for _, f := range findings {
+		text := f.Title
+		if f.Description != "" {
+			text += ": " + f.Description
+		}
+		result := sarifResult{
+			RuleID:    f.FindingType,
+			RuleIndex: ruleIndex[f.FindingType],
+			Level:     sarifLevel(f.Severity),
+			Message:   sarifMessage{Text: text},
+			Properties: map[string]interface{}{
+				"findingId": f.ID,
+				"scanId":    f.ScanID,
+				"severity":  f.Severity,
+				"status":    f.Status,
+			},
+		}
+		if f.Recommendation != "" {
+			result.Properties["recommendation"] = f.Recommendation
+		}
+		if loc, ok := sarifLocationFor(f.Location); ok {
+			result.Locations = []sarifLocation{loc}
+		}
+		results = append(results, result)
+	}
+
+	return json.MarshalIndent(sarifLog{
+		Version: SARIFVersion,
+		Schema:  SARIFSchema,
+		Runs: []sarifRun{{
+			Tool:    sarifTool{Driver: sarifDriver{Name: sarifToolName, Rules: rules}},
+			Results: results,
+		}},
+	}, "", "  ")
+}
+
+// sarifLevel maps a finding severity to a SARIF result level
+func sarifLevel(severity string) string {
+	switch severity {
+	case "critical", "high":
+		return "error"
+	case "medium":
+		return "warning"
+	default:
+		return "note"
+	}
+}
+
+// sarifLocationFor turns a finding location such as "src/auth/login.go:45"
+// into a SARIF location. The line is optional.
+func sarifLocationFor(location string) (sarifLocation, bool) {
+	location = strings.TrimSpace(location)
+	if location == "" {
+		return sarifLocation{}, false
+	}
+	loc := sarifLocation{}
+	path := location
+	if i := strings.LastIndex(location, ":"); i > 0 {
+		if line, err := strconv.Atoi(location[i+1:]); err == nil && line > 0 {
+			path = location[:i]
+			loc.PhysicalLocation.Region = &sarifRegion{StartLine: line}
+		}
+	}
+	loc.PhysicalLocation.ArtifactLocation.URI = strings.ReplaceAll(path, "\\", "/")
+	return loc, true
+}
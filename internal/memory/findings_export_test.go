@@ -0,0 +1,124 @@
+package memory
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// seedExportFindings registers an environment with one scan and two findings
+func seedExportFindings(t *testing.T, db *SQLiteMemoryDB) {
+	t.Helper()
+	ctx := context.Background()
+	if err := db.RegisterEnvironment(ctx, &Environment{ID: "env-export", Name: "Export", EnvType: "test"}); err != nil {
+		t.Fatalf("RegisterEnvironment failed: %v", err)
+	}
+	if err := db.RecordScan(ctx, &ReconScan{ID: "scan-export", EnvID: "env-export", AgentID: "Snake001", ScanType: "initial", StartedAt: time.Now(), Status: "completed"}); err != nil {
+		t.Fatalf("RecordScan failed: %v", err)
+	}
+	findings := []*ReconFinding{
+		{ID: "VULN-001", ScanID: "scan-export", EnvID: "env-export", FindingType: "security", Severity: "critical",
+			Title: "SQL injection", Description: "Query built with, \"string\" concatenation", Location: "internal/db/query.go:42",
+			Recommendation: "Use placeholders", Status: "open", Metadata: map[string]interface{}{"cwe": "CWE-89"}},
+		{ID: "ARCH-001", ScanID: "scan-export", EnvID: "env-export", FindingType: "architecture", Severity: "low",
+			Title: "Large package", Description: "Split it up", Location: "internal/server", Status: "open"},
+	}
+	if err := db.SaveFindings(ctx, findings, DefaultDuplicateStrategy); err != nil {
+		t.Fatalf("SaveFindings failed: %v", err)
+	}
+}
+
+func TestExportFindingsCSV(t *testing.T) {
+	mdb, cleanup := setupTestDB(t)
+	defer cleanup()
+	db := mdb.(*SQLiteMemoryDB)
+	seedExportFindings(t, db)
+
+	data, contentType, err := db.ExportFindings(context.Background(), "env-export", "csv")
+	if err != nil {
+		t.Fatalf("ExportFindings failed: %v", err)
+	}
+	if contentType != ContentTypeCSV {
+		t.Errorf("Expected content type %q, got %q", ContentTypeCSV, contentType)
+	}
+
+	records, err := csv.NewReader(bytes.NewReader(data)).ReadAll()
+	if err != nil {
+		t.Fatalf("Export is not valid CSV: %v", err)
+	}
+	if len(records) != 3 {
+		t.Fatalf("Expected header and 2 rows, got %d records", len(records))
+	}
+	if !reflect.DeepEqual(records[0], FindingsCSVHeader) {
+		t.Errorf("Unexpected header %v", records[0])
+	}
+	byID := map[string][]string{}
+	for _, r := range records[1:] {
+		byID[r[0]] = r
+	}
+	vuln := byID["VULN-001"]
+	if vuln == nil || vuln[6] != "Query built with, \"string\" concatenation" || vuln[13] != `{"cwe":"CWE-89"}` {
+		t.Errorf("Unexpected VULN-001 row %v", vuln)
+	}
+}
+
+func TestExportFindingsSARIF(t *testing.T) {
+	mdb, cleanup := setupTestDB(t)
+	defer cleanup()
+	db := mdb.(*SQLiteMemoryDB)
+	seedExportFindings(t, db)
+
+	data, contentType, err := db.ExportFindings(context.Background(), "env-export", "sarif")
+	if err != nil {
+		t.Fatalf("ExportFindings failed: %v", err)
+	}
+	if contentType != ContentTypeSARIF {
+		t.Errorf("Expected content type %q, got %q", ContentTypeSARIF, contentType)
+	}
+
+	var log sarifLog
+	if err := json.Unmarshal(data, &log); err != nil {
+		t.Fatalf("Export is not valid JSON: %v", err)
+	}
+	if log.Version != SARIFVersion || log.Schema != SARIFSchema || len(log.Runs) != 1 {
+		t.Fatalf("Unexpected SARIF envelope: version %q, schema %q, %d runs", log.Version, log.Schema, len(log.Runs))
+	}
+	run := log.Runs[0]
+	if len(run.Tool.Driver.Rules) != 2 || len(run.Results) != 2 {
+		t.Fatalf("Expected 2 rules and 2 results, got %d and %d", len(run.Tool.Driver.Rules), len(run.Results))
+	}
+	for _, result := range run.Results {
+		if run.Tool.Driver.Rules[result.RuleIndex].ID != result.RuleID {
+			t.Errorf("Result ruleIndex %d does not point at rule %s", result.RuleIndex, result.RuleID)
+		}
+		if result.Properties["findingId"] != "VULN-001" {
+			continue
+		}
+		if result.Level != "error" {
+			t.Errorf("Expected critical finding at level error, got %s", result.Level)
+		}
+		if len(result.Locations) != 1 || result.Locations[0].PhysicalLocation.ArtifactLocation.URI != "internal/db/query.go" ||
+			result.Locations[0].PhysicalLocation.Region == nil || result.Locations[0].PhysicalLocation.Region.StartLine != 42 {
+			t.Errorf("Unexpected location %+v", result.Locations)
+		}
+	}
+}
+
+func TestExportFindingsErrors(t *testing.T) {
+	mdb, cleanup := setupTestDB(t)
+	defer cleanup()
+	db := mdb.(*SQLiteMemoryDB)
+	seedExportFindings(t, db)
+
+	if _, _, err := db.ExportFindings(context.Background(), "env-export", "xml"); !errors.Is(err, ErrUnsupportedExportFormat) {
+		t.Errorf("Expected ErrUnsupportedExportFormat, got %v", err)
+	}
+	if _, _, err := db.ExportFindings(context.Background(), "env-missing", "csv"); !errors.Is(err, ErrEnvironmentNotFound) {
+		t.Errorf("Expected ErrEnvironmentNotFound, got %v", err)
+	}
+}
@@ -1,6 +1,9 @@
 package memory
 
-import "time"
+import (
+	"context"
+	"time"
+)
 
 // MemoryDB is the main interface for cross-session memory operations
 // This interface allows other work streams to develop in parallel without
@@ -12,6 +15,7 @@ type MemoryDB interface {
 	GetRepoByPath(basePath string) (*Repo, error)
 	UpdateRepoScan(repoID string) error
 	SetRepoRescan(repoID string, needsRescan bool) error
+	DetectRepoChanges(ctx context.Context, repoID string) (*RepoChangeSummary, error)
 
 	// Repository files
 	StoreRepoFile(file *RepoFile) error
@@ -36,6 +40,8 @@ type MemoryDB interface {
 	GetTasks(filter TaskFilter) ([]*WorkflowTask, error)
 	UpdateTaskStatus(taskID, status, agentID string) error
 	UpdateTask(task *WorkflowTask) error
+	AddTaskDependency(taskID, dependsOn string) error
+	GetTaskDependencies(taskID string) ([]*TaskDependency, error)
 
 	// Human decisions
 	StoreDecision(decision *HumanDecision) error
@@ -47,6 +53,7 @@ type MemoryDB interface {
 	GetDeployment(deploymentID int64) (*Deployment, error)
 	GetRecentDeployments(repoID string, limit int) ([]*Deployment, error)
 	UpdateDeploymentStatus(deploymentID int64, status string) error
+	StartDeployment(deploymentID int64) error
 
 
 	// Learning memory access
@@ -59,15 +66,45 @@ type MemoryDB interface {
 	GetContextByPriority(minPriority int) ([]*CaptainContext, error)
 	DeleteContext(key string) error
 	CleanExpiredContext() (int, error)
+	GetExpiringContext(withinHours int) ([]*CaptainContext, error)
+	GetContextExpiryWarningHours() int
 
 	// Captain session log
 	LogSessionEvent(sessionID, eventType, summary, details, agentID string) error
+	LogSessionEventContext(ctx context.Context, sessionID, eventType, summary, details, agentID string) error
 	GetSessionLog(sessionID string, limit int) ([]*SessionLogEntry, error)
 	GetRecentSessionLog(limit int) ([]*SessionLogEntry, error)
 
+	// Captain task provenance
+	RecordCaptainTask(task *CaptainTaskRecord) error
+	UpdateCaptainTaskStatus(id, status string) error
+	AssignCaptainTask(id, agentID string) error
+	RequeueCaptainTask(id string, maxRetries int) (*CaptainTaskRecord, error)
+	GetInFlightCaptainTaskIDs(agentID string) ([]string, error)
+	GetCaptainTasks(sourceType string, limit int) ([]*CaptainTaskRecord, error)
+	GetCaptainTaskSourceCounts(since time.Time) (map[string]int, error)
+	RecordMissionAttempt(attempt *MissionAttempt) error
+	GetMissionAttempts(missionID string) ([]*MissionAttempt, error)
+
+	// Captain escalations
+	SaveCaptainEscalation(esc *CaptainEscalationRecord) error
+	GetCaptainEscalations() ([]*CaptainEscalationRecord, error)
+
+	// Captain orchestration metrics
+	StoreOrchestratorMetric(metric *OrchestratorMetric) error
+	GetOrchestratorMetricHistory(days int) ([]*OrchestratorDailyStats, error)
+	GetTraceRecords(traceID string) (*TraceRecords, error)
+	StoreSessionStatsSnapshot(snapshot *SessionStatsSnapshot) error
+	GetSessionStatsHistory(from, to time.Time, bucket time.Duration) ([]*SessionStatsSnapshot, error)
+
 	// Metrics history
 	RecordMetricsHistory(agentID, model string, tokensUsed int64, estimatedCost float64, taskID string) error
 
+	// Cost attribution
+	RecordAgentCost(entry *AgentCostEntry) error
+	GetCostByRepo(repoID string, since time.Time) (float64, error)
+	GetRepoCosts(since time.Time) ([]*RepoCost, error)
+
 	// Metrics analysis
 	GetMetricsByModel(modelFilter string) ([]*ModelMetrics, error)
 	GetMetricsByAgentType() ([]*AgentTypeMetrics, error)
@@ -96,6 +133,8 @@ type MemoryDB interface {
 
 	// Review Board operations
 	CreateReviewBoard(board *ReviewBoard) error
+	GetAvailableReviewers(role string, excludeIDs []string) ([]string, error)
+	DispatchBoard(ctx context.Context, boardID int64, role string, excludeIDs []string, strategy ReviewerAssignmentStrategy) ([]string, error)
 	GetReviewBoard(id int64) (*ReviewBoard, error)
 	GetReviewBoardByAssignment(assignmentID int64) (*ReviewBoard, error)
 	UpdateReviewBoard(board *ReviewBoard) error
@@ -104,15 +143,38 @@ type MemoryDB interface {
 	GetDefectsByReviewer(boardID int64, reviewerID string) ([]*ReviewDefect, error)
 	CreateReviewerVote(vote *ReviewerVote) error
 	GetReviewerVotes(boardID int64) ([]*ReviewerVote, error)
+	LockReviewerSlot(boardID int64, reviewerID string) error
+	AbandonReviewerSlot(boardID int64, reviewerID string) error
+	GetReviewerSlots(boardID int64) ([]*ReviewerSlot, error)
 	GetOrCreateQualityScore(agentID, role string) (*AgentQualityScore, error)
 	UpdateQualityScore(score *AgentQualityScore) error
 	GetAgentLeaderboard(role string, limit int) ([]*AgentQualityScore, error)
+	GetLeaderboardWithRanks(role string, limit int) ([]*AgentQualityScoreWithRank, error)
+	GetQualityScoreHistory(agentID string, days int) ([]*QualityScoreSnapshot, error)
+	ComputeAgentHealthScore(agentID string, lastSeen time.Time) (*AgentHealthScore, error)
+	GetDefectPatterns(ctx context.Context, limit int) ([]*DefectPattern, error)
 	GetDefectCategories() ([]*DefectCategory, error)
+	PruneOrphanedBoards(ctx context.Context, olderThan time.Duration) (int, error)
+	CreateDefectCategory(category *DefectCategory) error
+	UpdateDefectCategory(code string, updates map[string]interface{}) error
+	DeleteDefectCategory(code string) error
 	CalculateConsensus(boardID int64) (*ConsensusResult, error)
 	UpdateQualityScoresAfterReview(boardID int64, consensus *ConsensusResult) error
 	GenerateReviewReport(boardID int64) (string, error)
 	SaveReviewReport(boardID int64, title, content, projectID string) error
 
+	// Review webhook outbox
+	EnqueueReviewWebhook(boardID int64, payload []byte, lastError string) error
+	GetPendingReviewWebhooks(maxAttempts, limit int) ([]*ReviewWebhookOutboxEntry, error)
+	MarkReviewWebhookDelivered(id int64) error
+	RecordReviewWebhookAttempt(id int64, lastError string) error
+
+	// Model A/B experiments
+	CreateModelExperiment(exp *ModelExperiment) error
+	GetModelExperiment(experimentID string) (*ModelExperiment, error)
+	GetActiveModelExperiments() ([]*ModelExperiment, error)
+	RecordExperimentResult(experimentID, model string, score float64) error
+
 	// Document operations
 	CreateDocument(doc *Document) error
 	GetDocument(id int64) (*Document, error)
@@ -127,9 +189,15 @@ type MemoryDB interface {
 	GetConfig(configType string) (*ConfigEntry, error)
 	SaveConfig(configType, content, format string) error
 	GetAllConfigs() ([]*ConfigEntry, error)
+	RecordConfigChanges(changes []*ConfigChange) error
+	GetConfigChanges(limit int) ([]*ConfigChange, error)
+
+	// Recon finding export (csv or sarif)
+	ExportFindings(ctx context.Context, envID string, format string) ([]byte, string, error)
 
 	// Health check
 	Health() (*HealthStatus, error)
+	RepairDatabase() error
 
 	// Lifecycle
 	Close() error
@@ -146,6 +214,14 @@ type HealthStatus struct {
 	DBPath          string `json:"db_path"`
 	DBSizeBytes     int64  `json:"db_size_bytes"`
 	LastContextSave string `json:"last_context_save,omitempty"`
+
+	// Integrity is "ok" or "error"; IntegrityErrors lists what
+	// PRAGMA integrity_check reported
+	Integrity       string   `json:"integrity"`
+	IntegrityErrors []string `json:"integrity_errors,omitempty"`
+	// WAL size and frame count before the health check's checkpoint
+	WALSizeBytes int64 `json:"wal_size_bytes"`
+	WALFrames    int   `json:"wal_frames"`
 }
 
 // Repo represents a discovered repository
@@ -164,6 +240,15 @@ func (r *Repo) ShouldRescan() bool {
 	return r.NeedsRescan || time.Since(r.LastScanned) > 24*time.Hour
 }
 
+// RepoChangeSummary describes git activity since a repository was last scanned
+type RepoChangeSummary struct {
+	RepoID       string
+	LastScanned  time.Time
+	ChangedFiles []string
+	CommitCount  int
+	NeedsRescan  bool
+}
+
 // RepoFile represents a discovered file in a repository
 type RepoFile struct {
 	RepoID       string
@@ -215,6 +300,13 @@ type WorkflowTask struct {
 	CompletedAt     *time.Time
 }
 
+// TaskDependency is an edge of the workflow task dependency graph: TaskID
+// cannot start until DependsOn is done
+type TaskDependency struct {
+	TaskID    string `json:"task_id"`
+	DependsOn string `json:"depends_on"`
+}
+
 // TaskFilter filters workflow tasks
 type TaskFilter struct {
 	RepoID          string
@@ -274,15 +366,134 @@ type CaptainContext struct {
 	UpdatedAt   time.Time
 }
 
+// ExpiresAt returns when the entry expires; ok is false if it never does
+func (c *CaptainContext) ExpiresAt() (expiresAt time.Time, ok bool) {
+	if c.MaxAgeHours <= 0 {
+		return time.Time{}, false
+	}
+	return c.UpdatedAt.Add(time.Duration(c.MaxAgeHours) * time.Hour), true
+}
+
 // SessionLogEntry records significant Captain events
 type SessionLogEntry struct {
-	ID        int64
-	SessionID string
-	EventType string // 'startup', 'command', 'spawn', 'decision', 'error', 'shutdown'
-	Summary   string
-	Details   string
-	AgentID   string
-	CreatedAt time.Time
+	ID        int64     `json:"id"`
+	SessionID string    `json:"session_id"`
+	EventType string    `json:"event_type"` // 'startup', 'command', 'spawn', 'decision', 'error', 'shutdown'
+	Summary   string    `json:"summary"`
+	Details   string    `json:"details,omitempty"`
+	AgentID   string    `json:"agent_id,omitempty"`
+	TraceID   string    `json:"trace_id,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// CaptainTaskRecord records a mission Captain executed and how it arrived
+type CaptainTaskRecord struct {
+	ID         string    `json:"id"`
+	Title      string    `json:"title"`
+	TaskType   string    `json:"task_type"`
+	SourceType string    `json:"source_type"` // 'api', 'github', 'json_file', 'internal'
+	SourceRef  string    `json:"source_ref,omitempty"`
+	Status     string    `json:"status"` // 'executing', 'spawned', 'pending', 'escalated', 'completed', 'failed'
+	AssignedTo string    `json:"assigned_to,omitempty"`
+	RetryCount int       `json:"retry_count"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// MissionAttempt is one execution attempt of a Captain mission
+type MissionAttempt struct {
+	ID        int64      `json:"id"`
+	MissionID string     `json:"mission_id"`
+	Phase     string     `json:"phase"`   // recon, execute
+	Attempt   int        `json:"attempt"` // 1-based within the phase
+	StartedAt time.Time  `json:"started_at"`
+	EndedAt   *time.Time `json:"ended_at,omitempty"`
+	Status    string     `json:"status"` // completed, failed
+	Error     string     `json:"error,omitempty"`
+}
+
+// CaptainEscalationRecord is a persisted Captain escalation and, once
+// resolved, who resolved it and how
+type CaptainEscalationRecord struct {
+	ID         string     `json:"id"`
+	TaskID     string     `json:"task_id,omitempty"`
+	AgentID    string     `json:"agent_id,omitempty"`
+	Reason     string     `json:"reason"`
+	Context    string     `json:"context,omitempty"`
+	Question   string     `json:"question,omitempty"`
+	Resolution string     `json:"resolution,omitempty"`
+	ResolvedBy string     `json:"resolved_by,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+	ResolvedAt *time.Time `json:"resolved_at,omitempty"`
+}
+
+// OrchestratorMetric records the work done by one Captain cycle
+type OrchestratorMetric struct {
+	ID                 int64     `json:"id"`
+	CycleAt            time.Time `json:"cycle_at"`
+	DurationMs         int64     `json:"duration_ms"`
+	TasksProcessed     int       `json:"tasks_processed"`
+	SubagentsSpawned   int       `json:"subagents_spawned"`
+	EscalationsCreated int       `json:"escalations_created"`
+	TraceID            string    `json:"trace_id,omitempty"`
+}
+
+// SessionStatsSnapshot is a point-in-time copy of the session statistics.
+// Token, cost, task and alert figures are running totals.
+type SessionStatsSnapshot struct {
+	ID              int64     `json:"-"`
+	Timestamp       time.Time `json:"timestamp"`
+	ActiveAgents    int       `json:"active_agents"`
+	TotalTokensUsed int64     `json:"total_tokens_used"`
+	TotalCost       float64   `json:"total_cost"`
+	TasksCompleted  int       `json:"tasks_completed"`
+	AlertsFired     int       `json:"alerts_fired"`
+}
+
+// TraceRecords holds the database rows tagged with one trace ID
+type TraceRecords struct {
+	TraceID             string                `json:"trace_id"`
+	SessionLog          []*SessionLogEntry    `json:"session_log"`
+	OrchestratorMetrics []*OrchestratorMetric `json:"orchestrator_metrics"`
+}
+
+// OrchestratorDailyStats aggregates orchestrator metrics for one UTC day
+type OrchestratorDailyStats struct {
+	Day                string  `json:"day"` // YYYY-MM-DD
+	Cycles             int     `json:"cycles"`
+	TasksProcessed     int     `json:"tasks_processed"`
+	SubagentsSpawned   int     `json:"subagents_spawned"`
+	EscalationsCreated int     `json:"escalations_created"`
+	AvgDurationMs      float64 `json:"avg_duration_ms"`
+}
+
+// ReviewWebhookOutboxEntry is a review completion webhook awaiting delivery
+type ReviewWebhookOutboxEntry struct {
+	ID            int64     `json:"id"`
+	BoardID       int64     `json:"board_id"`
+	Payload       string    `json:"payload"`
+	Attempts      int       `json:"attempts"`
+	LastError     string    `json:"last_error,omitempty"`
+	CreatedAt     time.Time `json:"created_at"`
+	LastAttemptAt time.Time `json:"last_attempt_at"`
+}
+
+// AgentCostEntry attributes estimated token spend to a repository and task
+type AgentCostEntry struct {
+	ID         int64     `json:"id"`
+	AgentID    string    `json:"agent_id"`
+	RepoID     string    `json:"repo_id,omitempty"`
+	TaskID     string    `json:"task_id,omitempty"`
+	Tokens     int64     `json:"tokens"`
+	Cost       float64   `json:"cost"`
+	RecordedAt time.Time `json:"recorded_at"`
+}
+
+// RepoCost totals the cost ledger for one repository
+type RepoCost struct {
+	RepoID string  `json:"repo_id"` // empty for spend outside any known repo
+	Tokens int64   `json:"tokens"`
+	Cost   float64 `json:"cost"`
 }
 
 // ModelMetrics represents aggregated metrics per model from the metrics_by_model view
@@ -370,6 +581,16 @@ type ConfigEntry struct {
 	UpdatedAt  time.Time
 }
 
+// ConfigChange is one agent field that changed when teams.yaml was reloaded
+type ConfigChange struct {
+	ID        int64     `json:"id"`
+	ChangedAt time.Time `json:"changed_at"`
+	AgentName string    `json:"agent_name"`
+	Field     string    `json:"field"`
+	OldValue  string    `json:"old_value"`
+	NewValue  string    `json:"new_value"`
+}
+
 // Common context keys for Captain
 const (
 	CtxKeyCurrentFocus   = "current_focus"    // What Captain is currently working on
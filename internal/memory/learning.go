@@ -4,7 +4,6 @@ import (
 	"database/sql"
 	"encoding/json"
 	"fmt"
-	"log"
 	"math"
 	"regexp"
 	"sort"
@@ -12,6 +11,8 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+
+	"github.com/CLIAIMONITOR/internal/logger"
 )
 
 // LearningDB provides RAG-style memory operations
@@ -637,23 +638,23 @@ func (l *SQLiteLearningDB) GetKnowledgeStats() (*KnowledgeStats, error) {
 
 	// Total knowledge
 	if err := l.db.QueryRow("SELECT COUNT(*) FROM knowledge").Scan(&stats.TotalKnowledge); err != nil {
-		log.Printf("[LEARNING] Warning: Failed to get total knowledge count: %v", err)
+		logger.For("learning").Warn("failed to get total knowledge count", "error", err)
 	}
 
 	// Total episodes
 	if err := l.db.QueryRow("SELECT COUNT(*) FROM episodes").Scan(&stats.TotalEpisodes); err != nil {
-		log.Printf("[LEARNING] Warning: Failed to get total episodes count: %v", err)
+		logger.For("learning").Warn("failed to get total episodes count", "error", err)
 	}
 
 	// Total terms
 	if err := l.db.QueryRow("SELECT COUNT(*) FROM term_stats").Scan(&stats.TotalTerms); err != nil {
-		log.Printf("[LEARNING] Warning: Failed to get total terms count: %v", err)
+		logger.For("learning").Warn("failed to get total terms count", "error", err)
 	}
 
 	// By category
 	rows, err := l.db.Query("SELECT category, COUNT(*) FROM knowledge GROUP BY category")
 	if err != nil {
-		log.Printf("[LEARNING] Warning: Failed to query categories: %v", err)
+		logger.For("learning").Warn("failed to query categories", "error", err)
 	}
 	if rows != nil {
 		for rows.Next() {
@@ -671,7 +672,7 @@ func (l *SQLiteLearningDB) GetKnowledgeStats() (*KnowledgeStats, error) {
 		FROM knowledge ORDER BY use_count DESC LIMIT 5
 	`)
 	if err != nil {
-		log.Printf("[LEARNING] Warning: Failed to query most used knowledge: %v", err)
+		logger.For("learning").Warn("failed to query most used knowledge", "error", err)
 	}
 	if rows != nil {
 		for rows.Next() {
@@ -1,6 +1,10 @@
 package memory
 
 import (
+	"context"
+	"errors"
+	"os"
+	"os/exec"
 	"path/filepath"
 	"testing"
 	"time"
@@ -115,6 +119,88 @@ func TestUpdateRepoScan(t *testing.T) {
 	}
 }
 
+func TestDetectRepoChanges(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	dir := t.TempDir()
+	git := func(env []string, args ...string) {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		cmd.Env = append(os.Environ(), env...)
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v failed: %v\n%s", args, err, out)
+		}
+	}
+	git(nil, "init", "-q")
+	git(nil, "config", "user.email", "test@example.com")
+	git(nil, "config", "user.name", "Test")
+
+	repo, err := db.DiscoverRepo(dir)
+	if err != nil {
+		t.Fatalf("DiscoverRepo failed: %v", err)
+	}
+
+	// Never scanned: always needs a rescan
+	summary, err := db.DetectRepoChanges(context.Background(), repo.ID)
+	if err != nil {
+		t.Fatalf("DetectRepoChanges failed: %v", err)
+	}
+	if !summary.NeedsRescan {
+		t.Error("Expected unscanned repo to need rescan")
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "old.txt"), []byte("old"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	past := time.Now().Add(-time.Hour).Format(time.RFC3339)
+	git([]string{"GIT_AUTHOR_DATE=" + past, "GIT_COMMITTER_DATE=" + past}, "add", ".")
+	git([]string{"GIT_AUTHOR_DATE=" + past, "GIT_COMMITTER_DATE=" + past}, "commit", "-q", "-m", "old")
+
+	if err := db.UpdateRepoScan(repo.ID); err != nil {
+		t.Fatalf("UpdateRepoScan failed: %v", err)
+	}
+
+	// Only commits before the scan: nothing to do
+	summary, err = db.DetectRepoChanges(context.Background(), repo.ID)
+	if err != nil {
+		t.Fatalf("DetectRepoChanges failed: %v", err)
+	}
+	if summary.NeedsRescan || summary.CommitCount != 0 || len(summary.ChangedFiles) != 0 {
+		t.Errorf("Expected no changes, got %+v", summary)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "new.txt"), []byte("new"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	future := time.Now().Add(time.Hour).Format(time.RFC3339)
+	git([]string{"GIT_AUTHOR_DATE=" + future, "GIT_COMMITTER_DATE=" + future}, "add", ".")
+	git([]string{"GIT_AUTHOR_DATE=" + future, "GIT_COMMITTER_DATE=" + future}, "commit", "-q", "-m", "new")
+
+	summary, err = db.DetectRepoChanges(context.Background(), repo.ID)
+	if err != nil {
+		t.Fatalf("DetectRepoChanges failed: %v", err)
+	}
+	if !summary.NeedsRescan || summary.CommitCount != 1 {
+		t.Errorf("Expected 1 new commit needing rescan, got %+v", summary)
+	}
+	if len(summary.ChangedFiles) != 1 || summary.ChangedFiles[0] != "new.txt" {
+		t.Errorf("Expected [new.txt], got %v", summary.ChangedFiles)
+	}
+
+	updated, err := db.GetRepo(repo.ID)
+	if err != nil {
+		t.Fatalf("GetRepo failed: %v", err)
+	}
+	if !updated.NeedsRescan {
+		t.Error("Expected repo to be flagged for rescan")
+	}
+}
+
 // Test Repository Files
 
 func TestStoreRepoFile(t *testing.T) {
@@ -552,6 +638,52 @@ func TestCreateDeployment(t *testing.T) {
 	}
 }
 
+func TestStartDeployment(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo, err := db.DiscoverRepo(".")
+	if err != nil {
+		t.Fatalf("DiscoverRepo failed: %v", err)
+	}
+	deployment := &Deployment{RepoID: repo.ID, DeploymentPlan: "{}", Status: "approved"}
+	if err := db.CreateDeployment(deployment); err != nil {
+		t.Fatalf("CreateDeployment failed: %v", err)
+	}
+
+	// Only one of several concurrent callers may start the deployment
+	const callers = 5
+	errs := make(chan error, callers)
+	for i := 0; i < callers; i++ {
+		go func() { errs <- db.StartDeployment(deployment.ID) }()
+	}
+	started := 0
+	for i := 0; i < callers; i++ {
+		err := <-errs
+		switch {
+		case err == nil:
+			started++
+		case !errors.Is(err, ErrDeploymentNotStartable):
+			t.Errorf("Unexpected error: %v", err)
+		}
+	}
+	if started != 1 {
+		t.Errorf("Expected exactly one caller to start the deployment, got %d", started)
+	}
+
+	stored, err := db.GetDeployment(deployment.ID)
+	if err != nil {
+		t.Fatalf("GetDeployment failed: %v", err)
+	}
+	if stored.Status != "executing" || stored.ExecutedAt == nil {
+		t.Errorf("Expected executing with executed_at set, got %+v", stored)
+	}
+
+	if err := db.StartDeployment(99999); !errors.Is(err, ErrDeploymentNotFound) {
+		t.Errorf("Expected ErrDeploymentNotFound, got %v", err)
+	}
+}
+
 func TestUpdateDeploymentStatus(t *testing.T) {
 	db, cleanup := setupTestDB(t)
 	defer cleanup()
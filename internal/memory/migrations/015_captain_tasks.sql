@@ -0,0 +1,19 @@
+-- Migration 015: Captain task provenance
+-- Records every mission Captain executes along with how it arrived
+
+CREATE TABLE IF NOT EXISTS captain_tasks (
+    id TEXT PRIMARY KEY,                  -- Mission ID (Planner task ID, task-<nanos>, etc.)
+    title TEXT NOT NULL,
+    task_type TEXT NOT NULL,
+    source_type TEXT NOT NULL DEFAULT 'internal',  -- api, github, json_file, internal
+    source_ref TEXT,                      -- GitHub issue URL, Planner task URL, file path, parent ID
+    status TEXT NOT NULL DEFAULT 'executing',      -- executing, spawned, completed, failed
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_captain_tasks_source_type ON captain_tasks(source_type);
+CREATE INDEX IF NOT EXISTS idx_captain_tasks_created_at ON captain_tasks(created_at);
+
+-- Update schema version
+INSERT OR REPLACE INTO schema_version (version, applied_at) VALUES (16, CURRENT_TIMESTAMP);
@@ -0,0 +1,16 @@
+-- Migration 016: Captain orchestration metrics
+-- One row per Captain.runCycle for trend analysis
+
+CREATE TABLE IF NOT EXISTS orchestrator_metrics (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    cycle_at DATETIME NOT NULL,
+    duration_ms INTEGER NOT NULL DEFAULT 0,
+    tasks_processed INTEGER NOT NULL DEFAULT 0,
+    subagents_spawned INTEGER NOT NULL DEFAULT 0,
+    escalations_created INTEGER NOT NULL DEFAULT 0
+);
+
+CREATE INDEX IF NOT EXISTS idx_orchestrator_metrics_cycle_at ON orchestrator_metrics(cycle_at);
+
+-- Update schema version
+INSERT OR REPLACE INTO schema_version (version, applied_at) VALUES (17, CURRENT_TIMESTAMP);
@@ -0,0 +1,31 @@
+-- Migration 017: Derive review board risk level from defect severities
+-- Mirrors CalculateRiskLevel so the value stays consistent when defects are
+-- written outside UpdateReviewBoard. Boards without defects keep the risk
+-- level they were created with.
+
+CREATE TRIGGER IF NOT EXISTS trg_review_defects_risk_insert
+AFTER INSERT ON review_defects
+BEGIN
+    UPDATE review_boards SET risk_level = CASE
+        WHEN (SELECT COUNT(*) FROM review_defects WHERE board_id = NEW.board_id AND severity = 'critical') > 0 THEN 'critical'
+        WHEN (SELECT COUNT(*) FROM review_defects WHERE board_id = NEW.board_id AND severity = 'high') >= 3 THEN 'high'
+        WHEN (SELECT COUNT(*) FROM review_defects WHERE board_id = NEW.board_id AND severity = 'medium') >= 5 THEN 'medium'
+        ELSE 'low'
+    END
+    WHERE id = NEW.board_id;
+END;
+
+CREATE TRIGGER IF NOT EXISTS trg_review_defects_risk_update
+AFTER UPDATE OF severity ON review_defects
+BEGIN
+    UPDATE review_boards SET risk_level = CASE
+        WHEN (SELECT COUNT(*) FROM review_defects WHERE board_id = NEW.board_id AND severity = 'critical') > 0 THEN 'critical'
+        WHEN (SELECT COUNT(*) FROM review_defects WHERE board_id = NEW.board_id AND severity = 'high') >= 3 THEN 'high'
+        WHEN (SELECT COUNT(*) FROM review_defects WHERE board_id = NEW.board_id AND severity = 'medium') >= 5 THEN 'medium'
+        ELSE 'low'
+    END
+    WHERE id = NEW.board_id;
+END;
+
+-- Update schema version
+INSERT OR REPLACE INTO schema_version (version, applied_at) VALUES (18, CURRENT_TIMESTAMP);
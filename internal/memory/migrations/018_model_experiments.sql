@@ -0,0 +1,28 @@
+-- Migration 018: Model A/B experiments
+-- Compares author quality scores between a control and a treatment model
+
+CREATE TABLE IF NOT EXISTS model_experiments (
+    id TEXT PRIMARY KEY,
+    control_model TEXT NOT NULL,
+    treatment_model TEXT NOT NULL,
+    sample_size INTEGER NOT NULL DEFAULT 0,
+    status TEXT NOT NULL DEFAULT 'active',
+    started_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    completed_at DATETIME
+);
+
+CREATE INDEX IF NOT EXISTS idx_model_experiments_status ON model_experiments(status);
+
+-- One row per recorded quality score
+CREATE TABLE IF NOT EXISTS model_experiment_results (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    experiment_id TEXT NOT NULL REFERENCES model_experiments(id) ON DELETE CASCADE,
+    model TEXT NOT NULL,
+    score REAL NOT NULL,
+    recorded_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_model_experiment_results_experiment ON model_experiment_results(experiment_id, model);
+
+-- Update schema version
+INSERT OR REPLACE INTO schema_version (version, applied_at) VALUES (19, CURRENT_TIMESTAMP);
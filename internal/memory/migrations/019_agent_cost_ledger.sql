@@ -0,0 +1,17 @@
+-- Migration 019: Agent cost ledger
+-- Attributes estimated token spend to the repository and task an agent is working on
+
+CREATE TABLE IF NOT EXISTS agent_cost_ledger (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    agent_id TEXT NOT NULL,
+    repo_id TEXT,
+    task_id TEXT,
+    tokens INTEGER NOT NULL DEFAULT 0,
+    cost REAL NOT NULL DEFAULT 0,
+    recorded_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_agent_cost_ledger_repo ON agent_cost_ledger(repo_id, recorded_at);
+
+-- Update schema version
+INSERT OR REPLACE INTO schema_version (version, applied_at) VALUES (20, CURRENT_TIMESTAMP);
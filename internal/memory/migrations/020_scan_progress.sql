@@ -0,0 +1,9 @@
+-- Migration 020: Recon scan progress
+-- Lets long-running scans report how far they have got
+
+ALTER TABLE recon_scans ADD COLUMN progress_files_scanned INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE recon_scans ADD COLUMN progress_current_file TEXT;
+ALTER TABLE recon_scans ADD COLUMN progress_updated_at DATETIME;
+
+-- Update schema version
+INSERT OR REPLACE INTO schema_version (version, applied_at) VALUES (21, CURRENT_TIMESTAMP);
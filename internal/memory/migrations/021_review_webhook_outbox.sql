@@ -0,0 +1,18 @@
+-- Migration 021: Review webhook outbox
+-- Holds review completion webhooks that could not be delivered so they can be retried
+
+CREATE TABLE IF NOT EXISTS outbox_review_webhooks (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    board_id INTEGER NOT NULL,
+    payload TEXT NOT NULL,                     -- exact JSON body that is signed and sent
+    attempts INTEGER NOT NULL DEFAULT 1,
+    last_error TEXT,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    last_attempt_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    delivered_at DATETIME
+);
+
+CREATE INDEX IF NOT EXISTS idx_outbox_review_webhooks_pending ON outbox_review_webhooks(delivered_at, created_at);
+
+-- Update schema version
+INSERT OR REPLACE INTO schema_version (version, applied_at) VALUES (22, CURRENT_TIMESTAMP);
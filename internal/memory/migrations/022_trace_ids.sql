@@ -0,0 +1,11 @@
+-- Migration 022: Trace IDs
+-- Links session log entries and Captain cycles to the trace that produced them
+
+ALTER TABLE captain_session_log ADD COLUMN trace_id TEXT;
+ALTER TABLE orchestrator_metrics ADD COLUMN trace_id TEXT;
+
+CREATE INDEX IF NOT EXISTS idx_captain_log_trace ON captain_session_log(trace_id);
+CREATE INDEX IF NOT EXISTS idx_orchestrator_metrics_trace ON orchestrator_metrics(trace_id);
+
+-- Update schema version
+INSERT OR REPLACE INTO schema_version (version, applied_at) VALUES (23, CURRENT_TIMESTAMP);
@@ -0,0 +1,8 @@
+-- Migration 023: Reviewer slots
+-- A reviewer_votes row is now created when a reviewer locks a slot on a board
+-- and completed when the vote is cast. Existing rows are completed votes.
+
+ALTER TABLE reviewer_votes ADD COLUMN status TEXT NOT NULL DEFAULT 'completed'; -- pending, completed, abandoned
+
+-- Update schema version
+INSERT OR REPLACE INTO schema_version (version, applied_at) VALUES (24, CURRENT_TIMESTAMP);
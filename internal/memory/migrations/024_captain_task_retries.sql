@@ -0,0 +1,11 @@
+-- Migration 024: Captain task retries
+-- Tracks which agent is running a captain task so it can be re-queued when
+-- that agent crashes, and how many times that has happened.
+
+ALTER TABLE captain_tasks ADD COLUMN assigned_to TEXT;
+ALTER TABLE captain_tasks ADD COLUMN retry_count INTEGER NOT NULL DEFAULT 0;
+
+CREATE INDEX IF NOT EXISTS idx_captain_tasks_assigned_to ON captain_tasks(assigned_to);
+
+-- Update schema version
+INSERT OR REPLACE INTO schema_version (version, applied_at) VALUES (25, CURRENT_TIMESTAMP);
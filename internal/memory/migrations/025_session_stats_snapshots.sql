@@ -0,0 +1,17 @@
+-- Migration 025: Session stats snapshots
+-- Periodic copies of the dashboard's session statistics for time-series charts
+
+CREATE TABLE IF NOT EXISTS session_stats_snapshots (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    taken_at DATETIME NOT NULL,           -- UTC, "YYYY-MM-DD HH:MM:SS"
+    active_agents INTEGER NOT NULL DEFAULT 0,
+    total_tokens_used INTEGER NOT NULL DEFAULT 0,
+    total_cost REAL NOT NULL DEFAULT 0,
+    tasks_completed INTEGER NOT NULL DEFAULT 0,
+    alerts_fired INTEGER NOT NULL DEFAULT 0
+);
+
+CREATE INDEX IF NOT EXISTS idx_session_stats_snapshots_taken_at ON session_stats_snapshots(taken_at);
+
+-- Update schema version
+INSERT OR REPLACE INTO schema_version (version, applied_at) VALUES (26, CURRENT_TIMESTAMP);
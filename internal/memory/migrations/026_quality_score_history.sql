@@ -0,0 +1,17 @@
+-- Migration 026: Quality score history
+-- agent_quality_scores holds only the current score; a snapshot is added here
+-- each time an agent's quality_score moves by more than 0.5 points
+
+CREATE TABLE IF NOT EXISTS quality_score_history (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    agent_id TEXT NOT NULL,
+    snapshot_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    quality_score REAL NOT NULL,
+    total_reviews INTEGER NOT NULL DEFAULT 0,
+    approval_rate REAL NOT NULL DEFAULT 0
+);
+
+CREATE INDEX IF NOT EXISTS idx_quality_score_history_agent ON quality_score_history(agent_id, snapshot_at);
+
+-- Update schema version
+INSERT OR REPLACE INTO schema_version (version, applied_at) VALUES (27, CURRENT_TIMESTAMP);
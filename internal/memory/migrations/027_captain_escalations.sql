@@ -0,0 +1,21 @@
+-- Migration 027: Captain escalations
+-- Escalations were kept only in Captain's memory; persisting them keeps an
+-- audit trail of who resolved what and lets them survive a restart
+
+CREATE TABLE IF NOT EXISTS captain_escalations (
+    id TEXT PRIMARY KEY,
+    task_id TEXT,
+    agent_id TEXT,
+    reason TEXT NOT NULL,
+    context TEXT,
+    question TEXT,
+    resolution TEXT,
+    resolved_by TEXT,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    resolved_at DATETIME
+);
+
+CREATE INDEX IF NOT EXISTS idx_captain_escalations_created ON captain_escalations(created_at);
+
+-- Update schema version
+INSERT OR REPLACE INTO schema_version (version, applied_at) VALUES (28, CURRENT_TIMESTAMP);
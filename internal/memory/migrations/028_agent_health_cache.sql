@@ -0,0 +1,17 @@
+-- Migration 028: Agent health cache
+-- Caches the database-derived parts of an agent's health score (task
+-- completion and defect density) so the health check does not re-aggregate
+-- them on every pass; heartbeat recency is always computed fresh
+
+CREATE TABLE IF NOT EXISTS agent_health_cache (
+    agent_id TEXT PRIMARY KEY,
+    completion_score REAL NOT NULL,
+    defect_score REAL NOT NULL,
+    tasks_finished INTEGER NOT NULL DEFAULT 0,
+    tasks_completed INTEGER NOT NULL DEFAULT 0,
+    defect_density REAL NOT NULL DEFAULT 0,
+    computed_at DATETIME NOT NULL
+);
+
+-- Update schema version
+INSERT OR REPLACE INTO schema_version (version, applied_at) VALUES (29, CURRENT_TIMESTAMP);
@@ -0,0 +1,18 @@
+-- Migration 029: Mission attempts
+-- One row per execution attempt of a Captain mission, so retries under a
+-- mission's RetryPolicy can be reviewed after the fact
+
+CREATE TABLE IF NOT EXISTS mission_attempts (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    mission_id TEXT NOT NULL,
+    attempt INTEGER NOT NULL,
+    started_at DATETIME NOT NULL,
+    ended_at DATETIME,
+    status TEXT NOT NULL,                    -- completed, failed
+    error TEXT
+);
+
+CREATE INDEX IF NOT EXISTS idx_mission_attempts_mission ON mission_attempts(mission_id, attempt);
+
+-- Update schema version
+INSERT OR REPLACE INTO schema_version (version, applied_at) VALUES (30, CURRENT_TIMESTAMP);
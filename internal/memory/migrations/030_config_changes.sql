@@ -0,0 +1,17 @@
+-- Migration 030: Config changes
+-- One row per field that changed when teams.yaml was reloaded, so agent
+-- configuration drift can be traced back to a reload
+
+CREATE TABLE IF NOT EXISTS config_changes (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    changed_at DATETIME NOT NULL,
+    agent_name TEXT NOT NULL,
+    field TEXT NOT NULL,
+    old_value TEXT,
+    new_value TEXT
+);
+
+CREATE INDEX IF NOT EXISTS idx_config_changes_changed_at ON config_changes(changed_at);
+
+-- Update schema version
+INSERT OR REPLACE INTO schema_version (version, applied_at) VALUES (31, CURRENT_TIMESTAMP);
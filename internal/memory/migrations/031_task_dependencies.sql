@@ -0,0 +1,14 @@
+-- Migration 031: Task dependencies
+-- Edges of the workflow task dependency graph: task_id cannot start until
+-- depends_on is done. Cycles are not prevented here; readers detect them.
+
+CREATE TABLE IF NOT EXISTS task_dependencies (
+    task_id TEXT NOT NULL,
+    depends_on TEXT NOT NULL,
+    PRIMARY KEY (task_id, depends_on)
+);
+
+CREATE INDEX IF NOT EXISTS idx_task_dependencies_depends_on ON task_dependencies(depends_on);
+
+-- Update schema version
+INSERT OR REPLACE INTO schema_version (version, applied_at) VALUES (32, CURRENT_TIMESTAMP);
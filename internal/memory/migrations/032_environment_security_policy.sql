@@ -0,0 +1,7 @@
+-- Migration 032: Per-environment recon security policy
+-- JSON SecurityPolicy; NULL means the environment type's default applies
+
+ALTER TABLE environments ADD COLUMN security_policy TEXT;
+
+-- Update schema version
+INSERT OR REPLACE INTO schema_version (version, applied_at) VALUES (33, CURRENT_TIMESTAMP);
@@ -0,0 +1,8 @@
+-- Migration 033: Mission attempt phase
+-- Recon attempts are recorded under the task they scan for, so each attempt
+-- says which phase of the task it belongs to
+
+ALTER TABLE mission_attempts ADD COLUMN phase TEXT NOT NULL DEFAULT 'execute'; -- recon, execute
+
+-- Update schema version
+INSERT OR REPLACE INTO schema_version (version, applied_at) VALUES (34, CURRENT_TIMESTAMP);
@@ -0,0 +1,55 @@
+package memory
+
+import (
+	"database/sql"
+	"fmt"
+)
+
+// RecordMissionAttempt stores one execution attempt of a Captain mission
+func (m *SQLiteMemoryDB) RecordMissionAttempt(a *MissionAttempt) error {
+	var endedAt sql.NullString
+	if a.EndedAt != nil {
+		endedAt = nullString(a.EndedAt.UTC().Format("2006-01-02 15:04:05"))
+	}
+	if a.Phase == "" {
+		a.Phase = "execute"
+	}
+	result, err := m.db.Exec(`
+		INSERT INTO mission_attempts (mission_id, phase, attempt, started_at, ended_at, status, error)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+	`, a.MissionID, a.Phase, a.Attempt, a.StartedAt.UTC().Format("2006-01-02 15:04:05"), endedAt, a.Status, nullString(a.Error))
+	if err != nil {
+		return fmt.Errorf("failed to record mission attempt %s #%d: %w", a.MissionID, a.Attempt, err)
+	}
+	a.ID, _ = result.LastInsertId()
+	return nil
+}
+
+// GetMissionAttempts returns a mission's attempts, recon and execution
+// alike, in the order they ran
+func (m *SQLiteMemoryDB) GetMissionAttempts(missionID string) ([]*MissionAttempt, error) {
+	rows, err := m.db.Query(`
+		SELECT id, mission_id, phase, attempt, started_at, ended_at, status, COALESCE(error, '')
+		FROM mission_attempts
+		WHERE mission_id = ?
+		ORDER BY id
+	`, missionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query mission attempts: %w", err)
+	}
+	defer rows.Close()
+
+	attempts := []*MissionAttempt{}
+	for rows.Next() {
+		a := &MissionAttempt{}
+		var endedAt sql.NullTime
+		if err := rows.Scan(&a.ID, &a.MissionID, &a.Phase, &a.Attempt, &a.StartedAt, &endedAt, &a.Status, &a.Error); err != nil {
+			return nil, fmt.Errorf("failed to scan mission attempt: %w", err)
+		}
+		if endedAt.Valid {
+			a.EndedAt = &endedAt.Time
+		}
+		attempts = append(attempts, a)
+	}
+	return attempts, rows.Err()
+}
@@ -0,0 +1,73 @@
+package memory
+
+import (
+	"fmt"
+	"time"
+)
+
+// StoreOrchestratorMetric records the outcome of one Captain cycle
+func (m *SQLiteMemoryDB) StoreOrchestratorMetric(metric *OrchestratorMetric) error {
+	if metric.CycleAt.IsZero() {
+		metric.CycleAt = time.Now()
+	}
+
+	query := `
+		INSERT INTO orchestrator_metrics (cycle_at, duration_ms, tasks_processed, subagents_spawned, escalations_created, trace_id)
+		VALUES (?, ?, ?, ?, ?, ?)
+	`
+	result, err := m.db.Exec(query,
+		metric.CycleAt.UTC().Format("2006-01-02 15:04:05"),
+		metric.DurationMs,
+		metric.TasksProcessed,
+		metric.SubagentsSpawned,
+		metric.EscalationsCreated,
+		nullString(metric.TraceID),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to store orchestrator metric: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("failed to get orchestrator metric ID: %w", err)
+	}
+	metric.ID = id
+	return nil
+}
+
+// GetOrchestratorMetricHistory returns per-day aggregates for the last days
+// days (including today), oldest first. Days without cycles are omitted.
+func (m *SQLiteMemoryDB) GetOrchestratorMetricHistory(days int) ([]*OrchestratorDailyStats, error) {
+	if days <= 0 {
+		days = 7
+	}
+	since := time.Now().UTC().AddDate(0, 0, -(days - 1)).Format("2006-01-02")
+
+	query := `
+		SELECT date(cycle_at) AS day,
+		       COUNT(*),
+		       SUM(tasks_processed),
+		       SUM(subagents_spawned),
+		       SUM(escalations_created),
+		       AVG(duration_ms)
+		FROM orchestrator_metrics
+		WHERE date(cycle_at) >= ?
+		GROUP BY day
+		ORDER BY day
+	`
+	rows, err := m.db.Query(query, since)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query orchestrator metrics: %w", err)
+	}
+	defer rows.Close()
+
+	var history []*OrchestratorDailyStats
+	for rows.Next() {
+		s := &OrchestratorDailyStats{}
+		if err := rows.Scan(&s.Day, &s.Cycles, &s.TasksProcessed, &s.SubagentsSpawned, &s.EscalationsCreated, &s.AvgDurationMs); err != nil {
+			return nil, fmt.Errorf("failed to scan orchestrator metrics: %w", err)
+		}
+		history = append(history, s)
+	}
+	return history, rows.Err()
+}
@@ -0,0 +1,49 @@
+package memory
+
+import (
+	"testing"
+	"time"
+)
+
+func TestOrchestratorMetricHistory(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	now := time.Now().UTC()
+	metrics := []*OrchestratorMetric{
+		{CycleAt: now, DurationMs: 100, TasksProcessed: 2, SubagentsSpawned: 1, EscalationsCreated: 0},
+		{CycleAt: now, DurationMs: 300, TasksProcessed: 1, SubagentsSpawned: 2, EscalationsCreated: 1},
+		{CycleAt: now.AddDate(0, 0, -1), DurationMs: 50, TasksProcessed: 1},
+		{CycleAt: now.AddDate(0, 0, -30), DurationMs: 999, SubagentsSpawned: 9},
+	}
+	for _, m := range metrics {
+		if err := db.StoreOrchestratorMetric(m); err != nil {
+			t.Fatalf("StoreOrchestratorMetric failed: %v", err)
+		}
+		if m.ID == 0 {
+			t.Error("Expected metric ID to be set")
+		}
+	}
+
+	history, err := db.GetOrchestratorMetricHistory(7)
+	if err != nil {
+		t.Fatalf("GetOrchestratorMetricHistory failed: %v", err)
+	}
+	if len(history) != 2 {
+		t.Fatalf("Expected 2 days of history, got %d: %+v", len(history), history)
+	}
+
+	yesterday, today := history[0], history[1]
+	if yesterday.Day != now.AddDate(0, 0, -1).Format("2006-01-02") || yesterday.Cycles != 1 {
+		t.Errorf("Unexpected first day: %+v", yesterday)
+	}
+	if today.Day != now.Format("2006-01-02") {
+		t.Errorf("Expected today last, got %s", today.Day)
+	}
+	if today.Cycles != 2 || today.SubagentsSpawned != 3 || today.EscalationsCreated != 1 || today.TasksProcessed != 3 {
+		t.Errorf("Unexpected today aggregates: %+v", today)
+	}
+	if today.AvgDurationMs != 200 {
+		t.Errorf("Expected avg duration 200, got %f", today.AvgDurationMs)
+	}
+}
@@ -0,0 +1,62 @@
+package memory
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// QualityHistoryThreshold is how far an agent's quality_score must move
+// before UpdateQualityScore records a history snapshot
+const QualityHistoryThreshold = 0.5
+
+// QualityScoreSnapshot is an agent's quality score at a point in time
+type QualityScoreSnapshot struct {
+	AgentID      string    `json:"agent_id"`
+	SnapshotAt   time.Time `json:"snapshot_at"`
+	QualityScore float64   `json:"quality_score"`
+	TotalReviews int       `json:"total_reviews"`
+	ApprovalRate float64   `json:"approval_rate"`
+}
+
+// recordQualitySnapshot adds score's current values to quality_score_history
+func recordQualitySnapshot(tx *sql.Tx, score *AgentQualityScore) error {
+	_, err := tx.Exec(`
+		INSERT INTO quality_score_history (agent_id, snapshot_at, quality_score, total_reviews, approval_rate)
+		VALUES (?, ?, ?, ?, ?)
+	`, score.AgentID, time.Now().UTC().Format("2006-01-02 15:04:05"), score.QualityScore, score.TotalReviews, score.ApprovalRate)
+	if err != nil {
+		return fmt.Errorf("failed to record quality score history: %w", err)
+	}
+	return nil
+}
+
+// GetQualityScoreHistory returns an agent's quality score snapshots from the
+// last days days, oldest first
+func (m *SQLiteMemoryDB) GetQualityScoreHistory(agentID string, days int) ([]*QualityScoreSnapshot, error) {
+	if days <= 0 {
+		days = 30
+	}
+	since := time.Now().UTC().AddDate(0, 0, -days).Format("2006-01-02 15:04:05")
+
+	rows, err := m.db.Query(`
+		SELECT agent_id, snapshot_at, quality_score, total_reviews, approval_rate
+		FROM quality_score_history
+		WHERE agent_id = ? AND snapshot_at >= ?
+		ORDER BY snapshot_at, id
+	`, agentID, since)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query quality score history: %w", err)
+	}
+	defer rows.Close()
+
+	history := []*QualityScoreSnapshot{}
+	for rows.Next() {
+		s := &QualityScoreSnapshot{}
+		if err := rows.Scan(&s.AgentID, &s.SnapshotAt, &s.QualityScore, &s.TotalReviews, &s.ApprovalRate); err != nil {
+			return nil, fmt.Errorf("failed to scan quality score snapshot: %w", err)
+		}
+		history = append(history, s)
+	}
+	return history, rows.Err()
+}
@@ -0,0 +1,41 @@
+package memory
+
+import "testing"
+
+func TestQualityScoreHistory(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	score, err := db.GetOrCreateQualityScore("reviewer-01", "reviewer")
+	if err != nil {
+		t.Fatalf("GetOrCreateQualityScore failed: %v", err)
+	}
+
+	// 50 -> 50.3 is below the threshold; 50.3 -> 55 and 55 -> 52 are recorded
+	for _, value := range []float64{50.3, 55, 52} {
+		score.QualityScore = value
+		score.TotalReviews++
+		if err := db.UpdateQualityScore(score); err != nil {
+			t.Fatalf("UpdateQualityScore(%v) failed: %v", value, err)
+		}
+	}
+
+	history, err := db.GetQualityScoreHistory("reviewer-01", 30)
+	if err != nil {
+		t.Fatalf("GetQualityScoreHistory failed: %v", err)
+	}
+	if len(history) != 2 {
+		t.Fatalf("Expected 2 snapshots, got %d: %+v", len(history), history)
+	}
+	if history[0].QualityScore != 55 || history[0].TotalReviews != 2 || history[1].QualityScore != 52 {
+		t.Errorf("Unexpected history: %+v, %+v", history[0], history[1])
+	}
+
+	other, err := db.GetQualityScoreHistory("reviewer-02", 30)
+	if err != nil {
+		t.Fatalf("GetQualityScoreHistory failed: %v", err)
+	}
+	if other == nil || len(other) != 0 {
+		t.Errorf("Expected empty history for unknown agent, got %#v", other)
+	}
+}
@@ -4,10 +4,14 @@ import (
 	"context"
 	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"time"
 )
 
+// ErrEnvironmentNotFound is returned when a recon environment does not exist
+var ErrEnvironmentNotFound = errors.New("environment not found")
+
 // ReconRepository provides methods for managing reconnaissance data
 type ReconRepository interface {
 	// Environment operations
@@ -15,6 +19,8 @@ type ReconRepository interface {
 	GetEnvironment(ctx context.Context, id string) (*Environment, error)
 	ListEnvironments(ctx context.Context) ([]*Environment, error)
 	UpdateEnvironmentLastScan(ctx context.Context, envID string) error
+	GetEnvironmentPolicy(ctx context.Context, envID string) (*SecurityPolicy, error)
+	SetEnvironmentPolicy(ctx context.Context, envID string, policy SecurityPolicy) error
 
 	// Scan operations
 	RecordScan(ctx context.Context, scan *ReconScan) error
@@ -23,10 +29,12 @@ type ReconRepository interface {
 	GetLatestScan(ctx context.Context, envID string) (*ReconScan, error)
 	GetScan(ctx context.Context, scanID string) (*ReconScan, error)
 	GetScans(ctx context.Context, filter ScanFilter) ([]*ReconScan, error)
+	ReportScanProgress(ctx context.Context, scanID string, filesScanned int, currentFile string) error
+	GetScanProgress(ctx context.Context, scanID string) (*ScanProgress, error)
 
 	// Finding operations
 	SaveFinding(ctx context.Context, finding *ReconFinding) error
-	SaveFindings(ctx context.Context, findings []*ReconFinding) error
+	SaveFindings(ctx context.Context, findings []*ReconFinding, strategy DuplicateStrategy) error
 	GetFinding(ctx context.Context, id string) (*ReconFinding, error)
 	GetFindingsByEnvironment(ctx context.Context, envID string) ([]*ReconFinding, error)
 	GetFindingsBySeverity(ctx context.Context, severity string) ([]*ReconFinding, error)
@@ -50,6 +58,10 @@ type Environment struct {
 	Metadata     map[string]interface{} // Additional info as JSON
 	RegisteredAt time.Time
 	LastScanned  *time.Time
+
+	// SecurityPolicy is the stored policy, nil if the environment type's
+	// default applies (see GetEnvironmentPolicy)
+	SecurityPolicy *SecurityPolicy
 }
 
 // ReconScan represents a reconnaissance scan operation
@@ -83,6 +95,20 @@ type ScanSummary struct {
 	LowCount       int      `json:"low_count"`
 }
 
+// ScanProgress is the latest progress report of a scan
+type ScanProgress struct {
+	ScanID       string     `json:"scan_id"`
+	Status       string     `json:"status"` // 'running', 'completed', 'failed'
+	FilesScanned int        `json:"files_scanned"`
+	CurrentFile  string     `json:"current_file,omitempty"`
+	UpdatedAt    *time.Time `json:"updated_at,omitempty"`
+}
+
+// Done reports whether the scan has finished
+func (p *ScanProgress) Done() bool {
+	return p.Status == "completed" || p.Status == "failed"
+}
+
 // ReconFinding represents a single finding from reconnaissance
 type ReconFinding struct {
 	ID              string
@@ -138,46 +164,58 @@ type FindingFilter struct {
 
 // Environment operations
 
+// RegisterEnvironment stores env, updating it if already registered. A nil
+// SecurityPolicy keeps any policy already stored.
 func (m *SQLiteMemoryDB) RegisterEnvironment(ctx context.Context, env *Environment) error {
 	metadataJSON, err := json.Marshal(env.Metadata)
 	if err != nil {
 		return fmt.Errorf("failed to marshal metadata: %w", err)
 	}
+	if env.SecurityPolicy != nil {
+		if err := env.SecurityPolicy.Validate(); err != nil {
+			return err
+		}
+	}
+	policyJSON, err := encodeSecurityPolicy(env.SecurityPolicy)
+	if err != nil {
+		return err
+	}
 
 	_, err = m.db.ExecContext(ctx, `
-		INSERT INTO environments (id, name, description, env_type, base_path, git_remote, metadata)
-		VALUES (?, ?, ?, ?, ?, ?, ?)
+		INSERT INTO environments (id, name, description, env_type, base_path, git_remote, metadata, security_policy)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
 		ON CONFLICT(id) DO UPDATE SET
 			name = excluded.name,
 			description = excluded.description,
 			env_type = excluded.env_type,
 			base_path = excluded.base_path,
 			git_remote = excluded.git_remote,
-			metadata = excluded.metadata`,
+			metadata = excluded.metadata,
+			security_policy = COALESCE(excluded.security_policy, environments.security_policy)`,
 		env.ID, env.Name, env.Description, env.EnvType,
-		nullString(env.BasePath), nullString(env.GitRemote), string(metadataJSON),
+		nullString(env.BasePath), nullString(env.GitRemote), string(metadataJSON), policyJSON,
 	)
 	return err
 }
 
 func (m *SQLiteMemoryDB) GetEnvironment(ctx context.Context, id string) (*Environment, error) {
 	var env Environment
-	var basePath, gitRemote, metadataJSON sql.NullString
+	var basePath, gitRemote, metadataJSON, policyJSON sql.NullString
 	var lastScanned sql.NullTime
 
 	err := m.db.QueryRowContext(ctx, `
-		SELECT id, name, description, env_type, base_path, git_remote, metadata, registered_at, last_scanned
+		SELECT id, name, description, env_type, base_path, git_remote, metadata, registered_at, last_scanned, security_policy
 		FROM environments
 		WHERE id = ?`,
 		id,
 	).Scan(
 		&env.ID, &env.Name, &env.Description, &env.EnvType,
-		&basePath, &gitRemote, &metadataJSON, &env.RegisteredAt, &lastScanned,
+		&basePath, &gitRemote, &metadataJSON, &env.RegisteredAt, &lastScanned, &policyJSON,
 	)
 
 	if err != nil {
 		if err == sql.ErrNoRows {
-			return nil, fmt.Errorf("environment not found: %s", id)
+			return nil, fmt.Errorf("%w: %s", ErrEnvironmentNotFound, id)
 		}
 		return nil, fmt.Errorf("failed to get environment: %w", err)
 	}
@@ -193,13 +231,16 @@ func (m *SQLiteMemoryDB) GetEnvironment(ctx context.Context, id string) (*Enviro
 			return nil, fmt.Errorf("failed to unmarshal metadata: %w", err)
 		}
 	}
+	if env.SecurityPolicy, err = decodeSecurityPolicy(policyJSON); err != nil {
+		return nil, err
+	}
 
 	return &env, nil
 }
 
 func (m *SQLiteMemoryDB) ListEnvironments(ctx context.Context) ([]*Environment, error) {
 	rows, err := m.db.QueryContext(ctx, `
-		SELECT id, name, description, env_type, base_path, git_remote, metadata, registered_at, last_scanned
+		SELECT id, name, description, env_type, base_path, git_remote, metadata, registered_at, last_scanned, security_policy
 		FROM environments
 		ORDER BY registered_at DESC`)
 	if err != nil {
@@ -210,12 +251,12 @@ func (m *SQLiteMemoryDB) ListEnvironments(ctx context.Context) ([]*Environment,
 	var environments []*Environment
 	for rows.Next() {
 		var env Environment
-		var basePath, gitRemote, metadataJSON sql.NullString
+		var basePath, gitRemote, metadataJSON, policyJSON sql.NullString
 		var lastScanned sql.NullTime
 
 		err := rows.Scan(
 			&env.ID, &env.Name, &env.Description, &env.EnvType,
-			&basePath, &gitRemote, &metadataJSON, &env.RegisteredAt, &lastScanned,
+			&basePath, &gitRemote, &metadataJSON, &env.RegisteredAt, &lastScanned, &policyJSON,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan environment: %w", err)
@@ -232,6 +273,9 @@ func (m *SQLiteMemoryDB) ListEnvironments(ctx context.Context) ([]*Environment,
 				return nil, fmt.Errorf("failed to unmarshal metadata: %w", err)
 			}
 		}
+		if env.SecurityPolicy, err = decodeSecurityPolicy(policyJSON); err != nil {
+			return nil, err
+		}
 
 		environments = append(environments, &env)
 	}
@@ -270,6 +314,7 @@ func (m *SQLiteMemoryDB) RecordScan(ctx context.Context, scan *ReconScan) error
 		 languages_detected, frameworks_detected, test_coverage_percent, security_score)
 		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 		ON CONFLICT(id) DO UPDATE SET
+			agent_id = excluded.agent_id,
 			status = excluded.status,
 			summary = excluded.summary,
 			total_files_scanned = excluded.total_files_scanned,
@@ -300,6 +345,51 @@ func (m *SQLiteMemoryDB) UpdateScanStatus(ctx context.Context, scanID, status st
 	return err
 }
 
+// ReportScanProgress records how many files a running scan has covered
+func (m *SQLiteMemoryDB) ReportScanProgress(ctx context.Context, scanID string, filesScanned int, currentFile string) error {
+	result, err := m.db.ExecContext(ctx, `
+		UPDATE recon_scans
+		SET progress_files_scanned = ?,
+		    progress_current_file = ?,
+		    progress_updated_at = CURRENT_TIMESTAMP
+		WHERE id = ?`,
+		filesScanned, nullString(currentFile), scanID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to report scan progress: %w", err)
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return fmt.Errorf("scan not found: %s", scanID)
+	}
+	return nil
+}
+
+// GetScanProgress returns the latest progress of a scan
+func (m *SQLiteMemoryDB) GetScanProgress(ctx context.Context, scanID string) (*ScanProgress, error) {
+	progress := &ScanProgress{ScanID: scanID}
+	var currentFile sql.NullString
+	var updatedAt sql.NullTime
+
+	err := m.db.QueryRowContext(ctx, `
+		SELECT status, progress_files_scanned, progress_current_file, progress_updated_at
+		FROM recon_scans
+		WHERE id = ?`,
+		scanID,
+	).Scan(&progress.Status, &progress.FilesScanned, &currentFile, &updatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get scan progress: %w", err)
+	}
+
+	progress.CurrentFile = currentFile.String
+	if updatedAt.Valid {
+		progress.UpdatedAt = &updatedAt.Time
+	}
+	return progress, nil
+}
+
 func (m *SQLiteMemoryDB) CompleteScan(ctx context.Context, scanID string, summary *ScanSummary) error {
 	summaryJSON, err := json.Marshal(summary)
 	if err != nil {
@@ -477,34 +567,55 @@ func (m *SQLiteMemoryDB) GetScans(ctx context.Context, filter ScanFilter) ([]*Re
 
 // Finding operations
 
+// SaveFinding stores a single finding, overwriting any stored finding with
+// the same ID
 func (m *SQLiteMemoryDB) SaveFinding(ctx context.Context, finding *ReconFinding) error {
-	return m.SaveFindings(ctx, []*ReconFinding{finding})
+	return m.SaveFindings(ctx, []*ReconFinding{finding}, DuplicateReplaceAll)
 }
 
-func (m *SQLiteMemoryDB) SaveFindings(ctx context.Context, findings []*ReconFinding) error {
-	return m.withTx(func(tx *sql.Tx) error {
+// SaveFindings stores findings in one transaction. Findings whose ID is
+// already stored are handled according to strategy. Findings more severe than
+// their environment's MaxFindingSeverity are saved as ignored.
+func (m *SQLiteMemoryDB) SaveFindings(ctx context.Context, findings []*ReconFinding, strategy DuplicateStrategy) error {
+	onConflict, err := strategy.conflictClause()
+	if err != nil {
+		return err
+	}
+
+	err = m.withTx(func(tx *sql.Tx) error {
 		stmt, err := tx.PrepareContext(ctx, `
 			INSERT INTO recon_findings
 			(id, scan_id, env_id, finding_type, severity, title, description, location,
 			 recommendation, status, metadata)
 			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
-			ON CONFLICT(id) DO UPDATE SET
-				title = excluded.title,
-				description = excluded.description,
-				recommendation = excluded.recommendation,
-				updated_at = CURRENT_TIMESTAMP`)
+			`+onConflict)
 		if err != nil {
 			return fmt.Errorf("failed to prepare finding insert: %w", err)
 		}
 		defer stmt.Close()
 
+		policies := make(map[string]*SecurityPolicy)
 		for _, finding := range findings {
+			policy, ok := policies[finding.EnvID]
+			if !ok {
+				// Findings for unregistered environments are saved unfiltered
+				policy, err = environmentPolicy(ctx, tx.QueryRowContext, finding.EnvID)
+				if err != nil && !errors.Is(err, ErrEnvironmentNotFound) {
+					return err
+				}
+				policies[finding.EnvID] = policy
+			}
+			status := finding.Status
+			if policy != nil && policy.Suppresses(finding.Severity) {
+				status = "ignored"
+			}
+
 			metadataJSON, _ := json.Marshal(finding.Metadata)
 			_, err := stmt.ExecContext(ctx,
 				finding.ID, finding.ScanID, finding.EnvID, finding.FindingType,
 				finding.Severity, finding.Title, finding.Description,
 				nullString(finding.Location), nullString(finding.Recommendation),
-				finding.Status, nullString(string(metadataJSON)),
+				status, nullString(string(metadataJSON)),
 			)
 			if err != nil {
 				return fmt.Errorf("failed to insert finding %s: %w", finding.ID, err)
@@ -513,6 +624,11 @@ func (m *SQLiteMemoryDB) SaveFindings(ctx context.Context, findings []*ReconFind
 
 		return nil
 	})
+	if err != nil {
+		// May wrap ErrRollbackFailed alongside the insert error
+		return fmt.Errorf("failed to save findings: %w", err)
+	}
+	return nil
 }
 
 func (m *SQLiteMemoryDB) GetFinding(ctx context.Context, id string) (*ReconFinding, error) {
@@ -133,6 +133,27 @@ func testScanOperations(t *testing.T, db MemoryDB, ctx context.Context) {
 		t.Errorf("Expected agent Snake001, got %s", retrieved.AgentID)
 	}
 
+	// Report progress while running
+	if err := db.(*SQLiteMemoryDB).ReportScanProgress(ctx, "SCAN-001", 42, "internal/server/server.go"); err != nil {
+		t.Fatalf("Failed to report scan progress: %v", err)
+	}
+	progress, err := db.(*SQLiteMemoryDB).GetScanProgress(ctx, "SCAN-001")
+	if err != nil {
+		t.Fatalf("Failed to get scan progress: %v", err)
+	}
+	if progress == nil || progress.FilesScanned != 42 || progress.CurrentFile != "internal/server/server.go" || progress.UpdatedAt == nil {
+		t.Errorf("Unexpected scan progress: %+v", progress)
+	}
+	if progress != nil && progress.Done() {
+		t.Error("Expected running scan not to be done")
+	}
+	if err := db.(*SQLiteMemoryDB).ReportScanProgress(ctx, "SCAN-MISSING", 1, ""); err == nil {
+		t.Error("Expected error reporting progress for unknown scan")
+	}
+	if missing, err := db.(*SQLiteMemoryDB).GetScanProgress(ctx, "SCAN-MISSING"); err != nil || missing != nil {
+		t.Errorf("Expected nil progress for unknown scan, got %+v (%v)", missing, err)
+	}
+
 	// Update status
 	if err := db.(*SQLiteMemoryDB).UpdateScanStatus(ctx, "SCAN-001", "completed"); err != nil {
 		t.Fatalf("Failed to update scan status: %v", err)
@@ -251,7 +272,7 @@ func testFindingOperations(t *testing.T, db MemoryDB, ctx context.Context) {
 		},
 	}
 
-	if err := db.(*SQLiteMemoryDB).SaveFindings(ctx, findings); err != nil {
+	if err := db.(*SQLiteMemoryDB).SaveFindings(ctx, findings, DefaultDuplicateStrategy); err != nil {
 		t.Fatalf("Failed to save findings: %v", err)
 	}
 
@@ -434,7 +455,7 @@ func TestLayerManager(t *testing.T) {
 			Status:      "open",
 		},
 	}
-	db.(*SQLiteMemoryDB).SaveFindings(ctx, findings)
+	db.(*SQLiteMemoryDB).SaveFindings(ctx, findings, DefaultDuplicateStrategy)
 
 	// Test layer manager
 	lm := NewLayerManager(db.(*SQLiteMemoryDB), tmpDir)
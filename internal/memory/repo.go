@@ -1,12 +1,15 @@
 package memory
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"strconv"
 	"strings"
+	"time"
 )
 
 // DiscoverRepo detects a repository and creates/updates its entry
@@ -271,8 +274,73 @@ func (m *SQLiteMemoryDB) GetRepoFile(repoID, filePath string) (*RepoFile, error)
 	return &file, nil
 }
 
+// DetectRepoChanges compares git history against the repo's last scan time.
+// A repo that has never been scanned always needs a rescan. When new commits
+// are found the repo is also flagged needs_rescan so later lookups agree.
+func (m *SQLiteMemoryDB) DetectRepoChanges(ctx context.Context, repoID string) (*RepoChangeSummary, error) {
+	repo, err := m.GetRepo(repoID)
+	if err != nil {
+		return nil, err
+	}
+
+	summary := &RepoChangeSummary{
+		RepoID:       repoID,
+		LastScanned:  repo.LastScanned,
+		ChangedFiles: []string{},
+	}
+	if repo.LastScanned.IsZero() {
+		summary.NeedsRescan = true
+		return summary, nil
+	}
+
+	since := "--since=" + repo.LastScanned.UTC().Format(time.RFC3339)
+
+	files, err := runGit(ctx, repo.BasePath, "log", since, "--name-only", "--format=")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read git log: %w", err)
+	}
+	seen := make(map[string]bool)
+	for _, line := range strings.Split(files, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || seen[line] {
+			continue
+		}
+		seen[line] = true
+		summary.ChangedFiles = append(summary.ChangedFiles, line)
+	}
+
+	count, err := runGit(ctx, repo.BasePath, "rev-list", "--count", since, "HEAD")
+	if err != nil {
+		return nil, fmt.Errorf("failed to count commits: %w", err)
+	}
+	summary.CommitCount, err = strconv.Atoi(strings.TrimSpace(count))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse commit count %q: %w", count, err)
+	}
+
+	summary.NeedsRescan = summary.CommitCount > 0
+	if summary.NeedsRescan && !repo.NeedsRescan {
+		if err := m.SetRepoRescan(repoID, true); err != nil {
+			return nil, fmt.Errorf("failed to mark repo for rescan: %w", err)
+		}
+	}
+
+	return summary, nil
+}
+
 // Helper functions
 
+// runGit runs a git command in dir and returns its stdout
+func runGit(ctx context.Context, dir string, args ...string) (string, error) {
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Dir = dir
+	output, err := cmd.Output()
+	if err != nil {
+		return "", err
+	}
+	return string(output), nil
+}
+
 // getGitRemote attempts to get the git remote URL for a repository
 func getGitRemote(basePath string) string {
 	cmd := exec.Command("git", "config", "--get", "remote.origin.url")
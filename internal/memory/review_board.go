@@ -3,7 +3,14 @@ package memory
 import (
 	"database/sql"
 	"fmt"
+	"math"
+	"os"
+	"strings"
 	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/CLIAIMONITOR/internal/logger"
 )
 
 // ReviewBoard represents a multi-reviewer review session
@@ -86,13 +93,21 @@ type AgentQualityScore struct {
 	UpdatedAt              time.Time
 }
 
+// AgentQualityScoreWithRank is a leaderboard entry with ranks computed by SQLite
+type AgentQualityScoreWithRank struct {
+	AgentQualityScore
+	Rank           int     // 1-based rank within the agent's role
+	PercentileRank float64 // PERCENT_RANK within the role: 0.0 = best, 1.0 = worst
+	TopNPercent    bool    // True if the agent is in the top 10% of its role
+}
+
 // DefectCategory represents a defect classification
 type DefectCategory struct {
-	Code            string
-	Name            string
-	CategoryType    string
-	Description     string
-	DefaultSeverity string
+	Code            string `yaml:"code"`
+	Name            string `yaml:"name"`
+	CategoryType    string `yaml:"category_type"`
+	Description     string `yaml:"description"`
+	DefaultSeverity string `yaml:"default_severity"`
 }
 
 // ConsensusResult represents the aggregated review decision
@@ -110,8 +125,13 @@ type ConsensusResult struct {
 	AggregatedFeedback string
 }
 
-// CreateReviewBoard creates a new review board
+// CreateReviewBoard creates a new review board. ReviewerCount must be
+// between 1 and the configured MaxReviewersPerBoard.
 func (m *SQLiteMemoryDB) CreateReviewBoard(board *ReviewBoard) error {
+	if err := m.validateReviewerCount(board.ReviewerCount); err != nil {
+		return err
+	}
+
 	query := `
 		INSERT INTO review_boards (
 			assignment_id, reviewer_count, status, complexity_score, risk_level,
@@ -163,7 +183,7 @@ func (m *SQLiteMemoryDB) GetReviewBoard(id int64) (*ReviewBoard, error) {
 		&board.CreatedAt, &startedAt, &completedAt,
 	)
 	if err == sql.ErrNoRows {
-		return nil, fmt.Errorf("review board not found: %d", id)
+		return nil, fmt.Errorf("%w: %d", ErrReviewBoardNotFound, id)
 	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to get review board: %w", err)
@@ -232,8 +252,51 @@ func (m *SQLiteMemoryDB) GetReviewBoardByAssignment(assignmentID int64) (*Review
 	return &board, nil
 }
 
-// UpdateReviewBoard updates an existing review board
+// SetReviewCompletedHook registers a callback that fires after UpdateReviewBoard
+// moves a board into completed status. Passing nil disables the hook.
+func (m *SQLiteMemoryDB) SetReviewCompletedHook(hook func(board *ReviewBoard)) {
+	m.reviewCompletedHook = hook
+}
+
+// CalculateRiskLevel derives a board's risk level from its defect severities:
+// critical if any defect is critical, high for 3+ high defects, medium for
+// 5+ medium defects, otherwise low. Migration 017's triggers apply the same
+// rules in SQL.
+func CalculateRiskLevel(defects []*ReviewDefect) string {
+	counts := make(map[string]int)
+	for _, d := range defects {
+		counts[d.Severity]++
+	}
+
+	switch {
+	case counts["critical"] > 0:
+		return "critical"
+	case counts["high"] >= 3:
+		return "high"
+	case counts["medium"] >= 5:
+		return "medium"
+	default:
+		return "low"
+	}
+}
+
+// UpdateReviewBoard updates an existing review board. Once the board has
+// defects, RiskLevel is recalculated from them rather than taken from board.
 func (m *SQLiteMemoryDB) UpdateReviewBoard(board *ReviewBoard) error {
+	// Capture the previous status so the completion hook fires only once
+	var previousStatus string
+	if err := m.db.QueryRow(`SELECT status FROM review_boards WHERE id = ?`, board.ID).Scan(&previousStatus); err != nil && err != sql.ErrNoRows {
+		return fmt.Errorf("failed to get review board status: %w", err)
+	}
+
+	defects, err := m.GetBoardDefects(board.ID)
+	if err != nil {
+		return fmt.Errorf("failed to get defects for risk level: %w", err)
+	}
+	if len(defects) > 0 {
+		board.RiskLevel = CalculateRiskLevel(defects)
+	}
+
 	query := `
 		UPDATE review_boards
 		SET reviewer_count = ?, status = ?, complexity_score = ?, risk_level = ?,
@@ -241,7 +304,7 @@ func (m *SQLiteMemoryDB) UpdateReviewBoard(board *ReviewBoard) error {
 		WHERE id = ?
 	`
 
-	_, err := m.db.Exec(
+	_, err = m.db.Exec(
 		query,
 		board.ReviewerCount,
 		board.Status,
@@ -257,6 +320,10 @@ func (m *SQLiteMemoryDB) UpdateReviewBoard(board *ReviewBoard) error {
 		return fmt.Errorf("failed to update review board: %w", err)
 	}
 
+	if board.Status == "completed" && previousStatus != "completed" && m.reviewCompletedHook != nil {
+		m.reviewCompletedHook(board)
+	}
+
 	return nil
 }
 
@@ -419,13 +486,15 @@ func (m *SQLiteMemoryDB) GetDefectsByReviewer(boardID int64, reviewerID string)
 	return defects, rows.Err()
 }
 
-// CreateReviewerVote creates a new reviewer vote
+// CreateReviewerVote records a reviewer's verdict. A slot the reviewer
+// locked with LockReviewerSlot is completed in place. Returns
+// ErrAlreadyVoted if the reviewer has already voted on the board.
 func (m *SQLiteMemoryDB) CreateReviewerVote(vote *ReviewerVote) error {
 	query := `
 		INSERT INTO reviewer_votes (
 			board_id, reviewer_id, approved, confidence_score, defects_found,
-			review_time_seconds, tokens_used, started_at, completed_at
-		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+			review_time_seconds, tokens_used, started_at, completed_at, status
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, 'completed')
 	`
 
 	result, err := m.db.Exec(
@@ -440,6 +509,9 @@ func (m *SQLiteMemoryDB) CreateReviewerVote(vote *ReviewerVote) error {
 		vote.StartedAt,
 		nullTime(vote.CompletedAt),
 	)
+	if isUniqueViolation(err) {
+		return m.completeReviewerSlot(vote)
+	}
 	if err != nil {
 		return fmt.Errorf("failed to create reviewer vote: %w", err)
 	}
@@ -459,7 +531,7 @@ func (m *SQLiteMemoryDB) GetReviewerVotes(boardID int64) ([]*ReviewerVote, error
 		SELECT id, board_id, reviewer_id, approved, confidence_score, defects_found,
 		       review_time_seconds, tokens_used, started_at, completed_at
 		FROM reviewer_votes
-		WHERE board_id = ?
+		WHERE board_id = ? AND status = 'completed'
 		ORDER BY completed_at ASC
 	`
 
@@ -556,8 +628,24 @@ func (m *SQLiteMemoryDB) GetOrCreateQualityScore(agentID, role string) (*AgentQu
 	return &score, nil
 }
 
-// UpdateQualityScore updates an agent quality score
+// UpdateQualityScore updates an agent quality score, recording a history
+// snapshot when quality_score moves by more than QualityHistoryThreshold
 func (m *SQLiteMemoryDB) UpdateQualityScore(score *AgentQualityScore) error {
+	tx, err := m.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin quality score update: %w", err)
+	}
+	defer tx.Rollback()
+
+	var previous float64
+	err = tx.QueryRow(`SELECT quality_score FROM agent_quality_scores WHERE id = ?`, score.ID).Scan(&previous)
+	if err == sql.ErrNoRows {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read quality score: %w", err)
+	}
+
 	query := `
 		UPDATE agent_quality_scores
 		SET total_submissions = ?, approved_first_try = ?, total_approvals = ?,
@@ -570,7 +658,7 @@ func (m *SQLiteMemoryDB) UpdateQualityScore(score *AgentQualityScore) error {
 		WHERE id = ?
 	`
 
-	_, err := m.db.Exec(
+	_, err = tx.Exec(
 		query,
 		score.TotalSubmissions, score.ApprovedFirstTry, score.TotalApprovals,
 		score.TotalReviewCycles, score.TotalDefectsReceived, score.CriticalDefectsReceived,
@@ -584,7 +672,13 @@ func (m *SQLiteMemoryDB) UpdateQualityScore(score *AgentQualityScore) error {
 		return fmt.Errorf("failed to update quality score: %w", err)
 	}
 
-	return nil
+	if math.Abs(score.QualityScore-previous) > QualityHistoryThreshold {
+		if err := recordQualitySnapshot(tx, score); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
 }
 
 // GetAgentLeaderboard retrieves top agents by quality score
@@ -647,6 +741,55 @@ func (m *SQLiteMemoryDB) GetAgentLeaderboard(role string, limit int) ([]*AgentQu
 	return scores, rows.Err()
 }
 
+// GetLeaderboardWithRanks retrieves top agents by quality score with rank and
+// percentile computed via window functions, partitioned by role
+func (m *SQLiteMemoryDB) GetLeaderboardWithRanks(role string, limit int) ([]*AgentQualityScoreWithRank, error) {
+	query := `
+		SELECT id, agent_id, role, total_submissions, approved_first_try, total_approvals,
+		       total_review_cycles, total_defects_received, critical_defects_received,
+		       total_reviews, defects_found, true_positives, false_positives, critical_finds,
+		       total_tokens_used, total_cost, value_delivered, approval_rate, first_pass_rate,
+		       avg_review_cycles, defect_density, detection_accuracy, defect_find_rate,
+		       cost_efficiency, quality_score, created_at, updated_at,
+		       role_rank, percentile_rank, percentile_rank <= 0.10 AS top_n_percent
+		FROM (
+			SELECT *,
+			       RANK() OVER (PARTITION BY role ORDER BY quality_score DESC) AS role_rank,
+			       PERCENT_RANK() OVER (PARTITION BY role ORDER BY quality_score DESC) AS percentile_rank
+			FROM agent_quality_scores
+		)
+		WHERE (? = '' OR role = ?)
+		ORDER BY quality_score DESC, role_rank ASC
+		LIMIT ?
+	`
+
+	rows, err := m.db.Query(query, role, role, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query ranked leaderboard: %w", err)
+	}
+	defer rows.Close()
+
+	var scores []*AgentQualityScoreWithRank
+	for rows.Next() {
+		var s AgentQualityScoreWithRank
+		if err := rows.Scan(
+			&s.ID, &s.AgentID, &s.Role, &s.TotalSubmissions, &s.ApprovedFirstTry,
+			&s.TotalApprovals, &s.TotalReviewCycles, &s.TotalDefectsReceived,
+			&s.CriticalDefectsReceived, &s.TotalReviews, &s.DefectsFound,
+			&s.TruePositives, &s.FalsePositives, &s.CriticalFinds, &s.TotalTokensUsed,
+			&s.TotalCost, &s.ValueDelivered, &s.ApprovalRate, &s.FirstPassRate,
+			&s.AvgReviewCycles, &s.DefectDensity, &s.DetectionAccuracy, &s.DefectFindRate,
+			&s.CostEfficiency, &s.QualityScore, &s.CreatedAt, &s.UpdatedAt,
+			&s.Rank, &s.PercentileRank, &s.TopNPercent,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan ranked quality score: %w", err)
+		}
+		scores = append(scores, &s)
+	}
+
+	return scores, rows.Err()
+}
+
 // GetDefectCategories retrieves all defect categories
 func (m *SQLiteMemoryDB) GetDefectCategories() ([]*DefectCategory, error) {
 	query := `
@@ -680,6 +823,40 @@ func (m *SQLiteMemoryDB) GetDefectCategories() ([]*DefectCategory, error) {
 	return categories, rows.Err()
 }
 
+// SeedDefectCategories loads defect categories from a YAML file.
+// Uses INSERT OR IGNORE so categories already in the DB are left untouched.
+func (m *SQLiteMemoryDB) SeedDefectCategories(yamlPath string) error {
+	data, err := os.ReadFile(yamlPath)
+	if err != nil {
+		return fmt.Errorf("failed to read defect categories: %w", err)
+	}
+
+	var file struct {
+		Categories []DefectCategory `yaml:"categories"`
+	}
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return fmt.Errorf("failed to parse defect categories: %w", err)
+	}
+
+	return m.withTx(func(tx *sql.Tx) error {
+		for _, c := range file.Categories {
+			if c.Code == "" || c.Name == "" || c.CategoryType == "" {
+				return fmt.Errorf("defect category missing code, name or category_type: %+v", c)
+			}
+			if c.DefaultSeverity == "" {
+				c.DefaultSeverity = "medium"
+			}
+			if _, err := tx.Exec(`
+				INSERT OR IGNORE INTO defect_categories (code, name, category_type, description, default_severity)
+				VALUES (?, ?, ?, ?, ?)
+			`, c.Code, c.Name, c.CategoryType, c.Description, c.DefaultSeverity); err != nil {
+				return fmt.Errorf("failed to insert defect category %s: %w", c.Code, err)
+			}
+		}
+		return nil
+	})
+}
+
 // CalculateConsensus analyzes reviewer votes and defects to determine consensus
 func (m *SQLiteMemoryDB) CalculateConsensus(boardID int64) (*ConsensusResult, error) {
 	// Get all votes
@@ -759,9 +936,9 @@ func (m *SQLiteMemoryDB) CalculateConsensus(boardID int64) (*ConsensusResult, er
 
 // UpdateQualityScoresAfterReview updates agent quality scores based on review results
 func (m *SQLiteMemoryDB) UpdateQualityScoresAfterReview(boardID int64, consensus *ConsensusResult) error {
-	return m.withTx(func(tx *sql.Tx) error {
+	var authorID string
+	err := m.withTx(func(tx *sql.Tx) error {
 		// Get assignment to find author
-		var authorID string
 		err := tx.QueryRow(`
 			SELECT ta.assigned_to
 			FROM review_boards rb
@@ -882,6 +1059,31 @@ func (m *SQLiteMemoryDB) UpdateQualityScoresAfterReview(boardID int64, consensus
 
 		return nil
 	})
+	if err != nil {
+		// May wrap ErrRollbackFailed alongside the update error
+		return fmt.Errorf("failed to update quality scores for board %d: %w", boardID, err)
+	}
+
+	// Feed model A/B experiments this review's own score, since the author's
+	// running QualityScore would count every earlier review again. Scores are
+	// already committed, so a failure here is reported but does not fail the
+	// review.
+	if err := m.recordAuthorExperimentScores(authorID, reviewQualityScore(consensus)); err != nil {
+		logger.For("experiments").Warn("failed to record experiment score", "author", authorID, "error", err)
+	}
+	return nil
+}
+
+// reviewQualityScore scores a single review on the author quality scale
+// (0-100): the author formula applied to one submission, which passed first
+// time if it was approved
+func reviewQualityScore(consensus *ConsensusResult) float64 {
+	approved := 0.0
+	if consensus.Approved {
+		approved = 1.0
+	}
+	score := approved*40 + approved*30 + (1.0-float64(consensus.TotalDefects)/10.0)*30
+	return math.Max(0, math.Min(100, score))
 }
 
 // SaveReviewReport generates and saves a review report to the documents table
@@ -934,6 +1136,11 @@ func (m *SQLiteMemoryDB) GenerateReviewReport(boardID int64) (string, error) {
 		return "", fmt.Errorf("failed to get defects: %w", err)
 	}
 
+	riskLevel := board.RiskLevel
+	if len(defects) > 0 {
+		riskLevel = CalculateRiskLevel(defects)
+	}
+
 	// Get consensus
 	consensus, err := m.CalculateConsensus(boardID)
 	if err != nil {
@@ -942,13 +1149,13 @@ func (m *SQLiteMemoryDB) GenerateReviewReport(boardID int64) (string, error) {
 
 	// Build markdown report
 	var report string
-	report += fmt.Sprintf("# Review Board #%d - Final Report\n\n", boardID)
+	report += fmt.Sprintf("# Review Board #%d - Final Report (%s risk)\n\n", boardID, strings.ToUpper(riskLevel))
 	report += fmt.Sprintf("**Status:** %s\n", board.Status)
 	report += fmt.Sprintf("**Final Verdict:** %s\n", board.FinalVerdict)
 	report += fmt.Sprintf("**Assignment ID:** %d\n", board.AssignmentID)
 	report += fmt.Sprintf("**Reviewer Count:** %d\n", board.ReviewerCount)
 	report += fmt.Sprintf("**Complexity Score:** %d\n", board.ComplexityScore)
-	report += fmt.Sprintf("**Risk Level:** %s\n\n", board.RiskLevel)
+	report += fmt.Sprintf("**Risk Level:** %s\n\n", riskLevel)
 
 	// Consensus results
 	report += "## Consensus Results\n\n"
@@ -0,0 +1,84 @@
+package memory
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Review board defaults used until SetReviewBoardConfig is called
+const (
+	DefaultMaxReviewersPerBoard = 5
+	DefaultMinReviewerScore     = 50 // New agents start at 50
+)
+
+// ErrInvalidReviewerCount is returned when a board's ReviewerCount is outside
+// 1..MaxReviewersPerBoard
+var ErrInvalidReviewerCount = errors.New("invalid reviewer count")
+
+// ReviewBoardConfig bounds review board size and who may be picked as a reviewer
+type ReviewBoardConfig struct {
+	MaxReviewersPerBoard int     // CreateReviewBoard rejects larger boards
+	MinReviewerScore     float64 // GetAvailableReviewers skips agents scoring lower
+}
+
+// DefaultReviewBoardConfig returns the review board defaults
+func DefaultReviewBoardConfig() ReviewBoardConfig {
+	return ReviewBoardConfig{
+		MaxReviewersPerBoard: DefaultMaxReviewersPerBoard,
+		MinReviewerScore:     DefaultMinReviewerScore,
+	}
+}
+
+// SetReviewBoardConfig replaces the review board limits. A non-positive
+// MaxReviewersPerBoard keeps the default.
+func (m *SQLiteMemoryDB) SetReviewBoardConfig(cfg ReviewBoardConfig) {
+	if cfg.MaxReviewersPerBoard <= 0 {
+		cfg.MaxReviewersPerBoard = DefaultMaxReviewersPerBoard
+	}
+	m.reviewBoardConfig = cfg
+}
+
+// validateReviewerCount checks a new board's ReviewerCount against the config
+func (m *SQLiteMemoryDB) validateReviewerCount(count int) error {
+	max := m.reviewBoardConfig.MaxReviewersPerBoard
+	if count < 1 || count > max {
+		return fmt.Errorf("%w: %d (must be between 1 and %d)", ErrInvalidReviewerCount, count, max)
+	}
+	return nil
+}
+
+// GetAvailableReviewers returns agent IDs with at least the configured
+// minimum quality score, best first, leaving out excludeIDs (typically the
+// author and reviewers already on the board). An empty role matches any role.
+func (m *SQLiteMemoryDB) GetAvailableReviewers(role string, excludeIDs []string) ([]string, error) {
+	query := `
+		SELECT agent_id
+		FROM agent_quality_scores
+		WHERE (? = '' OR role = ?) AND quality_score >= ?
+	`
+	args := []interface{}{role, role, m.reviewBoardConfig.MinReviewerScore}
+	if len(excludeIDs) > 0 {
+		query += ` AND agent_id NOT IN (?` + strings.Repeat(", ?", len(excludeIDs)-1) + `)`
+		for _, id := range excludeIDs {
+			args = append(args, id)
+		}
+	}
+	query += ` ORDER BY quality_score DESC, agent_id`
+
+	rows, err := m.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query available reviewers: %w", err)
+	}
+	defer rows.Close()
+
+	reviewers := []string{}
+	for rows.Next() {
+		var agentID string
+		if err := rows.Scan(&agentID); err != nil {
+			return nil, fmt.Errorf("failed to scan available reviewer: %w", err)
+		}
+		reviewers = append(reviewers, agentID)
+	}
+	return reviewers, rows.Err()
+}
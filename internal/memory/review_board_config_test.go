@@ -0,0 +1,78 @@
+package memory
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestCreateReviewBoardReviewerLimit(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	assignment := &TaskAssignment{TaskID: "task-1", AssignedTo: "sgt-green", AssignedBy: "captain", AssignmentType: "review", Status: "in_progress"}
+	if err := db.CreateAssignment(assignment); err != nil {
+		t.Fatalf("CreateAssignment failed: %v", err)
+	}
+
+	for _, count := range []int{0, DefaultMaxReviewersPerBoard + 1} {
+		board := &ReviewBoard{AssignmentID: assignment.ID, ReviewerCount: count, Status: "in_progress", RiskLevel: "low"}
+		if err := db.CreateReviewBoard(board); !errors.Is(err, ErrInvalidReviewerCount) {
+			t.Errorf("CreateReviewBoard(ReviewerCount=%d) error = %v, want ErrInvalidReviewerCount", count, err)
+		}
+	}
+
+	board := &ReviewBoard{AssignmentID: assignment.ID, ReviewerCount: DefaultMaxReviewersPerBoard, Status: "in_progress", RiskLevel: "low"}
+	if err := db.CreateReviewBoard(board); err != nil {
+		t.Fatalf("CreateReviewBoard at the limit failed: %v", err)
+	}
+
+	db.(*SQLiteMemoryDB).SetReviewBoardConfig(ReviewBoardConfig{MaxReviewersPerBoard: 7})
+	board = &ReviewBoard{AssignmentID: assignment.ID, ReviewerCount: 7, Status: "in_progress", RiskLevel: "low"}
+	if err := db.CreateReviewBoard(board); err != nil {
+		t.Errorf("CreateReviewBoard with raised limit failed: %v", err)
+	}
+}
+
+func TestGetAvailableReviewers(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	for _, s := range []struct {
+		agentID string
+		role    string
+		score   float64
+	}{
+		{"reviewer-a", "reviewer", 90},
+		{"reviewer-b", "reviewer", 70},
+		{"reviewer-c", "reviewer", 50},
+		{"reviewer-low", "reviewer", 20},
+		{"author-a", "author", 95},
+	} {
+		score, err := db.GetOrCreateQualityScore(s.agentID, s.role)
+		if err != nil {
+			t.Fatalf("GetOrCreateQualityScore failed: %v", err)
+		}
+		score.QualityScore = s.score
+		if err := db.UpdateQualityScore(score); err != nil {
+			t.Fatalf("UpdateQualityScore failed: %v", err)
+		}
+	}
+
+	got, err := db.GetAvailableReviewers("reviewer", []string{"reviewer-b"})
+	if err != nil {
+		t.Fatalf("GetAvailableReviewers failed: %v", err)
+	}
+	if want := []string{"reviewer-a", "reviewer-c"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("GetAvailableReviewers = %v, want %v", got, want)
+	}
+
+	db.(*SQLiteMemoryDB).SetReviewBoardConfig(ReviewBoardConfig{MinReviewerScore: 80})
+	got, err = db.GetAvailableReviewers("", nil)
+	if err != nil {
+		t.Fatalf("GetAvailableReviewers failed: %v", err)
+	}
+	if want := []string{"author-a", "reviewer-a"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("GetAvailableReviewers with min score 80 = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,43 @@
+package memory
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// orphanedBoardsQuery selects review boards whose assignment no longer
+// exists and which were created before the cutoff parameter
+const orphanedBoardsQuery = `
+	SELECT id FROM review_boards
+	WHERE assignment_id NOT IN (SELECT id FROM task_assignments)
+	  AND created_at < ?`
+
+// PruneOrphanedBoards deletes review boards older than olderThan whose
+// assignment no longer exists in task_assignments, together with their
+// reviewer votes and defects, in one transaction. Returns how many boards
+// were deleted.
+func (m *SQLiteMemoryDB) PruneOrphanedBoards(ctx context.Context, olderThan time.Duration) (int, error) {
+	cutoff := time.Now().Add(-olderThan).UTC().Format("2006-01-02 15:04:05")
+
+	var pruned int64
+	err := m.withTx(func(tx *sql.Tx) error {
+		for _, table := range []string{"reviewer_votes", "review_defects"} {
+			if _, err := tx.ExecContext(ctx, `DELETE FROM `+table+` WHERE board_id IN (`+orphanedBoardsQuery+`)`, cutoff); err != nil {
+				return fmt.Errorf("failed to prune orphaned %s: %w", table, err)
+			}
+		}
+
+		result, err := tx.ExecContext(ctx, `DELETE FROM review_boards WHERE id IN (`+orphanedBoardsQuery+`)`, cutoff)
+		if err != nil {
+			return fmt.Errorf("failed to prune orphaned review boards: %w", err)
+		}
+		pruned, _ = result.RowsAffected()
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return int(pruned), nil
+}
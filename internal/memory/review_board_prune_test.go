@@ -0,0 +1,71 @@
+package memory
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestPruneOrphanedBoards(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+	sqliteDB := db.(*SQLiteMemoryDB)
+
+	assignment := &TaskAssignment{TaskID: "TASK-LIVE", AssignedTo: "sgt-green", AssignedBy: "captain", AssignmentType: "review", Status: "in_progress"}
+	if err := db.CreateAssignment(assignment); err != nil {
+		t.Fatalf("CreateAssignment failed: %v", err)
+	}
+
+	newBoard := func(assignmentID int64, age time.Duration) *ReviewBoard {
+		board := &ReviewBoard{AssignmentID: assignmentID, ReviewerCount: 1, Status: "in_progress", RiskLevel: "medium"}
+		if err := db.CreateReviewBoard(board); err != nil {
+			t.Fatalf("CreateReviewBoard failed: %v", err)
+		}
+		created := time.Now().Add(-age).UTC().Format("2006-01-02 15:04:05")
+		if _, err := sqliteDB.db.Exec(`UPDATE review_boards SET created_at = ? WHERE id = ?`, created, board.ID); err != nil {
+			t.Fatalf("backdating board failed: %v", err)
+		}
+		if err := db.CreateReviewerVote(&ReviewerVote{BoardID: board.ID, ReviewerID: "r1", Approved: true, StartedAt: time.Now()}); err != nil {
+			t.Fatalf("CreateReviewerVote failed: %v", err)
+		}
+		if err := db.CreateDefect(&ReviewDefect{BoardID: board.ID, ReviewerID: "r1", Category: "LOGIC", Severity: "low", Title: "t", Description: "d", Status: "open"}); err != nil {
+			t.Fatalf("CreateDefect failed: %v", err)
+		}
+		return board
+	}
+
+	orphaned := newBoard(9999, 48*time.Hour)
+	recentOrphan := newBoard(9998, time.Hour)
+	live := newBoard(assignment.ID, 48*time.Hour)
+
+	pruned, err := db.PruneOrphanedBoards(context.Background(), 24*time.Hour)
+	if err != nil {
+		t.Fatalf("PruneOrphanedBoards failed: %v", err)
+	}
+	if pruned != 1 {
+		t.Fatalf("Expected 1 board pruned, got %d", pruned)
+	}
+
+	if _, err := db.GetReviewBoard(orphaned.ID); err == nil {
+		t.Error("Expected orphaned board to be deleted")
+	}
+	for _, board := range []*ReviewBoard{recentOrphan, live} {
+		if _, err := db.GetReviewBoard(board.ID); err != nil {
+			t.Errorf("Expected board %d to be kept: %v", board.ID, err)
+		}
+	}
+
+	if votes, _ := db.GetReviewerVotes(orphaned.ID); len(votes) != 0 {
+		t.Errorf("Expected orphaned votes to be deleted, got %d", len(votes))
+	}
+	if defects, _ := db.GetBoardDefects(orphaned.ID); len(defects) != 0 {
+		t.Errorf("Expected orphaned defects to be deleted, got %d", len(defects))
+	}
+	if votes, _ := db.GetReviewerVotes(live.ID); len(votes) != 1 {
+		t.Errorf("Expected live board votes to be kept, got %d", len(votes))
+	}
+
+	if pruned, err := db.PruneOrphanedBoards(context.Background(), 24*time.Hour); err != nil || pruned != 0 {
+		t.Errorf("Second prune = %d, %v; want 0", pruned, err)
+	}
+}
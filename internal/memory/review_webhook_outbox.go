@@ -0,0 +1,70 @@
+package memory
+
+import (
+	"fmt"
+)
+
+// EnqueueReviewWebhook stores a review webhook that failed its first
+// delivery so it can be retried
+func (m *SQLiteMemoryDB) EnqueueReviewWebhook(boardID int64, payload []byte, lastError string) error {
+	query := `
+		INSERT INTO outbox_review_webhooks (board_id, payload, last_error)
+		VALUES (?, ?, ?)
+	`
+	if _, err := m.db.Exec(query, boardID, string(payload), nullString(lastError)); err != nil {
+		return fmt.Errorf("failed to enqueue review webhook: %w", err)
+	}
+	return nil
+}
+
+// GetPendingReviewWebhooks returns undelivered webhooks with fewer than
+// maxAttempts attempts, oldest first
+func (m *SQLiteMemoryDB) GetPendingReviewWebhooks(maxAttempts, limit int) ([]*ReviewWebhookOutboxEntry, error) {
+	query := `
+		SELECT id, board_id, payload, attempts, COALESCE(last_error, ''), created_at, last_attempt_at
+		FROM outbox_review_webhooks
+		WHERE delivered_at IS NULL AND attempts < ?
+		ORDER BY created_at ASC, id ASC
+		LIMIT ?
+	`
+	rows, err := m.db.Query(query, maxAttempts, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pending review webhooks: %w", err)
+	}
+	defer rows.Close()
+
+	var entries []*ReviewWebhookOutboxEntry
+	for rows.Next() {
+		entry := &ReviewWebhookOutboxEntry{}
+		if err := rows.Scan(
+			&entry.ID, &entry.BoardID, &entry.Payload, &entry.Attempts,
+			&entry.LastError, &entry.CreatedAt, &entry.LastAttemptAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan review webhook: %w", err)
+		}
+		entries = append(entries, entry)
+	}
+	return entries, rows.Err()
+}
+
+// MarkReviewWebhookDelivered removes a webhook from the pending queue
+func (m *SQLiteMemoryDB) MarkReviewWebhookDelivered(id int64) error {
+	query := `UPDATE outbox_review_webhooks SET delivered_at = CURRENT_TIMESTAMP WHERE id = ?`
+	if _, err := m.db.Exec(query, id); err != nil {
+		return fmt.Errorf("failed to mark review webhook %d delivered: %w", id, err)
+	}
+	return nil
+}
+
+// RecordReviewWebhookAttempt counts a failed retry of a queued webhook
+func (m *SQLiteMemoryDB) RecordReviewWebhookAttempt(id int64, lastError string) error {
+	query := `
+		UPDATE outbox_review_webhooks
+		SET attempts = attempts + 1, last_error = ?, last_attempt_at = CURRENT_TIMESTAMP
+		WHERE id = ?
+	`
+	if _, err := m.db.Exec(query, nullString(lastError), id); err != nil {
+		return fmt.Errorf("failed to record review webhook attempt %d: %w", id, err)
+	}
+	return nil
+}
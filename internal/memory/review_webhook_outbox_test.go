@@ -0,0 +1,49 @@
+package memory
+
+import "testing"
+
+func TestReviewWebhookOutbox(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	if err := db.EnqueueReviewWebhook(1, []byte(`{"board_id":1}`), "connection refused"); err != nil {
+		t.Fatalf("EnqueueReviewWebhook failed: %v", err)
+	}
+	if err := db.EnqueueReviewWebhook(2, []byte(`{"board_id":2}`), "status 500"); err != nil {
+		t.Fatalf("EnqueueReviewWebhook failed: %v", err)
+	}
+
+	pending, err := db.GetPendingReviewWebhooks(3, 10)
+	if err != nil {
+		t.Fatalf("GetPendingReviewWebhooks failed: %v", err)
+	}
+	if len(pending) != 2 || pending[0].BoardID != 1 || pending[0].Attempts != 1 {
+		t.Fatalf("Unexpected pending webhooks: %+v", pending)
+	}
+	if pending[0].Payload != `{"board_id":1}` || pending[0].LastError != "connection refused" {
+		t.Errorf("Unexpected entry: %+v", pending[0])
+	}
+
+	if err := db.MarkReviewWebhookDelivered(pending[0].ID); err != nil {
+		t.Fatalf("MarkReviewWebhookDelivered failed: %v", err)
+	}
+	for i := 0; i < 2; i++ {
+		if err := db.RecordReviewWebhookAttempt(pending[1].ID, "still down"); err != nil {
+			t.Fatalf("RecordReviewWebhookAttempt failed: %v", err)
+		}
+	}
+
+	// Delivered entries and entries out of attempts are no longer pending
+	pending, err = db.GetPendingReviewWebhooks(3, 10)
+	if err != nil {
+		t.Fatalf("GetPendingReviewWebhooks failed: %v", err)
+	}
+	if len(pending) != 0 {
+		t.Errorf("Expected no pending webhooks, got %+v", pending)
+	}
+
+	pending, _ = db.GetPendingReviewWebhooks(10, 10)
+	if len(pending) != 1 || pending[0].Attempts != 3 || pending[0].LastError != "still down" {
+		t.Errorf("Expected one retried webhook, got %+v", pending)
+	}
+}
@@ -0,0 +1,250 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// ReviewerAssignmentStrategyContextKey is the captain_context key naming the
+// strategy DispatchBoard uses when none is passed in
+const ReviewerAssignmentStrategyContextKey = "reviewer_assignment_strategy"
+
+// Reviewer assignment strategy names accepted in the context key
+const (
+	StrategyRandom          = "random"
+	StrategyRoundRobin      = "round_robin"
+	StrategyQualityWeighted = "quality_weighted" // Default
+)
+
+// ErrUnknownAssignmentStrategy is returned when the context key names a
+// strategy that does not exist
+var ErrUnknownAssignmentStrategy = errors.New("unknown reviewer assignment strategy")
+
+// ReviewerAssignmentStrategy picks up to board.ReviewerCount reviewers from
+// a pool of eligible agent IDs
+type ReviewerAssignmentStrategy interface {
+	SelectReviewers(ctx context.Context, board *ReviewBoard, pool []string) ([]string, error)
+}
+
+type reviewerRoleKey struct{}
+
+// WithReviewerRole attaches the reviewer role being dispatched to ctx, so
+// strategies that track state per role can read it
+func WithReviewerRole(ctx context.Context, role string) context.Context {
+	return context.WithValue(ctx, reviewerRoleKey{}, role)
+}
+
+// reviewerRole returns the role set by WithReviewerRole, or ""
+func reviewerRole(ctx context.Context) string {
+	role, _ := ctx.Value(reviewerRoleKey{}).(string)
+	return role
+}
+
+// selectionSize is how many reviewers a strategy picks for a board
+func selectionSize(board *ReviewBoard, pool []string) int {
+	if board.ReviewerCount < len(pool) {
+		return board.ReviewerCount
+	}
+	return len(pool)
+}
+
+// RandomStrategy picks reviewers uniformly at random. A nil Rand uses the
+// global source; a *rand.Rand is not safe for concurrent use.
+type RandomStrategy struct {
+	Rand *rand.Rand
+}
+
+// SelectReviewers implements ReviewerAssignmentStrategy
+func (s *RandomStrategy) SelectReviewers(ctx context.Context, board *ReviewBoard, pool []string) ([]string, error) {
+	perm := rand.Perm
+	if s.Rand != nil {
+		perm = s.Rand.Perm
+	}
+	n := selectionSize(board, pool)
+	selected := make([]string, 0, n)
+	for _, i := range perm(len(pool))[:n] {
+		selected = append(selected, pool[i])
+	}
+	return selected, nil
+}
+
+// RoundRobinStrategy walks the pool in order, continuing where the last
+// board for the same reviewer role left off. Positions are kept in memory
+// and reset on restart.
+type RoundRobinStrategy struct {
+	mu   sync.Mutex
+	next map[string]int // role -> pool index of the next reviewer
+}
+
+// SelectReviewers implements ReviewerAssignmentStrategy
+func (s *RoundRobinStrategy) SelectReviewers(ctx context.Context, board *ReviewBoard, pool []string) ([]string, error) {
+	n := selectionSize(board, pool)
+	if n == 0 {
+		return []string{}, nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.next == nil {
+		s.next = make(map[string]int)
+	}
+	role := reviewerRole(ctx)
+	start := s.next[role] % len(pool)
+
+	selected := make([]string, 0, n)
+	for i := 0; i < n; i++ {
+		selected = append(selected, pool[(start+i)%len(pool)])
+	}
+	s.next[role] = (start + n) % len(pool)
+	return selected, nil
+}
+
+// QualityWeightedStrategy picks the reviewers with the highest
+// quality_score, breaking ties by agent ID
+type QualityWeightedStrategy struct {
+	db *SQLiteMemoryDB
+}
+
+// NewQualityWeightedStrategy creates a strategy reading scores from db
+func NewQualityWeightedStrategy(db *SQLiteMemoryDB) *QualityWeightedStrategy {
+	return &QualityWeightedStrategy{db: db}
+}
+
+// SelectReviewers implements ReviewerAssignmentStrategy
+func (s *QualityWeightedStrategy) SelectReviewers(ctx context.Context, board *ReviewBoard, pool []string) ([]string, error) {
+	scores, err := s.db.qualityScores(ctx, pool)
+	if err != nil {
+		return nil, err
+	}
+	ranked := append([]string(nil), pool...)
+	sort.SliceStable(ranked, func(i, j int) bool {
+		if scores[ranked[i]] != scores[ranked[j]] {
+			return scores[ranked[i]] > scores[ranked[j]]
+		}
+		return ranked[i] < ranked[j]
+	})
+	return ranked[:selectionSize(board, pool)], nil
+}
+
+// qualityScores returns the quality_score of each agent in ids that has one
+func (m *SQLiteMemoryDB) qualityScores(ctx context.Context, ids []string) (map[string]float64, error) {
+	scores := make(map[string]float64, len(ids))
+	if len(ids) == 0 {
+		return scores, nil
+	}
+	args := make([]interface{}, len(ids))
+	for i, id := range ids {
+		args[i] = id
+	}
+	rows, err := m.db.QueryContext(ctx, `
+		SELECT agent_id, quality_score FROM agent_quality_scores
+		WHERE agent_id IN (?`+strings.Repeat(", ?", len(ids)-1)+`)
+	`, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query quality scores: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var agentID string
+		var score float64
+		if err := rows.Scan(&agentID, &score); err != nil {
+			return nil, fmt.Errorf("failed to scan quality score: %w", err)
+		}
+		scores[agentID] = score
+	}
+	return scores, rows.Err()
+}
+
+// ReviewerAssignmentStrategy returns the strategy named by the
+// reviewer_assignment_strategy context key, defaulting to quality_weighted.
+// The round robin strategy is shared so its positions carry across boards.
+func (m *SQLiteMemoryDB) ReviewerAssignmentStrategy() (ReviewerAssignmentStrategy, error) {
+	name := StrategyQualityWeighted
+	ctx, err := m.GetContext(ReviewerAssignmentStrategyContextKey)
+	if err != nil {
+		return nil, err
+	}
+	if ctx != nil && strings.TrimSpace(ctx.Value) != "" {
+		name = strings.ToLower(strings.TrimSpace(ctx.Value))
+	}
+
+	switch name {
+	case StrategyRandom:
+		return &RandomStrategy{}, nil
+	case StrategyRoundRobin:
+		return m.roundRobin, nil
+	case StrategyQualityWeighted:
+		return NewQualityWeightedStrategy(m), nil
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnknownAssignmentStrategy, name)
+	}
+}
+
+// DispatchBoard assigns reviewers of the given role to a board's free slots
+// and locks a slot for each. Free slots are reviewer_count less the pending
+// and completed slots, so re-dispatching after an abandon only refills the
+// abandoned places. The pool is GetAvailableReviewers minus excludeIDs
+// (typically the author) and reviewers already holding a slot. A nil
+// strategy uses ReviewerAssignmentStrategy. Returns the reviewers locked.
+func (m *SQLiteMemoryDB) DispatchBoard(ctx context.Context, boardID int64, role string, excludeIDs []string, strategy ReviewerAssignmentStrategy) ([]string, error) {
+	board, err := m.GetReviewBoard(boardID)
+	if err != nil {
+		return nil, err
+	}
+
+	if strategy == nil {
+		if strategy, err = m.ReviewerAssignmentStrategy(); err != nil {
+			return nil, err
+		}
+	}
+
+	slots, err := m.GetReviewerSlots(boardID)
+	if err != nil {
+		return nil, err
+	}
+	exclude := append([]string(nil), excludeIDs...)
+	held := 0
+	for _, slot := range slots {
+		if slot.Status != ReviewerSlotAbandoned {
+			exclude = append(exclude, slot.ReviewerID)
+			held++
+		}
+	}
+	if held >= board.ReviewerCount {
+		return []string{}, nil
+	}
+	pool, err := m.GetAvailableReviewers(role, exclude)
+	if err != nil {
+		return nil, err
+	}
+
+	// Strategies pick board.ReviewerCount reviewers; ask only for the free slots
+	open := *board
+	open.ReviewerCount = board.ReviewerCount - held
+	selected, err := strategy.SelectReviewers(WithReviewerRole(ctx, role), &open, pool)
+	if err != nil {
+		return nil, fmt.Errorf("failed to select reviewers: %w", err)
+	}
+
+	locked := []string{}
+	for _, reviewerID := range selected {
+		if err := m.LockReviewerSlot(boardID, reviewerID); err != nil {
+			if errors.Is(err, ErrReviewerSlotLocked) {
+				continue
+			}
+			if errors.Is(err, ErrReviewBoardFull) {
+				// Another dispatch filled the board first
+				break
+			}
+			return locked, err
+		}
+		locked = append(locked, reviewerID)
+	}
+	return locked, nil
+}
@@ -0,0 +1,137 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+func seedReviewerScores(t *testing.T, db MemoryDB, scores map[string]float64) {
+	t.Helper()
+	for agentID, s := range scores {
+		score, err := db.GetOrCreateQualityScore(agentID, "reviewer")
+		if err != nil {
+			t.Fatalf("GetOrCreateQualityScore failed: %v", err)
+		}
+		score.QualityScore = s
+		if err := db.UpdateQualityScore(score); err != nil {
+			t.Fatalf("UpdateQualityScore failed: %v", err)
+		}
+	}
+}
+
+func TestReviewerAssignmentStrategies(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+	seedReviewerScores(t, db, map[string]float64{"rev-a": 60, "rev-b": 90, "rev-c": 75})
+
+	ctx := context.Background()
+	board := &ReviewBoard{ReviewerCount: 2}
+	pool := []string{"rev-a", "rev-b", "rev-c", "rev-unscored"}
+
+	got, err := NewQualityWeightedStrategy(db.(*SQLiteMemoryDB)).SelectReviewers(ctx, board, pool)
+	if err != nil {
+		t.Fatalf("QualityWeightedStrategy failed: %v", err)
+	}
+	if want := []string{"rev-b", "rev-c"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("QualityWeightedStrategy = %v, want %v", got, want)
+	}
+
+	random := &RandomStrategy{Rand: rand.New(rand.NewSource(1))}
+	got, err = random.SelectReviewers(ctx, board, pool)
+	if err != nil {
+		t.Fatalf("RandomStrategy failed: %v", err)
+	}
+	if len(got) != 2 || got[0] == got[1] {
+		t.Errorf("RandomStrategy = %v, want 2 distinct reviewers", got)
+	}
+	if got, _ := random.SelectReviewers(ctx, &ReviewBoard{ReviewerCount: 9}, pool); len(got) != len(pool) {
+		t.Errorf("RandomStrategy should cap at pool size, got %v", got)
+	}
+
+	rr := &RoundRobinStrategy{}
+	reviewerCtx := WithReviewerRole(ctx, "reviewer")
+	for _, want := range [][]string{{"rev-a", "rev-b"}, {"rev-c", "rev-unscored"}, {"rev-a", "rev-b"}} {
+		got, err := rr.SelectReviewers(reviewerCtx, board, pool)
+		if err != nil {
+			t.Fatalf("RoundRobinStrategy failed: %v", err)
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("RoundRobinStrategy = %v, want %v", got, want)
+		}
+	}
+	// Each role keeps its own position
+	if got, _ := rr.SelectReviewers(WithReviewerRole(ctx, "security"), board, pool); !reflect.DeepEqual(got, []string{"rev-a", "rev-b"}) {
+		t.Errorf("RoundRobinStrategy for new role = %v, want first two", got)
+	}
+}
+
+func TestDispatchBoard(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+	seedReviewerScores(t, db, map[string]float64{"rev-a": 60, "rev-b": 90, "rev-c": 75, "rev-d": 80})
+	board := createTestReviewBoard(t, db)
+	ctx := context.Background()
+
+	// Default strategy is quality weighted; the author is excluded
+	got, err := db.DispatchBoard(ctx, board.ID, "reviewer", []string{"rev-b"}, nil)
+	if err != nil {
+		t.Fatalf("DispatchBoard failed: %v", err)
+	}
+	if want := []string{"rev-d", "rev-c", "rev-a"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("DispatchBoard = %v, want %v", got, want)
+	}
+	slots, err := db.GetReviewerSlots(board.ID)
+	if err != nil {
+		t.Fatalf("GetReviewerSlots failed: %v", err)
+	}
+	if len(slots) != 3 {
+		t.Errorf("Expected 3 locked slots, got %d", len(slots))
+	}
+
+	// A full board gets no more reviewers
+	got, err = db.DispatchBoard(ctx, board.ID, "reviewer", nil, nil)
+	if err != nil {
+		t.Fatalf("DispatchBoard failed: %v", err)
+	}
+	if len(got) != 0 {
+		t.Errorf("DispatchBoard on a full board = %v, want none", got)
+	}
+
+	// Re-dispatching after an abandon refills only the free slot, and
+	// reviewers already holding a slot are not picked again
+	if err := db.AbandonReviewerSlot(board.ID, "rev-a"); err != nil {
+		t.Fatalf("AbandonReviewerSlot failed: %v", err)
+	}
+	got, err = db.DispatchBoard(ctx, board.ID, "reviewer", nil, nil)
+	if err != nil {
+		t.Fatalf("DispatchBoard failed: %v", err)
+	}
+	if want := []string{"rev-b"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("DispatchBoard after abandon = %v, want %v", got, want)
+	}
+
+	// Strategy comes from the context key
+	if err := db.SetContext(ReviewerAssignmentStrategyContextKey, "round_robin", 5, 0); err != nil {
+		t.Fatalf("SetContext failed: %v", err)
+	}
+	strategy, err := db.(*SQLiteMemoryDB).ReviewerAssignmentStrategy()
+	if err != nil {
+		t.Fatalf("ReviewerAssignmentStrategy failed: %v", err)
+	}
+	if _, ok := strategy.(*RoundRobinStrategy); !ok {
+		t.Errorf("Expected RoundRobinStrategy, got %T", strategy)
+	}
+
+	if err := db.SetContext(ReviewerAssignmentStrategyContextKey, "alphabetical", 5, 0); err != nil {
+		t.Fatalf("SetContext failed: %v", err)
+	}
+	if _, err := db.DispatchBoard(ctx, board.ID, "reviewer", nil, nil); !errors.Is(err, ErrUnknownAssignmentStrategy) {
+		t.Errorf("Expected ErrUnknownAssignmentStrategy, got %v", err)
+	}
+	if _, err := db.DispatchBoard(ctx, 9999, "reviewer", nil, &RandomStrategy{}); !errors.Is(err, ErrReviewBoardNotFound) {
+		t.Errorf("Expected ErrReviewBoardNotFound, got %v", err)
+	}
+}
@@ -0,0 +1,192 @@
+package memory
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"modernc.org/sqlite"
+	sqlite3 "modernc.org/sqlite/lib"
+)
+
+// Reviewer slot statuses, stored in reviewer_votes.status
+const (
+	ReviewerSlotPending   = "pending"
+	ReviewerSlotCompleted = "completed"
+	ReviewerSlotAbandoned = "abandoned"
+)
+
+var (
+	// ErrReviewBoardNotFound is returned when a review board ID does not exist
+	ErrReviewBoardNotFound = errors.New("review board not found")
+
+	// ErrAlreadyVoted is returned when a reviewer has already voted on a board
+	ErrAlreadyVoted = errors.New("reviewer has already voted on this board")
+
+	// ErrReviewerSlotLocked is returned when a reviewer already holds a
+	// pending slot on a board
+	ErrReviewerSlotLocked = errors.New("reviewer slot already locked")
+
+	// ErrReviewerSlotNotPending is returned when abandoning a slot that is not
+	// pending
+	ErrReviewerSlotNotPending = errors.New("no pending reviewer slot")
+
+	// ErrReviewBoardFull is returned when locking a slot on a board whose
+	// pending and completed slots already fill its reviewer_count
+	ErrReviewBoardFull = errors.New("review board has no free reviewer slots")
+)
+
+// slotCapacityCondition limits a slot insert or re-lock to boards with fewer
+// held (pending or completed) slots than reviewer_count. It takes the board
+// ID twice.
+const slotCapacityCondition = `
+	(SELECT COUNT(*) FROM reviewer_votes WHERE board_id = ? AND status != 'abandoned')
+	< (SELECT reviewer_count FROM review_boards WHERE id = ?)`
+
+// ReviewerSlot is one reviewer's place on a review board
+type ReviewerSlot struct {
+	BoardID     int64      `json:"board_id"`
+	ReviewerID  string     `json:"reviewer_id"`
+	Status      string     `json:"status"`
+	StartedAt   time.Time  `json:"started_at"`
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+}
+
+// isUniqueViolation reports whether err is a SQLite UNIQUE or PRIMARY KEY
+// constraint failure
+func isUniqueViolation(err error) bool {
+	var sqliteErr *sqlite.Error
+	if !errors.As(err, &sqliteErr) {
+		return false
+	}
+	code := sqliteErr.Code()
+	return code == sqlite3.SQLITE_CONSTRAINT_UNIQUE || code == sqlite3.SQLITE_CONSTRAINT_PRIMARYKEY
+}
+
+// LockReviewerSlot claims a slot on a board for a reviewer before review work
+// starts. The pending row is inserted in one statement, so two workers
+// cannot both claim the same reviewer's slot, and only while the board has
+// fewer held slots than its reviewer_count. An abandoned slot can be locked
+// again under the same capacity check.
+func (m *SQLiteMemoryDB) LockReviewerSlot(boardID int64, reviewerID string) error {
+	result, err := m.db.Exec(`
+		INSERT INTO reviewer_votes (board_id, reviewer_id, status, started_at)
+		SELECT ?, ?, 'pending', CURRENT_TIMESTAMP
+		WHERE `+slotCapacityCondition,
+		boardID, reviewerID, boardID, boardID)
+	if err != nil && !isUniqueViolation(err) {
+		return fmt.Errorf("failed to lock reviewer slot: %w", err)
+	}
+	if err == nil {
+		if n, _ := result.RowsAffected(); n > 0 {
+			return nil
+		}
+	}
+
+	result, err = m.db.Exec(`
+		UPDATE reviewer_votes SET status = 'pending', started_at = CURRENT_TIMESTAMP
+		WHERE board_id = ? AND reviewer_id = ? AND status = 'abandoned'
+		AND `+slotCapacityCondition,
+		boardID, reviewerID, boardID, boardID)
+	if err != nil {
+		return fmt.Errorf("failed to lock reviewer slot: %w", err)
+	}
+	if n, _ := result.RowsAffected(); n > 0 {
+		return nil
+	}
+
+	var status string
+	err = m.db.QueryRow(
+		`SELECT status FROM reviewer_votes WHERE board_id = ? AND reviewer_id = ?`,
+		boardID, reviewerID,
+	).Scan(&status)
+	switch {
+	case err == nil && status == ReviewerSlotCompleted:
+		return ErrAlreadyVoted
+	case err == nil && status == ReviewerSlotPending:
+		return ErrReviewerSlotLocked
+	case err != nil && err != sql.ErrNoRows:
+		return fmt.Errorf("failed to lock reviewer slot: %w", err)
+	}
+
+	// No slot could be taken: the board is full or does not exist
+	if _, err := m.GetReviewBoard(boardID); err != nil {
+		return err
+	}
+	return ErrReviewBoardFull
+}
+
+// AbandonReviewerSlot releases a pending slot whose reviewer stopped without
+// voting
+func (m *SQLiteMemoryDB) AbandonReviewerSlot(boardID int64, reviewerID string) error {
+	result, err := m.db.Exec(`
+		UPDATE reviewer_votes SET status = 'abandoned'
+		WHERE board_id = ? AND reviewer_id = ? AND status = 'pending'
+	`, boardID, reviewerID)
+	if err != nil {
+		return fmt.Errorf("failed to abandon reviewer slot: %w", err)
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return ErrReviewerSlotNotPending
+	}
+	return nil
+}
+
+// GetReviewerSlots returns every reviewer slot on a board in the order they
+// were taken
+func (m *SQLiteMemoryDB) GetReviewerSlots(boardID int64) ([]*ReviewerSlot, error) {
+	rows, err := m.db.Query(`
+		SELECT board_id, reviewer_id, status, started_at, completed_at
+		FROM reviewer_votes
+		WHERE board_id = ?
+		ORDER BY started_at ASC, id ASC
+	`, boardID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query reviewer slots: %w", err)
+	}
+	defer rows.Close()
+
+	slots := []*ReviewerSlot{}
+	for rows.Next() {
+		slot := &ReviewerSlot{}
+		var completedAt sql.NullTime
+		if err := rows.Scan(&slot.BoardID, &slot.ReviewerID, &slot.Status, &slot.StartedAt, &completedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan reviewer slot: %w", err)
+		}
+		if completedAt.Valid {
+			t := completedAt.Time
+			slot.CompletedAt = &t
+		}
+		slots = append(slots, slot)
+	}
+	return slots, rows.Err()
+}
+
+// completeReviewerSlot records a vote on the reviewer's existing slot.
+// Returns ErrAlreadyVoted if the slot is already completed.
+func (m *SQLiteMemoryDB) completeReviewerSlot(vote *ReviewerVote) error {
+	err := m.db.QueryRow(`
+		UPDATE reviewer_votes
+		SET approved = ?, confidence_score = ?, defects_found = ?, review_time_seconds = ?,
+		    tokens_used = ?, completed_at = ?, status = 'completed'
+		WHERE board_id = ? AND reviewer_id = ? AND status != 'completed'
+		RETURNING id
+	`,
+		vote.Approved,
+		vote.ConfidenceScore,
+		vote.DefectsFound,
+		nullInt(vote.ReviewTimeSeconds),
+		vote.TokensUsed,
+		nullTime(vote.CompletedAt),
+		vote.BoardID,
+		vote.ReviewerID,
+	).Scan(&vote.ID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrAlreadyVoted
+	}
+	if err != nil {
+		return fmt.Errorf("failed to complete reviewer slot: %w", err)
+	}
+	return nil
+}
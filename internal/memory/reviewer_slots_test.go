@@ -0,0 +1,139 @@
+package memory
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func createTestReviewBoard(t *testing.T, db MemoryDB) *ReviewBoard {
+	t.Helper()
+	assignment := &TaskAssignment{
+		TaskID:         "TASK-SLOTS",
+		AssignedTo:     "sgt-green",
+		AssignedBy:     "captain",
+		AssignmentType: "review",
+		Status:         "in_progress",
+	}
+	if err := db.CreateAssignment(assignment); err != nil {
+		t.Fatalf("CreateAssignment failed: %v", err)
+	}
+	board := &ReviewBoard{AssignmentID: assignment.ID, ReviewerCount: 3, Status: "in_progress", RiskLevel: "medium"}
+	if err := db.CreateReviewBoard(board); err != nil {
+		t.Fatalf("CreateReviewBoard failed: %v", err)
+	}
+	return board
+}
+
+func TestCreateReviewerVoteRejectsSecondVote(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+	board := createTestReviewBoard(t, db)
+
+	now := time.Now()
+	vote := &ReviewerVote{BoardID: board.ID, ReviewerID: "reviewer-1", Approved: true, StartedAt: now, CompletedAt: &now}
+	if err := db.CreateReviewerVote(vote); err != nil {
+		t.Fatalf("CreateReviewerVote failed: %v", err)
+	}
+
+	again := &ReviewerVote{BoardID: board.ID, ReviewerID: "reviewer-1", StartedAt: now, CompletedAt: &now}
+	if err := db.CreateReviewerVote(again); !errors.Is(err, ErrAlreadyVoted) {
+		t.Fatalf("second CreateReviewerVote error = %v, want ErrAlreadyVoted", err)
+	}
+	if err := db.LockReviewerSlot(board.ID, "reviewer-1"); !errors.Is(err, ErrAlreadyVoted) {
+		t.Errorf("LockReviewerSlot after vote error = %v, want ErrAlreadyVoted", err)
+	}
+}
+
+func TestLockReviewerSlotEnforcesCapacity(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+	board := createTestReviewBoard(t, db) // reviewer_count 3
+
+	for _, id := range []string{"reviewer-1", "reviewer-2", "reviewer-3"} {
+		if err := db.LockReviewerSlot(board.ID, id); err != nil {
+			t.Fatalf("LockReviewerSlot(%s) failed: %v", id, err)
+		}
+	}
+	if err := db.LockReviewerSlot(board.ID, "reviewer-4"); !errors.Is(err, ErrReviewBoardFull) {
+		t.Errorf("LockReviewerSlot on a full board error = %v, want ErrReviewBoardFull", err)
+	}
+	if err := db.LockReviewerSlot(board.ID, "reviewer-1"); !errors.Is(err, ErrReviewerSlotLocked) {
+		t.Errorf("relocking a held slot on a full board error = %v, want ErrReviewerSlotLocked", err)
+	}
+
+	// An abandoned place can be taken by someone else, but the abandoned
+	// reviewer cannot come back once the board is full again
+	if err := db.AbandonReviewerSlot(board.ID, "reviewer-2"); err != nil {
+		t.Fatalf("AbandonReviewerSlot failed: %v", err)
+	}
+	if err := db.LockReviewerSlot(board.ID, "reviewer-4"); err != nil {
+		t.Fatalf("LockReviewerSlot after abandon failed: %v", err)
+	}
+	if err := db.LockReviewerSlot(board.ID, "reviewer-2"); !errors.Is(err, ErrReviewBoardFull) {
+		t.Errorf("relocking abandoned slot on a full board error = %v, want ErrReviewBoardFull", err)
+	}
+	if err := db.LockReviewerSlot(9999, "reviewer-1"); !errors.Is(err, ErrReviewBoardNotFound) {
+		t.Errorf("LockReviewerSlot on missing board error = %v, want ErrReviewBoardNotFound", err)
+	}
+}
+
+func TestReviewerSlotLifecycle(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+	board := createTestReviewBoard(t, db)
+
+	if err := db.LockReviewerSlot(board.ID, "reviewer-1"); err != nil {
+		t.Fatalf("LockReviewerSlot failed: %v", err)
+	}
+	if err := db.LockReviewerSlot(board.ID, "reviewer-1"); !errors.Is(err, ErrReviewerSlotLocked) {
+		t.Errorf("second LockReviewerSlot error = %v, want ErrReviewerSlotLocked", err)
+	}
+	if err := db.LockReviewerSlot(board.ID, "reviewer-2"); err != nil {
+		t.Fatalf("LockReviewerSlot failed: %v", err)
+	}
+
+	// Pending slots are not votes
+	votes, err := db.GetReviewerVotes(board.ID)
+	if err != nil || len(votes) != 0 {
+		t.Fatalf("GetReviewerVotes = %v, %v; want no votes", votes, err)
+	}
+
+	now := time.Now()
+	vote := &ReviewerVote{BoardID: board.ID, ReviewerID: "reviewer-1", Approved: true, DefectsFound: 2, StartedAt: now, CompletedAt: &now}
+	if err := db.CreateReviewerVote(vote); err != nil {
+		t.Fatalf("CreateReviewerVote on locked slot failed: %v", err)
+	}
+	if vote.ID == 0 {
+		t.Error("expected vote ID to be set")
+	}
+
+	if err := db.AbandonReviewerSlot(board.ID, "reviewer-2"); err != nil {
+		t.Fatalf("AbandonReviewerSlot failed: %v", err)
+	}
+	if err := db.AbandonReviewerSlot(board.ID, "reviewer-1"); !errors.Is(err, ErrReviewerSlotNotPending) {
+		t.Errorf("AbandonReviewerSlot on completed slot error = %v, want ErrReviewerSlotNotPending", err)
+	}
+
+	slots, err := db.GetReviewerSlots(board.ID)
+	if err != nil {
+		t.Fatalf("GetReviewerSlots failed: %v", err)
+	}
+	statuses := map[string]string{}
+	for _, slot := range slots {
+		statuses[slot.ReviewerID] = slot.Status
+	}
+	if len(slots) != 2 || statuses["reviewer-1"] != ReviewerSlotCompleted || statuses["reviewer-2"] != ReviewerSlotAbandoned {
+		t.Errorf("unexpected slots: %v", statuses)
+	}
+
+	// An abandoned slot can be taken again
+	if err := db.LockReviewerSlot(board.ID, "reviewer-2"); err != nil {
+		t.Errorf("relocking abandoned slot failed: %v", err)
+	}
+
+	votes, err = db.GetReviewerVotes(board.ID)
+	if err != nil || len(votes) != 1 || votes[0].DefectsFound != 2 {
+		t.Errorf("GetReviewerVotes = %+v, %v; want the one completed vote", votes, err)
+	}
+}
@@ -0,0 +1,76 @@
+package memory
+
+import (
+	"fmt"
+	"time"
+)
+
+// StoreSessionStatsSnapshot records a copy of the session statistics
+func (m *SQLiteMemoryDB) StoreSessionStatsSnapshot(snapshot *SessionStatsSnapshot) error {
+	if snapshot.Timestamp.IsZero() {
+		snapshot.Timestamp = time.Now()
+	}
+
+	query := `
+		INSERT INTO session_stats_snapshots (taken_at, active_agents, total_tokens_used, total_cost, tasks_completed, alerts_fired)
+		VALUES (?, ?, ?, ?, ?, ?)
+	`
+	result, err := m.db.Exec(query,
+		snapshot.Timestamp.UTC().Format("2006-01-02 15:04:05"),
+		snapshot.ActiveAgents,
+		snapshot.TotalTokensUsed,
+		snapshot.TotalCost,
+		snapshot.TasksCompleted,
+		snapshot.AlertsFired,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to store session stats snapshot: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("failed to get session stats snapshot ID: %w", err)
+	}
+	snapshot.ID = id
+	return nil
+}
+
+// GetSessionStatsHistory returns snapshots taken in [from, to], oldest first.
+// With a positive bucket only the latest snapshot in each bucket-sized window
+// (aligned to the Unix epoch) is returned; the figures are running totals, so
+// the latest one summarizes the window.
+func (m *SQLiteMemoryDB) GetSessionStatsHistory(from, to time.Time, bucket time.Duration) ([]*SessionStatsSnapshot, error) {
+	bucketSeconds := int64(bucket / time.Second)
+	if bucketSeconds <= 0 {
+		bucketSeconds = 1
+	}
+
+	// SQLite fills the bare columns from the row that has MAX(taken_at)
+	query := `
+		SELECT id, taken_at, active_agents, total_tokens_used, total_cost, tasks_completed, alerts_fired, MAX(taken_at)
+		FROM session_stats_snapshots
+		WHERE taken_at >= ? AND taken_at <= ?
+		GROUP BY CAST(strftime('%s', taken_at) AS INTEGER) / ?
+		ORDER BY taken_at
+	`
+	rows, err := m.db.Query(query,
+		from.UTC().Format("2006-01-02 15:04:05"),
+		to.UTC().Format("2006-01-02 15:04:05"),
+		bucketSeconds,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query session stats history: %w", err)
+	}
+	defer rows.Close()
+
+	history := []*SessionStatsSnapshot{}
+	for rows.Next() {
+		s := &SessionStatsSnapshot{}
+		var latest string
+		if err := rows.Scan(&s.ID, &s.Timestamp, &s.ActiveAgents, &s.TotalTokensUsed, &s.TotalCost, &s.TasksCompleted, &s.AlertsFired, &latest); err != nil {
+			return nil, fmt.Errorf("failed to scan session stats snapshot: %w", err)
+		}
+		history = append(history, s)
+	}
+	return history, rows.Err()
+}
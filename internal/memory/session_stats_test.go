@@ -0,0 +1,60 @@
+package memory
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSessionStatsHistory(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	base := time.Date(2025, 6, 1, 10, 0, 0, 0, time.UTC)
+	for i, offset := range []time.Duration{0, 15 * time.Minute, 45 * time.Minute, 75 * time.Minute, 3 * time.Hour} {
+		snapshot := &SessionStatsSnapshot{
+			Timestamp:       base.Add(offset),
+			ActiveAgents:    i,
+			TotalTokensUsed: int64(1000 * (i + 1)),
+			TotalCost:       float64(i+1) / 10,
+			TasksCompleted:  i,
+			AlertsFired:     i,
+		}
+		if err := db.StoreSessionStatsSnapshot(snapshot); err != nil {
+			t.Fatalf("StoreSessionStatsSnapshot failed: %v", err)
+		}
+	}
+
+	all, err := db.GetSessionStatsHistory(base, base.Add(2*time.Hour), 0)
+	if err != nil {
+		t.Fatalf("GetSessionStatsHistory failed: %v", err)
+	}
+	if len(all) != 4 {
+		t.Fatalf("Expected 4 snapshots within range, got %d", len(all))
+	}
+	if !all[0].Timestamp.Equal(base) {
+		t.Errorf("Expected first snapshot at %s, got %s", base, all[0].Timestamp)
+	}
+
+	hourly, err := db.GetSessionStatsHistory(base, base.Add(4*time.Hour), time.Hour)
+	if err != nil {
+		t.Fatalf("GetSessionStatsHistory failed: %v", err)
+	}
+	if len(hourly) != 3 {
+		t.Fatalf("Expected 3 hourly buckets, got %d: %+v", len(hourly), hourly)
+	}
+	// The 10:00 bucket reports its latest snapshot (10:45)
+	if hourly[0].TotalTokensUsed != 3000 || !hourly[0].Timestamp.Equal(base.Add(45*time.Minute)) {
+		t.Errorf("Unexpected first bucket: %+v", hourly[0])
+	}
+	if hourly[2].TotalTokensUsed != 5000 || hourly[2].AlertsFired != 4 {
+		t.Errorf("Unexpected last bucket: %+v", hourly[2])
+	}
+
+	empty, err := db.GetSessionStatsHistory(base.Add(-48*time.Hour), base.Add(-24*time.Hour), time.Hour)
+	if err != nil {
+		t.Fatalf("GetSessionStatsHistory failed: %v", err)
+	}
+	if empty == nil || len(empty) != 0 {
+		t.Errorf("Expected empty non-nil history, got %#v", empty)
+	}
+}
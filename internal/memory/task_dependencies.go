@@ -0,0 +1,48 @@
+package memory
+
+import (
+	"fmt"
+)
+
+// AddTaskDependency records that taskID depends on dependsOn. Adding an
+// existing dependency is a no-op.
+func (m *SQLiteMemoryDB) AddTaskDependency(taskID, dependsOn string) error {
+	if taskID == "" || dependsOn == "" {
+		return fmt.Errorf("task ID and dependency are required")
+	}
+	if taskID == dependsOn {
+		return fmt.Errorf("task %s cannot depend on itself", taskID)
+	}
+	_, err := m.db.Exec(`
+		INSERT OR IGNORE INTO task_dependencies (task_id, depends_on)
+		VALUES (?, ?)
+	`, taskID, dependsOn)
+	if err != nil {
+		return fmt.Errorf("failed to add dependency %s -> %s: %w", taskID, dependsOn, err)
+	}
+	return nil
+}
+
+// GetTaskDependencies returns the tasks taskID directly depends on
+func (m *SQLiteMemoryDB) GetTaskDependencies(taskID string) ([]*TaskDependency, error) {
+	rows, err := m.db.Query(`
+		SELECT task_id, depends_on
+		FROM task_dependencies
+		WHERE task_id = ?
+		ORDER BY depends_on
+	`, taskID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query task dependencies: %w", err)
+	}
+	defer rows.Close()
+
+	deps := []*TaskDependency{}
+	for rows.Next() {
+		d := &TaskDependency{}
+		if err := rows.Scan(&d.TaskID, &d.DependsOn); err != nil {
+			return nil, fmt.Errorf("failed to scan task dependency: %w", err)
+		}
+		deps = append(deps, d)
+	}
+	return deps, rows.Err()
+}
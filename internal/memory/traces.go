@@ -0,0 +1,56 @@
+package memory
+
+import (
+	"fmt"
+)
+
+// GetTraceRecords returns the session log entries and Captain cycle metrics
+// tagged with traceID, oldest first
+func (m *SQLiteMemoryDB) GetTraceRecords(traceID string) (*TraceRecords, error) {
+	records := &TraceRecords{
+		TraceID:             traceID,
+		SessionLog:          []*SessionLogEntry{},
+		OrchestratorMetrics: []*OrchestratorMetric{},
+	}
+
+	rows, err := m.db.Query(`
+		SELECT id, session_id, event_type, summary, details, agent_id, COALESCE(trace_id, ''), created_at
+		FROM captain_session_log
+		WHERE trace_id = ?
+		ORDER BY created_at ASC, id ASC
+	`, traceID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query traced session log: %w", err)
+	}
+	entries, err := m.scanLogRows(rows)
+	rows.Close()
+	if err != nil {
+		return nil, err
+	}
+	if entries != nil {
+		records.SessionLog = entries
+	}
+
+	rows, err = m.db.Query(`
+		SELECT id, cycle_at, duration_ms, tasks_processed, subagents_spawned, escalations_created, COALESCE(trace_id, '')
+		FROM orchestrator_metrics
+		WHERE trace_id = ?
+		ORDER BY cycle_at ASC, id ASC
+	`, traceID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query traced orchestrator metrics: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		metric := &OrchestratorMetric{}
+		if err := rows.Scan(
+			&metric.ID, &metric.CycleAt, &metric.DurationMs, &metric.TasksProcessed,
+			&metric.SubagentsSpawned, &metric.EscalationsCreated, &metric.TraceID,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan orchestrator metric: %w", err)
+		}
+		records.OrchestratorMetrics = append(records.OrchestratorMetrics, metric)
+	}
+	return records, rows.Err()
+}
@@ -0,0 +1,46 @@
+package memory
+
+import (
+	"context"
+	"testing"
+
+	"github.com/CLIAIMONITOR/internal/logger"
+)
+
+func TestGetTraceRecords(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	ctx := logger.WithTraceID(context.Background(), "trace-1")
+	if err := db.LogSessionEventContext(ctx, "s1", "command", "traced", "", "Captain"); err != nil {
+		t.Fatalf("LogSessionEventContext failed: %v", err)
+	}
+	if err := db.LogSessionEvent("s1", "command", "untraced", "", "Captain"); err != nil {
+		t.Fatalf("LogSessionEvent failed: %v", err)
+	}
+	if err := db.StoreOrchestratorMetric(&OrchestratorMetric{DurationMs: 10, TraceID: "trace-1"}); err != nil {
+		t.Fatalf("StoreOrchestratorMetric failed: %v", err)
+	}
+	if err := db.StoreOrchestratorMetric(&OrchestratorMetric{DurationMs: 20, TraceID: "trace-2"}); err != nil {
+		t.Fatalf("StoreOrchestratorMetric failed: %v", err)
+	}
+
+	records, err := db.GetTraceRecords("trace-1")
+	if err != nil {
+		t.Fatalf("GetTraceRecords failed: %v", err)
+	}
+	if len(records.SessionLog) != 1 || records.SessionLog[0].Summary != "traced" || records.SessionLog[0].TraceID != "trace-1" {
+		t.Errorf("unexpected session log: %+v", records.SessionLog)
+	}
+	if len(records.OrchestratorMetrics) != 1 || records.OrchestratorMetrics[0].DurationMs != 10 {
+		t.Errorf("unexpected orchestrator metrics: %+v", records.OrchestratorMetrics)
+	}
+
+	records, err = db.GetTraceRecords("missing")
+	if err != nil {
+		t.Fatalf("GetTraceRecords failed: %v", err)
+	}
+	if records.SessionLog == nil || len(records.SessionLog) != 0 || len(records.OrchestratorMetrics) != 0 {
+		t.Errorf("expected empty records, got %+v", records)
+	}
+}
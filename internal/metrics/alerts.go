@@ -18,6 +18,10 @@ type AlertEngine interface {
 	CheckEscalationQueue(pendingCount int) *types.Alert
 }
 
+// AlertTypeBudgetExceeded is raised when an agent's cost passes CostBudget.
+// The server stops the agent when it sees this alert.
+const AlertTypeBudgetExceeded = "budget_exceeded"
+
 // AlertChecker implements AlertEngine
 type AlertChecker struct {
 	mu         sync.RWMutex
@@ -127,6 +131,39 @@ func (a *AlertChecker) CheckMetrics(metrics map[string]*types.AgentMetrics) []*t
 			}
 		}
 
+		// Check cost budget: warn first, then flag for shutdown
+		if thresholds.CostBudget > 0 {
+			warnPercent := thresholds.CostBudgetWarningPercent
+			if warnPercent <= 0 {
+				warnPercent = types.DefaultCostBudgetWarningPercent
+			}
+			if m.EstimatedCost > thresholds.CostBudget {
+				key := fmt.Sprintf("budget_exceeded_%s", agentID)
+				if a.shouldAlert(key) {
+					alerts = append(alerts, &types.Alert{
+						ID:        uuid.New().String(),
+						Type:      AlertTypeBudgetExceeded,
+						AgentID:   agentID,
+						Message:   fmt.Sprintf("Agent %s cost $%.2f exceeds budget $%.2f, stopping agent", agentID, m.EstimatedCost, thresholds.CostBudget),
+						Severity:  "critical",
+						CreatedAt: time.Now(),
+					})
+				}
+			} else if m.EstimatedCost >= thresholds.CostBudget*warnPercent/100 {
+				key := fmt.Sprintf("budget_warning_%s", agentID)
+				if a.shouldAlert(key) {
+					alerts = append(alerts, &types.Alert{
+						ID:        uuid.New().String(),
+						Type:      "budget_warning",
+						AgentID:   agentID,
+						Message:   fmt.Sprintf("Agent %s cost $%.2f has reached %.0f%% of budget $%.2f", agentID, m.EstimatedCost, warnPercent, thresholds.CostBudget),
+						Severity:  "warning",
+						CreatedAt: time.Now(),
+					})
+				}
+			}
+		}
+
 		// Check consecutive rejects
 		if thresholds.ConsecutiveRejectsMax > 0 && m.ConsecutiveRejects >= thresholds.ConsecutiveRejectsMax {
 			key := fmt.Sprintf("rejects_%s", agentID)
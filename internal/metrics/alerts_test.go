@@ -135,6 +135,35 @@ func TestCheckMetricsConsecutiveRejects(t *testing.T) {
 	}
 }
 
+func TestCheckMetricsCostBudget(t *testing.T) {
+	thresholds := types.AlertThresholds{
+		CostBudget: 10.0, // Warning percent unset, falls back to 80%
+	}
+	engine := NewAlertEngine(thresholds)
+
+	metrics := map[string]*types.AgentMetrics{
+		"Agent1": {AgentID: "Agent1", EstimatedCost: 5.0},  // Under warning
+		"Agent2": {AgentID: "Agent2", EstimatedCost: 8.5},  // Warning
+		"Agent3": {AgentID: "Agent3", EstimatedCost: 12.0}, // Exceeded
+	}
+
+	alerts := engine.CheckMetrics(metrics)
+
+	byAgent := make(map[string]string)
+	for _, alert := range alerts {
+		byAgent[alert.AgentID] = alert.Type
+	}
+	if _, ok := byAgent["Agent1"]; ok {
+		t.Errorf("expected no alert for Agent1, got %s", byAgent["Agent1"])
+	}
+	if byAgent["Agent2"] != "budget_warning" {
+		t.Errorf("expected budget_warning for Agent2, got %q", byAgent["Agent2"])
+	}
+	if byAgent["Agent3"] != AlertTypeBudgetExceeded {
+		t.Errorf("expected budget_exceeded for Agent3, got %q", byAgent["Agent3"])
+	}
+}
+
 func TestCheckMetricsNoAlertForZeroThreshold(t *testing.T) {
 	thresholds := types.AlertThresholds{
 		FailedTestsMax: 0, // Disabled
@@ -1,7 +1,6 @@
 package external
 
 import (
-	"bytes"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -22,16 +21,16 @@ type DiscordConfig struct {
 // DiscordNotifier sends notifications to Discord via webhooks
 type DiscordNotifier struct {
 	config DiscordConfig
-	client *http.Client
+	sender *webhookSender
 }
 
-// NewDiscordNotifier creates a new Discord notifier
-func NewDiscordNotifier(config DiscordConfig) *DiscordNotifier {
+// NewDiscordNotifier creates a new Discord notifier. Rate-limited posts are
+// retried with exponential backoff; opts can change the retry policy or
+// transport.
+func NewDiscordNotifier(config DiscordConfig, opts ...Option) *DiscordNotifier {
 	return &DiscordNotifier{
 		config: config,
-		client: &http.Client{
-			Timeout: 10 * time.Second,
-		},
+		sender: newWebhookSender(opts),
 	}
 }
 
@@ -142,7 +141,7 @@ func (d *DiscordNotifier) Send(event events.Event) error {
 	}
 
 	// Send HTTP request
-	resp, err := d.client.Post(d.config.WebhookURL, "application/json", bytes.NewBuffer(jsonData))
+	resp, err := d.sender.post(d.config.WebhookURL, jsonData)
 	if err != nil {
 		return fmt.Errorf("failed to send discord notification: %w", err)
 	}
@@ -5,7 +5,9 @@ import (
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/CLIAIMONITOR/internal/events"
 )
@@ -315,3 +317,94 @@ func TestDiscordNotifier_Send_ServerError(t *testing.T) {
 		t.Error("expected error for server error response")
 	}
 }
+
+func TestDiscordNotifier_Send_CustomTransport(t *testing.T) {
+	var got map[string]interface{}
+	transport := roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+		json.NewDecoder(r.Body).Decode(&got)
+		return &http.Response{StatusCode: http.StatusNoContent, Body: io.NopCloser(strings.NewReader("")), Header: http.Header{}}, nil
+	})
+
+	notifier := NewDiscordNotifier(DiscordConfig{WebhookURL: "https://discord.invalid/api/webhooks/1"}, WithTransport(transport))
+	err := notifier.Send(events.Event{ID: "evt-1", Type: events.EventAlert, Source: "captain", Priority: events.PriorityNormal})
+	if err != nil {
+		t.Fatalf("Send failed: %v", err)
+	}
+	embeds, ok := got["embeds"].([]interface{})
+	if !ok || len(embeds) != 1 {
+		t.Fatalf("expected one embed, got %v", got["embeds"])
+	}
+	if desc := embeds[0].(map[string]interface{})["description"]; desc != "Event ID: evt-1" {
+		t.Errorf("expected description 'Event ID: evt-1', got %v", desc)
+	}
+}
+
+func TestDiscordNotifier_Send_RetriesOnRateLimit(t *testing.T) {
+	tests := []struct {
+		name        string
+		limited     int
+		maxRetries  int
+		expectError bool
+		wantCalls   int
+		wantDelays  []time.Duration
+	}{
+		{
+			name:       "recovers after backoff",
+			limited:    3,
+			maxRetries: 3,
+			wantCalls:  4,
+			wantDelays: []time.Duration{5 * time.Millisecond, 10 * time.Millisecond, 20 * time.Millisecond},
+		},
+		{
+			name:        "retries disabled",
+			limited:     1,
+			maxRetries:  0,
+			expectError: true,
+			wantCalls:   1,
+		},
+		{
+			name:        "other errors are not retried",
+			limited:     0,
+			maxRetries:  3,
+			expectError: true,
+			wantCalls:   1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := 0
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				calls++
+				switch {
+				case calls <= tt.limited:
+					w.WriteHeader(http.StatusTooManyRequests)
+				case tt.limited == 0:
+					w.WriteHeader(http.StatusBadGateway)
+				default:
+					w.WriteHeader(http.StatusNoContent)
+				}
+			}))
+			defer server.Close()
+
+			notifier := NewDiscordNotifier(DiscordConfig{WebhookURL: server.URL}, WithRetry(tt.maxRetries, 5*time.Millisecond))
+			delays := recordSleeps(notifier.sender)
+
+			err := notifier.Send(events.Event{ID: "evt-1", Type: events.EventTask, Priority: events.PriorityNormal})
+			if (err != nil) != tt.expectError {
+				t.Fatalf("expectError=%v, got %v", tt.expectError, err)
+			}
+			if calls != tt.wantCalls {
+				t.Errorf("expected %d calls, got %d", tt.wantCalls, calls)
+			}
+			if len(*delays) != len(tt.wantDelays) {
+				t.Fatalf("expected delays %v, got %v", tt.wantDelays, *delays)
+			}
+			for i, want := range tt.wantDelays {
+				if (*delays)[i] != want {
+					t.Errorf("retry %d: expected delay %v, got %v", i+1, want, (*delays)[i])
+				}
+			}
+		})
+	}
+}
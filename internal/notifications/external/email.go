@@ -1,8 +1,11 @@
 package external
 
 import (
+	"bytes"
 	"fmt"
+	"html/template"
 	"net/smtp"
+	"os"
 	"strings"
 	"time"
 
@@ -11,6 +14,25 @@ import (
 
 const smtpTimeout = 30 * time.Second
 
+// defaultEmailTemplate wraps rendered HTML bodies when no template_path is configured
+const defaultEmailTemplate = `<!DOCTYPE html>
+<html>
+<head><meta charset="utf-8"><title>{{.Subject}}</title></head>
+<body style="font-family: sans-serif; line-height: 1.5;">
+{{.Content}}
+<hr>
+<p style="color: #888; font-size: 12px;">This is an automated notification from CLIAIMONITOR</p>
+</body>
+</html>
+`
+
+// emailTemplateData is passed to the HTML wrapper template
+type emailTemplateData struct {
+	Subject string
+	Content template.HTML
+	Event   events.Event
+}
+
 // EmailConfig holds configuration for email notifications
 type EmailConfig struct {
 	SMTPHost    string              `json:"smtp_host"`
@@ -23,6 +45,9 @@ type EmailConfig struct {
 	MinPriority int                 `json:"min_priority,omitempty"`
 	// TLS Configuration for secure SMTP connections
 	UseTLS     bool `json:"use_tls,omitempty"`     // Enable TLS (default: false for backwards compatibility)
+	// TemplatePath points to an html/template file used to wrap HTML bodies.
+	// The template receives .Subject, .Content (rendered HTML) and .Event.
+	TemplatePath string `json:"template_path,omitempty"`
 	// SECURITY: SkipVerify option removed - TLS certificate verification is now always enabled
 	// TLS certificate verification is critical for preventing MITM attacks
 }
@@ -83,11 +108,17 @@ func (e *EmailNotifier) Send(event events.Event) error {
 	// Build subject with priority prefix
 	subject := e.buildSubject(event)
 
-	// Build email body
-	body := e.buildBody(event)
-
-	// Build email message
-	message := e.buildMessage(subject, body)
+	// Build email message; review reports are sent as HTML
+	var message string
+	if event.Type == events.EventReviewCompleted {
+		body, err := e.buildHTMLBody(subject, event)
+		if err != nil {
+			return err
+		}
+		message = e.buildMessageWithContentType(subject, body, "text/html")
+	} else {
+		message = e.buildMessage(subject, e.buildBody(event))
+	}
 
 	// Send via SMTP
 	addr := fmt.Sprintf("%s:%d", e.config.SMTPHost, e.config.SMTPPort)
@@ -163,15 +194,55 @@ func (e *EmailNotifier) buildBody(event events.Event) string {
 	return body.String()
 }
 
+// buildHTMLBody renders the event's markdown report as HTML inside the
+// configured wrapper template
+func (e *EmailNotifier) buildHTMLBody(subject string, event events.Event) (string, error) {
+	markdown, _ := event.Payload["report"].(string)
+	if markdown == "" {
+		markdown, _ = event.Payload["summary"].(string)
+	}
+
+	tmplText := defaultEmailTemplate
+	if e.config.TemplatePath != "" {
+		content, err := os.ReadFile(e.config.TemplatePath)
+		if err != nil {
+			return "", fmt.Errorf("failed to read email template: %w", err)
+		}
+		tmplText = string(content)
+	}
+
+	tmpl, err := template.New("email").Parse(tmplText)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse email template: %w", err)
+	}
+
+	var body bytes.Buffer
+	err = tmpl.Execute(&body, emailTemplateData{
+		Subject: subject,
+		Content: template.HTML(renderMarkdownHTML(markdown)),
+		Event:   event,
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to render email template: %w", err)
+	}
+
+	return body.String(), nil
+}
+
 // buildMessage creates the full email message with headers
 func (e *EmailNotifier) buildMessage(subject, body string) string {
+	return e.buildMessageWithContentType(subject, body, "text/plain")
+}
+
+// buildMessageWithContentType creates the full email message with the given body content type
+func (e *EmailNotifier) buildMessageWithContentType(subject, body, contentType string) string {
 	var message strings.Builder
 
 	message.WriteString(fmt.Sprintf("From: %s\r\n", e.config.From))
 	message.WriteString(fmt.Sprintf("To: %s\r\n", strings.Join(e.config.To, ", ")))
 	message.WriteString(fmt.Sprintf("Subject: %s\r\n", subject))
 	message.WriteString("MIME-Version: 1.0\r\n")
-	message.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	message.WriteString(fmt.Sprintf("Content-Type: %s; charset=utf-8\r\n", contentType))
 	message.WriteString("\r\n")
 	message.WriteString(body)
 
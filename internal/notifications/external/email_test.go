@@ -4,6 +4,8 @@ import (
 	"bufio"
 	"fmt"
 	"net"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 	"time"
@@ -513,3 +515,76 @@ func TestPriorityString(t *testing.T) {
 		})
 	}
 }
+
+func TestEmailNotifier_buildHTMLBody(t *testing.T) {
+	event := events.Event{
+		ID:       "review-1",
+		Type:     events.EventReviewCompleted,
+		Source:   "review_board",
+		Priority: events.PriorityNormal,
+		Payload: map[string]interface{}{
+			"board_id":     int64(7),
+			"verdict":      "approved",
+			"defect_count": 1,
+			"report":       "# Review Board #7\n\n**Final Verdict:** approved\n\n- **Defects:** 1\n- <script>\n\n```\nx := 1 < 2\n```\n",
+		},
+	}
+
+	notifier := NewEmailNotifier(EmailConfig{})
+	body, err := notifier.buildHTMLBody("Review complete", event)
+	if err != nil {
+		t.Fatalf("buildHTMLBody failed: %v", err)
+	}
+
+	requiredStrings := []string{
+		"<title>Review complete</title>",
+		"<h1>Review Board #7</h1>",
+		"<strong>Final Verdict:</strong> approved",
+		"<li><strong>Defects:</strong> 1</li>",
+		"<li>&lt;script&gt;</li>",
+		"<pre><code>x := 1 &lt; 2\n</code></pre>",
+	}
+	for _, required := range requiredStrings {
+		if !strings.Contains(body, required) {
+			t.Errorf("HTML body missing %q\n%s", required, body)
+		}
+	}
+}
+
+func TestEmailNotifier_buildHTMLBody_CustomTemplate(t *testing.T) {
+	templatePath := filepath.Join(t.TempDir(), "wrapper.html")
+	if err := os.WriteFile(templatePath, []byte(`<div class="custom" data-board="{{index .Event.Payload "board_id"}}">{{.Content}}</div>`), 0644); err != nil {
+		t.Fatalf("failed to write template: %v", err)
+	}
+
+	notifier := NewEmailNotifier(EmailConfig{TemplatePath: templatePath})
+	body, err := notifier.buildHTMLBody("subject", events.Event{
+		Type:    events.EventReviewCompleted,
+		Payload: map[string]interface{}{"board_id": 3, "summary": "All **good**"},
+	})
+	if err != nil {
+		t.Fatalf("buildHTMLBody failed: %v", err)
+	}
+
+	expected := `<div class="custom" data-board="3"><p>All <strong>good</strong></p>` + "\n</div>"
+	if body != expected {
+		t.Errorf("expected %q, got %q", expected, body)
+	}
+
+	missing := NewEmailNotifier(EmailConfig{TemplatePath: filepath.Join(t.TempDir(), "missing.html")})
+	if _, err := missing.buildHTMLBody("subject", events.Event{Type: events.EventReviewCompleted}); err == nil {
+		t.Error("expected error for missing template file")
+	}
+}
+
+func TestEmailNotifier_buildMessageWithContentType(t *testing.T) {
+	notifier := NewEmailNotifier(EmailConfig{
+		From: "sender@example.com",
+		To:   []string{"recipient@example.com"},
+	})
+
+	message := notifier.buildMessageWithContentType("Subject", "<p>Body</p>", "text/html")
+	if !strings.Contains(message, "Content-Type: text/html; charset=utf-8") {
+		t.Error("message missing HTML content type header")
+	}
+}
@@ -0,0 +1,97 @@
+package external
+
+import (
+	"fmt"
+	"html"
+	"regexp"
+	"strings"
+)
+
+var (
+	markdownBold       = regexp.MustCompile(`\*\*(.+?)\*\*`)
+	markdownInlineCode = regexp.MustCompile("`([^`]+)`")
+)
+
+// renderMarkdownHTML converts the markdown subset produced by the review
+// board report (headings, bold, inline code, bullet lists, fenced code
+// blocks and paragraphs) into HTML. All text is escaped before formatting.
+func renderMarkdownHTML(markdown string) string {
+	var out strings.Builder
+	var paragraph []string
+	inList := false
+	inCode := false
+
+	flushParagraph := func() {
+		if len(paragraph) > 0 {
+			out.WriteString("<p>" + strings.Join(paragraph, "<br>\n") + "</p>\n")
+			paragraph = nil
+		}
+	}
+	closeList := func() {
+		if inList {
+			out.WriteString("</ul>\n")
+			inList = false
+		}
+	}
+
+	for _, line := range strings.Split(strings.ReplaceAll(markdown, "\r\n", "\n"), "\n") {
+		trimmed := strings.TrimSpace(line)
+
+		if strings.HasPrefix(trimmed, "```") {
+			if inCode {
+				out.WriteString("</code></pre>\n")
+				inCode = false
+			} else {
+				flushParagraph()
+				closeList()
+				out.WriteString("<pre><code>")
+				inCode = true
+			}
+			continue
+		}
+		if inCode {
+			out.WriteString(html.EscapeString(line) + "\n")
+			continue
+		}
+
+		switch {
+		case trimmed == "":
+			flushParagraph()
+			closeList()
+		case strings.HasPrefix(trimmed, "#"):
+			level := len(trimmed) - len(strings.TrimLeft(trimmed, "#"))
+			if level > 6 || (len(trimmed) > level && trimmed[level] != ' ') {
+				paragraph = append(paragraph, renderInline(trimmed))
+				continue
+			}
+			flushParagraph()
+			closeList()
+			out.WriteString(fmt.Sprintf("<h%d>%s</h%d>\n", level, renderInline(strings.TrimSpace(trimmed[level:])), level))
+		case strings.HasPrefix(trimmed, "- ") || strings.HasPrefix(trimmed, "* "):
+			flushParagraph()
+			if !inList {
+				out.WriteString("<ul>\n")
+				inList = true
+			}
+			out.WriteString("<li>" + renderInline(trimmed[2:]) + "</li>\n")
+		default:
+			closeList()
+			paragraph = append(paragraph, renderInline(trimmed))
+		}
+	}
+
+	if inCode {
+		out.WriteString("</code></pre>\n")
+	}
+	flushParagraph()
+	closeList()
+
+	return out.String()
+}
+
+// renderInline escapes a line of text and applies bold and inline code spans
+func renderInline(text string) string {
+	escaped := html.EscapeString(text)
+	escaped = markdownInlineCode.ReplaceAllString(escaped, "<code>$1</code>")
+	return markdownBold.ReplaceAllString(escaped, "<strong>$1</strong>")
+}
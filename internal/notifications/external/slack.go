@@ -1,11 +1,9 @@
 package external
 
 import (
-	"bytes"
 	"encoding/json"
 	"fmt"
 	"net/http"
-	"time"
 
 	"github.com/CLIAIMONITOR/internal/events"
 )
@@ -23,16 +21,16 @@ type SlackConfig struct {
 // SlackNotifier sends notifications to Slack via webhooks
 type SlackNotifier struct {
 	config SlackConfig
-	client *http.Client
+	sender *webhookSender
 }
 
-// NewSlackNotifier creates a new Slack notifier
-func NewSlackNotifier(config SlackConfig) *SlackNotifier {
+// NewSlackNotifier creates a new Slack notifier. Rate-limited posts are
+// retried with exponential backoff; opts can change the retry policy or
+// transport.
+func NewSlackNotifier(config SlackConfig, opts ...Option) *SlackNotifier {
 	return &SlackNotifier{
 		config: config,
-		client: &http.Client{
-			Timeout: 10 * time.Second,
-		},
+		sender: newWebhookSender(opts),
 	}
 }
 
@@ -145,7 +143,7 @@ func (s *SlackNotifier) Send(event events.Event) error {
 	}
 
 	// Send HTTP request
-	resp, err := s.client.Post(s.config.WebhookURL, "application/json", bytes.NewBuffer(jsonData))
+	resp, err := s.sender.post(s.config.WebhookURL, jsonData)
 	if err != nil {
 		return fmt.Errorf("failed to send slack notification: %w", err)
 	}
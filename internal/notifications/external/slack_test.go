@@ -5,7 +5,9 @@ import (
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/CLIAIMONITOR/internal/events"
 )
@@ -285,3 +287,142 @@ func TestSlackNotifier_Send_ServerError(t *testing.T) {
 		t.Error("expected error for server error response")
 	}
 }
+
+// roundTripperFunc lets a test answer webhook requests without a server
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(r *http.Request) (*http.Response, error) {
+	return f(r)
+}
+
+// recordSleeps replaces the sender's sleep so backoff delays can be checked
+// without waiting for them
+func recordSleeps(w *webhookSender) *[]time.Duration {
+	var delays []time.Duration
+	w.sleep = func(d time.Duration) { delays = append(delays, d) }
+	return &delays
+}
+
+func TestSlackNotifier_Send_CustomTransport(t *testing.T) {
+	var got map[string]interface{}
+	transport := roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+		if r.Header.Get("Content-Type") != "application/json" {
+			t.Errorf("expected JSON content type, got %q", r.Header.Get("Content-Type"))
+		}
+		json.NewDecoder(r.Body).Decode(&got)
+		return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader("ok")), Header: http.Header{}}, nil
+	})
+
+	notifier := NewSlackNotifier(SlackConfig{WebhookURL: "https://hooks.slack.invalid/T000"}, WithTransport(transport))
+	err := notifier.Send(events.Event{ID: "evt-1", Type: events.EventAlert, Source: "captain", Priority: events.PriorityNormal})
+	if err != nil {
+		t.Fatalf("Send failed: %v", err)
+	}
+	if got["text"] != "Event: evt-1" {
+		t.Errorf("expected text 'Event: evt-1', got %v", got["text"])
+	}
+}
+
+func TestSlackNotifier_Send_RetriesOnRateLimit(t *testing.T) {
+	tests := []struct {
+		name        string
+		limited     int
+		retryAfter  string
+		expectError bool
+		wantDelays  []time.Duration
+	}{
+		{
+			name:       "exponential backoff",
+			limited:    2,
+			wantDelays: []time.Duration{10 * time.Millisecond, 20 * time.Millisecond},
+		},
+		{
+			name:       "honors Retry-After",
+			limited:    1,
+			retryAfter: "2",
+			wantDelays: []time.Duration{2 * time.Second},
+		},
+		{
+			name:        "gives up after max retries",
+			limited:     10,
+			expectError: true,
+			wantDelays:  []time.Duration{10 * time.Millisecond, 20 * time.Millisecond, 40 * time.Millisecond},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := 0
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				calls++
+				if calls <= tt.limited {
+					if tt.retryAfter != "" {
+						w.Header().Set("Retry-After", tt.retryAfter)
+					}
+					w.WriteHeader(http.StatusTooManyRequests)
+					return
+				}
+				w.WriteHeader(http.StatusOK)
+			}))
+			defer server.Close()
+
+			notifier := NewSlackNotifier(SlackConfig{WebhookURL: server.URL}, WithRetry(3, 10*time.Millisecond))
+			delays := recordSleeps(notifier.sender)
+
+			err := notifier.Send(events.Event{ID: "evt-1", Type: events.EventAlert, Priority: events.PriorityHigh})
+			if (err != nil) != tt.expectError {
+				t.Fatalf("expectError=%v, got %v", tt.expectError, err)
+			}
+			if len(*delays) != len(tt.wantDelays) {
+				t.Fatalf("expected delays %v, got %v", tt.wantDelays, *delays)
+			}
+			for i, want := range tt.wantDelays {
+				if (*delays)[i] != want {
+					t.Errorf("retry %d: expected delay %v, got %v", i+1, want, (*delays)[i])
+				}
+			}
+		})
+	}
+}
+
+func TestSlackNotifier_Send_EventMatrix(t *testing.T) {
+	tests := []struct {
+		eventType events.EventType
+		priority  int
+		color     string
+		priorityS string
+	}{
+		{events.EventAlert, events.PriorityCritical, "danger", "Critical"},
+		{events.EventAlert, events.PriorityHigh, "warning", "High"},
+		{events.EventTask, events.PriorityNormal, "good", "Normal"},
+		{events.EventMessage, events.PriorityLow, "good", "Low"},
+		{events.EventStopApproval, events.PriorityCritical, "danger", "Critical"},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.eventType)+"/"+tt.priorityS, func(t *testing.T) {
+			var payload map[string]interface{}
+			transport := roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+				json.NewDecoder(r.Body).Decode(&payload)
+				return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader("ok")), Header: http.Header{}}, nil
+			})
+			notifier := NewSlackNotifier(SlackConfig{WebhookURL: "https://hooks.slack.invalid/T000"}, WithTransport(transport))
+
+			if err := notifier.Send(events.Event{ID: "evt", Type: tt.eventType, Source: "captain", Priority: tt.priority}); err != nil {
+				t.Fatalf("Send failed: %v", err)
+			}
+
+			attachment := payload["attachments"].([]interface{})[0].(map[string]interface{})
+			if attachment["color"] != tt.color {
+				t.Errorf("expected color %q, got %v", tt.color, attachment["color"])
+			}
+			if attachment["title"] != string(tt.eventType)+" Event" {
+				t.Errorf("expected title %q, got %v", string(tt.eventType)+" Event", attachment["title"])
+			}
+			fields := attachment["fields"].([]interface{})
+			if p := fields[2].(map[string]interface{}); p["value"] != tt.priorityS {
+				t.Errorf("expected priority %q, got %v", tt.priorityS, p["value"])
+			}
+		})
+	}
+}
@@ -0,0 +1,90 @@
+package external
+
+import (
+	"bytes"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Webhook delivery defaults shared by the Slack and Discord notifiers
+const (
+	webhookTimeout = 10 * time.Second
+
+	// DefaultWebhookMaxRetries is how many times a rate-limited (429) webhook
+	// post is retried before giving up
+	DefaultWebhookMaxRetries = 3
+
+	// DefaultWebhookBackoff is the wait before the first retry; it doubles on
+	// each further retry unless the server sends Retry-After
+	DefaultWebhookBackoff = time.Second
+)
+
+// webhookSender posts JSON payloads to a webhook, retrying when rate limited
+type webhookSender struct {
+	client     *http.Client
+	maxRetries int
+	backoff    time.Duration
+	sleep      func(time.Duration)
+}
+
+// Option customizes how a webhook notifier delivers messages
+type Option func(*webhookSender)
+
+// WithTransport sends webhook requests through rt instead of the default
+// transport, e.g. to route through a proxy or to mock the endpoint in tests
+func WithTransport(rt http.RoundTripper) Option {
+	return func(w *webhookSender) {
+		w.client.Transport = rt
+	}
+}
+
+// WithRetry sets how many times a rate-limited post is retried and the
+// initial backoff between attempts. maxRetries of 0 disables retries.
+func WithRetry(maxRetries int, backoff time.Duration) Option {
+	return func(w *webhookSender) {
+		w.maxRetries = maxRetries
+		w.backoff = backoff
+	}
+}
+
+// newWebhookSender applies opts over the defaults
+func newWebhookSender(opts []Option) *webhookSender {
+	w := &webhookSender{
+		client:     &http.Client{Timeout: webhookTimeout},
+		maxRetries: DefaultWebhookMaxRetries,
+		backoff:    DefaultWebhookBackoff,
+		sleep:      time.Sleep,
+	}
+	for _, opt := range opts {
+		opt(w)
+	}
+	return w
+}
+
+// post sends body to url, retrying with exponential backoff while the
+// webhook answers 429 Too Many Requests. The last response is returned for
+// the caller to check and close.
+func (w *webhookSender) post(url string, body []byte) (*http.Response, error) {
+	for attempt := 0; ; attempt++ {
+		resp, err := w.client.Post(url, "application/json", bytes.NewReader(body))
+		if err != nil {
+			return nil, err
+		}
+		if resp.StatusCode != http.StatusTooManyRequests || attempt >= w.maxRetries {
+			return resp, nil
+		}
+		delay := retryAfter(resp, w.backoff<<attempt)
+		resp.Body.Close()
+		w.sleep(delay)
+	}
+}
+
+// retryAfter returns the delay requested by the response's Retry-After
+// header in seconds, or fallback when it is missing or not a number
+func retryAfter(resp *http.Response, fallback time.Duration) time.Duration {
+	if secs, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && secs >= 0 {
+		return time.Duration(secs) * time.Second
+	}
+	return fallback
+}
@@ -1,10 +1,10 @@
 package notifications
 
 import (
-	"log"
 	"sync"
 
 	"github.com/CLIAIMONITOR/internal/events"
+	"github.com/CLIAIMONITOR/internal/logger"
 )
 
 // NotificationChannel represents a channel that can send notifications
@@ -76,7 +76,8 @@ func (r *Router) Route(event events.Event) {
 
 			// Send the event to the channel
 			if err := channel.Send(event); err != nil {
-				log.Printf("[NOTIFY-ROUTER] failed to send event %s to channel %s: %v", event.ID, channel.Name(), err)
+				logger.For("notify-router").Error("failed to send event to channel",
+					"event_id", event.ID, "channel", channel.Name(), "error", err)
 			}
 		}(ch)
 	}
@@ -105,7 +106,8 @@ func (r *Router) RouteWithWait(event events.Event) {
 
 			// Send the event to the channel
 			if err := channel.Send(event); err != nil {
-				log.Printf("[NOTIFY-ROUTER] failed to send event %s to channel %s: %v", event.ID, channel.Name(), err)
+				logger.For("notify-router").Error("failed to send event to channel",
+					"event_id", event.ID, "channel", channel.Name(), "error", err)
 			}
 		}(ch)
 	}
@@ -0,0 +1,154 @@
+package persistence
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/CLIAIMONITOR/internal/types"
+)
+
+// ActivityLogMaxEntries is how many activity entries are kept in memory and
+// in the state file. Older entries are queued for the activity archive.
+const ActivityLogMaxEntries = 500
+
+// ActivityArchiveMaxPending bounds entries queued for the archive, so a
+// failing archive write cannot grow memory without limit
+const ActivityArchiveMaxPending = 10000
+
+// ActivityArchiveFile is the archive's file name, kept next to the state file
+const ActivityArchiveFile = "activity-archive.ndjson.gz"
+
+// ActivityArchivePath returns where this store's activity archive lives
+func (s *JSONStore) ActivityArchivePath() string {
+	return filepath.Join(filepath.Dir(s.filepath), ActivityArchiveFile)
+}
+
+// queueForArchive adds trimmed activity entries to the archive queue. Caller
+// must hold s.mu.
+func (s *JSONStore) queueForArchive(entries []*types.ActivityLog) {
+	s.archivePending = append(s.archivePending, entries...)
+	if over := len(s.archivePending) - ActivityArchiveMaxPending; over > 0 {
+		s.archivePending = s.archivePending[over:]
+	}
+}
+
+// RotateActivityLog keeps the newest maxEntries activity entries in memory
+// and appends older ones, plus any already trimmed by AddActivity, to the
+// gzip-compressed NDJSON file at archivePath. Returns the number archived.
+func (s *JSONStore) RotateActivityLog(maxEntries int, archivePath string) (int, error) {
+	if maxEntries < 0 {
+		maxEntries = 0
+	}
+
+	s.mu.Lock()
+	trimmed := false
+	if excess := len(s.state.ActivityLog) - maxEntries; excess > 0 {
+		s.queueForArchive(s.state.ActivityLog[:excess])
+		s.state.ActivityLog = append([]*types.ActivityLog(nil), s.state.ActivityLog[excess:]...)
+		trimmed = true
+	}
+	entries := s.archivePending
+	s.archivePending = nil
+	s.mu.Unlock()
+
+	if trimmed {
+		s.scheduleSave()
+	}
+	if len(entries) == 0 {
+		return 0, nil
+	}
+
+	s.archiveMu.Lock()
+	err := appendActivityArchive(archivePath, entries)
+	s.archiveMu.Unlock()
+	if err != nil {
+		// Requeue ahead of anything trimmed meanwhile so the next rotation retries
+		s.mu.Lock()
+		pending := s.archivePending
+		s.archivePending = nil
+		s.queueForArchive(entries)
+		s.queueForArchive(pending)
+		s.mu.Unlock()
+		return 0, err
+	}
+	return len(entries), nil
+}
+
+// appendActivityArchive writes entries as a new gzip member at the end of the
+// archive. gzip readers treat concatenated members as one stream.
+func appendActivityArchive(archivePath string, entries []*types.ActivityLog) error {
+	if err := os.MkdirAll(filepath.Dir(archivePath), 0755); err != nil {
+		return fmt.Errorf("failed to create archive directory: %w", err)
+	}
+
+	f, err := os.OpenFile(archivePath, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open activity archive: %w", err)
+	}
+	defer f.Close()
+
+	gz := gzip.NewWriter(f)
+	enc := json.NewEncoder(gz)
+	for _, entry := range entries {
+		if err := enc.Encode(entry); err != nil {
+			gz.Close()
+			return fmt.Errorf("failed to write activity archive: %w", err)
+		}
+	}
+	if err := gz.Close(); err != nil {
+		return fmt.Errorf("failed to write activity archive: %w", err)
+	}
+	return f.Close()
+}
+
+// ReadActivityArchive returns archived activity entries with a timestamp in
+// [from, to], oldest first. A zero from or to leaves that end open. At most
+// limit entries are returned (0 means no limit); truncated reports whether
+// more matched.
+func (s *JSONStore) ReadActivityArchive(archivePath string, from, to time.Time, limit int) (entries []*types.ActivityLog, truncated bool, err error) {
+	s.archiveMu.Lock()
+	defer s.archiveMu.Unlock()
+
+	entries = []*types.ActivityLog{}
+	f, err := os.Open(archivePath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return entries, false, nil
+		}
+		return nil, false, fmt.Errorf("failed to open activity archive: %w", err)
+	}
+	defer f.Close()
+
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return entries, false, nil
+		}
+		return nil, false, fmt.Errorf("failed to read activity archive: %w", err)
+	}
+	defer gz.Close()
+
+	dec := json.NewDecoder(gz)
+	for {
+		var entry types.ActivityLog
+		if err := dec.Decode(&entry); err != nil {
+			if errors.Is(err, io.EOF) {
+				return entries, false, nil
+			}
+			return nil, false, fmt.Errorf("failed to decode activity archive: %w", err)
+		}
+		if (!from.IsZero() && entry.Timestamp.Before(from)) || (!to.IsZero() && entry.Timestamp.After(to)) {
+			continue
+		}
+		if limit > 0 && len(entries) == limit {
+			return entries, true, nil
+		}
+		entries = append(entries, &entry)
+	}
+}
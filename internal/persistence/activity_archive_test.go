@@ -0,0 +1,75 @@
+package persistence
+
+import (
+	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/CLIAIMONITOR/internal/types"
+)
+
+func TestRotateActivityLog(t *testing.T) {
+	tmpDir := t.TempDir()
+	store := NewJSONStore(filepath.Join(tmpDir, "data", "state.json"))
+	store.Load()
+	archivePath := store.ActivityArchivePath()
+
+	base := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i := 0; i < ActivityLogMaxEntries+20; i++ {
+		store.AddActivity(&types.ActivityLog{
+			ID:        fmt.Sprintf("act-%03d", i),
+			Action:    "test",
+			Timestamp: base.Add(time.Duration(i) * time.Minute),
+		})
+	}
+	if got := len(store.GetState().ActivityLog); got != ActivityLogMaxEntries {
+		t.Fatalf("expected %d in-memory entries, got %d", ActivityLogMaxEntries, got)
+	}
+
+	// The 20 entries trimmed by AddActivity plus 10 more rotated out
+	archived, err := store.RotateActivityLog(ActivityLogMaxEntries-10, archivePath)
+	if err != nil {
+		t.Fatalf("RotateActivityLog() error = %v", err)
+	}
+	if archived != 30 {
+		t.Errorf("archived = %d, want 30", archived)
+	}
+	state := store.GetState()
+	if len(state.ActivityLog) != ActivityLogMaxEntries-10 || state.ActivityLog[0].ID != "act-030" {
+		t.Errorf("unexpected in-memory log: %d entries starting at %s", len(state.ActivityLog), state.ActivityLog[0].ID)
+	}
+
+	// A second rotation appends another gzip member
+	if _, err := store.RotateActivityLog(ActivityLogMaxEntries-15, archivePath); err != nil {
+		t.Fatalf("RotateActivityLog() error = %v", err)
+	}
+	if n, _ := store.RotateActivityLog(ActivityLogMaxEntries-15, archivePath); n != 0 {
+		t.Errorf("expected nothing left to archive, got %d", n)
+	}
+
+	all, truncated, err := store.ReadActivityArchive(archivePath, time.Time{}, time.Time{}, 0)
+	if err != nil {
+		t.Fatalf("ReadActivityArchive() error = %v", err)
+	}
+	if len(all) != 35 || truncated || all[0].ID != "act-000" || all[34].ID != "act-034" {
+		t.Fatalf("unexpected archive: %d entries, truncated=%v", len(all), truncated)
+	}
+
+	ranged, truncated, err := store.ReadActivityArchive(archivePath, base.Add(10*time.Minute), base.Add(19*time.Minute), 5)
+	if err != nil {
+		t.Fatalf("ReadActivityArchive() error = %v", err)
+	}
+	if len(ranged) != 5 || !truncated || ranged[0].ID != "act-010" {
+		t.Errorf("unexpected range: %d entries, truncated=%v", len(ranged), truncated)
+	}
+}
+
+func TestReadActivityArchiveMissing(t *testing.T) {
+	store := NewJSONStore(filepath.Join(t.TempDir(), "state.json"))
+
+	entries, truncated, err := store.ReadActivityArchive(store.ActivityArchivePath(), time.Time{}, time.Time{}, 0)
+	if err != nil || truncated || len(entries) != 0 {
+		t.Errorf("expected empty archive, got %d entries (truncated=%v, err=%v)", len(entries), truncated, err)
+	}
+}
@@ -0,0 +1,37 @@
+package persistence
+
+import (
+	"fmt"
+	"os"
+)
+
+// fileLock is an advisory lock held on a companion ".lock" file. It
+// serializes writers of the guarded file across goroutines and processes;
+// readers take it shared.
+type fileLock struct {
+	f *os.File
+}
+
+// acquireFileLock blocks until it holds the lock for path, exclusive for
+// writers or shared for readers
+func acquireFileLock(path string, exclusive bool) (*fileLock, error) {
+	f, err := os.OpenFile(path+".lock", os.O_CREATE|os.O_RDWR, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open lock file: %w", err)
+	}
+	if err := lockFile(f, exclusive); err != nil {
+		f.Close()
+		return nil, fmt.Errorf("failed to lock %s: %w", path, err)
+	}
+	return &fileLock{f: f}, nil
+}
+
+// release unlocks and closes the lock file. The file itself is left in
+// place so every process keeps locking the same inode.
+func (l *fileLock) release() error {
+	err := unlockFile(l.f)
+	if cerr := l.f.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
@@ -0,0 +1,28 @@
+//go:build !windows
+// +build !windows
+
+package persistence
+
+import (
+	"os"
+	"syscall"
+)
+
+// lockFile takes a flock on f, waiting for any conflicting holder
+func lockFile(f *os.File, exclusive bool) error {
+	how := syscall.LOCK_SH
+	if exclusive {
+		how = syscall.LOCK_EX
+	}
+	for {
+		err := syscall.Flock(int(f.Fd()), how)
+		if err != syscall.EINTR {
+			return err
+		}
+	}
+}
+
+// unlockFile releases the flock on f
+func unlockFile(f *os.File) error {
+	return syscall.Flock(int(f.Fd()), syscall.LOCK_UN)
+}
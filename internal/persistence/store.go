@@ -2,6 +2,7 @@ package persistence
 
 import (
 	"encoding/json"
+	"fmt"
 	"os"
 	"path/filepath"
 	"sync"
@@ -26,6 +27,7 @@ type Store interface {
 
 	// Metrics operations
 	UpdateMetrics(agentID string, metrics *types.AgentMetrics)
+	AddTokenUsage(agentID string, tokens int64, cost float64)
 	GetMetrics(agentID string) *types.AgentMetrics
 	TakeMetricsSnapshot()
 
@@ -82,6 +84,11 @@ type JSONStore struct {
 	filepath string
 	state    *types.DashboardState
 
+	// Activity entries trimmed from the state, waiting for RotateActivityLog
+	// to archive them (guarded by mu). archiveMu serializes archive file access.
+	archivePending []*types.ActivityLog
+	archiveMu      sync.Mutex
+
 	// Debounced save
 	saveTimer *time.Timer
 	saveMu    sync.Mutex
@@ -97,15 +104,22 @@ func NewJSONStore(filepath string) *JSONStore {
 
 // Load reads state from JSON file
 func (s *JSONStore) Load() (*types.DashboardState, error) {
-	s.mu.Lock()
-	defer s.mu.Unlock()
-
 	// Ensure directory exists
 	dir := filepath.Dir(s.filepath)
 	if err := os.MkdirAll(dir, 0755); err != nil {
 		return nil, err
 	}
 
+	// The file lock is always taken before mu, as Save does
+	lock, err := acquireFileLock(s.filepath, false)
+	if err != nil {
+		return nil, err
+	}
+	defer lock.release()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
 	data, err := os.ReadFile(s.filepath)
 	if err != nil {
 		if os.IsNotExist(err) {
@@ -145,6 +159,14 @@ func (s *JSONStore) Load() (*types.DashboardState, error) {
 
 // Save writes state to JSON file
 func (s *JSONStore) Save() error {
+	// Held across marshal and rename so concurrent saves neither share the
+	// temp file nor land out of order
+	lock, err := acquireFileLock(s.filepath, true)
+	if err != nil {
+		return err
+	}
+	defer lock.release()
+
 	s.mu.RLock()
 	data, err := json.MarshalIndent(s.state, "", "  ")
 	if err != nil {
@@ -153,13 +175,44 @@ func (s *JSONStore) Save() error {
 	}
 	s.mu.RUnlock()
 
-	// Write to temp file first, then rename atomically
-	tempPath := s.filepath + ".tmp"
-	if err := os.WriteFile(tempPath, data, 0644); err != nil {
+	return writeStateFile(s.filepath, data)
+}
+
+// ReplaceState overwrites the state file with data, such as a state.json
+// taken from a backup, and reloads it
+func (s *JSONStore) ReplaceState(data []byte) error {
+	var state types.DashboardState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return fmt.Errorf("invalid state: %w", err)
+	}
+
+	if err := s.writeLocked(data); err != nil {
+		return err
+	}
+
+	_, err := s.Load()
+	return err
+}
+
+// writeLocked replaces the state file with data under its exclusive file lock
+func (s *JSONStore) writeLocked(data []byte) error {
+	lock, err := acquireFileLock(s.filepath, true)
+	if err != nil {
 		return err
 	}
+	defer lock.release()
+
+	return writeStateFile(s.filepath, data)
+}
 
-	return os.Rename(tempPath, s.filepath)
+// writeStateFile writes data to a temp file, then renames it over path so
+// readers never see a partial file. Callers hold the exclusive file lock.
+func writeStateFile(path string, data []byte) error {
+	tempPath := path + ".tmp"
+	if err := os.WriteFile(tempPath, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tempPath, path)
 }
 
 // scheduleSave debounces save operations
@@ -265,6 +318,24 @@ func (s *JSONStore) UpdateMetrics(agentID string, metrics *types.AgentMetrics) {
 	s.scheduleSave()
 }
 
+// AddTokenUsage adds tokens and cost to an agent's metrics and the session
+// totals in one locked step, so concurrent callers don't lose increments
+func (s *JSONStore) AddTokenUsage(agentID string, tokens int64, cost float64) {
+	s.mu.Lock()
+	// Replace rather than mutate: callers may hold the old pointer
+	metrics := &types.AgentMetrics{}
+	if old := s.state.Metrics[agentID]; old != nil {
+		*metrics = *old
+	}
+	metrics.TokensUsed += tokens
+	metrics.EstimatedCost += cost
+	s.state.Metrics[agentID] = metrics
+	s.state.SessionStats.TotalTokensUsed += tokens
+	s.state.SessionStats.TotalEstimatedCost += cost
+	s.mu.Unlock()
+	s.scheduleSave()
+}
+
 // GetMetrics returns metrics for an agent
 func (s *JSONStore) GetMetrics(agentID string) *types.AgentMetrics {
 	s.mu.RLock()
@@ -476,9 +547,10 @@ func (s *JSONStore) AddActivity(activity *types.ActivityLog) {
 	s.mu.Lock()
 	s.state.ActivityLog = append(s.state.ActivityLog, activity)
 
-	// Keep only last 500 entries
-	if len(s.state.ActivityLog) > 500 {
-		s.state.ActivityLog = s.state.ActivityLog[len(s.state.ActivityLog)-500:]
+	// Keep only the newest entries; older ones wait for RotateActivityLog
+	if excess := len(s.state.ActivityLog) - ActivityLogMaxEntries; excess > 0 {
+		s.queueForArchive(s.state.ActivityLog[:excess])
+		s.state.ActivityLog = s.state.ActivityLog[excess:]
 	}
 	s.mu.Unlock()
 	s.scheduleSave()
@@ -1,8 +1,10 @@
 package persistence
 
 import (
+	"encoding/json"
 	"os"
 	"path/filepath"
+	"sync"
 	"testing"
 	"time"
 
@@ -106,6 +108,30 @@ func TestSaveAndLoad(t *testing.T) {
 	}
 }
 
+func TestReplaceState(t *testing.T) {
+	storePath := filepath.Join(t.TempDir(), "state.json")
+	store := NewJSONStore(storePath)
+	store.Load()
+	store.AddAgent(&types.Agent{ID: "Old001", Status: types.StatusWorking})
+
+	if err := store.ReplaceState([]byte(`not json`)); err == nil {
+		t.Fatal("expected error for invalid state")
+	}
+	if store.GetAgent("Old001") == nil {
+		t.Fatal("invalid state should leave the current state alone")
+	}
+
+	if err := store.ReplaceState([]byte(`{"agents": {"New001": {"id": "New001", "status": "idle"}}}`)); err != nil {
+		t.Fatalf("ReplaceState() error = %v", err)
+	}
+	if store.GetAgent("Old001") != nil || store.GetAgent("New001") == nil {
+		t.Errorf("unexpected agents after replace: %v", store.GetState().Agents)
+	}
+	if _, err := os.Stat(storePath); err != nil {
+		t.Errorf("state file not written: %v", err)
+	}
+}
+
 func TestAddAgent(t *testing.T) {
 	store := NewJSONStore(filepath.Join(t.TempDir(), "state.json"))
 	store.Load()
@@ -189,6 +215,33 @@ func TestUpdateMetrics(t *testing.T) {
 	}
 }
 
+func TestAddTokenUsageConcurrent(t *testing.T) {
+	store := NewJSONStore(filepath.Join(t.TempDir(), "state.json"))
+	store.Load()
+	store.UpdateMetrics("TestAgent", &types.AgentMetrics{TokensUsed: 100, FailedTests: 2})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			store.AddTokenUsage("TestAgent", 500, 0.0015)
+		}()
+	}
+	wg.Wait()
+
+	metrics := store.GetMetrics("TestAgent")
+	if metrics.TokensUsed != 100+50*500 {
+		t.Errorf("TokensUsed = %d, want %d", metrics.TokensUsed, 100+50*500)
+	}
+	if metrics.FailedTests != 2 {
+		t.Errorf("FailedTests = %d, want other metrics kept", metrics.FailedTests)
+	}
+	if total := store.GetState().SessionStats.TotalTokensUsed; total != 100+50*500 {
+		t.Errorf("TotalTokensUsed = %d, want %d", total, 100+50*500)
+	}
+}
+
 func TestMetricsSnapshot(t *testing.T) {
 	store := NewJSONStore(filepath.Join(t.TempDir(), "state.json"))
 	store.Load()
@@ -482,3 +535,52 @@ func TestConcurrentRequestShutdown(t *testing.T) {
 		}
 	}
 }
+
+func TestConcurrentSaveAndLoadFileLocking(t *testing.T) {
+	tmpDir := t.TempDir()
+	storePath := filepath.Join(tmpDir, "state.json")
+	store := NewJSONStore(storePath)
+	store.Load()
+	for i := 0; i < 20; i++ {
+		store.AddAgent(&types.Agent{ID: filepath.Join("Agent", string(rune('A'+i))), Status: types.StatusWorking})
+	}
+
+	// A second store on the same file stands in for another process
+	other := NewJSONStore(storePath)
+
+	const goroutines = 100
+	errs := make(chan error, goroutines*2)
+	var wg sync.WaitGroup
+	for g := 0; g < goroutines; g++ {
+		s := store
+		if g%2 == 1 {
+			s = other
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := s.Save(); err != nil {
+				errs <- err
+			}
+			if _, err := s.Load(); err != nil {
+				errs <- err
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Errorf("concurrent Save/Load failed: %v", err)
+	}
+
+	data, err := os.ReadFile(storePath)
+	if err != nil {
+		t.Fatalf("ReadFile() error = %v", err)
+	}
+	if !json.Valid(data) {
+		t.Fatal("state file is not valid JSON after concurrent saves")
+	}
+	if _, err := os.Stat(storePath + ".tmp"); !os.IsNotExist(err) {
+		t.Errorf("temp file left behind, stat error = %v", err)
+	}
+}
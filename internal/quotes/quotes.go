@@ -2,11 +2,12 @@ package quotes
 
 import (
 	"encoding/json"
-	"log"
 	"math/rand"
 	"os"
 	"path/filepath"
 	"sync"
+
+	"github.com/CLIAIMONITOR/internal/logger"
 )
 
 // QuotesConfig holds all quote categories
@@ -85,7 +86,7 @@ func (m *Manager) Load() error {
 	quotesPath := filepath.Join(m.basePath, "configs", "quotes.json")
 	data, err := os.ReadFile(quotesPath)
 	if err != nil {
-		log.Printf("[QUOTES] Using default quotes (config not found: %v)", err)
+		logger.For("quotes").Info("using default quotes (config not found)", "error", err)
 		m.config = defaultQuotes
 		m.loaded = true
 		return nil
@@ -93,7 +94,7 @@ func (m *Manager) Load() error {
 
 	var config QuotesConfig
 	if err := json.Unmarshal(data, &config); err != nil {
-		log.Printf("[QUOTES] Error parsing quotes.json: %v, using defaults", err)
+		logger.For("quotes").Warn("error parsing quotes.json, using defaults", "error", err)
 		m.config = defaultQuotes
 		m.loaded = true
 		return err
@@ -112,8 +113,8 @@ func (m *Manager) Load() error {
 
 	m.config = config
 	m.loaded = true
-	log.Printf("[QUOTES] Loaded %d spawn, %d shutdown, %d hourly quotes",
-		len(config.Spawn), len(config.Shutdown), len(config.Hourly))
+	logger.For("quotes").Info("loaded quotes",
+		"spawn", len(config.Spawn), "shutdown", len(config.Shutdown), "hourly", len(config.Hourly))
 	return nil
 }
 
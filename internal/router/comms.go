@@ -1,10 +1,10 @@
 package router
 
 import (
-	"log"
 	"sync"
 	"time"
 
+	"github.com/CLIAIMONITOR/internal/logger"
 	"github.com/CLIAIMONITOR/internal/memory"
 )
 
@@ -77,7 +77,8 @@ type StatusUpdate struct {
 func (c *AgentComms) UpdateStatus(update *StatusUpdate) error {
 	// NOTE: Agent status is now tracked in-memory only via JSONStore
 	// This method is kept for API compatibility but does nothing
-	log.Printf("[COMMS] Status update for agent %s: %s - %s", update.AgentID, update.Status, update.CurrentTask)
+	logger.For("comms").Info("status update",
+		"agent_id", update.AgentID, "status", update.Status, "current_task", update.CurrentTask)
 	return nil
 }
 
@@ -111,7 +112,7 @@ func (c *AgentComms) SendSignal(req *SignalRequest) (*SignalResponse, error) {
 		if req.Signal == "resume" {
 			status = "working"
 		}
-		log.Printf("[COMMS] Signal %s -> agent %s: %s", req.Signal, req.ToAgentID, status)
+		logger.For("comms").Info("signal sent", "signal", req.Signal, "agent_id", req.ToAgentID, "status", status)
 	}
 
 	return &SignalResponse{
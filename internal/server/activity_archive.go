@@ -0,0 +1,70 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/CLIAIMONITOR/internal/persistence"
+)
+
+// activityArchiveMaxResults caps entries returned by one archive request
+const activityArchiveMaxResults = 5000
+
+// rotateActivityLog moves activity entries beyond the in-memory limit to the
+// compressed activity archive
+func (s *Server) rotateActivityLog() {
+	archived, err := s.store.RotateActivityLog(persistence.ActivityLogMaxEntries, s.store.ActivityArchivePath())
+	if err != nil {
+		s.log("activity").Warn("failed to archive activity log", "error", err)
+		return
+	}
+	if archived > 0 {
+		s.log("activity").Debug("archived activity entries", "count", archived)
+	}
+}
+
+// handleGetActivityArchive returns archived activity entries between the
+// RFC 3339 times ?from= and ?to=, oldest first (?limit=, default and max 5000)
+func (s *Server) handleGetActivityArchive(w http.ResponseWriter, r *http.Request) {
+	var from, to time.Time
+	for name, dst := range map[string]*time.Time{"from": &from, "to": &to} {
+		raw := r.URL.Query().Get(name)
+		if raw == "" {
+			continue
+		}
+		parsed, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			s.respondAPIError(w, ErrInvalidParameter.WithMessage(fmt.Sprintf("%s must be an RFC 3339 timestamp", name)))
+			return
+		}
+		*dst = parsed
+	}
+	if !from.IsZero() && !to.IsZero() && to.Before(from) {
+		s.respondAPIError(w, ErrInvalidParameter.WithMessage("to must not be before from"))
+		return
+	}
+
+	limit := activityArchiveMaxResults
+	if raw := r.URL.Query().Get("limit"); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil || parsed <= 0 || parsed > activityArchiveMaxResults {
+			s.respondAPIError(w, ErrInvalidParameter.WithMessage(fmt.Sprintf("limit must be between 1 and %d", activityArchiveMaxResults)))
+			return
+		}
+		limit = parsed
+	}
+
+	entries, truncated, err := s.store.ReadActivityArchive(s.store.ActivityArchivePath(), from, to, limit)
+	if err != nil {
+		s.respondAPIError(w, ErrInternal.WithMessage(fmt.Sprintf("Failed to read activity archive: %v", err)))
+		return
+	}
+
+	s.respondJSON(w, map[string]interface{}{
+		"entries":   entries,
+		"count":     len(entries),
+		"truncated": truncated,
+	})
+}
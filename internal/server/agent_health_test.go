@@ -0,0 +1,74 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/CLIAIMONITOR/internal/agents"
+	"github.com/CLIAIMONITOR/internal/captain"
+	"github.com/CLIAIMONITOR/internal/memory"
+	"github.com/CLIAIMONITOR/internal/tasks"
+	"github.com/CLIAIMONITOR/internal/types"
+)
+
+func TestCheckAgentHealthRequeuesCrashedAgentTasks(t *testing.T) {
+	s, memDB := newBackupTestServer(t)
+	s.spawner = agents.NewSpawner(s.basePath, "", nil)
+	s.captain = captain.NewCaptain(s.basePath, nil, memDB, nil)
+
+	queue, err := tasks.NewMemorySQLiteQueue()
+	if err != nil {
+		t.Fatalf("NewMemorySQLiteQueue failed: %v", err)
+	}
+	t.Cleanup(func() { queue.Close() })
+	s.taskQueue = queue
+
+	const agentID = "team-sntgreen001"
+	// No process has this PID, so the agent counts as crashed
+	s.store.AddAgent(&types.Agent{ID: agentID, PID: 999999, Status: types.StatusWorking})
+
+	if err := queue.Add(tasks.NewTask("Fix bug", "", 3)); err != nil {
+		t.Fatalf("Add failed: %v", err)
+	}
+	claimed, err := queue.Claim(agentID)
+	if err != nil || claimed == nil {
+		t.Fatalf("Claim failed: %v", err)
+	}
+
+	if err := memDB.RecordCaptainTask(&memory.CaptainTaskRecord{ID: "mission-1", Title: "Fix bug", TaskType: "implementation", SourceType: "internal", Status: "spawned"}); err != nil {
+		t.Fatalf("RecordCaptainTask failed: %v", err)
+	}
+	if err := memDB.AssignCaptainTask("mission-1", agentID); err != nil {
+		t.Fatalf("AssignCaptainTask failed: %v", err)
+	}
+
+	s.checkAgentHealth()
+
+	task := queue.GetByID(claimed.ID)
+	if task.Status != tasks.StatusPending || task.AssignedTo != "" {
+		t.Errorf("Expected claimed task back in pending and unassigned, got %s assigned to %q", task.Status, task.AssignedTo)
+	}
+	if next := queue.Next(); next == nil || next.ID != claimed.ID {
+		t.Errorf("Expected the task to be claimable again, got %+v", next)
+	}
+
+	records, err := memDB.GetCaptainTasks("", 10)
+	if err != nil || len(records) != 1 {
+		t.Fatalf("GetCaptainTasks = %v, %v", records, err)
+	}
+	if records[0].Status != "pending" || records[0].RetryCount != 1 {
+		t.Errorf("Expected captain task re-queued with retry 1, got %s (retry %d)", records[0].Status, records[0].RetryCount)
+	}
+
+	if agent := s.store.GetAgent(agentID); agent.Status != types.StatusDisconnected {
+		t.Errorf("Expected crashed agent disconnected, got %s", agent.Status)
+	}
+	reassigned := 0
+	for _, a := range s.store.GetState().ActivityLog {
+		if a.Action == "task_reassigned" {
+			reassigned++
+		}
+	}
+	if reassigned != 2 {
+		t.Errorf("Expected 2 task_reassigned activities, got %d", reassigned)
+	}
+}
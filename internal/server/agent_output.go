@@ -0,0 +1,147 @@
+package server
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/CLIAIMONITOR/internal/types"
+	"github.com/CLIAIMONITOR/internal/wezterm"
+	"github.com/gorilla/mux"
+	"github.com/gorilla/websocket"
+)
+
+const (
+	// AgentOutputPollInterval is how often an agent's pane is re-read
+	AgentOutputPollInterval = 2 * time.Second
+
+	// AgentOutputHistoryLines is how much scrollback is read from the pane
+	AgentOutputHistoryLines = 200
+)
+
+// AgentOutput is the payload of an agent_output WebSocket message
+type AgentOutput struct {
+	AgentID string   `json:"agent_id"`
+	Lines   []string `json:"lines"`
+	Initial bool     `json:"initial"` // true for the scrollback sent on connect
+}
+
+// handleAgentOutputWebSocket streams an agent's WezTerm pane output. The
+// existing scrollback is sent on connect, then only lines that appeared since
+// the previous poll.
+func (s *Server) handleAgentOutputWebSocket(w http.ResponseWriter, r *http.Request) {
+	agentID := mux.Vars(r)["id"]
+	if s.spawner == nil {
+		s.respondAPIError(w, ErrServiceUnavailable.WithMessage("Spawner not available"))
+		return
+	}
+
+	paneID, ok := s.spawner.GetAgentPaneID(agentID)
+	if !ok || paneID <= 0 {
+		s.respondAPIError(w, ErrAgentNotFound.WithMessage("Agent pane not found").WithDetails(map[string]string{"agent_id": agentID}))
+		return
+	}
+
+	conn, err := upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+
+	outLog := s.requestLog(r, "agent_output").With("agent_id", agentID, "pane_id", paneID)
+
+	// Drain client messages so close frames are processed
+	closed := make(chan struct{})
+	go func() {
+		defer close(closed)
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}()
+
+	text, err := wezterm.Get().GetPaneText(paneID, -AgentOutputHistoryLines, 0)
+	if err != nil {
+		outLog.Warn("failed to read agent pane", "error", err)
+		return
+	}
+	prev := paneLines(text)
+	if err := sendAgentOutput(conn, agentID, prev, true); err != nil {
+		return
+	}
+
+	ticker := time.NewTicker(AgentOutputPollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-closed:
+			return
+		case <-s.hub.ctx.Done():
+			conn.WriteMessage(websocket.CloseMessage, []byte{})
+			return
+		case <-ticker.C:
+			text, err := wezterm.Get().GetPaneText(paneID, -AgentOutputHistoryLines, 0)
+			if err != nil {
+				// Pane closed or WezTerm gone; nothing more to stream
+				outLog.Info("agent pane no longer readable", "error", err)
+				conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, "pane closed"))
+				return
+			}
+			cur := paneLines(text)
+			if added := newPaneLines(prev, cur); len(added) > 0 {
+				if err := sendAgentOutput(conn, agentID, added, false); err != nil {
+					return
+				}
+			}
+			prev = cur
+		}
+	}
+}
+
+func sendAgentOutput(conn *websocket.Conn, agentID string, lines []string, initial bool) error {
+	if lines == nil {
+		lines = []string{}
+	}
+	return conn.WriteJSON(types.WSMessage{
+		Type: types.WSTypeAgentOutput,
+		Data: AgentOutput{AgentID: agentID, Lines: lines, Initial: initial},
+	})
+}
+
+// paneLines splits pane text into lines, dropping carriage returns and the
+// blank rows WezTerm pads below the cursor
+func paneLines(text string) []string {
+	lines := strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n")
+	for len(lines) > 0 && strings.TrimSpace(lines[len(lines)-1]) == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// newPaneLines returns the lines of cur that follow its overlap with prev.
+// Both are windows over the same scrollback, so the longest suffix of prev
+// that is also a prefix of cur marks where new output starts. With no
+// overlap (e.g. the screen was cleared) all of cur is new.
+func newPaneLines(prev, cur []string) []string {
+	maxOverlap := len(prev)
+	if len(cur) < maxOverlap {
+		maxOverlap = len(cur)
+	}
+	for k := maxOverlap; k > 0; k-- {
+		if equalLines(prev[len(prev)-k:], cur[:k]) {
+			return cur[k:]
+		}
+	}
+	return cur
+}
+
+func equalLines(a, b []string) bool {
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
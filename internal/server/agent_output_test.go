@@ -0,0 +1,41 @@
+package server
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNewPaneLines(t *testing.T) {
+	tests := []struct {
+		name string
+		prev []string
+		cur  []string
+		want []string
+	}{
+		{"unchanged", []string{"a", "b"}, []string{"a", "b"}, []string{}},
+		{"appended", []string{"a", "b"}, []string{"a", "b", "c"}, []string{"c"}},
+		{"scrolled", []string{"a", "b", "c"}, []string{"b", "c", "d", "e"}, []string{"d", "e"}},
+		{"cleared", []string{"a", "b"}, []string{"x"}, []string{"x"}},
+		{"first poll", nil, []string{"a"}, []string{"a"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := newPaneLines(tt.prev, tt.cur)
+			if len(got) == 0 && len(tt.want) == 0 {
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("newPaneLines(%v, %v) = %v, want %v", tt.prev, tt.cur, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPaneLinesTrimsPadding(t *testing.T) {
+	got := paneLines("one\r\ntwo\n\n   \n")
+	want := []string{"one", "two"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("paneLines() = %v, want %v", got, want)
+	}
+}
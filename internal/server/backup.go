@@ -0,0 +1,316 @@
+package server
+
+import (
+	"archive/zip"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/CLIAIMONITOR/internal/memory"
+)
+
+// Backup archive entries. memory.sql is a SQL dump of memory.db in the
+// sqlite3 .dump format.
+const (
+	backupManifestFile = "manifest.json"
+	backupStateFile    = "state.json"
+	backupMemoryFile   = "memory.sql"
+	backupTeamsFile    = "configs/teams.yaml"
+	backupProjectsFile = "configs/projects.yaml"
+	backupCountersFile = "data/agent_counters.json"
+)
+
+// BackupFormatVersion is written to manifest.json and checked on restore
+const BackupFormatVersion = 1
+
+// MaxBackupSize limits uploads to POST /api/restore
+const MaxBackupSize = 256 << 20 // 256MB
+
+// BackupManifest lists the files in a backup archive with their checksums
+type BackupManifest struct {
+	FormatVersion int                   `json:"format_version"`
+	CreatedAt     time.Time             `json:"created_at"`
+	Files         []BackupManifestEntry `json:"files"`
+}
+
+// BackupManifestEntry is one archived file
+type BackupManifestEntry struct {
+	Name   string `json:"name"`
+	SHA256 string `json:"sha256"`
+	Size   int64  `json:"size"`
+}
+
+// backupFileSources maps the optional on-disk files in a backup to their
+// paths under basePath
+func (s *Server) backupFileSources() map[string]string {
+	return map[string]string{
+		backupTeamsFile:    filepath.Join(s.basePath, "configs", "teams.yaml"),
+		backupProjectsFile: filepath.Join(s.basePath, "configs", "projects.yaml"),
+		backupCountersFile: filepath.Join(s.basePath, "data", "agent_counters.json"),
+	}
+}
+
+// buildBackup writes a ZIP archive of the dashboard state, memory database
+// and config files, with a manifest of SHA-256 checksums
+func (s *Server) buildBackup(w io.Writer, sqliteDB *memory.SQLiteMemoryDB) error {
+	files := make(map[string][]byte)
+
+	state, err := json.MarshalIndent(s.store.GetState(), "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode state: %w", err)
+	}
+	files[backupStateFile] = state
+
+	var dump bytes.Buffer
+	if err := sqliteDB.DumpSQL(&dump); err != nil {
+		return fmt.Errorf("failed to dump memory database: %w", err)
+	}
+	files[backupMemoryFile] = dump.Bytes()
+
+	// Flush counters so the archive has the current sequence numbers
+	if s.spawner != nil {
+		if err := s.spawner.SaveCounters(); err != nil {
+			s.log("backup").Warn("failed to save agent counters", "error", err)
+		}
+	}
+	for name, path := range s.backupFileSources() {
+		data, err := os.ReadFile(path)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", name, err)
+		}
+		files[name] = data
+	}
+
+	manifest := BackupManifest{FormatVersion: BackupFormatVersion, CreatedAt: time.Now().UTC()}
+	zw := zip.NewWriter(w)
+	for _, name := range []string{backupStateFile, backupMemoryFile, backupTeamsFile, backupProjectsFile, backupCountersFile} {
+		data, ok := files[name]
+		if !ok {
+			continue
+		}
+		fw, err := zw.Create(name)
+		if err != nil {
+			return fmt.Errorf("failed to add %s: %w", name, err)
+		}
+		if _, err := fw.Write(data); err != nil {
+			return fmt.Errorf("failed to add %s: %w", name, err)
+		}
+		sum := sha256.Sum256(data)
+		manifest.Files = append(manifest.Files, BackupManifestEntry{Name: name, SHA256: hex.EncodeToString(sum[:]), Size: int64(len(data))})
+	}
+
+	fw, err := zw.Create(backupManifestFile)
+	if err != nil {
+		return fmt.Errorf("failed to add manifest: %w", err)
+	}
+	enc := json.NewEncoder(fw)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(manifest); err != nil {
+		return fmt.Errorf("failed to add manifest: %w", err)
+	}
+	return zw.Close()
+}
+
+// readBackup extracts a backup archive and checks every file against the
+// manifest. Only the files a backup can contain are accepted.
+func readBackup(data []byte) (map[string][]byte, error) {
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, fmt.Errorf("not a ZIP archive: %w", err)
+	}
+
+	allowed := map[string]bool{
+		backupManifestFile: true, backupStateFile: true, backupMemoryFile: true,
+		backupTeamsFile: true, backupProjectsFile: true, backupCountersFile: true,
+	}
+	files := make(map[string][]byte)
+	for _, f := range zr.File {
+		if !allowed[f.Name] {
+			return nil, fmt.Errorf("unexpected file %q in backup", f.Name)
+		}
+		rc, err := f.Open()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", f.Name, err)
+		}
+		content, err := io.ReadAll(io.LimitReader(rc, MaxBackupSize+1))
+		rc.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", f.Name, err)
+		}
+		if len(content) > MaxBackupSize {
+			return nil, fmt.Errorf("%s is too large", f.Name)
+		}
+		files[f.Name] = content
+	}
+
+	raw, ok := files[backupManifestFile]
+	if !ok {
+		return nil, fmt.Errorf("backup has no %s", backupManifestFile)
+	}
+	delete(files, backupManifestFile)
+
+	var manifest BackupManifest
+	if err := json.Unmarshal(raw, &manifest); err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", backupManifestFile, err)
+	}
+	if manifest.FormatVersion != BackupFormatVersion {
+		return nil, fmt.Errorf("unsupported backup format version %d", manifest.FormatVersion)
+	}
+
+	listed := make(map[string]bool, len(manifest.Files))
+	for _, entry := range manifest.Files {
+		content, ok := files[entry.Name]
+		if !ok {
+			return nil, fmt.Errorf("%s is listed in the manifest but missing", entry.Name)
+		}
+		sum := sha256.Sum256(content)
+		if hex.EncodeToString(sum[:]) != entry.SHA256 {
+			return nil, fmt.Errorf("checksum mismatch for %s", entry.Name)
+		}
+		listed[entry.Name] = true
+	}
+	for name := range files {
+		if !listed[name] {
+			return nil, fmt.Errorf("%s is not listed in the manifest", name)
+		}
+	}
+	for _, required := range []string{backupStateFile, backupMemoryFile} {
+		if !listed[required] {
+			return nil, fmt.Errorf("backup has no %s", required)
+		}
+	}
+	return files, nil
+}
+
+// writeFileAtomic writes data to a temp file and renames it over path
+func writeFileAtomic(path string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	tempPath := path + ".tmp"
+	if err := os.WriteFile(tempPath, data, 0644); err != nil {
+		return err
+	}
+	if err := os.Rename(tempPath, path); err != nil {
+		os.Remove(tempPath)
+		return err
+	}
+	return nil
+}
+
+// stopAllAgents force-stops every agent in the store, as the stop button does
+func (s *Server) stopAllAgents() int {
+	stopped := 0
+	for agentID := range s.store.GetState().Agents {
+		if s.spawner != nil {
+			s.spawner.StopAgent(agentID)
+			s.spawner.CleanupAgentFiles(agentID)
+		}
+		s.store.RemoveAgent(agentID)
+		s.metrics.RemoveAgent(agentID)
+		stopped++
+	}
+	return stopped
+}
+
+// handleBackup downloads the system state as a ZIP archive
+func (s *Server) handleBackup(w http.ResponseWriter, r *http.Request) {
+	sqliteDB, ok := s.memDB.(*memory.SQLiteMemoryDB)
+	if !ok {
+		s.respondAPIError(w, ErrMemoryDBUnavailable)
+		return
+	}
+
+	var buf bytes.Buffer
+	if err := s.buildBackup(&buf, sqliteDB); err != nil {
+		s.respondAPIError(w, ErrInternal.WithMessage(fmt.Sprintf("Failed to create backup: %v", err)))
+		return
+	}
+
+	filename := fmt.Sprintf("cliaimonitor-backup-%s.zip", time.Now().UTC().Format("20060102-150405"))
+	w.Header().Set("Content-Type", "application/zip")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	w.Header().Set("Content-Length", fmt.Sprintf("%d", buf.Len()))
+	w.Write(buf.Bytes())
+
+	s.requestLog(r, "backup").Info("backup downloaded", "bytes", buf.Len())
+}
+
+// handleRestore replaces the system state with an uploaded backup archive
+// (multipart field "backup"). Running agents are stopped first. The
+// dashboard state, memory database and agent counters are reloaded in
+// place; restored team and project configs apply on the next start.
+func (s *Server) handleRestore(w http.ResponseWriter, r *http.Request) {
+	sqliteDB, ok := s.memDB.(*memory.SQLiteMemoryDB)
+	if !ok {
+		s.respondAPIError(w, ErrMemoryDBUnavailable)
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, MaxBackupSize)
+	file, _, err := r.FormFile("backup")
+	if err != nil {
+		s.respondAPIError(w, ErrInvalidBackup.WithMessage(fmt.Sprintf("Missing backup file: %v", err)))
+		return
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(file)
+	if err != nil {
+		s.respondAPIError(w, ErrInvalidBackup.WithMessage(fmt.Sprintf("Failed to read backup: %v", err)))
+		return
+	}
+	files, err := readBackup(data)
+	if err != nil {
+		s.respondAPIError(w, ErrInvalidBackup.WithMessage(err.Error()))
+		return
+	}
+
+	stopped := s.stopAllAgents()
+
+	if err := sqliteDB.RestoreSQL(string(files[backupMemoryFile])); err != nil {
+		s.respondAPIError(w, ErrRestoreFailed.WithMessage(fmt.Sprintf("Failed to restore memory database: %v", err)))
+		return
+	}
+	if err := s.store.ReplaceState(files[backupStateFile]); err != nil {
+		s.respondAPIError(w, ErrRestoreFailed.WithMessage(fmt.Sprintf("Failed to restore state: %v", err)))
+		return
+	}
+
+	restored := []string{backupMemoryFile, backupStateFile}
+	for name, path := range s.backupFileSources() {
+		content, ok := files[name]
+		if !ok {
+			continue
+		}
+		if err := writeFileAtomic(path, content); err != nil {
+			s.respondAPIError(w, ErrRestoreFailed.WithMessage(fmt.Sprintf("Failed to restore %s: %v", name, err)))
+			return
+		}
+		restored = append(restored, name)
+	}
+	if _, ok := files[backupCountersFile]; ok && s.spawner != nil {
+		if err := s.spawner.LoadCounters(); err != nil {
+			s.log("backup").Warn("failed to reload agent counters", "error", err)
+		}
+	}
+
+	s.broadcastState()
+	s.requestLog(r, "backup").Info("backup restored", "files", len(restored), "agents_stopped", stopped)
+
+	s.respondJSON(w, map[string]interface{}{
+		"success":        true,
+		"restored":       restored,
+		"agents_stopped": stopped,
+	})
+}
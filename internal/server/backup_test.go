@@ -0,0 +1,122 @@
+package server
+
+import (
+	"archive/zip"
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/CLIAIMONITOR/internal/memory"
+	"github.com/CLIAIMONITOR/internal/persistence"
+	"github.com/CLIAIMONITOR/internal/types"
+)
+
+func newBackupTestServer(t *testing.T) (*Server, *memory.SQLiteMemoryDB) {
+	t.Helper()
+	basePath := t.TempDir()
+
+	memDB, err := memory.NewMemoryDB(filepath.Join(basePath, "data", "memory.db"))
+	if err != nil {
+		t.Fatalf("NewMemoryDB failed: %v", err)
+	}
+	t.Cleanup(func() { memDB.Close() })
+
+	store := persistence.NewJSONStore(filepath.Join(basePath, "data", "state.json"))
+	store.Load()
+
+	return &Server{store: store, memDB: memDB, basePath: basePath}, memDB.(*memory.SQLiteMemoryDB)
+}
+
+func TestBuildAndReadBackup(t *testing.T) {
+	s, memDB := newBackupTestServer(t)
+	s.store.AddAgent(&types.Agent{ID: "team-sntgreen001", Status: types.StatusWorking})
+	if err := memDB.SetContext("mission", "ship it", 5, 0); err != nil {
+		t.Fatalf("SetContext failed: %v", err)
+	}
+	teamsPath := filepath.Join(s.basePath, "configs", "teams.yaml")
+	os.MkdirAll(filepath.Dir(teamsPath), 0755)
+	if err := os.WriteFile(teamsPath, []byte("agents: []\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	if err := s.buildBackup(&buf, memDB); err != nil {
+		t.Fatalf("buildBackup failed: %v", err)
+	}
+
+	files, err := readBackup(buf.Bytes())
+	if err != nil {
+		t.Fatalf("readBackup failed: %v", err)
+	}
+	if !strings.Contains(string(files[backupStateFile]), "team-sntgreen001") {
+		t.Error("state.json missing agent")
+	}
+	if !strings.Contains(string(files[backupMemoryFile]), "ship it") {
+		t.Error("memory.sql missing context")
+	}
+	if string(files[backupTeamsFile]) != "agents: []\n" {
+		t.Errorf("teams.yaml = %q", files[backupTeamsFile])
+	}
+	if _, ok := files[backupProjectsFile]; ok {
+		t.Error("missing projects.yaml should be left out")
+	}
+}
+
+// rewriteBackup copies a backup archive, applying edit to each entry's content
+func rewriteBackup(t *testing.T, data []byte, edit func(name string, content []byte) []byte, extra string) []byte {
+	t.Helper()
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for _, f := range zr.File {
+		rc, _ := f.Open()
+		var content bytes.Buffer
+		content.ReadFrom(rc)
+		rc.Close()
+		fw, _ := zw.Create(f.Name)
+		fw.Write(edit(f.Name, content.Bytes()))
+	}
+	if extra != "" {
+		fw, _ := zw.Create(extra)
+		fw.Write([]byte("x"))
+	}
+	zw.Close()
+	return buf.Bytes()
+}
+
+func TestReadBackupRejectsInvalidArchives(t *testing.T) {
+	s, memDB := newBackupTestServer(t)
+	var buf bytes.Buffer
+	if err := s.buildBackup(&buf, memDB); err != nil {
+		t.Fatalf("buildBackup failed: %v", err)
+	}
+	unchanged := func(name string, content []byte) []byte { return content }
+
+	tests := map[string][]byte{
+		"not a zip": []byte("plain text"),
+		"tampered state": rewriteBackup(t, buf.Bytes(), func(name string, content []byte) []byte {
+			if name == backupStateFile {
+				return append(content, ' ')
+			}
+			return content
+		}, ""),
+		"unexpected file": rewriteBackup(t, buf.Bytes(), unchanged, "../../etc/passwd"),
+		"unlisted file":   rewriteBackup(t, buf.Bytes(), unchanged, backupCountersFile),
+		"bad manifest": rewriteBackup(t, buf.Bytes(), func(name string, content []byte) []byte {
+			if name == backupManifestFile {
+				return []byte(`{"format_version": 99}`)
+			}
+			return content
+		}, ""),
+	}
+	for name, data := range tests {
+		if _, err := readBackup(data); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+}
@@ -0,0 +1,110 @@
+package server
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// broadcastDebounce is how long state broadcasts are collected before one
+// consolidated update is sent
+const broadcastDebounce = 50 * time.Millisecond
+
+// broadcastMaxDelay caps how long a steady stream of mutations can hold
+// back a state update
+const broadcastMaxDelay = 250 * time.Millisecond
+
+// ThrottledBroadcaster coalesces bursts of state broadcasts. Each Trigger
+// restarts the debounce timer; when it fires, send runs once and reads
+// whatever state is current by then.
+type ThrottledBroadcaster struct {
+	// BroadcastCount is how many broadcasts were actually sent (atomic)
+	BroadcastCount uint64
+	// RequestCount is how many broadcasts were requested (atomic)
+	RequestCount uint64
+
+	mu           sync.Mutex
+	send         func()
+	debounce     time.Duration
+	maxDelay     time.Duration
+	timer        *time.Timer
+	pendingSince time.Time // zero when nothing is pending
+}
+
+// NewThrottledBroadcaster returns a broadcaster that calls send at most once
+// per burst of Trigger calls
+func NewThrottledBroadcaster(debounce time.Duration, send func()) *ThrottledBroadcaster {
+	return &ThrottledBroadcaster{
+		send:     send,
+		debounce: debounce,
+		maxDelay: broadcastMaxDelay,
+	}
+}
+
+// Trigger schedules a broadcast, pushing back any pending one by the
+// debounce interval, but never past maxDelay after the first pending call
+func (b *ThrottledBroadcaster) Trigger() {
+	atomic.AddUint64(&b.RequestCount, 1)
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := time.Now()
+	if b.pendingSince.IsZero() {
+		b.pendingSince = now
+	}
+	wait := b.debounce
+	if remaining := b.pendingSince.Add(b.maxDelay).Sub(now); remaining < wait {
+		wait = remaining
+	}
+	if b.timer == nil {
+		b.timer = time.AfterFunc(wait, b.fire)
+	} else {
+		b.timer.Reset(wait)
+	}
+}
+
+// Flush sends a pending broadcast immediately, e.g. before shutdown
+func (b *ThrottledBroadcaster) Flush() {
+	b.mu.Lock()
+	pending := !b.pendingSince.IsZero()
+	if b.timer != nil {
+		b.timer.Stop()
+	}
+	b.mu.Unlock()
+
+	if pending {
+		b.fire()
+	}
+}
+
+// Count returns how many broadcasts were sent. A nil broadcaster reports 0.
+func (b *ThrottledBroadcaster) Count() uint64 {
+	if b == nil {
+		return 0
+	}
+	return atomic.LoadUint64(&b.BroadcastCount)
+}
+
+// Requests returns how many broadcasts were requested. A nil broadcaster
+// reports 0.
+func (b *ThrottledBroadcaster) Requests() uint64 {
+	if b == nil {
+		return 0
+	}
+	return atomic.LoadUint64(&b.RequestCount)
+}
+
+// fire sends the consolidated broadcast if one is still pending
+func (b *ThrottledBroadcaster) fire() {
+	b.mu.Lock()
+	if b.pendingSince.IsZero() {
+		b.mu.Unlock()
+		return
+	}
+	b.pendingSince = time.Time{}
+	b.mu.Unlock()
+
+	atomic.AddUint64(&b.BroadcastCount, 1)
+	b.send()
+}
@@ -0,0 +1,72 @@
+package server
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestThrottledBroadcasterCoalescesBurst(t *testing.T) {
+	var sent int32
+	b := NewThrottledBroadcaster(broadcastDebounce, func() { atomic.AddInt32(&sent, 1) })
+
+	start := time.Now()
+	for i := 0; i < 100; i++ {
+		b.Trigger()
+	}
+	if elapsed := time.Since(start); elapsed >= broadcastDebounce {
+		t.Skipf("burst took %s, longer than the debounce window", elapsed)
+	}
+
+	time.Sleep(3 * broadcastDebounce)
+	if n := atomic.LoadInt32(&sent); n != 1 {
+		t.Errorf("100 rapid triggers sent %d broadcasts, want 1", n)
+	}
+	if b.Count() != 1 || b.Requests() != 100 {
+		t.Errorf("Count() = %d, Requests() = %d; want 1 and 100", b.Count(), b.Requests())
+	}
+
+	// A later burst is a new broadcast
+	b.Trigger()
+	time.Sleep(3 * broadcastDebounce)
+	if b.Count() != 2 {
+		t.Errorf("Count() after second burst = %d, want 2", b.Count())
+	}
+}
+
+func TestThrottledBroadcasterMaxDelay(t *testing.T) {
+	var sent int32
+	b := NewThrottledBroadcaster(20*time.Millisecond, func() { atomic.AddInt32(&sent, 1) })
+	b.maxDelay = 60 * time.Millisecond
+
+	// Triggers every 5ms would postpone a pure debounce forever
+	deadline := time.Now().Add(200 * time.Millisecond)
+	for time.Now().Before(deadline) {
+		b.Trigger()
+		time.Sleep(5 * time.Millisecond)
+	}
+	if n := atomic.LoadInt32(&sent); n < 2 {
+		t.Errorf("continuous triggers sent %d broadcasts in 200ms, want at least 2", n)
+	}
+}
+
+func TestThrottledBroadcasterFlush(t *testing.T) {
+	var sent int32
+	b := NewThrottledBroadcaster(time.Hour, func() { atomic.AddInt32(&sent, 1) })
+
+	b.Flush()
+	if n := atomic.LoadInt32(&sent); n != 0 {
+		t.Errorf("Flush with nothing pending sent %d broadcasts", n)
+	}
+
+	b.Trigger()
+	b.Flush()
+	if n := atomic.LoadInt32(&sent); n != 1 {
+		t.Errorf("Flush sent %d broadcasts, want 1", n)
+	}
+
+	var nilBroadcaster *ThrottledBroadcaster
+	if nilBroadcaster.Count() != 0 || nilBroadcaster.Requests() != 0 {
+		t.Error("nil broadcaster should report zero counts")
+	}
+}
@@ -0,0 +1,163 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/CLIAIMONITOR/internal/agents"
+	"github.com/CLIAIMONITOR/internal/events"
+	"github.com/CLIAIMONITOR/internal/memory"
+	"github.com/CLIAIMONITOR/internal/types"
+)
+
+// TeamsConfigPollInterval is how often teams.yaml's modification time is
+// checked for hot-reload
+const TeamsConfigPollInterval = 5 * time.Second
+
+// MaxConfigHistoryLimit caps the limit accepted by /api/config/history
+const MaxConfigHistoryLimit = 500
+
+// SetTeamsConfigPath sets the teams.yaml file watched for hot-reload, for
+// when it was loaded from somewhere other than configs/teams.yaml
+func (s *Server) SetTeamsConfigPath(path string) {
+	s.configMu.Lock()
+	defer s.configMu.Unlock()
+	s.teamsConfigPath = path
+	s.teamsConfigModTime = fileModTime(path)
+}
+
+// teamsConfig returns the current teams config
+func (s *Server) teamsConfig() *types.TeamsConfig {
+	s.configMu.RLock()
+	defer s.configMu.RUnlock()
+	return s.config
+}
+
+// watchTeamsConfig reloads teams.yaml whenever its modification time changes
+func (s *Server) watchTeamsConfig() {
+	ticker := time.NewTicker(TeamsConfigPollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.stopChan:
+			return
+		case <-ticker.C:
+			s.configMu.RLock()
+			path, loaded := s.teamsConfigPath, s.teamsConfigModTime
+			s.configMu.RUnlock()
+
+			if modTime := fileModTime(path); !modTime.IsZero() && !modTime.Equal(loaded) {
+				if _, err := s.reloadTeamsConfig(); err != nil {
+					s.log("config").Warn("teams config hot-reload failed", "path", path, "error", err)
+				}
+			}
+		}
+	}
+}
+
+// reloadTeamsConfig re-reads teams.yaml, swaps it in and records how it
+// differs from the previous config. An invalid file leaves the current
+// config in place. Reloads with changes are stored in config_changes and
+// published as a config_changed event.
+func (s *Server) reloadTeamsConfig() ([]agents.ConfigChange, error) {
+	s.configMu.Lock()
+	path := s.teamsConfigPath
+	modTime := fileModTime(path)
+	config, err := agents.LoadTeamsConfig(path)
+	if err != nil {
+		// Remember the bad file's mtime so the watcher doesn't retry it every poll
+		s.teamsConfigModTime = modTime
+		s.configMu.Unlock()
+		return nil, err
+	}
+	changes := agents.DiffConfigs(s.config, config)
+	s.config = config
+	s.teamsConfigModTime = modTime
+	s.configMu.Unlock()
+
+	if len(changes) == 0 {
+		return changes, nil
+	}
+	s.log("config").Info("teams config reloaded", "path", path, "changes", len(changes))
+	s.logActivity("Config Reloaded", fmt.Sprintf("teams.yaml reloaded with %d change(s)", len(changes)))
+
+	if s.memDB != nil {
+		now := time.Now()
+		records := make([]*memory.ConfigChange, len(changes))
+		for i, c := range changes {
+			records[i] = &memory.ConfigChange{
+				ChangedAt: now,
+				AgentName: c.AgentName,
+				Field:     c.Field,
+				OldValue:  c.OldValue,
+				NewValue:  c.NewValue,
+			}
+		}
+		if err := s.memDB.RecordConfigChanges(records); err != nil {
+			s.log("config").Warn("failed to record config changes", "error", err)
+		}
+	}
+	if s.eventBus != nil {
+		s.eventBus.Publish(events.NewEvent(events.EventConfigChanged, "config", "all", events.PriorityNormal, map[string]interface{}{
+			"changes": len(changes),
+		}))
+	}
+	return changes, nil
+}
+
+// fileModTime returns a file's modification time, or the zero time if it
+// cannot be read
+func fileModTime(path string) time.Time {
+	info, err := os.Stat(path)
+	if err != nil {
+		return time.Time{}
+	}
+	return info.ModTime()
+}
+
+// handleReloadConfig reloads teams.yaml immediately instead of waiting for
+// the hot-reload poll
+func (s *Server) handleReloadConfig(w http.ResponseWriter, r *http.Request) {
+	changes, err := s.reloadTeamsConfig()
+	if err != nil {
+		s.respondAPIError(w, ErrConfigReloadFailed.WithMessage(fmt.Sprintf("Failed to reload teams config: %v", err)))
+		return
+	}
+	s.respondJSON(w, map[string]interface{}{
+		"success": true,
+		"changes": changes,
+		"count":   len(changes),
+	})
+}
+
+// handleGetConfigHistory returns recorded teams.yaml changes, newest first
+func (s *Server) handleGetConfigHistory(w http.ResponseWriter, r *http.Request) {
+	if s.memDB == nil {
+		s.respondAPIError(w, ErrMemoryDBUnavailable)
+		return
+	}
+
+	limit := memory.DefaultConfigChangesLimit
+	if v := r.URL.Query().Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n <= 0 || n > MaxConfigHistoryLimit {
+			s.respondAPIError(w, ErrInvalidParameter.WithMessage(fmt.Sprintf("limit must be between 1 and %d", MaxConfigHistoryLimit)))
+			return
+		}
+		limit = n
+	}
+
+	changes, err := s.memDB.GetConfigChanges(limit)
+	if err != nil {
+		s.respondAPIError(w, ErrInternal.WithMessage(fmt.Sprintf("Failed to get config history: %v", err)))
+		return
+	}
+	s.respondJSON(w, map[string]interface{}{
+		"changes": changes,
+		"count":   len(changes),
+	})
+}
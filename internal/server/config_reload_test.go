@@ -0,0 +1,97 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/CLIAIMONITOR/internal/events"
+	"github.com/CLIAIMONITOR/internal/types"
+)
+
+func TestReloadTeamsConfig(t *testing.T) {
+	s, _ := newBackupTestServer(t)
+	s.eventBus = events.NewBus(nil)
+	sub := s.eventBus.Subscribe("dashboard", []events.EventType{events.EventConfigChanged})
+	defer s.eventBus.Unsubscribe("dashboard", sub)
+
+	teamsPath := filepath.Join(s.basePath, "configs", "teams.yaml")
+	os.MkdirAll(filepath.Dir(teamsPath), 0755)
+	if err := os.WriteFile(teamsPath, []byte(`agents:
+  - name: SNTGreen
+    model: claude-opus-4-5
+    role: Go Developer
+  - name: Security
+    model: claude-sonnet-4-5
+    role: Security
+`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	s.SetTeamsConfigPath(teamsPath)
+	s.config = &types.TeamsConfig{Agents: []types.AgentConfig{
+		{Name: "SNTGreen", Model: "claude-sonnet-4-5", Role: types.RoleGoDeveloper},
+	}}
+
+	w := httptest.NewRecorder()
+	s.handleReloadConfig(w, httptest.NewRequest("POST", "/api/config/reload", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("reload status = %d, body %s", w.Code, w.Body.String())
+	}
+	var resp struct {
+		Count int `json:"count"`
+	}
+	json.NewDecoder(w.Body).Decode(&resp)
+	if resp.Count != 2 {
+		t.Errorf("reload count = %d, want 2", resp.Count)
+	}
+	if cfg := s.getAgentConfig("SNTGreen"); cfg == nil || cfg.Model != "claude-opus-4-5" {
+		t.Errorf("config not swapped in, got %+v", cfg)
+	}
+
+	select {
+	case event := <-sub:
+		if event.Payload["changes"] != 2 {
+			t.Errorf("config_changed payload = %v, want 2 changes", event.Payload)
+		}
+	case <-time.After(time.Second):
+		t.Error("no config_changed event published")
+	}
+
+	w = httptest.NewRecorder()
+	s.handleGetConfigHistory(w, httptest.NewRequest("GET", "/api/config/history?limit=1", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("history status = %d, body %s", w.Code, w.Body.String())
+	}
+	var history struct {
+		Changes []struct {
+			AgentName string `json:"agent_name"`
+			Field     string `json:"field"`
+			NewValue  string `json:"new_value"`
+		} `json:"changes"`
+	}
+	json.NewDecoder(w.Body).Decode(&history)
+	if len(history.Changes) != 1 || history.Changes[0].AgentName != "Security" || history.Changes[0].Field != "agent" {
+		t.Errorf("history = %+v, want the Security addition", history.Changes)
+	}
+
+	// An invalid file keeps the current config
+	os.WriteFile(teamsPath, []byte("agents: [\n"), 0644)
+	w = httptest.NewRecorder()
+	s.handleReloadConfig(w, httptest.NewRequest("POST", "/api/config/reload", nil))
+	if w.Code != http.StatusUnprocessableEntity {
+		t.Errorf("invalid reload status = %d, want %d", w.Code, http.StatusUnprocessableEntity)
+	}
+	if s.getAgentConfig("Security") == nil {
+		t.Error("invalid reload replaced the config")
+	}
+
+	w = httptest.NewRecorder()
+	s.handleGetConfigHistory(w, httptest.NewRequest("GET", "/api/config/history?limit=0", nil))
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("limit=0 status = %d, want %d", w.Code, http.StatusBadRequest)
+	}
+}
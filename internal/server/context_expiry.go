@@ -0,0 +1,121 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/CLIAIMONITOR/internal/events"
+	"github.com/CLIAIMONITOR/internal/memory"
+)
+
+// ContextExpiryWarnPriority is the lowest context priority that is announced
+// on the event bus before it expires
+const ContextExpiryWarnPriority = 7
+
+// sweepCaptainContext warns Captain about high-priority context entering the
+// expiry window, then deletes entries that have expired. Each entry is
+// announced once per expiry time, so refreshing it re-arms the warning.
+func (s *Server) sweepCaptainContext() int {
+	if s.memDB == nil {
+		return 0
+	}
+
+	expiring, err := s.memDB.GetExpiringContext(s.memDB.GetContextExpiryWarningHours())
+	if err != nil {
+		s.log("context").Warn("failed to list expiring context", "error", err)
+	} else {
+		s.warnExpiringContext(expiring, time.Now())
+	}
+
+	cleaned, err := s.memDB.CleanExpiredContext()
+	if err != nil {
+		s.log("context").Warn("failed to clean expired context", "error", err)
+	}
+	return cleaned
+}
+
+// warnExpiringContext publishes a context_expiring event for each
+// high-priority entry not yet announced for its current expiry time
+func (s *Server) warnExpiringContext(entries []*memory.CaptainContext, now time.Time) {
+	s.contextWarnedMu.Lock()
+	defer s.contextWarnedMu.Unlock()
+
+	current := make(map[string]time.Time, len(entries))
+	for _, entry := range entries {
+		expiresAt, ok := entry.ExpiresAt()
+		if !ok || entry.Priority < ContextExpiryWarnPriority {
+			continue
+		}
+		current[entry.Key] = expiresAt
+		if warned, ok := s.contextWarned[entry.Key]; ok && warned.Equal(expiresAt) {
+			continue
+		}
+
+		expiresIn := int(expiresAt.Sub(now).Seconds())
+		if expiresIn < 0 {
+			expiresIn = 0
+		}
+		if s.eventBus != nil {
+			s.eventBus.Publish(events.NewEvent(events.EventContextExpiring, "memory", "captain", events.PriorityHigh, map[string]interface{}{
+				"key":                entry.Key,
+				"expires_in_seconds": expiresIn,
+			}))
+		}
+		s.log("context").Info("high-priority context expiring", "key", entry.Key, "expires_in_seconds", expiresIn)
+	}
+
+	// Forget entries that were refreshed or deleted
+	s.contextWarned = current
+}
+
+// handleGetExpiringCaptainContext lists context entries expiring within the
+// requested window (?within_hours=N, default the configured warning window)
+func (s *Server) handleGetExpiringCaptainContext(w http.ResponseWriter, r *http.Request) {
+	if s.memDB == nil {
+		s.respondAPIError(w, ErrMemoryDBUnavailable)
+		return
+	}
+
+	withinHours := s.memDB.GetContextExpiryWarningHours()
+	if v := r.URL.Query().Get("within_hours"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 || n > 168 {
+			s.respondAPIError(w, ErrInvalidParameter.WithMessage("within_hours must be between 1 and 168"))
+			return
+		}
+		withinHours = n
+	}
+
+	contexts, err := s.memDB.GetExpiringContext(withinHours)
+	if err != nil {
+		s.respondAPIError(w, ErrInternal.WithMessage(fmt.Sprintf("Failed to get expiring context: %v", err)))
+		return
+	}
+
+	now := time.Now()
+	entries := make([]map[string]interface{}, 0, len(contexts))
+	for _, ctx := range contexts {
+		expiresAt, _ := ctx.ExpiresAt()
+		expiresIn := int(expiresAt.Sub(now).Seconds())
+		if expiresIn < 0 {
+			expiresIn = 0
+		}
+		entries = append(entries, map[string]interface{}{
+			"key":                ctx.Key,
+			"value":              ctx.Value,
+			"priority":           ctx.Priority,
+			"max_age_hours":      ctx.MaxAgeHours,
+			"updated_at":         ctx.UpdatedAt,
+			"expires_at":         expiresAt,
+			"expires_in_seconds": expiresIn,
+		})
+	}
+
+	s.respondJSON(w, map[string]interface{}{
+		"contexts":     entries,
+		"count":        len(entries),
+		"within_hours": withinHours,
+	})
+}
@@ -0,0 +1,149 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/CLIAIMONITOR/internal/memory"
+	"github.com/CLIAIMONITOR/internal/tasks"
+)
+
+// recordAgentCost adds one tool call's estimated spend to the cost ledger,
+// attributed to the repository the agent is working in and its current task
+func (s *Server) recordAgentCost(agentID string, tokens int64, cost float64) {
+	if s.memDB == nil {
+		return
+	}
+
+	entry := &memory.AgentCostEntry{
+		AgentID: agentID,
+		Tokens:  tokens,
+		Cost:    cost,
+		TaskID:  s.currentTaskID(agentID),
+	}
+	if agent := s.store.GetAgent(agentID); agent != nil {
+		entry.RepoID = s.repoIDForPath(agent.ProjectPath)
+	}
+
+	if err := s.memDB.RecordAgentCost(entry); err != nil {
+		s.log("metrics").Warn("failed to record agent cost", "agent_id", agentID, "error", err)
+	}
+}
+
+// repoIDFailureTTL is how long a path whose repo could not be discovered is
+// left unattributed before discovery is tried again
+const repoIDFailureTTL = 5 * time.Minute
+
+// repoIDEntry is a cached repo lookup. Failed lookups have no ID and expire
+// after repoIDFailureTTL.
+type repoIDEntry struct {
+	id       string
+	failedAt time.Time
+}
+
+// repoIDForPath resolves a project path to its repo ID. Discovery shells out
+// to git, so it runs outside the cache lock and successful results are
+// cached for the life of the server.
+func (s *Server) repoIDForPath(projectPath string) string {
+	if projectPath == "" {
+		return ""
+	}
+
+	s.repoIDsMu.Lock()
+	entry, ok := s.repoIDs[projectPath]
+	s.repoIDsMu.Unlock()
+	if ok && (entry.id != "" || time.Since(entry.failedAt) < repoIDFailureTTL) {
+		return entry.id
+	}
+
+	// Concurrent first lookups of a path may both run discovery; they
+	// resolve to the same ID
+	entry = repoIDEntry{}
+	repo, err := s.memDB.DiscoverRepo(projectPath)
+	if err != nil {
+		s.log("metrics").Warn("failed to discover repo for cost attribution", "path", projectPath, "error", err)
+		entry.failedAt = time.Now()
+	} else {
+		entry.id = repo.ID
+	}
+
+	s.repoIDsMu.Lock()
+	if s.repoIDs == nil {
+		s.repoIDs = make(map[string]repoIDEntry)
+	}
+	s.repoIDs[projectPath] = entry
+	s.repoIDsMu.Unlock()
+	return entry.id
+}
+
+// currentTaskID returns the task the agent is working on, if any
+func (s *Server) currentTaskID(agentID string) string {
+	if m := s.store.GetMetrics(agentID); m != nil && m.TaskID != "" {
+		return m.TaskID
+	}
+	if s.taskQueue == nil {
+		return ""
+	}
+	for _, t := range s.taskQueue.GetByAgent(agentID) {
+		if t.Status == tasks.StatusAssigned || t.Status == tasks.StatusInProgress {
+			return t.ID
+		}
+	}
+	return ""
+}
+
+// handleGetCostByRepo returns estimated agent spend per repository over the
+// last ?days=N days (default 30), or for one repository with ?repo_id=
+func (s *Server) handleGetCostByRepo(w http.ResponseWriter, r *http.Request) {
+	if s.memDB == nil {
+		s.respondAPIError(w, ErrMemoryDBUnavailable)
+		return
+	}
+
+	days := 30
+	if raw := r.URL.Query().Get("days"); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil || parsed <= 0 || parsed > 365 {
+			s.respondAPIError(w, ErrInvalidParameter.WithMessage("days must be between 1 and 365"))
+			return
+		}
+		days = parsed
+	}
+	since := time.Now().AddDate(0, 0, -days)
+
+	if repoID := r.URL.Query().Get("repo_id"); repoID != "" {
+		cost, err := s.memDB.GetCostByRepo(repoID, since)
+		if err != nil {
+			s.respondAPIError(w, ErrInternal.WithMessage(fmt.Sprintf("Failed to get repo cost: %v", err)))
+			return
+		}
+		s.respondJSON(w, map[string]interface{}{
+			"days":    days,
+			"repo_id": repoID,
+			"cost":    cost,
+		})
+		return
+	}
+
+	costs, err := s.memDB.GetRepoCosts(since)
+	if err != nil {
+		s.respondAPIError(w, ErrInternal.WithMessage(fmt.Sprintf("Failed to get repo costs: %v", err)))
+		return
+	}
+	if costs == nil {
+		costs = []*memory.RepoCost{}
+	}
+
+	total := 0.0
+	for _, c := range costs {
+		total += c.Cost
+	}
+
+	s.respondJSON(w, map[string]interface{}{
+		"days":       days,
+		"repos":      costs,
+		"total_cost": total,
+	})
+}
@@ -0,0 +1,51 @@
+package server
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/CLIAIMONITOR/internal/memory"
+)
+
+// discoverCountingDB counts DiscoverRepo calls and fails them while err is set
+type discoverCountingDB struct {
+	memory.MemoryDB
+	calls int
+	err   error
+}
+
+func (d *discoverCountingDB) DiscoverRepo(basePath string) (*memory.Repo, error) {
+	d.calls++
+	if d.err != nil {
+		return nil, d.err
+	}
+	return &memory.Repo{ID: "repo-" + basePath}, nil
+}
+
+func TestRepoIDForPathCachesFailures(t *testing.T) {
+	s, memDB := newBackupTestServer(t)
+	db := &discoverCountingDB{MemoryDB: memDB, err: errors.New("not a git repo")}
+	s.memDB = db
+
+	for i := 0; i < 3; i++ {
+		if id := s.repoIDForPath("/work/app"); id != "" {
+			t.Fatalf("Expected no repo ID for a failed lookup, got %q", id)
+		}
+	}
+	if db.calls != 1 {
+		t.Errorf("Expected a failed lookup to be cached, got %d discoveries", db.calls)
+	}
+
+	// Once the failure expires discovery is tried again and its result kept
+	db.err = nil
+	s.repoIDs["/work/app"] = repoIDEntry{failedAt: time.Now().Add(-repoIDFailureTTL - time.Second)}
+	for i := 0; i < 2; i++ {
+		if id := s.repoIDForPath("/work/app"); id != "repo-/work/app" {
+			t.Fatalf("Expected repo-/work/app, got %q", id)
+		}
+	}
+	if db.calls != 2 {
+		t.Errorf("Expected one more discovery after the failure expired, got %d total", db.calls)
+	}
+}
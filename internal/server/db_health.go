@@ -0,0 +1,52 @@
+package server
+
+import (
+	"github.com/CLIAIMONITOR/internal/events"
+	"github.com/CLIAIMONITOR/internal/memory"
+)
+
+// memoryDBDetail reports the memory DB integrity and WAL state for
+// /api/health and raises an alert when the integrity check starts failing
+func (s *Server) memoryDBDetail(health *memory.HealthStatus) map[string]interface{} {
+	s.noteDBIntegrity(health)
+
+	detail := map[string]interface{}{
+		"integrity":      health.Integrity,
+		"wal_size_bytes": health.WALSizeBytes,
+		"wal_frames":     health.WALFrames,
+	}
+	if len(health.IntegrityErrors) > 0 {
+		detail["integrity_errors"] = health.IntegrityErrors
+	}
+	return detail
+}
+
+// noteDBIntegrity publishes a db_integrity_error event and starts a repair
+// attempt when the memory DB integrity check goes from ok to error
+func (s *Server) noteDBIntegrity(health *memory.HealthStatus) {
+	failed := health.Integrity == memory.IntegrityError
+
+	s.dbIntegrityFailedMu.Lock()
+	wasFailed := s.dbIntegrityFailed
+	s.dbIntegrityFailed = failed
+	s.dbIntegrityFailedMu.Unlock()
+
+	if !failed || wasFailed {
+		return
+	}
+
+	s.log("memory").Warn("memory database failed integrity check", "errors", health.IntegrityErrors)
+	if s.eventBus != nil {
+		s.eventBus.Publish(events.NewEvent(events.EventDBIntegrityError, "memory", "all", events.PriorityCritical, map[string]interface{}{
+			"errors": health.IntegrityErrors,
+		}))
+	}
+
+	go func() {
+		if err := s.memDB.RepairDatabase(); err != nil {
+			s.log("memory").Error("memory database repair failed", "error", err)
+			return
+		}
+		s.log("memory").Info("memory database repaired")
+	}()
+}
@@ -0,0 +1,44 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/CLIAIMONITOR/internal/mcp"
+	"github.com/CLIAIMONITOR/internal/types"
+)
+
+// handleGetTrace returns everything recorded under one trace ID: activity
+// log entries from the dashboard state, plus session log entries and Captain
+// cycle metrics from memory.db. MCP tool calls are traced via X-Trace-ID.
+func (s *Server) handleGetTrace(w http.ResponseWriter, r *http.Request) {
+	traceID := r.URL.Query().Get("trace_id")
+	if traceID == "" || len(traceID) > mcp.MaxTraceIDLength {
+		s.respondAPIError(w, ErrInvalidParameter.WithMessage("trace_id is required"))
+		return
+	}
+	if s.memDB == nil {
+		s.respondAPIError(w, ErrMemoryDBUnavailable)
+		return
+	}
+
+	records, err := s.memDB.GetTraceRecords(traceID)
+	if err != nil {
+		s.respondAPIError(w, ErrInternal.WithMessage(fmt.Sprintf("Failed to get trace records: %v", err)))
+		return
+	}
+
+	activity := []*types.ActivityLog{}
+	for _, entry := range s.store.GetState().ActivityLog {
+		if entry.TraceID == traceID {
+			activity = append(activity, entry)
+		}
+	}
+
+	s.respondJSON(w, map[string]interface{}{
+		"trace_id":             traceID,
+		"activity_log":         activity,
+		"session_log":          records.SessionLog,
+		"orchestrator_metrics": records.OrchestratorMetrics,
+	})
+}
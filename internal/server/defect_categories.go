@@ -0,0 +1,105 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/CLIAIMONITOR/internal/memory"
+	"github.com/gorilla/mux"
+)
+
+// respondDefectCategoryError maps memory defect category errors to API errors
+func (s *Server) respondDefectCategoryError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, memory.ErrInvalidDefectCategory):
+		s.respondAPIError(w, ErrInvalidDefectCategory.WithMessage(err.Error()))
+	case errors.Is(err, memory.ErrDefectCategoryNotFound):
+		s.respondAPIError(w, ErrDefectCategoryNotFound.WithMessage(err.Error()))
+	case errors.Is(err, memory.ErrDefectCategoryExists):
+		s.respondAPIError(w, ErrDefectCategoryExists.WithMessage(err.Error()))
+	case errors.Is(err, memory.ErrDefectCategoryInUse):
+		s.respondAPIError(w, ErrDefectCategoryInUse.WithMessage(err.Error()))
+	default:
+		s.respondAPIError(w, ErrInternal.WithMessage(err.Error()))
+	}
+}
+
+// handleCreateDefectCategory handles POST /api/defect-categories, adding a
+// custom category alongside those seeded from defect_categories.yaml
+func (s *Server) handleCreateDefectCategory(w http.ResponseWriter, r *http.Request) {
+	if s.memDB == nil {
+		s.respondAPIError(w, ErrMemoryDBUnavailable)
+		return
+	}
+
+	var category memory.DefectCategory
+	if err := json.NewDecoder(r.Body).Decode(&category); err != nil {
+		s.respondAPIError(w, ErrInvalidRequestBody)
+		return
+	}
+
+	if err := s.memDB.CreateDefectCategory(&category); err != nil {
+		s.respondDefectCategoryError(w, err)
+		return
+	}
+
+	s.logActivity("defect_category_created", fmt.Sprintf("Defect category %s (%s) created", category.Code, category.Name))
+	s.respondJSON(w, category)
+}
+
+// handleUpdateDefectCategory handles PUT /api/defect-categories/{code}. The
+// body holds the columns to change, e.g. {"default_severity": "high"}.
+func (s *Server) handleUpdateDefectCategory(w http.ResponseWriter, r *http.Request) {
+	if s.memDB == nil {
+		s.respondAPIError(w, ErrMemoryDBUnavailable)
+		return
+	}
+
+	code := mux.Vars(r)["code"]
+	var updates map[string]interface{}
+	if err := json.NewDecoder(r.Body).Decode(&updates); err != nil {
+		s.respondAPIError(w, ErrInvalidRequestBody)
+		return
+	}
+
+	if err := s.memDB.UpdateDefectCategory(code, updates); err != nil {
+		s.respondDefectCategoryError(w, err)
+		return
+	}
+
+	categories, err := s.memDB.GetDefectCategories()
+	if err != nil {
+		s.respondAPIError(w, ErrInternal.WithMessage(fmt.Sprintf("Failed to get categories: %v", err)))
+		return
+	}
+	for _, category := range categories {
+		if category.Code == code {
+			s.respondJSON(w, category)
+			return
+		}
+	}
+	s.respondAPIError(w, ErrDefectCategoryNotFound)
+}
+
+// handleDeleteDefectCategory handles DELETE /api/defect-categories/{code}.
+// Categories still referenced by review defects cannot be deleted.
+func (s *Server) handleDeleteDefectCategory(w http.ResponseWriter, r *http.Request) {
+	if s.memDB == nil {
+		s.respondAPIError(w, ErrMemoryDBUnavailable)
+		return
+	}
+
+	code := mux.Vars(r)["code"]
+	if err := s.memDB.DeleteDefectCategory(code); err != nil {
+		s.respondDefectCategoryError(w, err)
+		return
+	}
+
+	s.logActivity("defect_category_deleted", fmt.Sprintf("Defect category %s deleted", code))
+	s.respondJSON(w, map[string]interface{}{
+		"success": true,
+		"code":    code,
+	})
+}
@@ -0,0 +1,80 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/CLIAIMONITOR/internal/memory"
+	"github.com/gorilla/mux"
+)
+
+func TestDefectCategoryEndpoints(t *testing.T) {
+	s, memDB := newBackupTestServer(t)
+
+	w := httptest.NewRecorder()
+	s.handleCreateDefectCategory(w, httptest.NewRequest("POST", "/api/defect-categories",
+		strings.NewReader(`{"Code": "I18N", "Name": "Localization", "CategoryType": "custom"}`)))
+	if w.Code != http.StatusOK {
+		t.Fatalf("create status = %d, body %s", w.Code, w.Body.String())
+	}
+	var created memory.DefectCategory
+	json.NewDecoder(w.Body).Decode(&created)
+	if created.Code != "I18N" || created.DefaultSeverity != "medium" {
+		t.Errorf("created = %+v", created)
+	}
+
+	w = httptest.NewRecorder()
+	s.handleCreateDefectCategory(w, httptest.NewRequest("POST", "/api/defect-categories",
+		strings.NewReader(`{"Code": "I18N", "Name": "Again", "CategoryType": "custom"}`)))
+	if w.Code != http.StatusConflict {
+		t.Errorf("duplicate create status = %d, want 409", w.Code)
+	}
+
+	update := func(code, body string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest("PUT", "/api/defect-categories/"+code, strings.NewReader(body))
+		r = mux.SetURLVars(r, map[string]string{"code": code})
+		w := httptest.NewRecorder()
+		s.handleUpdateDefectCategory(w, r)
+		return w
+	}
+	w = update("I18N", `{"default_severity": "high"}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("update status = %d, body %s", w.Code, w.Body.String())
+	}
+	var updated memory.DefectCategory
+	json.NewDecoder(w.Body).Decode(&updated)
+	if updated.DefaultSeverity != "high" {
+		t.Errorf("updated = %+v", updated)
+	}
+	if w := update("I18N", `{"code": "L10N"}`); w.Code != http.StatusBadRequest {
+		t.Errorf("update of code status = %d, want 400", w.Code)
+	}
+	if w := update("MISSING", `{"name": "x"}`); w.Code != http.StatusNotFound {
+		t.Errorf("update of missing status = %d, want 404", w.Code)
+	}
+
+	assignment := &memory.TaskAssignment{TaskID: "TASK-CAT", AssignedTo: "sgt-green", AssignedBy: "captain", AssignmentType: "review", Status: "in_progress"}
+	memDB.CreateAssignment(assignment)
+	board := &memory.ReviewBoard{AssignmentID: assignment.ID, ReviewerCount: 2, Status: "in_progress", RiskLevel: "medium"}
+	memDB.CreateReviewBoard(board)
+	if err := memDB.CreateDefect(&memory.ReviewDefect{BoardID: board.ID, ReviewerID: "r1", Category: "I18N", Severity: "high", Title: "t", Description: "d", Status: "open"}); err != nil {
+		t.Fatalf("CreateDefect failed: %v", err)
+	}
+
+	remove := func(code string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest("DELETE", "/api/defect-categories/"+code, nil)
+		r = mux.SetURLVars(r, map[string]string{"code": code})
+		w := httptest.NewRecorder()
+		s.handleDeleteDefectCategory(w, r)
+		return w
+	}
+	if w := remove("I18N"); w.Code != http.StatusConflict {
+		t.Errorf("delete of referenced category status = %d, want 409", w.Code)
+	}
+	if w := remove("STYLE"); w.Code != http.StatusOK {
+		t.Errorf("delete status = %d, body %s", w.Code, w.Body.String())
+	}
+}
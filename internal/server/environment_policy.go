@@ -0,0 +1,68 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/CLIAIMONITOR/internal/memory"
+	"github.com/gorilla/mux"
+)
+
+// handleGetEnvironmentPolicy returns a recon environment's security policy,
+// or its type's default when none is stored
+func (s *Server) handleGetEnvironmentPolicy(w http.ResponseWriter, r *http.Request) {
+	reconRepo, ok := s.memDB.(memory.ReconRepository)
+	if !ok {
+		s.respondAPIError(w, ErrServiceUnavailable.WithMessage("Recon storage not available"))
+		return
+	}
+	envID := mux.Vars(r)["id"]
+
+	policy, err := reconRepo.GetEnvironmentPolicy(r.Context(), envID)
+	if s.respondEnvironmentPolicyError(w, envID, err) {
+		return
+	}
+	s.respondJSON(w, policy)
+}
+
+// handleUpdateEnvironmentPolicy replaces a recon environment's security
+// policy; it applies to the next scan and findings saved from then on
+func (s *Server) handleUpdateEnvironmentPolicy(w http.ResponseWriter, r *http.Request) {
+	reconRepo, ok := s.memDB.(memory.ReconRepository)
+	if !ok {
+		s.respondAPIError(w, ErrServiceUnavailable.WithMessage("Recon storage not available"))
+		return
+	}
+	envID := mux.Vars(r)["id"]
+
+	var policy memory.SecurityPolicy
+	if err := json.NewDecoder(r.Body).Decode(&policy); err != nil {
+		s.respondAPIError(w, ErrInvalidRequestBody)
+		return
+	}
+
+	err := reconRepo.SetEnvironmentPolicy(r.Context(), envID, policy)
+	if s.respondEnvironmentPolicyError(w, envID, err) {
+		return
+	}
+	s.logActivity("environment_policy_updated", fmt.Sprintf("Updated security policy for environment %s", envID))
+	s.respondJSON(w, policy)
+}
+
+// respondEnvironmentPolicyError writes the API error for err, reporting
+// whether one was written
+func (s *Server) respondEnvironmentPolicyError(w http.ResponseWriter, envID string, err error) bool {
+	switch {
+	case err == nil:
+		return false
+	case errors.Is(err, memory.ErrEnvironmentNotFound):
+		s.respondAPIError(w, ErrEnvironmentNotFound.WithDetails(map[string]string{"env_id": envID}))
+	case errors.Is(err, memory.ErrInvalidSecurityPolicy):
+		s.respondAPIError(w, ErrInvalidSecurityPolicy.WithMessage(err.Error()))
+	default:
+		s.respondAPIError(w, ErrInternal.WithMessage(fmt.Sprintf("Failed to access security policy: %v", err)))
+	}
+	return true
+}
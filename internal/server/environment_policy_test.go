@@ -0,0 +1,52 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/CLIAIMONITOR/internal/memory"
+	"github.com/gorilla/mux"
+)
+
+func TestEnvironmentPolicyEndpoints(t *testing.T) {
+	s, memDB := newBackupTestServer(t)
+	if err := memDB.RegisterEnvironment(context.Background(), &memory.Environment{ID: "acme", Name: "Acme", EnvType: "customer"}); err != nil {
+		t.Fatalf("RegisterEnvironment failed: %v", err)
+	}
+
+	call := func(handler http.HandlerFunc, method, envID, body string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(method, "/api/recon/environments/"+envID+"/policy", strings.NewReader(body))
+		r = mux.SetURLVars(r, map[string]string{"id": envID})
+		w := httptest.NewRecorder()
+		handler(w, r)
+		return w
+	}
+
+	w := call(s.handleGetEnvironmentPolicy, "GET", "acme", "")
+	var policy memory.SecurityPolicy
+	json.NewDecoder(w.Body).Decode(&policy)
+	if w.Code != http.StatusOK || policy.AllowNetworkScan || policy.AllowFileSystemWrite {
+		t.Errorf("GET default = %d %+v", w.Code, policy)
+	}
+
+	w = call(s.handleUpdateEnvironmentPolicy, "PUT", "acme", `{"allow_network_scan": true, "max_finding_severity": "low"}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("PUT status = %d, body %s", w.Code, w.Body.String())
+	}
+	w = call(s.handleGetEnvironmentPolicy, "GET", "acme", "")
+	json.NewDecoder(w.Body).Decode(&policy)
+	if !policy.AllowNetworkScan || policy.MaxFindingSeverity != "low" {
+		t.Errorf("GET after PUT = %+v", policy)
+	}
+
+	if w := call(s.handleUpdateEnvironmentPolicy, "PUT", "acme", `{"max_finding_severity": "extreme"}`); w.Code != http.StatusBadRequest {
+		t.Errorf("invalid severity status = %d, want 400", w.Code)
+	}
+	if w := call(s.handleGetEnvironmentPolicy, "GET", "missing", ""); w.Code != http.StatusNotFound {
+		t.Errorf("missing environment status = %d, want 404", w.Code)
+	}
+}
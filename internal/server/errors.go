@@ -0,0 +1,177 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+)
+
+// APIError is an error response with a machine-readable code. The
+// registered values below are templates; use WithMessage and WithDetails to
+// add request specifics without modifying them.
+type APIError struct {
+	Code       string      `json:"code"`
+	Message    string      `json:"message"`
+	HTTPStatus int         `json:"http_status"`
+	Details    interface{} `json:"details,omitempty"`
+}
+
+// Error implements the error interface
+func (e *APIError) Error() string {
+	return e.Code + ": " + e.Message
+}
+
+// WithMessage returns a copy of e with a more specific message
+func (e *APIError) WithMessage(message string) *APIError {
+	c := *e
+	c.Message = message
+	return &c
+}
+
+// WithDetails returns a copy of e carrying extra context for the client
+func (e *APIError) WithDetails(details interface{}) *APIError {
+	c := *e
+	c.Details = details
+	return &c
+}
+
+var (
+	apiErrorsMu sync.RWMutex
+	apiErrors   = make(map[string]*APIError)
+)
+
+// registerAPIError adds an error code to the registry listed by /api/errors
+func registerAPIError(code string, httpStatus int, message string) *APIError {
+	e := &APIError{Code: code, Message: message, HTTPStatus: httpStatus}
+
+	apiErrorsMu.Lock()
+	defer apiErrorsMu.Unlock()
+	if _, exists := apiErrors[code]; exists {
+		panic("duplicate API error code " + code)
+	}
+	apiErrors[code] = e
+	return e
+}
+
+// RegisteredAPIErrors returns every registered error code, sorted by code
+func RegisteredAPIErrors() []*APIError {
+	apiErrorsMu.RLock()
+	defer apiErrorsMu.RUnlock()
+
+	list := make([]*APIError, 0, len(apiErrors))
+	for _, e := range apiErrors {
+		list = append(list, e)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Code < list[j].Code })
+	return list
+}
+
+// General errors
+var (
+	ErrInvalidRequestBody  = registerAPIError("INVALID_REQUEST_BODY", http.StatusBadRequest, "Invalid request body")
+	ErrInvalidParameter    = registerAPIError("INVALID_PARAMETER", http.StatusBadRequest, "Invalid query parameter")
+	ErrUnsafeContent       = registerAPIError("UNSAFE_CONTENT", http.StatusBadRequest, "Input contains unsafe content")
+	ErrForbidden           = registerAPIError("FORBIDDEN", http.StatusForbidden, "Forbidden")
+	ErrInternal            = registerAPIError("INTERNAL_ERROR", http.StatusInternalServerError, "Internal server error")
+	ErrServiceUnavailable  = registerAPIError("SERVICE_UNAVAILABLE", http.StatusServiceUnavailable, "Service not available")
+	ErrMemoryDBUnavailable = registerAPIError("MEMORY_DB_UNAVAILABLE", http.StatusServiceUnavailable, "Memory database not available")
+)
+
+// Agent errors
+var (
+	ErrAgentNotFound      = registerAPIError("AGENT_NOT_FOUND", http.StatusNotFound, "Agent not found")
+	ErrInvalidAgentID     = registerAPIError("INVALID_AGENT_ID", http.StatusBadRequest, "Invalid agent ID")
+	ErrInvalidConfigName  = registerAPIError("INVALID_CONFIG_NAME", http.StatusBadRequest, "Invalid config name")
+	ErrConfigReloadFailed = registerAPIError("CONFIG_RELOAD_FAILED", http.StatusUnprocessableEntity, "Failed to reload teams config")
+	ErrUnknownAgentType   = registerAPIError("UNKNOWN_AGENT_TYPE", http.StatusBadRequest, "Unknown agent type")
+	ErrInvalidProjectPath = registerAPIError("INVALID_PROJECT_PATH", http.StatusBadRequest, "Invalid project path")
+	ErrInvalidTask        = registerAPIError("INVALID_TASK", http.StatusBadRequest, "Invalid task description")
+	ErrSpawnFailed        = registerAPIError("SPAWN_FAILED", http.StatusInternalServerError, "Failed to spawn agent")
+)
+
+// Human input, stop approval and escalation errors
+var (
+	ErrInvalidRequestID           = registerAPIError("INVALID_REQUEST_ID", http.StatusBadRequest, "Invalid request ID")
+	ErrInvalidAnswer              = registerAPIError("INVALID_ANSWER", http.StatusBadRequest, "Invalid answer")
+	ErrRequestNotFound            = registerAPIError("REQUEST_NOT_FOUND", http.StatusNotFound, "Request not found")
+	ErrRequestAlreadyAnswered     = registerAPIError("REQUEST_ALREADY_ANSWERED", http.StatusConflict, "Request already answered")
+	ErrStopRequestNotFound        = registerAPIError("STOP_REQUEST_NOT_FOUND", http.StatusNotFound, "Stop request not found")
+	ErrStopRequestAlreadyReviewed = registerAPIError("STOP_REQUEST_ALREADY_REVIEWED", http.StatusConflict, "Stop request already reviewed")
+	ErrInvalidEscalationID        = registerAPIError("INVALID_ESCALATION_ID", http.StatusBadRequest, "Invalid escalation ID")
+	ErrInvalidEscalationResponse  = registerAPIError("INVALID_ESCALATION_RESPONSE", http.StatusBadRequest, "Invalid escalation response")
+)
+
+// Captain errors
+var (
+	ErrInvalidCommandType    = registerAPIError("INVALID_COMMAND_TYPE", http.StatusBadRequest, "Invalid command type (must be spawn_agent, kill_agent, pause, resume, or message)")
+	ErrCaptainNotRestartable = registerAPIError("CAPTAIN_NOT_RESTARTABLE", http.StatusConflict, "Captain cannot be restarted in its current state")
+	ErrInvalidContextKey     = registerAPIError("INVALID_CONTEXT_KEY", http.StatusBadRequest, "Key is required")
+	ErrInvalidThresholds     = registerAPIError("INVALID_THRESHOLDS", http.StatusBadRequest, "Invalid alert thresholds")
+	ErrInvalidOrigin         = registerAPIError("INVALID_ORIGIN", http.StatusBadRequest, "Invalid WebSocket origin")
+)
+
+// Experiment and recon errors
+var (
+	ErrInvalidExperiment     = registerAPIError("INVALID_EXPERIMENT", http.StatusBadRequest, "Invalid experiment")
+	ErrInvalidExperimentID   = registerAPIError("INVALID_EXPERIMENT_ID", http.StatusBadRequest, "Invalid experiment ID")
+	ErrExperimentNotFound    = registerAPIError("EXPERIMENT_NOT_FOUND", http.StatusNotFound, "Experiment not found")
+	ErrScanNotFound          = registerAPIError("SCAN_NOT_FOUND", http.StatusNotFound, "Scan not found")
+	ErrEnvironmentNotFound   = registerAPIError("ENVIRONMENT_NOT_FOUND", http.StatusNotFound, "Recon environment not found")
+	ErrInvalidSecurityPolicy = registerAPIError("INVALID_SECURITY_POLICY", http.StatusBadRequest, "Invalid security policy")
+)
+
+// Review board errors
+var (
+	ErrInvalidReviewBoardID = registerAPIError("INVALID_REVIEW_BOARD_ID", http.StatusBadRequest, "Invalid review board ID")
+	ErrReviewBoardNotFound  = registerAPIError("REVIEW_BOARD_NOT_FOUND", http.StatusNotFound, "Review board not found")
+)
+
+// Defect category errors
+var (
+	ErrInvalidDefectCategory  = registerAPIError("INVALID_DEFECT_CATEGORY", http.StatusBadRequest, "Invalid defect category")
+	ErrDefectCategoryNotFound = registerAPIError("DEFECT_CATEGORY_NOT_FOUND", http.StatusNotFound, "Defect category not found")
+	ErrDefectCategoryExists   = registerAPIError("DEFECT_CATEGORY_EXISTS", http.StatusConflict, "Defect category already exists")
+	ErrDefectCategoryInUse    = registerAPIError("DEFECT_CATEGORY_IN_USE", http.StatusConflict, "Defect category is referenced by review defects")
+)
+
+// MCP errors
+var (
+	ErrMCPToolNotFound = registerAPIError("MCP_TOOL_NOT_FOUND", http.StatusNotFound, "MCP tool not registered")
+)
+
+// Backup errors
+var (
+	ErrInvalidBackup = registerAPIError("INVALID_BACKUP", http.StatusBadRequest, "Invalid backup archive")
+	ErrRestoreFailed = registerAPIError("RESTORE_FAILED", http.StatusInternalServerError, "Failed to restore backup")
+)
+
+// respondAPIError writes apiErr as a JSON error response with its HTTP status
+func (s *Server) respondAPIError(w http.ResponseWriter, apiErr *APIError) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Error-Type", "validation")
+	w.WriteHeader(apiErr.HTTPStatus)
+
+	s.log("http").Warn("request failed", "status", apiErr.HTTPStatus, "error_code", apiErr.Code,
+		"error", apiErr.Message, "request_id", w.Header().Get(RequestIDHeader))
+
+	errorResp := map[string]interface{}{
+		"error":      apiErr.Message,
+		"error_code": apiErr.Code,
+		"timestamp":  time.Now().UTC().Format(time.RFC3339),
+	}
+	if apiErr.Details != nil {
+		errorResp["details"] = apiErr.Details
+	}
+	json.NewEncoder(w).Encode(errorResp)
+}
+
+// handleListAPIErrors lists every error code the API can return
+func (s *Server) handleListAPIErrors(w http.ResponseWriter, r *http.Request) {
+	errs := RegisteredAPIErrors()
+	s.respondJSON(w, map[string]interface{}{
+		"errors": errs,
+		"count":  len(errs),
+	})
+}
@@ -0,0 +1,54 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestAPIErrorWithDetailsCopies(t *testing.T) {
+	e := ErrAgentNotFound.WithMessage("Agent pane not found").WithDetails(map[string]string{"agent_id": "team-sntgreen001"})
+	if e.Code != "AGENT_NOT_FOUND" || e.HTTPStatus != http.StatusNotFound || e.Message != "Agent pane not found" {
+		t.Errorf("unexpected error: %+v", e)
+	}
+	if ErrAgentNotFound.Details != nil || ErrAgentNotFound.Message != "Agent not found" {
+		t.Errorf("registered error was modified: %+v", ErrAgentNotFound)
+	}
+}
+
+func TestRespondAPIError(t *testing.T) {
+	s := &Server{}
+	rec := httptest.NewRecorder()
+	s.respondAPIError(rec, ErrExperimentNotFound.WithDetails(map[string]string{"experiment_id": "exp-1"}))
+
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("status = %d, want %d", rec.Code, http.StatusNotFound)
+	}
+	var body struct {
+		Error     string            `json:"error"`
+		ErrorCode string            `json:"error_code"`
+		Details   map[string]string `json:"details"`
+	}
+	if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if body.ErrorCode != "EXPERIMENT_NOT_FOUND" || body.Error != "Experiment not found" || body.Details["experiment_id"] != "exp-1" {
+		t.Errorf("unexpected body: %+v", body)
+	}
+}
+
+func TestRegisteredAPIErrors(t *testing.T) {
+	errs := RegisteredAPIErrors()
+	if len(errs) == 0 {
+		t.Fatal("no registered errors")
+	}
+	for i, e := range errs {
+		if e.Code == "" || e.Message == "" || e.HTTPStatus < 400 {
+			t.Errorf("incomplete registered error: %+v", e)
+		}
+		if i > 0 && errs[i-1].Code >= e.Code {
+			t.Errorf("errors not sorted by code: %s before %s", errs[i-1].Code, e.Code)
+		}
+	}
+}
@@ -0,0 +1,78 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/CLIAIMONITOR/internal/events"
+)
+
+// defaultEventHistoryWindow is how far back /api/events/history looks when
+// ?from= is not given
+const defaultEventHistoryWindow = 24 * time.Hour
+
+// handleGetEventHistory returns persisted events between the RFC 3339 times
+// ?from= (default 24h ago) and ?to= (default now), oldest first, optionally
+// filtered by ?target= and ?type= (repeatable or comma separated). At most
+// events.EventHistoryLimit events are returned.
+func (s *Server) handleGetEventHistory(w http.ResponseWriter, r *http.Request) {
+	if s.eventStore == nil {
+		s.respondAPIError(w, ErrServiceUnavailable.WithMessage("Event store not available"))
+		return
+	}
+
+	query := r.URL.Query()
+	to := time.Now()
+	from := time.Time{}
+	for name, dst := range map[string]*time.Time{"from": &from, "to": &to} {
+		raw := query.Get(name)
+		if raw == "" {
+			continue
+		}
+		parsed, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			s.respondAPIError(w, ErrInvalidParameter.WithMessage(fmt.Sprintf("%s must be an RFC 3339 timestamp", name)))
+			return
+		}
+		*dst = parsed
+	}
+	if from.IsZero() {
+		from = to.Add(-defaultEventHistoryWindow)
+	}
+	if to.Before(from) {
+		s.respondAPIError(w, ErrInvalidParameter.WithMessage("to must not be before from"))
+		return
+	}
+
+	known := make(map[events.EventType]bool)
+	for _, t := range events.AllEventTypes() {
+		known[t] = true
+	}
+	var eventTypes []events.EventType
+	for _, raw := range query["type"] {
+		for _, name := range strings.Split(raw, ",") {
+			eventType := events.EventType(strings.TrimSpace(name))
+			if eventType == "" {
+				continue
+			}
+			if !known[eventType] {
+				s.respondAPIError(w, ErrInvalidParameter.WithMessage(fmt.Sprintf("unknown event type %q", eventType)))
+				return
+			}
+			eventTypes = append(eventTypes, eventType)
+		}
+	}
+
+	history, err := s.eventStore.GetEventsByTimeRange(r.Context(), from, to, query.Get("target"), eventTypes)
+	if err != nil {
+		s.respondAPIError(w, ErrInternal.WithMessage(fmt.Sprintf("Failed to get event history: %v", err)))
+		return
+	}
+	s.respondJSON(w, map[string]interface{}{
+		"events":    history,
+		"count":     len(history),
+		"truncated": len(history) == events.EventHistoryLimit,
+	})
+}
@@ -2,6 +2,10 @@
 package server
 
 import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 
@@ -95,3 +99,59 @@ func TestEventBus_FilteredSubscription(t *testing.T) {
 		t.Error("did not receive alert event")
 	}
 }
+
+func TestHandleGetEventHistory(t *testing.T) {
+	s, memDB := newBackupTestServer(t)
+
+	// Without an event store the endpoint is unavailable, not a memory DB error
+	rec := httptest.NewRecorder()
+	s.handleGetEventHistory(rec, httptest.NewRequest(http.MethodGet, "/api/events/history", nil))
+	if rec.Code != http.StatusServiceUnavailable || !strings.Contains(rec.Body.String(), ErrServiceUnavailable.Code) {
+		t.Errorf("Expected 503 %s without an event store, got %d: %s", ErrServiceUnavailable.Code, rec.Code, rec.Body.String())
+	}
+
+	store, err := events.NewSQLiteStore(memDB.DB())
+	if err != nil {
+		t.Fatalf("NewSQLiteStore failed: %v", err)
+	}
+	s.eventStore = store
+
+	for _, e := range []*events.Event{
+		events.NewEvent(events.EventMessage, "human", "team-sntgreen001", events.PriorityNormal, nil),
+		events.NewEvent(events.EventAlert, "system", "team-sntgreen001", events.PriorityHigh, nil),
+		events.NewEvent(events.EventMessage, "human", "Captain", events.PriorityNormal, nil),
+	} {
+		if err := store.Save(e); err != nil {
+			t.Fatalf("Save failed: %v", err)
+		}
+	}
+
+	get := func(query string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		s.handleGetEventHistory(rec, httptest.NewRequest(http.MethodGet, "/api/events/history"+query, nil))
+		return rec
+	}
+
+	rec = get("?target=team-sntgreen001&type=message")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var resp struct {
+		Events []*events.Event `json:"events"`
+		Count  int             `json:"count"`
+	}
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if resp.Count != 1 || resp.Events[0].Type != events.EventMessage || resp.Events[0].Target != "team-sntgreen001" {
+		t.Errorf("Unexpected history: %+v", resp)
+	}
+
+	from := time.Now().Add(time.Hour).UTC().Format(time.RFC3339)
+	if rec := get("?from=" + from + "&to=" + time.Now().UTC().Format(time.RFC3339)); rec.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for to before from, got %d", rec.Code)
+	}
+	if rec := get("?type=agent_message"); rec.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for unknown event type, got %d", rec.Code)
+	}
+}
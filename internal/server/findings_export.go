@@ -0,0 +1,55 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/CLIAIMONITOR/internal/memory"
+	"github.com/gorilla/mux"
+)
+
+// unsafeFilenameChars are replaced when an environment ID is used in an
+// export's download filename
+var unsafeFilenameChars = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// handleExportFindings downloads a recon environment's findings as CSV or
+// SARIF (?format=csv|sarif, default csv)
+func (s *Server) handleExportFindings(w http.ResponseWriter, r *http.Request) {
+	if s.memDB == nil {
+		s.respondAPIError(w, ErrMemoryDBUnavailable)
+		return
+	}
+	envID := mux.Vars(r)["id"]
+	format := strings.ToLower(strings.TrimSpace(r.URL.Query().Get("format")))
+	if format == "" {
+		format = memory.ExportFormatCSV
+	}
+
+	data, contentType, err := s.memDB.ExportFindings(r.Context(), envID, format)
+	switch {
+	case errors.Is(err, memory.ErrUnsupportedExportFormat):
+		s.respondAPIError(w, ErrInvalidParameter.WithMessage("format must be csv or sarif"))
+		return
+	case errors.Is(err, memory.ErrEnvironmentNotFound):
+		s.respondAPIError(w, ErrEnvironmentNotFound.WithDetails(map[string]string{"env_id": envID}))
+		return
+	case err != nil:
+		s.respondAPIError(w, ErrInternal.WithMessage(fmt.Sprintf("Failed to export findings: %v", err)))
+		return
+	}
+
+	ext := format
+	if format == memory.ExportFormatSARIF {
+		ext = "sarif.json"
+	}
+	filename := fmt.Sprintf("findings-%s.%s", unsafeFilenameChars.ReplaceAllString(envID, "_"), ext)
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	w.Header().Set("Content-Length", strconv.Itoa(len(data)))
+	w.Write(data)
+	s.requestLog(r, "recon").Info("findings exported", "env_id", envID, "format", format, "bytes", len(data))
+}
@@ -0,0 +1,99 @@
+package server
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/CLIAIMONITOR/internal/memory"
+	"github.com/gorilla/mux"
+)
+
+func TestExportFindingsEndpoint(t *testing.T) {
+	s, memDB := newBackupTestServer(t)
+	ctx := context.Background()
+	memDB.RegisterEnvironment(ctx, &memory.Environment{ID: "env-1", Name: "Prod", EnvType: "production"})
+	memDB.RecordScan(ctx, &memory.ReconScan{ID: "scan-1", EnvID: "env-1", AgentID: "Snake001", ScanType: "initial", StartedAt: time.Now(), Status: "completed"})
+	if err := memDB.SaveFinding(ctx, &memory.ReconFinding{ID: "VULN-001", ScanID: "scan-1", EnvID: "env-1", FindingType: "security",
+		Severity: "high", Title: "Hardcoded secret", Description: "API key in source", Location: "cmd/main.go:12", Status: "open"}); err != nil {
+		t.Fatalf("SaveFinding failed: %v", err)
+	}
+
+	export := func(envID, query string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest("GET", "/api/recon/environments/"+envID+"/findings/export"+query, nil)
+		r = mux.SetURLVars(r, map[string]string{"id": envID})
+		w := httptest.NewRecorder()
+		s.handleExportFindings(w, r)
+		return w
+	}
+
+	w := export("env-1", "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("csv export status = %d, body %s", w.Code, w.Body.String())
+	}
+	if ct := w.Header().Get("Content-Type"); ct != memory.ContentTypeCSV {
+		t.Errorf("csv Content-Type = %q", ct)
+	}
+	if cd := w.Header().Get("Content-Disposition"); cd != `attachment; filename="findings-env-1.csv"` {
+		t.Errorf("csv Content-Disposition = %q", cd)
+	}
+	records, err := csv.NewReader(w.Body).ReadAll()
+	if err != nil || len(records) != 2 || strings.Join(records[0], ",") != strings.Join(memory.FindingsCSVHeader, ",") {
+		t.Errorf("csv export = %v, %v; want header plus one row", records, err)
+	}
+
+	w = export("env-1", "?format=sarif")
+	if w.Code != http.StatusOK {
+		t.Fatalf("sarif export status = %d, body %s", w.Code, w.Body.String())
+	}
+	if ct := w.Header().Get("Content-Type"); ct != memory.ContentTypeSARIF {
+		t.Errorf("sarif Content-Type = %q", ct)
+	}
+	if cd := w.Header().Get("Content-Disposition"); !strings.Contains(cd, "findings-env-1.sarif.json") {
+		t.Errorf("sarif Content-Disposition = %q", cd)
+	}
+	var sarif struct {
+		Version string `json:"version"`
+		Schema  string `json:"$schema"`
+		Runs    []struct {
+			Tool struct {
+				Driver struct {
+					Name string `json:"name"`
+				} `json:"driver"`
+			} `json:"tool"`
+			Results []struct {
+				RuleID  string `json:"ruleId"`
+				Level   string `json:"level"`
+				Message struct {
+					Text string `json:"text"`
+				} `json:"message"`
+			} `json:"results"`
+		} `json:"runs"`
+	}
+	if err := json.NewDecoder(w.Body).Decode(&sarif); err != nil {
+		t.Fatalf("sarif export is not JSON: %v", err)
+	}
+	if sarif.Version != "2.1.0" || sarif.Schema == "" || len(sarif.Runs) != 1 || sarif.Runs[0].Tool.Driver.Name == "" {
+		t.Fatalf("sarif export missing required properties: %+v", sarif)
+	}
+	if results := sarif.Runs[0].Results; len(results) != 1 || results[0].RuleID != "security" || results[0].Level != "error" || results[0].Message.Text == "" {
+		t.Errorf("sarif results = %+v", results)
+	}
+
+	w = export("env-1", "?format=SARIF")
+	if cd := w.Header().Get("Content-Disposition"); w.Code != http.StatusOK || !strings.Contains(cd, "findings-env-1.sarif.json") {
+		t.Errorf("SARIF export status = %d, Content-Disposition = %q", w.Code, cd)
+	}
+
+	if w := export("env-1", "?format=xml"); w.Code != http.StatusBadRequest {
+		t.Errorf("xml export status = %d, want %d", w.Code, http.StatusBadRequest)
+	}
+	if w := export("env-missing", ""); w.Code != http.StatusNotFound {
+		t.Errorf("missing environment status = %d, want %d", w.Code, http.StatusNotFound)
+	}
+}
@@ -3,18 +3,22 @@ package server
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"log"
 	"net"
 	"net/http"
 	"net/url"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"sort"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/CLIAIMONITOR/internal/agents"
+	"github.com/CLIAIMONITOR/internal/captain"
+	"github.com/CLIAIMONITOR/internal/memory"
 	"github.com/CLIAIMONITOR/internal/types"
 	"github.com/gorilla/mux"
 	"github.com/gorilla/websocket"
@@ -30,9 +34,10 @@ const (
 	GracefulStopTimeout = 60 * time.Second
 )
 
-// AllowedOrigins contains the list of allowed WebSocket origins
+// AllowedOrigins contains the list of allowed WebSocket and REST API (CORS) origins
 // Default: localhost only. Can be configured via CLIAIMONITOR_ALLOWED_ORIGINS env var
 // Example: CLIAIMONITOR_ALLOWED_ORIGINS=http://myhost.local:3000,https://dashboard.example.com
+// With a memory DB this only seeds ws_allowed_origins (see ws_origins.go)
 var allowedOrigins = initAllowedOrigins()
 
 func initAllowedOrigins() []string {
@@ -82,7 +87,7 @@ func checkWebSocketOrigin(r *http.Request) bool {
 	}
 
 	// Check against configured allowed origins
-	for _, allowed := range allowedOrigins {
+	for _, allowed := range wsOrigins.get() {
 		if origin == allowed {
 			return true
 		}
@@ -112,12 +117,6 @@ func checkWebSocketOrigin(r *http.Request) bool {
 	return false
 }
 
-// limitRequestSize limits the request body size to prevent DoS via large payloads
-// Returns the limited body reader to use for decoding
-func limitRequestSize(r *http.Request, maxSize int64) {
-	r.Body = http.MaxBytesReader(nil, r.Body, maxSize)
-}
-
 var upgrader = websocket.Upgrader{
 	CheckOrigin: checkWebSocketOrigin,
 }
@@ -135,13 +134,18 @@ func (s *Server) handleWebSocket(w http.ResponseWriter, r *http.Request) {
 		send: make(chan []byte, WebSocketBufferSize),
 	}
 
-	s.hub.Register(client)
+	if !s.hub.Register(client) {
+		// Server is shutting down
+		conn.Close()
+		return
+	}
 
 	// Send current state immediately
 	state := s.store.GetState()
 	data, _ := json.Marshal(types.WSMessage{
-		Type: types.WSTypeStateUpdate,
-		Data: state,
+		Type:            types.WSTypeStateUpdate,
+		Data:            state,
+		ReconnectConfig: wsReconnectConfig(),
 	})
 	client.send <- data
 
@@ -159,7 +163,7 @@ func (s *Server) handleGetState(w http.ResponseWriter, r *http.Request) {
 func (s *Server) handleGetProjects(w http.ResponseWriter, r *http.Request) {
 	projects, err := agents.GetAllProjects(s.projectsConfig)
 	if err != nil {
-		s.respondError(w, http.StatusInternalServerError, "Failed to load projects")
+		s.respondAPIError(w, ErrInternal.WithMessage("Failed to load projects"))
 		return
 	}
 
@@ -170,9 +174,6 @@ func (s *Server) handleGetProjects(w http.ResponseWriter, r *http.Request) {
 
 // handleSpawnAgent spawns a new agent
 func (s *Server) handleSpawnAgent(w http.ResponseWriter, r *http.Request) {
-	// Limit request size to prevent DoS
-	limitRequestSize(r, MaxPayloadSize)
-
 	var req struct {
 		ConfigName  string `json:"config_name"`
 		ProjectPath string `json:"project_path"`
@@ -180,19 +181,19 @@ func (s *Server) handleSpawnAgent(w http.ResponseWriter, r *http.Request) {
 		Headless    *bool  `json:"headless"` // true=hidden workspace (default), false=visible tab
 	}
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		s.respondError(w, http.StatusBadRequest, "Invalid request body")
+		s.respondAPIError(w, ErrInvalidRequestBody)
 		return
 	}
 
 	// Validate ConfigName
 	if req.ConfigName == "" {
-		s.respondError(w, http.StatusBadRequest, "ConfigName is required")
+		s.respondAPIError(w, ErrInvalidConfigName.WithMessage("ConfigName is required"))
 		return
 	}
 
 	// Validate ConfigName length (prevent arbitrarily long inputs)
 	if len(req.ConfigName) > 50 {
-		s.respondError(w, http.StatusBadRequest, "ConfigName too long (max 50 characters)")
+		s.respondAPIError(w, ErrInvalidConfigName.WithMessage("ConfigName too long (max 50 characters)"))
 		return
 	}
 
@@ -201,33 +202,41 @@ func (s *Server) handleSpawnAgent(w http.ResponseWriter, r *http.Request) {
 		cleanPath := filepath.Clean(req.ProjectPath)
 		// Reject path traversal attempts in relative paths
 		if !filepath.IsAbs(cleanPath) && strings.Contains(cleanPath, "..") {
-			s.respondError(w, http.StatusBadRequest, "Invalid project path: path traversal not allowed")
+			s.respondAPIError(w, ErrInvalidProjectPath.WithMessage("Invalid project path: path traversal not allowed"))
 			return
 		}
 		// Verify the path exists and is a directory
 		info, err := os.Stat(cleanPath)
 		if err != nil || !info.IsDir() {
-			s.respondError(w, http.StatusBadRequest, "Invalid project path: directory does not exist")
+			s.respondAPIError(w, ErrInvalidProjectPath.WithMessage("Invalid project path: directory does not exist"))
 			return
 		}
 	}
 
 	// Validate Task length (if provided)
 	if len(req.Task) > 5000 {
-		s.respondError(w, http.StatusBadRequest, "Task description too long (max 5000 characters)")
+		s.respondAPIError(w, ErrInvalidTask.WithMessage("Task description too long (max 5000 characters)"))
 		return
 	}
 
 	// Find agent config
 	agentConfig := s.getAgentConfig(req.ConfigName)
 	if agentConfig == nil {
-		s.respondError(w, http.StatusBadRequest, "Unknown agent type")
+		s.respondAPIError(w, ErrUnknownAgentType.WithDetails(map[string]string{"config_name": req.ConfigName}))
 		return
 	}
 
+	// dry_run=true previews the spawn without starting WezTerm or Claude
+	dryRun := r.URL.Query().Get("dry_run") == "true"
+
 	// Generate team-compatible agent ID using spawner's method
 	// This ensures consistent ID format: team-{type}{seq:03d}
-	agentID := s.spawner.GenerateAgentID(req.ConfigName)
+	var agentID string
+	if dryRun {
+		agentID = s.spawner.PreviewAgentID(req.ConfigName)
+	} else {
+		agentID = s.spawner.GenerateAgentID(req.ConfigName)
+	}
 
 	// Default project path
 	projectPath := req.ProjectPath
@@ -255,10 +264,23 @@ func (s *Server) handleSpawnAgent(w http.ResponseWriter, r *http.Request) {
 		headless = *req.Headless
 	}
 
+	if dryRun {
+		pid, command := s.spawner.PreviewSpawn(*agentConfig, agentID, projectPath, initialPrompt, headless)
+		s.respondJSON(w, map[string]interface{}{
+			"dry_run":      true,
+			"agent_id":     agentID,
+			"pid":          pid,
+			"headless":     headless,
+			"project_path": projectPath,
+			"command":      command,
+		})
+		return
+	}
+
 	// Spawn agent with options
 	pid, err := s.spawner.SpawnAgentWithOptions(*agentConfig, agentID, projectPath, initialPrompt, headless)
 	if err != nil {
-		s.respondError(w, http.StatusInternalServerError, err.Error())
+		s.respondAPIError(w, ErrSpawnFailed.WithMessage(fmt.Sprintf("Failed to spawn agent: %v", err)))
 		return
 	}
 
@@ -278,7 +300,7 @@ func (s *Server) handleSpawnAgent(w http.ResponseWriter, r *http.Request) {
 
 	s.store.AddAgent(agent)
 
-	log.Printf("[SPAWN] Agent %s spawned and working", agentID)
+	s.requestLog(r, "spawn").Info("agent spawned and working", "agent_id", agentID)
 
 	s.broadcastState()
 
@@ -292,7 +314,7 @@ func (s *Server) handleStopAgent(w http.ResponseWriter, r *http.Request) {
 
 	// Validate agent ID
 	if !isValidAgentID(agentID) {
-		s.respondError(w, http.StatusBadRequest, "Invalid agent ID")
+		s.respondAPIError(w, ErrInvalidAgentID)
 		return
 	}
 
@@ -317,10 +339,33 @@ func (s *Server) handleGracefulStopAgent(w http.ResponseWriter, r *http.Request)
 
 	// Validate agent ID
 	if !isValidAgentID(agentID) {
-		s.respondError(w, http.StatusBadRequest, "Invalid agent ID")
+		s.respondAPIError(w, ErrInvalidAgentID)
 		return
 	}
 
+	timeout := s.gracefulStopAgent(agentID)
+
+	s.respondJSON(w, map[string]interface{}{
+		"success": true,
+		"message": fmt.Sprintf("Graceful shutdown requested. Agent will be force-stopped in %d seconds if it doesn't exit.", int(timeout.Seconds())),
+	})
+}
+
+// agentStopTimeout returns how long an agent gets to exit gracefully: the
+// thresholds override for its config name, or GracefulStopTimeout
+func (s *Server) agentStopTimeout(agentID string) time.Duration {
+	agent := s.store.GetAgent(agentID)
+	if agent == nil {
+		return GracefulStopTimeout
+	}
+	return s.store.GetThresholds().StopTimeout(agent.ConfigName, GracefulStopTimeout)
+}
+
+// gracefulStopAgent requests agent shutdown and force-kills it once its stop
+// timeout passes. Returns the timeout used.
+func (s *Server) gracefulStopAgent(agentID string) time.Duration {
+	timeout := s.agentStopTimeout(agentID)
+
 	// Mark agent for shutdown
 	now := time.Now()
 	s.store.RequestAgentShutdown(agentID, now)
@@ -328,7 +373,7 @@ func (s *Server) handleGracefulStopAgent(w http.ResponseWriter, r *http.Request)
 
 	// Start a goroutine to force-kill after timeout
 	go func() {
-		timer := time.NewTimer(GracefulStopTimeout)
+		timer := time.NewTimer(timeout)
 		defer timer.Stop()
 
 		select {
@@ -348,24 +393,17 @@ func (s *Server) handleGracefulStopAgent(w http.ResponseWriter, r *http.Request)
 			}
 		}
 	}()
-
-	s.respondJSON(w, map[string]interface{}{
-		"success": true,
-		"message": fmt.Sprintf("Graceful shutdown requested. Agent will be force-stopped in %d seconds if it doesn't exit.", int(GracefulStopTimeout.Seconds())),
-	})
+	return timeout
 }
 
 // handleAnswerHumanInput answers a human input request
 func (s *Server) handleAnswerHumanInput(w http.ResponseWriter, r *http.Request) {
-	// Limit request size to prevent DoS
-	limitRequestSize(r, MaxPayloadSize)
-
 	vars := mux.Vars(r)
 	requestID := vars["id"]
 
 	// Validate requestID (prevent potential NoSQL/SQL/path injection)
 	if requestID == "" || len(requestID) > 100 {
-		s.respondError(w, http.StatusBadRequest, "Invalid request ID")
+		s.respondAPIError(w, ErrInvalidRequestID)
 		return
 	}
 
@@ -373,23 +411,23 @@ func (s *Server) handleAnswerHumanInput(w http.ResponseWriter, r *http.Request)
 		Answer string `json:"answer"`
 	}
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		s.respondError(w, http.StatusBadRequest, "Invalid request body")
+		s.respondAPIError(w, ErrInvalidRequestBody)
 		return
 	}
 
 	// Validate answer
 	if len(req.Answer) == 0 {
-		s.respondError(w, http.StatusBadRequest, "Answer cannot be empty")
+		s.respondAPIError(w, ErrInvalidAnswer.WithMessage("Answer cannot be empty"))
 		return
 	}
 	if len(req.Answer) > 10000 {
-		s.respondError(w, http.StatusBadRequest, "Answer exceeds maximum length of 10000 characters")
+		s.respondAPIError(w, ErrInvalidAnswer.WithMessage("Answer exceeds maximum length of 10000 characters"))
 		return
 	}
 
 	// Optional: Basic sanitization or content validation
 	if hasUnsafeContent(req.Answer) {
-		s.respondError(w, http.StatusBadRequest, "Answer contains unsafe content")
+		s.respondAPIError(w, ErrUnsafeContent.WithMessage("Answer contains unsafe content"))
 		return
 	}
 
@@ -397,11 +435,11 @@ func (s *Server) handleAnswerHumanInput(w http.ResponseWriter, r *http.Request)
 	state := s.store.GetState()
 	humanReq := state.HumanRequests[requestID]
 	if humanReq == nil {
-		s.respondError(w, http.StatusNotFound, "Request not found")
+		s.respondAPIError(w, ErrRequestNotFound.WithDetails(map[string]string{"request_id": requestID}))
 		return
 	}
 	if humanReq.Answered {
-		s.respondError(w, http.StatusConflict, "Request already answered")
+		s.respondAPIError(w, ErrRequestAlreadyAnswered.WithDetails(map[string]string{"request_id": requestID}))
 		return
 	}
 
@@ -444,18 +482,20 @@ func (s *Server) handleClearAllAlerts(w http.ResponseWriter, r *http.Request) {
 
 // handleUpdateThresholds updates alert thresholds
 func (s *Server) handleUpdateThresholds(w http.ResponseWriter, r *http.Request) {
-	// Limit request size to prevent DoS
-	limitRequestSize(r, MaxPayloadSize)
-
 	var thresholds types.AlertThresholds
 	if err := json.NewDecoder(r.Body).Decode(&thresholds); err != nil {
-		s.respondError(w, http.StatusBadRequest, "Invalid request body")
+		s.respondAPIError(w, ErrInvalidRequestBody)
 		return
 	}
 
+	// Stop timeouts are kept unless the request sends them
+	if thresholds.AgentStopTimeoutSeconds == nil {
+		thresholds.AgentStopTimeoutSeconds = s.store.GetThresholds().AgentStopTimeoutSeconds
+	}
+
 	// Validate threshold values
 	if err := thresholds.Validate(); err != nil {
-		s.respondError(w, http.StatusBadRequest, err.Error())
+		s.respondAPIError(w, ErrInvalidThresholds.WithMessage(err.Error()))
 		return
 	}
 
@@ -479,11 +519,15 @@ func (s *Server) handleResetMetrics(w http.ResponseWriter, r *http.Request) {
 func (s *Server) handleHealthCheck(w http.ResponseWriter, r *http.Request) {
 	state := s.store.GetState()
 
-	// Count connected agents
+	// Count connected and degraded agents
 	connectedAgents := 0
+	degradedAgents := 0
 	for _, agent := range state.Agents {
-		if agent.Status == types.StatusConnected || agent.Status == types.StatusWorking {
+		switch agent.Status {
+		case types.StatusConnected, types.StatusWorking:
 			connectedAgents++
+		case types.StatusDegraded:
+			degradedAgents++
 		}
 	}
 
@@ -499,6 +543,7 @@ func (s *Server) handleHealthCheck(w http.ResponseWriter, r *http.Request) {
 	memoryHealth := map[string]interface{}{
 		"connected": false,
 	}
+	var memoryDetail map[string]interface{}
 	if s.memDB != nil {
 		if health, err := s.memDB.Health(); err == nil {
 			memoryHealth = map[string]interface{}{
@@ -511,6 +556,7 @@ func (s *Server) handleHealthCheck(w http.ResponseWriter, r *http.Request) {
 				"last_context_save": health.LastContextSave,
 				"db_size_bytes":     health.DBSizeBytes,
 			}
+			memoryDetail = s.memoryDBDetail(health)
 		}
 	}
 
@@ -524,13 +570,34 @@ func (s *Server) handleHealthCheck(w http.ResponseWriter, r *http.Request) {
 		"agents": map[string]int{
 			"total":     len(state.Agents),
 			"connected": connectedAgents,
+			"degraded":  degradedAgents,
 		},
 		"alerts": map[string]int{
 			"total":  len(state.Alerts),
 			"active": activeAlerts,
 		},
-		"captain_connected": state.CaptainConnected,
-		"memory_db":         memoryHealth,
+		"captain_connected":      state.CaptainConnected,
+		"memory_db":              memoryHealth,
+		"orphaned_files_cleaned": s.orphanedFilesCleaned,
+		"state_broadcasts": map[string]uint64{
+			"requested": s.stateBroadcaster.Requests(),
+			"sent":      s.stateBroadcaster.Count(),
+		},
+	}
+	if memoryDetail != nil {
+		health["memory_db_detail"] = memoryDetail
+	}
+	if healthDetailEnabled() {
+		if stats := s.runtimeStats.get(time.Now()); stats != nil {
+			health["go_runtime"] = stats
+		}
+	}
+
+	// Histogram of where Captain's recent tasks came from
+	if s.memDB != nil {
+		if sources, err := s.memDB.GetCaptainTaskSourceCounts(time.Now().Add(-captainTaskSourceWindow)); err == nil {
+			health["captain_task_sources_7d"] = sources
+		}
 	}
 
 	s.respondJSON(w, health)
@@ -541,7 +608,7 @@ func (s *Server) handleShutdown(w http.ResponseWriter, r *http.Request) {
 	// Only allow from localhost
 	host, _, _ := net.SplitHostPort(r.RemoteAddr)
 	if host != "127.0.0.1" && host != "::1" && host != "[::1]" {
-		s.respondError(w, http.StatusForbidden, "Shutdown can only be requested from localhost")
+		s.respondAPIError(w, ErrForbidden.WithMessage("Shutdown can only be requested from localhost"))
 		return
 	}
 
@@ -579,7 +646,7 @@ func (s *Server) handleGetBanner(w http.ResponseWriter, r *http.Request) {
 
 func (s *Server) handleClearBanner(w http.ResponseWriter, r *http.Request) {
 	if err := s.notifications.ClearAlert(); err != nil {
-		s.respondError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to clear banner: %v", err))
+		s.respondAPIError(w, ErrInternal.WithMessage(fmt.Sprintf("Failed to clear banner: %v", err)))
 		return
 	}
 
@@ -600,9 +667,6 @@ func (s *Server) handleGetStopRequests(w http.ResponseWriter, r *http.Request) {
 
 // handleRespondStopRequest responds to a stop approval request
 func (s *Server) handleRespondStopRequest(w http.ResponseWriter, r *http.Request) {
-	// Limit request size to prevent DoS
-	limitRequestSize(r, MaxPayloadSize)
-
 	vars := mux.Vars(r)
 	requestID := vars["id"]
 
@@ -611,7 +675,7 @@ func (s *Server) handleRespondStopRequest(w http.ResponseWriter, r *http.Request
 		Response string `json:"response"`
 	}
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		s.respondError(w, http.StatusBadRequest, "Invalid request body")
+		s.respondAPIError(w, ErrInvalidRequestBody)
 		return
 	}
 
@@ -619,11 +683,11 @@ func (s *Server) handleRespondStopRequest(w http.ResponseWriter, r *http.Request
 	state := s.store.GetState()
 	stopReq := state.StopRequests[requestID]
 	if stopReq == nil {
-		s.respondError(w, http.StatusNotFound, "Stop request not found")
+		s.respondAPIError(w, ErrStopRequestNotFound.WithDetails(map[string]string{"request_id": requestID}))
 		return
 	}
 	if stopReq.Reviewed {
-		s.respondError(w, http.StatusConflict, "Stop request already reviewed")
+		s.respondAPIError(w, ErrStopRequestAlreadyReviewed.WithDetails(map[string]string{"request_id": requestID}))
 		return
 	}
 
@@ -668,13 +732,20 @@ func (s *Server) handleGetAgentsFromDB(w http.ResponseWriter, r *http.Request) {
 
 // handleCleanupAgents removes stale disconnected agents and kills their processes
 func (s *Server) handleCleanupAgents(w http.ResponseWriter, r *http.Request) {
+	// Optional: keep agents that disconnected recently (e.g. mid-restart)
+	minAge, err := parseMinAge(r)
+	if err != nil {
+		s.respondAPIError(w, ErrInvalidParameter.WithMessage(err.Error()))
+		return
+	}
+
 	// Get current state to find disconnected agents
 	state := s.store.GetState()
 	removedCount := 0
 
 	for agentID, agent := range state.Agents {
 		// Only clean up disconnected agents
-		if agent.Status == types.StatusDisconnected {
+		if agentMatchesFilter(agent, types.StatusDisconnected, minAge) {
 			// Kill the process if it's still running
 			if err := s.spawner.StopAgent(agentID); err != nil {
 				// Log but continue - process may already be dead
@@ -693,33 +764,150 @@ func (s *Server) handleCleanupAgents(w http.ResponseWriter, r *http.Request) {
 	s.broadcastState()
 
 	s.respondJSON(w, map[string]interface{}{
-		"success": true,
-		"removed": removedCount,
-		"message": fmt.Sprintf("Removed %d stale agent(s)", removedCount),
+		"success":        true,
+		"removed":        removedCount,
+		"affected_count": removedCount,
+		"message":        fmt.Sprintf("Removed %d stale agent(s)", removedCount),
 	})
 }
 
-// Helper functions
-func (s *Server) respondJSON(w http.ResponseWriter, data interface{}) {
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(data)
+// handleListAgents returns agents from the live store.
+// Optional filters: ?status=<status>&min_age_seconds=<n> (age since last seen).
+func (s *Server) handleListAgents(w http.ResponseWriter, r *http.Request) {
+	minAge, err := parseMinAge(r)
+	if err != nil {
+		s.respondAPIError(w, ErrInvalidParameter.WithMessage(err.Error()))
+		return
+	}
+	status := types.AgentStatus(r.URL.Query().Get("status"))
+
+	state := s.store.GetState()
+	agents := make([]*types.Agent, 0, len(state.Agents))
+	for _, agent := range state.Agents {
+		if agentMatchesFilter(agent, status, minAge) {
+			agents = append(agents, agent)
+		}
+	}
+	sort.Slice(agents, func(i, j int) bool {
+		return agents[i].ID < agents[j].ID
+	})
+
+	s.respondJSON(w, map[string]interface{}{
+		"agents": agents,
+		"count":  len(agents),
+	})
 }
 
-func (s *Server) respondError(w http.ResponseWriter, status int, message string) {
-	w.Header().Set("Content-Type", "application/json")
-	w.Header().Set("X-Error-Type", "validation")
-	w.WriteHeader(status)
+// handleGetAgentColors returns the pane colors of each running agent, as
+// allocated by the spawner, and the configured colors of each agent config:
+// colors from teams.yaml, else the name or role default
+func (s *Server) handleGetAgentColors(w http.ResponseWriter, r *http.Request) {
+	palette := make(map[string]map[string]string)
+	for name, cfg := range s.getAgentConfigsMap() {
+		palette[name] = agentColorsJSON(agents.GetAgentColors(cfg))
+	}
 
-	// Log error for server-side tracking (optional)
-	log.Printf("[HTTP_ERROR] Status %d: %s", status, message)
+	panes := make(map[string]map[string]string)
+	if s.spawner != nil {
+		for id, colors := range s.spawner.PaneColors() {
+			panes[id] = agentColorsJSON(colors)
+		}
+	}
 
-	// More detailed error response
-	errorResp := map[string]interface{}{
-		"error":      message,
-		"error_code": fmt.Sprintf("ERR_%d", status),
-		"timestamp":  time.Now().UTC().Format(time.RFC3339),
+	s.respondJSON(w, map[string]interface{}{
+		"colors": palette,
+		"count":  len(palette),
+		"agents": panes,
+	})
+}
+
+// agentColorsJSON is the dashboard view of an agent color scheme
+func agentColorsJSON(colors agents.AgentColors) map[string]string {
+	return map[string]string{
+		"background": colors.BgHex,
+		"foreground": colors.FgHex,
+		"tab_color":  colors.TabHex,
+		"emoji":      colors.Emoji,
 	}
-	json.NewEncoder(w).Encode(errorResp)
+}
+
+// handleGetSpawnHistory returns the most recent agent spawn attempts, newest
+// first, with WezTerm diagnostics for failures
+func (s *Server) handleGetSpawnHistory(w http.ResponseWriter, r *http.Request) {
+	if s.spawner == nil {
+		s.respondAPIError(w, ErrServiceUnavailable.WithMessage("Spawner not available"))
+		return
+	}
+
+	limit := 20
+	if raw := r.URL.Query().Get("limit"); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil || parsed <= 0 || parsed > agents.SpawnHistorySize {
+			s.respondAPIError(w, ErrInvalidParameter.WithMessage(fmt.Sprintf("limit must be between 1 and %d", agents.SpawnHistorySize)))
+			return
+		}
+		limit = parsed
+	}
+
+	history := s.spawner.SpawnHistory(limit)
+	s.respondJSON(w, map[string]interface{}{
+		"attempts": history,
+		"count":    len(history),
+	})
+}
+
+// handleGetAgentHealthScore returns an agent's health score with its
+// heartbeat, task completion and defect breakdown
+func (s *Server) handleGetAgentHealthScore(w http.ResponseWriter, r *http.Request) {
+	agentID := mux.Vars(r)["id"]
+	if !isValidAgentID(agentID) {
+		s.respondAPIError(w, ErrInvalidAgentID)
+		return
+	}
+	if s.memDB == nil {
+		s.respondAPIError(w, ErrMemoryDBUnavailable)
+		return
+	}
+
+	// Agents not in the store have no heartbeat and score from history only
+	var lastSeen time.Time
+	if agent := s.store.GetAgent(agentID); agent != nil {
+		lastSeen = agent.LastSeen
+	}
+	health, err := s.memDB.ComputeAgentHealthScore(agentID, lastSeen)
+	if err != nil {
+		s.respondAPIError(w, ErrInternal.WithMessage(err.Error()))
+		return
+	}
+	s.respondJSON(w, health)
+}
+
+// parseMinAge reads the optional min_age_seconds query parameter
+func parseMinAge(r *http.Request) (time.Duration, error) {
+	raw := r.URL.Query().Get("min_age_seconds")
+	if raw == "" {
+		return 0, nil
+	}
+	secs, err := strconv.Atoi(raw)
+	if err != nil || secs < 0 {
+		return 0, fmt.Errorf("min_age_seconds must be a non-negative integer")
+	}
+	return time.Duration(secs) * time.Second, nil
+}
+
+// agentMatchesFilter reports whether an agent has the given status (empty
+// matches any) and was last seen at least minAge ago
+func agentMatchesFilter(agent *types.Agent, status types.AgentStatus, minAge time.Duration) bool {
+	if status != "" && agent.Status != status {
+		return false
+	}
+	return minAge <= 0 || time.Since(agent.LastSeen) >= minAge
+}
+
+// Helper functions
+func (s *Server) respondJSON(w http.ResponseWriter, data interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(data)
 }
 
 func formatAgentNumber(n int) string {
@@ -755,15 +943,12 @@ func isValidAgentID(id string) bool {
 
 // handleSubmitEscalationResponse handles POST /api/escalation/{id}/respond
 func (s *Server) handleSubmitEscalationResponse(w http.ResponseWriter, r *http.Request) {
-	// Limit request size to prevent DoS
-	limitRequestSize(r, MaxPayloadSize)
-
 	vars := mux.Vars(r)
 	escalationID := vars["id"]
 
 	// Validate escalation ID
 	if escalationID == "" || len(escalationID) > 100 {
-		s.respondError(w, http.StatusBadRequest, "Invalid escalation ID")
+		s.respondAPIError(w, ErrInvalidEscalationID)
 		return
 	}
 
@@ -771,30 +956,30 @@ func (s *Server) handleSubmitEscalationResponse(w http.ResponseWriter, r *http.R
 		Response string `json:"response"`
 	}
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		s.respondError(w, http.StatusBadRequest, "Invalid request body")
+		s.respondAPIError(w, ErrInvalidRequestBody)
 		return
 	}
 
 	// Validate response
 	if req.Response == "" {
-		s.respondError(w, http.StatusBadRequest, "Response cannot be empty")
+		s.respondAPIError(w, ErrInvalidEscalationResponse.WithMessage("Response cannot be empty"))
 		return
 	}
 
 	// Validate response length
 	if len(req.Response) > 5000 {
-		s.respondError(w, http.StatusBadRequest, "Response is too long (max 5000 characters)")
+		s.respondAPIError(w, ErrInvalidEscalationResponse.WithMessage("Response is too long (max 5000 characters)"))
 		return
 	}
 
 	// Optional: Content safety check
 	if hasUnsafeContent(req.Response) {
-		s.respondError(w, http.StatusBadRequest, "Response contains unsafe content")
+		s.respondAPIError(w, ErrUnsafeContent.WithMessage("Response contains unsafe content"))
 		return
 	}
 
 	// Log escalation response
-	log.Printf("[ESCALATION] Response for %s: %s", escalationID, req.Response)
+	s.requestLog(r, "escalation").Info("escalation response received", "escalation_id", escalationID, "response", req.Response)
 
 	s.respondJSON(w, map[string]interface{}{
 		"success": true,
@@ -805,15 +990,12 @@ func (s *Server) handleSubmitEscalationResponse(w http.ResponseWriter, r *http.R
 // handleSendCaptainCommand handles POST /api/captain/command
 // Broadcasts command via MCP event bus
 func (s *Server) handleSendCaptainCommand(w http.ResponseWriter, r *http.Request) {
-	// Limit request size to prevent DoS
-	limitRequestSize(r, MaxPayloadSize)
-
 	var req struct {
 		Type    string                 `json:"type"`
 		Payload map[string]interface{} `json:"payload"`
 	}
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		s.respondError(w, http.StatusBadRequest, "Invalid request body")
+		s.respondAPIError(w, ErrInvalidRequestBody)
 		return
 	}
 
@@ -826,12 +1008,12 @@ func (s *Server) handleSendCaptainCommand(w http.ResponseWriter, r *http.Request
 		"message":     true, // Allow human messages to Captain
 	}
 	if !validTypes[req.Type] {
-		s.respondError(w, http.StatusBadRequest, "Invalid command type (must be spawn_agent, kill_agent, pause, resume, or message)")
+		s.respondAPIError(w, ErrInvalidCommandType)
 		return
 	}
 
 	// Log Captain command
-	log.Printf("[CAPTAIN] Command received: %s, payload: %v", req.Type, req.Payload)
+	s.requestLog(r, "captain").Info("command received", "type", req.Type, "payload", req.Payload)
 
 	// Handle message type - log as activity
 	if req.Type == "message" {
@@ -871,21 +1053,34 @@ func (s *Server) handleCaptainTerminalStatus(w http.ResponseWriter, r *http.Requ
 	s.respondJSON(w, info)
 }
 
+// handleCaptainTerminalHeartbeat records that Captain is alive. The Captain
+// Claude session posts here from its prompt, tool and stop hooks, naming the
+// hook in the event query parameter.
+func (s *Server) handleCaptainTerminalHeartbeat(w http.ResponseWriter, r *http.Request) {
+	if s.captainSupervisor == nil {
+		s.respondAPIError(w, ErrServiceUnavailable.WithMessage("Captain supervisor not configured"))
+		return
+	}
+
+	s.captainSupervisor.RecordHeartbeatEvent(r.URL.Query().Get("event"))
+	s.respondJSON(w, s.captainSupervisor.GetInfo())
+}
+
 // handleCaptainTerminalRestart manually restarts the Captain terminal
 func (s *Server) handleCaptainTerminalRestart(w http.ResponseWriter, r *http.Request) {
 	if s.captainSupervisor == nil {
-		s.respondError(w, http.StatusServiceUnavailable, "Captain supervisor not configured")
+		s.respondAPIError(w, ErrServiceUnavailable.WithMessage("Captain supervisor not configured"))
 		return
 	}
 
 	info := s.captainSupervisor.GetInfo()
 	if !info.CanRestart {
-		s.respondError(w, http.StatusConflict, fmt.Sprintf("Cannot restart Captain (status: %s)", info.Status))
+		s.respondAPIError(w, ErrCaptainNotRestartable.WithMessage(fmt.Sprintf("Cannot restart Captain (status: %s)", info.Status)))
 		return
 	}
 
 	if err := s.captainSupervisor.Restart(); err != nil {
-		s.respondError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to restart Captain: %v", err))
+		s.respondAPIError(w, ErrInternal.WithMessage(fmt.Sprintf("Failed to restart Captain: %v", err)))
 		return
 	}
 
@@ -900,13 +1095,13 @@ func (s *Server) handleCaptainTerminalRestart(w http.ResponseWriter, r *http.Req
 // handleGetCaptainContext returns all Captain context entries
 func (s *Server) handleGetCaptainContext(w http.ResponseWriter, r *http.Request) {
 	if s.memDB == nil {
-		s.respondError(w, http.StatusServiceUnavailable, "Memory database not available")
+		s.respondAPIError(w, ErrMemoryDBUnavailable)
 		return
 	}
 
 	contexts, err := s.memDB.GetAllContext()
 	if err != nil {
-		s.respondError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to get context: %v", err))
+		s.respondAPIError(w, ErrInternal.WithMessage(fmt.Sprintf("Failed to get context: %v", err)))
 		return
 	}
 
@@ -918,11 +1113,8 @@ func (s *Server) handleGetCaptainContext(w http.ResponseWriter, r *http.Request)
 
 // handleSetCaptainContext sets a context entry
 func (s *Server) handleSetCaptainContext(w http.ResponseWriter, r *http.Request) {
-	// Limit request size to prevent DoS
-	limitRequestSize(r, MaxPayloadSize)
-
 	if s.memDB == nil {
-		s.respondError(w, http.StatusServiceUnavailable, "Memory database not available")
+		s.respondAPIError(w, ErrMemoryDBUnavailable)
 		return
 	}
 
@@ -933,12 +1125,12 @@ func (s *Server) handleSetCaptainContext(w http.ResponseWriter, r *http.Request)
 		MaxAgeHours int    `json:"max_age_hours"`
 	}
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		s.respondError(w, http.StatusBadRequest, "Invalid request body")
+		s.respondAPIError(w, ErrInvalidRequestBody)
 		return
 	}
 
 	if req.Key == "" {
-		s.respondError(w, http.StatusBadRequest, "Key is required")
+		s.respondAPIError(w, ErrInvalidContextKey)
 		return
 	}
 
@@ -948,7 +1140,7 @@ func (s *Server) handleSetCaptainContext(w http.ResponseWriter, r *http.Request)
 	}
 
 	if err := s.memDB.SetContext(req.Key, req.Value, req.Priority, req.MaxAgeHours); err != nil {
-		s.respondError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to set context: %v", err))
+		s.respondAPIError(w, ErrInternal.WithMessage(fmt.Sprintf("Failed to set context: %v", err)))
 		return
 	}
 
@@ -961,7 +1153,7 @@ func (s *Server) handleSetCaptainContext(w http.ResponseWriter, r *http.Request)
 // handleDeleteCaptainContext deletes a context entry
 func (s *Server) handleDeleteCaptainContext(w http.ResponseWriter, r *http.Request) {
 	if s.memDB == nil {
-		s.respondError(w, http.StatusServiceUnavailable, "Memory database not available")
+		s.respondAPIError(w, ErrMemoryDBUnavailable)
 		return
 	}
 
@@ -969,7 +1161,7 @@ func (s *Server) handleDeleteCaptainContext(w http.ResponseWriter, r *http.Reque
 	key := vars["key"]
 
 	if err := s.memDB.DeleteContext(key); err != nil {
-		s.respondError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to delete context: %v", err))
+		s.respondAPIError(w, ErrInternal.WithMessage(fmt.Sprintf("Failed to delete context: %v", err)))
 		return
 	}
 
@@ -982,16 +1174,16 @@ func (s *Server) handleDeleteCaptainContext(w http.ResponseWriter, r *http.Reque
 // handleGetCaptainContextSummary returns formatted context for Captain startup
 func (s *Server) handleGetCaptainContextSummary(w http.ResponseWriter, r *http.Request) {
 	if s.memDB == nil {
-		s.respondError(w, http.StatusServiceUnavailable, "Memory database not available")
+		s.respondAPIError(w, ErrMemoryDBUnavailable)
 		return
 	}
 
-	// Clean expired context first
-	cleaned, _ := s.memDB.CleanExpiredContext()
+	// Clean expired context first, warning about high-priority entries
+	cleaned := s.sweepCaptainContext()
 
 	contexts, err := s.memDB.GetAllContext()
 	if err != nil {
-		s.respondError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to get context: %v", err))
+		s.respondAPIError(w, ErrInternal.WithMessage(fmt.Sprintf("Failed to get context: %v", err)))
 		return
 	}
 
@@ -1014,7 +1206,7 @@ func (s *Server) handleGetCaptainContextSummary(w http.ResponseWriter, r *http.R
 // handleGetMetricsByModel returns aggregated metrics per model
 func (s *Server) handleGetMetricsByModel(w http.ResponseWriter, r *http.Request) {
 	if s.memDB == nil {
-		s.respondError(w, http.StatusServiceUnavailable, "Memory database not available")
+		s.respondAPIError(w, ErrMemoryDBUnavailable)
 		return
 	}
 
@@ -1022,7 +1214,7 @@ func (s *Server) handleGetMetricsByModel(w http.ResponseWriter, r *http.Request)
 
 	metrics, err := s.memDB.GetMetricsByModel(modelFilter)
 	if err != nil {
-		s.respondError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to get metrics: %v", err))
+		s.respondAPIError(w, ErrInternal.WithMessage(fmt.Sprintf("Failed to get metrics: %v", err)))
 		return
 	}
 
@@ -1035,13 +1227,13 @@ func (s *Server) handleGetMetricsByModel(w http.ResponseWriter, r *http.Request)
 // handleGetMetricsByAgentType returns aggregated metrics by agent type (captain, sgt, spawned_window, subagent)
 func (s *Server) handleGetMetricsByAgentType(w http.ResponseWriter, r *http.Request) {
 	if s.memDB == nil {
-		s.respondError(w, http.StatusServiceUnavailable, "Memory database not available")
+		s.respondAPIError(w, ErrMemoryDBUnavailable)
 		return
 	}
 
 	metrics, err := s.memDB.GetMetricsByAgentType()
 	if err != nil {
-		s.respondError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to get metrics: %v", err))
+		s.respondAPIError(w, ErrInternal.WithMessage(fmt.Sprintf("Failed to get metrics: %v", err)))
 		return
 	}
 
@@ -1054,13 +1246,13 @@ func (s *Server) handleGetMetricsByAgentType(w http.ResponseWriter, r *http.Requ
 // handleGetMetricsByAgent returns per-agent metrics breakdown
 func (s *Server) handleGetMetricsByAgent(w http.ResponseWriter, r *http.Request) {
 	if s.memDB == nil {
-		s.respondError(w, http.StatusServiceUnavailable, "Memory database not available")
+		s.respondAPIError(w, ErrMemoryDBUnavailable)
 		return
 	}
 
 	metrics, err := s.memDB.GetMetricsByAgent()
 	if err != nil {
-		s.respondError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to get metrics: %v", err))
+		s.respondAPIError(w, ErrInternal.WithMessage(fmt.Sprintf("Failed to get metrics: %v", err)))
 		return
 	}
 
@@ -1070,6 +1262,101 @@ func (s *Server) handleGetMetricsByAgent(w http.ResponseWriter, r *http.Request)
 	})
 }
 
+// captainTaskSourceWindow is how far back the health endpoint's task source histogram looks
+const captainTaskSourceWindow = 7 * 24 * time.Hour
+
+// handleListCaptainTasks returns recorded Captain missions with their provenance
+func (s *Server) handleListCaptainTasks(w http.ResponseWriter, r *http.Request) {
+	if s.memDB == nil {
+		s.respondAPIError(w, ErrMemoryDBUnavailable)
+		return
+	}
+
+	sourceType := r.URL.Query().Get("source_type")
+	if sourceType != "" && !captain.ValidSourceType(sourceType) {
+		s.respondAPIError(w, ErrInvalidParameter.WithMessage("source_type must be api, github, json_file or internal"))
+		return
+	}
+
+	limit := 100
+	if raw := r.URL.Query().Get("limit"); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil || parsed <= 0 || parsed > 1000 {
+			s.respondAPIError(w, ErrInvalidParameter.WithMessage("limit must be between 1 and 1000"))
+			return
+		}
+		limit = parsed
+	}
+
+	captainTasks, err := s.memDB.GetCaptainTasks(sourceType, limit)
+	if err != nil {
+		s.respondAPIError(w, ErrInternal.WithMessage(fmt.Sprintf("Failed to get captain tasks: %v", err)))
+		return
+	}
+	if captainTasks == nil {
+		captainTasks = []*memory.CaptainTaskRecord{}
+	}
+
+	s.respondJSON(w, map[string]interface{}{
+		"tasks": captainTasks,
+		"count": len(captainTasks),
+	})
+}
+
+// handleGetMissionAttempts returns each execution attempt of a Captain
+// mission, in the order they ran
+func (s *Server) handleGetMissionAttempts(w http.ResponseWriter, r *http.Request) {
+	if s.memDB == nil {
+		s.respondAPIError(w, ErrMemoryDBUnavailable)
+		return
+	}
+
+	taskID := mux.Vars(r)["id"]
+	attempts, err := s.memDB.GetMissionAttempts(taskID)
+	if err != nil {
+		s.respondAPIError(w, ErrInternal.WithMessage(fmt.Sprintf("Failed to get mission attempts: %v", err)))
+		return
+	}
+
+	s.respondJSON(w, map[string]interface{}{
+		"task_id":  taskID,
+		"attempts": attempts,
+		"count":    len(attempts),
+	})
+}
+
+// handleGetOrchestratorMetricHistory returns daily Captain cycle aggregates
+func (s *Server) handleGetOrchestratorMetricHistory(w http.ResponseWriter, r *http.Request) {
+	if s.memDB == nil {
+		s.respondAPIError(w, ErrMemoryDBUnavailable)
+		return
+	}
+
+	days := 7
+	if raw := r.URL.Query().Get("days"); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil || parsed <= 0 || parsed > 90 {
+			s.respondAPIError(w, ErrInvalidParameter.WithMessage("days must be between 1 and 90"))
+			return
+		}
+		days = parsed
+	}
+
+	history, err := s.memDB.GetOrchestratorMetricHistory(days)
+	if err != nil {
+		s.respondAPIError(w, ErrInternal.WithMessage(fmt.Sprintf("Failed to get orchestrator metrics: %v", err)))
+		return
+	}
+	if history == nil {
+		history = []*memory.OrchestratorDailyStats{}
+	}
+
+	s.respondJSON(w, map[string]interface{}{
+		"days":    days,
+		"history": history,
+	})
+}
+
 // handleCaptainHealth returns Captain health status
 func (s *Server) handleCaptainHealth(w http.ResponseWriter, r *http.Request) {
 	// Check memory database health
@@ -1096,7 +1383,7 @@ func (s *Server) handleCaptainHealth(w http.ResponseWriter, r *http.Request) {
 // handleGetLeaderboard returns agent quality scores for the leaderboard
 func (s *Server) handleGetLeaderboard(w http.ResponseWriter, r *http.Request) {
 	if s.memDB == nil {
-		s.respondError(w, http.StatusServiceUnavailable, "Memory database not available")
+		s.respondAPIError(w, ErrMemoryDBUnavailable)
 		return
 	}
 
@@ -1113,23 +1400,166 @@ func (s *Server) handleGetLeaderboard(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	scores, err := s.memDB.GetAgentLeaderboard(role, limit)
+	scores, err := s.memDB.GetLeaderboardWithRanks(role, limit)
 	if err != nil {
-		s.respondError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to get leaderboard: %v", err))
+		s.respondAPIError(w, ErrInternal.WithMessage(fmt.Sprintf("Failed to get leaderboard: %v", err)))
 		return
 	}
 
+	// Annotate agents who frequently cause the top defect patterns
+	patternAgents := make(map[string][]string)
+	patterns, err := s.memDB.GetDefectPatterns(r.Context(), leaderboardPatternCount)
+	if err != nil {
+		s.requestLog(r, "leaderboard").Warn("failed to get defect patterns", "error", err)
+	}
+	for _, p := range patterns {
+		for _, agentID := range p.FrequentAuthors {
+			patternAgents[agentID] = append(patternAgents[agentID], p.Category)
+		}
+	}
+
 	s.respondJSON(w, map[string]interface{}{
-		"leaderboard": scores,
-		"count":       len(scores),
-		"role_filter": role,
+		"leaderboard":     scores,
+		"count":           len(scores),
+		"role_filter":     role,
+		"defect_patterns": patternAgents,
+	})
+}
+
+// handleGetQualityScoreHistory returns an agent's quality score snapshots
+// for the trend chart (?days=, default 30)
+func (s *Server) handleGetQualityScoreHistory(w http.ResponseWriter, r *http.Request) {
+	if s.memDB == nil {
+		s.respondAPIError(w, ErrMemoryDBUnavailable)
+		return
+	}
+
+	agentID := mux.Vars(r)["agent_id"]
+	days := 30
+	if raw := r.URL.Query().Get("days"); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil || parsed <= 0 || parsed > 365 {
+			s.respondAPIError(w, ErrInvalidParameter.WithMessage("days must be between 1 and 365"))
+			return
+		}
+		days = parsed
+	}
+
+	history, err := s.memDB.GetQualityScoreHistory(agentID, days)
+	if err != nil {
+		s.respondAPIError(w, ErrInternal.WithMessage(fmt.Sprintf("Failed to get quality score history: %v", err)))
+		return
+	}
+
+	s.respondJSON(w, map[string]interface{}{
+		"agent_id": agentID,
+		"days":     days,
+		"history":  history,
+	})
+}
+
+// leaderboardPatternCount is how many top defect patterns the leaderboard
+// checks when annotating agents
+const leaderboardPatternCount = 5
+
+// handleGetDefectPatterns returns recurring defect categories across review boards
+func (s *Server) handleGetDefectPatterns(w http.ResponseWriter, r *http.Request) {
+	if s.memDB == nil {
+		s.respondAPIError(w, ErrMemoryDBUnavailable)
+		return
+	}
+
+	limit := 20
+	if raw := r.URL.Query().Get("limit"); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil || parsed <= 0 || parsed > 100 {
+			s.respondAPIError(w, ErrInvalidParameter.WithMessage("limit must be between 1 and 100"))
+			return
+		}
+		limit = parsed
+	}
+
+	patterns, err := s.memDB.GetDefectPatterns(r.Context(), limit)
+	if err != nil {
+		s.respondAPIError(w, ErrInternal.WithMessage(fmt.Sprintf("Failed to get defect patterns: %v", err)))
+		return
+	}
+	if patterns == nil {
+		patterns = []*memory.DefectPattern{}
+	}
+
+	s.respondJSON(w, map[string]interface{}{
+		"patterns": patterns,
+		"count":    len(patterns),
+	})
+}
+
+// handleCreateExperiment starts a model A/B experiment
+func (s *Server) handleCreateExperiment(w http.ResponseWriter, r *http.Request) {
+	if s.memDB == nil {
+		s.respondAPIError(w, ErrMemoryDBUnavailable)
+		return
+	}
+
+	var req struct {
+		ControlModel   string `json:"control_model"`
+		TreatmentModel string `json:"treatment_model"`
+		SampleSize     int    `json:"sample_size"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		s.respondAPIError(w, ErrInvalidRequestBody)
+		return
+	}
+
+	exp := &memory.ModelExperiment{
+		ControlModel:   req.ControlModel,
+		TreatmentModel: req.TreatmentModel,
+		SampleSize:     req.SampleSize,
+	}
+	if err := s.memDB.CreateModelExperiment(exp); err != nil {
+		s.respondAPIError(w, ErrInvalidExperiment.WithMessage(fmt.Sprintf("Failed to create experiment: %v", err)))
+		return
+	}
+
+	s.requestLog(r, "experiments").Info("model experiment started",
+		"experiment_id", exp.ExperimentID, "control", exp.ControlModel, "treatment", exp.TreatmentModel)
+	s.respondJSON(w, exp)
+}
+
+// handleGetExperimentResults returns an experiment's scores with Welch's
+// t-test statistics comparing treatment to control
+func (s *Server) handleGetExperimentResults(w http.ResponseWriter, r *http.Request) {
+	if s.memDB == nil {
+		s.respondAPIError(w, ErrMemoryDBUnavailable)
+		return
+	}
+
+	experimentID := mux.Vars(r)["id"]
+	if experimentID == "" || len(experimentID) > 100 {
+		s.respondAPIError(w, ErrInvalidExperimentID)
+		return
+	}
+
+	exp, err := s.memDB.GetModelExperiment(experimentID)
+	if err != nil {
+		s.respondAPIError(w, ErrInternal.WithMessage(fmt.Sprintf("Failed to get experiment: %v", err)))
+		return
+	}
+	if exp == nil {
+		s.respondAPIError(w, ErrExperimentNotFound.WithDetails(map[string]string{"experiment_id": experimentID}))
+		return
+	}
+
+	s.respondJSON(w, map[string]interface{}{
+		"experiment": exp,
+		"stats":      memory.CalculateExperimentStats(exp),
 	})
 }
 
 // handleGetReviewBoards returns active review boards
 func (s *Server) handleGetReviewBoards(w http.ResponseWriter, r *http.Request) {
 	if s.memDB == nil {
-		s.respondError(w, http.StatusServiceUnavailable, "Memory database not available")
+		s.respondAPIError(w, ErrMemoryDBUnavailable)
 		return
 	}
 
@@ -1141,16 +1571,52 @@ func (s *Server) handleGetReviewBoards(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
+// handleGetReviewerSlots returns each reviewer slot on a board and whether it
+// is pending, completed or abandoned
+func (s *Server) handleGetReviewerSlots(w http.ResponseWriter, r *http.Request) {
+	if s.memDB == nil {
+		s.respondAPIError(w, ErrMemoryDBUnavailable)
+		return
+	}
+
+	boardID, err := strconv.ParseInt(mux.Vars(r)["id"], 10, 64)
+	if err != nil || boardID <= 0 {
+		s.respondAPIError(w, ErrInvalidReviewBoardID)
+		return
+	}
+
+	if _, err := s.memDB.GetReviewBoard(boardID); err != nil {
+		if errors.Is(err, memory.ErrReviewBoardNotFound) {
+			s.respondAPIError(w, ErrReviewBoardNotFound)
+			return
+		}
+		s.respondAPIError(w, ErrInternal.WithMessage(fmt.Sprintf("Failed to get review board: %v", err)))
+		return
+	}
+
+	slots, err := s.memDB.GetReviewerSlots(boardID)
+	if err != nil {
+		s.respondAPIError(w, ErrInternal.WithMessage(fmt.Sprintf("Failed to get reviewer slots: %v", err)))
+		return
+	}
+
+	s.respondJSON(w, map[string]interface{}{
+		"board_id": boardID,
+		"slots":    slots,
+		"count":    len(slots),
+	})
+}
+
 // handleGetDefectCategories returns valid defect categories
 func (s *Server) handleGetDefectCategories(w http.ResponseWriter, r *http.Request) {
 	if s.memDB == nil {
-		s.respondError(w, http.StatusServiceUnavailable, "Memory database not available")
+		s.respondAPIError(w, ErrMemoryDBUnavailable)
 		return
 	}
 
 	categories, err := s.memDB.GetDefectCategories()
 	if err != nil {
-		s.respondError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to get categories: %v", err))
+		s.respondAPIError(w, ErrInternal.WithMessage(fmt.Sprintf("Failed to get categories: %v", err)))
 		return
 	}
 
@@ -1,10 +1,20 @@
 package server
 
 import (
+	"encoding/json"
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
+
+	"github.com/CLIAIMONITOR/internal/agents"
+	"github.com/CLIAIMONITOR/internal/mcp"
+	"github.com/CLIAIMONITOR/internal/memory"
+	"github.com/CLIAIMONITOR/internal/types"
+	"github.com/gorilla/mux"
 )
 
 func TestCheckWebSocketOrigin(t *testing.T) {
@@ -123,3 +133,261 @@ func TestInitAllowedOrigins(t *testing.T) {
 		t.Error("initAllowedOrigins() should trim whitespace from origins")
 	}
 }
+
+func TestAgentMatchesFilter(t *testing.T) {
+	recent := &types.Agent{ID: "a1", Status: types.StatusDisconnected, LastSeen: time.Now().Add(-10 * time.Second)}
+	stale := &types.Agent{ID: "a2", Status: types.StatusDisconnected, LastSeen: time.Now().Add(-5 * time.Minute)}
+	working := &types.Agent{ID: "a3", Status: types.StatusWorking, LastSeen: time.Now().Add(-5 * time.Minute)}
+
+	if !agentMatchesFilter(recent, types.StatusDisconnected, 0) {
+		t.Error("zero min age should match any disconnected agent")
+	}
+	if agentMatchesFilter(recent, types.StatusDisconnected, time.Minute) {
+		t.Error("recently seen agent should be preserved by min age")
+	}
+	if !agentMatchesFilter(stale, types.StatusDisconnected, time.Minute) {
+		t.Error("stale disconnected agent should match")
+	}
+	if agentMatchesFilter(working, types.StatusDisconnected, 0) {
+		t.Error("working agent should not match disconnected filter")
+	}
+	if !agentMatchesFilter(working, "", 0) {
+		t.Error("empty status should match any agent")
+	}
+}
+
+func TestParseMinAge(t *testing.T) {
+	tests := []struct {
+		query   string
+		want    time.Duration
+		wantErr bool
+	}{
+		{"", 0, false},
+		{"?min_age_seconds=60", time.Minute, false},
+		{"?min_age_seconds=-1", 0, true},
+		{"?min_age_seconds=abc", 0, true},
+	}
+
+	for _, tt := range tests {
+		r := httptest.NewRequest(http.MethodPost, "/api/agents/cleanup"+tt.query, nil)
+		got, err := parseMinAge(r)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("parseMinAge(%q) error = %v, wantErr %v", tt.query, err, tt.wantErr)
+		}
+		if got != tt.want {
+			t.Errorf("parseMinAge(%q) = %v, want %v", tt.query, got, tt.want)
+		}
+	}
+}
+
+func TestHandleGetAgentHealthScore(t *testing.T) {
+	s, _ := newBackupTestServer(t)
+	s.store.AddAgent(&types.Agent{ID: "team-sntgreen001", Status: types.StatusWorking})
+	s.store.UpdateAgent("team-sntgreen001", func(a *types.Agent) {
+		a.LastSeen = time.Now()
+	})
+
+	get := func(id string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/api/agents/x/health-score", nil)
+		req = mux.SetURLVars(req, map[string]string{"id": id})
+		rec := httptest.NewRecorder()
+		s.handleGetAgentHealthScore(rec, req)
+		return rec
+	}
+
+	rec := get("team-sntgreen001")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var health memory.AgentHealthScore
+	if err := json.NewDecoder(rec.Body).Decode(&health); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if health.AgentID != "team-sntgreen001" || health.HeartbeatScore < 39 || health.Score < 99 {
+		t.Errorf("Expected a near-perfect score for a fresh agent, got %+v", health)
+	}
+
+	// Unknown agents have no heartbeat
+	rec = get("team-sntgreen002")
+	if err := json.NewDecoder(rec.Body).Decode(&health); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if health.HeartbeatScore != 0 || health.Score != 60 {
+		t.Errorf("Expected history-only score for unknown agent, got %+v", health)
+	}
+
+	if rec := get(strings.Repeat("x", 101)); rec.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for invalid agent ID, got %d", rec.Code)
+	}
+}
+
+func TestHandleSpawnAgentDryRun(t *testing.T) {
+	s, _ := newBackupTestServer(t)
+	s.config = &types.TeamsConfig{Agents: []types.AgentConfig{{Name: "SNTGreen", Model: "claude-sonnet-4-5", Role: types.RoleGoDeveloper}}}
+	s.spawner = agents.NewSpawner(s.basePath, "http://localhost:3000/mcp/sse", nil)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/agents/spawn?dry_run=true", strings.NewReader(`{"config_name":"SNTGreen","task":"fix it"}`))
+	rec := httptest.NewRecorder()
+	s.handleSpawnAgent(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	var resp struct {
+		DryRun  bool   `json:"dry_run"`
+		AgentID string `json:"agent_id"`
+		PID     int    `json:"pid"`
+		Command string `json:"command"`
+	}
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if !resp.DryRun || resp.AgentID != "team-sntgreen001" || resp.PID != agents.DryRunPIDBase+1 {
+		t.Errorf("Unexpected dry-run response: %+v", resp)
+	}
+	if !strings.Contains(resp.Command, "--model claude-sonnet-4-5") || !strings.Contains(resp.Command, "TASK: fix it") {
+		t.Errorf("Unexpected command: %s", resp.Command)
+	}
+	if len(s.store.GetState().Agents) != 0 {
+		t.Error("Dry run should not add the agent to the store")
+	}
+	if next := s.spawner.GenerateAgentID("SNTGreen"); next != "team-sntgreen001" {
+		t.Errorf("Dry run should not consume an agent ID, next is %s", next)
+	}
+}
+
+func TestMCPToolEndpoints(t *testing.T) {
+	s, _ := newBackupTestServer(t)
+	s.mcp = mcp.NewServer()
+	s.mcp.RegisterTool(mcp.ToolDefinition{Name: "spawn_agent", Description: "Spawn an agent"})
+	s.mcp.RegisterTool(mcp.ToolDefinition{Name: "ping", Description: "Ping"})
+
+	rec := httptest.NewRecorder()
+	s.handleListMCPTools(rec, httptest.NewRequest(http.MethodGet, "/api/mcp/tools", nil))
+	var list struct {
+		Tools []map[string]interface{} `json:"tools"`
+		Count int                      `json:"count"`
+	}
+	if err := json.NewDecoder(rec.Body).Decode(&list); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if list.Count != 2 || list.Tools[0]["name"] != "ping" || list.Tools[1]["name"] != "spawn_agent" {
+		t.Fatalf("Unexpected tool list: %+v", list)
+	}
+
+	remove := func(name string) *httptest.ResponseRecorder {
+		req := mux.SetURLVars(httptest.NewRequest(http.MethodDelete, "/api/mcp/tools/"+name, nil), map[string]string{"name": name})
+		rec := httptest.NewRecorder()
+		s.handleDeregisterMCPTool(rec, req)
+		return rec
+	}
+	if rec := remove("spawn_agent"); rec.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if rec := remove("spawn_agent"); rec.Code != http.StatusNotFound {
+		t.Errorf("Expected 404 for already removed tool, got %d", rec.Code)
+	}
+	if tools := s.mcp.ListTools(); len(tools) != 1 || tools[0]["name"] != "ping" {
+		t.Errorf("Expected only ping to remain, got %v", tools)
+	}
+}
+
+func TestMCPRegisterAgent(t *testing.T) {
+	s, _ := newBackupTestServer(t)
+	s.hub = NewHub()
+	go s.hub.Run()
+	t.Cleanup(s.hub.Shutdown)
+	s.mcp = mcp.NewServer()
+	s.setupMCPCallbacks()
+
+	body := `{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"register_agent","arguments":{"role":"Reviewer","capabilities":["go","security-review"]}}}`
+	req := httptest.NewRequest(http.MethodPost, "/mcp", strings.NewReader(body))
+	req.Header.Set("X-Agent-ID", "team-sntgreen001")
+	rec := httptest.NewRecorder()
+	s.mcp.ServeHTTP(rec, req)
+
+	var resp types.MCPResponse
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if resp.Error != nil {
+		t.Fatalf("register_agent failed: %+v", resp.Error)
+	}
+
+	agent := s.store.GetAgent("team-sntgreen001")
+	if agent == nil {
+		t.Fatal("Expected the registering agent to be added to the store")
+	}
+	if agent.Role != "Reviewer" || agent.Status != types.StatusConnected || agent.LastSeen.IsZero() {
+		t.Errorf("Unexpected agent after registration: %+v", agent)
+	}
+	if len(agent.Capabilities) != 2 || agent.Capabilities[0] != "go" || agent.Capabilities[1] != "security-review" {
+		t.Errorf("Expected declared capabilities, got %v", agent.Capabilities)
+	}
+}
+
+func TestMCPDocs(t *testing.T) {
+	s, _ := newBackupTestServer(t)
+	s.basePath = t.TempDir()
+	s.mcp = mcp.NewServer()
+	s.mcp.RegisterTool(mcp.ToolDefinition{
+		Name:        "ping",
+		Description: "Ping",
+		Parameters:  map[string]mcp.ParameterDef{"message": {Type: "string", Description: "Echoed back"}},
+	})
+
+	rec := httptest.NewRecorder()
+	s.handleGetMCPDocs(rec, httptest.NewRequest(http.MethodGet, "/api/mcp/docs", nil))
+	if ct := rec.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/markdown") {
+		t.Errorf("Expected text/markdown, got %q", ct)
+	}
+	if !strings.Contains(rec.Body.String(), "## ping") || !strings.Contains(rec.Body.String(), "`message`") {
+		t.Errorf("Unexpected docs: %s", rec.Body.String())
+	}
+
+	if err := s.writeMCPDocs(); err != nil {
+		t.Fatalf("writeMCPDocs failed: %v", err)
+	}
+	written, err := os.ReadFile(filepath.Join(s.basePath, "data", "mcp-tools.md"))
+	if err != nil {
+		t.Fatalf("Docs file not written: %v", err)
+	}
+	if string(written) != rec.Body.String() {
+		t.Error("Docs file should match the /api/mcp/docs response")
+	}
+}
+
+func TestGetSpawnHistory(t *testing.T) {
+	s, _ := newBackupTestServer(t)
+	s.spawner = agents.NewSpawner(t.TempDir(), "", nil)
+	s.spawner.DryRun = true
+	config := types.AgentConfig{Name: "SNTGreen", Model: "claude-sonnet-4-5"}
+	for _, id := range []string{"team-sntgreen001", "team-sntgreen002", "team-sntgreen003"} {
+		if _, err := s.spawner.SpawnAgent(config, id, t.TempDir(), ""); err != nil {
+			t.Fatalf("SpawnAgent failed: %v", err)
+		}
+	}
+
+	get := func(query string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		s.handleGetSpawnHistory(w, httptest.NewRequest(http.MethodGet, "/api/agents/spawn-history"+query, nil))
+		return w
+	}
+
+	w := get("?limit=2")
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, body %s", w.Code, w.Body.String())
+	}
+	var body struct {
+		Attempts []agents.SpawnAttempt `json:"attempts"`
+		Count    int                   `json:"count"`
+	}
+	json.NewDecoder(w.Body).Decode(&body)
+	if body.Count != 2 || body.Attempts[0].AgentID != "team-sntgreen003" || !body.Attempts[0].Success {
+		t.Errorf("Unexpected history: %+v", body)
+	}
+
+	if w := get("?limit=0"); w.Code != http.StatusBadRequest {
+		t.Errorf("limit=0 status = %d, want 400", w.Code)
+	}
+}
@@ -0,0 +1,85 @@
+package server
+
+import (
+	"os"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// runtimeStatsTTL is how long a runtime.ReadMemStats snapshot is reused
+	runtimeStatsTTL = 30 * time.Second
+
+	// runtimeStatsTimeout bounds how long /api/health waits on
+	// runtime.ReadMemStats, which stops the world
+	runtimeStatsTimeout = 100 * time.Millisecond
+)
+
+// GoRuntimeStats is the go_runtime section of /api/health
+type GoRuntimeStats struct {
+	Goroutines     int    `json:"goroutines"`
+	HeapAllocBytes uint64 `json:"heap_alloc_bytes"`
+	HeapSysBytes   uint64 `json:"heap_sys_bytes"`
+	GCPauseNsTotal uint64 `json:"gc_pause_ns_total"`
+	NumGC          uint32 `json:"num_gc"`
+}
+
+// runtimeStatsCache holds the last GoRuntimeStats snapshot. The zero value
+// reads stats with runtime.ReadMemStats.
+type runtimeStatsCache struct {
+	mu     sync.Mutex
+	stats  *GoRuntimeStats
+	readAt time.Time
+
+	// readMemStats replaces runtime.ReadMemStats in tests
+	readMemStats func(*runtime.MemStats)
+}
+
+// get returns the cached snapshot while it is younger than runtimeStatsTTL,
+// otherwise takes a new one. If ReadMemStats does not finish within
+// runtimeStatsTimeout the previous snapshot (possibly nil) is returned.
+func (c *runtimeStatsCache) get(now time.Time) *GoRuntimeStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.stats != nil && now.Sub(c.readAt) < runtimeStatsTTL {
+		return c.stats
+	}
+
+	read := c.readMemStats
+	if read == nil {
+		read = runtime.ReadMemStats
+	}
+
+	result := make(chan *GoRuntimeStats, 1)
+	go func() {
+		var m runtime.MemStats
+		read(&m)
+		result <- &GoRuntimeStats{
+			Goroutines:     runtime.NumGoroutine(),
+			HeapAllocBytes: m.HeapAlloc,
+			HeapSysBytes:   m.HeapSys,
+			GCPauseNsTotal: m.PauseTotalNs,
+			NumGC:          m.NumGC,
+		}
+	}()
+
+	select {
+	case stats := <-result:
+		c.stats = stats
+		c.readAt = now
+	case <-time.After(runtimeStatsTimeout):
+	}
+	return c.stats
+}
+
+// healthDetailEnabled reports whether /api/health includes go_runtime.
+// CLIAIMONITOR_HEALTH_DETAIL=false omits it for lightweight load balancer
+// probes; anything else, or unset, keeps it.
+func healthDetailEnabled() bool {
+	enabled, err := strconv.ParseBool(strings.TrimSpace(os.Getenv("CLIAIMONITOR_HEALTH_DETAIL")))
+	return err != nil || enabled
+}
@@ -0,0 +1,81 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"runtime"
+	"testing"
+	"time"
+)
+
+func TestRuntimeStatsCache(t *testing.T) {
+	reads := 0
+	cache := &runtimeStatsCache{readMemStats: func(m *runtime.MemStats) {
+		reads++
+		m.HeapAlloc = uint64(reads)
+		m.NumGC = 7
+	}}
+
+	now := time.Now()
+	stats := cache.get(now)
+	if stats == nil || stats.HeapAllocBytes != 1 || stats.NumGC != 7 || stats.Goroutines == 0 {
+		t.Fatalf("Unexpected stats: %+v", stats)
+	}
+	if stats := cache.get(now.Add(runtimeStatsTTL - time.Second)); stats.HeapAllocBytes != 1 || reads != 1 {
+		t.Errorf("Expected cached stats within TTL, got %+v after %d reads", stats, reads)
+	}
+	if stats := cache.get(now.Add(runtimeStatsTTL)); stats.HeapAllocBytes != 2 {
+		t.Errorf("Expected fresh stats after TTL, got %+v", stats)
+	}
+}
+
+func TestRuntimeStatsCacheTimeout(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+	cache := &runtimeStatsCache{readMemStats: func(m *runtime.MemStats) { <-release }}
+
+	start := time.Now()
+	if stats := cache.get(start); stats != nil {
+		t.Errorf("Expected no stats when ReadMemStats times out, got %+v", stats)
+	}
+	if elapsed := time.Since(start); elapsed > 10*runtimeStatsTimeout {
+		t.Errorf("get blocked for %v", elapsed)
+	}
+
+	stale := &GoRuntimeStats{NumGC: 3}
+	cache.stats, cache.readAt = stale, start.Add(-time.Hour)
+	if stats := cache.get(start); stats != stale {
+		t.Errorf("Expected stale stats on timeout, got %+v", stats)
+	}
+}
+
+func TestHealthCheckRuntimeDetail(t *testing.T) {
+	s, _ := newBackupTestServer(t)
+	s.stateBroadcaster = NewThrottledBroadcaster(broadcastDebounce, func() {})
+
+	health := func() map[string]interface{} {
+		w := httptest.NewRecorder()
+		s.handleHealthCheck(w, httptest.NewRequest("GET", "/api/health", nil))
+		var body map[string]interface{}
+		if err := json.NewDecoder(w.Body).Decode(&body); err != nil {
+			t.Fatalf("Invalid health response: %v", err)
+		}
+		return body
+	}
+
+	t.Setenv("CLIAIMONITOR_HEALTH_DETAIL", "")
+	runtimeStats, ok := health()["go_runtime"].(map[string]interface{})
+	if !ok {
+		t.Fatal("Expected go_runtime in health response")
+	}
+	for _, key := range []string{"goroutines", "heap_alloc_bytes", "heap_sys_bytes", "gc_pause_ns_total", "num_gc"} {
+		if _, ok := runtimeStats[key]; !ok {
+			t.Errorf("go_runtime missing %s", key)
+		}
+	}
+
+	t.Setenv("CLIAIMONITOR_HEALTH_DETAIL", "false")
+	if _, ok := health()["go_runtime"]; ok {
+		t.Error("Expected go_runtime to be omitted when CLIAIMONITOR_HEALTH_DETAIL=false")
+	}
+}
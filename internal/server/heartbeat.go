@@ -0,0 +1,114 @@
+package server
+
+import (
+	"fmt"
+	"log/slog"
+	"sort"
+	"time"
+
+	"github.com/CLIAIMONITOR/internal/metrics"
+	"github.com/CLIAIMONITOR/internal/persistence"
+	"github.com/CLIAIMONITOR/internal/types"
+	"github.com/google/uuid"
+)
+
+// HeartbeatCheckInterval is how often the heartbeat checker scans agents
+const HeartbeatCheckInterval = 60 * time.Second
+
+// AlertTypeHeartbeatMissed is raised when an agent is marked degraded
+const AlertTypeHeartbeatMissed = "heartbeat_missed"
+
+// HeartbeatChecker marks agents degraded when they stop making MCP calls.
+// checkAgentHealth only sees whether the OS process is alive; this catches
+// agents that are running but stuck.
+type HeartbeatChecker struct {
+	store    *persistence.JSONStore
+	alerts   *metrics.AlertChecker
+	hub      *Hub
+	logger   *slog.Logger
+	interval time.Duration
+}
+
+// NewHeartbeatChecker creates a heartbeat checker using the alert engine's
+// heartbeat_timeout_seconds threshold
+func NewHeartbeatChecker(store *persistence.JSONStore, alerts *metrics.AlertChecker, hub *Hub, logger *slog.Logger) *HeartbeatChecker {
+	return &HeartbeatChecker{
+		store:    store,
+		alerts:   alerts,
+		hub:      hub,
+		logger:   logger,
+		interval: HeartbeatCheckInterval,
+	}
+}
+
+// Run checks heartbeats every interval until stop is closed
+func (h *HeartbeatChecker) Run(stop <-chan struct{}) {
+	ticker := time.NewTicker(h.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			h.Check()
+		}
+	}
+}
+
+// Check marks stale agents degraded, raises a heartbeat_missed alert for
+// each, and returns how many agents were newly degraded
+func (h *HeartbeatChecker) Check() int {
+	timeout := types.DefaultThresholds().HeartbeatTimeout()
+	if h.alerts != nil {
+		timeout = h.alerts.GetThresholds().HeartbeatTimeout()
+	}
+
+	now := time.Now()
+	stale := staleAgents(h.store.GetState().Agents, timeout, now)
+	for _, agent := range stale {
+		since := int(now.Sub(agent.LastSeen).Seconds())
+		h.store.UpdateAgent(agent.ID, func(a *types.Agent) {
+			a.Status = types.StatusDegraded
+		})
+
+		alert := &types.Alert{
+			ID:        uuid.New().String(),
+			Type:      AlertTypeHeartbeatMissed,
+			AgentID:   agent.ID,
+			Message:   fmt.Sprintf("Agent %s missed heartbeat: last seen %d seconds ago", agent.ID, since),
+			Severity:  "warning",
+			CreatedAt: now,
+		}
+		h.store.AddAlert(alert)
+		h.hub.BroadcastAlert(alert)
+		h.logger.Warn("agent heartbeat missed, marked degraded", "agent_id", agent.ID, "seconds_since_last_seen", since)
+	}
+
+	if len(stale) > 0 {
+		h.hub.BroadcastState(h.store.GetState())
+	}
+	return len(stale)
+}
+
+// staleAgents returns active agents not seen within timeout, sorted by ID.
+// Agents already degraded, stopping, or disconnected are skipped so each
+// missed heartbeat alerts once.
+func staleAgents(agents map[string]*types.Agent, timeout time.Duration, now time.Time) []*types.Agent {
+	var stale []*types.Agent
+	for _, agent := range agents {
+		switch agent.Status {
+		case types.StatusConnected, types.StatusWorking, types.StatusIdle, types.StatusBlocked:
+		default:
+			continue
+		}
+		if agent.LastSeen.IsZero() || now.Sub(agent.LastSeen) <= timeout {
+			continue
+		}
+		stale = append(stale, agent)
+	}
+	sort.Slice(stale, func(i, j int) bool {
+		return stale[i].ID < stale[j].ID
+	})
+	return stale
+}
@@ -0,0 +1,30 @@
+package server
+
+import (
+	"testing"
+	"time"
+
+	"github.com/CLIAIMONITOR/internal/types"
+)
+
+func TestStaleAgents(t *testing.T) {
+	now := time.Now()
+	timeout := 5 * time.Minute
+
+	agents := map[string]*types.Agent{
+		"fresh":        {ID: "fresh", Status: types.StatusWorking, LastSeen: now.Add(-time.Minute)},
+		"stale-b":      {ID: "stale-b", Status: types.StatusConnected, LastSeen: now.Add(-10 * time.Minute)},
+		"stale-a":      {ID: "stale-a", Status: types.StatusIdle, LastSeen: now.Add(-6 * time.Minute)},
+		"degraded":     {ID: "degraded", Status: types.StatusDegraded, LastSeen: now.Add(-time.Hour)},
+		"disconnected": {ID: "disconnected", Status: types.StatusDisconnected, LastSeen: now.Add(-time.Hour)},
+		"never-seen":   {ID: "never-seen", Status: types.StatusWorking},
+	}
+
+	stale := staleAgents(agents, timeout, now)
+	if len(stale) != 2 {
+		t.Fatalf("expected 2 stale agents, got %d", len(stale))
+	}
+	if stale[0].ID != "stale-a" || stale[1].ID != "stale-b" {
+		t.Errorf("expected [stale-a stale-b], got [%s %s]", stale[0].ID, stale[1].ID)
+	}
+}
@@ -3,7 +3,11 @@ package server
 import (
 	"context"
 	"encoding/json"
+	"os"
+	"strconv"
+	"strings"
 	"sync"
+	"time"
 
 	"github.com/CLIAIMONITOR/internal/types"
 	"github.com/gorilla/websocket"
@@ -14,8 +18,48 @@ const (
 	// WebSocketBufferSize is the buffer size for WebSocket send/broadcast channels
 	// Allows pending messages to queue up before blocking, useful for burst traffic
 	WebSocketBufferSize = 256
+
+	// WebSocketPingInterval is how often clients receive a ping so they can
+	// detect dead connections without waiting for TCP timeouts
+	WebSocketPingInterval = 15 * time.Second
+
+	// WebSocketDrainTimeout bounds how long shutdown waits for queued
+	// messages to reach clients before closing their connections
+	WebSocketDrainTimeout = 2 * time.Second
+
+	// wsDrainPollInterval is how often DrainAndShutdown checks the queues
+	wsDrainPollInterval = 10 * time.Millisecond
 )
 
+// pingMessage is sent to every client each WebSocketPingInterval
+var pingMessage = []byte(`{"type":"` + types.WSTypePing + `"}`)
+
+// wsReconnectConfig returns the client reconnect backoff settings.
+// Defaults can be overridden via CLIAIMONITOR_WS_RECONNECT_INITIAL_DELAY_MS,
+// CLIAIMONITOR_WS_RECONNECT_MAX_DELAY_MS and CLIAIMONITOR_WS_RECONNECT_JITTER.
+func wsReconnectConfig() *types.WSReconnectConfig {
+	cfg := &types.WSReconnectConfig{
+		InitialDelayMs: 500,
+		MaxDelayMs:     30000,
+		Jitter:         true,
+	}
+
+	if v, err := strconv.Atoi(os.Getenv("CLIAIMONITOR_WS_RECONNECT_INITIAL_DELAY_MS")); err == nil && v > 0 {
+		cfg.InitialDelayMs = v
+	}
+	if v, err := strconv.Atoi(os.Getenv("CLIAIMONITOR_WS_RECONNECT_MAX_DELAY_MS")); err == nil && v > 0 {
+		cfg.MaxDelayMs = v
+	}
+	if cfg.MaxDelayMs < cfg.InitialDelayMs {
+		cfg.MaxDelayMs = cfg.InitialDelayMs
+	}
+	if v, err := strconv.ParseBool(strings.TrimSpace(os.Getenv("CLIAIMONITOR_WS_RECONNECT_JITTER"))); err == nil {
+		cfg.Jitter = v
+	}
+
+	return cfg
+}
+
 // Client represents a WebSocket client (browser)
 type Client struct {
 	hub  *Hub
@@ -31,6 +75,7 @@ type Hub struct {
 	unregister chan *Client
 	broadcast  chan []byte
 	shutdown   chan struct{} // Shutdown signal channel
+	draining   bool          // Set once DrainAndShutdown starts
 	ctx        context.Context
 	cancel     context.CancelFunc
 }
@@ -65,7 +110,9 @@ func (h *Hub) Run() {
 
 		case client := <-h.register:
 			h.mu.Lock()
-			h.clients[client] = true
+			if !h.draining {
+				h.clients[client] = true
+			}
 			h.mu.Unlock()
 
 		case client := <-h.unregister:
@@ -96,35 +143,50 @@ func (h *Hub) cleanup() {
 	h.mu.Lock()
 	defer h.mu.Unlock()
 
-	// Close all client channels
+	// Close all client channels. The hub channels stay open: senders select
+	// on ctx instead, so a late Unregister or broadcast cannot panic.
 	for client := range h.clients {
 		close(client.send)
 		delete(h.clients, client)
 	}
-
-	// Close hub channels to prevent goroutine leaks
-	close(h.register)
-	close(h.unregister)
-	close(h.broadcast)
 }
 
-// Register adds a client
-func (h *Hub) Register(client *Client) {
-	h.register <- client
+// Register adds a client. Returns false once the hub is draining or shut
+// down, in which case the caller should close the connection.
+func (h *Hub) Register(client *Client) bool {
+	if h.isDraining() {
+		return false
+	}
+	select {
+	case h.register <- client:
+		return true
+	case <-h.ctx.Done():
+		return false
+	}
 }
 
 // Unregister removes a client
 func (h *Hub) Unregister(client *Client) {
-	h.unregister <- client
+	select {
+	case h.unregister <- client:
+	case <-h.ctx.Done():
+	}
 }
 
-// BroadcastJSON sends a JSON message to all clients
+// BroadcastJSON sends a JSON message to all clients. Messages are dropped
+// once the hub starts draining so the drain can finish.
 func (h *Hub) BroadcastJSON(msg interface{}) {
+	if h.isDraining() {
+		return
+	}
 	data, err := json.Marshal(msg)
 	if err != nil {
 		return
 	}
-	h.broadcast <- data
+	select {
+	case h.broadcast <- data:
+	case <-h.ctx.Done():
+	}
 }
 
 // BroadcastState sends full state to all clients
@@ -182,6 +244,49 @@ func (h *Hub) ClientCount() int {
 	return len(h.clients)
 }
 
+// isDraining reports whether DrainAndShutdown has started
+func (h *Hub) isDraining() bool {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return h.draining
+}
+
+// pendingMessages counts messages not yet handed to a connection: those in
+// each client's send queue plus queued broadcasts, once per client
+func (h *Hub) pendingMessages() int {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	pending := len(h.broadcast) * len(h.clients)
+	for client := range h.clients {
+		pending += len(client.send)
+	}
+	return pending
+}
+
+// DrainAndShutdown stops accepting new clients and broadcasts, waits up to
+// timeout for queued messages to be written out, then shuts the hub down.
+// Returns the number of messages drained.
+func (h *Hub) DrainAndShutdown(timeout time.Duration) int {
+	h.mu.Lock()
+	h.draining = true
+	h.mu.Unlock()
+
+	queued := h.pendingMessages()
+	remaining := queued
+	deadline := time.Now().Add(timeout)
+	for remaining > 0 && time.Now().Before(deadline) {
+		time.Sleep(wsDrainPollInterval)
+		remaining = h.pendingMessages()
+	}
+
+	h.Shutdown()
+
+	if drained := queued - remaining; drained > 0 {
+		return drained
+	}
+	return 0
+}
+
 // Shutdown gracefully shuts down the hub and closes all channels
 func (h *Hub) Shutdown() {
 	h.cancel() // Cancel context to signal all goroutines
@@ -217,10 +322,19 @@ func (c *Client) readPump() {
 
 // writePump writes messages to the WebSocket
 func (c *Client) writePump() {
-	defer c.conn.Close()
+	ticker := time.NewTicker(WebSocketPingInterval)
+	defer func() {
+		ticker.Stop()
+		c.conn.Close()
+	}()
 
 	for {
 		select {
+		case <-ticker.C:
+			if err := c.conn.WriteMessage(websocket.TextMessage, pingMessage); err != nil {
+				return
+			}
+
 		case <-c.hub.ctx.Done():
 			// Hub is shutting down, close connection gracefully
 			c.conn.WriteMessage(websocket.CloseMessage, []byte{})
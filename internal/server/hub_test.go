@@ -277,6 +277,70 @@ func TestHubBroadcastToEmptyHub(t *testing.T) {
 	}
 }
 
+func TestHubDrainAndShutdown(t *testing.T) {
+	hub := NewHub()
+	go hub.Run()
+
+	client := &Client{
+		hub:  hub,
+		conn: nil,
+		send: make(chan []byte, WebSocketBufferSize),
+	}
+	hub.Register(client)
+	time.Sleep(10 * time.Millisecond)
+
+	for i := 0; i < 3; i++ {
+		hub.BroadcastJSON(map[string]int{"n": i})
+	}
+	time.Sleep(10 * time.Millisecond)
+
+	// Stand-in for writePump: consume the queue after the drain has started
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		for i := 0; i < 3; i++ {
+			<-client.send
+		}
+	}()
+
+	if drained := hub.DrainAndShutdown(time.Second); drained != 3 {
+		t.Errorf("expected 3 drained messages, got %d", drained)
+	}
+
+	late := &Client{hub: hub, send: make(chan []byte, 1)}
+	if hub.Register(late) {
+		t.Error("expected registration to be refused after shutdown")
+	}
+
+	// Neither should block or panic once the hub is gone
+	hub.BroadcastJSON(map[string]string{"test": "late"})
+	hub.Unregister(client)
+}
+
+func TestHubDrainTimeout(t *testing.T) {
+	hub := NewHub()
+	go hub.Run()
+
+	client := &Client{
+		hub:  hub,
+		conn: nil,
+		send: make(chan []byte, WebSocketBufferSize),
+	}
+	hub.Register(client)
+	time.Sleep(10 * time.Millisecond)
+
+	hub.BroadcastJSON(map[string]string{"test": "stuck"})
+	time.Sleep(10 * time.Millisecond)
+
+	// Nobody reads the queue, so the drain gives up at the deadline
+	start := time.Now()
+	if drained := hub.DrainAndShutdown(50 * time.Millisecond); drained != 0 {
+		t.Errorf("expected 0 drained messages, got %d", drained)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("drain did not respect timeout: %v", elapsed)
+	}
+}
+
 func TestFormatAgentNumber(t *testing.T) {
 	tests := []struct {
 		input    int
@@ -296,3 +360,28 @@ func TestFormatAgentNumber(t *testing.T) {
 		}
 	}
 }
+
+func TestWSReconnectConfig(t *testing.T) {
+	t.Setenv("CLIAIMONITOR_WS_RECONNECT_INITIAL_DELAY_MS", "")
+	t.Setenv("CLIAIMONITOR_WS_RECONNECT_MAX_DELAY_MS", "")
+	t.Setenv("CLIAIMONITOR_WS_RECONNECT_JITTER", "")
+
+	cfg := wsReconnectConfig()
+	if cfg.InitialDelayMs != 500 || cfg.MaxDelayMs != 30000 || !cfg.Jitter {
+		t.Errorf("unexpected defaults: %+v", cfg)
+	}
+
+	t.Setenv("CLIAIMONITOR_WS_RECONNECT_INITIAL_DELAY_MS", "1000")
+	t.Setenv("CLIAIMONITOR_WS_RECONNECT_MAX_DELAY_MS", "5000")
+	t.Setenv("CLIAIMONITOR_WS_RECONNECT_JITTER", "false")
+
+	cfg = wsReconnectConfig()
+	if cfg.InitialDelayMs != 1000 || cfg.MaxDelayMs != 5000 || cfg.Jitter {
+		t.Errorf("env overrides not applied: %+v", cfg)
+	}
+
+	var msg types.WSMessage
+	if err := json.Unmarshal(pingMessage, &msg); err != nil || msg.Type != types.WSTypePing {
+		t.Errorf("ping message malformed: %s", pingMessage)
+	}
+}
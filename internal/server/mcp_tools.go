@@ -0,0 +1,57 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+
+	"github.com/CLIAIMONITOR/internal/mcp"
+	"github.com/gorilla/mux"
+)
+
+// handleListMCPTools returns the tools currently registered on the MCP server
+func (s *Server) handleListMCPTools(w http.ResponseWriter, r *http.Request) {
+	tools := s.mcp.ListTools()
+	s.respondJSON(w, map[string]interface{}{
+		"tools": tools,
+		"count": len(tools),
+	})
+}
+
+// handleDeregisterMCPTool removes an MCP tool until the next restart, e.g.
+// to disable spawn_agent during a maintenance window
+func (s *Server) handleDeregisterMCPTool(w http.ResponseWriter, r *http.Request) {
+	name := mux.Vars(r)["name"]
+	if !s.mcp.DeregisterTool(name) {
+		s.respondAPIError(w, ErrMCPToolNotFound.WithDetails(map[string]string{"name": name}))
+		return
+	}
+
+	s.requestLog(r, "mcp").Info("mcp tool deregistered", "tool", name)
+	s.logActivity("mcp_tool_removed", fmt.Sprintf("MCP tool %s deregistered", name))
+	s.respondJSON(w, map[string]interface{}{"success": true, "name": name})
+}
+
+// MCPDocsFile is where the MCP tool reference is written at startup, relative
+// to the base path, so it can be included in Claude system prompts
+const MCPDocsFile = "data/mcp-tools.md"
+
+// handleGetMCPDocs returns the Markdown reference for the registered MCP tools
+func (s *Server) handleGetMCPDocs(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/markdown; charset=utf-8")
+	w.Write([]byte(mcp.GenerateMarkdownDocs(s.mcp.ToolDefinitions())))
+}
+
+// writeMCPDocs writes the MCP tool reference to MCPDocsFile
+func (s *Server) writeMCPDocs() error {
+	path := filepath.Join(s.basePath, filepath.FromSlash(MCPDocsFile))
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create docs directory: %w", err)
+	}
+	docs := mcp.GenerateMarkdownDocs(s.mcp.ToolDefinitions())
+	if err := os.WriteFile(path, []byte(docs), 0644); err != nil {
+		return fmt.Errorf("failed to write MCP docs: %w", err)
+	}
+	return nil
+}
@@ -2,8 +2,100 @@ package server
 
 import (
 	"net/http"
+
+	"github.com/CLIAIMONITOR/internal/logger"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+)
+
+// RequestIDHeader carries the per-request correlation ID on responses
+const RequestIDHeader = "X-Request-ID"
+
+// RequestIDMiddleware assigns each request a UUID, stores it in the request
+// context for logging and MemoryDB calls, and echoes it in the X-Request-ID
+// response header so clients can quote it when reporting problems.
+func RequestIDMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := uuid.New().String()
+		w.Header().Set(RequestIDHeader, id)
+		next.ServeHTTP(w, r.WithContext(logger.WithRequestID(r.Context(), id)))
+	})
+}
+
+// MaxBodySizeMiddleware caps every request body at maxBytes so handlers do
+// not have to limit their own input. Requests that declare a larger
+// Content-Length are rejected with 413 up front; other bodies fail once the
+// limit is read past. The MCP endpoint is exempt because it manages its own
+// streaming connections, and backup restores apply MaxBackupSize instead.
+func MaxBodySizeMiddleware(maxBytes int64) mux.MiddlewareFunc {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == "/mcp" || r.URL.Path == "/api/restore" || r.Body == nil {
+				next.ServeHTTP(w, r)
+				return
+			}
+			if r.ContentLength > maxBytes {
+				http.Error(w, "Request body too large", http.StatusRequestEntityTooLarge)
+				return
+			}
+			r.Body = http.MaxBytesReader(w, r.Body, maxBytes)
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// CORS methods and request headers the REST API accepts from browsers
+const (
+	corsAllowedMethods = "GET, POST, PUT, PATCH, DELETE, OPTIONS"
+	corsAllowedHeaders = "Content-Type, Authorization, X-Request-ID"
+	corsMaxAge         = "600"
 )
 
+// CORSMiddleware lets dashboards served from allowedOrigins call the REST
+// API. Requests from an allowed origin get the Access-Control-Allow-* headers
+// with their origin echoed back; other origins get none, so the browser
+// blocks the response. Preflight OPTIONS requests are answered here without
+// reaching the handler: 204 for allowed origins, 403 otherwise.
+func CORSMiddleware(allowedOrigins []string) mux.MiddlewareFunc {
+	allowed := make(map[string]bool, len(allowedOrigins))
+	for _, origin := range allowedOrigins {
+		allowed[origin] = true
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			origin := r.Header.Get("Origin")
+			preflight := r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != ""
+			if origin == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			h := w.Header()
+			h.Add("Vary", "Origin")
+			if !allowed[origin] {
+				if preflight {
+					http.Error(w, "Origin not allowed", http.StatusForbidden)
+					return
+				}
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			h.Set("Access-Control-Allow-Origin", origin)
+			h.Set("Access-Control-Allow-Methods", corsAllowedMethods)
+			h.Set("Access-Control-Allow-Headers", corsAllowedHeaders)
+			h.Set("Access-Control-Expose-Headers", RequestIDHeader)
+			if preflight {
+				h.Set("Access-Control-Max-Age", corsMaxAge)
+				w.WriteHeader(http.StatusNoContent)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
 // SecurityHeadersMiddleware removes or masks version headers from HTTP responses
 // for security hardening. It prevents information disclosure about the server,
 // Go version, and framework information.
@@ -1,9 +1,14 @@
 package server
 
 import (
+	"bytes"
+	"io"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+
+	"github.com/CLIAIMONITOR/internal/logger"
+	"github.com/CLIAIMONITOR/internal/types"
 )
 
 // TestSecurityHeadersMiddleware verifies that version headers are removed/masked
@@ -113,6 +118,152 @@ func TestSecurityHeadersMiddlewareWithoutWriteHeader(t *testing.T) {
 	}
 }
 
+// TestRequestIDMiddleware verifies the request ID reaches both the handler's
+// context and the X-Request-ID response header, and differs per request
+func TestRequestIDMiddleware(t *testing.T) {
+	var seen string
+	handler := RequestIDMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		seen = logger.RequestID(r.Context())
+	}))
+
+	first := httptest.NewRecorder()
+	handler.ServeHTTP(first, httptest.NewRequest("GET", "http://localhost/test", nil))
+
+	if seen == "" {
+		t.Fatal("expected request ID in handler context")
+	}
+	if got := first.Header().Get(RequestIDHeader); got != seen {
+		t.Errorf("X-Request-ID header: got %q, want %q", got, seen)
+	}
+
+	second := httptest.NewRecorder()
+	handler.ServeHTTP(second, httptest.NewRequest("GET", "http://localhost/test", nil))
+	if second.Header().Get(RequestIDHeader) == first.Header().Get(RequestIDHeader) {
+		t.Error("expected a distinct request ID per request")
+	}
+}
+
+// TestMaxBodySizeMiddleware verifies oversized bodies are rejected with 413
+// everywhere except the MCP endpoint
+func TestMaxBodySizeMiddleware(t *testing.T) {
+	var read int
+	handler := MaxBodySizeMiddleware(MaxPayloadSize)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, err := io.ReadAll(r.Body)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusRequestEntityTooLarge)
+			return
+		}
+		read = len(data)
+	}))
+
+	tests := []struct {
+		name   string
+		path   string
+		size   int
+		status int
+	}{
+		{"at limit", "/api/captain/context", MaxPayloadSize, http.StatusOK},
+		{"over limit", "/api/captain/context", MaxPayloadSize + 1, http.StatusRequestEntityTooLarge},
+		{"mcp exempt", "/mcp", MaxPayloadSize + 1, http.StatusOK},
+		{"restore exempt", "/api/restore", MaxPayloadSize + 1, http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			read = 0
+			recorder := httptest.NewRecorder()
+			req := httptest.NewRequest("POST", "http://localhost"+tt.path, bytes.NewReader(make([]byte, tt.size)))
+			handler.ServeHTTP(recorder, req)
+
+			if recorder.Code != tt.status {
+				t.Fatalf("status: got %d, want %d", recorder.Code, tt.status)
+			}
+			if tt.status == http.StatusOK && read != tt.size {
+				t.Errorf("handler read %d bytes, want %d", read, tt.size)
+			}
+		})
+	}
+
+	// Without a Content-Length the limit applies while the body is read
+	recorder := httptest.NewRecorder()
+	req := httptest.NewRequest("POST", "http://localhost/api/tasks", io.NopCloser(bytes.NewReader(make([]byte, MaxPayloadSize+1))))
+	req.ContentLength = -1
+	handler.ServeHTTP(recorder, req)
+	if recorder.Code != http.StatusRequestEntityTooLarge {
+		t.Errorf("streamed body status: got %d, want %d", recorder.Code, http.StatusRequestEntityTooLarge)
+	}
+}
+
+// TestCORSMiddleware verifies CORS headers on the /api routes for allowed
+// and disallowed origins, including preflight requests
+func TestCORSMiddleware(t *testing.T) {
+	s, _ := newBackupTestServer(t)
+	s.config = &types.TeamsConfig{}
+	s.setupRoutes()
+	srv := httptest.NewServer(s.router)
+	defer srv.Close()
+
+	tests := []struct {
+		name        string
+		method      string
+		path        string
+		origin      string
+		preflight   bool
+		status      int
+		allowOrigin string
+	}{
+		{"allowed origin", "GET", "/api/stats", "http://localhost:3000", false, http.StatusOK, "http://localhost:3000"},
+		{"disallowed origin", "GET", "/api/stats", "http://evil.example.com", false, http.StatusOK, ""},
+		{"no origin", "GET", "/api/stats", "", false, http.StatusOK, ""},
+		{"allowed preflight", "OPTIONS", "/api/agents/spawn", "http://127.0.0.1:8080", true, http.StatusNoContent, "http://127.0.0.1:8080"},
+		{"disallowed preflight", "OPTIONS", "/api/agents/spawn", "http://evil.example.com", true, http.StatusForbidden, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := http.NewRequest(tt.method, srv.URL+tt.path, nil)
+			if err != nil {
+				t.Fatalf("NewRequest failed: %v", err)
+			}
+			if tt.origin != "" {
+				req.Header.Set("Origin", tt.origin)
+			}
+			if tt.preflight {
+				req.Header.Set("Access-Control-Request-Method", "POST")
+			}
+			resp, err := http.DefaultClient.Do(req)
+			if err != nil {
+				t.Fatalf("request failed: %v", err)
+			}
+			resp.Body.Close()
+
+			if resp.StatusCode != tt.status {
+				t.Errorf("status: got %d, want %d", resp.StatusCode, tt.status)
+			}
+			if got := resp.Header.Get("Access-Control-Allow-Origin"); got != tt.allowOrigin {
+				t.Errorf("Access-Control-Allow-Origin: got %q, want %q", got, tt.allowOrigin)
+			}
+			wantMethods := ""
+			if tt.allowOrigin != "" {
+				wantMethods = corsAllowedMethods
+			}
+			if got := resp.Header.Get("Access-Control-Allow-Methods"); got != wantMethods {
+				t.Errorf("Access-Control-Allow-Methods: got %q, want %q", got, wantMethods)
+			}
+		})
+	}
+
+	// CORS is scoped to the API; static files get no CORS headers
+	req, _ := http.NewRequest("GET", srv.URL+"/", nil)
+	req.Header.Set("Origin", "http://localhost:3000")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	resp.Body.Close()
+	if got := resp.Header.Get("Access-Control-Allow-Origin"); got != "" {
+		t.Errorf("static route Access-Control-Allow-Origin: got %q, want none", got)
+	}
+}
+
 // BenchmarkSecurityHeadersMiddleware measures middleware overhead
 func BenchmarkSecurityHeadersMiddleware(b *testing.B) {
 	innerHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
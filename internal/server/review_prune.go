@@ -0,0 +1,33 @@
+package server
+
+import (
+	"context"
+	"time"
+)
+
+// OrphanedBoardMinAge keeps review boards created within the last day even
+// when their assignment is missing, so boards are never pruned while the
+// assignment that owns them is still being written
+const OrphanedBoardMinAge = 24 * time.Hour
+
+// untilNextMidnight returns the wait from now until the next local midnight
+func untilNextMidnight(now time.Time) time.Duration {
+	year, month, day := now.Date()
+	return time.Date(year, month, day+1, 0, 0, 0, 0, now.Location()).Sub(now)
+}
+
+// pruneOrphanedBoards deletes review boards whose task assignment no longer
+// exists. Runs daily at midnight from backgroundTasks.
+func (s *Server) pruneOrphanedBoards() int {
+	if s.memDB == nil {
+		return 0
+	}
+
+	pruned, err := s.memDB.PruneOrphanedBoards(context.Background(), OrphanedBoardMinAge)
+	if err != nil {
+		s.log("review").Warn("failed to prune orphaned review boards", "error", err)
+		return 0
+	}
+	s.log("review").Info("pruned orphaned review boards", "count", pruned)
+	return pruned
+}
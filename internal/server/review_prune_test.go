@@ -0,0 +1,33 @@
+package server
+
+import (
+	"testing"
+	"time"
+)
+
+func TestUntilNextMidnight(t *testing.T) {
+	loc := time.FixedZone("test", -5*60*60)
+	cases := []struct {
+		now  time.Time
+		want time.Duration
+	}{
+		{time.Date(2024, 3, 9, 23, 30, 0, 0, loc), 30 * time.Minute},
+		{time.Date(2024, 3, 9, 0, 0, 0, 0, loc), 24 * time.Hour},
+		{time.Date(2024, 12, 31, 12, 0, 0, 0, loc), 12 * time.Hour},
+	}
+	for _, tc := range cases {
+		if got := untilNextMidnight(tc.now); got != tc.want {
+			t.Errorf("untilNextMidnight(%v) = %v, want %v", tc.now, got, tc.want)
+		}
+	}
+}
+
+func TestPruneOrphanedBoardsNoBoards(t *testing.T) {
+	s, _ := newBackupTestServer(t)
+	if pruned := s.pruneOrphanedBoards(); pruned != 0 {
+		t.Errorf("pruned = %d, want 0", pruned)
+	}
+	if pruned := (&Server{}).pruneOrphanedBoards(); pruned != 0 {
+		t.Errorf("pruned without memDB = %d, want 0", pruned)
+	}
+}
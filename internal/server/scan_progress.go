@@ -0,0 +1,109 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/CLIAIMONITOR/internal/captain"
+	"github.com/CLIAIMONITOR/internal/events"
+	"github.com/CLIAIMONITOR/internal/memory"
+	"github.com/gorilla/mux"
+)
+
+// ScanProgressPollInterval is how often the progress stream re-reads the
+// scan, so it still ends if a completion event was missed
+const ScanProgressPollInterval = 30 * time.Second
+
+// handleScanProgressStream streams a recon scan's progress as Server-Sent
+// Events. The current progress is sent first, then each scan_progress event,
+// until the scan is completed or failed.
+func (s *Server) handleScanProgressStream(w http.ResponseWriter, r *http.Request) {
+	scanID := mux.Vars(r)["id"]
+
+	reconRepo, ok := s.memDB.(memory.ReconRepository)
+	if !ok || s.eventBus == nil {
+		s.respondAPIError(w, ErrServiceUnavailable.WithMessage("Recon storage not available"))
+		return
+	}
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		s.respondAPIError(w, ErrInternal.WithMessage("Streaming not supported"))
+		return
+	}
+
+	// Subscribe before reading the current state so no update is missed
+	ch := s.eventBus.Subscribe(captain.ScanProgressTarget, []events.EventType{events.EventScanProgress})
+	defer s.eventBus.Unsubscribe(captain.ScanProgressTarget, ch)
+
+	progress, err := reconRepo.GetScanProgress(r.Context(), scanID)
+	if err != nil {
+		s.respondAPIError(w, ErrInternal.WithMessage(fmt.Sprintf("Failed to get scan progress: %v", err)))
+		return
+	}
+	if progress == nil {
+		s.respondAPIError(w, ErrScanNotFound.WithDetails(map[string]string{"scan_id": scanID}))
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+
+	send := func(data interface{}) bool {
+		payload, err := json.Marshal(data)
+		if err != nil {
+			return false
+		}
+		if _, err := fmt.Fprintf(w, "data: %s\n\n", payload); err != nil {
+			return false
+		}
+		flusher.Flush()
+		return true
+	}
+
+	if !send(progress) || progress.Done() {
+		return
+	}
+
+	ticker := time.NewTicker(ScanProgressPollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case event, ok := <-ch:
+			if !ok {
+				return
+			}
+			if event.Payload["scan_id"] != scanID {
+				continue
+			}
+			if !send(event.Payload) {
+				return
+			}
+			if status, _ := event.Payload["status"].(string); status == "completed" || status == "failed" {
+				return
+			}
+		case <-ticker.C:
+			progress, err := reconRepo.GetScanProgress(r.Context(), scanID)
+			if err != nil || progress == nil {
+				return
+			}
+			if progress.Done() {
+				send(progress)
+				return
+			}
+		}
+	}
+}
+
+// handleGetReconProfiles lists the scan profiles recon uses per environment type
+func (s *Server) handleGetReconProfiles(w http.ResponseWriter, r *http.Request) {
+	s.respondJSON(w, map[string]interface{}{
+		"profiles": captain.ScanProfiles(),
+	})
+}
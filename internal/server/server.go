@@ -4,17 +4,19 @@ import (
 	"context"
 	"fmt"
 	"io/fs"
-	"log"
+	"log/slog"
 	"net/http"
 	"os"
 	"path/filepath"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/CLIAIMONITOR/internal/agents"
 	"github.com/CLIAIMONITOR/internal/captain"
 	"github.com/CLIAIMONITOR/internal/events"
 	"github.com/CLIAIMONITOR/internal/handlers"
+	"github.com/CLIAIMONITOR/internal/logger"
 	"github.com/CLIAIMONITOR/internal/mcp"
 	"github.com/CLIAIMONITOR/internal/memory"
 	"github.com/CLIAIMONITOR/internal/metrics"
@@ -31,6 +33,10 @@ import (
 const (
 	// MaxReviewCycles is the maximum number of review-rework cycles before escalation
 	MaxReviewCycles = 3
+
+	// AgentUnhealthyScore is the health score below which a running agent
+	// is reported unhealthy
+	AgentUnhealthyScore = 40.0
 )
 
 // Server is the main HTTP server
@@ -39,6 +45,9 @@ type Server struct {
 	router     *mux.Router
 	hub        *Hub
 
+	// Coalesces broadcastState calls (see broadcastDebounce)
+	stateBroadcaster *ThrottledBroadcaster
+
 	// Dependencies
 	store             *persistence.JSONStore
 	spawner           *agents.ProcessSpawner
@@ -46,6 +55,7 @@ type Server struct {
 	metrics           *metrics.MetricsCollector
 	alerts            *metrics.AlertChecker
 	config            *types.TeamsConfig
+	configMu          sync.RWMutex
 	projectsConfig    *types.ProjectsConfig
 	memDB             memory.MemoryDB
 	notifications     *notifications.Manager
@@ -53,8 +63,18 @@ type Server struct {
 	captainSupervisor *captain.CaptainSupervisor
 	basePath          string
 
+	// teams.yaml watched for hot-reload, and its mtime when last loaded
+	teamsConfigPath    string
+	teamsConfigModTime time.Time
+
+	// Orphaned PID files and MCP configs removed at startup
+	orphanedFilesCleaned int
+
+	// Go runtime stats reported by /api/health
+	runtimeStats runtimeStatsCache
+
 	// Task system
-	taskQueue *tasks.Queue
+	taskQueue tasks.TaskQueue
 	taskStore *tasks.Store
 
 	// Event bus for real-time notifications
@@ -62,10 +82,29 @@ type Server struct {
 	eventStore   *events.SQLiteStore
 	notifyRouter *notifications.Router
 
+	// Signed review completion webhook (nil unless configured)
+	reviewWebhook *captain.ReviewWebhookDispatcher
+
+	// Context keys already warned about, with the expiry they were warned for
+	contextWarned   map[string]time.Time
+	contextWarnedMu sync.Mutex
+
+	// Set while the memory DB is failing its integrity check, so the
+	// db_integrity_error alert and repair attempt happen once per failure
+	dbIntegrityFailed   bool
+	dbIntegrityFailedMu sync.Mutex
+
+	// Repo IDs by project path, for cost attribution
+	repoIDs   map[string]repoIDEntry
+	repoIDsMu sync.Mutex
+
 	// Instance metadata
 	port      int
 	startTime time.Time
 
+	// Structured logger (components are tagged via s.log)
+	logger *slog.Logger
+
 	// Background tasks
 	stopChan chan struct{}
 
@@ -77,13 +116,13 @@ type Server struct {
 func loadNotificationConfig(configPath string) *types.NotificationsConfig {
 	data, err := os.ReadFile(configPath)
 	if err != nil {
-		log.Printf("[NOTIFY] Config not found at %s, notifications disabled", configPath)
+		logger.For("notify").Info("config not found, notifications disabled", "path", configPath)
 		return nil
 	}
 
 	var config types.NotificationsConfig
 	if err := yaml.Unmarshal(data, &config); err != nil {
-		log.Printf("[NOTIFY] Failed to parse config: %v", err)
+		logger.For("notify").Error("failed to parse config", "error", err)
 		return nil
 	}
 
@@ -121,8 +160,18 @@ func NewServer(
 		agentConfigs[cfg.Name] = cfg
 	}
 
-	// Initialize Captain orchestrator
-	cap := captain.NewCaptain(basePath, spawner, memDB, agentConfigs)
+	// Initialize Captain orchestrator. Pass a nil interface rather than a nil
+	// *ProcessSpawner so Captain's spawner checks still see it as unset.
+	var captainSpawner captain.AgentSpawner
+	if spawner != nil {
+		captainSpawner = spawner
+	}
+	cap := captain.NewCaptain(basePath, captainSpawner, memDB, agentConfigs)
+	cap.SetActivityRecorder(store)
+	if oauth := captain.PlannerOAuth2ConfigFromEnv(); oauth != nil {
+		cap.SetPlannerOAuth2(*oauth)
+	}
+	cap.SetPlannerProxyURL(fmt.Sprintf("http://localhost:%d/api/captain/planner", port))
 
 	s := &Server{
 		hub:            NewHub(),
@@ -139,16 +188,24 @@ func NewServer(
 		basePath:       basePath,
 		port:           port,
 		startTime:      time.Now(),
+		logger:         slog.Default(),
 		stopChan:       make(chan struct{}),
 		ShutdownChan:   make(chan struct{}),
 	}
+	s.stateBroadcaster = NewThrottledBroadcaster(broadcastDebounce, s.sendState)
+	s.teamsConfigPath = filepath.Join(basePath, "configs", "teams.yaml")
+	s.teamsConfigModTime = fileModTime(s.teamsConfigPath)
 
 	// Seed default prompts from files if DB is empty
 	if s.memDB != nil {
 		if sqliteDB, ok := s.memDB.(*memory.SQLiteMemoryDB); ok {
 			promptsDir := filepath.Join(basePath, "configs", "prompts")
 			if err := sqliteDB.SeedDefaultPrompts(promptsDir); err != nil {
-				log.Printf("[SERVER] Warning: Failed to seed default prompts: %v", err)
+				s.log("server").Warn("failed to seed default prompts", "error", err)
+			}
+			categoriesPath := filepath.Join(basePath, "configs", "defect_categories.yaml")
+			if err := sqliteDB.SeedDefectCategories(categoriesPath); err != nil {
+				s.log("server").Warn("failed to seed defect categories", "error", err)
 			}
 		}
 	}
@@ -162,9 +219,9 @@ func NewServer(
 		if content, err := os.ReadFile(teamsPath); err == nil {
 			if existing, _ := s.memDB.GetConfig("teams"); existing == nil {
 				if err := s.memDB.SaveConfig("teams", string(content), "yaml"); err != nil {
-					log.Printf("[SERVER] Warning: Failed to seed teams config: %v", err)
+					s.log("server").Warn("failed to seed teams config", "error", err)
 				} else {
-					log.Printf("[SERVER] Seeded teams config from teams.yaml")
+					s.log("server").Info("seeded teams config from teams.yaml")
 				}
 			}
 		}
@@ -174,9 +231,9 @@ func NewServer(
 		if content, err := os.ReadFile(projectsPath); err == nil {
 			if existing, _ := s.memDB.GetConfig("projects"); existing == nil {
 				if err := s.memDB.SaveConfig("projects", string(content), "yaml"); err != nil {
-					log.Printf("[SERVER] Warning: Failed to seed projects config: %v", err)
+					s.log("server").Warn("failed to seed projects config", "error", err)
 				} else {
-					log.Printf("[SERVER] Seeded projects config from projects.yaml")
+					s.log("server").Info("seeded projects config from projects.yaml")
 				}
 			}
 		}
@@ -186,9 +243,9 @@ func NewServer(
 		if content, err := os.ReadFile(notificationsPath); err == nil {
 			if existing, _ := s.memDB.GetConfig("notifications"); existing == nil {
 				if err := s.memDB.SaveConfig("notifications", string(content), "yaml"); err != nil {
-					log.Printf("[SERVER] Warning: Failed to seed notifications config: %v", err)
+					s.log("server").Warn("failed to seed notifications config", "error", err)
 				} else {
-					log.Printf("[SERVER] Seeded notifications config from notifications.yaml")
+					s.log("server").Info("seeded notifications config from notifications.yaml")
 				}
 			}
 		}
@@ -203,21 +260,15 @@ func NewServer(
 	s.store.SetCaptainConnected(false) // Will be set true when Captain registers via MCP
 
 	// Initialize task system
-	s.taskQueue = tasks.NewQueue()
 	s.taskStore = tasks.NewStore(memDB.(*memory.SQLiteMemoryDB).DB())
 	if err := s.taskStore.Init(); err != nil {
-		log.Printf("[TASKS] Warning: Failed to initialize task store: %v", err)
+		// Without a tasks table, keep tasks in memory for this run
+		s.log("tasks").Warn("failed to initialize task store", "error", err)
+		s.taskQueue = tasks.NewQueue()
 	} else {
-		// Load persisted tasks into queue
-		savedTasks, err := s.taskStore.GetAll()
-		if err != nil {
-			log.Printf("[TASKS] Warning: Failed to load tasks: %v", err)
-		} else {
-			for _, t := range savedTasks {
-				s.taskQueue.Add(t)
-			}
-			log.Printf("[TASKS] Loaded %d persisted tasks", len(savedTasks))
-		}
+		queue := tasks.NewSQLiteQueue(s.taskStore)
+		s.taskQueue = queue
+		s.log("tasks").Info("loaded persisted tasks", "count", queue.Len())
 	}
 
 	// Initialize event store using the same database connection
@@ -226,17 +277,18 @@ func NewServer(
 		var err error
 		eventStore, err = events.NewSQLiteStore(sqliteDB.DB())
 		if err != nil {
-			log.Printf("[EVENTS] Warning: Failed to initialize event store: %v", err)
+			s.log("events").Warn("failed to initialize event store", "error", err)
 		}
 	}
 
 	// Initialize event bus (works with nil store)
 	eventBus := events.NewBus(eventStore)
-	log.Printf("[EVENTS] Event bus initialized (store: %v)", eventStore != nil)
+	s.log("events").Info("event bus initialized", "store", eventStore != nil)
 
 	// Assign to server struct
 	s.eventBus = eventBus
 	s.eventStore = eventStore
+	cap.SetEventBus(eventBus)
 
 	// Initialize notification router
 	notifyRouter := notifications.NewRouter(nil)
@@ -253,7 +305,7 @@ func NewServer(
 				EventTypes:  parseEventTypes(notifyConfig.Slack.EventTypes),
 				MinPriority: notifyConfig.Slack.MinPriority,
 			}))
-			log.Printf("[NOTIFY] Slack channel enabled")
+			s.log("notify").Info("channel enabled", "channel", "slack")
 		}
 		if notifyConfig.Discord.Enabled && notifyConfig.Discord.WebhookURL != "" {
 			notifyRouter.AddChannel(external.NewDiscordNotifier(external.DiscordConfig{
@@ -263,32 +315,44 @@ func NewServer(
 				EventTypes:  parseEventTypes(notifyConfig.Discord.EventTypes),
 				MinPriority: notifyConfig.Discord.MinPriority,
 			}))
-			log.Printf("[NOTIFY] Discord channel enabled")
+			s.log("notify").Info("channel enabled", "channel", "discord")
 		}
 		if notifyConfig.Email.Enabled && notifyConfig.Email.SMTPHost != "" {
 			notifyRouter.AddChannel(external.NewEmailNotifier(external.EmailConfig{
-				SMTPHost:    notifyConfig.Email.SMTPHost,
-				SMTPPort:    notifyConfig.Email.SMTPPort,
-				Username:    notifyConfig.Email.Username,
-				Password:    notifyConfig.Email.Password,
-				From:        notifyConfig.Email.From,
-				To:          notifyConfig.Email.To,
-				EventTypes:  parseEventTypes(notifyConfig.Email.EventTypes),
-				MinPriority: notifyConfig.Email.MinPriority,
+				SMTPHost:     notifyConfig.Email.SMTPHost,
+				SMTPPort:     notifyConfig.Email.SMTPPort,
+				Username:     notifyConfig.Email.Username,
+				Password:     notifyConfig.Email.Password,
+				From:         notifyConfig.Email.From,
+				To:           notifyConfig.Email.To,
+				EventTypes:   parseEventTypes(notifyConfig.Email.EventTypes),
+				MinPriority:  notifyConfig.Email.MinPriority,
+				TemplatePath: notifyConfig.Email.TemplatePath,
 			}))
-			log.Printf("[NOTIFY] Email channel enabled")
+			s.log("notify").Info("channel enabled", "channel", "email")
 		}
 	}
-	log.Printf("[NOTIFY] Router initialized with %d channels", len(notifyRouter.GetChannels()))
+	s.log("notify").Info("router initialized", "channels", len(notifyRouter.GetChannels()))
 
 	// Assign to server struct
 	s.notifyRouter = notifyRouter
 
+	// Allowed WebSocket origins are read from memory context at request time
+	if err := wsOrigins.setStore(s.memDB); err != nil {
+		s.log("server").Warn("failed to seed allowed WebSocket origins", "error", err)
+	}
+
+	// Publish review_completed events and webhooks when a review board finishes
+	if sqliteDB, ok := s.memDB.(*memory.SQLiteMemoryDB); ok {
+		s.reviewWebhook = captain.NewReviewWebhookDispatcherFromEnv(sqliteDB)
+		sqliteDB.SetReviewCompletedHook(s.onReviewCompleted)
+	}
+
 	// Start notification routing goroutine
 	if s.eventBus != nil && s.notifyRouter != nil {
 		go func() {
 			sub := s.eventBus.Subscribe("all", nil)
-			log.Printf("[NOTIFY] Started routing events to notification channels")
+			s.log("notify").Info("started routing events to notification channels")
 			for event := range sub {
 				s.notifyRouter.Route(event)
 			}
@@ -307,16 +371,23 @@ func (s *Server) setupRoutes() {
 
 	// Apply security middleware globally to all routes
 	s.router.Use(SecurityHeadersMiddleware)
+	s.router.Use(RequestIDMiddleware)
+	s.router.Use(MaxBodySizeMiddleware(MaxPayloadSize))
 
 	// API routes
 	api := s.router.PathPrefix("/api").Subrouter()
+	api.Use(CORSMiddleware(allowedOrigins))
 	api.HandleFunc("/state", s.handleGetState).Methods("GET")
 	api.HandleFunc("/projects", s.handleGetProjects).Methods("GET")
+	api.HandleFunc("/agents", s.handleListAgents).Methods("GET")
 	api.HandleFunc("/agents/spawn", s.handleSpawnAgent).Methods("POST")
 	api.HandleFunc("/agents/{id}/stop", s.handleStopAgent).Methods("POST")
 	api.HandleFunc("/agents/{id}/graceful-stop", s.handleGracefulStopAgent).Methods("POST")
 	api.HandleFunc("/agents/cleanup", s.handleCleanupAgents).Methods("POST")
 	api.HandleFunc("/agents/db", s.handleGetAgentsFromDB).Methods("GET")
+	api.HandleFunc("/agents/colors", s.handleGetAgentColors).Methods("GET")
+	api.HandleFunc("/agents/spawn-history", s.handleGetSpawnHistory).Methods("GET")
+	api.HandleFunc("/agents/{id}/health-score", s.handleGetAgentHealthScore).Methods("GET")
 	api.HandleFunc("/human-input/{id}", s.handleAnswerHumanInput).Methods("POST")
 	api.HandleFunc("/alerts", s.handleGetAlerts).Methods("GET")
 	api.HandleFunc("/alerts/clear", s.handleClearAllAlerts).Methods("POST")
@@ -326,9 +397,19 @@ func (s *Server) setupRoutes() {
 	api.HandleFunc("/metrics/by-model", s.handleGetMetricsByModel).Methods("GET")
 	api.HandleFunc("/metrics/by-agent-type", s.handleGetMetricsByAgentType).Methods("GET")
 	api.HandleFunc("/metrics/by-agent", s.handleGetMetricsByAgent).Methods("GET")
+	api.HandleFunc("/metrics/cost-by-repo", s.handleGetCostByRepo).Methods("GET")
+	api.HandleFunc("/activity/archive", s.handleGetActivityArchive).Methods("GET")
 	api.HandleFunc("/health", s.handleHealthCheck).Methods("GET")
+	api.HandleFunc("/errors", s.handleListAPIErrors).Methods("GET")
+	api.HandleFunc("/backup", s.handleBackup).Methods("GET")
+	api.HandleFunc("/restore", s.handleRestore).Methods("POST")
+	api.HandleFunc("/config/ws-origins", s.handleGetWSOrigins).Methods("GET")
+	api.HandleFunc("/config/ws-origins", s.handlePutWSOrigins).Methods("PUT")
+	api.HandleFunc("/config/reload", s.handleReloadConfig).Methods("POST")
+	api.HandleFunc("/config/history", s.handleGetConfigHistory).Methods("GET")
 	api.HandleFunc("/shutdown", s.handleShutdown).Methods("POST")
 	api.HandleFunc("/stats", s.handleGetStats).Methods("GET")
+	api.HandleFunc("/stats/history", s.handleGetStatsHistory).Methods("GET")
 
 	// Notification API routes
 	api.HandleFunc("/notifications/banner", s.handleGetBanner).Methods("GET")
@@ -339,7 +420,7 @@ func (s *Server) setupRoutes() {
 	api.HandleFunc("/stop-requests/{id}/respond", s.handleRespondStopRequest).Methods("POST")
 
 	// Supervisor API routes
-	supervisorHandler := handlers.NewSupervisorHandler(s.memDB)
+	supervisorHandler := handlers.NewSupervisorHandler(s.memDB).WithExecutor(handlers.DeploymentExecutorFromEnv())
 	supervisorHandler.RegisterRoutes(api)
 
 	// Coordination API routes (Captain's decision engine)
@@ -347,7 +428,8 @@ func (s *Server) setupRoutes() {
 	coordinationHandler.RegisterRoutes(api)
 
 	// Task management routes
-	taskHandler := handlers.NewTasksHandler(s.taskQueue, s.taskStore)
+	// The SQLite queue persists tasks itself, so the handler needs no store
+	taskHandler := handlers.NewTasksHandler(s.taskQueue, nil)
 	api.HandleFunc("/tasks", taskHandler.HandleList).Methods("GET")
 	api.HandleFunc("/tasks", taskHandler.HandleCreate).Methods("POST")
 	api.HandleFunc("/tasks/{id}", taskHandler.HandleGet).Methods("GET")
@@ -362,18 +444,27 @@ func (s *Server) setupRoutes() {
 	api.HandleFunc("/captain/execute/parallel", captainHandler.HandleExecuteParallel).Methods("POST")
 	api.HandleFunc("/captain/import-tasks", captainHandler.HandleImportTasks).Methods("POST")
 	api.HandleFunc("/captain/subagents", captainHandler.HandleActiveSubagents).Methods("GET")
+	api.HandleFunc("/captain/models", captainHandler.HandleGetModels).Methods("GET")
+	api.HandleFunc("/captain/claude-status", captainHandler.HandleClaudeStatus).Methods("GET")
 	api.HandleFunc("/captain/api-key", captainHandler.HandleSetAPIKey).Methods("POST")
+	api.HandleFunc("/captain/oauth/refresh", captainHandler.HandleOAuthRefresh).Methods("POST")
+	api.HandleFunc("/captain/planner/{path:.*}", captainHandler.HandlePlannerProxy).Methods("GET", "POST", "PUT", "PATCH", "DELETE")
 	api.HandleFunc("/captain/recon", captainHandler.HandleRecon).Methods("POST")
 	// New Captain endpoints
 	api.HandleFunc("/captain/task", captainHandler.HandleSubmitTask).Methods("POST")
+	api.HandleFunc("/captain/tasks/{id}", captainHandler.HandleDeleteTask).Methods("DELETE")
 	api.HandleFunc("/captain/status", captainHandler.HandleGetStatus).Methods("GET")
+	api.HandleFunc("/captain/teams/{team_id}/pause", captainHandler.HandlePauseTeam).Methods("POST")
+	api.HandleFunc("/captain/teams/{team_id}/resume", captainHandler.HandleResumeTeam).Methods("POST")
 	api.HandleFunc("/captain/trigger-recon", captainHandler.HandleTriggerRecon).Methods("POST")
 	api.HandleFunc("/captain/escalations", captainHandler.HandleGetEscalations).Methods("GET")
 	api.HandleFunc("/captain/escalation/{id}/respond", captainHandler.HandleRespondToEscalation).Methods("POST")
+	api.HandleFunc("/captain/escalations/{id}/resolve", captainHandler.HandleResolveEscalation).Methods("POST")
 
 	// Captain Supervisor (terminal process) endpoints
 	api.HandleFunc("/captain/terminal/status", s.handleCaptainTerminalStatus).Methods("GET")
 	api.HandleFunc("/captain/terminal/restart", s.handleCaptainTerminalRestart).Methods("POST")
+	api.HandleFunc("/captain/terminal/heartbeat", s.handleCaptainTerminalHeartbeat).Methods("POST")
 
 	// Captain health endpoint
 	api.HandleFunc("/captain/health", s.handleCaptainHealth).Methods("GET")
@@ -383,18 +474,53 @@ func (s *Server) setupRoutes() {
 	api.HandleFunc("/captain/context", s.handleSetCaptainContext).Methods("POST")
 	api.HandleFunc("/captain/context/{key}", s.handleDeleteCaptainContext).Methods("DELETE")
 	api.HandleFunc("/captain/context/summary", s.handleGetCaptainContextSummary).Methods("GET")
+	api.HandleFunc("/captain/context/expiring", s.handleGetExpiringCaptainContext).Methods("GET")
+	api.HandleFunc("/recon/scans/{id}/progress", s.handleScanProgressStream).Methods("GET")
+	api.HandleFunc("/recon/profiles", s.handleGetReconProfiles).Methods("GET")
+	api.HandleFunc("/recon/environments/{id}/findings/export", s.handleExportFindings).Methods("GET")
+	api.HandleFunc("/recon/environments/{id}/policy", s.handleGetEnvironmentPolicy).Methods("GET")
+	api.HandleFunc("/recon/environments/{id}/policy", s.handleUpdateEnvironmentPolicy).Methods("PUT")
+
+	// Captain task provenance
+	api.HandleFunc("/captain/tasks", s.handleListCaptainTasks).Methods("GET")
+	api.HandleFunc("/captain/tasks/{id}/attempts", s.handleGetMissionAttempts).Methods("GET")
+	api.HandleFunc("/captain/tasks/{id}/logs", s.handleTaskLogsStream).Methods("GET")
+	api.HandleFunc("/captain/tasks/{id}/stream-output", s.handleStreamTaskOutput).Methods("POST")
+
+	// Captain orchestration metrics
+	api.HandleFunc("/captain/metrics/history", s.handleGetOrchestratorMetricHistory).Methods("GET")
+	api.HandleFunc("/debug/traces", s.handleGetTrace).Methods("GET")
+	api.HandleFunc("/events/history", s.handleGetEventHistory).Methods("GET")
+	api.HandleFunc("/mcp/tools", s.handleListMCPTools).Methods("GET")
+	api.HandleFunc("/mcp/docs", s.handleGetMCPDocs).Methods("GET")
+	api.HandleFunc("/mcp/tools/{name}", s.handleDeregisterMCPTool).Methods("DELETE")
 
 	// Review Board / Leaderboard endpoints
 	api.HandleFunc("/leaderboard", s.handleGetLeaderboard).Methods("GET")
+	api.HandleFunc("/leaderboard/{agent_id}/history", s.handleGetQualityScoreHistory).Methods("GET")
 	api.HandleFunc("/review-boards", s.handleGetReviewBoards).Methods("GET")
+	api.HandleFunc("/review-boards/{id}/slots", s.handleGetReviewerSlots).Methods("GET")
 	api.HandleFunc("/defect-categories", s.handleGetDefectCategories).Methods("GET")
+	api.HandleFunc("/defect-categories", s.handleCreateDefectCategory).Methods("POST")
+	api.HandleFunc("/defect-categories/{code}", s.handleUpdateDefectCategory).Methods("PUT")
+	api.HandleFunc("/defect-categories/{code}", s.handleDeleteDefectCategory).Methods("DELETE")
+	api.HandleFunc("/defect-patterns", s.handleGetDefectPatterns).Methods("GET")
+	api.HandleFunc("/experiments", s.handleCreateExperiment).Methods("POST")
+	api.HandleFunc("/experiments/{id}/results", s.handleGetExperimentResults).Methods("GET")
 
 	// Escalation & Captain Control endpoints
 	api.HandleFunc("/escalation/{id}/respond", s.handleSubmitEscalationResponse).Methods("POST")
 	api.HandleFunc("/captain/command", s.handleSendCaptainCommand).Methods("POST")
 
+	// Subrouter middleware only runs on matched routes, so give CORS
+	// preflights a route of their own
+	api.PathPrefix("/").Methods("OPTIONS").HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	})
+
 	// WebSocket
 	s.router.HandleFunc("/ws", s.handleWebSocket)
+	s.router.HandleFunc("/ws/agents/{id}/output", s.handleAgentOutputWebSocket)
 
 	// MCP endpoint (POST-only JSON-RPC)
 	s.router.HandleFunc("/mcp", s.mcp.ServeHTTP)
@@ -402,7 +528,7 @@ func (s *Server) setupRoutes() {
 	// Static files
 	staticFS, err := fs.Sub(web.StaticFiles, ".")
 	if err != nil {
-		log.Printf("[SERVER] Warning: Failed to create static file system: %v", err)
+		s.log("server").Warn("failed to create static file system", "error", err)
 	} else {
 		s.router.PathPrefix("/").Handler(http.FileServer(http.FS(staticFS)))
 	}
@@ -413,7 +539,7 @@ func (s *Server) setupRoutes() {
 func (s *Server) setupMCPCallbacks() {
 	callbacks := mcp.ToolCallbacks{
 		// Captain context callbacks
-		OnSaveContext: func(key, value string, priority, maxAgeHours int) (interface{}, error) {
+		OnSaveContext: func(ctx context.Context, key, value string, priority, maxAgeHours int) (interface{}, error) {
 			if err := s.memDB.SetContext(key, value, priority, maxAgeHours); err != nil {
 				return nil, fmt.Errorf("failed to save context: %w", err)
 			}
@@ -424,19 +550,19 @@ func (s *Server) setupMCPCallbacks() {
 			}, nil
 		},
 
-		OnGetAllContext: func() (interface{}, error) {
+		OnGetAllContext: func(ctx context.Context) (interface{}, error) {
 			contexts, err := s.memDB.GetAllContext()
 			if err != nil {
 				return nil, fmt.Errorf("failed to get all context: %w", err)
 			}
 			var items []map[string]interface{}
-			for _, ctx := range contexts {
+			for _, entry := range contexts {
 				items = append(items, map[string]interface{}{
-					"key":           ctx.Key,
-					"value":         ctx.Value,
-					"priority":      ctx.Priority,
-					"max_age_hours": ctx.MaxAgeHours,
-					"updated_at":    ctx.UpdatedAt,
+					"key":           entry.Key,
+					"value":         entry.Value,
+					"priority":      entry.Priority,
+					"max_age_hours": entry.MaxAgeHours,
+					"updated_at":    entry.UpdatedAt,
 				})
 			}
 			return map[string]interface{}{
@@ -445,8 +571,8 @@ func (s *Server) setupMCPCallbacks() {
 			}, nil
 		},
 
-		OnLogSession: func(sessionID, eventType, summary, details, agentID string) (interface{}, error) {
-			if err := s.memDB.LogSessionEvent(sessionID, eventType, summary, details, agentID); err != nil {
+		OnLogSession: func(ctx context.Context, sessionID, eventType, summary, details, agentID string) (interface{}, error) {
+			if err := s.memDB.LogSessionEventContext(ctx, sessionID, eventType, summary, details, agentID); err != nil {
 				return nil, fmt.Errorf("failed to log session event: %w", err)
 			}
 			return map[string]interface{}{
@@ -497,6 +623,44 @@ func (s *Server) setupMCPCallbacks() {
 				"message": "Response sent to dashboard",
 			}, nil
 		},
+
+		OnRegisterAgent: func(ctx context.Context, agentID, role string, capabilities []string) (interface{}, error) {
+			if !isValidAgentID(agentID) {
+				return nil, fmt.Errorf("invalid agent ID: %s", agentID)
+			}
+			now := time.Now()
+			if s.store.GetAgent(agentID) == nil {
+				// Agents spawned by Captain in terminal mode are not in the
+				// store until they register
+				s.store.AddAgent(&types.Agent{
+					ID:        agentID,
+					Role:      types.AgentRole(role),
+					SpawnedAt: now,
+				})
+			}
+			s.store.UpdateAgent(agentID, func(a *types.Agent) {
+				a.Role = types.AgentRole(role)
+				a.Status = types.StatusConnected
+				a.Capabilities = capabilities
+				a.LastSeen = now
+			})
+			s.store.AddActivity(&types.ActivityLog{
+				ID:        fmt.Sprintf("agent-registered-%d", now.UnixNano()),
+				AgentID:   agentID,
+				Action:    "agent_registered",
+				Details:   role,
+				Timestamp: now,
+				TraceID:   logger.TraceID(ctx),
+			})
+			s.broadcastState()
+			logger.FromContext(ctx, s.log("mcp")).Info("agent registered", "agent_id", agentID, "role", role, "capabilities", capabilities)
+			return map[string]interface{}{
+				"success":      true,
+				"agent_id":     agentID,
+				"status":       types.StatusConnected,
+				"capabilities": capabilities,
+			}, nil
+		},
 	}
 
 	mcp.RegisterDefaultTools(s.mcp, callbacks)
@@ -510,23 +674,23 @@ func (s *Server) setupMCPCallbacks() {
 		const tokensPerCall = 500
 		const costPer1kTokens = 0.003 // Rough estimate
 
-		// Get current metrics or create new
-		state := s.store.GetState()
-		agentMetrics := state.Metrics[agentID]
-		if agentMetrics == nil {
-			agentMetrics = &types.AgentMetrics{}
+		// Every MCP call counts as a heartbeat; a degraded agent that
+		// calls in again is back to connected
+		if agentID == "Captain" && s.captainSupervisor != nil {
+			s.captainSupervisor.RecordHeartbeat()
 		}
-
-		// Add estimated tokens
-		newTokens := agentMetrics.TokensUsed + tokensPerCall
-		newCost := float64(newTokens) / 1000.0 * costPer1kTokens
-
-		s.store.UpdateMetrics(agentID, &types.AgentMetrics{
-			TokensUsed:         newTokens,
-			EstimatedCost:      newCost,
-			FailedTests:        agentMetrics.FailedTests,
-			ConsecutiveRejects: agentMetrics.ConsecutiveRejects,
+		s.store.UpdateAgent(agentID, func(a *types.Agent) {
+			a.LastSeen = time.Now()
+			if a.Status == types.StatusDegraded {
+				a.Status = types.StatusConnected
+			}
 		})
+
+		// Add estimated tokens in one locked step; batch calls run this
+		// callback concurrently
+		callCost := float64(tokensPerCall) / 1000.0 * costPer1kTokens
+		s.store.AddTokenUsage(agentID, tokensPerCall, callCost)
+		s.recordAgentCost(agentID, tokensPerCall, callCost)
 		s.broadcastState()
 	})
 
@@ -534,7 +698,13 @@ func (s *Server) setupMCPCallbacks() {
 	if s.eventBus != nil {
 		mcp.RegisterWaitForEventsTool(s.mcp, s.eventBus)
 		mcp.RegisterSendToAgentTool(s.mcp, s.eventBus)
-		log.Printf("[MCP] Registered wait_for_events and send_to_agent tools")
+		s.log("mcp").Info("registered wait_for_events and send_to_agent tools")
+	}
+
+	// claim_task needs the persisted task store; the in-memory fallback
+	// queue has no atomic claim
+	if _, ok := s.taskQueue.(*tasks.SQLiteQueue); ok {
+		mcp.RegisterClaimTaskTool(s.mcp, s.taskStore)
 	}
 }
 
@@ -548,8 +718,15 @@ func (s *Server) Start(addr string) error {
 	// Start hub
 	go s.hub.Run()
 
+	if err := s.writeMCPDocs(); err != nil {
+		s.log("mcp").Warn("failed to write MCP tool docs", "path", MCPDocsFile, "error", err)
+	}
+
 	// Start background tasks
 	go s.backgroundTasks()
+	go s.reviewWebhookRetryLoop()
+	go s.watchTeamsConfig()
+	go NewHeartbeatChecker(s.store, s.alerts, s.hub, s.log("heartbeat")).Run(s.stopChan)
 
 	fmt.Printf("Dashboard ready at http://localhost%s\n", addr)
 	return s.httpServer.ListenAndServe()
@@ -559,13 +736,18 @@ func (s *Server) Start(addr string) error {
 func (s *Server) Shutdown(ctx context.Context) error {
 	close(s.stopChan)
 
-	// Shutdown WebSocket hub to close all channels properly
+	// Send any coalesced state update, flush queued WebSocket messages,
+	// then close all connections
+	if s.stateBroadcaster != nil {
+		s.stateBroadcaster.Flush()
+	}
 	if s.hub != nil {
-		s.hub.Shutdown()
-		log.Printf("[HUB] WebSocket hub shutdown complete")
+		drained := s.hub.DrainAndShutdown(WebSocketDrainTimeout)
+		s.log("hub").Info("WebSocket hub shutdown complete", "drained_messages", drained)
 	}
 
-	// Save state
+	// Archive trimmed activity, then save state
+	s.rotateActivityLog()
 	s.store.Save()
 
 	return s.httpServer.Shutdown(ctx)
@@ -582,6 +764,39 @@ func (s *Server) RequestShutdown() {
 	}
 }
 
+// SetLogger sets the base structured logger for the server and its spawner
+func (s *Server) SetLogger(l *slog.Logger) {
+	if l == nil {
+		return
+	}
+	s.logger = l
+	if s.spawner != nil {
+		s.spawner.SetLogger(l)
+	}
+}
+
+// log returns the server logger tagged with a component group
+func (s *Server) log(component string) *slog.Logger {
+	return logger.WithComponent(s.logger, component)
+}
+
+// requestLog returns a component logger tagged with the request's ID
+func (s *Server) requestLog(r *http.Request, component string) *slog.Logger {
+	return logger.FromContext(r.Context(), s.log(component))
+}
+
+// Captain returns the orchestrator used by the Captain API endpoints, so the
+// orchestration loop and the API share one task queue
+func (s *Server) Captain() *captain.Captain {
+	return s.captain
+}
+
+// SetOrphanedFilesCleaned records how many orphaned agent files startup
+// removed, reported by /api/health
+func (s *Server) SetOrphanedFilesCleaned(n int) {
+	s.orphanedFilesCleaned = n
+}
+
 // SetCaptainSupervisor sets the captain supervisor reference for API endpoints
 func (s *Server) SetCaptainSupervisor(supervisor *captain.CaptainSupervisor) {
 	s.captainSupervisor = supervisor
@@ -591,15 +806,26 @@ func (s *Server) SetCaptainSupervisor(supervisor *captain.CaptainSupervisor) {
 func (s *Server) backgroundTasks() {
 	ticker := time.NewTicker(30 * time.Second)
 	defer ticker.Stop()
+	statsTicker := time.NewTicker(SessionStatsSnapshotInterval)
+	defer statsTicker.Stop()
+	pruneTimer := time.NewTimer(untilNextMidnight(time.Now()))
+	defer pruneTimer.Stop()
 
 	for {
 		select {
 		case <-s.stopChan:
 			return
+		case <-statsTicker.C:
+			s.snapshotSessionStats()
+		case <-pruneTimer.C:
+			s.pruneOrphanedBoards()
+			pruneTimer.Reset(untilNextMidnight(time.Now()))
 		case <-ticker.C:
 			s.checkAlerts()
 			s.checkAgentHealth()
 			s.metrics.TakeSnapshot()
+			s.sweepCaptainContext()
+			s.rotateActivityLog()
 		}
 	}
 }
@@ -613,6 +839,14 @@ func (s *Server) checkAlerts() {
 	for _, alert := range metricsAlerts {
 		s.store.AddAlert(alert)
 		s.hub.BroadcastAlert(alert)
+
+		// Over-budget agents are stopped the same way as the dashboard stop button
+		if alert.Type == metrics.AlertTypeBudgetExceeded {
+			if agent, ok := state.Agents[alert.AgentID]; ok && !agent.ShutdownRequested {
+				s.log("alerts").Warn("agent exceeded cost budget, stopping", "agent_id", alert.AgentID)
+				s.gracefulStopAgent(alert.AgentID)
+			}
+		}
 	}
 
 	// Check agent status alerts
@@ -631,50 +865,244 @@ func (s *Server) checkAlerts() {
 	}
 }
 
-// checkAgentHealth verifies agent processes are still running
+// onReviewCompleted runs when a review board transitions to completed
+func (s *Server) onReviewCompleted(board *memory.ReviewBoard) {
+	s.publishReviewCompleted(board)
+
+	if s.reviewWebhook.Enabled() {
+		defectCount := 0
+		if defects, err := s.memDB.GetBoardDefects(board.ID); err == nil {
+			defectCount = len(defects)
+		}
+		s.reviewWebhook.DispatchCompletion(captain.NewReviewWebhookPayload(board, defectCount))
+	}
+}
+
+// ReviewWebhookRetryInterval is how often queued review webhooks are resent
+const ReviewWebhookRetryInterval = 30 * time.Second
+
+// reviewWebhookRetryLoop retries queued review webhooks on its own ticker.
+// A retry pass can take minutes against a slow receiver, so it stays off
+// the shared background ticker that drives alerts and health checks.
+func (s *Server) reviewWebhookRetryLoop() {
+	ticker := time.NewTicker(ReviewWebhookRetryInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.stopChan:
+			return
+		case <-ticker.C:
+			s.retryReviewWebhooks()
+		}
+	}
+}
+
+// retryReviewWebhooks resends review webhooks queued after failed deliveries
+func (s *Server) retryReviewWebhooks() {
+	if !s.reviewWebhook.Enabled() {
+		return
+	}
+	delivered, err := s.reviewWebhook.RetryPending()
+	if err != nil {
+		s.log("review").Warn("failed to retry review webhooks", "error", err)
+	}
+	if delivered > 0 {
+		s.log("review").Info("delivered queued review webhooks", "count", delivered)
+	}
+}
+
+// publishReviewCompleted emits a review_completed event carrying the board's
+// final report so notification channels can deliver it
+func (s *Server) publishReviewCompleted(board *memory.ReviewBoard) {
+	if s.eventBus == nil || s.memDB == nil {
+		return
+	}
+
+	defectCount := 0
+	if defects, err := s.memDB.GetBoardDefects(board.ID); err == nil {
+		defectCount = len(defects)
+	} else {
+		s.log("review").Warn("failed to count board defects", "board_id", board.ID, "error", err)
+	}
+
+	payload := map[string]interface{}{
+		"board_id":     board.ID,
+		"verdict":      board.FinalVerdict,
+		"defect_count": defectCount,
+		"summary":      board.AggregatedFeedback,
+	}
+	if report, err := s.memDB.GenerateReviewReport(board.ID); err == nil {
+		payload["report"] = report
+	} else {
+		s.log("review").Warn("failed to generate review report", "board_id", board.ID, "error", err)
+	}
+
+	s.eventBus.Publish(events.NewEvent(events.EventReviewCompleted, "review_board", "captain", events.PriorityNormal, payload))
+}
+
+// checkAgentHealth scores each live agent and disconnects agents whose
+// process has exited. An exited process scores no heartbeat points; an
+// activity is logged when a running agent's score drops below
+// AgentUnhealthyScore.
 func (s *Server) checkAgentHealth() {
 	state := s.store.GetState()
 
 	for agentID, agent := range state.Agents {
-		if agent.Status != types.StatusDisconnected && agent.PID > 0 {
-			if !s.spawner.IsAgentRunning(agent.PID) {
-				s.store.UpdateAgent(agentID, func(a *types.Agent) {
-					a.Status = types.StatusDisconnected
-				})
+		if agent.Status == types.StatusDisconnected || agent.PID <= 0 {
+			continue
+		}
+
+		running := s.spawner.IsAgentRunning(agent.PID)
+		lastSeen := agent.LastSeen
+		if !running {
+			lastSeen = time.Time{}
+		}
+		wasHealthy := agent.HealthScore == nil || *agent.HealthScore >= AgentUnhealthyScore
+		score, ok := s.agentHealthScore(agentID, lastSeen)
+
+		s.store.UpdateAgent(agentID, func(a *types.Agent) {
+			if ok {
+				a.HealthScore = &score
 			}
+			if !running {
+				a.Status = types.StatusDisconnected
+			}
+		})
+
+		if !running {
+			s.requeueCrashedAgentTasks(agentID)
+			continue
 		}
+		if ok && score < AgentUnhealthyScore && wasHealthy {
+			s.logActivity("agent_unhealthy", fmt.Sprintf("Agent %s health score dropped to %.1f", agentID, score))
+		}
+	}
+}
+
+// agentHealthScore returns an agent's health score, or false when memory.db
+// is unavailable or the score could not be computed
+func (s *Server) agentHealthScore(agentID string, lastSeen time.Time) (float64, bool) {
+	if s.memDB == nil {
+		return 0, false
+	}
+	health, err := s.memDB.ComputeAgentHealthScore(agentID, lastSeen)
+	if err != nil {
+		s.log("health").Warn("failed to compute agent health score", "agent_id", agentID, "error", err)
+		return 0, false
 	}
+	return health.Score, true
 }
 
-// broadcastState sends current state to all WebSocket clients
+// requeueCrashedAgentTasks returns the tasks a crashed agent had claimed
+// from the task queue to pending, and re-queues the captain tasks it was
+// running
+func (s *Server) requeueCrashedAgentTasks(agentID string) {
+	if s.taskQueue != nil {
+		for _, task := range s.taskQueue.GetByAgent(agentID) {
+			if task.Status != tasks.StatusAssigned && task.Status != tasks.StatusInProgress {
+				continue
+			}
+			if err := releaseTask(task); err != nil {
+				s.log("health").Error("failed to release crashed agent task", "agent_id", agentID, "task_id", task.ID, "error", err)
+				continue
+			}
+			if _, err := s.taskQueue.Update(task); err != nil {
+				s.log("health").Error("failed to release crashed agent task", "agent_id", agentID, "task_id", task.ID, "error", err)
+				continue
+			}
+			s.logActivity("task_reassigned", fmt.Sprintf("Task %s from crashed agent %s is back in the queue", task.ID, agentID))
+		}
+	}
+
+	if s.memDB == nil {
+		return
+	}
+	ids, err := s.memDB.GetInFlightCaptainTaskIDs(agentID)
+	if err != nil {
+		s.log("health").Error("failed to look up crashed agent captain tasks", "agent_id", agentID, "error", err)
+		return
+	}
+	for _, id := range ids {
+		s.reassignCrashedAgentTask(agentID, id)
+	}
+}
+
+// releaseTask moves a claimed task back to pending and unassigns it. In
+// progress tasks step back through assigned, as the state machine requires.
+func releaseTask(task *tasks.Task) error {
+	if task.Status == tasks.StatusInProgress {
+		if err := task.TransitionTo(tasks.StatusAssigned); err != nil {
+			return err
+		}
+	}
+	if err := task.TransitionTo(tasks.StatusPending); err != nil {
+		return err
+	}
+	task.AssignedTo = ""
+	task.StartedAt = nil
+	return nil
+}
+
+// reassignCrashedAgentTask re-queues the captain task a crashed agent was
+// running, or escalates it once it has exhausted its retries
+func (s *Server) reassignCrashedAgentTask(agentID, taskID string) {
+	if s.captain == nil || taskID == "" {
+		return
+	}
+	record, err := s.captain.RequeueCrashedTask(agentID, taskID)
+	if err != nil {
+		s.log("health").Error("failed to reassign crashed agent task", "agent_id", agentID, "task_id", taskID, "error", err)
+		return
+	}
+	if record == nil {
+		return
+	}
+	s.logActivity("task_reassigned", fmt.Sprintf("Task %s from crashed agent %s is now %s (retry %d/%d)",
+		taskID, agentID, record.Status, record.RetryCount, captain.MaxTaskRetries))
+}
+
+// broadcastState sends current state to all WebSocket clients. Bursts of
+// calls are coalesced by the state broadcaster into one update.
 func (s *Server) broadcastState() {
+	if s.stateBroadcaster == nil {
+		s.sendState()
+		return
+	}
+	s.stateBroadcaster.Trigger()
+}
+
+// sendState sends the current state to all WebSocket clients immediately
+func (s *Server) sendState() {
 	s.hub.BroadcastState(s.store.GetState())
 }
 
 // getAgentConfig finds agent config by name
 func (s *Server) getAgentConfig(name string) *types.AgentConfig {
+	config := s.teamsConfig()
 	// Check regular agents first
-	for _, cfg := range s.config.Agents {
+	for _, cfg := range config.Agents {
 		if cfg.Name == name {
 			return &cfg
 		}
 	}
 	// Check supervisor config
-	if s.config.Supervisor.Name == name {
-		return &s.config.Supervisor
+	if config.Supervisor.Name == name {
+		return &config.Supervisor
 	}
 	return nil
 }
 
 // getAgentConfigsMap returns all agent configs as a map by name
 func (s *Server) getAgentConfigsMap() map[string]types.AgentConfig {
+	config := s.teamsConfig()
 	configs := make(map[string]types.AgentConfig)
-	for _, cfg := range s.config.Agents {
+	for _, cfg := range config.Agents {
 		configs[cfg.Name] = cfg
 	}
 	// Include supervisor in the map
-	if s.config.Supervisor.Name != "" {
-		configs[s.config.Supervisor.Name] = s.config.Supervisor
+	if config.Supervisor.Name != "" {
+		configs[config.Supervisor.Name] = config.Supervisor
 	}
 	return configs
 }
@@ -715,22 +1143,22 @@ func (s *Server) verifyAgentStatus(agentID string) {
 	// Check if process is still running
 	if agent.PID > 0 && s.spawner.IsAgentRunning(agent.PID) {
 		// Process still alive - keep current status
-		log.Printf("[VERIFY] Agent %s (PID %d) still running", agentID, agent.PID)
+		s.log("verify").Debug("agent still running", "agent_id", agentID, "pid", agent.PID)
 		return
 	}
 
 	// Check last heartbeat time (allow 60 second grace period)
 	if time.Since(agent.LastSeen) < 60*time.Second {
-		log.Printf("[VERIFY] Agent %s last seen %v ago, keeping status", agentID, time.Since(agent.LastSeen))
+		s.log("verify").Debug("agent seen recently, keeping status", "agent_id", agentID, "last_seen_ago", time.Since(agent.LastSeen))
 		return
 	}
 
 	// Process not running and no recent heartbeat - mark as disconnected
-	log.Printf("[VERIFY] Agent %s appears dead (PID %d, last seen %v ago), marking disconnected",
-		agentID, agent.PID, time.Since(agent.LastSeen))
+	s.log("verify").Warn("agent appears dead, marking disconnected",
+		"agent_id", agentID, "pid", agent.PID, "last_seen_ago", time.Since(agent.LastSeen))
 
 	if err := s.atomicAgentUpdate(agentID, "disconnected", ""); err != nil {
-		log.Printf("[VERIFY] Failed to update agent %s status: %v", agentID, err)
+		s.log("verify").Error("failed to update agent status", "agent_id", agentID, "error", err)
 	}
 	s.broadcastState()
 }
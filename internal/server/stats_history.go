@@ -0,0 +1,92 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/CLIAIMONITOR/internal/memory"
+	"github.com/CLIAIMONITOR/internal/types"
+)
+
+// SessionStatsSnapshotInterval is how often session stats are copied to the
+// session_stats_snapshots table
+const SessionStatsSnapshotInterval = 15 * time.Minute
+
+// defaultStatsHistoryWindow is the range returned when ?from= is omitted
+const defaultStatsHistoryWindow = 24 * time.Hour
+
+// snapshotSessionStats records the current session stats for the history API
+func (s *Server) snapshotSessionStats() {
+	if s.memDB == nil {
+		return
+	}
+
+	state := s.store.GetState()
+	active := 0
+	for _, agent := range state.Agents {
+		if agent.Status != types.StatusDisconnected {
+			active++
+		}
+	}
+
+	err := s.memDB.StoreSessionStatsSnapshot(&memory.SessionStatsSnapshot{
+		ActiveAgents:    active,
+		TotalTokensUsed: state.SessionStats.TotalTokensUsed,
+		TotalCost:       state.SessionStats.TotalEstimatedCost,
+		TasksCompleted:  state.SessionStats.CompletedTasks,
+		AlertsFired:     len(state.Alerts),
+	})
+	if err != nil {
+		s.log("stats").Warn("failed to store session stats snapshot", "error", err)
+	}
+}
+
+// handleGetStatsHistory returns session stats snapshots between the RFC 3339
+// times ?from= (default 24h ago) and ?to= (default now), keeping the latest
+// snapshot per ?bucket= (Go duration, default 1h)
+func (s *Server) handleGetStatsHistory(w http.ResponseWriter, r *http.Request) {
+	if s.memDB == nil {
+		s.respondAPIError(w, ErrMemoryDBUnavailable)
+		return
+	}
+
+	bucket := time.Hour
+	if raw := r.URL.Query().Get("bucket"); raw != "" {
+		parsed, err := time.ParseDuration(raw)
+		if err != nil || parsed < time.Minute {
+			s.respondAPIError(w, ErrInvalidParameter.WithMessage("bucket must be a duration of at least 1m (e.g. 15m, 1h, 24h)"))
+			return
+		}
+		bucket = parsed
+	}
+
+	to := time.Now()
+	from := time.Time{}
+	for name, dst := range map[string]*time.Time{"from": &from, "to": &to} {
+		raw := r.URL.Query().Get(name)
+		if raw == "" {
+			continue
+		}
+		parsed, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			s.respondAPIError(w, ErrInvalidParameter.WithMessage(fmt.Sprintf("%s must be an RFC 3339 timestamp", name)))
+			return
+		}
+		*dst = parsed
+	}
+	if from.IsZero() {
+		from = to.Add(-defaultStatsHistoryWindow)
+	}
+	if to.Before(from) {
+		s.respondAPIError(w, ErrInvalidParameter.WithMessage("to must not be before from"))
+		return
+	}
+
+	history, err := s.memDB.GetSessionStatsHistory(from, to, bucket)
+	if err != nil {
+		s.respondAPIError(w, ErrInternal.WithMessage(fmt.Sprintf("Failed to get stats history: %v", err)))
+		return
+	}
+	s.respondJSON(w, history)
+}
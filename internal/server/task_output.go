@@ -0,0 +1,89 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/CLIAIMONITOR/internal/captain"
+	"github.com/CLIAIMONITOR/internal/events"
+	"github.com/gorilla/mux"
+)
+
+// handleStreamTaskOutput publishes a chunk of a Captain task's output, for
+// runners whose output Captain does not capture itself (e.g. terminal agents)
+func (s *Server) handleStreamTaskOutput(w http.ResponseWriter, r *http.Request) {
+	taskID := mux.Vars(r)["id"]
+
+	if s.captain == nil || s.eventBus == nil {
+		s.respondAPIError(w, ErrServiceUnavailable.WithMessage("Captain output streaming not available"))
+		return
+	}
+
+	var req struct {
+		AgentID string `json:"agent_id"`
+		Chunk   string `json:"chunk"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		s.respondAPIError(w, ErrInvalidRequestBody)
+		return
+	}
+	if req.Chunk == "" {
+		s.respondAPIError(w, ErrInvalidRequestBody.WithMessage("chunk is required"))
+		return
+	}
+
+	s.captain.PublishOutputChunk(taskID, req.AgentID, req.Chunk)
+	s.respondJSON(w, map[string]interface{}{
+		"task_id": taskID,
+		"bytes":   len(req.Chunk),
+	})
+}
+
+// handleTaskLogsStream streams a Captain task's live output as Server-Sent
+// Events, one subagent_output_chunk payload per event, until the client
+// disconnects
+func (s *Server) handleTaskLogsStream(w http.ResponseWriter, r *http.Request) {
+	taskID := mux.Vars(r)["id"]
+
+	if s.eventBus == nil {
+		s.respondAPIError(w, ErrServiceUnavailable.WithMessage("Event bus not available"))
+		return
+	}
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		s.respondAPIError(w, ErrInternal.WithMessage("Streaming not supported"))
+		return
+	}
+
+	ch := s.eventBus.Subscribe(captain.OutputStreamTarget, []events.EventType{events.EventSubagentOutputChunk})
+	defer s.eventBus.Unsubscribe(captain.OutputStreamTarget, ch)
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case event, ok := <-ch:
+			if !ok {
+				return
+			}
+			if event.Payload["task_id"] != taskID {
+				continue
+			}
+			payload, err := json.Marshal(event.Payload)
+			if err != nil {
+				continue
+			}
+			if _, err := fmt.Fprintf(w, "data: %s\n\n", payload); err != nil {
+				return
+			}
+			flusher.Flush()
+		}
+	}
+}
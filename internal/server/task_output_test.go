@@ -0,0 +1,77 @@
+package server
+
+import (
+	"bufio"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/CLIAIMONITOR/internal/captain"
+	"github.com/CLIAIMONITOR/internal/events"
+	"github.com/gorilla/mux"
+)
+
+func TestTaskLogsStream(t *testing.T) {
+	s, memDB := newBackupTestServer(t)
+	s.eventBus = events.NewBus(nil)
+	s.captain = captain.NewCaptain(s.basePath, nil, memDB, nil)
+	s.captain.SetEventBus(s.eventBus)
+
+	router := mux.NewRouter()
+	router.HandleFunc("/api/captain/tasks/{id}/logs", s.handleTaskLogsStream).Methods("GET")
+	router.HandleFunc("/api/captain/tasks/{id}/stream-output", s.handleStreamTaskOutput).Methods("POST")
+	ts := httptest.NewServer(router)
+	defer ts.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	req, _ := http.NewRequestWithContext(ctx, "GET", ts.URL+"/api/captain/tasks/task-1/logs", nil)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("GET logs failed: %v", err)
+	}
+	defer resp.Body.Close()
+	if ct := resp.Header.Get("Content-Type"); ct != "text/event-stream" {
+		t.Fatalf("Content-Type = %q", ct)
+	}
+
+	post := func(taskID, body string) int {
+		resp, err := http.Post(ts.URL+"/api/captain/tasks/"+taskID+"/stream-output", "application/json", strings.NewReader(body))
+		if err != nil {
+			t.Fatalf("POST stream-output failed: %v", err)
+		}
+		resp.Body.Close()
+		return resp.StatusCode
+	}
+
+	if code := post("task-1", `{"agent_id": "team-sntgreen001"}`); code != http.StatusBadRequest {
+		t.Errorf("empty chunk status = %d, want 400", code)
+	}
+	if code := post("task-2", `{"chunk": "other task"}`); code != http.StatusOK {
+		t.Errorf("status = %d, want 200", code)
+	}
+	if code := post("task-1", `{"agent_id": "team-sntgreen001", "chunk": "hello"}`); code != http.StatusOK {
+		t.Errorf("status = %d, want 200", code)
+	}
+
+	line, err := bufio.NewReader(resp.Body).ReadString('\n')
+	if err != nil {
+		t.Fatalf("Failed to read event: %v", err)
+	}
+	if !strings.HasPrefix(line, "data: ") || !strings.Contains(line, `"chunk":"hello"`) || !strings.Contains(line, `"task_id":"task-1"`) {
+		t.Errorf("Unexpected event line %q", line)
+	}
+}
+
+func TestStreamTaskOutputWithoutCaptain(t *testing.T) {
+	s, _ := newBackupTestServer(t)
+	w := httptest.NewRecorder()
+	r := mux.SetURLVars(httptest.NewRequest("POST", "/api/captain/tasks/task-1/stream-output", strings.NewReader(`{"chunk": "x"}`)), map[string]string{"id": "task-1"})
+	s.handleStreamTaskOutput(w, r)
+	if w.Code != http.StatusServiceUnavailable {
+		t.Errorf("status = %d, want 503", w.Code)
+	}
+}
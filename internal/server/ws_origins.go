@@ -0,0 +1,174 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/CLIAIMONITOR/internal/memory"
+)
+
+// WSAllowedOriginsContextKey is the captain_context key holding the allowed
+// WebSocket origins as a JSON array, so they can be changed without a restart
+const WSAllowedOriginsContextKey = "ws_allowed_origins"
+
+// wsOriginsCacheTTL is how long origins read from the memory DB are reused
+// before checkWebSocketOrigin reads them again
+const wsOriginsCacheTTL = 60 * time.Second
+
+// wsOriginCache is a read-through cache of the stored allowed origins.
+// Without a memory DB the startup allowedOrigins are used.
+type wsOriginCache struct {
+	mu       sync.Mutex
+	memDB    memory.MemoryDB
+	origins  []string
+	loadedAt time.Time
+}
+
+var wsOrigins = &wsOriginCache{}
+
+// setStore points the cache at a memory DB, seeding the stored origins from
+// allowedOrigins (defaults plus CLIAIMONITOR_ALLOWED_ORIGINS) when none exist
+func (c *wsOriginCache) setStore(memDB memory.MemoryDB) error {
+	c.mu.Lock()
+	c.memDB = memDB
+	c.origins = nil
+	c.loadedAt = time.Time{}
+	c.mu.Unlock()
+
+	if memDB == nil {
+		return nil
+	}
+	stored, err := memDB.GetContext(WSAllowedOriginsContextKey)
+	if err != nil || stored != nil {
+		return err
+	}
+	return c.store(allowedOrigins)
+}
+
+// get returns the current allowed origins, reloading them once the cache
+// is older than wsOriginsCacheTTL. A failed reload keeps the previous list.
+func (c *wsOriginCache) get() []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.memDB == nil {
+		return allowedOrigins
+	}
+	if c.origins != nil && time.Since(c.loadedAt) < wsOriginsCacheTTL {
+		return c.origins
+	}
+
+	origins, err := loadWSOrigins(c.memDB)
+	if err != nil {
+		if c.origins != nil {
+			return c.origins
+		}
+		return allowedOrigins
+	}
+	c.origins = origins
+	c.loadedAt = time.Now()
+	return origins
+}
+
+// store saves origins to the memory DB and replaces the cached list
+func (c *wsOriginCache) store(origins []string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.memDB == nil {
+		return fmt.Errorf("memory database not configured")
+	}
+	data, err := json.Marshal(origins)
+	if err != nil {
+		return fmt.Errorf("failed to encode allowed origins: %w", err)
+	}
+	if err := c.memDB.SetContext(WSAllowedOriginsContextKey, string(data), 5, 0); err != nil {
+		return err
+	}
+	c.origins = origins
+	c.loadedAt = time.Now()
+	return nil
+}
+
+// loadWSOrigins reads the stored origins, falling back to allowedOrigins when
+// none are stored
+func loadWSOrigins(memDB memory.MemoryDB) ([]string, error) {
+	stored, err := memDB.GetContext(WSAllowedOriginsContextKey)
+	if err != nil {
+		return nil, err
+	}
+	if stored == nil {
+		return allowedOrigins, nil
+	}
+	var origins []string
+	if err := json.Unmarshal([]byte(stored.Value), &origins); err != nil {
+		return nil, fmt.Errorf("invalid %s context: %w", WSAllowedOriginsContextKey, err)
+	}
+	return origins, nil
+}
+
+// normalizeWSOrigins trims and de-duplicates origins, rejecting any that are
+// not an http(s) scheme and host
+func normalizeWSOrigins(origins []string) ([]string, error) {
+	seen := make(map[string]bool, len(origins))
+	result := make([]string, 0, len(origins))
+	for _, origin := range origins {
+		origin = strings.TrimRight(strings.TrimSpace(origin), "/")
+		if origin == "" || seen[origin] {
+			continue
+		}
+		u, err := url.Parse(origin)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" || u.Path != "" {
+			return nil, fmt.Errorf("invalid origin %q: want scheme://host[:port]", origin)
+		}
+		seen[origin] = true
+		result = append(result, origin)
+	}
+	return result, nil
+}
+
+// handleGetWSOrigins returns the WebSocket origins currently allowed in
+// addition to localhost
+func (s *Server) handleGetWSOrigins(w http.ResponseWriter, r *http.Request) {
+	s.respondJSON(w, map[string]interface{}{
+		"origins": wsOrigins.get(),
+	})
+}
+
+// handlePutWSOrigins replaces the allowed WebSocket origins. The change
+// applies to new connections immediately.
+func (s *Server) handlePutWSOrigins(w http.ResponseWriter, r *http.Request) {
+	if s.memDB == nil {
+		s.respondAPIError(w, ErrMemoryDBUnavailable)
+		return
+	}
+
+	var req struct {
+		Origins []string `json:"origins"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		s.respondAPIError(w, ErrInvalidRequestBody)
+		return
+	}
+	origins, err := normalizeWSOrigins(req.Origins)
+	if err != nil {
+		s.respondAPIError(w, ErrInvalidOrigin.WithMessage(err.Error()))
+		return
+	}
+
+	if err := wsOrigins.store(origins); err != nil {
+		s.respondAPIError(w, ErrInternal.WithMessage(fmt.Sprintf("Failed to save allowed origins: %v", err)))
+		return
+	}
+	s.log("server").Info("allowed WebSocket origins updated", "count", len(origins))
+
+	s.respondJSON(w, map[string]interface{}{
+		"success": true,
+		"origins": origins,
+	})
+}
@@ -0,0 +1,67 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/CLIAIMONITOR/internal/memory"
+)
+
+func TestWSOriginCache(t *testing.T) {
+	memDB, err := memory.NewMemoryDB(filepath.Join(t.TempDir(), "memory.db"))
+	if err != nil {
+		t.Fatalf("NewMemoryDB failed: %v", err)
+	}
+	defer memDB.Close()
+
+	original := allowedOrigins
+	allowedOrigins = []string{"https://seed.example.com"}
+	defer func() {
+		allowedOrigins = original
+		wsOrigins.setStore(nil)
+	}()
+
+	// An empty store is seeded from the environment origins
+	if err := wsOrigins.setStore(memDB); err != nil {
+		t.Fatalf("setStore failed: %v", err)
+	}
+	if got := wsOrigins.get(); len(got) != 1 || got[0] != "https://seed.example.com" {
+		t.Fatalf("seeded origins = %v", got)
+	}
+
+	// Stored origins win over the environment on later starts
+	if err := wsOrigins.store([]string{"https://dashboard.example.com"}); err != nil {
+		t.Fatalf("store failed: %v", err)
+	}
+	if err := wsOrigins.setStore(memDB); err != nil {
+		t.Fatalf("setStore failed: %v", err)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/ws", nil)
+	req.Header.Set("Origin", "https://dashboard.example.com")
+	if !checkWebSocketOrigin(req) {
+		t.Error("stored origin should be allowed")
+	}
+	req.Header.Set("Origin", "https://seed.example.com")
+	if checkWebSocketOrigin(req) {
+		t.Error("replaced seed origin should be rejected")
+	}
+}
+
+func TestNormalizeWSOrigins(t *testing.T) {
+	got, err := normalizeWSOrigins([]string{" https://a.com/ ", "https://a.com", "", "http://b.local:8080"})
+	if err != nil {
+		t.Fatalf("normalizeWSOrigins failed: %v", err)
+	}
+	if len(got) != 2 || got[0] != "https://a.com" || got[1] != "http://b.local:8080" {
+		t.Errorf("normalizeWSOrigins = %v", got)
+	}
+
+	for _, bad := range []string{"not-a-url", "ftp://a.com", "https://a.com/path", "https://"} {
+		if _, err := normalizeWSOrigins([]string{bad}); err == nil {
+			t.Errorf("expected error for %q", bad)
+		}
+	}
+}
@@ -3,11 +3,11 @@ package supervisor
 import (
 	"context"
 	"fmt"
-	"log"
 	"sync"
 	"time"
 
 	"github.com/CLIAIMONITOR/internal/agents"
+	"github.com/CLIAIMONITOR/internal/logger"
 	"github.com/CLIAIMONITOR/internal/memory"
 	"github.com/CLIAIMONITOR/internal/types"
 )
@@ -164,7 +164,7 @@ func (d *StandardDispatcher) spawnAgents(ctx context.Context, plan *ActionPlan,
 	// For now, use current working directory as default project path
 	projectPath := state.projectPath
 	if projectPath == "" {
-		log.Printf("[DISPATCHER] Warning: No project path specified, defaulting to current directory")
+		logger.For("dispatcher").Warn("no project path specified, defaulting to current directory")
 		// Fall back to current working directory if not provided in plan
 		projectPath = "."
 		// In production: would extract from plan.ReportID context or memory DB repo lookup
@@ -298,7 +298,7 @@ func (d *StandardDispatcher) AbortDispatch(ctx context.Context, dispatchID strin
 	// Stop all agents
 	for agentID := range state.agents {
 		if err := d.spawner.StopAgent(agentID); err != nil {
-			log.Printf("[DISPATCHER] Failed to stop agent %s: %v", agentID, err)
+			logger.For("dispatcher").Error("failed to stop agent", "agent_id", agentID, "error", err)
 		}
 	}
 
@@ -0,0 +1,284 @@
+package supervisor
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// DefaultHeaderAliases maps lower-cased markdown table headers to the
+// finding field they fill. Recognized fields are id, title, description,
+// severity, type, location and recommendation.
+var DefaultHeaderAliases = map[string]string{
+	"id":             "id",
+	"#":              "id",
+	"finding id":     "id",
+	"issue":          "title",
+	"title":          "title",
+	"finding":        "title",
+	"name":           "title",
+	"description":    "description",
+	"details":        "description",
+	"severity":       "severity",
+	"priority":       "severity",
+	"risk":           "severity",
+	"type":           "type",
+	"category":       "type",
+	"location":       "location",
+	"file":           "location",
+	"path":           "location",
+	"recommendation": "recommendation",
+	"fix":            "recommendation",
+	"remediation":    "recommendation",
+	"action":         "recommendation",
+}
+
+// tableState tracks where ParseMarkdownTable is within the input
+type tableState int
+
+const (
+	stateText   tableState = iota // outside any table
+	stateHeader                   // saw a candidate header row
+	stateBody                     // inside a findings table
+	stateSkip                     // inside a table without finding columns
+)
+
+// ParseMarkdownTable extracts findings from pipe-separated markdown tables.
+// Columns are matched to finding fields through the parser's header aliases;
+// tables without a title, description or severity column are ignored.
+func (p *StandardReportParser) ParseMarkdownTable(data []byte) (*ReconReport, error) {
+	aliases := p.HeaderAliases
+	if aliases == nil {
+		aliases = DefaultHeaderAliases
+	}
+
+	report := &ReconReport{
+		Timestamp: time.Now(),
+		Findings: &ReconFindings{
+			Critical: make([]*ReconFinding, 0),
+			High:     make([]*ReconFinding, 0),
+			Medium:   make([]*ReconFinding, 0),
+			Low:      make([]*ReconFinding, 0),
+		},
+		Summary: &ReconSummary{},
+		Recommendations: &ReconRecommendations{
+			Immediate: make([]string, 0),
+			ShortTerm: make([]string, 0),
+			LongTerm:  make([]string, 0),
+		},
+	}
+	report.ID = fmt.Sprintf("recon-%d", report.Timestamp.Unix())
+
+	state := stateText
+	var header []string
+	var columns []string // finding field per column, "" if unmapped
+	tables := 0
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+
+		switch state {
+		case stateHeader:
+			if isTableSeparator(line) && len(splitTableRow(line)) == len(header) {
+				columns = mapTableColumns(header, aliases)
+				if columns != nil {
+					state = stateBody
+					tables++
+				} else {
+					state = stateSkip
+				}
+				continue
+			}
+		case stateBody:
+			if isTableRow(line) {
+				if finding, severity := tableFinding(splitTableRow(line), columns); finding != nil {
+					addFinding(report.Findings, finding, severity)
+				}
+				continue
+			}
+		case stateSkip:
+			if isTableRow(line) {
+				continue
+			}
+		}
+
+		// Any other line may start a new table
+		if isTableRow(line) && !isTableSeparator(line) {
+			header = splitTableRow(line)
+			state = stateHeader
+		} else {
+			state = stateText
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read markdown: %w", err)
+	}
+	if tables == 0 {
+		return nil, fmt.Errorf("no markdown findings table found")
+	}
+
+	return report, nil
+}
+
+// hasMarkdownTable reports whether data contains a header row followed by a
+// separator row, the minimum for a markdown table
+func hasMarkdownTable(data []byte) bool {
+	prevRow := false
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if prevRow && isTableSeparator(line) {
+			return true
+		}
+		prevRow = isTableRow(line) && !isTableSeparator(line)
+	}
+	return false
+}
+
+// isTableRow reports whether line looks like a pipe-separated table row
+func isTableRow(line string) bool {
+	return strings.HasPrefix(line, "|") && len(line) > 1
+}
+
+// isTableSeparator reports whether line is a header separator such as
+// |---|:---:|
+func isTableSeparator(line string) bool {
+	if !isTableRow(line) {
+		return false
+	}
+	for _, cell := range splitTableRow(line) {
+		cell = strings.Trim(cell, ":")
+		if cell == "" || strings.Trim(cell, "-") != "" {
+			return false
+		}
+	}
+	return true
+}
+
+// splitTableRow returns the trimmed cells of a table row. Escaped pipes (\|)
+// stay part of the cell.
+func splitTableRow(line string) []string {
+	line = strings.TrimPrefix(line, "|")
+	if strings.HasSuffix(line, "|") && !strings.HasSuffix(line, `\|`) {
+		line = strings.TrimSuffix(line, "|")
+	}
+
+	var cells []string
+	var cell strings.Builder
+	for i := 0; i < len(line); i++ {
+		switch {
+		case line[i] == '\\' && i+1 < len(line) && line[i+1] == '|':
+			cell.WriteByte('|')
+			i++
+		case line[i] == '|':
+			cells = append(cells, strings.TrimSpace(cell.String()))
+			cell.Reset()
+		default:
+			cell.WriteByte(line[i])
+		}
+	}
+	return append(cells, strings.TrimSpace(cell.String()))
+}
+
+// mapTableColumns resolves each header to a finding field. Returns nil if
+// the table has no column describing a finding.
+func mapTableColumns(header []string, aliases map[string]string) []string {
+	columns := make([]string, len(header))
+	useful := false
+	for i, h := range header {
+		field := aliases[strings.ToLower(stripMarkdown(h))]
+		columns[i] = field
+		if field == "title" || field == "description" || field == "severity" {
+			useful = true
+		}
+	}
+	if !useful {
+		return nil
+	}
+	return columns
+}
+
+// tableFinding builds a finding from one table row and returns it with its
+// severity. Rows without any content are skipped.
+func tableFinding(cells, columns []string) (*ReconFinding, string) {
+	finding := &ReconFinding{}
+	var title, description, severity string
+	for i, field := range columns {
+		if i >= len(cells) {
+			break
+		}
+		value := stripMarkdown(cells[i])
+		switch field {
+		case "id":
+			finding.ID = value
+		case "title":
+			title = value
+		case "description":
+			description = value
+		case "severity":
+			severity = value
+		case "type":
+			finding.Type = strings.ToLower(value)
+		case "location":
+			finding.Location = value
+		case "recommendation":
+			finding.Recommendation = value
+		}
+	}
+
+	switch {
+	case title != "" && description != "":
+		finding.Description = title + ": " + description
+	case title != "":
+		finding.Description = title
+	default:
+		finding.Description = description
+	}
+	if finding.Description == "" && finding.Location == "" && finding.Recommendation == "" {
+		return nil, ""
+	}
+	if finding.ID == "" {
+		finding.ID = fmt.Sprintf("finding-%d", time.Now().UnixNano())
+	}
+	return finding, normalizeSeverity(severity)
+}
+
+// normalizeSeverity maps free-form severity text such as "🔴 **High**" to
+// critical, high, medium or low. Unrecognized values count as medium.
+func normalizeSeverity(s string) string {
+	s = strings.ToLower(s)
+	switch {
+	case strings.Contains(s, "critical"), strings.Contains(s, "blocker"):
+		return "critical"
+	case strings.Contains(s, "high"), strings.Contains(s, "major"):
+		return "high"
+	case strings.Contains(s, "low"), strings.Contains(s, "minor"), strings.Contains(s, "info"):
+		return "low"
+	default:
+		return "medium"
+	}
+}
+
+// addFinding files a finding under its severity
+func addFinding(findings *ReconFindings, finding *ReconFinding, severity string) {
+	switch severity {
+	case "critical":
+		findings.Critical = append(findings.Critical, finding)
+	case "high":
+		findings.High = append(findings.High, finding)
+	case "low":
+		findings.Low = append(findings.Low, finding)
+	default:
+		findings.Medium = append(findings.Medium, finding)
+	}
+}
+
+// stripMarkdown removes emphasis and code markers around a cell value
+func stripMarkdown(s string) string {
+	return strings.TrimSpace(strings.Trim(strings.TrimSpace(s), "*_`"))
+}
@@ -0,0 +1,139 @@
+package supervisor
+
+import (
+	"strings"
+	"testing"
+)
+
+const markdownReport = `## Findings
+
+The scan found the following issues:
+
+| ID | Issue | Severity | Location | Fix |
+|----|-------|:--------:|----------|-----|
+| VULN-001 | SQL injection in login | 🔴 **Critical** | ` + "`src/auth/login.go:45`" + ` | Use parameterized queries |
+| ARCH-002 | God object | High | internal/server/handlers.go | Split handlers |
+| DEP-003 | Outdated yaml \| json libs | moderate | go.mod | Upgrade |
+| PROC-004 | Missing CI lint | info | .github/ | Add golangci-lint |
+
+| Metric | Value |
+|--------|-------|
+| Files | 120 |
+`
+
+func TestParseMarkdownTable(t *testing.T) {
+	parser := &StandardReportParser{}
+
+	report, err := parser.ParseMarkdownTable([]byte(markdownReport))
+	if err != nil {
+		t.Fatalf("ParseMarkdownTable() error = %v", err)
+	}
+
+	f := report.Findings
+	if len(f.Critical) != 1 || len(f.High) != 1 || len(f.Medium) != 1 || len(f.Low) != 1 {
+		t.Fatalf("unexpected finding counts: critical=%d high=%d medium=%d low=%d",
+			len(f.Critical), len(f.High), len(f.Medium), len(f.Low))
+	}
+
+	critical := f.Critical[0]
+	if critical.ID != "VULN-001" || critical.Description != "SQL injection in login" {
+		t.Errorf("unexpected critical finding: %+v", critical)
+	}
+	if critical.Location != "src/auth/login.go:45" || critical.Recommendation != "Use parameterized queries" {
+		t.Errorf("unexpected critical location/fix: %+v", critical)
+	}
+	if f.Medium[0].Description != "Outdated yaml | json libs" {
+		t.Errorf("escaped pipe not preserved: %q", f.Medium[0].Description)
+	}
+	if report.Summary == nil || report.Recommendations == nil {
+		t.Error("expected empty summary and recommendations")
+	}
+}
+
+func TestParseMarkdownTableCustomAliases(t *testing.T) {
+	parser := &StandardReportParser{HeaderAliases: map[string]string{
+		"problème": "title",
+		"gravité":  "severity",
+	}}
+
+	input := "| Problème | Gravité |\n|---|---|\n| Fuite mémoire | high |\n"
+	report, err := parser.ParseMarkdownTable([]byte(input))
+	if err != nil {
+		t.Fatalf("ParseMarkdownTable() error = %v", err)
+	}
+	if len(report.Findings.High) != 1 || report.Findings.High[0].Description != "Fuite mémoire" {
+		t.Errorf("unexpected findings: %+v", report.Findings)
+	}
+}
+
+func TestParseMarkdownTableNoFindings(t *testing.T) {
+	parser := &StandardReportParser{}
+
+	inputs := []string{
+		"no tables here",
+		"| Metric | Value |\n|---|---|\n| Files | 3 |\n",
+		"| Issue | Severity |\nnot a separator\n| a | b |\n",
+	}
+	for _, input := range inputs {
+		if _, err := parser.ParseMarkdownTable([]byte(input)); err == nil {
+			t.Errorf("expected error for %q", input)
+		}
+	}
+}
+
+func TestParseYAMLDetectsMarkdownTable(t *testing.T) {
+	parser := NewReportParser()
+
+	report, err := parser.ParseYAML([]byte(markdownReport))
+	if err != nil {
+		t.Fatalf("ParseYAML() error = %v", err)
+	}
+	if len(report.Findings.Critical) != 1 {
+		t.Errorf("expected markdown findings, got %+v", report.Findings)
+	}
+}
+
+func TestNormalizeSeverity(t *testing.T) {
+	tests := map[string]string{
+		"CRITICAL":    "critical",
+		"🟠 High":      "high",
+		"Medium":      "medium",
+		"minor":       "low",
+		"informative": "low",
+		"":            "medium",
+	}
+	for in, want := range tests {
+		if got := normalizeSeverity(in); got != want {
+			t.Errorf("normalizeSeverity(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func FuzzParseMarkdownTable(f *testing.F) {
+	f.Add([]byte(markdownReport))
+	f.Add([]byte("| Issue | Severity |\n|---|---|\n| x | low |\n"))
+	f.Add([]byte("|\n|-|\n|"))
+	f.Add([]byte("| a \\| b | Severity |\n|:-:|--|\n| \\|\\| | |\n"))
+	f.Add([]byte("snake_report:\n  agent_id: x\n"))
+
+	parser := &StandardReportParser{}
+	f.Fuzz(func(t *testing.T, data []byte) {
+		report, err := parser.ParseMarkdownTable(data)
+		if err != nil {
+			return
+		}
+		if report.Findings == nil || report.Summary == nil || report.Recommendations == nil {
+			t.Fatalf("incomplete report: %+v", report)
+		}
+		for _, group := range [][]*ReconFinding{report.Findings.Critical, report.Findings.High, report.Findings.Medium, report.Findings.Low} {
+			for _, finding := range group {
+				if finding == nil || finding.ID == "" {
+					t.Fatalf("invalid finding: %+v", finding)
+				}
+				if strings.Contains(finding.Description, "\n") {
+					t.Fatalf("finding spans lines: %q", finding.Description)
+				}
+			}
+		}
+	})
+}
@@ -1,6 +1,7 @@
 package supervisor
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
 	"time"
@@ -18,18 +19,30 @@ type ReportParser interface {
 
 	// Parse from MCP tool call parameters
 	ParseMCPReport(params map[string]interface{}) (*ReconReport, error)
+
+	// Parse findings from markdown tables
+	ParseMarkdownTable(data []byte) (*ReconReport, error)
 }
 
 // StandardReportParser implements report parsing
-type StandardReportParser struct{}
+type StandardReportParser struct {
+	// HeaderAliases maps lower-cased markdown table headers to finding
+	// fields; nil uses DefaultHeaderAliases
+	HeaderAliases map[string]string
+}
 
 // NewReportParser creates a new report parser
 func NewReportParser() ReportParser {
 	return &StandardReportParser{}
 }
 
-// ParseYAML parses a YAML reconnaissance report
+// ParseYAML parses a YAML reconnaissance report. Output that is a markdown
+// table rather than a snake_report document is handed to ParseMarkdownTable.
 func (p *StandardReportParser) ParseYAML(data []byte) (*ReconReport, error) {
+	if !bytes.Contains(data, []byte("snake_report:")) && hasMarkdownTable(data) {
+		return p.ParseMarkdownTable(data)
+	}
+
 	var raw map[string]interface{}
 	if err := yaml.Unmarshal(data, &raw); err != nil {
 		return nil, fmt.Errorf("failed to parse YAML: %w", err)
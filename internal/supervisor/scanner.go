@@ -3,11 +3,11 @@ package supervisor
 import (
 	"crypto/sha256"
 	"fmt"
-	"log"
 	"os"
 	"path/filepath"
 	"strings"
 
+	"github.com/CLIAIMONITOR/internal/logger"
 	"github.com/CLIAIMONITOR/internal/memory"
 	"gopkg.in/yaml.v3"
 )
@@ -111,7 +111,7 @@ func (s *Scanner) ScanForWorkflows(repoID string) (*ScanResult, error) {
 		for _, planFile := range planFiles {
 			tasks, err := s.ParseWorkflowYAML(repoID, planFile.FilePath, planFile.Content)
 			if err != nil {
-				log.Printf("[SCANNER] Skipping plan file %s: not a valid task list (%v)", planFile.FilePath, err)
+				logger.For("scanner").Debug("skipping plan file: not a valid task list", "path", planFile.FilePath, "error", err)
 				continue
 			}
 			result.DiscoveredTasks = append(result.DiscoveredTasks, tasks...)
@@ -272,7 +272,7 @@ func (s *Scanner) scanPlanFiles(dir string) ([]*PlanFile, error) {
 		path := filepath.Join(dir, name)
 		content, err := os.ReadFile(path)
 		if err != nil {
-			log.Printf("[SCANNER] Failed to read file %s: %v", path, err)
+			logger.For("scanner").Warn("failed to read file", "path", path, "error", err)
 			continue
 		}
 
@@ -307,7 +307,7 @@ func (s *Scanner) scanWorkflowFiles(dir string) ([]*WorkflowFile, error) {
 		path := filepath.Join(dir, name)
 		content, err := os.ReadFile(path)
 		if err != nil {
-			log.Printf("[SCANNER] Failed to read file %s: %v", path, err)
+			logger.For("scanner").Warn("failed to read file", "path", path, "error", err)
 			continue
 		}
 
@@ -0,0 +1,72 @@
+// internal/tasks/cursor.go
+package tasks
+
+import (
+	"encoding/base64"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Cursor marks a position in a newest-first task listing. Pages continue
+// with tasks strictly older than (CreatedAt, ID).
+type Cursor struct {
+	CreatedAt time.Time
+	ID        string
+}
+
+// EncodeCursor returns the opaque page token that resumes after task
+func EncodeCursor(task *Task) string {
+	raw := task.CreatedAt.UTC().Format(time.RFC3339Nano) + "|" + task.ID
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// DecodeCursor parses a token produced by EncodeCursor
+func DecodeCursor(token string) (*Cursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cursor: %w", err)
+	}
+	createdAt, id, ok := strings.Cut(string(raw), "|")
+	if !ok || id == "" {
+		return nil, fmt.Errorf("invalid cursor: malformed token")
+	}
+	t, err := time.Parse(time.RFC3339Nano, createdAt)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cursor: %w", err)
+	}
+	return &Cursor{CreatedAt: t, ID: id}, nil
+}
+
+// before reports whether task sorts after the cursor in newest-first order,
+// i.e. (created_at, id) < (cursor_time, cursor_id)
+func (c *Cursor) before(task *Task) bool {
+	if !task.CreatedAt.Equal(c.CreatedAt) {
+		return task.CreatedAt.Before(c.CreatedAt)
+	}
+	return task.ID < c.ID
+}
+
+// PageByCreated orders tasks newest first (ties broken by ID descending) and
+// returns up to limit tasks after cursor. nextCursor is empty on the last page.
+func PageByCreated(list []*Task, cursor *Cursor, limit int) (page []*Task, nextCursor string) {
+	sorted := make([]*Task, 0, len(list))
+	for _, t := range list {
+		if cursor == nil || cursor.before(t) {
+			sorted = append(sorted, t)
+		}
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		if !sorted[i].CreatedAt.Equal(sorted[j].CreatedAt) {
+			return sorted[i].CreatedAt.After(sorted[j].CreatedAt)
+		}
+		return sorted[i].ID > sorted[j].ID
+	})
+
+	if len(sorted) <= limit {
+		return sorted, ""
+	}
+	page = sorted[:limit]
+	return page, EncodeCursor(page[len(page)-1])
+}
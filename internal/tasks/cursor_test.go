@@ -0,0 +1,62 @@
+// internal/tasks/cursor_test.go
+package tasks
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCursorRoundTrip(t *testing.T) {
+	task := NewTask("Task", "Desc", 1)
+	cursor, err := DecodeCursor(EncodeCursor(task))
+	if err != nil {
+		t.Fatalf("DecodeCursor failed: %v", err)
+	}
+	if cursor.ID != task.ID || !cursor.CreatedAt.Equal(task.CreatedAt) {
+		t.Errorf("cursor = %+v, want ID %s at %v", cursor, task.ID, task.CreatedAt)
+	}
+
+	for _, bad := range []string{"!!!", "bm8tc2VwYXJhdG9y", "eHx5"} {
+		if _, err := DecodeCursor(bad); err == nil {
+			t.Errorf("expected error decoding %q", bad)
+		}
+	}
+}
+
+func TestPageByCreated(t *testing.T) {
+	base := time.Now()
+	var list []*Task
+	for i, id := range []string{"a", "b", "c", "d"} {
+		task := NewTask("Task "+id, "Desc", 1)
+		task.ID = id
+		task.CreatedAt = base.Add(time.Duration(i/2) * time.Second) // a,b share a timestamp, as do c,d
+		list = append(list, task)
+	}
+
+	var got []string
+	var cursor *Cursor
+	for {
+		page, next := PageByCreated(list, cursor, 3)
+		for _, task := range page {
+			got = append(got, task.ID)
+		}
+		if next == "" {
+			break
+		}
+		c, err := DecodeCursor(next)
+		if err != nil {
+			t.Fatalf("DecodeCursor failed: %v", err)
+		}
+		cursor = c
+	}
+
+	want := []string{"d", "c", "b", "a"}
+	if len(got) != len(want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("got %v, want %v", got, want)
+		}
+	}
+}
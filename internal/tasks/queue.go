@@ -21,14 +21,16 @@ func NewQueue() *Queue {
 	}
 }
 
-// Add inserts a task into the queue, maintaining priority order
-func (q *Queue) Add(task *Task) {
+// Add inserts a task into the queue, maintaining priority order. It never
+// fails.
+func (q *Queue) Add(task *Task) error {
 	q.mu.Lock()
 	defer q.mu.Unlock()
 
 	q.tasks = append(q.tasks, task)
 	q.index[task.ID] = task
 	q.sortLocked()
+	return nil
 }
 
 // Peek returns the highest priority task without removing it
@@ -42,6 +44,20 @@ func (q *Queue) Peek() *Task {
 	return q.tasks[0]
 }
 
+// Next returns the highest priority pending task without removing it.
+// Ties are broken by creation time so equal-priority work stays FIFO.
+func (q *Queue) Next() *Task {
+	q.mu.RLock()
+	defer q.mu.RUnlock()
+
+	for _, t := range q.tasks {
+		if t.Status == StatusPending {
+			return t
+		}
+	}
+	return nil
+}
+
 // Pop removes and returns the highest priority task
 func (q *Queue) Pop() *Task {
 	q.mu.Lock()
@@ -58,13 +74,13 @@ func (q *Queue) Pop() *Task {
 }
 
 // Remove removes a task by ID
-func (q *Queue) Remove(id string) bool {
+func (q *Queue) Remove(id string) (bool, error) {
 	q.mu.Lock()
 	defer q.mu.Unlock()
 
 	task, exists := q.index[id]
 	if !exists {
-		return false
+		return false, nil
 	}
 
 	delete(q.index, id)
@@ -75,7 +91,7 @@ func (q *Queue) Remove(id string) bool {
 		}
 	}
 	_ = task // silence unused
-	return true
+	return true, nil
 }
 
 // GetByID returns a task by its ID
@@ -131,12 +147,12 @@ func (q *Queue) All() []*Task {
 }
 
 // Update modifies a task in the queue
-func (q *Queue) Update(task *Task) bool {
+func (q *Queue) Update(task *Task) (bool, error) {
 	q.mu.Lock()
 	defer q.mu.Unlock()
 
 	if _, exists := q.index[task.ID]; !exists {
-		return false
+		return false, nil
 	}
 
 	q.index[task.ID] = task
@@ -147,7 +163,7 @@ func (q *Queue) Update(task *Task) bool {
 		}
 	}
 	q.sortLocked()
-	return true
+	return true, nil
 }
 
 // sortLocked sorts tasks by priority (must hold lock)
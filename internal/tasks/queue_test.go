@@ -3,6 +3,7 @@ package tasks
 
 import (
 	"testing"
+	"time"
 )
 
 func TestQueuePriorityOrdering(t *testing.T) {
@@ -82,3 +83,24 @@ func TestQueueGetByAgent(t *testing.T) {
 		t.Errorf("expected 1 task for agent, got %d", len(agentTasks))
 	}
 }
+
+func TestQueueNextSkipsNonPending(t *testing.T) {
+	q := NewQueue()
+	assigned := NewTask("Already assigned", "", 1)
+	assigned.Status = StatusAssigned
+	older := NewTask("Older", "", 3)
+	newer := NewTask("Newer", "", 3)
+	newer.CreatedAt = older.CreatedAt.Add(time.Second)
+
+	q.Add(assigned)
+	q.Add(newer)
+	q.Add(older)
+
+	next := q.Next()
+	if next == nil || next.ID != older.ID {
+		t.Fatalf("expected oldest pending task, got %+v", next)
+	}
+	if q.Len() != 3 {
+		t.Errorf("Next should not remove tasks, got len %d", q.Len())
+	}
+}
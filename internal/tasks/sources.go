@@ -71,7 +71,9 @@ func (l *LocalTaskSource) ClaimTask(taskID string, agentID string) error {
 		return fmt.Errorf("failed to transition task to assigned: %w", err)
 	}
 
-	l.queue.Update(task)
+	if _, err := l.queue.Update(task); err != nil {
+		return err
+	}
 
 	if l.store != nil {
 		if err := l.store.Save(task); err != nil {
@@ -133,7 +135,9 @@ func (l *LocalTaskSource) CompleteTask(taskID string, result TaskResult) error {
 		}
 	}
 
-	l.queue.Update(task)
+	if _, err := l.queue.Update(task); err != nil {
+		return err
+	}
 
 	if l.store != nil {
 		if err := l.store.Save(task); err != nil {
@@ -0,0 +1,246 @@
+// internal/tasks/sqlite_queue.go
+package tasks
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/CLIAIMONITOR/internal/logger"
+	_ "modernc.org/sqlite"
+)
+
+// TaskQueue is the task queue API used by the server and the task handlers.
+// Writes return an error when the task could not be persisted.
+type TaskQueue interface {
+	Add(task *Task) error
+	Next() *Task
+	Remove(id string) (bool, error)
+	Len() int
+	GetByID(id string) *Task
+	GetByStatus(status TaskStatus) []*Task
+	GetByAgent(agentID string) []*Task
+	All() []*Task
+	Update(task *Task) (bool, error)
+}
+
+var (
+	_ TaskQueue = (*Queue)(nil)
+	_ TaskQueue = (*SQLiteQueue)(nil)
+)
+
+// taskColumns is the column list scanTask and scanTasks expect
+const taskColumns = `id, title, description, priority, status, source, repo, assigned_to, branch, pr_url, requirements, metadata, created_at, updated_at, started_at, completed_at`
+
+// SQLiteQueue is a TaskQueue backed by the tasks table of a Store. Reads go
+// straight to the database, so it never drifts from what is persisted, and
+// writes run in BEGIN IMMEDIATE transactions so concurrent writers queue on
+// the database lock instead of interleaving. Ordering matches Queue:
+// priority, then creation time.
+type SQLiteQueue struct {
+	store    *Store
+	inMemory bool
+}
+
+// NewSQLiteQueue creates a queue over an initialized task store
+func NewSQLiteQueue(store *Store) *SQLiteQueue {
+	return &SQLiteQueue{store: store}
+}
+
+// NewMemorySQLiteQueue creates a queue over a private in-memory database, for
+// tests. Call Close to release it.
+func NewMemorySQLiteQueue() (*SQLiteQueue, error) {
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		return nil, fmt.Errorf("failed to open in-memory task database: %w", err)
+	}
+	// Every connection to :memory: is a separate database
+	db.SetMaxOpenConns(1)
+
+	store := NewStore(db)
+	if err := store.Init(); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to initialize in-memory task database: %w", err)
+	}
+	return &SQLiteQueue{store: store, inMemory: true}, nil
+}
+
+// Close releases the database of an in-memory queue. Queues over a shared
+// store leave it open.
+func (q *SQLiteQueue) Close() error {
+	if !q.inMemory {
+		return nil
+	}
+	return q.store.db.Close()
+}
+
+// immediate runs fn on a single connection inside a BEGIN IMMEDIATE
+// transaction, committing if fn succeeds and rolling back otherwise
+func (q *SQLiteQueue) immediate(fn func(ctx context.Context, conn *sql.Conn) error) error {
+	ctx := context.Background()
+	conn, err := q.store.db.Conn(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get task database connection: %w", err)
+	}
+	defer conn.Close()
+
+	if _, err := conn.ExecContext(ctx, "BEGIN IMMEDIATE"); err != nil {
+		return fmt.Errorf("failed to begin task transaction: %w", err)
+	}
+	if err := fn(ctx, conn); err != nil {
+		conn.ExecContext(ctx, "ROLLBACK")
+		return err
+	}
+	if _, err := conn.ExecContext(ctx, "COMMIT"); err != nil {
+		conn.ExecContext(ctx, "ROLLBACK")
+		return fmt.Errorf("failed to commit task transaction: %w", err)
+	}
+	return nil
+}
+
+// Add inserts or replaces a task
+func (q *SQLiteQueue) Add(task *Task) error {
+	err := q.immediate(func(ctx context.Context, conn *sql.Conn) error {
+		return q.store.save(ctx, conn, task)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to persist task %s: %w", task.ID, err)
+	}
+	return nil
+}
+
+// Next returns the highest priority pending task without claiming it
+func (q *SQLiteQueue) Next() *Task {
+	row := q.store.db.QueryRow(`
+		SELECT `+taskColumns+`
+		FROM tasks WHERE status = ? ORDER BY priority, created_at LIMIT 1
+	`, StatusPending)
+
+	task, err := q.store.scanTask(row)
+	if err != nil {
+		if !errors.Is(err, sql.ErrNoRows) {
+			logger.For("tasks").Error("failed to read next task", "error", err)
+		}
+		return nil
+	}
+	return task
+}
+
+// Claim atomically assigns the highest priority pending task to agentID and
+// marks it in progress. Returns nil if no task is pending.
+func (q *SQLiteQueue) Claim(agentID string) (*Task, error) {
+	var task *Task
+	err := q.immediate(func(ctx context.Context, conn *sql.Conn) error {
+		var err error
+		task, err = q.store.claimNextTask(ctx, conn, agentID, []TaskStatus{StatusPending})
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return task, nil
+}
+
+// Remove deletes a task by ID, reporting whether it existed
+func (q *SQLiteQueue) Remove(id string) (bool, error) {
+	removed := false
+	err := q.immediate(func(ctx context.Context, conn *sql.Conn) error {
+		result, err := conn.ExecContext(ctx, `DELETE FROM tasks WHERE id = ?`, id)
+		if err != nil {
+			return err
+		}
+		n, err := result.RowsAffected()
+		removed = n > 0
+		return err
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to delete task %s: %w", id, err)
+	}
+	return removed, nil
+}
+
+// Len returns the number of tasks
+func (q *SQLiteQueue) Len() int {
+	var n int
+	if err := q.store.db.QueryRow(`SELECT COUNT(*) FROM tasks`).Scan(&n); err != nil {
+		logger.For("tasks").Error("failed to count tasks", "error", err)
+		return 0
+	}
+	return n
+}
+
+// GetByID returns a task by its ID, or nil if it does not exist
+func (q *SQLiteQueue) GetByID(id string) *Task {
+	task, err := q.store.GetByID(id)
+	if err != nil {
+		if !errors.Is(err, sql.ErrNoRows) {
+			logger.For("tasks").Error("failed to read task", "task_id", id, "error", err)
+		}
+		return nil
+	}
+	return task
+}
+
+// GetByStatus returns all tasks with the given status
+func (q *SQLiteQueue) GetByStatus(status TaskStatus) []*Task {
+	result, err := q.store.GetByStatus(status)
+	if err != nil {
+		logger.For("tasks").Error("failed to read tasks by status", "status", status, "error", err)
+		return nil
+	}
+	return result
+}
+
+// GetByAgent returns all tasks assigned to an agent
+func (q *SQLiteQueue) GetByAgent(agentID string) []*Task {
+	rows, err := q.store.db.Query(`
+		SELECT `+taskColumns+`
+		FROM tasks WHERE assigned_to = ? ORDER BY priority, created_at
+	`, agentID)
+	if err != nil {
+		logger.For("tasks").Error("failed to read agent tasks", "agent_id", agentID, "error", err)
+		return nil
+	}
+	defer rows.Close()
+
+	result, err := q.store.scanTasks(rows)
+	if err != nil {
+		logger.For("tasks").Error("failed to read agent tasks", "agent_id", agentID, "error", err)
+		return nil
+	}
+	return result
+}
+
+// All returns all tasks (for dashboard display)
+func (q *SQLiteQueue) All() []*Task {
+	result, err := q.store.GetAll()
+	if err != nil {
+		logger.For("tasks").Error("failed to read tasks", "error", err)
+	}
+	if result == nil {
+		result = []*Task{}
+	}
+	return result
+}
+
+// Update saves changes to an existing task, reporting whether it existed
+func (q *SQLiteQueue) Update(task *Task) (bool, error) {
+	updated := false
+	err := q.immediate(func(ctx context.Context, conn *sql.Conn) error {
+		var exists int
+		err := conn.QueryRowContext(ctx, `SELECT 1 FROM tasks WHERE id = ?`, task.ID).Scan(&exists)
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		updated = true
+		return q.store.save(ctx, conn, task)
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to persist updated task %s: %w", task.ID, err)
+	}
+	return updated, nil
+}
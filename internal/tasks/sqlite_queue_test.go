@@ -0,0 +1,169 @@
+// internal/tasks/sqlite_queue_test.go
+package tasks
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+)
+
+func newTestSQLiteQueue(t *testing.T) *SQLiteQueue {
+	t.Helper()
+	q, err := NewMemorySQLiteQueue()
+	if err != nil {
+		t.Fatalf("NewMemorySQLiteQueue() error = %v", err)
+	}
+	t.Cleanup(func() { q.Close() })
+	return q
+}
+
+func TestSQLiteQueueOrdering(t *testing.T) {
+	q := newTestSQLiteQueue(t)
+
+	base := time.Now()
+	low := NewTask("Low", "", 7)
+	first := NewTask("First critical", "", 1)
+	first.CreatedAt = base
+	second := NewTask("Second critical", "", 1)
+	second.CreatedAt = base.Add(time.Second)
+	q.Add(low)
+	q.Add(second)
+	q.Add(first)
+
+	if q.Len() != 3 {
+		t.Fatalf("Len() = %d, want 3", q.Len())
+	}
+	all := q.All()
+	if len(all) != 3 || all[0].ID != first.ID || all[1].ID != second.ID || all[2].ID != low.ID {
+		t.Errorf("All() not in priority/FIFO order: %+v", all)
+	}
+	if next := q.Next(); next == nil || next.ID != first.ID {
+		t.Errorf("Next() = %+v, want %s", next, first.ID)
+	}
+
+	first.Status = StatusAssigned
+	if ok, err := q.Update(first); err != nil || !ok {
+		t.Fatalf("Update() = %v, %v for existing task", ok, err)
+	}
+	if next := q.Next(); next == nil || next.ID != second.ID {
+		t.Errorf("Next() after assigning first = %+v, want %s", next, second.ID)
+	}
+}
+
+func TestSQLiteQueueLookups(t *testing.T) {
+	q := newTestSQLiteQueue(t)
+
+	task := NewTask("Find me", "", 3)
+	task.AssignedTo = "agent-1"
+	task.Status = StatusAssigned
+	q.Add(task)
+	q.Add(NewTask("Other", "", 3))
+
+	if got := q.GetByID(task.ID); got == nil || got.Title != "Find me" {
+		t.Errorf("GetByID() = %+v", got)
+	}
+	if got := q.GetByID("missing"); got != nil {
+		t.Errorf("GetByID(missing) = %+v, want nil", got)
+	}
+	if got := q.GetByAgent("agent-1"); len(got) != 1 || got[0].ID != task.ID {
+		t.Errorf("GetByAgent() = %+v", got)
+	}
+	if got := q.GetByStatus(StatusPending); len(got) != 1 {
+		t.Errorf("GetByStatus(pending) returned %d tasks, want 1", len(got))
+	}
+}
+
+func TestSQLiteQueueRemoveAndUpdateMissing(t *testing.T) {
+	q := newTestSQLiteQueue(t)
+
+	task := NewTask("Remove me", "", 3)
+	q.Add(task)
+
+	if ok, err := q.Remove(task.ID); err != nil || !ok {
+		t.Fatalf("Remove() = %v, %v for existing task", ok, err)
+	}
+	if ok, _ := q.Remove(task.ID); ok {
+		t.Error("Remove() = true for already removed task")
+	}
+	if ok, _ := q.Update(task); ok {
+		t.Error("Update() = true for removed task")
+	}
+	if q.Len() != 0 {
+		t.Errorf("Len() = %d, want 0", q.Len())
+	}
+	if all := q.All(); all == nil || len(all) != 0 {
+		t.Errorf("All() = %#v, want empty slice", all)
+	}
+	if q.Next() != nil {
+		t.Error("Next() on empty queue should be nil")
+	}
+}
+
+func TestSQLiteQueueClaim(t *testing.T) {
+	store, cleanup := setupTestDB(t)
+	defer cleanup()
+	q := NewSQLiteQueue(store)
+
+	const n = 10
+	for i := 0; i < n; i++ {
+		q.Add(NewTask(fmt.Sprintf("Task %d", i), "", 3))
+	}
+
+	var mu sync.Mutex
+	claimed := make(map[string]string)
+	var wg sync.WaitGroup
+	for i := 0; i < n*2; i++ {
+		wg.Add(1)
+		go func(agent string) {
+			defer wg.Done()
+			task, err := q.Claim(agent)
+			if err != nil {
+				t.Errorf("Claim() error = %v", err)
+				return
+			}
+			if task == nil {
+				return
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			if prev, ok := claimed[task.ID]; ok {
+				t.Errorf("task %s claimed by %s and %s", task.ID, prev, agent)
+			}
+			claimed[task.ID] = agent
+		}(fmt.Sprintf("agent-%d", i))
+	}
+	wg.Wait()
+
+	if len(claimed) != n {
+		t.Errorf("claimed %d tasks, want %d", len(claimed), n)
+	}
+	if q.Next() != nil {
+		t.Error("Next() should be nil once every task is claimed")
+	}
+	for id, agent := range claimed {
+		task := q.GetByID(id)
+		if task.Status != StatusInProgress || task.AssignedTo != agent {
+			t.Errorf("task %s: status=%s assigned_to=%s, want in_progress/%s", id, task.Status, task.AssignedTo, agent)
+		}
+	}
+}
+
+func TestSQLiteQueueWriteErrors(t *testing.T) {
+	q, err := NewMemorySQLiteQueue()
+	if err != nil {
+		t.Fatalf("NewMemorySQLiteQueue() error = %v", err)
+	}
+	task := NewTask("Lost", "", 3)
+	q.Close()
+
+	if err := q.Add(task); err == nil {
+		t.Error("Add() on a closed database returned no error")
+	}
+	if _, err := q.Update(task); err == nil {
+		t.Error("Update() on a closed database returned no error")
+	}
+	if _, err := q.Remove(task.ID); err == nil {
+		t.Error("Remove() on a closed database returned no error")
+	}
+}
@@ -2,8 +2,11 @@
 package tasks
 
 import (
+	"context"
 	"database/sql"
 	"encoding/json"
+	"errors"
+	"strings"
 	"time"
 )
 
@@ -48,12 +51,24 @@ func (s *Store) Init() error {
 	return nil
 }
 
+// querier is the subset of *sql.DB and *sql.Conn the store's queries use, so
+// they can also run inside a SQLiteQueue transaction
+type querier interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
 // Save creates or updates a task
 func (s *Store) Save(task *Task) error {
+	return s.save(context.Background(), s.db, task)
+}
+
+func (s *Store) save(ctx context.Context, q querier, task *Task) error {
 	metadata, _ := json.Marshal(task.Metadata)
 	requirements, _ := json.Marshal(task.Requirements)
 
-	_, err := s.db.Exec(`
+	_, err := q.ExecContext(ctx, `
 		INSERT INTO tasks (id, title, description, priority, status, source, repo, assigned_to, branch, pr_url, requirements, metadata, created_at, updated_at, started_at, completed_at)
 		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 		ON CONFLICT(id) DO UPDATE SET
@@ -116,6 +131,42 @@ func (s *Store) GetAll() ([]*Task, error) {
 	return s.scanTasks(rows)
 }
 
+// ClaimNextTask atomically moves the highest-priority task in one of the
+// given statuses to in_progress and assigns it to agentID. The select and
+// update run as a single statement, so concurrent callers never claim the
+// same task. Returns nil if no task is available.
+func (s *Store) ClaimNextTask(agentID string, statuses []TaskStatus) (*Task, error) {
+	return s.claimNextTask(context.Background(), s.db, agentID, statuses)
+}
+
+func (s *Store) claimNextTask(ctx context.Context, q querier, agentID string, statuses []TaskStatus) (*Task, error) {
+	if len(statuses) == 0 {
+		statuses = []TaskStatus{StatusPending}
+	}
+
+	placeholders := strings.TrimSuffix(strings.Repeat("?,", len(statuses)), ",")
+	now := time.Now()
+	args := []interface{}{StatusInProgress, agentID, now, now}
+	for _, status := range statuses {
+		args = append(args, status)
+	}
+
+	row := q.QueryRowContext(ctx, `
+		UPDATE tasks SET status = ?, assigned_to = ?, started_at = ?, updated_at = ?
+		WHERE id = (
+			SELECT id FROM tasks WHERE status IN (`+placeholders+`)
+			ORDER BY priority, created_at LIMIT 1
+		)
+		RETURNING id, title, description, priority, status, source, repo, assigned_to, branch, pr_url, requirements, metadata, created_at, updated_at, started_at, completed_at
+	`, args...)
+
+	task, err := s.scanTask(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	return task, err
+}
+
 // Delete removes a task
 func (s *Store) Delete(id string) error {
 	_, err := s.db.Exec(`DELETE FROM tasks WHERE id = ?`, id)
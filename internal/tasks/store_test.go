@@ -4,6 +4,7 @@ package tasks
 import (
 	"database/sql"
 	"os"
+	"sync"
 	"testing"
 	"time"
 
@@ -17,7 +18,8 @@ func setupTestDB(t *testing.T) (*Store, func()) {
 	}
 	f.Close()
 
-	db, err := sql.Open("sqlite", f.Name())
+	// Busy timeout lets concurrent writers wait for the lock instead of failing
+	db, err := sql.Open("sqlite", f.Name()+"?_pragma=busy_timeout(5000)")
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -85,3 +87,70 @@ func TestStoreGetByStatus(t *testing.T) {
 		t.Errorf("expected 1 pending task, got %d", len(pending))
 	}
 }
+
+func TestStoreClaimNextTask(t *testing.T) {
+	store, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	low := NewTask("Low", "", 5)
+	time.Sleep(1 * time.Millisecond)
+	high := NewTask("High", "", 2)
+	for _, task := range []*Task{low, high} {
+		if err := store.Save(task); err != nil {
+			t.Fatalf("Save failed: %v", err)
+		}
+	}
+
+	claimed, err := store.ClaimNextTask("agent-1", []TaskStatus{StatusPending})
+	if err != nil {
+		t.Fatalf("ClaimNextTask failed: %v", err)
+	}
+	if claimed == nil || claimed.ID != high.ID {
+		t.Fatalf("expected highest-priority task %s, got %+v", high.ID, claimed)
+	}
+	if claimed.Status != StatusInProgress || claimed.AssignedTo != "agent-1" || claimed.StartedAt == nil {
+		t.Errorf("expected claimed task in progress for agent-1, got %+v", claimed)
+	}
+
+	if _, err := store.ClaimNextTask("agent-1", nil); err != nil {
+		t.Fatalf("ClaimNextTask failed: %v", err)
+	}
+	none, err := store.ClaimNextTask("agent-1", []TaskStatus{StatusPending})
+	if err != nil || none != nil {
+		t.Errorf("expected no task left, got %+v (%v)", none, err)
+	}
+}
+
+func TestStoreClaimNextTaskConcurrent(t *testing.T) {
+	store, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	if err := store.Save(NewTask("Only task", "", 3)); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	var wg sync.WaitGroup
+	results := make([]*Task, 2)
+	errs := make([]error, 2)
+	for i := range results {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			results[i], errs[i] = store.ClaimNextTask("agent-"+string(rune('a'+i)), []TaskStatus{StatusPending})
+		}(i)
+	}
+	wg.Wait()
+
+	claims := 0
+	for i, task := range results {
+		if errs[i] != nil {
+			t.Fatalf("ClaimNextTask %d failed: %v", i, errs[i])
+		}
+		if task != nil {
+			claims++
+		}
+	}
+	if claims != 1 {
+		t.Errorf("expected exactly one claim, got %d", claims)
+	}
+}
@@ -30,6 +30,19 @@ const (
 	SourceFile      TaskSource = "file"
 )
 
+// Priority bounds (lower number = higher priority)
+const (
+	PriorityCritical = 1
+	PriorityLowest   = 7
+	// DefaultPriority matches the tasks.priority column default
+	DefaultPriority = 5
+)
+
+// ValidPriority reports whether p is within the 1-7 priority range
+func ValidPriority(p int) bool {
+	return p >= PriorityCritical && p <= PriorityLowest
+}
+
 // Task represents a unit of work in the system
 type Task struct {
 	ID          string            `json:"id"`
@@ -86,7 +99,7 @@ func NewTask(title, description string, priority int) *Task {
 
 // Validate checks that the task has valid field values
 func (t *Task) Validate() error {
-	if t.Priority < 1 || t.Priority > 7 {
+	if !ValidPriority(t.Priority) {
 		return fmt.Errorf("priority must be between 1 and 7")
 	}
 	if t.Title == "" {
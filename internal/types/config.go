@@ -30,8 +30,9 @@ type MCPResponse struct {
 
 // MCPError for error responses
 type MCPError struct {
-	Code    int    `json:"code"`
-	Message string `json:"message"`
+	Code    int         `json:"code"`
+	Message string      `json:"message"`
+	Data    interface{} `json:"data,omitempty"`
 }
 
 // MCPNotification for server-initiated messages
@@ -45,6 +46,15 @@ type MCPNotification struct {
 type WSMessage struct {
 	Type string      `json:"type"`
 	Data interface{} `json:"data"`
+	// ReconnectConfig is only sent with the initial state on connect
+	ReconnectConfig *WSReconnectConfig `json:"ws_reconnect_config,omitempty"`
+}
+
+// WSReconnectConfig tells dashboard clients how to back off when reconnecting
+type WSReconnectConfig struct {
+	InitialDelayMs int  `json:"initial_delay_ms"`
+	MaxDelayMs     int  `json:"max_delay_ms"`
+	Jitter         bool `json:"jitter"`
 }
 
 // WebSocket message type constants
@@ -56,4 +66,6 @@ const (
 	WSTypeEscalation     = "escalation"
 	WSTypeCaptainMessage = "captain_message"
 	WSTypeChat           = "chat"
+	WSTypePing           = "ping"
+	WSTypeAgentOutput    = "agent_output"
 )
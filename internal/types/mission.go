@@ -0,0 +1,17 @@
+package types
+
+// RequiredKey is a metadata key a mission of a given task type must carry
+type RequiredKey struct {
+	Key         string
+	Description string
+}
+
+// MetadataSchema lists the metadata keys required per mission task type.
+// Task types without an entry accept any metadata.
+var MetadataSchema = map[string][]RequiredKey{
+	"planning": {
+		{Key: "source", Description: "where the task came from (e.g. planner, dashboard)"},
+		{Key: "estimated_effort", Description: "rough size estimate for the work"},
+		{Key: "agent_recommendation", Description: "agent type suggested for the work"},
+	},
+}
@@ -39,4 +39,6 @@ type NotifyEmailConfig struct {
 	To          []string `yaml:"to"`
 	EventTypes  []string `yaml:"events"`
 	MinPriority int      `yaml:"min_priority"`
+	// TemplatePath is an optional HTML wrapper for review_completed reports
+	TemplatePath string `yaml:"email_template_path"`
 }
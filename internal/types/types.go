@@ -16,6 +16,7 @@ const (
 	StatusBlocked      AgentStatus = "blocked"
 	StatusDisconnected AgentStatus = "disconnected"
 	StatusStopping     AgentStatus = "stopping"
+	StatusDegraded     AgentStatus = "degraded" // Process alive but no MCP activity within the heartbeat timeout
 )
 
 // AgentRole defines the role/specialization of an agent
@@ -34,14 +35,23 @@ const (
 
 // AgentConfig from teams.yaml
 type AgentConfig struct {
-	Name            string    `yaml:"name" json:"name"`
-	Model           string    `yaml:"model" json:"model"`
-	Role            AgentRole `yaml:"role" json:"role"`
-	Color           string    `yaml:"color" json:"color"`
-	Prefix          string    `yaml:"prefix" json:"prefix"`           // e.g., "Snake" for Snake001
-	Numbering       bool      `yaml:"numbering" json:"numbering"`     // Whether to auto-number agents
-	PromptFile      string    `yaml:"prompt_file" json:"prompt_file"` // Optional override for prompt file
-	SkipPermissions bool      `yaml:"skip_permissions" json:"skip_permissions"`
+	Name            string      `yaml:"name" json:"name"`
+	Model           string      `yaml:"model" json:"model"`
+	Role            AgentRole   `yaml:"role" json:"role"`
+	Color           string      `yaml:"color" json:"color"`
+	Prefix          string      `yaml:"prefix" json:"prefix"`           // e.g., "Snake" for Snake001
+	Numbering       bool        `yaml:"numbering" json:"numbering"`     // Whether to auto-number agents
+	PromptFile      string      `yaml:"prompt_file" json:"prompt_file"` // Optional override for prompt file
+	SkipPermissions bool        `yaml:"skip_permissions" json:"skip_permissions"`
+	Colors          AgentColors `yaml:"colors" json:"colors"` // Optional WezTerm pane colors
+}
+
+// AgentColors overrides an agent's pane color scheme. Values are "#RRGGBB";
+// empty fields fall back to the built-in palette.
+type AgentColors struct {
+	Background string `yaml:"background" json:"background,omitempty"` // Pane background (OSC 11)
+	Foreground string `yaml:"foreground" json:"foreground,omitempty"` // Text color
+	TabColor   string `yaml:"tab_color" json:"tab_color,omitempty"`   // Banner and dashboard tab color
 }
 
 // Agent represents a running agent instance
@@ -59,6 +69,8 @@ type Agent struct {
 	CurrentTask         string      `json:"current_task"`
 	ShutdownRequested   bool        `json:"shutdown_requested"`
 	ShutdownRequestedAt *time.Time  `json:"shutdown_requested_at,omitempty"`
+	Capabilities        []string    `json:"capabilities,omitempty"` // Declared via the register_agent MCP tool
+	HealthScore         *float64    `json:"health_score,omitempty"` // 0-100, set by the background health check
 }
 
 // AgentMetrics tracks per-agent statistics
@@ -85,16 +97,54 @@ type AlertThresholds struct {
 	EscalationQueueMax    int   `json:"escalation_queue_max"`
 	TokenUsageMax         int64 `json:"token_usage_max"`
 	ConsecutiveRejectsMax int   `json:"consecutive_rejects_max"`
+	// CostBudget is the per-agent spend limit in USD (0 = unlimited).
+	// Agents exceeding it are gracefully stopped.
+	CostBudget float64 `json:"cost_budget"`
+	// CostBudgetWarningPercent raises a warning at this share of CostBudget
+	// (0 = DefaultCostBudgetWarningPercent)
+	CostBudgetWarningPercent float64 `json:"cost_budget_warning_percent"`
+	// HeartbeatTimeoutSeconds marks agents degraded when they have not been
+	// seen for this long (0 = DefaultHeartbeatTimeoutSeconds)
+	HeartbeatTimeoutSeconds int `json:"heartbeat_timeout_seconds"`
+	// AgentStopTimeoutSeconds overrides the graceful stop timeout per agent
+	// config name (e.g. "Snake": 10); unlisted agents use the server default
+	AgentStopTimeoutSeconds map[string]int `json:"agent_stop_timeout_seconds,omitempty"`
+}
+
+// DefaultCostBudgetWarningPercent is used when no warning percent is configured
+const DefaultCostBudgetWarningPercent = 80.0
+
+// DefaultHeartbeatTimeoutSeconds is used when no heartbeat timeout is configured
+const DefaultHeartbeatTimeoutSeconds = 300
+
+// HeartbeatTimeout returns the configured heartbeat timeout, falling back to the default
+func (t AlertThresholds) HeartbeatTimeout() time.Duration {
+	if t.HeartbeatTimeoutSeconds <= 0 {
+		return DefaultHeartbeatTimeoutSeconds * time.Second
+	}
+	return time.Duration(t.HeartbeatTimeoutSeconds) * time.Second
+}
+
+// StopTimeout returns the graceful stop timeout for an agent config,
+// falling back to fallback when none is configured
+func (t AlertThresholds) StopTimeout(configName string, fallback time.Duration) time.Duration {
+	if seconds, ok := t.AgentStopTimeoutSeconds[configName]; ok && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	return fallback
 }
 
 // DefaultThresholds returns sensible defaults
 func DefaultThresholds() AlertThresholds {
 	return AlertThresholds{
-		FailedTestsMax:        5,
-		IdleTimeMaxSeconds:    600, // 10 minutes
-		EscalationQueueMax:    10,
-		TokenUsageMax:         100000,
-		ConsecutiveRejectsMax: 3,
+		FailedTestsMax:           5,
+		IdleTimeMaxSeconds:       600, // 10 minutes
+		EscalationQueueMax:       10,
+		TokenUsageMax:            100000,
+		ConsecutiveRejectsMax:    3,
+		CostBudgetWarningPercent: DefaultCostBudgetWarningPercent,
+		HeartbeatTimeoutSeconds:  DefaultHeartbeatTimeoutSeconds,
+		AgentStopTimeoutSeconds:  map[string]int{"Snake": 10},
 	}
 }
 
@@ -115,6 +165,20 @@ func (t AlertThresholds) Validate() error {
 	if t.ConsecutiveRejectsMax < 1 {
 		return fmt.Errorf("consecutive_rejects_max must be at least 1")
 	}
+	if t.CostBudget < 0 {
+		return fmt.Errorf("cost_budget must not be negative")
+	}
+	if t.CostBudgetWarningPercent < 0 || t.CostBudgetWarningPercent >= 100 {
+		return fmt.Errorf("cost_budget_warning_percent must be between 0 and 100")
+	}
+	if t.HeartbeatTimeoutSeconds != 0 && t.HeartbeatTimeoutSeconds < 60 {
+		return fmt.Errorf("heartbeat_timeout_seconds must be at least 60")
+	}
+	for name, seconds := range t.AgentStopTimeoutSeconds {
+		if seconds < 1 || seconds > 3600 {
+			return fmt.Errorf("agent_stop_timeout_seconds for %s must be between 1 and 3600", name)
+		}
+	}
 	return nil
 }
 
@@ -147,6 +211,7 @@ type ActivityLog struct {
 	Action    string    `json:"action"`
 	Details   string    `json:"details"`
 	Timestamp time.Time `json:"timestamp"`
+	TraceID   string    `json:"trace_id,omitempty"`
 }
 
 // SupervisorJudgment records supervisor decisions
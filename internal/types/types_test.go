@@ -3,6 +3,7 @@ package types
 import (
 	"encoding/json"
 	"testing"
+	"time"
 )
 
 func TestAgentStatusConstants(t *testing.T) {
@@ -13,6 +14,7 @@ func TestAgentStatusConstants(t *testing.T) {
 		StatusIdle,
 		StatusBlocked,
 		StatusDisconnected,
+		StatusDegraded,
 	}
 
 	expected := []string{
@@ -22,6 +24,7 @@ func TestAgentStatusConstants(t *testing.T) {
 		"idle",
 		"blocked",
 		"disconnected",
+		"degraded",
 	}
 
 	for i, status := range statuses {
@@ -73,6 +76,44 @@ func TestDefaultThresholds(t *testing.T) {
 	if thresholds.ConsecutiveRejectsMax != 3 {
 		t.Errorf("ConsecutiveRejectsMax = %d, want 3", thresholds.ConsecutiveRejectsMax)
 	}
+	if thresholds.HeartbeatTimeout() != 5*time.Minute {
+		t.Errorf("HeartbeatTimeout() = %v, want 5m", thresholds.HeartbeatTimeout())
+	}
+}
+
+func TestHeartbeatTimeoutFallback(t *testing.T) {
+	if got := (AlertThresholds{}).HeartbeatTimeout(); got != DefaultHeartbeatTimeoutSeconds*time.Second {
+		t.Errorf("unset HeartbeatTimeout() = %v, want default", got)
+	}
+	if got := (AlertThresholds{HeartbeatTimeoutSeconds: 120}).HeartbeatTimeout(); got != 2*time.Minute {
+		t.Errorf("HeartbeatTimeout() = %v, want 2m", got)
+	}
+
+	thresholds := DefaultThresholds()
+	thresholds.HeartbeatTimeoutSeconds = 30
+	if err := thresholds.Validate(); err == nil {
+		t.Error("expected validation error for heartbeat timeout below 60 seconds")
+	}
+}
+
+func TestAgentStopTimeout(t *testing.T) {
+	thresholds := DefaultThresholds()
+	if got := thresholds.StopTimeout("Snake", time.Minute); got != 10*time.Second {
+		t.Errorf("StopTimeout(Snake) = %v, want 10s", got)
+	}
+	if got := thresholds.StopTimeout("SNTGreen", time.Minute); got != time.Minute {
+		t.Errorf("StopTimeout(SNTGreen) = %v, want fallback 1m", got)
+	}
+
+	thresholds.AgentStopTimeoutSeconds["OpusGreen"] = 120
+	if got := thresholds.StopTimeout("OpusGreen", time.Minute); got != 2*time.Minute {
+		t.Errorf("StopTimeout(OpusGreen) = %v, want 2m", got)
+	}
+
+	thresholds.AgentStopTimeoutSeconds["OpusGreen"] = 0
+	if err := thresholds.Validate(); err == nil {
+		t.Error("expected validation error for zero stop timeout")
+	}
 }
 
 func TestNewDashboardState(t *testing.T) {
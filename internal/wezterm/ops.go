@@ -6,12 +6,13 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"log"
 	"os/exec"
 	"strconv"
 	"strings"
 	"sync"
 	"time"
+
+	"github.com/CLIAIMONITOR/internal/logger"
 )
 
 // PaneInfo represents WezTerm pane information
@@ -88,14 +89,14 @@ func (o *Ops) KillPaneContext(ctx context.Context, paneID int) error {
 
 	o.waitForInterval()
 
-	log.Printf("[WEZTERM] Closing pane %d", paneID)
+	logger.For("wezterm").Info("closing pane", "pane_id", paneID)
 
 	output, err := o.runCommand(ctx, "cli", "kill-pane", "--pane-id", strconv.Itoa(paneID))
 	if err != nil {
 		return fmt.Errorf("failed to close pane %d: %w (output: %s)", paneID, err, string(output))
 	}
 
-	log.Printf("[WEZTERM] Successfully closed pane %d", paneID)
+	logger.For("wezterm").Info("closed pane", "pane_id", paneID)
 	return nil
 }
 
@@ -117,7 +118,7 @@ func (o *Ops) KillPanesContext(ctx context.Context, paneIDs []int) []error {
 		}
 
 		if err := o.KillPaneContext(ctx, paneID); err != nil {
-			log.Printf("[WEZTERM] Warning: Failed to close pane %d: %v", paneID, err)
+			logger.For("wezterm").Warn("failed to close pane", "pane_id", paneID, "error", err)
 			errors = append(errors, err)
 		}
 	}
@@ -135,13 +136,13 @@ func (o *Ops) GracefulKillPane(paneID int) error {
 // Sequence: Send Ctrl+C -> wait 300ms -> Send "exit" -> wait 500ms -> kill pane
 // This gives Windows conpty time to clean up the pseudo-console properly.
 func (o *Ops) GracefulKillPaneContext(ctx context.Context, paneID int) error {
-	log.Printf("[WEZTERM] Gracefully closing pane %d (sending exit signals first)", paneID)
+	logger.For("wezterm").Info("gracefully closing pane (sending exit signals first)", "pane_id", paneID)
 
 	// Step 1: Send Ctrl+C to interrupt any running process
 	// We don't hold the mutex here since SendTextContext handles its own locking
 	ctrlC := "\x03" // ASCII ETX (Ctrl+C)
 	if err := o.SendTextContext(ctx, paneID, ctrlC, false); err != nil {
-		log.Printf("[WEZTERM] Warning: Failed to send Ctrl+C to pane %d: %v", paneID, err)
+		logger.For("wezterm").Warn("failed to send Ctrl+C to pane", "pane_id", paneID, "error", err)
 		// Continue anyway - process might already be idle
 	}
 
@@ -154,7 +155,7 @@ func (o *Ops) GracefulKillPaneContext(ctx context.Context, paneID int) error {
 
 	// Step 2: Send "exit" command to terminate shell gracefully
 	if err := o.SendTextContext(ctx, paneID, "exit", true); err != nil {
-		log.Printf("[WEZTERM] Warning: Failed to send exit to pane %d: %v", paneID, err)
+		logger.For("wezterm").Warn("failed to send exit to pane", "pane_id", paneID, "error", err)
 		// Continue anyway - we'll force kill
 	}
 
@@ -187,10 +188,10 @@ func (o *Ops) GracefulKillPanesContext(ctx context.Context, paneIDs []int) []err
 		default:
 		}
 
-		log.Printf("[WEZTERM] Gracefully closing pane %d/%d", i+1, len(paneIDs))
+		logger.For("wezterm").Info("gracefully closing pane", "index", i+1, "total", len(paneIDs))
 
 		if err := o.GracefulKillPaneContext(ctx, paneID); err != nil {
-			log.Printf("[WEZTERM] Warning: Failed to gracefully close pane %d: %v", paneID, err)
+			logger.For("wezterm").Warn("failed to gracefully close pane", "pane_id", paneID, "error", err)
 			errors = append(errors, err)
 		}
 
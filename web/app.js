@@ -7,12 +7,18 @@ class Dashboard {
         this.state = null;
         this.reconnectAttempts = 0;
         this.maxReconnectAttempts = 10;
+        // Overridden by ws_reconnect_config from the server on connect
+        this.reconnectConfig = { initial_delay_ms: 500, max_delay_ms: 30000, jitter: true };
+        this.lastMessageAt = 0;
+        this.heartbeatTimer = null;
+        this.thresholdsLoaded = false;
         this.sessionStartTime = null;
 
         this.init();
     }
 
     init() {
+        this.bindThresholdsForm();
         this.connectWebSocket();
         this.loadInitialState();
         this.loadSessionStats();
@@ -39,11 +45,13 @@ class Dashboard {
             console.log('[DASHBOARD] WebSocket connected');
             this.updateConnectionStatus(true);
             this.reconnectAttempts = 0;
+            this.startHeartbeatWatch();
         };
 
         this.ws.onclose = () => {
             console.log('[DASHBOARD] WebSocket disconnected');
             this.updateConnectionStatus(false);
+            this.stopHeartbeatWatch();
             this.scheduleReconnect();
         };
 
@@ -52,15 +60,48 @@ class Dashboard {
         };
 
         this.ws.onmessage = (event) => {
+            this.lastMessageAt = Date.now();
             const message = JSON.parse(event.data);
+            if (message.ws_reconnect_config) {
+                this.reconnectConfig = message.ws_reconnect_config;
+            }
             this.handleMessage(message);
         };
     }
 
+    // Server pings every 15s; treat 3 missed pings as a dead connection
+    startHeartbeatWatch() {
+        this.stopHeartbeatWatch();
+        this.lastMessageAt = Date.now();
+        this.heartbeatTimer = setInterval(() => {
+            if (Date.now() - this.lastMessageAt > 45000) {
+                console.warn('[DASHBOARD] No messages from server, closing stale WebSocket');
+                this.ws.close();
+            }
+        }, 5000);
+    }
+
+    stopHeartbeatWatch() {
+        if (this.heartbeatTimer) {
+            clearInterval(this.heartbeatTimer);
+            this.heartbeatTimer = null;
+        }
+    }
+
+    // Doubling backoff from initial_delay_ms up to max_delay_ms, with optional +/-20% jitter
+    reconnectDelay() {
+        const cfg = this.reconnectConfig;
+        let delay = Math.min(cfg.initial_delay_ms * Math.pow(2, this.reconnectAttempts - 1), cfg.max_delay_ms);
+        if (cfg.jitter) {
+            delay *= 0.8 + Math.random() * 0.4;
+        }
+        return Math.round(delay);
+    }
+
     scheduleReconnect() {
         if (this.reconnectAttempts < this.maxReconnectAttempts) {
             this.reconnectAttempts++;
-            const delay = Math.min(1000 * Math.pow(2, this.reconnectAttempts), 30000);
+            const delay = this.reconnectDelay();
             console.log(`[DASHBOARD] Reconnecting in ${delay}ms (attempt ${this.reconnectAttempts})`);
             setTimeout(() => this.connectWebSocket(), delay);
         }
@@ -88,6 +129,9 @@ class Dashboard {
             case 'metrics_update':
                 this.loadModelMetrics();
                 break;
+            case 'ping':
+                // Keepalive only; lastMessageAt is already updated
+                break;
         }
     }
 
@@ -123,10 +167,56 @@ class Dashboard {
         }
     }
 
+    // Thresholds
+    bindThresholdsForm() {
+        const form = document.getElementById('thresholds-form');
+        if (!form) return;
+        form.addEventListener('submit', (e) => {
+            e.preventDefault();
+            this.saveThresholds(form);
+        });
+    }
+
+    async saveThresholds(form) {
+        const status = document.getElementById('thresholds-status');
+        const thresholds = {};
+        for (const input of form.querySelectorAll('input[name]')) {
+            thresholds[input.name] = Number(input.value);
+        }
+
+        try {
+            const response = await fetch('/api/thresholds', {
+                method: 'PUT',
+                headers: { 'Content-Type': 'application/json' },
+                body: JSON.stringify(thresholds)
+            });
+            const result = await response.json();
+            if (status) {
+                status.textContent = response.ok ? 'Saved' : (result.error || 'Save failed');
+            }
+        } catch (error) {
+            console.error('[DASHBOARD] Failed to save thresholds:', error);
+            if (status) status.textContent = 'Save failed';
+        }
+    }
+
+    // Fill the form once; later state updates must not clobber user edits
+    renderThresholds(thresholds) {
+        const form = document.getElementById('thresholds-form');
+        if (!form || !thresholds || this.thresholdsLoaded) return;
+        for (const input of form.querySelectorAll('input[name]')) {
+            if (thresholds[input.name] !== undefined) {
+                input.value = thresholds[input.name];
+            }
+        }
+        this.thresholdsLoaded = true;
+    }
+
     // Rendering
     render() {
         if (!this.state) return;
         // Agent monitoring removed - focusing on Captain interaction and metrics only
+        this.renderThresholds(this.state.thresholds);
     }
 
     renderModelMetrics(metrics) {
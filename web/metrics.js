@@ -6,7 +6,10 @@
 class MetricsDashboard {
     constructor() {
         this.ws = null;
-        this.reconnectInterval = null;
+        this.reconnectTimer = null;
+        this.reconnectAttempts = 0;
+        // Overridden by ws_reconnect_config from the server on connect
+        this.reconnectConfig = { initial_delay_ms: 500, max_delay_ms: 30000, jitter: true };
         this.metrics = {
             byModel: {},
             byAgent: {},
@@ -221,16 +224,15 @@ class MetricsDashboard {
         this.ws.onopen = () => {
             console.log('[METRICS] WebSocket connected');
             this.updateConnectionStatus();
-
-            if (this.reconnectInterval) {
-                clearInterval(this.reconnectInterval);
-                this.reconnectInterval = null;
-            }
+            this.reconnectAttempts = 0;
         };
 
         this.ws.onmessage = (event) => {
             try {
                 const data = JSON.parse(event.data);
+                if (data.ws_reconnect_config) {
+                    this.reconnectConfig = data.ws_reconnect_config;
+                }
                 this.handleWebSocketMessage(data);
             } catch (error) {
                 console.error('[METRICS] Failed to parse WebSocket message:', error);
@@ -245,12 +247,19 @@ class MetricsDashboard {
             console.log('[METRICS] WebSocket disconnected');
             this.updateConnectionStatus();
 
-            // Attempt to reconnect
-            if (!this.reconnectInterval) {
-                this.reconnectInterval = setInterval(() => {
+            // Attempt to reconnect with doubling backoff and optional +/-20% jitter
+            if (!this.reconnectTimer) {
+                const cfg = this.reconnectConfig;
+                let delay = Math.min(cfg.initial_delay_ms * Math.pow(2, this.reconnectAttempts), cfg.max_delay_ms);
+                if (cfg.jitter) {
+                    delay *= 0.8 + Math.random() * 0.4;
+                }
+                this.reconnectAttempts++;
+                this.reconnectTimer = setTimeout(() => {
+                    this.reconnectTimer = null;
                     console.log('[METRICS] Attempting to reconnect WebSocket...');
                     this.connectWebSocket();
-                }, 5000);
+                }, Math.round(delay));
             }
         };
     }